@@ -0,0 +1,164 @@
+// Package auth authenticates the requests to the simulator's API with static bearer tokens or OIDC ID tokens.
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Role decides which requests the client is allowed to send.
+type Role string
+
+const (
+	// RoleReadOnly allows only the requests not mutating anything, i.e., GET, HEAD and OPTIONS.
+	RoleReadOnly Role = "read-only"
+	// RoleReadWrite allows all the requests.
+	RoleReadWrite Role = "read-write"
+)
+
+var (
+	// ErrUnauthenticated is returned when the request doesn't have any valid credential.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrInvalidOptions is returned when Options is invalid.
+	ErrInvalidOptions = errors.New("invalid auth options")
+)
+
+// Identity is the client which sends the request.
+type Identity struct {
+	// Name is the name of the static token, or the subject of the OIDC ID token.
+	Name string
+	Role Role
+}
+
+// Allows returns true if the identity is allowed to send the request with the method.
+func (i *Identity) Allows(method string) bool {
+	if i.Role == RoleReadWrite {
+		return true
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+type identityKey struct{}
+
+// WithIdentity returns the copy of ctx having the identity of the client authenticated.
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// IdentityFrom returns the identity of the client put on ctx by WithIdentity, or nil if there is no identity.
+func IdentityFrom(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityKey{}).(*Identity)
+	return id
+}
+
+// Authenticator authenticates the request.
+// It returns an error wrapping ErrUnauthenticated if the request doesn't have any valid credential.
+type Authenticator func(r *http.Request) (*Identity, error)
+
+// StaticToken is a bearer token accepted by the simulator.
+type StaticToken struct {
+	// Name identifies the client in the logs.
+	Name  string
+	Token string
+	Role  Role
+}
+
+// OIDCOptions configures the validation of the OIDC ID tokens.
+type OIDCOptions struct {
+	// IssuerURL is the URL of the issuer. The keys are discovered from <IssuerURL>/.well-known/openid-configuration.
+	IssuerURL string
+	// Audience must be in the aud claim of the ID tokens.
+	Audience string
+	// GroupsClaim is the claim having the groups of the user. "groups" is used if empty.
+	GroupsClaim string
+	// ReadWriteGroups are the groups given RoleReadWrite. The other users are given RoleReadOnly.
+	ReadWriteGroups []string
+}
+
+// Options configures the authentication of the simulator's API.
+type Options struct {
+	Tokens []StaticToken
+	// OIDC enables the OIDC ID tokens if non-nil.
+	OIDC *OIDCOptions
+	// AuthenticateHealthz and AuthenticateMetrics decide whether /healthz, /readyz and /metrics are authenticated as well.
+	AuthenticateHealthz bool
+	AuthenticateMetrics bool
+}
+
+// Validate returns an error wrapping ErrInvalidOptions if o is invalid.
+func (o *Options) Validate() error {
+	if len(o.Tokens) == 0 && o.OIDC == nil {
+		return xerrors.Errorf("neither tokens nor OIDC is configured: %w", ErrInvalidOptions)
+	}
+	names := map[string]bool{}
+	for i, t := range o.Tokens {
+		if t.Name == "" || t.Token == "" {
+			return xerrors.Errorf("tokens[%d]: name and token are required: %w", i, ErrInvalidOptions)
+		}
+		if names[t.Name] {
+			return xerrors.Errorf("tokens[%d]: duplicated name %q: %w", i, t.Name, ErrInvalidOptions)
+		}
+		names[t.Name] = true
+		if t.Role != RoleReadOnly && t.Role != RoleReadWrite {
+			return xerrors.Errorf("tokens[%d]: unknown role %q, must be %q or %q: %w", i, t.Role, RoleReadOnly, RoleReadWrite, ErrInvalidOptions)
+		}
+	}
+	if o.OIDC != nil && (o.OIDC.IssuerURL == "" || o.OIDC.Audience == "") {
+		return xerrors.Errorf("oidc: issuerURL and audience are required: %w", ErrInvalidOptions)
+	}
+	return nil
+}
+
+// New returns Authenticator accepting the static tokens first, and then the OIDC ID tokens if configured.
+// The options must be validated in advance.
+func New(opts *Options) Authenticator {
+	static := newStaticTokenAuthenticator(opts.Tokens)
+	var oidc Authenticator
+	if opts.OIDC != nil {
+		oidc = NewOIDCAuthenticator(opts.OIDC, &http.Client{Timeout: oidcRequestTimeout})
+	}
+	return func(r *http.Request) (*Identity, error) {
+		token, ok := bearerToken(r)
+		if !ok {
+			return nil, xerrors.Errorf("bearer token is required: %w", ErrUnauthenticated)
+		}
+		if id := static(token); id != nil {
+			return id, nil
+		}
+		if oidc == nil {
+			return nil, xerrors.Errorf("invalid bearer token: %w", ErrUnauthenticated)
+		}
+		return oidc(r)
+	}
+}
+
+// newStaticTokenAuthenticator returns the function to find the static token matching the given one.
+func newStaticTokenAuthenticator(tokens []StaticToken) func(token string) *Identity {
+	return func(token string) *Identity {
+		var found *Identity
+		for _, t := range tokens {
+			// The constant time comparison doesn't leak the token by the response time.
+			if subtle.ConstantTimeCompare([]byte(token), []byte(t.Token)) == 1 && found == nil {
+				found = &Identity{Name: t.Name, Role: t.Role}
+			}
+		}
+		return found
+	}
+}
+
+// bearerToken returns the token in `Authorization: Bearer <token>`.
+func bearerToken(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	token = strings.TrimSpace(token)
+	return token, ok && token != ""
+}
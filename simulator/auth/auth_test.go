@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_staticTokens(t *testing.T) {
+	t.Parallel()
+	authenticate := New(&Options{Tokens: []StaticToken{
+		{Name: "viewer", Token: "view-token", Role: RoleReadOnly},
+		{Name: "admin", Token: "admin-token", Role: RoleReadWrite},
+	}})
+	tests := []struct {
+		name          string
+		authorization string
+		want          *Identity
+	}{
+		{
+			name:          "read-only token",
+			authorization: "Bearer view-token",
+			want:          &Identity{Name: "viewer", Role: RoleReadOnly},
+		},
+		{
+			name:          "read-write token",
+			authorization: "Bearer admin-token",
+			want:          &Identity{Name: "admin", Role: RoleReadWrite},
+		},
+		{
+			name:          "unknown token",
+			authorization: "Bearer unknown",
+		},
+		{
+			name:          "no token",
+			authorization: "",
+		},
+		{
+			name:          "not bearer",
+			authorization: "Basic view-token",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", tt.authorization)
+
+			got, err := authenticate(req)
+			if tt.want == nil {
+				assert.ErrorIs(t, err, ErrUnauthenticated)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIdentity_Allows(t *testing.T) {
+	t.Parallel()
+	readOnly := &Identity{Role: RoleReadOnly}
+	readWrite := &Identity{Role: RoleReadWrite}
+
+	assert.True(t, readOnly.Allows(http.MethodGet))
+	assert.False(t, readOnly.Allows(http.MethodPost))
+	assert.False(t, readOnly.Allows(http.MethodPut))
+	assert.False(t, readOnly.Allows(http.MethodDelete))
+	assert.True(t, readWrite.Allows(http.MethodGet))
+	assert.True(t, readWrite.Allows(http.MethodPost))
+}
+
+func TestOptions_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{
+			name: "static tokens",
+			opts: Options{Tokens: []StaticToken{{Name: "admin", Token: "t", Role: RoleReadWrite}}},
+		},
+		{
+			name: "OIDC",
+			opts: Options{OIDC: &OIDCOptions{IssuerURL: "https://issuer.example.com", Audience: "simulator"}},
+		},
+		{
+			name:    "nothing configured",
+			wantErr: true,
+		},
+		{
+			name:    "unknown role",
+			opts:    Options{Tokens: []StaticToken{{Name: "admin", Token: "t", Role: "admin"}}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicated name",
+			opts:    Options{Tokens: []StaticToken{{Name: "admin", Token: "t1", Role: RoleReadWrite}, {Name: "admin", Token: "t2", Role: RoleReadOnly}}},
+			wantErr: true,
+		},
+		{
+			name:    "OIDC without audience",
+			opts:    Options{OIDC: &OIDCOptions{IssuerURL: "https://issuer.example.com"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.opts.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidOptions)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNewOIDCAuthenticator(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer := newFakeIssuer(t, &key.PublicKey, nil)
+	now := time.Now()
+	opts := &OIDCOptions{IssuerURL: issuer.URL, Audience: "simulator", ReadWriteGroups: []string{"sched-admins"}}
+
+	tests := []struct {
+		name   string
+		key    *rsa.PrivateKey
+		claims map[string]interface{}
+		want   *Identity
+	}{
+		{
+			name:   "user in the read-write group",
+			key:    key,
+			claims: map[string]interface{}{"iss": issuer.URL, "aud": "simulator", "sub": "alice", "exp": now.Add(time.Hour).Unix(), "groups": []string{"sched-admins"}},
+			want:   &Identity{Name: "alice", Role: RoleReadWrite},
+		},
+		{
+			name:   "user not in the read-write group",
+			key:    key,
+			claims: map[string]interface{}{"iss": issuer.URL, "aud": []string{"other", "simulator"}, "sub": "bob", "exp": now.Add(time.Hour).Unix()},
+			want:   &Identity{Name: "bob", Role: RoleReadOnly},
+		},
+		{
+			name:   "expired",
+			key:    key,
+			claims: map[string]interface{}{"iss": issuer.URL, "aud": "simulator", "sub": "alice", "exp": now.Add(-time.Hour).Unix()},
+		},
+		{
+			name:   "another audience",
+			key:    key,
+			claims: map[string]interface{}{"iss": issuer.URL, "aud": "other", "sub": "alice", "exp": now.Add(time.Hour).Unix()},
+		},
+		{
+			name:   "another issuer",
+			key:    key,
+			claims: map[string]interface{}{"iss": "https://evil.example.com", "aud": "simulator", "sub": "alice", "exp": now.Add(time.Hour).Unix()},
+		},
+		{
+			name:   "signed by another key",
+			key:    otherKey,
+			claims: map[string]interface{}{"iss": issuer.URL, "aud": "simulator", "sub": "alice", "exp": now.Add(time.Hour).Unix()},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			authenticate := NewOIDCAuthenticator(opts, issuer.Client())
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+signRS256(t, tt.key, tt.claims))
+
+			got, err := authenticate(req)
+			if tt.want == nil {
+				assert.ErrorIs(t, err, ErrUnauthenticated)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewOIDCAuthenticator_concurrentRequests(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	var keysFetched atomic.Int32
+	issuer := newFakeIssuer(t, &key.PublicKey, func() {
+		keysFetched.Add(1)
+		// keep the fetch in flight until all the requests arrive.
+		time.Sleep(100 * time.Millisecond)
+	})
+	authenticate := NewOIDCAuthenticator(&OIDCOptions{IssuerURL: issuer.URL, Audience: "simulator"}, issuer.Client())
+	token := signRS256(t, key, map[string]interface{}{"iss": issuer.URL, "aud": "simulator", "sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})
+
+	const requests = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			_, err := authenticate(req)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), keysFetched.Load(), "the requests arriving while the keys are fetched share the fetch")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	_, err = authenticate(req)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), keysFetched.Load(), "the known key isn't fetched again")
+}
+
+const testKeyID = "key-1"
+
+// newFakeIssuer starts the server serving the discovery document and the JWKS with the key.
+// onKeys is called on each request to the JWKS if non-nil.
+func newFakeIssuer(t *testing.T, key *rsa.PublicKey, onKeys func()) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": s.URL, "jwks_uri": s.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		if onKeys != nil {
+			onKeys()
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKeyID,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	return s
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, claims map[string]interface{}) string {
+	t.Helper()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": testKeyID, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestIdentityFrom(t *testing.T) {
+	t.Parallel()
+	id := &Identity{Name: "viewer", Role: RoleReadOnly}
+	assert.Equal(t, id, IdentityFrom(WithIdentity(context.Background(), id)))
+	assert.Nil(t, IdentityFrom(context.Background()))
+}
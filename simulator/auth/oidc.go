@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/xerrors"
+)
+
+// oidcRequestTimeout is the timeout of the requests to the issuer, not to block the authentication forever.
+const oidcRequestTimeout = 10 * time.Second
+
+// supportedSigningAlgs are the algorithms of the signatures of the ID tokens accepted by the simulator.
+var supportedSigningAlgs = []string{oidc.RS256, oidc.RS384, oidc.RS512, oidc.ES256, oidc.ES384, oidc.ES512}
+
+// oidcVerifier verifies the ID tokens issued by the issuer with go-oidc.
+type oidcVerifier struct {
+	opts *OIDCOptions
+	// ctx is used to discover the issuer and to fetch its keys, which outlive the requests.
+	ctx context.Context
+
+	// discovering deduplicates the concurrent discoveries of the issuer.
+	discovering singleflight.Group
+
+	mu       sync.Mutex
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewOIDCAuthenticator returns Authenticator accepting the ID tokens issued by opts.IssuerURL for opts.Audience.
+// The issuer is discovered with client on the first request, not to block the simulator from starting,
+// and discovered again on the next request if it fails.
+// The client should have the timeout since the requests with the unknown key wait for the fetch.
+func NewOIDCAuthenticator(opts *OIDCOptions, client *http.Client) Authenticator {
+	v := &oidcVerifier{opts: opts, ctx: oidc.ClientContext(context.Background(), client)}
+	return func(r *http.Request) (*Identity, error) {
+		token, ok := bearerToken(r)
+		if !ok {
+			return nil, xerrors.Errorf("bearer token is required: %w", ErrUnauthenticated)
+		}
+		return v.verify(r.Context(), token)
+	}
+}
+
+func (v *oidcVerifier) verify(ctx context.Context, token string) (*Identity, error) {
+	verifier, err := v.idTokenVerifier()
+	if err != nil {
+		return nil, xerrors.Errorf("discover issuer: %w", err)
+	}
+	idToken, err := verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, xerrors.Errorf("verify ID token: %v: %w", err, ErrUnauthenticated)
+	}
+	if idToken.Subject == "" {
+		return nil, xerrors.Errorf("the ID token doesn't have sub: %w", ErrUnauthenticated)
+	}
+	// the groups are decoded from the raw claims since their claim name is configurable.
+	claims := map[string]json.RawMessage{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, xerrors.Errorf("decode claims of ID token: %v: %w", err, ErrUnauthenticated)
+	}
+	return &Identity{Name: idToken.Subject, Role: v.role(claims)}, nil
+}
+
+// idTokenVerifier returns the verifier of the issuer, discovering the issuer if it hasn't been discovered yet.
+// The concurrent requests share the same discovery.
+func (v *oidcVerifier) idTokenVerifier() (*oidc.IDTokenVerifier, error) {
+	v.mu.Lock()
+	verifier := v.verifier
+	v.mu.Unlock()
+	if verifier != nil {
+		return verifier, nil
+	}
+
+	ret, err, _ := v.discovering.Do("discovery", func() (interface{}, error) {
+		provider, err := oidc.NewProvider(v.ctx, v.opts.IssuerURL)
+		if err != nil {
+			return nil, err
+		}
+		verifier := provider.Verifier(&oidc.Config{ClientID: v.opts.Audience, SupportedSigningAlgs: supportedSigningAlgs})
+		v.mu.Lock()
+		defer v.mu.Unlock()
+		v.verifier = verifier
+		return verifier, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret.(*oidc.IDTokenVerifier), nil
+}
+
+// role returns RoleReadWrite if the user is in any of ReadWriteGroups.
+func (v *oidcVerifier) role(claims map[string]json.RawMessage) Role {
+	claim := v.opts.GroupsClaim
+	if claim == "" {
+		claim = "groups"
+	}
+	var groups []string
+	if raw, ok := claims[claim]; ok {
+		if err := json.Unmarshal(raw, &groups); err != nil {
+			// some issuers put the single group as a string.
+			var g string
+			if json.Unmarshal(raw, &g) == nil {
+				groups = []string{g}
+			}
+		}
+	}
+	for _, g := range groups {
+		for _, rw := range v.opts.ReadWriteGroups {
+			if g == rw {
+				return RoleReadWrite
+			}
+		}
+	}
+	return RoleReadOnly
+}
@@ -0,0 +1,356 @@
+// Package bulknode creates a fleet of Nodes from a template at once, for capacity planning.
+package bulknode
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/validation"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+)
+
+// ErrInvalidCreateOptions is returned when the given CreateOptions is invalid.
+var ErrInvalidCreateOptions = errors.New("invalid bulk node creation options")
+
+const (
+	// MaxCount is the maximum number of Nodes created by one request.
+	MaxCount = 5000
+	// BatchLabelKey is the label put on the created Nodes, whose value is the ID of the batch.
+	// The Nodes created together can be deleted with it, e.g., `kubectl delete nodes -l <BatchLabelKey>=<batch ID>`.
+	BatchLabelKey = "kube-scheduler-simulator.sigs.k8s.io/node-batch"
+	// defaultMaxPods is the number of Pods allowed on the created Node when the template doesn't have it,
+	// which is the default of kubelet.
+	defaultMaxPods = "110"
+)
+
+// ResourceApplier applies the generated Nodes to the simulator.
+type ResourceApplier interface {
+	ApplyAll(ctx context.Context, resources []unstructured.Unstructured, opts resourceapplier.ApplyAllOptions) ([]resourceapplier.Result, error)
+	Create(ctx context.Context, resource *unstructured.Unstructured) error
+	UpdateStatus(ctx context.Context, resource *unstructured.Unstructured) error
+	Get(ctx context.Context, resource *unstructured.Unstructured) (*unstructured.Unstructured, error)
+}
+
+// CreateOptions is the options for creating Nodes in bulk.
+type CreateOptions struct {
+	// Template is the Node the created Nodes are generated from.
+	// Either Template or CloneFrom must be given.
+	Template *unstructured.Unstructured
+	// CloneFrom is the name of the Node in the simulator which is used as the template.
+	CloneFrom string
+	// Count is the number of Nodes to create.
+	// It can be omitted when Groups is given, and must be the sum of the counts of Groups otherwise.
+	Count int
+	// NamePrefix is the prefix of the Nodes' names, which are "<NamePrefix>-<index>".
+	NamePrefix string
+	// Groups overrides the capacity of the Nodes group by group.
+	// The Nodes are assigned to the groups in order, e.g., the first Groups[0].Count Nodes belong to Groups[0].
+	Groups []Group
+	// Labels is added to all the Nodes. It takes precedence over the labels of the template and Groups.
+	Labels map[string]string
+	// Concurrency is the maximum number of Nodes created concurrently.
+	// If zero, resourceapplier.DefaultWorkers is used.
+	Concurrency int
+}
+
+// Group is a group of the Nodes with the same capacity.
+type Group struct {
+	// Count is the number of Nodes in the group.
+	Count int `json:"count"`
+	// Capacity overrides the capacity and allocatable of the template for the resources in it.
+	// It can have the extended resources, e.g., nvidia.com/gpu, as well as cpu and memory.
+	Capacity v1.ResourceList `json:"capacity"`
+	// Labels is added to the Nodes in the group.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// CreateSummary is the result of creating Nodes in bulk.
+type CreateSummary struct {
+	// BatchID is the value of BatchLabelKey on the created Nodes.
+	BatchID   string        `json:"batchID"`
+	Requested int           `json:"requested"`
+	Created   int           `json:"created"`
+	Failures  []NodeFailure `json:"failures,omitempty"`
+}
+
+// NodeFailure is a Node which failed to be created.
+type NodeFailure struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// Service creates Nodes in bulk.
+type Service struct {
+	applier ResourceApplier
+}
+
+// NewService initializes Service.
+func NewService(applier ResourceApplier) *Service {
+	return &Service{applier: applier}
+}
+
+// Create generates Nodes from the template and creates them with their status,
+// so that Pods can be scheduled onto them right away.
+// The Nodes failing to be created don't stop the others, and they're reported in CreateSummary.Failures.
+func (s *Service) Create(ctx context.Context, opts CreateOptions) (*CreateSummary, error) {
+	opts, err := completeCreateOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	template := opts.Template
+	if opts.CloneFrom != "" {
+		template, err = s.getNode(ctx, opts.CloneFrom)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	batchID := string(uuid.NewUUID())
+	nodes, err := generate(template, batchID, opts)
+	if err != nil {
+		// The template is the only thing which can make it fail.
+		return nil, xerrors.Errorf("generate nodes: %v: %w", err, ErrInvalidCreateOptions)
+	}
+	indexes := make(map[string]int, len(nodes))
+	for i := range nodes {
+		indexes[nodes[i].GetName()] = i
+	}
+
+	results, err := s.applier.ApplyAll(ctx, nodes, resourceapplier.ApplyAllOptions{
+		Workers: opts.Concurrency,
+		Apply:   s.createWithStatus,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("apply nodes: %w", err)
+	}
+
+	summary := &CreateSummary{BatchID: batchID, Requested: opts.Count}
+	for _, r := range results {
+		if r.Err == nil {
+			summary.Created++
+			continue
+		}
+		summary.Failures = append(summary.Failures, NodeFailure{
+			Index: indexes[r.Resource.GetName()],
+			Name:  r.Resource.GetName(),
+			Error: r.Err.Error(),
+		})
+	}
+	// The Nodes are created concurrently.
+	sort.Slice(summary.Failures, func(i, j int) bool {
+		return summary.Failures[i].Index < summary.Failures[j].Index
+	})
+
+	return summary, nil
+}
+
+// createWithStatus creates the node and then updates its status,
+// because the status is dropped when the node is created.
+func (s *Service) createWithStatus(ctx context.Context, node *unstructured.Unstructured) error {
+	withStatus := node.DeepCopy()
+	if err := s.applier.Create(ctx, node); err != nil {
+		return err
+	}
+	if err := s.applier.UpdateStatus(ctx, withStatus); err != nil {
+		return xerrors.Errorf("update status: %w", err)
+	}
+	return nil
+}
+
+// getNode gets the node to clone from the simulator.
+func (s *Service) getNode(ctx context.Context, name string) (*unstructured.Unstructured, error) {
+	ref := &unstructured.Unstructured{}
+	ref.SetAPIVersion("v1")
+	ref.SetKind("Node")
+	ref.SetName(name)
+	node, err := s.applier.Get(ctx, ref)
+	if err != nil {
+		// Don't care about what the error is, since the node to clone is given by the user.
+		return nil, xerrors.Errorf("get node %s to clone: %v: %w", name, err, ErrInvalidCreateOptions)
+	}
+	return node, nil
+}
+
+// completeCreateOptions fills the count from the groups and validates opts.
+func completeCreateOptions(opts CreateOptions) (CreateOptions, error) {
+	if (opts.Template == nil) == (opts.CloneFrom == "") {
+		return opts, xerrors.Errorf("either template or the node to clone must be given: %w", ErrInvalidCreateOptions)
+	}
+	if opts.Template != nil {
+		if gvk := opts.Template.GroupVersionKind(); (gvk.Kind != "" && gvk.Kind != "Node") || gvk.Group != "" {
+			return opts, xerrors.Errorf("template of %s, not Node: %w", gvk.String(), ErrInvalidCreateOptions)
+		}
+	}
+	if len(opts.Groups) > 0 {
+		total := 0
+		for i, g := range opts.Groups {
+			if g.Count <= 0 {
+				return opts, xerrors.Errorf("non-positive count %d of group %d: %w", g.Count, i, ErrInvalidCreateOptions)
+			}
+			if err := validateExtendedResources(g.Capacity); err != nil {
+				return opts, xerrors.Errorf("capacity of group %d: %v: %w", i, err, ErrInvalidCreateOptions)
+			}
+			total += g.Count
+		}
+		if opts.Count == 0 {
+			opts.Count = total
+		}
+		if opts.Count != total {
+			return opts, xerrors.Errorf("count %d doesn't match the sum of the counts of the groups %d: %w", opts.Count, total, ErrInvalidCreateOptions)
+		}
+	}
+	if opts.Count <= 0 || opts.Count > MaxCount {
+		return opts, xerrors.Errorf("count %d out of range (1-%d): %w", opts.Count, MaxCount, ErrInvalidCreateOptions)
+	}
+	if opts.NamePrefix == "" {
+		return opts, xerrors.Errorf("no name prefix: %w", ErrInvalidCreateOptions)
+	}
+	// The longest name must be a valid Node name.
+	if errs := validation.IsDNS1123Subdomain(nodeName(opts.NamePrefix, opts.Count-1)); len(errs) > 0 {
+		return opts, xerrors.Errorf("invalid name prefix %q: %s: %w", opts.NamePrefix, strings.Join(errs, ", "), ErrInvalidCreateOptions)
+	}
+	if opts.Concurrency < 0 {
+		return opts, xerrors.Errorf("negative concurrency %d: %w", opts.Concurrency, ErrInvalidCreateOptions)
+	}
+	return opts, nil
+}
+
+// generate generates the Nodes to create from the template.
+func generate(template *unstructured.Unstructured, batchID string, opts CreateOptions) ([]unstructured.Unstructured, error) {
+	var base v1.Node
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template.UnstructuredContent(), &base); err != nil {
+		return nil, xerrors.Errorf("convert template to node: %w", err)
+	}
+	base.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Node"}
+	// Only the labels, annotations and taints are inherited from the template.
+	base.ObjectMeta = metav1.ObjectMeta{Labels: base.Labels, Annotations: base.Annotations}
+	base.Spec = v1.NodeSpec{Taints: base.Spec.Taints}
+	if err := validateExtendedResources(base.Status.Capacity); err != nil {
+		return nil, xerrors.Errorf("capacity of template: %w", err)
+	}
+	if err := validateExtendedResources(base.Status.Allocatable); err != nil {
+		return nil, xerrors.Errorf("allocatable of template: %w", err)
+	}
+
+	nodes := make([]unstructured.Unstructured, 0, opts.Count)
+	for i := 0; i < opts.Count; i++ {
+		node := base.DeepCopy()
+		node.Name = nodeName(opts.NamePrefix, i)
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[v1.LabelHostname] = node.Name
+		node.Labels[BatchLabelKey] = batchID
+
+		if g := groupOf(opts.Groups, i); g != nil {
+			for k, v := range g.Labels {
+				node.Labels[k] = v
+			}
+			overrideCapacity(&node.Status, g.Capacity)
+		}
+		for k, v := range opts.Labels {
+			node.Labels[k] = v
+		}
+		completeStatus(&node.Status)
+
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(node)
+		if err != nil {
+			return nil, xerrors.Errorf("convert node %d to unstructured: %w", i, err)
+		}
+		nodes = append(nodes, unstructured.Unstructured{Object: obj})
+	}
+	return nodes, nil
+}
+
+// groupOf returns the group which the index-th Node belongs to, or nil if there is no group.
+func groupOf(groups []Group, index int) *Group {
+	for i := range groups {
+		if index < groups[i].Count {
+			return &groups[i]
+		}
+		index -= groups[i].Count
+	}
+	return nil
+}
+
+// overrideCapacity overrides the capacity and allocatable in status with capacity.
+func overrideCapacity(status *v1.NodeStatus, capacity v1.ResourceList) {
+	if len(capacity) == 0 {
+		return
+	}
+	if status.Capacity == nil {
+		status.Capacity = v1.ResourceList{}
+	}
+	if status.Allocatable == nil {
+		status.Allocatable = v1.ResourceList{}
+	}
+	for name, q := range capacity {
+		status.Capacity[name] = q.DeepCopy()
+		status.Allocatable[name] = q.DeepCopy()
+	}
+}
+
+// completeStatus makes the status of the Node ready to accept Pods.
+// The allocatable defaults to the capacity, and the Node is Ready.
+func completeStatus(status *v1.NodeStatus) {
+	if status.Capacity == nil {
+		status.Capacity = v1.ResourceList{}
+	}
+	if _, ok := status.Capacity[v1.ResourcePods]; !ok {
+		status.Capacity[v1.ResourcePods] = resource.MustParse(defaultMaxPods)
+	}
+	if status.Allocatable == nil {
+		status.Allocatable = v1.ResourceList{}
+	}
+	for name, q := range status.Capacity {
+		if _, ok := status.Allocatable[name]; !ok {
+			status.Allocatable[name] = q.DeepCopy()
+		}
+	}
+
+	now := metav1.Now()
+	ready := v1.NodeCondition{
+		Type:               v1.NodeReady,
+		Status:             v1.ConditionTrue,
+		Reason:             "KubeletReady",
+		Message:            "created by the simulator",
+		LastHeartbeatTime:  now,
+		LastTransitionTime: now,
+	}
+	conditions := []v1.NodeCondition{ready}
+	for _, c := range status.Conditions {
+		if c.Type != v1.NodeReady {
+			conditions = append(conditions, c)
+		}
+	}
+	status.Conditions = conditions
+}
+
+// validateExtendedResources checks that the quantities of the extended resources are whole numbers,
+// because the Pods can only request them in whole numbers.
+func validateExtendedResources(resources v1.ResourceList) error {
+	for name, q := range resources {
+		if v1helper.IsExtendedResourceName(name) && q.MilliValue()%1000 != 0 {
+			return xerrors.Errorf("extended resource %s must be a whole number, but %s", name, q.String())
+		}
+	}
+	return nil
+}
+
+func nodeName(prefix string, index int) string {
+	return prefix + "-" + strconv.Itoa(index)
+}
@@ -0,0 +1,328 @@
+package bulknode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/noderesources"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+)
+
+var nodesGVR = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+
+const gpuResource v1.ResourceName = "nvidia.com/gpu"
+
+func TestService_Create(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		existingNodes []*v1.Node
+		opts          CreateOptions
+		wantCreated   int
+		wantFailures  []int
+		// wantCPU is the CPU capacity of the nodes by the name.
+		wantCPU    map[string]string
+		wantLabels map[string]map[string]string
+	}{
+		{
+			name: "create the nodes in the groups",
+			opts: CreateOptions{
+				Template:   nodeTemplate(t, nil),
+				NamePrefix: "fleet",
+				Groups: []Group{
+					{Count: 50, Capacity: v1.ResourceList{v1.ResourceCPU: resource.MustParse("16")}, Labels: map[string]string{"size": "large"}},
+					{Count: 150, Capacity: v1.ResourceList{v1.ResourceCPU: resource.MustParse("8")}},
+				},
+			},
+			wantCreated: 200,
+			wantCPU:     map[string]string{"fleet-0": "16", "fleet-49": "16", "fleet-50": "8", "fleet-199": "8"},
+			wantLabels: map[string]map[string]string{
+				"fleet-0":  {"zone": "a", "size": "large", v1.LabelHostname: "fleet-0"},
+				"fleet-50": {"zone": "a", v1.LabelHostname: "fleet-50"},
+			},
+		},
+		{
+			name: "the labels for all the nodes take precedence over the ones of the groups",
+			opts: CreateOptions{
+				Template:   nodeTemplate(t, nil),
+				NamePrefix: "fleet",
+				Groups:     []Group{{Count: 2, Labels: map[string]string{"size": "large", "team": "a"}}},
+				Labels:     map[string]string{"team": "b"},
+			},
+			wantCreated: 2,
+			wantLabels: map[string]map[string]string{
+				"fleet-1": {"zone": "a", "size": "large", "team": "b", v1.LabelHostname: "fleet-1"},
+			},
+		},
+		{
+			name: "create the nodes with the capacity of the template",
+			opts: CreateOptions{
+				Template:   nodeTemplate(t, nil),
+				Count:      10,
+				NamePrefix: "fleet",
+			},
+			wantCreated: 10,
+			wantCPU:     map[string]string{"fleet-0": "4", "fleet-9": "4"},
+		},
+		{
+			name:          "clone the existing node",
+			existingNodes: []*v1.Node{existingNode("node-a", "32")},
+			opts: CreateOptions{
+				CloneFrom:  "node-a",
+				Count:      5,
+				NamePrefix: "clone",
+			},
+			wantCreated: 5,
+			wantCPU:     map[string]string{"clone-0": "32", "clone-4": "32"},
+			wantLabels: map[string]map[string]string{
+				"clone-0": {"zone": "b", v1.LabelHostname: "clone-0"},
+			},
+		},
+		{
+			name:          "report the nodes failing to be created by their indexes",
+			existingNodes: []*v1.Node{existingNode("fleet-2", "4")},
+			opts: CreateOptions{
+				Template:   nodeTemplate(t, nil),
+				Count:      5,
+				NamePrefix: "fleet",
+			},
+			wantCreated:  4,
+			wantFailures: []int{2},
+			wantCPU:      map[string]string{"fleet-0": "4"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, mapper := prepare()
+			for _, n := range tt.existingNodes {
+				obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(n)
+				require.NoError(t, err)
+				_, err = client.Resource(nodesGVR).Create(context.Background(), &unstructured.Unstructured{Object: obj}, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			s := NewService(resourceapplier.New(client, mapper, resourceapplier.Options{}))
+
+			summary, err := s.Create(context.Background(), tt.opts)
+			require.NoError(t, err)
+			assert.NotEmpty(t, summary.BatchID)
+			assert.Equal(t, tt.wantCreated+len(tt.wantFailures), summary.Requested)
+			assert.Equal(t, tt.wantCreated, summary.Created)
+			gotFailures := []int{}
+			for _, f := range summary.Failures {
+				assert.Equal(t, nodeName(tt.opts.NamePrefix, f.Index), f.Name)
+				gotFailures = append(gotFailures, f.Index)
+			}
+			assert.ElementsMatch(t, tt.wantFailures, gotFailures)
+
+			list, err := client.Resource(nodesGVR).List(context.Background(), metav1.ListOptions{LabelSelector: BatchLabelKey + "=" + summary.BatchID})
+			require.NoError(t, err)
+			assert.Len(t, list.Items, tt.wantCreated)
+			nodes := map[string]*v1.Node{}
+			for _, item := range list.Items {
+				var node v1.Node
+				require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &node))
+				nodes[node.Name] = &node
+				assertSchedulable(t, &node)
+			}
+			for name, cpu := range tt.wantCPU {
+				require.Contains(t, nodes, name)
+				want := resource.MustParse(cpu)
+				assert.True(t, want.Equal(nodes[name].Status.Capacity[v1.ResourceCPU]), "capacity of %s", name)
+				assert.True(t, want.Equal(nodes[name].Status.Allocatable[v1.ResourceCPU]), "allocatable of %s", name)
+			}
+			for name, labels := range tt.wantLabels {
+				require.Contains(t, nodes, name)
+				for k, v := range labels {
+					assert.Equal(t, v, nodes[name].Labels[k], "label %s of %s", k, name)
+				}
+			}
+		})
+	}
+}
+
+func TestService_Create_invalidOptions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		opts CreateOptions
+	}{
+		{
+			name: "neither template nor the node to clone",
+			opts: CreateOptions{Count: 1, NamePrefix: "fleet"},
+		},
+		{
+			name: "both template and the node to clone",
+			opts: CreateOptions{Template: nodeTemplate(t, nil), CloneFrom: "node-a", Count: 1, NamePrefix: "fleet"},
+		},
+		{
+			name: "the node to clone doesn't exist",
+			opts: CreateOptions{CloneFrom: "node-a", Count: 1, NamePrefix: "fleet"},
+		},
+		{
+			name: "count mismatching the groups",
+			opts: CreateOptions{Template: nodeTemplate(t, nil), Count: 3, NamePrefix: "fleet", Groups: []Group{{Count: 1}, {Count: 1}}},
+		},
+		{
+			name: "group without nodes",
+			opts: CreateOptions{Template: nodeTemplate(t, nil), NamePrefix: "fleet", Groups: []Group{{Count: 0}}},
+		},
+		{
+			name: "too many nodes",
+			opts: CreateOptions{Template: nodeTemplate(t, nil), Count: MaxCount + 1, NamePrefix: "fleet"},
+		},
+		{
+			name: "fractional extended resource in the group",
+			opts: CreateOptions{Template: nodeTemplate(t, nil), NamePrefix: "fleet", Groups: []Group{{Count: 1, Capacity: v1.ResourceList{gpuResource: resource.MustParse("500m")}}}},
+		},
+		{
+			name: "fractional extended resource in the template",
+			opts: CreateOptions{Template: nodeTemplate(t, func(u *unstructured.Unstructured) {
+				require.NoError(t, unstructured.SetNestedField(u.Object, "0.5", "status", "capacity", string(gpuResource)))
+			}), Count: 1, NamePrefix: "fleet"},
+		},
+		{
+			name: "template of another kind",
+			opts: CreateOptions{Template: nodeTemplate(t, func(u *unstructured.Unstructured) { u.SetKind("Pod") }), Count: 1, NamePrefix: "fleet"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, mapper := prepare()
+			s := NewService(resourceapplier.New(client, mapper, resourceapplier.Options{}))
+
+			_, err := s.Create(context.Background(), tt.opts)
+			assert.ErrorIs(t, err, ErrInvalidCreateOptions)
+		})
+	}
+}
+
+func TestService_Create_extendedResources(t *testing.T) {
+	t.Parallel()
+	client, mapper := prepare()
+	s := NewService(resourceapplier.New(client, mapper, resourceapplier.Options{}))
+
+	// Only the first node has GPUs, and the template has a resource of another vendor on all the nodes.
+	template := nodeTemplate(t, func(u *unstructured.Unstructured) {
+		require.NoError(t, unstructured.SetNestedField(u.Object, "2", "status", "capacity", "example.com/fpga"))
+	})
+	summary, err := s.Create(context.Background(), CreateOptions{
+		Template:   template,
+		NamePrefix: "fleet",
+		Groups: []Group{
+			{Count: 1, Capacity: v1.ResourceList{gpuResource: resource.MustParse("4")}, Labels: map[string]string{"accelerator": "gpu"}},
+			{Count: 3},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, summary.Created)
+
+	list, err := client.Resource(nodesGVR).List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 4)
+	gpuPod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{
+		Resources: v1.ResourceRequirements{Requests: v1.ResourceList{gpuResource: resource.MustParse("1")}},
+	}}}}
+	fits := []string{}
+	for _, item := range list.Items {
+		var node v1.Node
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &node))
+		fpga := resource.MustParse("2")
+		assert.True(t, fpga.Equal(node.Status.Allocatable["example.com/fpga"]), "allocatable of %s", node.Name)
+
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(&node)
+		if len(noderesources.Fits(gpuPod, nodeInfo, noderesources.ResourceRequestsOptions{})) == 0 {
+			fits = append(fits, node.Name)
+		}
+	}
+	assert.Equal(t, []string{"fleet-0"}, fits, "the GPU pod fits only the GPU node")
+}
+
+// assertSchedulable checks that the node is Ready and has the room for a Pod requesting 1 CPU.
+func assertSchedulable(t *testing.T, node *v1.Node) {
+	t.Helper()
+	ready := false
+	for _, c := range node.Status.Conditions {
+		if c.Type == v1.NodeReady {
+			ready = c.Status == v1.ConditionTrue
+		}
+	}
+	assert.True(t, ready, "node %s isn't ready", node.Name)
+	assert.False(t, node.Spec.Unschedulable)
+
+	pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{
+		Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}},
+	}}}}
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(node)
+	assert.Empty(t, noderesources.Fits(pod, nodeInfo, noderesources.ResourceRequestsOptions{}), "pod doesn't fit node %s", node.Name)
+}
+
+func nodeTemplate(t *testing.T, mutate func(*unstructured.Unstructured)) *unstructured.Unstructured {
+	t.Helper()
+	node := &v1.Node{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"zone": "a"}},
+		Status: v1.NodeStatus{
+			Capacity: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("4"),
+				v1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(node)
+	require.NoError(t, err)
+	u := &unstructured.Unstructured{Object: obj}
+	if mutate != nil {
+		mutate(u)
+	}
+	return u
+}
+
+func existingNode(name, cpu string) *v1.Node {
+	return &v1.Node{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"zone": "b", v1.LabelHostname: name}},
+		Spec:       v1.NodeSpec{ProviderID: "fake://" + name},
+		Status: v1.NodeStatus{
+			Capacity:    v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu), v1.ResourcePods: resource.MustParse("110")},
+			Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu), v1.ResourcePods: resource.MustParse("110")},
+			Conditions:  []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionFalse}},
+		},
+	}
+}
+
+func prepare() (*dynamicFake.FakeDynamicClient, meta.RESTMapper) {
+	client := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{nodesGVR: "NodeList"})
+	mapper := restmapper.NewDiscoveryRESTMapper([]*restmapper.APIGroupResources{
+		{
+			Group: metav1.APIGroup{
+				Versions: []metav1.GroupVersionForDiscovery{{Version: "v1"}},
+			},
+			VersionedResources: map[string][]metav1.APIResource{
+				"v1": {
+					{Name: "pods", Namespaced: true, Kind: "Pod"},
+					{Name: "nodes", Namespaced: false, Kind: "Node"},
+				},
+			},
+		},
+	})
+	return client, mapper
+}
@@ -0,0 +1,252 @@
+// Package bulkpod creates many Pods from a template at once, for load-style experiments.
+package bulkpod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+)
+
+// ErrInvalidCreateOptions is returned when the given CreateOptions is invalid.
+var ErrInvalidCreateOptions = errors.New("invalid bulk pod creation options")
+
+const (
+	// MaxCount is the maximum number of Pods created by one request.
+	MaxCount = 10000
+	// MaxJitterPercent is the maximum of CreateOptions.ResourceJitterPercent.
+	// The resources can't be jittered by 100% or more since they'd be zero or negative.
+	MaxJitterPercent = 99
+	// IndexPlaceholder is replaced with the index of the Pod in the label values.
+	IndexPlaceholder = "{{index}}"
+)
+
+// ResourceApplier applies the generated Pods to the simulator.
+type ResourceApplier interface {
+	ApplyAll(ctx context.Context, resources []unstructured.Unstructured, opts resourceapplier.ApplyAllOptions) ([]resourceapplier.Result, error)
+}
+
+// CreateOptions is the options for creating Pods in bulk.
+type CreateOptions struct {
+	// Template is the Pod the created Pods are generated from.
+	// Its name is ignored, and its namespace is "default" if empty.
+	Template *unstructured.Unstructured
+	// Count is the number of Pods to create.
+	Count int
+	// NamePrefix is the prefix of the Pods' names, which are "<NamePrefix>-<index>".
+	NamePrefix string
+	// Labels is added to each Pod. IndexPlaceholder in the values is replaced with the index of the Pod.
+	Labels map[string]string
+	// ResourceJitterPercent randomizes the resource requests and limits of each container by up to this percentage.
+	// The requests and limits of a container are scaled by the same factor, so that the limits never fall below the requests.
+	ResourceJitterPercent int
+	// Concurrency is the maximum number of Pods created concurrently.
+	// If zero, resourceapplier.DefaultWorkers is used.
+	Concurrency int
+}
+
+// CreateSummary is the result of creating Pods in bulk.
+type CreateSummary struct {
+	Requested int          `json:"requested"`
+	Created   int          `json:"created"`
+	Failures  []PodFailure `json:"failures,omitempty"`
+}
+
+// PodFailure is a Pod which failed to be created.
+type PodFailure struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// Service creates Pods in bulk.
+type Service struct {
+	applier ResourceApplier
+
+	// randMu protects rand, which isn't safe for concurrent use.
+	randMu sync.Mutex
+	rand   *rand.Rand
+}
+
+// NewService initializes Service.
+func NewService(applier ResourceApplier) *Service {
+	return &Service{
+		applier: applier,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec // the jitter doesn't need to be secure.
+	}
+}
+
+// Create generates opts.Count Pods from opts.Template and creates them.
+// The Pods failing to be created don't stop the others, and they're reported in CreateSummary.Failures.
+func (s *Service) Create(ctx context.Context, opts CreateOptions) (*CreateSummary, error) {
+	if err := validateCreateOptions(opts); err != nil {
+		return nil, err
+	}
+
+	pods, err := s.generate(opts)
+	if err != nil {
+		// The template is the only thing which can make it fail.
+		return nil, xerrors.Errorf("generate pods: %v: %w", err, ErrInvalidCreateOptions)
+	}
+	indexes := make(map[string]int, len(pods))
+	for i := range pods {
+		indexes[pods[i].GetName()] = i
+	}
+
+	results, err := s.applier.ApplyAll(ctx, pods, resourceapplier.ApplyAllOptions{Workers: opts.Concurrency})
+	if err != nil {
+		return nil, xerrors.Errorf("apply pods: %w", err)
+	}
+
+	summary := &CreateSummary{Requested: opts.Count}
+	for _, r := range results {
+		if r.Err == nil {
+			summary.Created++
+			continue
+		}
+		summary.Failures = append(summary.Failures, PodFailure{
+			Index: indexes[r.Resource.GetName()],
+			Name:  r.Resource.GetName(),
+			Error: r.Err.Error(),
+		})
+	}
+	// The Pods are created concurrently.
+	sort.Slice(summary.Failures, func(i, j int) bool {
+		return summary.Failures[i].Index < summary.Failures[j].Index
+	})
+
+	return summary, nil
+}
+
+func validateCreateOptions(opts CreateOptions) error {
+	if opts.Template == nil {
+		return xerrors.Errorf("no template: %w", ErrInvalidCreateOptions)
+	}
+	if gvk := opts.Template.GroupVersionKind(); (gvk.Kind != "" && gvk.Kind != "Pod") || (gvk.Group != "") {
+		return xerrors.Errorf("template of %s, not Pod: %w", gvk.String(), ErrInvalidCreateOptions)
+	}
+	if opts.Count <= 0 || opts.Count > MaxCount {
+		return xerrors.Errorf("count %d out of range (1-%d): %w", opts.Count, MaxCount, ErrInvalidCreateOptions)
+	}
+	if opts.NamePrefix == "" {
+		return xerrors.Errorf("no name prefix: %w", ErrInvalidCreateOptions)
+	}
+	// The longest name must be a valid Pod name.
+	if errs := validation.IsDNS1123Subdomain(podName(opts.NamePrefix, opts.Count-1)); len(errs) > 0 {
+		return xerrors.Errorf("invalid name prefix %q: %s: %w", opts.NamePrefix, strings.Join(errs, ", "), ErrInvalidCreateOptions)
+	}
+	if opts.ResourceJitterPercent < 0 || opts.ResourceJitterPercent > MaxJitterPercent {
+		return xerrors.Errorf("resource jitter percent %d out of range (0-%d): %w", opts.ResourceJitterPercent, MaxJitterPercent, ErrInvalidCreateOptions)
+	}
+	if opts.Concurrency < 0 {
+		return xerrors.Errorf("negative concurrency %d: %w", opts.Concurrency, ErrInvalidCreateOptions)
+	}
+	return nil
+}
+
+// generate generates the Pods to create from opts.
+func (s *Service) generate(opts CreateOptions) ([]unstructured.Unstructured, error) {
+	pods := make([]unstructured.Unstructured, 0, opts.Count)
+	for i := 0; i < opts.Count; i++ {
+		pod := opts.Template.DeepCopy()
+		pod.SetAPIVersion("v1")
+		pod.SetKind("Pod")
+		pod.SetName(podName(opts.NamePrefix, i))
+		pod.SetGenerateName("")
+		if pod.GetNamespace() == "" {
+			pod.SetNamespace(metav1.NamespaceDefault)
+		}
+
+		if len(opts.Labels) > 0 {
+			labels := pod.GetLabels()
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			for k, v := range opts.Labels {
+				labels[k] = strings.ReplaceAll(v, IndexPlaceholder, strconv.Itoa(i))
+			}
+			pod.SetLabels(labels)
+		}
+
+		if opts.ResourceJitterPercent > 0 {
+			if err := s.jitterResources(pod, opts.ResourceJitterPercent); err != nil {
+				return nil, xerrors.Errorf("jitter resources of pod %d: %w", i, err)
+			}
+		}
+
+		pods = append(pods, *pod)
+	}
+	return pods, nil
+}
+
+// jitterResources scales the resource requests and limits of each container in the pod
+// by a random factor within ±percent%.
+func (s *Service) jitterResources(pod *unstructured.Unstructured, percent int) error {
+	for _, field := range []string{"containers", "initContainers"} {
+		containers, found, err := unstructured.NestedSlice(pod.Object, "spec", field)
+		if err != nil {
+			return xerrors.Errorf("get %s: %w", field, err)
+		}
+		if !found {
+			continue
+		}
+		for i := range containers {
+			container, ok := containers[i].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			factor := s.jitterFactor(percent)
+			for _, kind := range []string{"requests", "limits"} {
+				if err := scaleResources(container, kind, factor); err != nil {
+					return xerrors.Errorf("scale %s of %s[%d]: %w", kind, field, i, err)
+				}
+			}
+		}
+		if err := unstructured.SetNestedSlice(pod.Object, containers, "spec", field); err != nil {
+			return xerrors.Errorf("set %s: %w", field, err)
+		}
+	}
+	return nil
+}
+
+// jitterFactor returns a random factor within [1-percent/100, 1+percent/100].
+func (s *Service) jitterFactor(percent int) float64 {
+	s.randMu.Lock()
+	defer s.randMu.Unlock()
+	return 1 + float64(percent)/100*(2*s.rand.Float64()-1)
+}
+
+// scaleResources scales the quantities in container.resources.<kind> by factor.
+func scaleResources(container map[string]interface{}, kind string, factor float64) error {
+	quantities, found, err := unstructured.NestedMap(container, "resources", kind)
+	if err != nil || !found {
+		return err
+	}
+	for name, v := range quantities {
+		// The quantities can be numbers when the template is given in JSON or YAML, e.g., `cpu: 1`.
+		q, err := resource.ParseQuantity(fmt.Sprint(v))
+		if err != nil {
+			return xerrors.Errorf("parse quantity %v of %s: %w", v, name, err)
+		}
+		scaled := resource.NewMilliQuantity(int64(float64(q.MilliValue())*factor), q.Format)
+		quantities[name] = scaled.String()
+	}
+	return unstructured.SetNestedMap(container, quantities, "resources", kind)
+}
+
+func podName(prefix string, index int) string {
+	return prefix + "-" + strconv.Itoa(index)
+}
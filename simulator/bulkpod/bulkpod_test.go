@@ -0,0 +1,230 @@
+package bulkpod
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/restmapper"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+)
+
+var podsGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+func TestService_Create(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		existingPods  []string
+		opts          CreateOptions
+		wantCreated   int
+		wantFailures  []int
+		wantJitterPct int
+	}{
+		{
+			name: "create 100 pods with the labels substituted",
+			opts: CreateOptions{
+				Template:   podTemplate(t),
+				Count:      100,
+				NamePrefix: "load",
+				Labels:     map[string]string{"index": "pod-{{index}}", "app": "load"},
+			},
+			wantCreated: 100,
+		},
+		{
+			name: "create 100 pods with the resources jittered",
+			opts: CreateOptions{
+				Template:              podTemplate(t),
+				Count:                 100,
+				NamePrefix:            "load",
+				ResourceJitterPercent: 20,
+				Concurrency:           4,
+			},
+			wantCreated:   100,
+			wantJitterPct: 20,
+		},
+		{
+			name:         "report the pods failing to be created by their indexes",
+			existingPods: []string{"load-3", "load-42"},
+			opts: CreateOptions{
+				Template:   podTemplate(t),
+				Count:      100,
+				NamePrefix: "load",
+			},
+			wantCreated:  98,
+			wantFailures: []int{3, 42},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, mapper := prepare()
+			for _, name := range tt.existingPods {
+				existing := podTemplate(t)
+				existing.SetName(name)
+				existing.SetNamespace(metav1.NamespaceDefault)
+				_, err := client.Resource(podsGVR).Namespace(metav1.NamespaceDefault).Create(context.Background(), existing, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			s := NewService(resourceapplier.New(client, mapper, resourceapplier.Options{}))
+
+			summary, err := s.Create(context.Background(), tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.opts.Count, summary.Requested)
+			assert.Equal(t, tt.wantCreated, summary.Created)
+			gotFailures := []int{}
+			for _, f := range summary.Failures {
+				assert.Equal(t, podName(tt.opts.NamePrefix, f.Index), f.Name)
+				assert.NotEmpty(t, f.Error)
+				gotFailures = append(gotFailures, f.Index)
+			}
+			assert.ElementsMatch(t, tt.wantFailures, gotFailures)
+
+			list, err := client.Resource(podsGVR).Namespace(metav1.NamespaceDefault).List(context.Background(), metav1.ListOptions{})
+			require.NoError(t, err)
+			assert.Len(t, list.Items, tt.opts.Count)
+			for _, item := range list.Items {
+				var pod v1.Pod
+				require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &pod))
+				if slices.Contains(tt.existingPods, pod.Name) {
+					continue
+				}
+				index := strings.TrimPrefix(pod.Name, tt.opts.NamePrefix+"-")
+				assert.Equal(t, "template", pod.Labels["from"])
+				for k, v := range tt.opts.Labels {
+					assert.Equal(t, strings.ReplaceAll(v, IndexPlaceholder, index), pod.Labels[k])
+				}
+				assertJitter(t, pod.Spec.Containers[0], tt.wantJitterPct)
+			}
+		})
+	}
+}
+
+func TestService_Create_invalidOptions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		opts CreateOptions
+	}{
+		{
+			name: "no template",
+			opts: CreateOptions{Count: 1, NamePrefix: "load"},
+		},
+		{
+			name: "template of another kind",
+			opts: CreateOptions{Template: nodeTemplate(), Count: 1, NamePrefix: "load"},
+		},
+		{
+			name: "zero count",
+			opts: CreateOptions{Template: podTemplate(t), NamePrefix: "load"},
+		},
+		{
+			name: "too many pods",
+			opts: CreateOptions{Template: podTemplate(t), Count: MaxCount + 1, NamePrefix: "load"},
+		},
+		{
+			name: "invalid name prefix",
+			opts: CreateOptions{Template: podTemplate(t), Count: 1, NamePrefix: "Load_"},
+		},
+		{
+			name: "jitter of 100%",
+			opts: CreateOptions{Template: podTemplate(t), Count: 1, NamePrefix: "load", ResourceJitterPercent: 100},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, mapper := prepare()
+			s := NewService(resourceapplier.New(client, mapper, resourceapplier.Options{}))
+
+			_, err := s.Create(context.Background(), tt.opts)
+			assert.ErrorIs(t, err, ErrInvalidCreateOptions)
+		})
+	}
+}
+
+// assertJitter checks that the resources of the container are within ±percent% of the template's,
+// and that the requests and limits are scaled by the same factor.
+func assertJitter(t *testing.T, container v1.Container, percent int) {
+	t.Helper()
+	cpuRequest := container.Resources.Requests[v1.ResourceCPU]
+	memoryRequest := container.Resources.Requests[v1.ResourceMemory]
+	cpuLimit := container.Resources.Limits[v1.ResourceCPU]
+	assertWithin(t, resource.MustParse("500m"), cpuRequest, percent)
+	assertWithin(t, resource.MustParse("1Gi"), memoryRequest, percent)
+	assertWithin(t, resource.MustParse("1"), cpuLimit, percent)
+	assert.GreaterOrEqual(t, cpuLimit.MilliValue(), cpuRequest.MilliValue())
+}
+
+func assertWithin(t *testing.T, base, got resource.Quantity, percent int) {
+	t.Helper()
+	delta := float64(base.MilliValue()) * float64(percent) / 100
+	assert.InDelta(t, float64(base.MilliValue()), float64(got.MilliValue()), delta+1)
+}
+
+func podTemplate(t *testing.T) *unstructured.Unstructured {
+	t.Helper()
+	pod := &v1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "ignored", Labels: map[string]string{"from": "template"}},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:  "app",
+					Image: "registry.k8s.io/pause:3.10",
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceCPU:    resource.MustParse("500m"),
+							v1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+						Limits: v1.ResourceList{
+							v1.ResourceCPU: resource.MustParse("1"),
+						},
+					},
+				},
+			},
+		},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	require.NoError(t, err)
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func nodeTemplate() *unstructured.Unstructured {
+	node := &unstructured.Unstructured{}
+	node.SetAPIVersion("v1")
+	node.SetKind("Node")
+	return node
+}
+
+func prepare() (*dynamicFake.FakeDynamicClient, meta.RESTMapper) {
+	client := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{podsGVR: "PodList"})
+	mapper := restmapper.NewDiscoveryRESTMapper([]*restmapper.APIGroupResources{
+		{
+			Group: metav1.APIGroup{
+				Versions: []metav1.GroupVersionForDiscovery{{Version: "v1"}},
+			},
+			VersionedResources: map[string][]metav1.APIResource{
+				"v1": {
+					{Name: "pods", Namespaced: true, Kind: "Pod"},
+					{Name: "nodes", Namespaced: false, Kind: "Node"},
+				},
+			},
+		},
+	})
+	return client, mapper
+}
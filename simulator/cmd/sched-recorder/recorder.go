@@ -14,6 +14,7 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog"
 
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/oplog"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/recorder"
 )
 
@@ -42,7 +43,7 @@ func startRecorder() error {
 	client := dynamic.NewForConfigOrDie(restCfg)
 
 	recorderOptions := recorder.Options{RecordFile: recordFile}
-	recorder := recorder.New(client, recorderOptions)
+	recorder := recorder.New(client, recorderOptions, oplog.Logger(oplog.ComponentRecorder))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	if duration > 0 {
@@ -1,3 +1,7 @@
+// The scheduler is deterministic with the random seed written by the simulator only when math/rand.Seed takes effect,
+// which is a no-op by default since Go 1.24.
+//
+//go:debug randseednop=0
 package main
 
 import (
@@ -7,50 +7,90 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/spf13/cobra"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"golang.org/x/xerrors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/configreload"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/logging"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/oneshotimporter"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/oplog"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/replayer"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/server"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/grpcserver"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/startup"
 )
 
 const (
-	kubeAPIServerPollInterval = 5 * time.Second
-	kubeAPIServerReadyTimeout = 2 * time.Minute
-	importTimeout             = 2 * time.Minute
+	importTimeout      = 2 * time.Minute
+	jobShutdownTimeout = 10 * time.Second
 )
 
 // entry point.
 func main() {
-	if err := startSimulator(); err != nil {
+	if err := newSimulatorCommand().Execute(); err != nil {
 		klog.Fatalf("failed with error on running simulator: %+v", err)
 	}
 }
 
+// newSimulatorCommand creates the command starting the simulator.
+func newSimulatorCommand() *cobra.Command {
+	opts := &config.Options{}
+	cmd := &cobra.Command{
+		Use:   "simulator",
+		Short: "Start the kube-scheduler-simulator server",
+		Long: `Start the kube-scheduler-simulator server.
+
+Each setting is taken from the flag first, then from the environment variable, and then from the config file.`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			opts.ApplyEnv(os.Getenv)
+			return startSimulator(opts)
+		},
+	}
+	opts.AddFlags(cmd.Flags())
+	return cmd
+}
+
 // startSimulator starts simulator and needed k8s components.
 //
 //nolint:funlen,cyclop
-func startSimulator() error {
-	cfg, err := config.NewConfig()
+func startSimulator(opts *config.Options) error {
+	cfg, err := config.NewConfig(opts)
 	if err != nil {
 		return xerrors.Errorf("get config: %w", err)
 	}
 
-	restCfg := &rest.Config{
-		Host: cfg.KubeAPIServerURL,
+	// keep the recent logs so that the web UI can show them.
+	logBuffer := oplog.NewBuffer(oplog.DefaultCapacity)
+	loggers, err := logging.New(cfg.LoggingOptions(), os.Stderr, logBuffer)
+	if err != nil {
+		return xerrors.Errorf("initialize logging: %w", err)
 	}
+	defer loggers.Flush()
+	klog.SetLoggerWithOptions(loggers.Logger(), klog.ContextualLogger(true))
+
+	// The components register the functions applying the fields reloaded with SIGHUP or the API.
+	configReloadService := configreload.NewService(cfg, config.Reload)
+	configReloadService.Register(config.FieldLogVerbosity, func(cfg *config.Config) error {
+		return loggers.SetVerbosity(cfg.LogVerbosity)
+	})
+
+	restCfg := cfg.KubeClientConfig()
 	client := clientset.NewForConfigOrDie(restCfg)
 	dynamicClient := dynamic.NewForConfigOrDie(restCfg)
 	discoverClient := discovery.NewDiscoveryClient(client.RESTClient())
@@ -58,17 +98,18 @@ func startSimulator() error {
 	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscoveryClient)
 
 	var importClusterDynamicClient dynamic.Interface
-	if cfg.ExternalImportEnabled || cfg.ResourceSyncEnabled {
-		importClusterDynamicClient, err = dynamic.NewForConfig(cfg.ExternalKubeClientCfg)
+	if externalKubeClientCfg := cfg.ExternalKubeClientConfig(); externalKubeClientCfg != nil {
+		importClusterDynamicClient, err = dynamic.NewForConfig(externalKubeClientCfg)
 		if err != nil {
 			return xerrors.Errorf("creates a new dynamic Clientset for the ExternalKubeClientCfg: %w", err)
 		}
 	}
 
-	etcdclient, err := clientv3.New(clientv3.Config{
-		Endpoints:   []string{cfg.EtcdURL},
-		DialTimeout: 2 * time.Second,
-	})
+	etcdClientCfg, err := cfg.EtcdClientConfig()
+	if err != nil {
+		return xerrors.Errorf("build the config of the etcd client: %w", err)
+	}
+	etcdclient, err := clientv3.New(etcdClientCfg)
 	if err != nil {
 		return xerrors.Errorf("create an etcd client: %w", err)
 	}
@@ -76,36 +117,89 @@ func startSimulator() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	err = wait.PollUntilContextTimeout(ctx, kubeAPIServerPollInterval, kubeAPIServerReadyTimeout, true, func(_ context.Context) (bool, error) {
-		_, err := client.CoreV1().Namespaces().Get(context.Background(), "kube-system", metav1.GetOptions{})
-		if err != nil {
-			klog.Infof("waiting for kube-system namespace to be ready: %v", err)
-			return false, nil
-		}
-		klog.Info("kubeapi-server is ready")
-		return true, nil
-	})
+	// etcd is probed separately so that the error tells whether etcd or kube-apiserver is down.
+	err = startup.Wait(ctx, cfg.Startup, clock.RealClock{},
+		startup.Probe{Name: "etcd", URL: cfg.EtcdURL, Check: func(ctx context.Context) error {
+			_, err := etcdclient.Status(ctx, cfg.EtcdURL)
+			return err
+		}},
+		startup.Probe{Name: "kube-apiserver", URL: cfg.KubeAPIServerURL, Check: func(ctx context.Context) error {
+			_, err := client.CoreV1().Namespaces().Get(ctx, "kube-system", metav1.GetOptions{})
+			return err
+		}},
+	)
 	if err != nil {
-		return xerrors.Errorf("kubeapi-server is not ready: %w", err)
+		return xerrors.Errorf("wait for the simulator's cluster to be ready: %w", err)
 	}
 
 	replayerOptions := replayer.Options{RecordFile: cfg.RecordFilePath}
 	resourceApplierOptions := resourceapplier.Options{}
+	resourceWatcherOptions := resourcewatcher.Options{
+		HeartbeatInterval: cfg.WatcherHeartbeatInterval,
+		QueueSize:         cfg.WatcherQueueSize,
+		OverflowPolicy:    cfg.WatcherOverflowPolicy,
+	}
+	resourcewatcher.RegisterMetrics()
+	server.RegisterMetrics()
+
+	var diOptions []di.Option
+	if cfg.PodLifecycleEnabled {
+		diOptions = append(diOptions, di.WithPodLifecycle(cfg.PodLifecycle))
+	}
+	if cfg.SchedulerRandomSeed != nil {
+		diOptions = append(diOptions, di.WithSchedulerRandomSeed(*cfg.SchedulerRandomSeed))
+	}
 
-	dic, err := di.NewDIContainer(client, dynamicClient, restMapper, etcdclient, restCfg, cfg.InitialSchedulerCfg, cfg.ExternalImportEnabled, cfg.ResourceSyncEnabled, cfg.ReplayerEnabled, importClusterDynamicClient, cfg.Port, resourceApplierOptions, replayerOptions)
+	dic, err := di.NewDIContainerWithOptions(client, dynamicClient, restMapper, etcdclient, restCfg, cfg.InitialSchedulerCfg, cfg.SchedulerFeatureGates, cfg.ResourceSyncEnabled, cfg.ReplayerEnabled, cfg.RecordEnabled, importClusterDynamicClient, cfg.ImportManifestsPath, cfg.EtcdSnapshotDir, cfg.Ports.ExtenderProxyAddress(), resourceApplierOptions, cfg.Syncer, replayerOptions, cfg.Recorder, resourceWatcherOptions, logBuffer, configReloadService, loggers, diOptions...)
 	if err != nil {
 		return xerrors.Errorf("create di container: %w", err)
 	}
+	// The container is closed after the server is shut down not to start new background jobs.
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), jobShutdownTimeout)
+		defer shutdownCancel()
+		if err := dic.Close(shutdownCtx); err != nil {
+			klog.Warningf("failed to close the di container: %+v", err)
+		}
+	}()
+
+	dic.SchedulerService().SetSchedulerConfig(cfg.InitialSchedulerCfg)
+	if len(cfg.SchedulerFeatureGates) != 0 || cfg.SchedulerRandomSeed != nil {
+		// The scheduler container may have started before the feature gates and the random seed are written.
+		if err := dic.SchedulerService().ResetScheduler(); err != nil {
+			return xerrors.Errorf("restart the scheduler with the feature gates and the random seed: %w", err)
+		}
+	}
+
+	// Keep the scheduling results so that they can be queried even after the Pods are deleted.
+	if err := dic.SchedulingResultsService().RegisterRecordingToInformer(client, ctx.Done()); err != nil {
+		return xerrors.Errorf("start recording scheduling results: %w", err)
+	}
+	if err := dic.StatsService().Start(ctx.Done()); err != nil {
+		return xerrors.Errorf("start computing scheduling stats: %w", err)
+	}
+	if err := dic.WhatIfService().Start(ctx.Done()); err != nil {
+		return xerrors.Errorf("start what-if scheduling: %w", err)
+	}
 
 	// If ExternalImportEnabled is enabled, the simulator import resources
 	// from the target cluster that indicated by the `KUBECONFIG`.
-	if cfg.ExternalImportEnabled {
+	// If ImportManifestsPath is given, the simulator import resources from the manifests instead.
+	if cfg.ExternalImportEnabled || cfg.ImportManifestsPath != "" {
 		// This must be called after `StartScheduler`
 		timeoutCtx, timeoutCancel := context.WithTimeout(ctx, importTimeout)
 		defer timeoutCancel()
-		if err := dic.OneshotClusterResourceImporter().ImportClusterResources(timeoutCtx, cfg.ResourceImportLabelSelector); err != nil {
+		summary, err := dic.OneshotClusterResourceImporter().ImportClusterResources(timeoutCtx, oneshotimporter.ImportOptions{
+			LabelSelector:          cfg.ResourceImportLabelSelector,
+			ImportSchedulerConfig:  cfg.ImportSchedulerConfigEnabled,
+			ResourceCountThreshold: cfg.ImportResourceCountThreshold,
+			Force:                  cfg.ExternalImportForce,
+			ForceNodeReady:         cfg.ImportForceNodeReady,
+		})
+		if err != nil {
 			return xerrors.Errorf("import from the target cluster: %w", err)
 		}
+		klog.InfoS("Imported resources from the target cluster", "created", summary.Created, "updated", summary.Updated, "skipped", summary.Skipped, "failed", summary.Failed)
 	}
 
 	// If ReplayEnabled is enabled, the simulator replays the recorded resources.
@@ -115,27 +209,62 @@ func startSimulator() error {
 		}
 	}
 
-	dic.SchedulerService().SetSchedulerConfig(cfg.InitialSchedulerCfg)
-
 	if cfg.ResourceSyncEnabled {
 		// Start the resource syncer to sync resources from the target cluster.
-		if err = dic.ResourceSyncer().Run(ctx); err != nil {
+		// It's started as the component so that it can be paused and stopped through the API.
+		if err = dic.Components()[di.ComponentSyncer].Start(nil); err != nil {
 			return xerrors.Errorf("start syncing: %w", err)
 		}
 	}
 
+	if cfg.RecordEnabled {
+		// Start the recorder to record the resources in the simulator to RecordFilePath.
+		// The resources existing at this point, e.g., the imported ones, are recorded as added.
+		if err = dic.Components()[di.ComponentRecorder].Start(nil); err != nil {
+			return xerrors.Errorf("start recording: %w", err)
+		}
+	}
+
+	if cfg.PodLifecycleEnabled {
+		// Start the pod lifecycle simulator to make the Pods bound to the nodes Running, as the kubelets do.
+		if err = dic.Components()[di.ComponentPodLifecycle].Start(nil); err != nil {
+			return xerrors.Errorf("start pod lifecycle simulator: %w", err)
+		}
+	}
+
 	// start simulator server
-	s := server.NewSimulatorServer(cfg, dic)
-	shutdownFn, err := s.Start(cfg.Port)
+	s, err := server.NewSimulatorServer(cfg, dic)
+	if err != nil {
+		return xerrors.Errorf("create simulator server: %w", err)
+	}
+	shutdownFn, err := s.Start()
 	if err != nil {
 		return xerrors.Errorf("start simulator server: %w", err)
 	}
 	defer shutdownFn()
 
-	// wait the signal
+	// start the gRPC API alongside the REST API if its port is configured.
+	if cfg.Ports.GRPC.Enabled() {
+		grpcShutdownFn, err := grpcserver.Start(grpcserver.NewGRPCServer(cfg, dic), cfg.Ports.GRPC)
+		if err != nil {
+			return xerrors.Errorf("start gRPC server: %w", err)
+		}
+		defer grpcShutdownFn()
+	}
+
+	// wait the signal, and reload the config file with SIGHUP.
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, os.Interrupt)
-	<-quit
-
-	return nil
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	for {
+		select {
+		case <-reload:
+			if _, err := configReloadService.Reload(ctx); err != nil {
+				klog.Errorf("failed to reload config: %+v", err)
+			}
+		case <-quit:
+			return nil
+		}
+	}
 }
@@ -1,199 +1,554 @@
 package config
 
 import (
+	"context"
 	"errors"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
-	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/xerrors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 	configv1 "k8s.io/kube-scheduler/config/v1"
 	"k8s.io/kubernetes/pkg/scheduler/apis/config/scheme"
 
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/auth"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/config/v1alpha1"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/podlifecycle"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/ratelimit"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/recorder"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/startup"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/syncer"
 )
 
 // ErrEmptyConfig represents the required config variable don't exist.
 var ErrEmptyConfig = errors.New("config is required, but empty")
 
-// configYaml represents the value from the config file.
-var configYaml = &v1alpha1.SimulatorConfiguration{}
-
 // defaultSchedulerCfgPath is where we have the scheduler config in the container by default.
 const defaultSchedulerCfgPath = "/config/scheduler.yaml"
 
+// defaultWatcherHeartbeatInterval is the default interval of the heartbeat to the clients watching resources.
+const defaultWatcherHeartbeatInterval = 30 * time.Second
+
+// defaultRequestTimeout is the default timeout of the requests to the simulator's API.
+const defaultRequestTimeout = 60 * time.Second
+
+const (
+	// defaultKubeClientQPS is the default QPS of the clients accessing kube-apiserver in the simulator.
+	// It's much higher than the default of client-go since the large imports and the syncer send many requests.
+	defaultKubeClientQPS = 100
+	// defaultKubeClientBurst is the default burst of the clients accessing kube-apiserver in the simulator.
+	defaultKubeClientBurst = 200
+)
+
 // Config is configuration for simulator.
 type Config struct {
-	Port                  int
-	KubeAPIServerURL      string
-	EtcdURL               string
+	// Ports are the addresses which the servers of the simulator listen on.
+	Ports            Ports
+	KubeAPIServerURL string
+	// KubeClientQPS and KubeClientBurst are the QPS and the burst of the clients accessing KubeAPIServerURL.
+	KubeClientQPS   float32
+	KubeClientBurst int
+	EtcdURL         string
+	// Etcd is the options of the connection to EtcdURL.
+	Etcd EtcdOptions
+	// Startup configures the wait for etcd and kube-apiserver when the simulator is started.
+	Startup startup.Options
+	// CorsAllowedOriginList is the origins allowed to access the simulator's API and kube-apiserver.
+	// Only the same origin is allowed if it's empty.
 	CorsAllowedOriginList []string
+	// CorsAllowedMethods is the methods which the allowed origins can use for the simulator's API.
+	CorsAllowedMethods []string
+	// CorsAllowCredentials indicates whether the allowed origins can send the requests with the credentials.
+	CorsAllowCredentials bool
+	// RequestTimeout is the timeout of the requests to the simulator's API, except the ones watching resources.
+	// The requests don't time out if it's zero.
+	RequestTimeout time.Duration
 	// ExternalImportEnabled indicates whether the simulator will import resources from a target cluster once
 	// when it's started.
 	ExternalImportEnabled bool
+	// ImportManifestsPath is the path to the manifests which the simulator imports resources from once
+	// when it's started, instead of a target cluster.
+	ImportManifestsPath string
 	// ResourceImportLabelSelector is the label selector used to determine which resources from the target cluster should be imported.
 	ResourceImportLabelSelector metav1.LabelSelector
+	// ImportSchedulerConfigEnabled indicates whether the simulator will import the scheduler configuration
+	// from a target cluster along with resources.
+	ImportSchedulerConfigEnabled bool
+	// ImportResourceCountThreshold is the maximum number of resources imported from a target cluster.
+	ImportResourceCountThreshold int
+	// ExternalImportForce indicates whether the simulator will import resources even if the number of them
+	// exceeds ImportResourceCountThreshold.
+	ExternalImportForce bool
+	// ImportForceNodeReady indicates whether the simulator will set the Ready condition of imported nodes to True.
+	ImportForceNodeReady bool
 	// ResourceSyncEnabled indicates whether the simulator will keep syncing resources from a target cluster.
 	ResourceSyncEnabled bool
+	// ExternalSchedulerEnabled indicates whether an external scheduler is used.
+	// Deprecated: it's kept only for compatibility and has no effect.
+	ExternalSchedulerEnabled bool
+	// AllowExternalImportWithSync allows ExternalImportEnabled and ResourceSyncEnabled to be used simultaneously.
+	AllowExternalImportWithSync bool
 	// ReplayerEnabled indicates whether the simulator will replay events recorded in a file.
 	ReplayerEnabled bool
+	// RecordEnabled indicates whether the simulator will record the events of the resources in itself to RecordFilePath.
+	RecordEnabled bool
 	// RecordFilePath is the path to the file where the simulator records events.
 	RecordFilePath string
+	// Syncer is the options of the syncer.
+	Syncer syncer.Options
+	// Recorder is the options of the recorder, whose RecordFile is RecordFilePath.
+	Recorder recorder.Options
+	// PodLifecycleEnabled indicates whether the simulator will make the Pods bound to the nodes Running,
+	// and Succeeded after the durations in their annotations, as the kubelets do.
+	PodLifecycleEnabled bool
+	// PodLifecycle is the options of the pod lifecycle simulator.
+	PodLifecycle podlifecycle.Options
+	// EtcdSnapshotDir is the directory where the snapshots of etcd are saved. The snapshot API is disabled if it's empty.
+	EtcdSnapshotDir string
+	// WatcherHeartbeatInterval is the interval to send the heartbeat to the clients watching resources.
+	WatcherHeartbeatInterval time.Duration
+	// WatcherQueueSize is the number of events which can be pending for each client watching resources.
+	WatcherQueueSize int
+	// WatcherOverflowPolicy decides what to do when the events pending for a client exceed WatcherQueueSize.
+	WatcherOverflowPolicy resourcewatcher.OverflowPolicy
+	// WatcherBearerToken is the static bearer token which the clients watching resources must send.
+	// The connections aren't authenticated if it's empty. It's ignored if Auth is configured.
+	//
+	// Deprecated: use the static tokens in Auth instead, which authenticate the whole API.
+	WatcherBearerToken string
+	// LogVerbosity is the verbosity of the logs of the simulator.
+	LogVerbosity int
+	// LogFormat is the format of the logs, logging.FormatText or logging.FormatJSON.
+	LogFormat string
+	// LogComponentVerbosity overrides LogVerbosity for the components.
+	LogComponentVerbosity map[string]int
+	// Auth configures the authentication of the simulator's API.
+	// The API isn't authenticated if it's nil.
+	Auth *auth.Options
+	// RateLimit configures the rate limits of the requests to the simulator's API for each client.
+	// The requests aren't limited if it's nil.
+	RateLimit *ratelimit.Options
 	// ExternalKubeClientCfg is KubeConfig to get resources from external cluster.
-	// This field should be set when ExternalImportEnabled == true or ResourceSyncEnabled == true.
+	// This field is set when ExternalImportEnabled == true or ResourceSyncEnabled == true,
+	// or when kubeConfig is given in the config file. Otherwise, it's nil.
 	ExternalKubeClientCfg *rest.Config
-	InitialSchedulerCfg   *configv1.KubeSchedulerConfiguration
+	// ExternalKubeClientQPS and ExternalKubeClientBurst are the QPS and the burst of the client
+	// accessing the external cluster. The defaults of client-go are used if they're zero.
+	ExternalKubeClientQPS   float32
+	ExternalKubeClientBurst int
+	InitialSchedulerCfg     *configv1.KubeSchedulerConfiguration
+	// SchedulerFeatureGates are the feature gates set to the scheduler.
+	SchedulerFeatureGates map[string]bool
+	// SchedulerRandomSeed is the seed of the random numbers of the scheduler, which makes it deterministic.
+	// The scheduler is random if it's nil.
+	SchedulerRandomSeed *int64
+
+	// options are the options which the config is created with, used to reload the config file.
+	options *Options
 }
 
 const (
-	// defaultFilePath is the config file path.
-	// TODO: move it to somewhere configurable from outside if users want.
+	// defaultFilePath is the default path to the config file, which can be changed with --config.
 	defaultFilePath = "./config.yaml"
 )
 
-// NewConfig gets some settings from config file or environment variables.
+// NewConfig gets some settings from the options given from the command line flags or environment variables,
+// and if empty from the config file.
 //
 //nolint:cyclop
-func NewConfig() (*Config, error) {
-	if err := LoadYamlConfig(defaultFilePath); err != nil {
+func NewConfig(opts *Options) (*Config, error) {
+	configFile := opts.ConfigFile
+	if configFile == "" {
+		configFile = defaultFilePath
+	}
+	configYaml, err := LoadYamlConfig(configFile)
+	if err != nil {
 		return nil, err
 	}
 
-	port, err := getPort()
+	ports, err := getPorts(opts, configYaml)
 	if err != nil {
-		return nil, xerrors.Errorf("get port: %w", err)
+		return nil, xerrors.Errorf("get ports: %w", err)
 	}
 
-	etcdurl, err := getEtcdURL()
+	etcdurl, err := getEtcdURL(opts, configYaml)
 	if err != nil {
-		return nil, xerrors.Errorf("get etcd URL: %w", err)
+		return nil, xerrors.Errorf("get etcdURL: %w", err)
 	}
 
-	corsAllowedOriginList, err := getCorsAllowedOriginList()
+	corsAllowedOriginList, err := getCorsAllowedOriginList(opts, configYaml)
 	if err != nil {
 		return nil, xerrors.Errorf("get frontend URL: %w", err)
 	}
 
-	apiurl, err := getKubeAPIServerURL()
+	corsAllowedMethods, err := getCorsAllowedMethods(configYaml)
 	if err != nil {
-		return nil, xerrors.Errorf("get kube API server URL: %w", err)
+		return nil, xerrors.Errorf("get cors-allowed-methods: %w", err)
 	}
 
-	externalimportenabled := getExternalImportEnabled()
-	resourceSyncEnabled := getResourceSyncEnabled()
-	replayerEnabled := getReplayerEnabled()
-	recordFilePath := getRecordFilePath()
-	externalKubeClientCfg := &rest.Config{}
-	if hasTwoOrMoreTrue(externalimportenabled, resourceSyncEnabled, replayerEnabled) {
-		return nil, xerrors.Errorf("externalImportEnabled, resourceSyncEnabled and replayerEnabled cannot be used simultaneously.")
+	requestTimeout, err := getRequestTimeout(configYaml)
+	if err != nil {
+		return nil, xerrors.Errorf("get request timeout: %w", err)
 	}
+
+	apiurl, err := getKubeAPIServerURL(opts, configYaml)
+	if err != nil {
+		return nil, xerrors.Errorf("get kubeApiServerUrl: %w", err)
+	}
+
+	externalimportenabled := getExternalImportEnabled(opts, configYaml)
+	resourceSyncEnabled := getResourceSyncEnabled(opts, configYaml)
+	replayerEnabled := getReplayerEnabled(opts, configYaml)
+	recordEnabled := getRecordEnabled(opts, configYaml)
+	recordFilePath := getRecordFilePath(opts, configYaml)
+	importManifestsPath := getImportManifestsPath(opts, configYaml)
+	var externalKubeClientCfg *rest.Config
+	kubeConfig, kubeContext, inCluster := getKubeConfig(opts, configYaml), getExternalKubeContext(opts, configYaml), getExternalInCluster(opts, configYaml)
 	if externalimportenabled || resourceSyncEnabled {
-		externalKubeClientCfg, err = clientcmd.BuildConfigFromFlags("", configYaml.KubeConfig)
+		externalKubeClientCfg, err = buildExternalKubeClientCfg(kubeConfig, kubeContext, inCluster)
 		if err != nil {
-			return nil, xerrors.Errorf("get kube clientconfig: %w", err)
+			// Validate reports it along with the other violations.
+			klog.Errorf("failed to load the kubeconfig of the cluster: %v", err)
+			externalKubeClientCfg = nil
+		}
+	} else if kubeConfig != "" || kubeContext != "" || inCluster {
+		// The kubeconfig is still loaded so that users can import resources on demand via the API.
+		// The simulator should start even if it's invalid because any feature doesn't require it at startup.
+		externalKubeClientCfg, err = buildExternalKubeClientCfg(kubeConfig, kubeContext, inCluster)
+		if err != nil {
+			klog.Warningf("failed to load kubeConfig, importing resources on demand is disabled: %v", err)
+			externalKubeClientCfg = nil
 		}
 	}
 
-	initialschedulerCfg, err := GetSchedulerCfg()
+	watcherOverflowPolicy, err := getWatcherOverflowPolicy(configYaml)
+	if err != nil {
+		return nil, xerrors.Errorf("get watcherOverflowPolicy: %w", err)
+	}
+
+	authOpts, err := getAuthOptions(configYaml)
+	if err != nil {
+		return nil, xerrors.Errorf("get auth: %w", err)
+	}
+
+	rateLimitOpts, err := getRateLimitOptions(configYaml)
+	if err != nil {
+		return nil, xerrors.Errorf("get rateLimit: %w", err)
+	}
+
+	etcdOpts, err := convertEtcdConfiguration(configYaml.Etcd)
+	if err != nil {
+		return nil, xerrors.Errorf("get etcd: %w", err)
+	}
+
+	startupOpts, err := convertStartupConfiguration(configYaml.Startup)
+	if err != nil {
+		return nil, xerrors.Errorf("get startup: %w", err)
+	}
+
+	syncerOpts, err := convertSyncerConfiguration(configYaml.Syncer)
+	if err != nil {
+		return nil, xerrors.Errorf("get syncer: %w", err)
+	}
+
+	recorderOpts, err := convertRecorderConfiguration(configYaml.Recorder)
+	if err != nil {
+		return nil, xerrors.Errorf("get recorder: %w", err)
+	}
+	recorderOpts.RecordFile = recordFilePath
+
+	podLifecycleEnabled, podLifecycleOpts, err := convertPodLifecycleConfiguration(configYaml.PodLifecycle)
+	if err != nil {
+		return nil, xerrors.Errorf("get podLifecycle: %w", err)
+	}
+
+	initialschedulerCfg, err := GetSchedulerCfg(getKubeSchedulerConfigPath(opts, configYaml), externalKubeClientCfg)
 	if err != nil {
 		return nil, xerrors.Errorf("get SchedulerCfg: %w", err)
 	}
 
-	return &Config{
-		Port:                        port,
-		KubeAPIServerURL:            apiurl,
-		EtcdURL:                     etcdurl,
-		CorsAllowedOriginList:       corsAllowedOriginList,
-		InitialSchedulerCfg:         initialschedulerCfg,
-		ExternalImportEnabled:       externalimportenabled,
-		ResourceImportLabelSelector: configYaml.ResourceImportLabelSelector,
-		ExternalKubeClientCfg:       externalKubeClientCfg,
-		ResourceSyncEnabled:         resourceSyncEnabled,
-		ReplayerEnabled:             replayerEnabled,
-		RecordFilePath:              recordFilePath,
-	}, nil
+	cfg := &Config{
+		Ports:                        ports,
+		KubeAPIServerURL:             apiurl,
+		KubeClientQPS:                getKubeClientQPS(configYaml),
+		KubeClientBurst:              getKubeClientBurst(configYaml),
+		EtcdURL:                      etcdurl,
+		Etcd:                         etcdOpts,
+		Startup:                      startupOpts,
+		CorsAllowedOriginList:        corsAllowedOriginList,
+		CorsAllowedMethods:           corsAllowedMethods,
+		CorsAllowCredentials:         getCorsAllowCredentials(configYaml),
+		RequestTimeout:               requestTimeout,
+		InitialSchedulerCfg:          initialschedulerCfg,
+		SchedulerFeatureGates:        configYaml.SchedulerFeatureGates,
+		SchedulerRandomSeed:          configYaml.SchedulerRandomSeed,
+		ExternalImportEnabled:        externalimportenabled,
+		ImportManifestsPath:          importManifestsPath,
+		ResourceImportLabelSelector:  configYaml.ResourceImportLabelSelector,
+		ImportSchedulerConfigEnabled: configYaml.ImportSchedulerConfigEnabled,
+		ImportResourceCountThreshold: configYaml.ImportResourceCountThreshold,
+		ExternalImportForce:          configYaml.ExternalImportForce,
+		ImportForceNodeReady:         configYaml.ImportForceNodeReady,
+		ExternalKubeClientCfg:        externalKubeClientCfg,
+		ExternalKubeClientQPS:        configYaml.ExternalKubeClientQPS,
+		ExternalKubeClientBurst:      configYaml.ExternalKubeClientBurst,
+		ResourceSyncEnabled:          resourceSyncEnabled,
+		AllowExternalImportWithSync:  configYaml.AllowExternalImportWithSync,
+		ExternalSchedulerEnabled:     getExternalSchedulerEnabled(opts, configYaml),
+		ReplayerEnabled:              replayerEnabled,
+		RecordEnabled:                recordEnabled,
+		RecordFilePath:               recordFilePath,
+		Syncer:                       syncerOpts,
+		Recorder:                     recorderOpts,
+		PodLifecycleEnabled:          podLifecycleEnabled,
+		PodLifecycle:                 podLifecycleOpts,
+		EtcdSnapshotDir:              configYaml.EtcdSnapshotDir,
+		WatcherHeartbeatInterval:     getWatcherHeartbeatInterval(configYaml),
+		WatcherQueueSize:             configYaml.WatcherQueueSize,
+		WatcherOverflowPolicy:        watcherOverflowPolicy,
+		WatcherBearerToken:           getWatcherBearerToken(opts, configYaml),
+		LogVerbosity:                 configYaml.LogVerbosity,
+		LogFormat:                    configYaml.LogFormat,
+		LogComponentVerbosity:        configYaml.LogComponentVerbosity,
+		Auth:                         authOpts,
+		RateLimit:                    rateLimitOpts,
+		options:                      opts,
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, xerrors.Errorf("validate config: %w", err)
+	}
+	if opts.CheckReachability != nil && *opts.CheckReachability {
+		ctx := context.Background()
+		if err := checkReachability(ctx, "kubeApiServerUrl", cfg.KubeAPIServerURL); err != nil {
+			return nil, err
+		}
+		if err := checkReachability(ctx, "etcdURL", cfg.EtcdURL); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// KubeClientConfig returns the rest.Config of the clients accessing kube-apiserver in the simulator.
+func (c *Config) KubeClientConfig() *rest.Config {
+	return &rest.Config{
+		Host:  c.KubeAPIServerURL,
+		QPS:   c.KubeClientQPS,
+		Burst: c.KubeClientBurst,
+	}
+}
+
+// ExternalKubeClientConfig returns the copy of ExternalKubeClientCfg with the QPS and the burst configured.
+// It returns nil if ExternalKubeClientCfg is nil.
+func (c *Config) ExternalKubeClientConfig() *rest.Config {
+	if c.ExternalKubeClientCfg == nil {
+		return nil
+	}
+	cfg := rest.CopyConfig(c.ExternalKubeClientCfg)
+	if c.ExternalKubeClientQPS != 0 {
+		cfg.QPS = c.ExternalKubeClientQPS
+	}
+	if c.ExternalKubeClientBurst != 0 {
+		cfg.Burst = c.ExternalKubeClientBurst
+	}
+	return cfg
+}
+
+// Validate checks the options depending on each other, and returns all the violations at once.
+func (c *Config) Validate() error {
+	errs := c.validatePorts()
+	if err := validateServerURL(c.EtcdURL); err != nil {
+		errs = append(errs, xerrors.Errorf("etcdURL: %w", err))
+	}
+	if err := validateServerURL(c.KubeAPIServerURL); err != nil {
+		errs = append(errs, xerrors.Errorf("kubeApiServerUrl: %w", err))
+	}
+
+	if c.ExternalImportEnabled && c.ExternalKubeClientCfg == nil {
+		errs = append(errs, xerrors.New("externalImportEnabled requires the kubeconfig of the cluster, set kubeConfig or externalInCluster"))
+	}
+	if c.ResourceSyncEnabled && c.ExternalKubeClientCfg == nil {
+		errs = append(errs, xerrors.New("resourceSyncEnabled requires the kubeconfig of the cluster, set kubeConfig or externalInCluster"))
+	}
+	if c.KubeClientQPS < 0 || c.KubeClientBurst < 0 {
+		errs = append(errs, xerrors.New("kubeClientQPS and kubeClientBurst must not be negative"))
+	}
+	if c.ExternalKubeClientQPS < 0 || c.ExternalKubeClientBurst < 0 {
+		errs = append(errs, xerrors.New("externalKubeClientQPS and externalKubeClientBurst must not be negative"))
+	}
+	if c.LogVerbosity < 0 {
+		errs = append(errs, xerrors.Errorf("logVerbosity %d must not be negative", c.LogVerbosity))
+	}
+	errs = append(errs, c.validateLogging()...)
+	if err := config.ValidateFeatureGates(c.SchedulerFeatureGates); err != nil {
+		errs = append(errs, xerrors.Errorf("schedulerFeatureGates: %w", err))
+	}
+	if c.ReplayerEnabled && c.RecordFilePath == "" {
+		errs = append(errs, xerrors.New("replayEnabled requires recordFilePath"))
+	}
+	if c.RecordEnabled && c.RecordFilePath == "" {
+		errs = append(errs, xerrors.New("recordEnabled requires recordFilePath"))
+	}
+	if c.RecordEnabled && c.ReplayerEnabled {
+		// The recorder would truncate the file being replayed.
+		errs = append(errs, xerrors.New("recordEnabled and replayEnabled cannot be used simultaneously because they use the same recordFilePath"))
+	}
+	if c.AllowExternalImportWithSync {
+		// The resources are imported once, and then synced.
+		if hasTwoOrMoreTrue(c.ExternalImportEnabled || c.ResourceSyncEnabled, c.ImportManifestsPath != "", c.ReplayerEnabled) {
+			errs = append(errs, xerrors.New("externalImportEnabled or resourceSyncEnabled, importManifestsPath and replayEnabled cannot be used simultaneously"))
+		}
+	} else if hasTwoOrMoreTrue(c.ExternalImportEnabled, c.ImportManifestsPath != "", c.ResourceSyncEnabled, c.ReplayerEnabled) {
+		errs = append(errs, xerrors.New("externalImportEnabled, importManifestsPath, resourceSyncEnabled and replayEnabled cannot be used simultaneously unless allowExternalImportWithSync allows the first and the third"))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// maxPort is the maximum port number.
+const maxPort = 65535
+
+// validateServerURL checks that u is the URL of an HTTP server, e.g., http://localhost:2379.
+func validateServerURL(u string) error {
+	parsed, err := url.ParseRequestURI(u)
+	if err != nil {
+		return xerrors.Errorf("parse %q: %w", u, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return xerrors.Errorf("%q must be http or https URL with the host", u)
+	}
+	return nil
+}
+
+// isLoopback returns true if host is the loopback address which the simulator listens on as well.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
 }
 
-// LoadYamlConfig read the yaml file and set configYaml.
-func LoadYamlConfig(configFile string) error {
+// LoadYamlConfig reads the yaml file, and returns the config in it.
+// It returns the empty config if configFile is empty.
+func LoadYamlConfig(configFile string) (*v1alpha1.SimulatorConfiguration, error) {
 	if configFile == "" {
 		klog.V(1).InfoS("Config file not specified")
-		return nil
+		return &v1alpha1.SimulatorConfiguration{}, nil
 	}
 
 	conf, err := os.ReadFile(configFile)
 	if err != nil {
-		return xerrors.Errorf("failed to read config file: %w", err)
+		return nil, xerrors.Errorf("failed to read config file: %w", err)
+	}
+
+	conf, err = applyPreset(conf)
+	if err != nil {
+		return nil, xerrors.Errorf("apply the preset of simulator's config: %w", err)
 	}
 
 	versionedConfig := &v1alpha1.SimulatorConfiguration{}
 
+	// The unknown fields are rejected so that the typos fail fast.
 	decoder := scheme.Codecs.UniversalDecoder(v1alpha1.SchemeGroupVersion)
 	if err := runtime.DecodeInto(decoder, conf, versionedConfig); err != nil {
-		return xerrors.Errorf("failed decoding simulator's config %w", err)
+		return nil, xerrors.Errorf("failed decoding simulator's config %w", err)
+	}
+	if err := validateTypeMeta(versionedConfig.TypeMeta); err != nil {
+		return nil, xerrors.Errorf("validate simulator's config: %w", err)
 	}
 
-	configYaml = versionedConfig
-
-	return nil
+	return versionedConfig, nil
 }
 
-// getPort gets port from environment variable named PORT first, if empty from the config file.
-func getPort() (int, error) {
-	p := os.Getenv("PORT")
-	port, err := strconv.Atoi(p)
-	if p == "" || err != nil {
-		port = configYaml.Port
-		if port == 0 {
-			return 0, xerrors.Errorf("get PORT from config: %w", ErrEmptyConfig)
-		}
+// validateTypeMeta checks that the config file is SimulatorConfiguration of the known version.
+func validateTypeMeta(typeMeta metav1.TypeMeta) error {
+	if typeMeta.Kind != "SimulatorConfiguration" {
+		return xerrors.Errorf("unknown kind %q, must be SimulatorConfiguration", typeMeta.Kind)
+	}
+	switch gv := typeMeta.GroupVersionKind().GroupVersion(); gv {
+	case v1alpha1.SchemeGroupVersion, v1alpha1.LegacySchemeGroupVersion:
+		return nil
+	default:
+		return xerrors.Errorf("unknown apiVersion %q, must be %q", typeMeta.APIVersion, v1alpha1.SchemeGroupVersion)
 	}
-	return port, nil
 }
 
-// getKubeAPIServerURL gets KubeAPIServerURL from environment variable first, if empty from the config file.
-func getKubeAPIServerURL() (string, error) {
-	url := os.Getenv("KUBE_APISERVER_URL")
+// getKubeAPIServerURL gets KubeAPIServerURL from the options first, if empty from the config file.
+// It's normalized with normalizeServerURL, and the port is 3131 if it's omitted.
+func getKubeAPIServerURL(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) (string, error) {
+	url := opts.KubeAPIServerURL
 	if url == "" {
 		url = configYaml.KubeAPIServerURL
 		if url == "" {
 			return "", xerrors.Errorf("get KUBE_APISERVER_URL from config: %w", ErrEmptyConfig)
 		}
 	}
-	return url, nil
+	normalized, err := normalizeServerURL(url, defaultKubeAPIServerURLPort)
+	if err != nil {
+		return "", err
+	}
+	return normalized, nil
+}
+
+// getKubeClientQPS gets KubeClientQPS from the config file.
+// If not set, it returns the default value.
+func getKubeClientQPS(configYaml *v1alpha1.SimulatorConfiguration) float32 {
+	if configYaml.KubeClientQPS == 0 {
+		return defaultKubeClientQPS
+	}
+	return configYaml.KubeClientQPS
 }
 
-// getEtcdURL gets EtcdURL from environment variable first,
+// getKubeClientBurst gets KubeClientBurst from the config file.
+// If not set, it returns the default value.
+func getKubeClientBurst(configYaml *v1alpha1.SimulatorConfiguration) int {
+	if configYaml.KubeClientBurst == 0 {
+		return defaultKubeClientBurst
+	}
+	return configYaml.KubeClientBurst
+}
+
+// getEtcdURL gets EtcdURL from the options first,
 // if empty from the config file.
-func getEtcdURL() (string, error) {
-	e := os.Getenv("KUBE_SCHEDULER_SIMULATOR_ETCD_URL")
+// It's normalized with normalizeServerURL, and the port is 2379 if it's omitted.
+func getEtcdURL(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) (string, error) {
+	e := opts.EtcdURL
 	if e == "" {
 		e = configYaml.EtcdURL
 		if e == "" {
 			return "", xerrors.Errorf("get KUBE_SCHEDULER_SIMULATOR_ETCD_URL from config: %w", ErrEmptyConfig)
 		}
 	}
-	return e, nil
+	normalized, err := normalizeServerURL(e, defaultEtcdURLPort)
+	if err != nil {
+		return "", err
+	}
+	return normalized, nil
 }
 
-// getCorsAllowedOriginList fetches CorsAllowedOriginList from the env named CORS_ALLOWED_ORIGIN_LIST
+// getCorsAllowedOriginList fetches CorsAllowedOriginList from the options
 // if empty from the config file.
 // This allowed list is applied to kube-apiserver and the simulator server.
 //
 // Let's say CORS_ALLOWED_ORIGIN_LIST=http://localhost:3000,http://localhost:3001,http://localhost:3002 is given.
 // Then, getCorsAllowedOriginList returns []string{"http://localhost:3000", "http://localhost:3001", "http://localhost:3002"}
-func getCorsAllowedOriginList() ([]string, error) {
-	e := os.Getenv("CORS_ALLOWED_ORIGIN_LIST")
-	urls := parseStringListEnv(e)
-
-	if err := validateURLs(urls); e == "" || err != nil {
+func getCorsAllowedOriginList(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) ([]string, error) {
+	urls := opts.CorsAllowedOriginList
+	if len(urls) == 0 {
 		urls = configYaml.CorsAllowedOriginList
 	}
 	if err := validateURLs(urls); err != nil {
@@ -203,6 +558,54 @@ func getCorsAllowedOriginList() ([]string, error) {
 	return urls, nil
 }
 
+// defaultCorsAllowedMethods is the methods allowed for the other origins by default.
+var defaultCorsAllowedMethods = []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete}
+
+// getCorsAllowedMethods gets the methods allowed for the other origins from the config file.
+// If it's not set, defaultCorsAllowedMethods is used.
+func getCorsAllowedMethods(configYaml *v1alpha1.SimulatorConfiguration) ([]string, error) {
+	return normalizeCorsAllowedMethods(configYaml.CorsAllowedMethods)
+}
+
+// normalizeCorsAllowedMethods upper-cases the methods, and returns an error if any of them is unknown.
+func normalizeCorsAllowedMethods(methods []string) ([]string, error) {
+	if len(methods) == 0 {
+		return defaultCorsAllowedMethods, nil
+	}
+	ret := make([]string, 0, len(methods))
+	for _, m := range methods {
+		m = strings.ToUpper(strings.TrimSpace(m))
+		switch m {
+		case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+			ret = append(ret, m)
+		default:
+			return nil, xerrors.Errorf("unknown method %q", m)
+		}
+	}
+	return ret, nil
+}
+
+// getCorsAllowCredentials gets whether the other origins can send the credentials from the config file.
+// It's true by default since the web UI sends them.
+func getCorsAllowCredentials(configYaml *v1alpha1.SimulatorConfiguration) bool {
+	if configYaml.CorsAllowCredentials == nil {
+		return true
+	}
+	return *configYaml.CorsAllowCredentials
+}
+
+// getRequestTimeout gets the timeout of the requests to the simulator's API from the config file.
+// If it's not set, defaultRequestTimeout is used.
+func getRequestTimeout(configYaml *v1alpha1.SimulatorConfiguration) (time.Duration, error) {
+	if configYaml.RequestTimeout == nil {
+		return defaultRequestTimeout, nil
+	}
+	if configYaml.RequestTimeout.Duration < 0 {
+		return 0, xerrors.Errorf("requestTimeout must not be negative: %s", configYaml.RequestTimeout.Duration)
+	}
+	return configYaml.RequestTimeout.Duration, nil
+}
+
 // validateURLs checks if all URLs in slice is valid or not.
 func validateURLs(urls []string) error {
 	for _, u := range urls {
@@ -224,31 +627,199 @@ func parseStringListEnv(e string) []string {
 	return list
 }
 
-// GetSchedulerCfg reads KUBE_SCHEDULER_CONFIG_PATH which means initial kube-scheduler configuration
+// getWatcherHeartbeatInterval gets the interval of the heartbeat to the clients watching resources from the config file.
+// If it's not set, defaultWatcherHeartbeatInterval is used.
+func getWatcherHeartbeatInterval(configYaml *v1alpha1.SimulatorConfiguration) time.Duration {
+	if configYaml.WatcherHeartbeatInterval.Duration <= 0 {
+		return defaultWatcherHeartbeatInterval
+	}
+	return configYaml.WatcherHeartbeatInterval.Duration
+}
+
+// getWatcherOverflowPolicy gets the policy for the overflow of the events pending for a client watching resources
+// from the config file.
+func getWatcherOverflowPolicy(configYaml *v1alpha1.SimulatorConfiguration) (resourcewatcher.OverflowPolicy, error) {
+	switch p := resourcewatcher.OverflowPolicy(configYaml.WatcherOverflowPolicy); p {
+	case "", resourcewatcher.OverflowCoalesce, resourcewatcher.OverflowDisconnect:
+		return p, nil
+	default:
+		return "", xerrors.Errorf("unknown policy %q, must be %q or %q", p, resourcewatcher.OverflowCoalesce, resourcewatcher.OverflowDisconnect)
+	}
+}
+
+// getWatcherBearerToken gets the bearer token for the clients watching resources
+// from the options first, if empty from the config file.
+func getWatcherBearerToken(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) string {
+	t := opts.WatcherBearerToken
+	if t == "" {
+		t = configYaml.WatcherBearerToken
+	}
+	switch {
+	case t == "":
+	case configYaml.Auth != nil:
+		klog.Warning("watcherBearerToken is ignored because auth is configured, the connections to watch resources are authenticated with auth")
+	default:
+		klog.Warning("watcherBearerToken is deprecated, use auth.tokens instead")
+	}
+	return t
+}
+
+// getAuthOptions gets the authentication of the simulator's API from the config file.
+// It returns nil if it's not configured.
+func getAuthOptions(configYaml *v1alpha1.SimulatorConfiguration) (*auth.Options, error) {
+	return convertAuthConfiguration(configYaml.Auth)
+}
+
+// convertAuthConfiguration converts and validates the authentication configuration in the config file.
+func convertAuthConfiguration(cfg *v1alpha1.AuthConfiguration) (*auth.Options, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	opts := &auth.Options{
+		AuthenticateHealthz: cfg.AuthenticateHealthz,
+		AuthenticateMetrics: cfg.AuthenticateMetrics,
+	}
+	for _, t := range cfg.Tokens {
+		opts.Tokens = append(opts.Tokens, auth.StaticToken{Name: t.Name, Token: t.Token, Role: auth.Role(t.Role)})
+	}
+	if cfg.OIDC != nil {
+		opts.OIDC = &auth.OIDCOptions{
+			IssuerURL:       cfg.OIDC.IssuerURL,
+			Audience:        cfg.OIDC.Audience,
+			GroupsClaim:     cfg.OIDC.GroupsClaim,
+			ReadWriteGroups: cfg.OIDC.ReadWriteGroups,
+		}
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, xerrors.Errorf("validate auth: %w", err)
+	}
+	return opts, nil
+}
+
+// getRateLimitOptions gets the rate limits of the simulator's API from the config file.
+func getRateLimitOptions(configYaml *v1alpha1.SimulatorConfiguration) (*ratelimit.Options, error) {
+	return convertRateLimitConfiguration(configYaml.RateLimit)
+}
+
+// convertRateLimitConfiguration converts and validates the rate limit configuration in the config file.
+// If it's not set, ratelimit.DefaultOptions is used.
+func convertRateLimitConfiguration(cfg *v1alpha1.RateLimitConfiguration) (*ratelimit.Options, error) {
+	opts := ratelimit.DefaultOptions()
+	if cfg == nil {
+		return opts, nil
+	}
+	if cfg.RequestsPerSecond != nil {
+		opts.Default = ratelimit.Limit{RequestsPerSecond: *cfg.RequestsPerSecond, Burst: cfg.Burst}
+	} else if cfg.Burst != 0 {
+		opts.Default.Burst = cfg.Burst
+	}
+	for _, r := range cfg.Routes {
+		opts.Routes = append(opts.Routes, ratelimit.RouteLimit{
+			Method: strings.ToUpper(r.Method),
+			Path:   r.Path,
+			Limit:  ratelimit.Limit{RequestsPerSecond: r.RequestsPerSecond, Burst: r.Burst},
+		})
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, xerrors.Errorf("validate rateLimit: %w", err)
+	}
+	return opts, nil
+}
+
+// convertSyncerConfiguration converts and validates the syncer configuration in the config file.
+func convertSyncerConfiguration(cfg *v1alpha1.SyncerConfiguration) (syncer.Options, error) {
+	if cfg == nil {
+		return syncer.Options{}, nil
+	}
+	gvrs, err := convertGroupVersionResources(cfg.Resources)
+	if err != nil {
+		return syncer.Options{}, xerrors.Errorf("convert resources: %w", err)
+	}
+	if _, err := labels.Parse(cfg.LabelSelector); err != nil {
+		return syncer.Options{}, xerrors.Errorf("parse labelSelector: %w", err)
+	}
+	return syncer.Options{GVRs: gvrs, LabelSelector: cfg.LabelSelector}, nil
+}
+
+// convertRecorderConfiguration converts and validates the recorder configuration in the config file.
+func convertRecorderConfiguration(cfg *v1alpha1.RecorderConfiguration) (recorder.Options, error) {
+	if cfg == nil {
+		return recorder.Options{}, nil
+	}
+	gvrs, err := convertGroupVersionResources(cfg.Resources)
+	if err != nil {
+		return recorder.Options{}, xerrors.Errorf("convert resources: %w", err)
+	}
+	opts := recorder.Options{GVRs: gvrs}
+	if cfg.FlushInterval != nil {
+		if cfg.FlushInterval.Duration <= 0 {
+			return recorder.Options{}, xerrors.Errorf("flushInterval must be positive: %s", cfg.FlushInterval.Duration)
+		}
+		opts.FlushInterval = &cfg.FlushInterval.Duration
+	}
+	return opts, nil
+}
+
+// convertGroupVersionResources converts the resources in the config file.
+// It returns nil if they're empty so that the default resources are used.
+func convertGroupVersionResources(resources []v1alpha1.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+	if len(resources) == 0 {
+		return nil, nil
+	}
+	gvrs := make([]schema.GroupVersionResource, 0, len(resources))
+	for _, r := range resources {
+		if r.Version == "" || r.Resource == "" {
+			return nil, xerrors.Errorf("version and resource are required: %+v", r)
+		}
+		gvrs = append(gvrs, schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource})
+	}
+	return gvrs, nil
+}
+
+// getKubeSchedulerConfigPath reads the KubeSchedulerConfigPath option
 // if empty from the config file.
+func getKubeSchedulerConfigPath(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) string {
+	if opts.KubeSchedulerConfigPath != "" {
+		return opts.KubeSchedulerConfigPath
+	}
+	return configYaml.KubeSchedulerConfigPath
+}
+
+// GetSchedulerCfg reads kubeSchedulerConfigPath which means initial kube-scheduler configuration,
 // and converts it into *configv1.KubeSchedulerConfiguration.
-// KUBE_SCHEDULER_CONFIG_PATH is not required.
-// If KUBE_SCHEDULER_CONFIG_PATH is not set, the default configuration of kube-scheduler will be used.
-func GetSchedulerCfg() (*configv1.KubeSchedulerConfiguration, error) {
-	kubeSchedulerConfigPath := os.Getenv("KUBE_SCHEDULER_CONFIG_PATH")
+// kubeSchedulerConfigPath is not required.
+// If kubeSchedulerConfigPath is empty, the default configuration of kube-scheduler will be used.
+// It can be an http(s):// URL, or configmap://namespace/name/key, which is read with externalKubeClientCfg.
+func GetSchedulerCfg(kubeSchedulerConfigPath string, externalKubeClientCfg *rest.Config) (*configv1.KubeSchedulerConfiguration, error) {
 	if kubeSchedulerConfigPath == "" {
-		kubeSchedulerConfigPath = configYaml.KubeSchedulerConfigPath
-		if kubeSchedulerConfigPath == "" {
-			config.SetKubeSchedulerCfgPath(defaultSchedulerCfgPath)
-			dsc, err := config.DefaultSchedulerConfig()
-			if err != nil {
-				return nil, xerrors.Errorf("create default scheduler config: %w", err)
-			}
-			return dsc, nil
+		config.SetKubeSchedulerCfgPath(defaultSchedulerCfgPath)
+		dsc, err := config.DefaultSchedulerConfig()
+		if err != nil {
+			return nil, xerrors.Errorf("create default scheduler config: %w", err)
 		}
+		return dsc, nil
 	}
-	config.SetKubeSchedulerCfgPath(kubeSchedulerConfigPath)
-	data, err := os.ReadFile(kubeSchedulerConfigPath)
+
+	var externalClient clientset.Interface
+	if externalKubeClientCfg != nil {
+		c, err := clientset.NewForConfig(externalKubeClientCfg)
+		if err != nil {
+			return nil, xerrors.Errorf("create the client of the external cluster: %w", err)
+		}
+		externalClient = c
+	}
+	data, err := readSchedulerCfgSource(context.Background(), kubeSchedulerConfigPath, externalClient)
 	if err != nil {
-		return nil, xerrors.Errorf("read scheduler config file: %w", err)
+		return nil, err
+	}
+	if isRemoteSchedulerCfg(kubeSchedulerConfigPath) {
+		// The changes of the config can't be written back to the URL or the ConfigMap.
+		config.SetKubeSchedulerCfgPath(defaultSchedulerCfgPath)
+	} else {
+		config.SetKubeSchedulerCfgPath(kubeSchedulerConfigPath)
 	}
 
-	sc, err := decodeSchedulerCfg(data)
+	sc, err := DecodeSchedulerCfg(data)
 	if err != nil {
 		return nil, xerrors.Errorf("decode scheduler config file: %w", err)
 	}
@@ -256,53 +827,124 @@ func GetSchedulerCfg() (*configv1.KubeSchedulerConfiguration, error) {
 	return sc, nil
 }
 
-// getExternalImportEnabled reads EXTERNAL_IMPORT_ENABLED and convert it to bool
+// getExternalImportEnabled reads the ExternalImportEnabled option
 // if empty from the config file.
-// This function will return `true` if `EXTERNAL_IMPORT_ENABLED` is "1".
-func getExternalImportEnabled() bool {
-	isExternalImportEnabledString := os.Getenv("EXTERNAL_IMPORT_ENABLED")
-	if isExternalImportEnabledString == "" {
-		isExternalImportEnabledString = strconv.FormatBool(configYaml.ExternalImportEnabled)
-	}
-	isExternalImportEnabled, _ := strconv.ParseBool(isExternalImportEnabledString)
-	return isExternalImportEnabled
+func getExternalImportEnabled(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) bool {
+	return getBool(opts.ExternalImportEnabled, configYaml.ExternalImportEnabled)
 }
 
-// getResourceSyncEnabled reads RESOURCE_SYNC_ENABLED and converts it to bool
+// getImportManifestsPath reads the ImportManifestsPath option
 // if empty from the config file.
-// This function will return `true` if `RESOURCE_SYNC_ENABLED` is "1".
-func getResourceSyncEnabled() bool {
-	resourceSyncEnabledString := os.Getenv("RESOURCE_SYNC_ENABLED")
-	if resourceSyncEnabledString == "" {
-		resourceSyncEnabledString = strconv.FormatBool(configYaml.ResourceSyncEnabled)
+func getImportManifestsPath(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) string {
+	importManifestsPath := opts.ImportManifestsPath
+	if importManifestsPath == "" {
+		importManifestsPath = configYaml.ImportManifestsPath
 	}
-	resourceSyncEnabled, _ := strconv.ParseBool(resourceSyncEnabledString)
-	return resourceSyncEnabled
+	return importManifestsPath
 }
 
-// getReplayerEnabled reads REPLAYER_ENABLED and converts it to bool
+// getResourceSyncEnabled reads the ResourceSyncEnabled option
 // if empty from the config file.
-// This function will return `true` if `REPLAYER_ENABLED` is "1".
-func getReplayerEnabled() bool {
-	replayerEnabledString := os.Getenv("REPLAYER_ENABLED")
-	if replayerEnabledString == "" {
-		replayerEnabledString = strconv.FormatBool(configYaml.ReplayerEnabled)
-	}
-	replayerEnabled, _ := strconv.ParseBool(replayerEnabledString)
-	return replayerEnabled
+func getResourceSyncEnabled(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) bool {
+	return getBool(opts.ResourceSyncEnabled, configYaml.ResourceSyncEnabled)
 }
 
-// getRecordFilePath reads RECORD_FILE_PATH
+// getExternalSchedulerEnabled reads the ExternalSchedulerEnabled option
 // if empty from the config file.
-func getRecordFilePath() string {
-	recordFilePath := os.Getenv("RECORD_FILE_PATH")
+func getExternalSchedulerEnabled(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) bool {
+	return getBool(opts.ExternalSchedulerEnabled, configYaml.ExternalSchedulerEnabled)
+}
+
+// getReplayerEnabled reads the ReplayerEnabled option
+// if empty from the config file.
+func getReplayerEnabled(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) bool {
+	return getBool(opts.ReplayerEnabled, configYaml.ReplayerEnabled)
+}
+
+// getRecordEnabled reads the RecordEnabled option
+// if empty from the config file.
+func getRecordEnabled(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) bool {
+	return getBool(opts.RecordEnabled, configYaml.RecordEnabled)
+}
+
+// getRecordFilePath reads the RecordFilePath option
+// if empty from the config file.
+func getRecordFilePath(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) string {
+	recordFilePath := opts.RecordFilePath
 	if recordFilePath == "" {
 		recordFilePath = configYaml.RecordFilePath
 	}
 	return recordFilePath
 }
 
-func decodeSchedulerCfg(buf []byte) (*configv1.KubeSchedulerConfiguration, error) {
+// getKubeConfig reads the KubeConfig option
+// if empty from the config file.
+func getKubeConfig(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) string {
+	if opts.KubeConfig != "" {
+		return opts.KubeConfig
+	}
+	return configYaml.KubeConfig
+}
+
+// getExternalKubeContext reads the ExternalKubeContext option
+// if empty from the config file.
+func getExternalKubeContext(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) string {
+	if opts.ExternalKubeContext != "" {
+		return opts.ExternalKubeContext
+	}
+	return configYaml.ExternalKubeContext
+}
+
+// getExternalInCluster reads the ExternalInCluster option
+// if empty from the config file.
+func getExternalInCluster(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) bool {
+	return getBool(opts.ExternalInCluster, configYaml.ExternalInCluster)
+}
+
+// buildExternalKubeClientCfg builds the config to access the cluster which the simulator imports or syncs resources from.
+// If inCluster is true, it's the cluster which the simulator is running in.
+// Otherwise, it's the kubeContext in kubeConfig, which is looked up like kubectl, e.g., from KUBECONFIG, if it's empty.
+func buildExternalKubeClientCfg(kubeConfig, kubeContext string, inCluster bool) (*rest.Config, error) {
+	if inCluster {
+		if kubeConfig != "" || kubeContext != "" {
+			return nil, xerrors.New("externalInCluster cannot be used with kubeConfig or externalKubeContext")
+		}
+		cfg, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, xerrors.Errorf("get in-cluster config: %w", err)
+		}
+		return cfg, nil
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeConfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: kubeContext})
+	if kubeContext != "" {
+		raw, err := clientConfig.RawConfig()
+		if err != nil {
+			return nil, xerrors.Errorf("load kubeconfig: %w", err)
+		}
+		if _, ok := raw.Contexts[kubeContext]; !ok {
+			return nil, xerrors.Errorf("context %q not found in the kubeconfig", kubeContext)
+		}
+	}
+	cfg, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, xerrors.Errorf("get client config: %w", err)
+	}
+	return cfg, nil
+}
+
+// getBool returns the option if it's given, otherwise the value in the config file.
+func getBool(option *bool, fromFile bool) bool {
+	if option != nil {
+		return *option
+	}
+	return fromFile
+}
+
+// DecodeSchedulerCfg decodes the given bytes into *configv1.KubeSchedulerConfiguration.
+func DecodeSchedulerCfg(buf []byte) (*configv1.KubeSchedulerConfiguration, error) {
 	decoder := scheme.Codecs.UniversalDeserializer()
 	obj, _, err := decoder.Decode(buf, nil, nil)
 	if err != nil {
@@ -1,16 +1,33 @@
 package config
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/rest"
 	configv1 "k8s.io/kube-scheduler/config/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/auth"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config/v1alpha1"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/logging"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/ratelimit"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/recorder"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/syncer"
 )
 
-func Test_decodeSchedulerCfg(t *testing.T) {
+func TestDecodeSchedulerCfg(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
@@ -86,9 +103,9 @@ profiles:
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got, err := decodeSchedulerCfg(tt.buf)
+			got, err := DecodeSchedulerCfg(tt.buf)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("decodeSchedulerCfg() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("DecodeSchedulerCfg() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if got != nil {
@@ -251,3 +268,749 @@ func Test_hasTwoOrMoreTrue(t *testing.T) {
 		})
 	}
 }
+
+func Test_convertAuthConfiguration(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		cfg     *v1alpha1.AuthConfiguration
+		want    *auth.Options
+		wantErr bool
+	}{
+		{
+			name: "not configured",
+		},
+		{
+			name: "static tokens and OIDC",
+			cfg: &v1alpha1.AuthConfiguration{
+				Tokens:              []v1alpha1.StaticToken{{Name: "ci", Token: "secret", Role: "read-write"}},
+				OIDC:                &v1alpha1.OIDCConfiguration{IssuerURL: "https://issuer.example.com", Audience: "simulator", ReadWriteGroups: []string{"admins"}},
+				AuthenticateMetrics: true,
+			},
+			want: &auth.Options{
+				Tokens:              []auth.StaticToken{{Name: "ci", Token: "secret", Role: auth.RoleReadWrite}},
+				OIDC:                &auth.OIDCOptions{IssuerURL: "https://issuer.example.com", Audience: "simulator", ReadWriteGroups: []string{"admins"}},
+				AuthenticateMetrics: true,
+			},
+		},
+		{
+			name:    "unknown role",
+			cfg:     &v1alpha1.AuthConfiguration{Tokens: []v1alpha1.StaticToken{{Name: "ci", Token: "secret", Role: "admin"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := convertAuthConfiguration(tt.cfg)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, auth.ErrInvalidOptions)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_convertRateLimitConfiguration(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		cfg     *v1alpha1.RateLimitConfiguration
+		want    *ratelimit.Options
+		wantErr bool
+	}{
+		{
+			name: "not configured",
+			want: &ratelimit.Options{Default: ratelimit.Limit{RequestsPerSecond: 10, Burst: 20}},
+		},
+		{
+			name: "unlimited with a route override",
+			cfg: &v1alpha1.RateLimitConfiguration{
+				RequestsPerSecond: ptr.To[int32](0),
+				Routes:            []v1alpha1.RouteRateLimit{{Method: "post", Path: "/api/v1/pods/bulk", RequestsPerSecond: 1, Burst: 5}},
+			},
+			want: &ratelimit.Options{
+				Routes: []ratelimit.RouteLimit{{Method: "POST", Path: "/api/v1/pods/bulk", Limit: ratelimit.Limit{RequestsPerSecond: 1, Burst: 5}}},
+			},
+		},
+		{
+			name: "only burst",
+			cfg:  &v1alpha1.RateLimitConfiguration{Burst: 50},
+			want: &ratelimit.Options{Default: ratelimit.Limit{RequestsPerSecond: 10, Burst: 50}},
+		},
+		{
+			name:    "negative rate",
+			cfg:     &v1alpha1.RateLimitConfiguration{RequestsPerSecond: ptr.To[int32](-1)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := convertRateLimitConfiguration(tt.cfg)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ratelimit.ErrInvalidOptions)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_normalizeCorsAllowedMethods(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		methods []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "default",
+			want: []string{"GET", "HEAD", "PUT", "PATCH", "POST", "DELETE"},
+		},
+		{
+			name:    "lower-case methods",
+			methods: []string{"get", " post"},
+			want:    []string{"GET", "POST"},
+		},
+		{
+			name:    "unknown method",
+			methods: []string{"GET", "FETCH"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := normalizeCorsAllowedMethods(tt.methods)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_convertSyncerAndRecorderConfiguration(t *testing.T) {
+	t.Parallel()
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	flushInterval := 10 * time.Second
+	tests := []struct {
+		name         string
+		syncer       *v1alpha1.SyncerConfiguration
+		recorder     *v1alpha1.RecorderConfiguration
+		wantSyncer   syncer.Options
+		wantRecorder recorder.Options
+		wantErr      bool
+	}{
+		{
+			name: "default",
+		},
+		{
+			name:     "empty resources are defaulted",
+			syncer:   &v1alpha1.SyncerConfiguration{LabelSelector: "env=staging"},
+			recorder: &v1alpha1.RecorderConfiguration{},
+			wantSyncer: syncer.Options{
+				LabelSelector: "env=staging",
+			},
+		},
+		{
+			name:         "resources",
+			syncer:       &v1alpha1.SyncerConfiguration{Resources: []v1alpha1.GroupVersionResource{{Version: "v1", Resource: "pods"}}},
+			recorder:     &v1alpha1.RecorderConfiguration{Resources: []v1alpha1.GroupVersionResource{{Version: "v1", Resource: "pods"}}, FlushInterval: &metav1.Duration{Duration: flushInterval}},
+			wantSyncer:   syncer.Options{GVRs: []schema.GroupVersionResource{pods}},
+			wantRecorder: recorder.Options{GVRs: []schema.GroupVersionResource{pods}, FlushInterval: &flushInterval},
+		},
+		{
+			name:    "resource without version",
+			syncer:  &v1alpha1.SyncerConfiguration{Resources: []v1alpha1.GroupVersionResource{{Resource: "pods"}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid labelSelector",
+			syncer:  &v1alpha1.SyncerConfiguration{LabelSelector: "env in"},
+			wantErr: true,
+		},
+		{
+			name:     "non-positive flushInterval",
+			recorder: &v1alpha1.RecorderConfiguration{FlushInterval: &metav1.Duration{}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gotSyncer, syncerErr := convertSyncerConfiguration(tt.syncer)
+			gotRecorder, recorderErr := convertRecorderConfiguration(tt.recorder)
+			if tt.wantErr {
+				assert.Error(t, errors.Join(syncerErr, recorderErr))
+				return
+			}
+			require.NoError(t, syncerErr)
+			require.NoError(t, recorderErr)
+			assert.Equal(t, tt.wantSyncer, gotSyncer)
+			assert.Equal(t, tt.wantRecorder, gotRecorder)
+		})
+	}
+}
+
+func TestNewConfig(t *testing.T) {
+	t.Parallel()
+	const validConfig = `apiVersion: simulator.config.sigs.k8s.io/v1alpha1
+kind: SimulatorConfiguration
+port: 1212
+etcdURL: http://file:2379
+kubeApiServerUrl: http://file:3131
+syncer:
+  labelSelector: env=staging
+`
+	tests := []struct {
+		name     string
+		config   string
+		opts     *Options
+		wantErr  bool
+		validate func(t *testing.T, cfg *Config)
+	}{
+		{
+			name:   "from the config file",
+			config: validConfig,
+			opts:   &Options{},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.Equal(t, 1212, cfg.Ports.API.Port)
+				assert.Equal(t, "http://file:2379", cfg.EtcdURL)
+				assert.Equal(t, "env=staging", cfg.Syncer.LabelSelector)
+				assert.Equal(t, defaultRequestTimeout, cfg.RequestTimeout)
+				assert.Equal(t, defaultWatcherHeartbeatInterval, cfg.WatcherHeartbeatInterval)
+				assert.Nil(t, cfg.Recorder.GVRs)
+				assert.Equal(t, float32(defaultKubeClientQPS), cfg.KubeClientQPS)
+				assert.Equal(t, defaultKubeClientBurst, cfg.KubeClientBurst)
+			},
+		},
+		{
+			name:   "the QPS and the burst of the clients",
+			config: validConfig + "kubeClientQPS: 300\nkubeClientBurst: 600\nexternalKubeClientQPS: 20\nexternalKubeClientBurst: 40\n",
+			opts:   &Options{},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.Equal(t, float32(300), cfg.KubeClientQPS)
+				assert.Equal(t, 600, cfg.KubeClientBurst)
+				assert.Equal(t, float32(20), cfg.ExternalKubeClientQPS)
+				assert.Equal(t, 40, cfg.ExternalKubeClientBurst)
+			},
+		},
+		{
+			name:   "the options take precedence over the config file",
+			config: validConfig,
+			opts:   &Options{Port: 1313, KubeAPIServerURL: "http://option:3131", RecordFilePath: "/tmp/record.json"},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.Equal(t, 1313, cfg.Ports.API.Port)
+				assert.Equal(t, "http://option:3131", cfg.KubeAPIServerURL)
+				assert.Equal(t, "http://file:2379", cfg.EtcdURL)
+				assert.Equal(t, "/tmp/record.json", cfg.Recorder.RecordFile)
+			},
+		},
+		{
+			name:   "the logging options",
+			config: validConfig + "logVerbosity: 2\nlogFormat: json\nlogComponentVerbosity:\n  syncer: 4\n",
+			opts:   &Options{},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.Equal(t, logging.Options{Format: logging.FormatJSON, Verbosity: 2, Components: map[string]int{"syncer": 4}}, cfg.LoggingOptions())
+			},
+		},
+		{
+			name:   "the feature gates of the scheduler",
+			config: validConfig + "schedulerFeatureGates:\n  DynamicResourceAllocation: true\n",
+			opts:   &Options{},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.Equal(t, map[string]bool{"DynamicResourceAllocation": true}, cfg.SchedulerFeatureGates)
+			},
+		},
+		{
+			name:   "the ports of the listeners",
+			config: validConfig + "ports:\n  api:\n    address: 127.0.0.1\n    port: 1414\n  metrics:\n    port: 9090\n  extenderProxy:\n    address: 127.0.0.1\n    port: 1415\n  grpc:\n    address: 127.0.0.1\n    port: 1416\n",
+			opts:   &Options{},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.Equal(t, Ports{
+					API:           Listener{Address: "127.0.0.1", Port: 1414},
+					Metrics:       Listener{Port: 9090},
+					ExtenderProxy: Listener{Address: "127.0.0.1", Port: 1415},
+					GRPC:          Listener{Address: "127.0.0.1", Port: 1416},
+				}, cfg.Ports)
+				assert.Equal(t, "127.0.0.1:1415", cfg.Ports.ExtenderProxyAddress())
+			},
+		},
+		{
+			name:   "the port option takes precedence over ports.api",
+			config: validConfig + "ports:\n  api:\n    address: 127.0.0.1\n    port: 1414\n",
+			opts:   &Options{Port: 1313},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.Equal(t, Listener{Address: "127.0.0.1", Port: 1313}, cfg.Ports.API)
+				assert.Equal(t, "127.0.0.1:1313", cfg.Ports.ExtenderProxyAddress())
+			},
+		},
+		{
+			name:    "colliding ports",
+			config:  validConfig + "ports:\n  metrics:\n    port: 1212\n",
+			opts:    &Options{},
+			wantErr: true,
+		},
+		{
+			name:   "the legacy apiVersion",
+			config: strings.Replace(validConfig, "simulator.config.sigs.k8s.io/v1alpha1", "kube-scheduler-simulator-config/v1alpha1", 1),
+			opts:   &Options{},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.Equal(t, 1212, cfg.Ports.API.Port)
+			},
+		},
+		{
+			name:    "unknown field",
+			config:  validConfig + "prot: 1212\n",
+			opts:    &Options{},
+			wantErr: true,
+		},
+		{
+			name:    "unknown field in a section",
+			config:  validConfig + "  resoures: []\n",
+			opts:    &Options{},
+			wantErr: true,
+		},
+		{
+			name:   "the URLs are normalized",
+			config: validConfig,
+			opts:   &Options{KubeAPIServerURL: " https://option/ ", EtcdURL: "http://[::1]"},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.Equal(t, "https://option:3131", cfg.KubeAPIServerURL)
+				assert.Equal(t, "http://[::1]:2379", cfg.EtcdURL)
+			},
+		},
+		{
+			name:    "the URL without the scheme",
+			config:  validConfig,
+			opts:    &Options{EtcdURL: "localhost:2379"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown apiVersion",
+			config:  strings.Replace(validConfig, "simulator.config.sigs.k8s.io/v1alpha1", "simulator.config.sigs.k8s.io/v1", 1),
+			opts:    &Options{},
+			wantErr: true,
+		},
+		{
+			name:    "without kind",
+			config:  strings.Replace(validConfig, "kind: SimulatorConfiguration\n", "", 1),
+			opts:    &Options{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.config), 0o600))
+			tt.opts.ConfigFile = path
+
+			got, err := NewConfig(tt.opts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tt.validate(t, got)
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+	valid := func() *Config {
+		return &Config{
+			Ports:            Ports{API: Listener{Port: 1212}},
+			EtcdURL:          "http://127.0.0.1:2379",
+			KubeAPIServerURL: "http://localhost:3131",
+		}
+	}
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr []string
+	}{
+		{
+			name:   "valid",
+			modify: func(_ *Config) {},
+		},
+		{
+			name: "valid with all the optional features",
+			modify: func(c *Config) {
+				c.Ports.GRPC.Port = 1213
+				c.ExternalImportEnabled = true
+				c.ResourceSyncEnabled = true
+				c.AllowExternalImportWithSync = true
+				c.ExternalKubeClientCfg = &rest.Config{}
+			},
+		},
+		{
+			name:    "port out of range",
+			modify:  func(c *Config) { c.Ports.API.Port = 70000 },
+			wantErr: []string{"ports.api.port 70000 is out of range"},
+		},
+		{
+			name:    "grpc port out of range",
+			modify:  func(c *Config) { c.Ports.GRPC.Port = -1 },
+			wantErr: []string{"ports.grpc -1 is out of range"},
+		},
+		{
+			name:    "grpc collides with api",
+			modify:  func(c *Config) { c.Ports.GRPC.Port = 1212 },
+			wantErr: []string{"ports.grpc collides with ports.api on port 1212"},
+		},
+		{
+			name:    "metrics port out of range",
+			modify:  func(c *Config) { c.Ports.Metrics.Port = 70000 },
+			wantErr: []string{"ports.metrics 70000 is out of range"},
+		},
+		{
+			name:    "address isn't an IP",
+			modify:  func(c *Config) { c.Ports.Metrics = Listener{Address: "localhost", Port: 9090} },
+			wantErr: []string{`ports.metrics.address "localhost" must be an IP address`},
+		},
+		{
+			name:    "metrics collides with api",
+			modify:  func(c *Config) { c.Ports.Metrics.Port = 1212 },
+			wantErr: []string{"ports.metrics collides with ports.api on port 1212"},
+		},
+		{
+			name: "extender proxy on the same address as metrics",
+			modify: func(c *Config) {
+				c.Ports.Metrics = Listener{Address: "127.0.0.1", Port: 9090}
+				c.Ports.ExtenderProxy = Listener{Address: "127.0.0.1", Port: 9090}
+			},
+			wantErr: []string{"ports.extenderProxy collides with ports.metrics on port 9090"},
+		},
+		{
+			name: "extender proxy collides with metrics listening on all the interfaces",
+			modify: func(c *Config) {
+				c.Ports.Metrics = Listener{Port: 9090}
+				c.Ports.ExtenderProxy = Listener{Address: "127.0.0.1", Port: 9090}
+			},
+			wantErr: []string{"ports.extenderProxy collides with ports.metrics on port 9090"},
+		},
+		{
+			name: "the same port on the different addresses",
+			modify: func(c *Config) {
+				c.Ports.Metrics = Listener{Address: "127.0.0.1", Port: 9090}
+				c.Ports.ExtenderProxy = Listener{Address: "10.0.0.1", Port: 9090}
+			},
+		},
+		{
+			name: "grpc collides with extender proxy",
+			modify: func(c *Config) {
+				c.Ports.GRPC.Port = 1214
+				c.Ports.ExtenderProxy.Port = 1214
+			},
+			wantErr: []string{"ports.grpc collides with ports.extenderProxy on port 1214"},
+		},
+		{
+			name: "grpc on another address than extender proxy",
+			modify: func(c *Config) {
+				c.Ports.GRPC = Listener{Address: "127.0.0.1", Port: 1214}
+				c.Ports.ExtenderProxy = Listener{Address: "10.0.0.1", Port: 1214}
+			},
+		},
+		{
+			name:    "kube-apiserver collides with port",
+			modify:  func(c *Config) { c.KubeAPIServerURL = "http://127.0.0.1:1212" },
+			wantErr: []string{"the port of kubeApiServerUrl collides with ports.api 1212"},
+		},
+		{
+			name:   "kube-apiserver on the other host can have the same port",
+			modify: func(c *Config) { c.KubeAPIServerURL = "http://simulator-cluster:1212" },
+		},
+		{
+			name:    "invalid etcd URL",
+			modify:  func(c *Config) { c.EtcdURL = "127.0.0.1:2379" },
+			wantErr: []string{"etcdURL"},
+		},
+		{
+			name:    "invalid kube-apiserver URL",
+			modify:  func(c *Config) { c.KubeAPIServerURL = "ftp://localhost:3131" },
+			wantErr: []string{"kubeApiServerUrl"},
+		},
+		{
+			name:    "externalImportEnabled without kubeconfig",
+			modify:  func(c *Config) { c.ExternalImportEnabled = true },
+			wantErr: []string{"externalImportEnabled requires the kubeconfig"},
+		},
+		{
+			name:    "resourceSyncEnabled without kubeconfig",
+			modify:  func(c *Config) { c.ResourceSyncEnabled = true },
+			wantErr: []string{"resourceSyncEnabled requires the kubeconfig"},
+		},
+		{
+			name:    "negative client QPS",
+			modify:  func(c *Config) { c.KubeClientQPS = -1 },
+			wantErr: []string{"kubeClientQPS and kubeClientBurst must not be negative"},
+		},
+		{
+			name:    "negative external client burst",
+			modify:  func(c *Config) { c.ExternalKubeClientBurst = -1 },
+			wantErr: []string{"externalKubeClientQPS and externalKubeClientBurst must not be negative"},
+		},
+		{
+			name: "the format and the verbosity of the components",
+			modify: func(c *Config) {
+				c.LogFormat = "json"
+				c.LogComponentVerbosity = map[string]int{"syncer": 4}
+			},
+		},
+		{
+			name:    "unknown log format",
+			modify:  func(c *Config) { c.LogFormat = "yaml" },
+			wantErr: []string{`logFormat "yaml" must be "text" or "json"`},
+		},
+		{
+			name:    "unknown component of the log verbosity",
+			modify:  func(c *Config) { c.LogComponentVerbosity = map[string]int{"scheduler": 4} },
+			wantErr: []string{`logComponentVerbosity has the unknown component "scheduler"`},
+		},
+		{
+			name:    "negative verbosity of the component",
+			modify:  func(c *Config) { c.LogComponentVerbosity = map[string]int{"recorder": -1} },
+			wantErr: []string{`logComponentVerbosity -1 of "recorder" must not be negative`},
+		},
+		{
+			name:   "the feature gates of the scheduler",
+			modify: func(c *Config) { c.SchedulerFeatureGates = map[string]bool{"DynamicResourceAllocation": true} },
+		},
+		{
+			name:    "unknown feature gate of the scheduler",
+			modify:  func(c *Config) { c.SchedulerFeatureGates = map[string]bool{"NoSuchFeature": true} },
+			wantErr: []string{"schedulerFeatureGates: unknown feature gates [NoSuchFeature], the valid gates are ["},
+		},
+		{
+			name:    "replayEnabled without recordFilePath",
+			modify:  func(c *Config) { c.ReplayerEnabled = true },
+			wantErr: []string{"replayEnabled requires recordFilePath"},
+		},
+		{
+			name:    "recordEnabled without recordFilePath",
+			modify:  func(c *Config) { c.RecordEnabled = true },
+			wantErr: []string{"recordEnabled requires recordFilePath"},
+		},
+		{
+			name: "recordEnabled and replayEnabled",
+			modify: func(c *Config) {
+				c.RecordEnabled = true
+				c.ReplayerEnabled = true
+				c.RecordFilePath = "/record.jsonl"
+			},
+			wantErr: []string{"recordEnabled and replayEnabled cannot be used simultaneously"},
+		},
+		{
+			name: "recordEnabled with resourceSyncEnabled",
+			modify: func(c *Config) {
+				c.RecordEnabled = true
+				c.RecordFilePath = "/record.jsonl"
+				c.ResourceSyncEnabled = true
+				c.ExternalKubeClientCfg = &rest.Config{}
+			},
+		},
+		{
+			name: "externalImportEnabled and resourceSyncEnabled without allowExternalImportWithSync",
+			modify: func(c *Config) {
+				c.ExternalImportEnabled = true
+				c.ResourceSyncEnabled = true
+				c.ExternalKubeClientCfg = &rest.Config{}
+			},
+			wantErr: []string{"cannot be used simultaneously"},
+		},
+		{
+			name: "allowExternalImportWithSync doesn't allow the other combinations",
+			modify: func(c *Config) {
+				c.ResourceSyncEnabled = true
+				c.ImportManifestsPath = "/manifests"
+				c.AllowExternalImportWithSync = true
+				c.ExternalKubeClientCfg = &rest.Config{}
+			},
+			wantErr: []string{"cannot be used simultaneously"},
+		},
+		{
+			name: "all the violations are returned at once",
+			modify: func(c *Config) {
+				c.Ports.API.Port = 0
+				c.EtcdURL = ""
+				c.ExternalImportEnabled = true
+				c.ReplayerEnabled = true
+			},
+			wantErr: []string{
+				"ports.api.port 0 is out of range",
+				"etcdURL",
+				"externalImportEnabled requires the kubeconfig",
+				"replayEnabled requires recordFilePath",
+				"cannot be used simultaneously",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := valid()
+			tt.modify(c)
+			err := c.Validate()
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var agg utilerrors.Aggregate
+			require.ErrorAs(t, err, &agg)
+			assert.Len(t, agg.Errors(), len(tt.wantErr))
+			for _, want := range tt.wantErr {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestConfig_KubeClientConfig(t *testing.T) {
+	t.Parallel()
+	c := &Config{
+		KubeAPIServerURL:        "http://localhost:3131",
+		KubeClientQPS:           100,
+		KubeClientBurst:         200,
+		ExternalKubeClientCfg:   &rest.Config{Host: "https://external:6443", QPS: 1, Burst: 2},
+		ExternalKubeClientQPS:   20,
+		ExternalKubeClientBurst: 40,
+	}
+
+	got := c.KubeClientConfig()
+	assert.Equal(t, &rest.Config{Host: "http://localhost:3131", QPS: 100, Burst: 200}, got)
+
+	external := c.ExternalKubeClientConfig()
+	assert.Equal(t, "https://external:6443", external.Host)
+	assert.Equal(t, float32(20), external.QPS)
+	assert.Equal(t, 40, external.Burst)
+	assert.Equal(t, float32(1), c.ExternalKubeClientCfg.QPS, "ExternalKubeClientCfg isn't modified")
+
+	c.ExternalKubeClientQPS, c.ExternalKubeClientBurst = 0, 0
+	external = c.ExternalKubeClientConfig()
+	assert.Equal(t, float32(1), external.QPS, "the QPS in the kubeconfig is kept if not configured")
+	assert.Equal(t, 2, external.Burst)
+
+	c.ExternalKubeClientCfg = nil
+	assert.Nil(t, c.ExternalKubeClientConfig())
+}
+
+func Test_buildExternalKubeClientCfg(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		kubeConfig  string
+		kubeContext string
+		inCluster   bool
+		wantHost    string
+		wantErr     string
+	}{
+		{
+			name:       "current context",
+			kubeConfig: "testdata/kubeconfig.yaml",
+			wantHost:   "https://staging.example.com:6443",
+		},
+		{
+			name:        "named context",
+			kubeConfig:  "testdata/kubeconfig.yaml",
+			kubeContext: "production",
+			wantHost:    "https://production.example.com:6443",
+		},
+		{
+			name:        "missing context",
+			kubeConfig:  "testdata/kubeconfig.yaml",
+			kubeContext: "development",
+			wantErr:     `context "development" not found`,
+		},
+		{
+			name:       "missing kubeconfig",
+			kubeConfig: "testdata/missing.yaml",
+			wantErr:    "get client config",
+		},
+		{
+			name:       "in-cluster with kubeconfig",
+			kubeConfig: "testdata/kubeconfig.yaml",
+			inCluster:  true,
+			wantErr:    "externalInCluster cannot be used with kubeConfig or externalKubeContext",
+		},
+		{
+			name:      "in-cluster out of the cluster",
+			inCluster: true,
+			wantErr:   "get in-cluster config",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := buildExternalKubeClientCfg(tt.kubeConfig, tt.kubeContext, tt.inCluster)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantHost, got.Host)
+		})
+	}
+}
+
+func TestPorts_ExtenderProxyAddress(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		ports Ports
+		want  string
+	}{
+		{
+			name:  "the proxy is served on the API on all the interfaces",
+			ports: Ports{API: Listener{Port: 1212}},
+			want:  "localhost:1212",
+		},
+		{
+			name:  "the proxy is served on the API on the bound address",
+			ports: Ports{API: Listener{Address: "10.0.0.1", Port: 1212}},
+			want:  "10.0.0.1:1212",
+		},
+		{
+			name:  "the proxy on its own address",
+			ports: Ports{API: Listener{Address: "10.0.0.1", Port: 1212}, ExtenderProxy: Listener{Address: "::1", Port: 1213}},
+			want:  "[::1]:1213",
+		},
+		{
+			name:  "the proxy on the unspecified address",
+			ports: Ports{API: Listener{Port: 1212}, ExtenderProxy: Listener{Address: "0.0.0.0", Port: 1213}},
+			want:  "localhost:1213",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.ports.ExtenderProxyAddress())
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config/v1alpha1"
+)
+
+// defaultEtcdDialTimeout is the default timeout to establish the connection to etcd.
+const defaultEtcdDialTimeout = 2 * time.Second
+
+// EtcdOptions are the options of the connection to etcd.
+type EtcdOptions struct {
+	// CAFile is the CA certificate to verify the server certificate of etcd.
+	// The system roots are used if it's empty.
+	CAFile string
+	// CertFile and KeyFile are the client certificate and its key for the mutual TLS.
+	CertFile string
+	KeyFile  string
+	// Username and Password are used for the authentication of etcd if they're given.
+	Username string
+	Password string
+	// DialTimeout is the timeout to establish the connection.
+	DialTimeout time.Duration
+	// MaxRetries is the maximum number of the retries of each request.
+	// The default of the etcd client is used if it's zero.
+	MaxRetries uint
+}
+
+// tlsEnabled returns true if etcd is accessed with TLS.
+func (o *EtcdOptions) tlsEnabled() bool {
+	return o.CAFile != "" || o.CertFile != "" || o.KeyFile != ""
+}
+
+// convertEtcdConfiguration converts and validates the etcd configuration in the config file.
+func convertEtcdConfiguration(cfg *v1alpha1.EtcdConfiguration) (EtcdOptions, error) {
+	opts := EtcdOptions{DialTimeout: defaultEtcdDialTimeout}
+	if cfg == nil {
+		return opts, nil
+	}
+	opts.CAFile = cfg.CAFile
+	opts.CertFile = cfg.CertFile
+	opts.KeyFile = cfg.KeyFile
+	opts.Username = cfg.Username
+	opts.Password = cfg.Password
+	if (opts.CertFile == "") != (opts.KeyFile == "") {
+		return EtcdOptions{}, xerrors.New("certFile and keyFile must be set together")
+	}
+	if (opts.Username == "") != (opts.Password == "") {
+		return EtcdOptions{}, xerrors.New("username and password must be set together")
+	}
+	if cfg.DialTimeout != nil {
+		if cfg.DialTimeout.Duration <= 0 {
+			return EtcdOptions{}, xerrors.Errorf("dialTimeout %s must be positive", cfg.DialTimeout.Duration)
+		}
+		opts.DialTimeout = cfg.DialTimeout.Duration
+	}
+	if cfg.MaxRetries != nil {
+		if *cfg.MaxRetries < 0 {
+			return EtcdOptions{}, xerrors.Errorf("maxRetries %d must not be negative", *cfg.MaxRetries)
+		}
+		opts.MaxRetries = uint(*cfg.MaxRetries)
+	}
+	return opts, nil
+}
+
+// EtcdClientConfig returns the config of the etcd client accessing EtcdURL.
+// It returns an error if the TLS files can't be read.
+func (c *Config) EtcdClientConfig() (clientv3.Config, error) {
+	cfg := clientv3.Config{
+		Endpoints:       []string{c.EtcdURL},
+		DialTimeout:     c.Etcd.DialTimeout,
+		Username:        c.Etcd.Username,
+		Password:        c.Etcd.Password,
+		MaxUnaryRetries: c.Etcd.MaxRetries,
+	}
+	if c.Etcd.tlsEnabled() {
+		tlsCfg, err := etcdTLSConfig(c.Etcd.CAFile, c.Etcd.CertFile, c.Etcd.KeyFile)
+		if err != nil {
+			return clientv3.Config{}, xerrors.Errorf("load the TLS files of etcd: %w", err)
+		}
+		cfg.TLS = tlsCfg
+	}
+	return cfg, nil
+}
+
+// etcdTLSConfig loads the CA certificate from caFile, and the client certificate from certFile and keyFile.
+// caFile, or both certFile and keyFile, can be empty.
+func etcdTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, xerrors.Errorf("read caFile: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, xerrors.Errorf("caFile %q has no PEM encoded certificate", caFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, xerrors.Errorf("load certFile %q and keyFile %q: %w", certFile, keyFile, err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config/v1alpha1"
+)
+
+// writeCertificate writes a self-signed certificate and its key in dir, and returns their paths.
+func writeCertificate(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "etcd"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile := filepath.Join(dir, "etcd.crt"), filepath.Join(dir, "etcd.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func Test_convertEtcdConfiguration(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		cfg     *v1alpha1.EtcdConfiguration
+		want    EtcdOptions
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			want: EtcdOptions{DialTimeout: defaultEtcdDialTimeout},
+		},
+		{
+			name: "all the fields",
+			cfg: &v1alpha1.EtcdConfiguration{
+				CAFile:      "/etc/etcd/ca.crt",
+				CertFile:    "/etc/etcd/client.crt",
+				KeyFile:     "/etc/etcd/client.key",
+				Username:    "root",
+				Password:    "secret",
+				DialTimeout: &metav1.Duration{Duration: 10 * time.Second},
+				MaxRetries:  ptr.To[int32](3),
+			},
+			want: EtcdOptions{
+				CAFile:      "/etc/etcd/ca.crt",
+				CertFile:    "/etc/etcd/client.crt",
+				KeyFile:     "/etc/etcd/client.key",
+				Username:    "root",
+				Password:    "secret",
+				DialTimeout: 10 * time.Second,
+				MaxRetries:  3,
+			},
+		},
+		{
+			name:    "certFile without keyFile",
+			cfg:     &v1alpha1.EtcdConfiguration{CertFile: "/etc/etcd/client.crt"},
+			wantErr: true,
+		},
+		{
+			name:    "username without password",
+			cfg:     &v1alpha1.EtcdConfiguration{Username: "root"},
+			wantErr: true,
+		},
+		{
+			name:    "zero dialTimeout",
+			cfg:     &v1alpha1.EtcdConfiguration{DialTimeout: &metav1.Duration{}},
+			wantErr: true,
+		},
+		{
+			name:    "negative maxRetries",
+			cfg:     &v1alpha1.EtcdConfiguration{MaxRetries: ptr.To[int32](-1)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := convertEtcdConfiguration(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfig_EtcdClientConfig(t *testing.T) {
+	t.Parallel()
+	certFile, keyFile := writeCertificate(t, t.TempDir())
+
+	t.Run("without TLS", func(t *testing.T) {
+		t.Parallel()
+		c := &Config{EtcdURL: "http://127.0.0.1:2379", Etcd: EtcdOptions{Username: "root", Password: "secret", DialTimeout: 5 * time.Second, MaxRetries: 3}}
+		got, err := c.EtcdClientConfig()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"http://127.0.0.1:2379"}, got.Endpoints)
+		assert.Equal(t, "root", got.Username)
+		assert.Equal(t, "secret", got.Password)
+		assert.Equal(t, 5*time.Second, got.DialTimeout)
+		assert.Equal(t, uint(3), got.MaxUnaryRetries)
+		assert.Nil(t, got.TLS)
+	})
+
+	t.Run("with TLS", func(t *testing.T) {
+		t.Parallel()
+		c := &Config{EtcdURL: "https://etcd:2379", Etcd: EtcdOptions{CAFile: certFile, CertFile: certFile, KeyFile: keyFile}}
+		got, err := c.EtcdClientConfig()
+		require.NoError(t, err)
+		require.NotNil(t, got.TLS)
+		assert.NotNil(t, got.TLS.RootCAs)
+		assert.Len(t, got.TLS.Certificates, 1)
+		assert.Equal(t, uint16(tls.VersionTLS12), got.TLS.MinVersion)
+	})
+
+	t.Run("only the CA", func(t *testing.T) {
+		t.Parallel()
+		c := &Config{EtcdURL: "https://etcd:2379", Etcd: EtcdOptions{CAFile: certFile}}
+		got, err := c.EtcdClientConfig()
+		require.NoError(t, err)
+		require.NotNil(t, got.TLS)
+		assert.Empty(t, got.TLS.Certificates)
+	})
+
+	for name, opts := range map[string]EtcdOptions{
+		"missing caFile":   {CAFile: filepath.Join(t.TempDir(), "missing.crt")},
+		"missing certFile": {CertFile: filepath.Join(t.TempDir(), "missing.crt"), KeyFile: keyFile},
+		"invalid caFile":   {CAFile: keyFile},
+	} {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c := &Config{EtcdURL: "https://etcd:2379", Etcd: opts}
+			_, err := c.EtcdClientConfig()
+			assert.ErrorContains(t, err, "load the TLS files of etcd")
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"maps"
+	"slices"
+
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/logging"
+)
+
+// LoggingOptions returns the options of the logs of the simulator.
+func (c *Config) LoggingOptions() logging.Options {
+	return logging.Options{
+		Format:     c.LogFormat,
+		Verbosity:  c.LogVerbosity,
+		Components: c.LogComponentVerbosity,
+	}
+}
+
+// validateLogging checks the format of the logs and the verbosity of the components.
+func (c *Config) validateLogging() []error {
+	var errs []error
+	switch c.LogFormat {
+	case "", logging.FormatText, logging.FormatJSON:
+	default:
+		errs = append(errs, xerrors.Errorf("logFormat %q must be %q or %q", c.LogFormat, logging.FormatText, logging.FormatJSON))
+	}
+	for _, component := range slices.Sorted(maps.Keys(c.LogComponentVerbosity)) {
+		v := c.LogComponentVerbosity[component]
+		if !slices.Contains(logging.Components, component) {
+			errs = append(errs, xerrors.Errorf("logComponentVerbosity has the unknown component %q, which must be one of %v", component, logging.Components))
+		}
+		if v < 0 {
+			errs = append(errs, xerrors.Errorf("logComponentVerbosity %d of %q must not be negative", v, component))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"strconv"
+
+	"github.com/spf13/pflag"
+)
+
+// Options is the settings given from the command line flags and the environment variables.
+// The flags take precedence over the environment variables, and both take precedence over the config file.
+// The empty fields aren't given, and the values in the config file are used for them.
+type Options struct {
+	// ConfigFile is from SIMULATOR_CONFIG or --config. The config file at ./config.yaml is read if it's empty.
+	ConfigFile string
+	// Port is from PORT or --port.
+	Port int
+	// KubeAPIServerURL is from KUBE_APISERVER_URL or --kube-apiserver-url.
+	KubeAPIServerURL string
+	// EtcdURL is from KUBE_SCHEDULER_SIMULATOR_ETCD_URL or --etcd-url.
+	EtcdURL string
+	// CheckReachability is from CHECK_REACHABILITY or --check-reachability.
+	CheckReachability *bool
+	// CorsAllowedOriginList is from CORS_ALLOWED_ORIGIN_LIST or --cors-allowed-origin-list.
+	CorsAllowedOriginList []string
+	// KubeConfig is from --kubeconfig.
+	KubeConfig string
+	// ExternalKubeContext is from --external-kube-context.
+	ExternalKubeContext string
+	// ExternalInCluster is from --external-in-cluster.
+	ExternalInCluster *bool
+	// KubeSchedulerConfigPath is from KUBE_SCHEDULER_CONFIG_PATH or --kube-scheduler-config-path.
+	KubeSchedulerConfigPath string
+	// ExternalImportEnabled is from EXTERNAL_IMPORT_ENABLED or --external-import-enabled.
+	ExternalImportEnabled *bool
+	// ImportManifestsPath is from IMPORT_MANIFESTS_PATH or --import-manifests-path.
+	ImportManifestsPath string
+	// ExternalSchedulerEnabled is from EXTERNAL_SCHEDULER_ENABLED or --external-scheduler-enabled.
+	// Deprecated: it's kept only for compatibility and has no effect.
+	ExternalSchedulerEnabled *bool
+	// ResourceSyncEnabled is from RESOURCE_SYNC_ENABLED or --resource-sync-enabled.
+	ResourceSyncEnabled *bool
+	// ReplayerEnabled is from REPLAYER_ENABLED or --replayer-enabled.
+	ReplayerEnabled *bool
+	// RecordEnabled is from RECORD_ENABLED or --record-enabled.
+	RecordEnabled *bool
+	// RecordFilePath is from RECORD_FILE_PATH or --record-file-path.
+	RecordFilePath string
+	// WatcherBearerToken is from WATCHER_BEARER_TOKEN.
+	// It has no flag not to leak the token via the command line.
+	//
+	// Deprecated: use the static tokens of auth in the config file instead.
+	WatcherBearerToken string
+}
+
+// AddFlags adds the flags setting the options to fs.
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.ConfigFile, "config", o.ConfigFile, "The path to the config file of the simulator. It can be given from SIMULATOR_CONFIG as well. (default \""+defaultFilePath+"\")")
+	fs.IntVar(&o.Port, "port", o.Port, "The port number on which the simulator server is started.")
+	fs.StringVar(&o.KubeAPIServerURL, "kube-apiserver-url", o.KubeAPIServerURL, "The URL of kube-apiserver which the simulator uses.")
+	fs.StringVar(&o.EtcdURL, "etcd-url", o.EtcdURL, "The URL of etcd which kube-apiserver uses.")
+	fs.Var(newOptionalBool(&o.CheckReachability), "check-reachability", "Connect to kube-apiserver and etcd when the config is loaded, to report the wrong URLs right away.")
+	fs.Lookup("check-reachability").NoOptDefVal = "true"
+	fs.StringSliceVar(&o.CorsAllowedOriginList, "cors-allowed-origin-list", o.CorsAllowedOriginList, "The origins allowed to access the simulator's API and kube-apiserver.")
+	fs.StringVar(&o.KubeConfig, "kubeconfig", o.KubeConfig, "The path to the kubeconfig of the cluster which the simulator imports or syncs resources from.")
+	fs.StringVar(&o.ExternalKubeContext, "external-kube-context", o.ExternalKubeContext, "The context in the kubeconfig used to access the cluster. The current context is used if it's empty.")
+	fs.Var(newOptionalBool(&o.ExternalInCluster), "external-in-cluster", "Access the cluster which the simulator is running in with the service account, instead of the kubeconfig.")
+	fs.Lookup("external-in-cluster").NoOptDefVal = "true"
+	fs.StringVar(&o.KubeSchedulerConfigPath, "kube-scheduler-config-path", o.KubeSchedulerConfigPath, "The path to the KubeSchedulerConfiguration which the scheduler is started with. It can be an http(s):// URL, or configmap://namespace/name/key in the external cluster.")
+	fs.Var(newOptionalBool(&o.ExternalImportEnabled), "external-import-enabled", "Import resources from the cluster once when the simulator is started.")
+	fs.Lookup("external-import-enabled").NoOptDefVal = "true"
+	fs.StringVar(&o.ImportManifestsPath, "import-manifests-path", o.ImportManifestsPath, "The path to a directory or a tarball of manifests which the simulator imports resources from once when it's started.")
+	fs.Var(newOptionalBool(&o.ExternalSchedulerEnabled), "external-scheduler-enabled", "Use the external scheduler.")
+	fs.Lookup("external-scheduler-enabled").NoOptDefVal = "true"
+	// MarkDeprecated returns an error only when the flag doesn't exist.
+	_ = fs.MarkDeprecated("external-scheduler-enabled", "it has no effect and will be removed in the future. See docs/external-scheduler.md.")
+	fs.Var(newOptionalBool(&o.ResourceSyncEnabled), "resource-sync-enabled", "Keep syncing resources from the cluster.")
+	fs.Lookup("resource-sync-enabled").NoOptDefVal = "true"
+	fs.Var(newOptionalBool(&o.ReplayerEnabled), "replayer-enabled", "Replay the events recorded in the record file.")
+	fs.Lookup("replayer-enabled").NoOptDefVal = "true"
+	fs.Var(newOptionalBool(&o.RecordEnabled), "record-enabled", "Record the events of the resources in the simulator to the record file.")
+	fs.Lookup("record-enabled").NoOptDefVal = "true"
+	fs.StringVar(&o.RecordFilePath, "record-file-path", o.RecordFilePath, "The path to the file where the simulator records events.")
+}
+
+// ApplyEnv sets the options which aren't given from the flags from the environment variables looked up with getenv.
+// The invalid PORT and CORS_ALLOWED_ORIGIN_LIST are ignored, and the invalid booleans are regarded as false.
+func (o *Options) ApplyEnv(getenv func(string) string) {
+	setStringFromEnv(&o.ConfigFile, getenv("SIMULATOR_CONFIG"))
+	if o.Port == 0 {
+		if port, err := strconv.Atoi(getenv("PORT")); err == nil {
+			o.Port = port
+		}
+	}
+	setStringFromEnv(&o.KubeAPIServerURL, getenv("KUBE_APISERVER_URL"))
+	setStringFromEnv(&o.EtcdURL, getenv("KUBE_SCHEDULER_SIMULATOR_ETCD_URL"))
+	setBoolFromEnv(&o.CheckReachability, getenv("CHECK_REACHABILITY"))
+	if e := getenv("CORS_ALLOWED_ORIGIN_LIST"); len(o.CorsAllowedOriginList) == 0 && e != "" {
+		if urls := parseStringListEnv(e); validateURLs(urls) == nil {
+			o.CorsAllowedOriginList = urls
+		}
+	}
+	setStringFromEnv(&o.KubeSchedulerConfigPath, getenv("KUBE_SCHEDULER_CONFIG_PATH"))
+	setBoolFromEnv(&o.ExternalImportEnabled, getenv("EXTERNAL_IMPORT_ENABLED"))
+	setStringFromEnv(&o.ImportManifestsPath, getenv("IMPORT_MANIFESTS_PATH"))
+	setBoolFromEnv(&o.ExternalSchedulerEnabled, getenv("EXTERNAL_SCHEDULER_ENABLED"))
+	setBoolFromEnv(&o.ResourceSyncEnabled, getenv("RESOURCE_SYNC_ENABLED"))
+	setBoolFromEnv(&o.ReplayerEnabled, getenv("REPLAYER_ENABLED"))
+	setBoolFromEnv(&o.RecordEnabled, getenv("RECORD_ENABLED"))
+	setStringFromEnv(&o.RecordFilePath, getenv("RECORD_FILE_PATH"))
+	setStringFromEnv(&o.WatcherBearerToken, getenv("WATCHER_BEARER_TOKEN"))
+}
+
+func setStringFromEnv(p *string, e string) {
+	if *p == "" {
+		*p = e
+	}
+}
+
+func setBoolFromEnv(p **bool, e string) {
+	if *p != nil || e == "" {
+		return
+	}
+	b, _ := strconv.ParseBool(e)
+	*p = &b
+}
+
+// optionalBool is pflag.Value of the boolean which is nil unless the flag is given.
+type optionalBool struct {
+	p **bool
+}
+
+func newOptionalBool(p **bool) *optionalBool {
+	return &optionalBool{p: p}
+}
+
+func (b *optionalBool) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*b.p = &v
+	return nil
+}
+
+func (b *optionalBool) String() string {
+	if *b.p == nil {
+		return ""
+	}
+	return strconv.FormatBool(**b.p)
+}
+
+func (b *optionalBool) Type() string {
+	return "bool"
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want *Options
+	}{
+		{
+			name: "nothing is given",
+			want: &Options{},
+		},
+		{
+			name: "from the environment variables",
+			env: map[string]string{
+				"SIMULATOR_CONFIG":                  "/etc/simulator.yaml",
+				"PORT":                              "1212",
+				"KUBE_APISERVER_URL":                "http://localhost:3131",
+				"KUBE_SCHEDULER_SIMULATOR_ETCD_URL": "http://localhost:2379",
+				"CORS_ALLOWED_ORIGIN_LIST":          "http://localhost:3000, http://localhost:3001",
+				"KUBE_SCHEDULER_CONFIG_PATH":        "/config/scheduler.yaml",
+				"EXTERNAL_IMPORT_ENABLED":           "1",
+				"RESOURCE_SYNC_ENABLED":             "false",
+				"EXTERNAL_SCHEDULER_ENABLED":        "true",
+				"WATCHER_BEARER_TOKEN":              "token",
+				"CHECK_REACHABILITY":                "true",
+			},
+			want: &Options{
+				ConfigFile:               "/etc/simulator.yaml",
+				Port:                     1212,
+				KubeAPIServerURL:         "http://localhost:3131",
+				EtcdURL:                  "http://localhost:2379",
+				CorsAllowedOriginList:    []string{"http://localhost:3000", "http://localhost:3001"},
+				KubeSchedulerConfigPath:  "/config/scheduler.yaml",
+				ExternalImportEnabled:    ptr.To(true),
+				ResourceSyncEnabled:      ptr.To(false),
+				ExternalSchedulerEnabled: ptr.To(true),
+				WatcherBearerToken:       "token",
+				CheckReachability:        ptr.To(true),
+			},
+		},
+		{
+			name: "the flags take precedence over the environment variables",
+			args: []string{
+				"--config=/etc/simulator.yaml",
+				"--port=1313",
+				"--kube-apiserver-url=http://flag:3131",
+				"--cors-allowed-origin-list=http://flag:3000",
+				"--kubeconfig=/root/.kube/config",
+				"--external-kube-context=production",
+				"--external-in-cluster=false",
+				"--external-import-enabled=false",
+				"--resource-sync-enabled",
+				"--external-scheduler-enabled=false",
+			},
+			env: map[string]string{
+				"SIMULATOR_CONFIG":                  "/etc/env.yaml",
+				"PORT":                              "1212",
+				"KUBE_APISERVER_URL":                "http://env:3131",
+				"KUBE_SCHEDULER_SIMULATOR_ETCD_URL": "http://env:2379",
+				"CORS_ALLOWED_ORIGIN_LIST":          "http://env:3000",
+				"EXTERNAL_IMPORT_ENABLED":           "true",
+				"RESOURCE_SYNC_ENABLED":             "false",
+				"EXTERNAL_SCHEDULER_ENABLED":        "true",
+			},
+			want: &Options{
+				ConfigFile:               "/etc/simulator.yaml",
+				Port:                     1313,
+				KubeAPIServerURL:         "http://flag:3131",
+				EtcdURL:                  "http://env:2379",
+				CorsAllowedOriginList:    []string{"http://flag:3000"},
+				KubeConfig:               "/root/.kube/config",
+				ExternalKubeContext:      "production",
+				ExternalInCluster:        ptr.To(false),
+				ExternalImportEnabled:    ptr.To(false),
+				ResourceSyncEnabled:      ptr.To(true),
+				ExternalSchedulerEnabled: ptr.To(false),
+			},
+		},
+		{
+			name: "the invalid environment variables are ignored",
+			env: map[string]string{
+				"PORT":                     "invalid",
+				"CORS_ALLOWED_ORIGIN_LIST": "invalid",
+				"REPLAYER_ENABLED":         "invalid",
+			},
+			want: &Options{
+				ReplayerEnabled: ptr.To(false),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts := &Options{}
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			opts.AddFlags(fs)
+			require.NoError(t, fs.Parse(tt.args))
+			opts.ApplyEnv(func(key string) string { return tt.env[key] })
+			assert.Equal(t, tt.want, opts)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config/v1alpha1"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/podlifecycle"
+)
+
+// convertPodLifecycleConfiguration converts and validates the pod lifecycle configuration in the config file.
+// It returns whether the pod lifecycle simulator is enabled, and its options.
+func convertPodLifecycleConfiguration(cfg *v1alpha1.PodLifecycleConfiguration) (bool, podlifecycle.Options, error) {
+	if cfg == nil {
+		return false, podlifecycle.Options{}, nil
+	}
+	opts := podlifecycle.Options{DeleteSucceededPods: cfg.DeleteSucceededPods}
+	if cfg.RunningDelay != nil {
+		if cfg.RunningDelay.Duration < 0 {
+			return false, podlifecycle.Options{}, xerrors.Errorf("runningDelay must not be negative: %s", cfg.RunningDelay.Duration)
+		}
+		opts.RunningDelay = &cfg.RunningDelay.Duration
+	}
+	return cfg.Enabled, opts, nil
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config/v1alpha1"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/podlifecycle"
+)
+
+func Test_convertPodLifecycleConfiguration(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		cfg         *v1alpha1.PodLifecycleConfiguration
+		wantEnabled bool
+		want        podlifecycle.Options
+		wantErr     bool
+	}{
+		{
+			name: "disabled by default",
+		},
+		{
+			name:        "enabled with the options",
+			cfg:         &v1alpha1.PodLifecycleConfiguration{Enabled: true, RunningDelay: &metav1.Duration{Duration: 3 * time.Second}, DeleteSucceededPods: true},
+			wantEnabled: true,
+			want:        podlifecycle.Options{RunningDelay: ptr.To(3 * time.Second), DeleteSucceededPods: true},
+		},
+		{
+			name:        "zero runningDelay makes the pods Running right away",
+			cfg:         &v1alpha1.PodLifecycleConfiguration{Enabled: true, RunningDelay: &metav1.Duration{}},
+			wantEnabled: true,
+			want:        podlifecycle.Options{RunningDelay: ptr.To(time.Duration(0))},
+		},
+		{
+			name:    "negative runningDelay",
+			cfg:     &v1alpha1.PodLifecycleConfiguration{Enabled: true, RunningDelay: &metav1.Duration{Duration: -time.Second}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			enabled, got, err := convertPodLifecycleConfiguration(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEnabled, enabled)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config/v1alpha1"
+)
+
+// Listener is an address which a server listens on.
+type Listener struct {
+	// Address is the IP address of the interface to bind. All the interfaces are bound if it's empty.
+	Address string
+	Port    int
+}
+
+// HostPort returns the address in the form of host:port, which can be given to net.Listen.
+func (l Listener) HostPort() string {
+	return net.JoinHostPort(l.Address, strconv.Itoa(l.Port))
+}
+
+// Enabled returns true if the port is configured.
+func (l Listener) Enabled() bool {
+	return l.Port != 0
+}
+
+// collidesWith returns true if l and other can't listen at the same time.
+func (l Listener) collidesWith(other Listener) bool {
+	return l.Port == other.Port && (l.Address == "" || other.Address == "" || l.Address == other.Address)
+}
+
+// Ports are the addresses which the servers of the simulator listen on.
+type Ports struct {
+	// API is the address of the simulator's API.
+	API Listener
+	// Metrics is the address serving /metrics. It's served on API if the port is zero.
+	Metrics Listener
+	// ExtenderProxy is the address serving the proxy for the extenders. It's served on API if the port is zero.
+	ExtenderProxy Listener
+	// GRPC is the address serving the gRPC API. The gRPC API is disabled if the port is zero.
+	GRPC Listener
+}
+
+// ExtenderProxyAddress returns the address in the form of host:port which the scheduler sends the requests for the extenders to.
+// It's the loopback address if the proxy listens on all the interfaces.
+func (p Ports) ExtenderProxyAddress() string {
+	l := p.API
+	if p.ExtenderProxy.Enabled() {
+		l = p.ExtenderProxy
+	}
+	if ip := net.ParseIP(l.Address); ip == nil || ip.IsUnspecified() {
+		l.Address = "localhost"
+	}
+	return l.HostPort()
+}
+
+// getPorts gets Ports from the config file, and the port of the API from the options first.
+func getPorts(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) (Ports, error) {
+	port, err := getPort(opts, configYaml)
+	if err != nil {
+		return Ports{}, err
+	}
+	ports := convertPortsConfiguration(configYaml.Ports)
+	if opts.Port != 0 || ports.API.Port == 0 {
+		ports.API.Port = port
+	}
+	return ports, nil
+}
+
+// getPort gets Port from the options first, if empty from ports.api or port in the config file.
+func getPort(opts *Options, configYaml *v1alpha1.SimulatorConfiguration) (int, error) {
+	port := opts.Port
+	if port == 0 && configYaml.Ports != nil && configYaml.Ports.API != nil {
+		port = configYaml.Ports.API.Port
+	}
+	if port == 0 {
+		port = configYaml.Port
+		if port == 0 {
+			return 0, xerrors.Errorf("get PORT from config: %w", ErrEmptyConfig)
+		}
+	}
+	return port, nil
+}
+
+// convertPortsConfiguration converts the ports configuration in the config file.
+func convertPortsConfiguration(cfg *v1alpha1.PortsConfiguration) Ports {
+	if cfg == nil {
+		return Ports{}
+	}
+	return Ports{
+		API:           convertListenerConfiguration(cfg.API),
+		Metrics:       convertListenerConfiguration(cfg.Metrics),
+		ExtenderProxy: convertListenerConfiguration(cfg.ExtenderProxy),
+		GRPC:          convertListenerConfiguration(cfg.GRPC),
+	}
+}
+
+func convertListenerConfiguration(cfg *v1alpha1.ListenerConfiguration) Listener {
+	if cfg == nil {
+		return Listener{}
+	}
+	return Listener{Address: cfg.Address, Port: cfg.Port}
+}
+
+// validatePorts checks the ports of the listeners, and that no two of them collide.
+// The kube-apiserver at kubeAPIServerURL mustn't collide with them either if it's on the loopback address.
+func (c *Config) validatePorts() []error {
+	var errs []error
+	named := []struct {
+		name     string
+		listener Listener
+	}{
+		{name: "ports.api", listener: c.Ports.API},
+		{name: "ports.metrics", listener: c.Ports.Metrics},
+		{name: "ports.extenderProxy", listener: c.Ports.ExtenderProxy},
+		{name: "ports.grpc", listener: c.Ports.GRPC},
+	}
+	if c.Ports.API.Port <= 0 || c.Ports.API.Port > maxPort {
+		errs = append(errs, xerrors.Errorf("ports.api.port %d is out of range", c.Ports.API.Port))
+	}
+	for _, n := range named[1:] {
+		if n.listener.Port < 0 || n.listener.Port > maxPort {
+			errs = append(errs, xerrors.Errorf("%s %d is out of range", n.name, n.listener.Port))
+		}
+	}
+	for _, n := range named {
+		if n.listener.Address != "" && net.ParseIP(n.listener.Address) == nil {
+			errs = append(errs, xerrors.Errorf("%s.address %q must be an IP address", n.name, n.listener.Address))
+		}
+	}
+
+	for i := range named {
+		if !named[i].listener.Enabled() {
+			continue
+		}
+		for j := i + 1; j < len(named); j++ {
+			if named[j].listener.Enabled() && named[i].listener.collidesWith(named[j].listener) {
+				errs = append(errs, xerrors.Errorf("%s collides with %s on port %d", named[j].name, named[i].name, named[j].listener.Port))
+			}
+		}
+	}
+
+	if u, err := url.Parse(c.KubeAPIServerURL); err == nil && isLoopback(u.Hostname()) {
+		for _, n := range named {
+			if n.listener.Enabled() && u.Port() == strconv.Itoa(n.listener.Port) {
+				errs = append(errs, xerrors.Errorf("the port of kubeApiServerUrl collides with %s %d", n.name, n.listener.Port))
+			}
+		}
+	}
+	return errs
+}
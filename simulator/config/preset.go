@@ -0,0 +1,85 @@
+package config
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"golang.org/x/xerrors"
+	"sigs.k8s.io/yaml"
+)
+
+// The presets of the config file for the common modes of the simulator.
+const (
+	// PresetSandbox starts the simulator with no resources, and doesn't access any cluster.
+	PresetSandbox = "sandbox"
+	// PresetImport imports the resources and the scheduler configuration from the cluster of kubeConfig once.
+	PresetImport = "import"
+	// PresetSync keeps syncing the resources from the cluster of kubeConfig.
+	PresetSync = "sync"
+	// PresetReplay replays the events recorded in recordFilePath.
+	PresetReplay = "replay"
+)
+
+// presetField is the field of the config file which has the preset.
+const presetField = "preset"
+
+// presets are the defaults of the top-level fields of the config file for each preset.
+// The fields set in the config file take precedence over them, and the options take precedence over the config file as usual.
+var presets = map[string]map[string]interface{}{
+	PresetSandbox: {
+		"externalImportEnabled": false,
+		"importManifestsPath":   "",
+		"resourceSyncEnabled":   false,
+		"replayEnabled":         false,
+	},
+	PresetImport: {
+		"externalImportEnabled":        true,
+		"importSchedulerConfigEnabled": true,
+		// The nodes are imported as Ready so that the pods can be scheduled even if the nodes are down in the cluster.
+		"importForceNodeReady": true,
+		"resourceSyncEnabled":  false,
+		"replayEnabled":        false,
+	},
+	PresetSync: {
+		"externalImportEnabled": false,
+		"resourceSyncEnabled":   true,
+		"replayEnabled":         false,
+	},
+	PresetReplay: {
+		"externalImportEnabled": false,
+		"resourceSyncEnabled":   false,
+		"replayEnabled":         true,
+		"recordEnabled":         false,
+		"recordFilePath":        "/record.jsonl",
+	},
+}
+
+// applyPreset returns the config file conf with the defaults of its preset set to the fields not set in conf.
+// conf is returned as it is if it has no preset.
+func applyPreset(conf []byte) ([]byte, error) {
+	doc := map[string]interface{}{}
+	if err := yaml.Unmarshal(conf, &doc); err != nil {
+		return nil, xerrors.Errorf("decode the config file: %w", err)
+	}
+	preset, ok := doc[presetField]
+	if !ok {
+		return conf, nil
+	}
+	name, _ := preset.(string)
+	defaults, ok := presets[name]
+	if !ok {
+		return nil, xerrors.Errorf("unknown preset %v, must be one of %v", preset, slices.Sorted(maps.Keys(presets)))
+	}
+
+	for field, v := range defaults {
+		if _, ok := doc[field]; !ok {
+			doc[field] = v
+		}
+	}
+	ret, err := json.Marshal(doc)
+	if err != nil {
+		return nil, xerrors.Errorf("encode the config file with the preset: %w", err)
+	}
+	return ret, nil
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+func Test_applyPreset(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		conf    string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "without preset",
+			conf: "port: 1212\n",
+			want: map[string]interface{}{"port": float64(1212)},
+		},
+		{
+			name: "the defaults of the preset",
+			conf: "preset: sync\nport: 1212\n",
+			want: map[string]interface{}{
+				"preset":                "sync",
+				"port":                  float64(1212),
+				"externalImportEnabled": false,
+				"resourceSyncEnabled":   true,
+				"replayEnabled":         false,
+			},
+		},
+		{
+			name: "the fields set explicitly take precedence over the preset",
+			conf: "preset: import\nimportForceNodeReady: false\n",
+			want: map[string]interface{}{
+				"preset":                       "import",
+				"externalImportEnabled":        true,
+				"importSchedulerConfigEnabled": true,
+				"importForceNodeReady":         false,
+				"resourceSyncEnabled":          false,
+				"replayEnabled":                false,
+			},
+		},
+		{
+			name:    "unknown preset",
+			conf:    "preset: standalone\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := applyPreset([]byte(tt.conf))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			doc := map[string]interface{}{}
+			require.NoError(t, yaml.Unmarshal(got, &doc))
+			assert.Equal(t, tt.want, doc)
+		})
+	}
+}
+
+func TestNewConfig_preset(t *testing.T) {
+	t.Parallel()
+	const baseConfig = `apiVersion: simulator.config.sigs.k8s.io/v1alpha1
+kind: SimulatorConfiguration
+port: 1212
+etcdURL: http://127.0.0.1:2379
+kubeApiServerUrl: http://127.0.0.1:3131
+`
+	tests := []struct {
+		name     string
+		config   string
+		opts     *Options
+		wantErr  string
+		validate func(t *testing.T, cfg *Config)
+	}{
+		{
+			name:   "sandbox",
+			config: baseConfig + "preset: sandbox\n",
+			opts:   &Options{},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.False(t, cfg.ExternalImportEnabled)
+				assert.False(t, cfg.ResourceSyncEnabled)
+				assert.False(t, cfg.ReplayerEnabled)
+				assert.Empty(t, cfg.ImportManifestsPath)
+			},
+		},
+		{
+			name:   "import",
+			config: baseConfig + "preset: import\nkubeConfig: testdata/kubeconfig.yaml\n",
+			opts:   &Options{},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.True(t, cfg.ExternalImportEnabled)
+				assert.True(t, cfg.ImportSchedulerConfigEnabled)
+				assert.True(t, cfg.ImportForceNodeReady)
+				assert.False(t, cfg.ResourceSyncEnabled)
+				assert.NotNil(t, cfg.ExternalKubeClientCfg)
+			},
+		},
+		{
+			name:   "sync",
+			config: baseConfig + "preset: sync\nkubeConfig: testdata/kubeconfig.yaml\n",
+			opts:   &Options{},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.True(t, cfg.ResourceSyncEnabled)
+				assert.False(t, cfg.ExternalImportEnabled)
+				assert.False(t, cfg.ReplayerEnabled)
+			},
+		},
+		{
+			name:   "replay",
+			config: baseConfig + "preset: replay\n",
+			opts:   &Options{},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.True(t, cfg.ReplayerEnabled)
+				assert.Equal(t, "/record.jsonl", cfg.RecordFilePath)
+				assert.False(t, cfg.ResourceSyncEnabled)
+			},
+		},
+		{
+			name:   "the config file overrides the preset",
+			config: baseConfig + "preset: replay\nrecordFilePath: /tmp/record.jsonl\n",
+			opts:   &Options{},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.True(t, cfg.ReplayerEnabled)
+				assert.Equal(t, "/tmp/record.jsonl", cfg.RecordFilePath)
+			},
+		},
+		{
+			name:   "the options override the preset",
+			config: baseConfig + "preset: replay\n",
+			opts:   &Options{ReplayerEnabled: ptr.To(false)},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.False(t, cfg.ReplayerEnabled)
+			},
+		},
+		{
+			name:    "the config with the preset is validated",
+			config:  baseConfig + "preset: import\n",
+			opts:    &Options{},
+			wantErr: "externalImportEnabled requires the kubeconfig",
+		},
+		{
+			name:    "unknown preset",
+			config:  baseConfig + "preset: standalone\n",
+			opts:    &Options{},
+			wantErr: `unknown preset standalone`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.config), 0o600))
+			tt.opts.ConfigFile = path
+
+			got, err := NewConfig(tt.opts)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.validate(t, got)
+		})
+	}
+}
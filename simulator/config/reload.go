@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ErrNotReloadable is returned when the fields which can't be reloaded are changed in the config file.
+var ErrNotReloadable = errors.New("the changed fields can't be reloaded, restart the simulator to change them")
+
+// The fields of Config which can be changed without restarting the simulator.
+const (
+	FieldLogVerbosity = "LogVerbosity"
+	FieldRateLimit    = "RateLimit"
+	FieldSyncer       = "Syncer"
+	FieldRecorder     = "Recorder"
+)
+
+// reloadableFields are the fields of Config which Reload accepts the changes of.
+var reloadableFields = sets.New(FieldLogVerbosity, FieldRateLimit, FieldSyncer, FieldRecorder)
+
+// Reload reads the config file again with the options which current is created with,
+// and returns the new Config with the names of the fields changed from current.
+// If any field which can't be reloaded is changed, it returns an error wrapping ErrNotReloadable
+// listing those fields.
+func Reload(current *Config) (*Config, []string, error) {
+	opts := current.options
+	if opts == nil {
+		opts = &Options{}
+	}
+	next, err := NewConfig(opts)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("load config: %w", err)
+	}
+
+	changed, rejected := diffFields(current, next)
+	if len(rejected) > 0 {
+		return nil, nil, xerrors.Errorf("%s: %w", strings.Join(rejected, ", "), ErrNotReloadable)
+	}
+	return next, changed, nil
+}
+
+// diffFields returns the names of the exported fields differing between a and b,
+// divided into the reloadable ones and the others.
+func diffFields(a, b *Config) (reloadable, others []string) {
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	for i := 0; i < va.NumField(); i++ {
+		if !va.Type().Field(i).IsExported() || reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			continue
+		}
+		name := va.Type().Field(i).Name
+		if reloadableFields.Has(name) {
+			reloadable = append(reloadable, name)
+		} else {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(reloadable)
+	sort.Strings(others)
+	return reloadable, others
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReload(t *testing.T) {
+	t.Parallel()
+	const baseConfig = `apiVersion: simulator.config.sigs.k8s.io/v1alpha1
+kind: SimulatorConfiguration
+port: 1212
+etcdURL: http://file:2379
+kubeApiServerUrl: http://file:3131
+syncer:
+  labelSelector: env=staging
+`
+	tests := []struct {
+		name        string
+		config      string
+		wantChanged []string
+		wantErr     bool
+		wantErrIs   error
+		wantErrMsg  string
+		validate    func(t *testing.T, cfg *Config)
+	}{
+		{
+			name:        "the syncer filter is reloaded",
+			config:      strings.Replace(baseConfig, "env=staging", "env=production", 1),
+			wantChanged: []string{FieldSyncer},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.Equal(t, "env=production", cfg.Syncer.LabelSelector)
+				assert.Equal(t, 1212, cfg.Ports.API.Port)
+			},
+		},
+		{
+			name:        "the log verbosity and the rate limits are reloaded",
+			config:      baseConfig + "logVerbosity: 4\nrateLimit:\n  burst: 50\n",
+			wantChanged: []string{FieldLogVerbosity, FieldRateLimit},
+			validate: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				assert.Equal(t, 4, cfg.LogVerbosity)
+				assert.Equal(t, int32(50), cfg.RateLimit.Default.Burst)
+			},
+		},
+		{
+			name:   "nothing is changed",
+			config: baseConfig,
+		},
+		{
+			name:       "the port and the etcd URL can't be reloaded",
+			config:     strings.Replace(strings.Replace(baseConfig, "1212", "1313", 1), "file:2379", "other:2379", 1) + "logVerbosity: 4\n",
+			wantErr:    true,
+			wantErrIs:  ErrNotReloadable,
+			wantErrMsg: "EtcdURL, Ports",
+		},
+		{
+			name:    "invalid config file",
+			config:  baseConfig + "prot: 1313\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(baseConfig), 0o600))
+			current, err := NewConfig(&Options{ConfigFile: path})
+			require.NoError(t, err)
+
+			require.NoError(t, os.WriteFile(path, []byte(tt.config), 0o600))
+			got, changed, err := Reload(current)
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
+				assert.Contains(t, err.Error(), tt.wantErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantChanged, changed)
+			if tt.validate != nil {
+				tt.validate(t, got)
+			}
+		})
+	}
+}
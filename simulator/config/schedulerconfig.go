@@ -0,0 +1,128 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// schedulerCfgFetchTimeout is the timeout to fetch the scheduler configuration from the URL or the ConfigMap.
+	schedulerCfgFetchTimeout = 30 * time.Second
+	// maxSchedulerCfgSize is the maximum size of the scheduler configuration fetched from the URL.
+	maxSchedulerCfgSize = 10 << 20
+)
+
+// The schemes of kubeSchedulerConfigPath other than the local file.
+const (
+	schedulerCfgSchemeHTTP      = "http"
+	schedulerCfgSchemeHTTPS     = "https"
+	schedulerCfgSchemeConfigMap = "configmap"
+)
+
+// isRemoteSchedulerCfg returns true if the scheduler configuration at source isn't a local file.
+func isRemoteSchedulerCfg(source string) bool {
+	u, err := url.Parse(source)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case schedulerCfgSchemeHTTP, schedulerCfgSchemeHTTPS, schedulerCfgSchemeConfigMap:
+		return true
+	}
+	return false
+}
+
+// readSchedulerCfgSource reads the scheduler configuration from source, which is one of
+// - the path to the local file,
+// - the http:// or https:// URL,
+// - or configmap://namespace/name/key, which is the key of the ConfigMap in the external cluster.
+//
+// externalClient is the client of the external cluster, which is nil if it isn't configured.
+func readSchedulerCfgSource(ctx context.Context, source string, externalClient clientset.Interface) ([]byte, error) {
+	if !isRemoteSchedulerCfg(source) {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, xerrors.Errorf("read scheduler config file: %w", err)
+		}
+		return data, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, schedulerCfgFetchTimeout)
+	defer cancel()
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, xerrors.Errorf("parse %q: %w", source, err)
+	}
+	var data []byte
+	if u.Scheme == schedulerCfgSchemeConfigMap {
+		data, err = readSchedulerCfgFromConfigMap(ctx, u, externalClient)
+	} else {
+		data, err = fetchSchedulerCfg(ctx, source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	klog.InfoS("Fetched the scheduler configuration", "source", source, "size", len(data), "sha256", fmt.Sprintf("%x", sha256.Sum256(data)))
+	return data, nil
+}
+
+// fetchSchedulerCfg fetches the scheduler configuration from rawURL.
+func fetchSchedulerCfg(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create the request to %s: %w", rawURL, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("fetch scheduler config from %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("fetch scheduler config from %s: unexpected status %s", rawURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSchedulerCfgSize+1))
+	if err != nil {
+		return nil, xerrors.Errorf("read scheduler config from %s: %w", rawURL, err)
+	}
+	if len(data) > maxSchedulerCfgSize {
+		return nil, xerrors.Errorf("scheduler config from %s exceeds %d bytes", rawURL, maxSchedulerCfgSize)
+	}
+	return data, nil
+}
+
+// readSchedulerCfgFromConfigMap reads the scheduler configuration from the ConfigMap referred by u,
+// i.e., configmap://namespace/name/key, in the external cluster.
+func readSchedulerCfgFromConfigMap(ctx context.Context, u *url.URL, externalClient clientset.Interface) ([]byte, error) {
+	namespace := u.Host
+	nameAndKey := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	if namespace == "" || len(nameAndKey) != 2 || nameAndKey[0] == "" || nameAndKey[1] == "" {
+		return nil, xerrors.Errorf("%q must be configmap://namespace/name/key", u.String())
+	}
+	if externalClient == nil {
+		return nil, xerrors.Errorf("%q requires the external cluster, configure kubeConfig or externalInCluster", u.String())
+	}
+
+	name, key := nameAndKey[0], nameAndKey[1]
+	cm, err := externalClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, xerrors.Errorf("get ConfigMap %s/%s: %w", namespace, name, err)
+	}
+	data, ok := cm.Data[key]
+	if !ok {
+		return nil, xerrors.Errorf("ConfigMap %s/%s doesn't have the key %q", namespace, name, key)
+	}
+	return []byte(data), nil
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testSchedulerCfg = `apiVersion: kubescheduler.config.k8s.io/v1
+kind: KubeSchedulerConfiguration
+profiles:
+- schedulerName: from-source
+`
+
+// newSchedulerCfgServer starts the server serving testSchedulerCfg at /scheduler.yaml.
+func newSchedulerCfgServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/scheduler.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(testSchedulerCfg))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func Test_readSchedulerCfgSource(t *testing.T) {
+	t.Parallel()
+	server := newSchedulerCfgServer(t)
+	path := filepath.Join(t.TempDir(), "scheduler.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testSchedulerCfg), 0o600))
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "scheduler-config"},
+		Data:       map[string]string{"scheduler.yaml": testSchedulerCfg},
+	})
+
+	tests := []struct {
+		name    string
+		source  string
+		client  clientset.Interface
+		wantErr string
+	}{
+		{
+			name:   "local file",
+			source: path,
+		},
+		{
+			name:   "URL",
+			source: server.URL + "/scheduler.yaml",
+		},
+		{
+			name:    "URL not found",
+			source:  server.URL + "/missing.yaml",
+			wantErr: "unexpected status 404",
+		},
+		{
+			name:   "ConfigMap",
+			source: "configmap://kube-system/scheduler-config/scheduler.yaml",
+			client: client,
+		},
+		{
+			name:    "ConfigMap without the key",
+			source:  "configmap://kube-system/scheduler-config/config.yaml",
+			client:  client,
+			wantErr: `doesn't have the key "config.yaml"`,
+		},
+		{
+			name:    "ConfigMap not found",
+			source:  "configmap://default/scheduler-config/scheduler.yaml",
+			client:  client,
+			wantErr: "get ConfigMap default/scheduler-config",
+		},
+		{
+			name:    "ConfigMap without the key in the reference",
+			source:  "configmap://kube-system/scheduler-config",
+			client:  client,
+			wantErr: "must be configmap://namespace/name/key",
+		},
+		{
+			name:    "ConfigMap without the external cluster",
+			source:  "configmap://kube-system/scheduler-config/scheduler.yaml",
+			wantErr: "requires the external cluster",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			data, err := readSchedulerCfgSource(context.Background(), tt.source, tt.client)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			// The config from any source is decoded in the same way.
+			cfg, err := DecodeSchedulerCfg(data)
+			require.NoError(t, err)
+			require.Len(t, cfg.Profiles, 1)
+			assert.Equal(t, "from-source", *cfg.Profiles[0].SchedulerName)
+		})
+	}
+}
+
+func TestGetSchedulerCfg_URL(t *testing.T) {
+	t.Parallel()
+	server := newSchedulerCfgServer(t)
+
+	cfg, err := GetSchedulerCfg(server.URL+"/scheduler.yaml", nil)
+	require.NoError(t, err)
+	require.Len(t, cfg.Profiles, 1)
+	assert.Equal(t, "from-source", *cfg.Profiles[0].SchedulerName)
+
+	_, err = GetSchedulerCfg(server.URL+"/missing.yaml", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// The ports used when they're omitted in the URLs of kube-apiserver and etcd.
+const (
+	defaultKubeAPIServerURLPort = 3131
+	defaultEtcdURLPort          = 2379
+)
+
+// reachabilityCheckTimeout is the timeout to connect to each server in checkReachability.
+const reachabilityCheckTimeout = 5 * time.Second
+
+// normalizeServerURL validates raw as the URL of an HTTP server, and normalizes it
+// so that the clients get the same URL however it's written:
+// - the spaces around it and the trailing slashes are removed.
+// - defaultPort is added if the port is omitted.
+//
+// The scheme must be http or https, and the URL must not have the path, the query, the fragment or the user info,
+// which the clients of the simulator don't support.
+func normalizeServerURL(raw string, defaultPort int) (string, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return "", xerrors.New("the URL is empty")
+	}
+	if !strings.Contains(s, "://") {
+		// url.Parse takes "localhost:2379" as the scheme "localhost", so it's checked first.
+		return "", xerrors.Errorf("%q has no scheme, add http:// or https://, e.g., http://%s", raw, s)
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", xerrors.Errorf("parse %q: %w", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", xerrors.Errorf("%q has the scheme %q, it must be http or https", raw, u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return "", xerrors.Errorf("%q has no host", raw)
+	}
+	if u.User != nil {
+		return "", xerrors.Errorf("%q must not have the user info", raw)
+	}
+	if strings.TrimRight(u.Path, "/") != "" || u.RawQuery != "" || u.Fragment != "" {
+		return "", xerrors.Errorf("%q must not have the path, the query or the fragment", raw)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = strconv.Itoa(defaultPort)
+	} else if p, err := strconv.Atoi(port); err != nil || p < 1 || p > maxPort {
+		return "", xerrors.Errorf("%q has the port out of range", raw)
+	}
+	return u.Scheme + "://" + net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// checkReachability connects to the host and the port of the URL with TCP,
+// so that the wrong URL is reported right after the simulator is started.
+// name is the name of the field of the URL, which is shown in the error.
+func checkReachability(ctx context.Context, name, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return xerrors.Errorf("parse %s %q: %w", name, rawURL, err)
+	}
+	dialer := &net.Dialer{Timeout: reachabilityCheckTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return xerrors.Errorf("%s %s isn't reachable, check the host and the port are correct from where the simulator is running, e.g., the DNS name of the service may resolve differently inside the container: %w", name, rawURL, err)
+	}
+	return conn.Close()
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"time"
+
+	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config/v1alpha1"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/startup"
+)
+
+// The defaults of the wait for etcd and kube-apiserver when the simulator is started.
+const (
+	defaultStartupTimeout             = 2 * time.Minute
+	defaultStartupInitialPollInterval = time.Second
+	defaultStartupMaxPollInterval     = 10 * time.Second
+)
+
+// convertStartupConfiguration converts and validates the startup configuration in the config file.
+func convertStartupConfiguration(cfg *v1alpha1.StartupConfiguration) (startup.Options, error) {
+	opts := startup.Options{
+		Timeout:         defaultStartupTimeout,
+		InitialInterval: defaultStartupInitialPollInterval,
+		MaxInterval:     defaultStartupMaxPollInterval,
+	}
+	if cfg == nil {
+		return opts, nil
+	}
+	for _, d := range []struct {
+		name  string
+		value *metav1.Duration
+		dst   *time.Duration
+	}{
+		{name: "timeout", value: cfg.Timeout, dst: &opts.Timeout},
+		{name: "initialPollInterval", value: cfg.InitialPollInterval, dst: &opts.InitialInterval},
+		{name: "maxPollInterval", value: cfg.MaxPollInterval, dst: &opts.MaxInterval},
+	} {
+		if d.value == nil {
+			continue
+		}
+		if d.value.Duration <= 0 {
+			return startup.Options{}, xerrors.Errorf("%s %s must be positive", d.name, d.value.Duration)
+		}
+		*d.dst = d.value.Duration
+	}
+	if opts.InitialInterval > opts.MaxInterval {
+		return startup.Options{}, xerrors.Errorf("initialPollInterval %s must not be longer than maxPollInterval %s", opts.InitialInterval, opts.MaxInterval)
+	}
+	return opts, nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config/v1alpha1"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/startup"
+)
+
+func Test_convertStartupConfiguration(t *testing.T) {
+	t.Parallel()
+	defaults := startup.Options{Timeout: defaultStartupTimeout, InitialInterval: defaultStartupInitialPollInterval, MaxInterval: defaultStartupMaxPollInterval}
+	tests := []struct {
+		name    string
+		cfg     *v1alpha1.StartupConfiguration
+		want    startup.Options
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			want: defaults,
+		},
+		{
+			name: "all the fields",
+			cfg: &v1alpha1.StartupConfiguration{
+				Timeout:             &metav1.Duration{Duration: 10 * time.Minute},
+				InitialPollInterval: &metav1.Duration{Duration: 500 * time.Millisecond},
+				MaxPollInterval:     &metav1.Duration{Duration: 30 * time.Second},
+			},
+			want: startup.Options{Timeout: 10 * time.Minute, InitialInterval: 500 * time.Millisecond, MaxInterval: 30 * time.Second},
+		},
+		{
+			name: "only the timeout",
+			cfg:  &v1alpha1.StartupConfiguration{Timeout: &metav1.Duration{Duration: 5 * time.Minute}},
+			want: startup.Options{Timeout: 5 * time.Minute, InitialInterval: defaultStartupInitialPollInterval, MaxInterval: defaultStartupMaxPollInterval},
+		},
+		{
+			name:    "zero timeout",
+			cfg:     &v1alpha1.StartupConfiguration{Timeout: &metav1.Duration{}},
+			wantErr: true,
+		},
+		{
+			name:    "negative initialPollInterval",
+			cfg:     &v1alpha1.StartupConfiguration{InitialPollInterval: &metav1.Duration{Duration: -time.Second}},
+			wantErr: true,
+		},
+		{
+			name:    "initialPollInterval longer than maxPollInterval",
+			cfg:     &v1alpha1.StartupConfiguration{InitialPollInterval: &metav1.Duration{Duration: time.Minute}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := convertStartupConfiguration(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -19,5 +19,5 @@ limitations under the License.
 // +k8s:defaulter-gen=TypeMeta
 
 // Package v1alpha1 is the v1alpha1 version of the kube-scheduler-simulator's config API
-// +groupName=kube-scheduler-simulator-config
+// +groupName=simulator.config.sigs.k8s.io
 package v1alpha1 // Package v1alpha1 import "sigs.k8s.io/kube-scheduler-simulator/simulator/config/v1alpha1"
@@ -29,13 +29,18 @@ var (
 
 // GroupName is the group name use in this package.
 const (
-	GroupName    = "kube-scheduler-simulator-config"
+	GroupName    = "simulator.config.sigs.k8s.io"
 	GroupVersion = "v1alpha1"
+	// LegacyGroupName is the group name used before GroupName. It's still accepted in the config file.
+	LegacyGroupName = "kube-scheduler-simulator-config"
 )
 
 // SchemeGroupVersion is group version used to register these objects.
 var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: GroupVersion}
 
+// LegacySchemeGroupVersion is the group version with LegacyGroupName.
+var LegacySchemeGroupVersion = schema.GroupVersion{Group: LegacyGroupName, Version: GroupVersion}
+
 // Kind takes an unqualified kind and returns a Group qualified GroupKind.
 func Kind(kind string) schema.GroupKind {
 	return SchemeGroupVersion.WithKind(kind).GroupKind()
@@ -23,26 +23,85 @@ import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 type SimulatorConfiguration struct {
 	metav1.TypeMeta `json:",inline"`
 
+	// This is the preset of the fields for the common modes of
+	// the simulator, "sandbox", "import", "sync" or "replay".
+	// The fields set in this file take precedence over the preset.
+	Preset string `json:"preset,omitempty"`
+
 	// This is the port number on which kube-scheduler-simulator
-	// server is started.
+	// server is started. ports.api takes precedence over it.
 	Port int `json:"port,omitempty"`
 
+	// This configures the addresses which the servers of the
+	// simulator listen on.
+	Ports *PortsConfiguration `json:"ports,omitempty"`
+
 	// This is the URL for etcd.
 	EtcdURL string `json:"etcdURL,omitempty"`
 
+	// This configures the connection to etcd, e.g., TLS and
+	// the authentication, for the secured etcd.
+	Etcd *EtcdConfiguration `json:"etcd,omitempty"`
+
+	// This configures how long and how often the simulator waits
+	// for etcd and kube-apiserver to be ready when it's started.
+	Startup *StartupConfiguration `json:"startup,omitempty"`
+
 	// This URL represents the URL once web UI is started.
 	// The simulator and internal kube-apiserver set the allowed
 	// origin for CorsAllowedOriginList
 	CorsAllowedOriginList []string `json:"corsAllowedOriginList,omitempty"`
 
+	// These are the methods which the allowed origins can use
+	// for the simulator's API. Its default value is
+	// GET, HEAD, PUT, PATCH, POST and DELETE.
+	CorsAllowedMethods []string `json:"corsAllowedMethods,omitempty"`
+
+	// This indicates whether the allowed origins can send
+	// the requests with the credentials to the simulator's API.
+	// Its default value is true since the web UI sends them.
+	CorsAllowCredentials *bool `json:"corsAllowCredentials,omitempty"`
+
+	// This is the timeout of the requests to the simulator's API,
+	// except the ones watching resources. The requests exceeding it
+	// are cancelled and responded with 504. Its default value is 60s,
+	// and 0 disables the timeout.
+	RequestTimeout *metav1.Duration `json:"requestTimeout,omitempty"`
+
 	// This is for the beta feature "Importing cluster's resources".
 	// This variable is used to find Kubeconfig required to access your
 	// cluster for importing resources to scheduler simulator.
 	KubeConfig string `json:"kubeConfig,omitempty"`
 
+	// This is the context in kubeConfig used to access your cluster.
+	// The current context is used if it's empty.
+	ExternalKubeContext string `json:"externalKubeContext,omitempty"`
+
+	// This variable indicates whether the simulator accesses the
+	// cluster it's running in with the service account, instead of
+	// kubeConfig. It cannot be used with kubeConfig or externalKubeContext.
+	ExternalInCluster bool `json:"externalInCluster,omitempty"`
+
+	// This is the QPS of the client accessing your cluster.
+	// If not set, the default of client-go, 5, is used not to
+	// put much load on your cluster.
+	ExternalKubeClientQPS float32 `json:"externalKubeClientQPS,omitempty"`
+
+	// This is the burst of the client accessing your cluster.
+	// If not set, the default of client-go, 10, is used.
+	ExternalKubeClientBurst int `json:"externalKubeClientBurst,omitempty"`
+
 	// This is the URL for kube-apiserver.
 	KubeAPIServerURL string `json:"kubeApiServerUrl,omitempty"`
 
+	// This is the QPS of the clients accessing kube-apiserver
+	// in the simulator. Its default value is 100.
+	KubeClientQPS float32 `json:"kubeClientQPS,omitempty"`
+
+	// This is the burst of the clients accessing kube-apiserver
+	// in the simulator. Its default value is 200.
+	KubeClientBurst int `json:"kubeClientBurst,omitempty"`
+
 	// This is the host of kube-apiserver which the simulator
 	// starts internally. Its default value is 127.0.0.1.
 	KubeAPIHost string `json:"kubeApiHost,omitempty"`
@@ -54,27 +113,343 @@ type SimulatorConfiguration struct {
 	// If passed, the simulator will start the scheduler
 	// with that configuration. Or, if you use web UI,
 	// you can change the configuration from the web UI as well.
+	// It can be an http(s):// URL, or configmap://namespace/name/key
+	// in the cluster of kubeConfig or externalInCluster.
 	KubeSchedulerConfigPath string `json:"kubeSchedulerConfigPath,omitempty"`
 
+	// The feature gates of the scheduler, e.g., DynamicResourceAllocation: true.
+	// They're set to the debuggable scheduler when it's (re)started.
+	// Note that they aren't set to kube-apiserver, which needs its own feature gates
+	// to serve the API of some features.
+	SchedulerFeatureGates map[string]bool `json:"schedulerFeatureGates,omitempty"`
+
+	// The seed of the random numbers of the scheduler, e.g., to break the ties of the scores.
+	// The scheduler is deterministic with it; the same Pods are scheduled to the same Nodes
+	// every time it's (re)started. It's random if it's nil.
+	SchedulerRandomSeed *int64 `json:"schedulerRandomSeed,omitempty"`
+
 	// This variable indicates whether the simulator will
 	// import resources from an user cluster's or not.
 	// Note, this is still a beta feature.
 	ExternalImportEnabled bool `json:"externalImportEnabled,omitempty"`
 
+	// This variable is the path to a directory or a tarball of manifests
+	// which the simulator imports resources from once when it's started,
+	// instead of an user cluster.
+	// It cannot be used with externalImportEnabled.
+	ImportManifestsPath string `json:"importManifestsPath,omitempty"`
+
 	ResourceImportLabelSelector metav1.LabelSelector `json:"resourceImportLabelSelector,omitempty"`
 
+	// This variable indicates whether the simulator will
+	// also import the scheduler configuration from an user cluster
+	// when externalImportEnabled is true.
+	// The configuration is read from the ConfigMap kube-system/kube-scheduler-config.
+	ImportSchedulerConfigEnabled bool `json:"importSchedulerConfigEnabled,omitempty"`
+
+	// This variable is the maximum number of resources
+	// the simulator imports from an user cluster.
+	// The import is aborted if the cluster has more resources than this,
+	// unless externalImportForce is true.
+	// If zero, 5000 is used.
+	ImportResourceCountThreshold int `json:"importResourceCountThreshold,omitempty"`
+
+	// This variable indicates whether the simulator will import resources
+	// even if the number of them exceeds importResourceCountThreshold.
+	ExternalImportForce bool `json:"externalImportForce,omitempty"`
+
+	// This variable indicates whether the simulator will set
+	// the Ready condition of nodes imported from an user cluster to True.
+	ImportForceNodeReady bool `json:"importForceNodeReady,omitempty"`
+
 	// This variable indicates whether the simulator will
 	// sync resources from an user cluster's or not.
 	ResourceSyncEnabled bool `json:"resourceSyncEnabled,omitempty"`
 
+	// This variable allows externalImportEnabled and
+	// resourceSyncEnabled to be true simultaneously. The resources
+	// are imported once, and then synced.
+	AllowExternalImportWithSync bool `json:"allowExternalImportWithSync,omitempty"`
+
 	// This variable indicates whether the simulator will
 	// replay events recorded in a file or not.
 	ReplayerEnabled bool `json:"replayEnabled,omitempty"`
 
+	// This variable indicates whether the simulator will record
+	// the events of the resources in the simulator to the file
+	// specified by recordFilePath from when it's started.
+	RecordEnabled bool `json:"recordEnabled,omitempty"`
+
 	// The path to a file where the record files are stored.
 	RecordFilePath string `json:"recordFilePath,omitempty"`
 
+	// The path to a directory where the snapshots of etcd are saved
+	// through the API, and restored from. The snapshot API is
+	// disabled if it's empty.
+	EtcdSnapshotDir string `json:"etcdSnapshotDir,omitempty"`
+
+	// This is the interval to send the heartbeat to the clients
+	// watching resources, so that the idle connection isn't killed
+	// by load balancers or browsers. Its default value is 30s.
+	WatcherHeartbeatInterval metav1.Duration `json:"watcherHeartbeatInterval,omitempty"`
+
+	// This is the number of events which can be pending for each
+	// client watching resources. Its default value is 1000.
+	WatcherQueueSize int `json:"watcherQueueSize,omitempty"`
+
+	// This decides what to do when the events pending for a client
+	// exceed watcherQueueSize. "Coalesce" drops the intermediate
+	// updates of the same resource, and "Disconnect" disconnects the
+	// client. Its default value is "Coalesce".
+	WatcherOverflowPolicy string `json:"watcherOverflowPolicy,omitempty"`
+
+	// This is the static bearer token which the clients watching
+	// resources must send in the Authorization header. The
+	// connections aren't authenticated if it's empty.
+	//
+	// Deprecated: use Auth.Tokens instead. It's ignored if Auth is set.
+	WatcherBearerToken string `json:"watcherBearerToken,omitempty"`
+
 	// This variable indicates whether an external scheduler
 	// is used.
 	ExternalSchedulerEnabled bool `json:"externalSchedulerEnabled,omitempty"`
+
+	// This is the verbosity of the logs of the simulator, i.e., the
+	// info logs with the level up to it are written. It can be
+	// changed without restarting the simulator by reloading the
+	// config file.
+	LogVerbosity int `json:"logVerbosity,omitempty"`
+
+	// This is the format of the logs of the simulator, "text" or
+	// "json". If not set, "text" is used.
+	LogFormat string `json:"logFormat,omitempty"`
+
+	// This overrides logVerbosity for the components, i.e.,
+	// "syncer", "importer", "replayer" and "recorder".
+	// The components not listed here use logVerbosity.
+	LogComponentVerbosity map[string]int `json:"logComponentVerbosity,omitempty"`
+
+	// This configures the authentication of the simulator's API.
+	// The API isn't authenticated if it's not set.
+	Auth *AuthConfiguration `json:"auth,omitempty"`
+
+	// This configures the rate limits of the requests to the
+	// simulator's API for each client, identified by the bearer
+	// token or by the IP address. By default, the mutating requests
+	// are limited to 10 per second with the burst of 20, and the
+	// other requests are unlimited.
+	RateLimit *RateLimitConfiguration `json:"rateLimit,omitempty"`
+
+	// This configures the syncer which keeps syncing resources
+	// from an user cluster when resourceSyncEnabled is true.
+	Syncer *SyncerConfiguration `json:"syncer,omitempty"`
+
+	// This configures the recorder which records the events of
+	// resources in an user cluster to recordFilePath.
+	Recorder *RecorderConfiguration `json:"recorder,omitempty"`
+
+	// This configures the pod lifecycle simulator, which acts as
+	// the kubelets of the nodes in the simulator. It's disabled by
+	// default.
+	PodLifecycle *PodLifecycleConfiguration `json:"podLifecycle,omitempty"`
+}
+
+// GroupVersionResource identifies a resource, e.g., the version v1 and the resource pods.
+type GroupVersionResource struct {
+	// The group of the resource, which is empty for the core group.
+	Group string `json:"group,omitempty"`
+
+	Version string `json:"version"`
+
+	Resource string `json:"resource"`
+}
+
+// SyncerConfiguration configures the syncer.
+type SyncerConfiguration struct {
+	// The resources synced from an user cluster, in the order of
+	// their dependencies. Its default value is namespaces,
+	// priorityclasses, storageclasses, persistentvolumeclaims,
+	// nodes, persistentvolumes and pods.
+	Resources []GroupVersionResource `json:"resources,omitempty"`
+
+	// The label selector of the resources synced, e.g., env=staging.
+	// It's applied to all the resources including the namespaces.
+	// All the resources are synced if it's empty.
+	LabelSelector string `json:"labelSelector,omitempty"`
+}
+
+// RecorderConfiguration configures the recorder.
+type RecorderConfiguration struct {
+	// The resources recorded from an user cluster. Its default
+	// value is the same as the one of the syncer.
+	Resources []GroupVersionResource `json:"resources,omitempty"`
+
+	// The interval to flush the recorded events to the file.
+	// Its default value is 5s.
+	FlushInterval *metav1.Duration `json:"flushInterval,omitempty"`
+}
+
+// PodLifecycleConfiguration configures the pod lifecycle simulator.
+// It makes the Pods bound to the nodes Running, and Succeeded after
+// the duration in their simulator/run-duration annotation, e.g., 5m.
+type PodLifecycleConfiguration struct {
+	// This enables the pod lifecycle simulator.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The delay before the Pods bound to the nodes become Running.
+	// Its default value is 1s.
+	RunningDelay *metav1.Duration `json:"runningDelay,omitempty"`
+
+	// This deletes the Pods after they succeed, so that they're
+	// removed from the simulator as well as from the nodes.
+	DeleteSucceededPods bool `json:"deleteSucceededPods,omitempty"`
+}
+
+// RateLimitConfiguration configures the token buckets limiting the requests.
+// The requests exceeding the limits are rejected with 429.
+type RateLimitConfiguration struct {
+	// The rate of the mutating requests allowed for each client.
+	// 0 makes them unlimited.
+	RequestsPerSecond *int32 `json:"requestsPerSecond,omitempty"`
+
+	// The number of the mutating requests each client can send at
+	// once. Its default value is requestsPerSecond.
+	Burst int32 `json:"burst,omitempty"`
+
+	// The limits overriding the ones above for the routes.
+	Routes []RouteRateLimit `json:"routes,omitempty"`
+}
+
+// RouteRateLimit is the rate limit of a route.
+type RouteRateLimit struct {
+	// The method of the route, e.g., POST.
+	Method string `json:"method"`
+
+	// The path of the route, e.g., /api/v1/pods/bulk or /api/v1/jobs/:id.
+	Path string `json:"path"`
+
+	// The rate of the requests allowed for each client.
+	// 0 makes them unlimited.
+	RequestsPerSecond int32 `json:"requestsPerSecond"`
+
+	// The number of the requests each client can send at once.
+	// Its default value is requestsPerSecond.
+	Burst int32 `json:"burst,omitempty"`
+}
+
+// PortsConfiguration configures the addresses which the servers of the simulator listen on.
+// No two servers can listen on the same port of the same address.
+type PortsConfiguration struct {
+	// The address of the simulator's API.
+	API *ListenerConfiguration `json:"api,omitempty"`
+
+	// The address serving /metrics. If its port is 0 or not set,
+	// /metrics is served on the address of the simulator's API.
+	Metrics *ListenerConfiguration `json:"metrics,omitempty"`
+
+	// The address serving the proxy for the extenders, i.e.,
+	// /api/v1/extender/*. If its port is 0 or not set, the proxy is
+	// served on the address of the simulator's API. The debuggable
+	// scheduler embedded in the simulator listens on it as well.
+	ExtenderProxy *ListenerConfiguration `json:"extenderProxy,omitempty"`
+
+	// The address serving the gRPC API of the simulator.
+	// The gRPC API is disabled if its port is 0 or not set.
+	GRPC *ListenerConfiguration `json:"grpc,omitempty"`
+}
+
+// ListenerConfiguration is an address which a server listens on.
+type ListenerConfiguration struct {
+	// The IP address of the interface to bind.
+	// All the interfaces are bound if it's empty.
+	Address string `json:"address,omitempty"`
+
+	Port int `json:"port,omitempty"`
+}
+
+// StartupConfiguration configures the wait for etcd and kube-apiserver
+// when the simulator is started. The simulator probes etcd first,
+// and then kube-apiserver, retrying with the exponential backoff.
+type StartupConfiguration struct {
+	// The time to wait for both of them to be ready.
+	// Its default value is 2m.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// The interval after the first failed probe, which is doubled
+	// after each failed probe up to maxPollInterval.
+	// Its default value is 1s.
+	InitialPollInterval *metav1.Duration `json:"initialPollInterval,omitempty"`
+
+	// The maximum interval between the probes. Its default value is 10s.
+	MaxPollInterval *metav1.Duration `json:"maxPollInterval,omitempty"`
+}
+
+// EtcdConfiguration configures the connection to etcd.
+type EtcdConfiguration struct {
+	// The path to the CA certificate to verify the server
+	// certificate of etcd. The system roots are used if it's empty.
+	CAFile string `json:"caFile,omitempty"`
+
+	// The paths to the client certificate and its key for the
+	// mutual TLS. Both or neither of them must be set.
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+
+	// The user name and the password for the authentication of
+	// etcd. Both or neither of them must be set.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// The timeout to establish the connection. Its default value is 2s.
+	DialTimeout *metav1.Duration `json:"dialTimeout,omitempty"`
+
+	// The maximum number of the retries of each request.
+	// The default of the etcd client is used if it's not set.
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+}
+
+// AuthConfiguration configures the authentication of the simulator's API.
+// The requests to /api/v1 must have a static token or an OIDC ID token
+// in the Authorization header as `Bearer <token>`.
+type AuthConfiguration struct {
+	// The static bearer tokens accepted.
+	Tokens []StaticToken `json:"tokens,omitempty"`
+
+	// This enables the OIDC ID tokens if set.
+	OIDC *OIDCConfiguration `json:"oidc,omitempty"`
+
+	// These indicate whether /healthz and /readyz, and /metrics
+	// are authenticated as well. They aren't by default so that
+	// the probes and the metrics scrapers work without tokens.
+	AuthenticateHealthz bool `json:"authenticateHealthz,omitempty"`
+	AuthenticateMetrics bool `json:"authenticateMetrics,omitempty"`
+}
+
+// StaticToken is a bearer token accepted by the simulator.
+type StaticToken struct {
+	// The name of the client, which is shown in the logs.
+	Name string `json:"name"`
+
+	Token string `json:"token"`
+
+	// The role of the client, "read-only" or "read-write".
+	// "read-only" clients can only send GET requests.
+	Role string `json:"role"`
+}
+
+// OIDCConfiguration configures the validation of the OIDC ID tokens.
+type OIDCConfiguration struct {
+	// The URL of the issuer. The keys are discovered from
+	// <issuerURL>/.well-known/openid-configuration.
+	IssuerURL string `json:"issuerURL"`
+
+	// This must be in the aud claim of the ID tokens.
+	Audience string `json:"audience"`
+
+	// The claim having the groups of the user. Its default value is "groups".
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+
+	// The users in these groups are given the "read-write" role,
+	// and the other users are given the "read-only" role.
+	ReadWriteGroups []string `json:"readWriteGroups,omitempty"`
 }
@@ -22,18 +22,325 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthConfiguration) DeepCopyInto(out *AuthConfiguration) {
+	*out = *in
+	if in.Tokens != nil {
+		in, out := &in.Tokens, &out.Tokens
+		*out = make([]StaticToken, len(*in))
+		copy(*out, *in)
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthConfiguration.
+func (in *AuthConfiguration) DeepCopy() *AuthConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdConfiguration) DeepCopyInto(out *EtcdConfiguration) {
+	*out = *in
+	if in.DialTimeout != nil {
+		in, out := &in.DialTimeout, &out.DialTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdConfiguration.
+func (in *EtcdConfiguration) DeepCopy() *EtcdConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupVersionResource) DeepCopyInto(out *GroupVersionResource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupVersionResource.
+func (in *GroupVersionResource) DeepCopy() *GroupVersionResource {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupVersionResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListenerConfiguration) DeepCopyInto(out *ListenerConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListenerConfiguration.
+func (in *ListenerConfiguration) DeepCopy() *ListenerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ListenerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCConfiguration) DeepCopyInto(out *OIDCConfiguration) {
+	*out = *in
+	if in.ReadWriteGroups != nil {
+		in, out := &in.ReadWriteGroups, &out.ReadWriteGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCConfiguration.
+func (in *OIDCConfiguration) DeepCopy() *OIDCConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodLifecycleConfiguration) DeepCopyInto(out *PodLifecycleConfiguration) {
+	*out = *in
+	if in.RunningDelay != nil {
+		in, out := &in.RunningDelay, &out.RunningDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodLifecycleConfiguration.
+func (in *PodLifecycleConfiguration) DeepCopy() *PodLifecycleConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PodLifecycleConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortsConfiguration) DeepCopyInto(out *PortsConfiguration) {
+	*out = *in
+	if in.API != nil {
+		in, out := &in.API, &out.API
+		*out = new(ListenerConfiguration)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(ListenerConfiguration)
+		**out = **in
+	}
+	if in.ExtenderProxy != nil {
+		in, out := &in.ExtenderProxy, &out.ExtenderProxy
+		*out = new(ListenerConfiguration)
+		**out = **in
+	}
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(ListenerConfiguration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortsConfiguration.
+func (in *PortsConfiguration) DeepCopy() *PortsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PortsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitConfiguration) DeepCopyInto(out *RateLimitConfiguration) {
+	*out = *in
+	if in.RequestsPerSecond != nil {
+		in, out := &in.RequestsPerSecond, &out.RequestsPerSecond
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]RouteRateLimit, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitConfiguration.
+func (in *RateLimitConfiguration) DeepCopy() *RateLimitConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecorderConfiguration) DeepCopyInto(out *RecorderConfiguration) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]GroupVersionResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.FlushInterval != nil {
+		in, out := &in.FlushInterval, &out.FlushInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecorderConfiguration.
+func (in *RecorderConfiguration) DeepCopy() *RecorderConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RecorderConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteRateLimit) DeepCopyInto(out *RouteRateLimit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteRateLimit.
+func (in *RouteRateLimit) DeepCopy() *RouteRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SimulatorConfiguration) DeepCopyInto(out *SimulatorConfiguration) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = new(PortsConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Etcd != nil {
+		in, out := &in.Etcd, &out.Etcd
+		*out = new(EtcdConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(StartupConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CorsAllowedOriginList != nil {
 		in, out := &in.CorsAllowedOriginList, &out.CorsAllowedOriginList
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CorsAllowedMethods != nil {
+		in, out := &in.CorsAllowedMethods, &out.CorsAllowedMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CorsAllowCredentials != nil {
+		in, out := &in.CorsAllowCredentials, &out.CorsAllowCredentials
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RequestTimeout != nil {
+		in, out := &in.RequestTimeout, &out.RequestTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SchedulerFeatureGates != nil {
+		in, out := &in.SchedulerFeatureGates, &out.SchedulerFeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SchedulerRandomSeed != nil {
+		in, out := &in.SchedulerRandomSeed, &out.SchedulerRandomSeed
+		*out = new(int64)
+		**out = **in
+	}
+	in.ResourceImportLabelSelector.DeepCopyInto(&out.ResourceImportLabelSelector)
+	out.WatcherHeartbeatInterval = in.WatcherHeartbeatInterval
+	if in.LogComponentVerbosity != nil {
+		in, out := &in.LogComponentVerbosity, &out.LogComponentVerbosity
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(AuthConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimitConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Syncer != nil {
+		in, out := &in.Syncer, &out.Syncer
+		*out = new(SyncerConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Recorder != nil {
+		in, out := &in.Recorder, &out.Recorder
+		*out = new(RecorderConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodLifecycle != nil {
+		in, out := &in.PodLifecycle, &out.PodLifecycle
+		*out = new(PodLifecycleConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -54,3 +361,71 @@ func (in *SimulatorConfiguration) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StartupConfiguration) DeepCopyInto(out *StartupConfiguration) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.InitialPollInterval != nil {
+		in, out := &in.InitialPollInterval, &out.InitialPollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxPollInterval != nil {
+		in, out := &in.MaxPollInterval, &out.MaxPollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartupConfiguration.
+func (in *StartupConfiguration) DeepCopy() *StartupConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(StartupConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaticToken) DeepCopyInto(out *StaticToken) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticToken.
+func (in *StaticToken) DeepCopy() *StaticToken {
+	if in == nil {
+		return nil
+	}
+	out := new(StaticToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncerConfiguration) DeepCopyInto(out *SyncerConfiguration) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]GroupVersionResource, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncerConfiguration.
+func (in *SyncerConfiguration) DeepCopy() *SyncerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,89 @@
+// Package configreload reloads the config file of the simulator while it's running,
+// and applies the changes of the fields which can be reloaded to the components.
+package configreload
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"golang.org/x/xerrors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+)
+
+// ErrInvalidConfig is returned when the config file can't be loaded, e.g., it's malformed.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// ApplyFunc applies the field of cfg to a component.
+type ApplyFunc func(cfg *config.Config) error
+
+// LoadFunc reads the config again, and returns it with the names of the fields changed from current.
+// config.Reload is used by default.
+type LoadFunc func(current *config.Config) (*config.Config, []string, error)
+
+// Result is the result of the reload.
+type Result struct {
+	// Reloaded is the names of the fields changed and applied to the components.
+	Reloaded []string `json:"reloaded"`
+}
+
+// Service reloads the config and applies the changes with the ApplyFuncs registered for the fields.
+type Service struct {
+	load LoadFunc
+
+	mu      sync.Mutex
+	current *config.Config
+	// appliers are the functions registered for each field of config.Config.
+	appliers map[string][]ApplyFunc
+}
+
+// NewService initializes Service with the config which the simulator is started with.
+func NewService(current *config.Config, load LoadFunc) *Service {
+	if load == nil {
+		load = config.Reload
+	}
+	return &Service{load: load, current: current, appliers: map[string][]ApplyFunc{}}
+}
+
+// Register registers fn called when the field of config.Config, e.g., config.FieldSyncer, is changed.
+func (s *Service) Register(field string, fn ApplyFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.appliers[field] = append(s.appliers[field], fn)
+}
+
+// Reload reloads the config and applies the fields changed.
+// It returns an error wrapping config.ErrNotReloadable if the fields which can't be reloaded are changed,
+// or ErrInvalidConfig if the config file can't be loaded, and nothing is applied then.
+func (s *Service) Reload(_ context.Context) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next, changed, err := s.load(s.current)
+	if errors.Is(err, config.ErrNotReloadable) {
+		return nil, xerrors.Errorf("reload config: %w", err)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("reload config: %v: %w", err, ErrInvalidConfig)
+	}
+
+	var errs []error
+	for _, field := range changed {
+		for _, fn := range s.appliers[field] {
+			if err := fn(next); err != nil {
+				errs = append(errs, xerrors.Errorf("apply %s: %w", field, err))
+			}
+		}
+	}
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		// The current config is kept so that the failed fields are applied again with the next reload.
+		return nil, err
+	}
+
+	s.current = next
+	klog.InfoS("Reloaded the config", "fields", changed)
+	return &Result{Reloaded: append([]string{}, changed...)}, nil
+}
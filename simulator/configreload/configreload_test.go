@@ -0,0 +1,98 @@
+package configreload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/syncer"
+)
+
+func TestService_Reload(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		load        LoadFunc
+		applyErr    error
+		wantErrIs   error
+		wantResult  *Result
+		wantApplied []string
+	}{
+		{
+			name: "the fields changed are applied",
+			load: func(current *config.Config) (*config.Config, []string, error) {
+				next := *current
+				next.Syncer = syncer.Options{LabelSelector: "env=production"}
+				return &next, []string{config.FieldSyncer}, nil
+			},
+			wantResult:  &Result{Reloaded: []string{config.FieldSyncer}},
+			wantApplied: []string{"env=production"},
+		},
+		{
+			name: "nothing is changed",
+			load: func(current *config.Config) (*config.Config, []string, error) {
+				return current, nil, nil
+			},
+			wantResult: &Result{Reloaded: []string{}},
+		},
+		{
+			name: "the fields which can't be reloaded are changed",
+			load: func(_ *config.Config) (*config.Config, []string, error) {
+				return nil, nil, xerrors.Errorf("Ports: %w", config.ErrNotReloadable)
+			},
+			wantErrIs: config.ErrNotReloadable,
+		},
+		{
+			name: "the config file is invalid",
+			load: func(_ *config.Config) (*config.Config, []string, error) {
+				return nil, nil, xerrors.New("failed decoding simulator's config")
+			},
+			wantErrIs: ErrInvalidConfig,
+		},
+		{
+			name: "failed to apply",
+			load: func(current *config.Config) (*config.Config, []string, error) {
+				next := *current
+				next.Syncer = syncer.Options{LabelSelector: "env=production"}
+				return &next, []string{config.FieldSyncer}, nil
+			},
+			applyErr:    xerrors.New("failed"),
+			wantApplied: []string{"env=production"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			current := &config.Config{Ports: config.Ports{API: config.Listener{Port: 1212}}, Syncer: syncer.Options{LabelSelector: "env=staging"}}
+			s := NewService(current, tt.load)
+			var applied []string
+			s.Register(config.FieldSyncer, func(cfg *config.Config) error {
+				applied = append(applied, cfg.Syncer.LabelSelector)
+				return tt.applyErr
+			})
+			s.Register(config.FieldRateLimit, func(_ *config.Config) error {
+				t.Error("the field not changed must not be applied")
+				return nil
+			})
+
+			got, err := s.Reload(context.Background())
+			assert.Equal(t, tt.wantApplied, applied)
+			if tt.wantResult == nil {
+				require.Error(t, err)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
+				assert.Same(t, current, s.current, "the current config is kept")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantResult, got)
+			assert.Equal(t, 1212, s.current.Ports.API.Port)
+		})
+	}
+}
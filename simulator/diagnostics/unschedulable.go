@@ -0,0 +1,237 @@
+// Package diagnostics explains the state of the simulator, e.g., why the Pods are not scheduled.
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientset "k8s.io/client-go/kubernetes"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/annotation"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/resultstore"
+)
+
+// UnschedulableReport is the report of the Pods which are not scheduled.
+type UnschedulableReport struct {
+	Pods []UnschedulablePod `json:"pods"`
+}
+
+// UnschedulablePod explains why the Pod is not scheduled.
+type UnschedulablePod struct {
+	Namespace         string      `json:"namespace"`
+	Name              string      `json:"name"`
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+	// HasResults is false if the Pod doesn't have the scheduling results, e.g., it's not tried to be scheduled yet.
+	HasResults bool `json:"hasResults"`
+	// Message summarizes Reasons like the message of the FailedScheduling event,
+	// e.g., "0/5 nodes are available: 3 Insufficient cpu, 2 node(s) had untolerated taint {foo: bar}."
+	Message string `json:"message,omitempty"`
+	// Nodes is the number of the nodes evaluated by the Filter plugins.
+	Nodes int `json:"nodes"`
+	// FeasibleNodes is the number of the nodes which passed all the Filter plugins.
+	FeasibleNodes int `json:"feasibleNodes"`
+	// PreFilterFailures is the statuses of the PreFilter plugins which rejected the Pod, by the plugin name.
+	PreFilterFailures map[string]string `json:"preFilterFailures,omitempty"`
+	// Reasons is the reasons why the nodes are filtered out, from the most common one.
+	Reasons []ReasonBucket `json:"reasons,omitempty"`
+	Events  []Event        `json:"events,omitempty"`
+}
+
+// ReasonBucket is the nodes filtered out for the same reason.
+type ReasonBucket struct {
+	Plugin string `json:"plugin"`
+	Reason string `json:"reason"`
+	// Count is the number of the nodes.
+	Count int      `json:"count"`
+	Nodes []string `json:"nodes"`
+}
+
+// Event is an event of the Pod.
+type Event struct {
+	Type          string      `json:"type"`
+	Reason        string      `json:"reason"`
+	Message       string      `json:"message"`
+	Count         int32       `json:"count,omitempty"`
+	LastTimestamp metav1.Time `json:"lastTimestamp"`
+}
+
+// Service diagnoses the resources in the simulator.
+type Service struct {
+	client clientset.Interface
+}
+
+// NewService initializes Service.
+func NewService(client clientset.Interface) *Service {
+	return &Service{client: client}
+}
+
+// UnschedulablePods reports the Pending Pods which aren't bound to any node,
+// with their latest scheduling results and events.
+func (s *Service) UnschedulablePods(ctx context.Context) (*UnschedulableReport, error) {
+	pods, err := s.client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, xerrors.Errorf("list pods: %w", err)
+	}
+	events, err := s.client.CoreV1().Events(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, xerrors.Errorf("list events: %w", err)
+	}
+	eventsByPod := map[types.UID][]corev1.Event{}
+	for _, e := range events.Items {
+		if e.InvolvedObject.Kind == "Pod" {
+			eventsByPod[e.InvolvedObject.UID] = append(eventsByPod[e.InvolvedObject.UID], e)
+		}
+	}
+
+	report := &UnschedulableReport{Pods: []UnschedulablePod{}}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName != "" {
+			continue
+		}
+		report.Pods = append(report.Pods, DiagnoseUnschedulablePod(pod, eventsByPod[pod.UID]))
+	}
+	sort.Slice(report.Pods, func(i, j int) bool {
+		if report.Pods[i].Namespace != report.Pods[j].Namespace {
+			return report.Pods[i].Namespace < report.Pods[j].Namespace
+		}
+		return report.Pods[i].Name < report.Pods[j].Name
+	})
+	return report, nil
+}
+
+// DiagnoseUnschedulablePod explains why the pod isn't scheduled from the scheduling results on its annotations.
+// The nodes filtered out are bucketed by the plugin and the reason which rejected them.
+// The malformed results are ignored, as if the pod didn't have them.
+func DiagnoseUnschedulablePod(pod *corev1.Pod, events []corev1.Event) UnschedulablePod {
+	d := UnschedulablePod{
+		Namespace:         pod.Namespace,
+		Name:              pod.Name,
+		CreationTimestamp: pod.CreationTimestamp,
+		Events:            toEvents(events),
+	}
+
+	// plugin name → status
+	preFilterStatus := map[string]string{}
+	if v, ok := pod.Annotations[annotation.PreFilterStatusResultAnnotationKey]; ok && json.Unmarshal([]byte(v), &preFilterStatus) == nil {
+		d.HasResults = true
+		for plugin, status := range preFilterStatus {
+			if status == resultstore.SuccessMessage {
+				continue
+			}
+			if d.PreFilterFailures == nil {
+				d.PreFilterFailures = map[string]string{}
+			}
+			d.PreFilterFailures[plugin] = status
+		}
+	}
+
+	// node name → plugin name → filtering result
+	filter := map[string]map[string]string{}
+	if v, ok := pod.Annotations[annotation.FilterResultAnnotationKey]; ok && json.Unmarshal([]byte(v), &filter) == nil {
+		d.HasResults = true
+		d.Nodes = len(filter)
+		d.Reasons = bucketFilterFailures(filter)
+		failed := 0
+		for _, b := range d.Reasons {
+			failed += b.Count
+		}
+		d.FeasibleNodes = d.Nodes - failed
+	}
+
+	if d.HasResults {
+		d.Message = summarize(d)
+	}
+	return d
+}
+
+// bucketFilterFailures buckets the nodes by the plugin and the reason which rejected them.
+// A node is put in the bucket of the first plugin in the name order if it's rejected by multiple plugins,
+// which doesn't happen with the default scheduler since it stops running the Filter plugins on the first rejection.
+func bucketFilterFailures(filter map[string]map[string]string) []ReasonBucket {
+	type key struct{ plugin, reason string }
+	buckets := map[key]*ReasonBucket{}
+	for node, results := range filter {
+		plugins := make([]string, 0, len(results))
+		for plugin := range results {
+			plugins = append(plugins, plugin)
+		}
+		sort.Strings(plugins)
+		for _, plugin := range plugins {
+			reason := results[plugin]
+			if reason == resultstore.PassedFilterMessage {
+				continue
+			}
+			k := key{plugin: plugin, reason: reason}
+			if _, ok := buckets[k]; !ok {
+				buckets[k] = &ReasonBucket{Plugin: plugin, Reason: reason}
+			}
+			buckets[k].Count++
+			buckets[k].Nodes = append(buckets[k].Nodes, node)
+			break
+		}
+	}
+
+	ret := make([]ReasonBucket, 0, len(buckets))
+	for _, b := range buckets {
+		sort.Strings(b.Nodes)
+		ret = append(ret, *b)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Count != ret[j].Count {
+			return ret[i].Count > ret[j].Count
+		}
+		if ret[i].Reason != ret[j].Reason {
+			return ret[i].Reason < ret[j].Reason
+		}
+		return ret[i].Plugin < ret[j].Plugin
+	})
+	return ret
+}
+
+// summarize returns the message in the same format as the FailedScheduling event.
+func summarize(d UnschedulablePod) string {
+	reasons := make([]string, 0, len(d.PreFilterFailures)+len(d.Reasons))
+	plugins := make([]string, 0, len(d.PreFilterFailures))
+	for plugin := range d.PreFilterFailures {
+		plugins = append(plugins, plugin)
+	}
+	sort.Strings(plugins)
+	for _, plugin := range plugins {
+		reasons = append(reasons, fmt.Sprintf("%s rejected the pod: %s", plugin, d.PreFilterFailures[plugin]))
+	}
+	for _, b := range d.Reasons {
+		reasons = append(reasons, fmt.Sprintf("%d %s", b.Count, b.Reason))
+	}
+	msg := fmt.Sprintf("%d/%d nodes are available", d.FeasibleNodes, d.Nodes)
+	if len(reasons) == 0 {
+		return msg + "."
+	}
+	return msg + ": " + strings.Join(reasons, ", ") + "."
+}
+
+// toEvents converts the events, from the latest one.
+func toEvents(events []corev1.Event) []Event {
+	if len(events) == 0 {
+		return nil
+	}
+	ret := make([]Event, 0, len(events))
+	for _, e := range events {
+		last := e.LastTimestamp
+		if last.IsZero() {
+			last = metav1.NewTime(e.EventTime.Time)
+		}
+		ret = append(ret, Event{Type: e.Type, Reason: e.Reason, Message: e.Message, Count: e.Count, LastTimestamp: last})
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].LastTimestamp.Time.After(ret[j].LastTimestamp.Time)
+	})
+	return ret
+}
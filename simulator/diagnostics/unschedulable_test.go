@@ -0,0 +1,164 @@
+package diagnostics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/annotation"
+)
+
+func TestDiagnoseUnschedulablePod(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        UnschedulablePod
+	}{
+		{
+			name: "nodes filtered out for different reasons",
+			annotations: map[string]string{
+				annotation.FilterResultAnnotationKey: `{
+					"node1": {"NodeResourcesFit": "Insufficient cpu"},
+					"node2": {"NodeResourcesFit": "Insufficient cpu"},
+					"node3": {"NodeResourcesFit": "passed", "TaintToleration": "node(s) had untolerated taint {dedicated: gpu}"},
+					"node4": {"NodeResourcesFit": "Insufficient cpu"},
+					"node5": {"NodeResourcesFit": "passed", "TaintToleration": "node(s) had untolerated taint {dedicated: gpu}"}
+				}`,
+			},
+			want: UnschedulablePod{
+				HasResults:    true,
+				Message:       "0/5 nodes are available: 3 Insufficient cpu, 2 node(s) had untolerated taint {dedicated: gpu}.",
+				Nodes:         5,
+				FeasibleNodes: 0,
+				Reasons: []ReasonBucket{
+					{Plugin: "NodeResourcesFit", Reason: "Insufficient cpu", Count: 3, Nodes: []string{"node1", "node2", "node4"}},
+					{Plugin: "TaintToleration", Reason: "node(s) had untolerated taint {dedicated: gpu}", Count: 2, Nodes: []string{"node3", "node5"}},
+				},
+			},
+		},
+		{
+			name: "some nodes are feasible",
+			annotations: map[string]string{
+				annotation.FilterResultAnnotationKey: `{
+					"node1": {"NodeResourcesFit": "passed", "NodeAffinity": "passed"},
+					"node2": {"NodeResourcesFit": "passed", "NodeAffinity": "node(s) didn't match Pod's node affinity/selector"}
+				}`,
+			},
+			want: UnschedulablePod{
+				HasResults:    true,
+				Message:       "1/2 nodes are available: 1 node(s) didn't match Pod's node affinity/selector.",
+				Nodes:         2,
+				FeasibleNodes: 1,
+				Reasons: []ReasonBucket{
+					{Plugin: "NodeAffinity", Reason: "node(s) didn't match Pod's node affinity/selector", Count: 1, Nodes: []string{"node2"}},
+				},
+			},
+		},
+		{
+			name: "rejected by the PreFilter plugin",
+			annotations: map[string]string{
+				annotation.PreFilterStatusResultAnnotationKey: `{"NodeResourcesFit": "success", "VolumeBinding": "pod has unbound immediate PersistentVolumeClaims"}`,
+				annotation.FilterResultAnnotationKey:          `{}`,
+			},
+			want: UnschedulablePod{
+				HasResults:        true,
+				Message:           "0/0 nodes are available: VolumeBinding rejected the pod: pod has unbound immediate PersistentVolumeClaims.",
+				PreFilterFailures: map[string]string{"VolumeBinding": "pod has unbound immediate PersistentVolumeClaims"},
+				Reasons:           []ReasonBucket{},
+			},
+		},
+		{
+			name: "not tried to be scheduled yet",
+			want: UnschedulablePod{},
+		},
+		{
+			name: "malformed results are ignored",
+			annotations: map[string]string{
+				annotation.FilterResultAnnotationKey: `broken`,
+			},
+			want: UnschedulablePod{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod1", Annotations: tt.annotations}}
+			tt.want.Namespace = "default"
+			tt.want.Name = "pod1"
+
+			got := DiagnoseUnschedulablePod(pod, nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_UnschedulablePods(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := fake.NewSimpleClientset(
+		pendingPod("default", "cpu-hungry", map[string]string{
+			annotation.FilterResultAnnotationKey: `{"node1": {"NodeResourcesFit": "Insufficient cpu"}, "node2": {"NodeResourcesFit": "Insufficient cpu"}}`,
+		}),
+		pendingPod("team-a", "gpu-intolerant", map[string]string{
+			annotation.FilterResultAnnotationKey: `{"node1": {"TaintToleration": "node(s) had untolerated taint {dedicated: gpu}"}, "node2": {"NodeResourcesFit": "Insufficient cpu"}}`,
+		}),
+		pendingPod("default", "new", nil),
+		// The bound pod isn't reported even if it's Pending.
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bound", UID: "bound"},
+			Spec:       corev1.PodSpec{NodeName: "node1"},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "running", UID: "running"},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: "cpu-hungry.1"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "cpu-hungry", UID: "default/cpu-hungry"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "FailedScheduling",
+			Message:        "0/2 nodes are available: 2 Insufficient cpu.",
+			Count:          3,
+			LastTimestamp:  metav1.NewTime(now),
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: "running.1"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "running", UID: "running"},
+			Type:           corev1.EventTypeNormal,
+			Reason:         "Scheduled",
+		},
+	)
+	s := NewService(client)
+
+	report, err := s.UnschedulablePods(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.Pods, 3)
+
+	assert.Equal(t, "cpu-hungry", report.Pods[0].Name)
+	assert.Equal(t, "0/2 nodes are available: 2 Insufficient cpu.", report.Pods[0].Message)
+	assert.Equal(t, []Event{{Type: corev1.EventTypeWarning, Reason: "FailedScheduling", Message: "0/2 nodes are available: 2 Insufficient cpu.", Count: 3, LastTimestamp: metav1.NewTime(now)}}, report.Pods[0].Events)
+
+	assert.Equal(t, "new", report.Pods[1].Name)
+	assert.False(t, report.Pods[1].HasResults)
+	assert.Empty(t, report.Pods[1].Events)
+
+	assert.Equal(t, "team-a", report.Pods[2].Namespace)
+	assert.Equal(t, "0/2 nodes are available: 1 Insufficient cpu, 1 node(s) had untolerated taint {dedicated: gpu}.", report.Pods[2].Message)
+}
+
+func pendingPod(namespace, name string, annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(namespace + "/" + name), Annotations: annotations},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+}
@@ -0,0 +1,299 @@
+// Package etcdsnapshot saves the whole state of the simulator in etcd to the files, and restores it.
+//
+// A snapshot has all the keys under the prefix of the simulator read at a single revision, and the scheduler configuration.
+// It's not the backend database of etcd taken by `etcdctl snapshot save`
+// because etcd runs out of the simulator, and such a snapshot can't be restored without stopping etcd.
+package etcdsnapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"golang.org/x/xerrors"
+	"k8s.io/klog/v2"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/reset"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/util"
+)
+
+// fileExtension is the extension of the snapshot files. The snapshot name doesn't include it.
+const fileExtension = ".snapshot.json"
+
+// restoreTimeout is the time limit to replace the data in etcd, which isn't canceled with the context of the caller.
+const restoreTimeout = 5 * time.Minute
+
+var (
+	// ErrNotFound is returned when the snapshot doesn't exist.
+	ErrNotFound = errors.New("snapshot not found")
+	// ErrAlreadyExists is returned when saving the snapshot with the name already used.
+	ErrAlreadyExists = errors.New("snapshot already exists")
+	// ErrInvalidName is returned when the snapshot name can't be used as the file name.
+	ErrInvalidName = errors.New("invalid snapshot name")
+	// ErrJobsInProgress is returned when restoring the snapshot while any job is pending or running.
+	ErrJobsInProgress = errors.New("other jobs are pending or running")
+)
+
+var nameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,127}$`)
+
+// SchedulerService stops and restarts the scheduler around the restore.
+type SchedulerService interface {
+	GetSchedulerConfig() (*configv1.KubeSchedulerConfiguration, error)
+	RestartScheduler(cfg *configv1.KubeSchedulerConfiguration) error
+	ResetScheduler() error
+	ShutdownScheduler()
+}
+
+// WatcherService disconnects the clients watching resources so that they list the restored resources again.
+type WatcherService interface {
+	Watchers() []resourcewatcher.Watcher
+	Disconnect(id string) error
+}
+
+// JobLister lists the jobs, which must be finished before the restore.
+type JobLister interface {
+	List() []job.Job
+}
+
+// Info is the summary of a snapshot file.
+type Info struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// snapshot is the content of a snapshot file.
+type snapshot struct {
+	// Revision is the etcd revision at which the keys are read.
+	Revision        int64                                `json:"revision"`
+	SchedulerConfig *configv1.KubeSchedulerConfiguration `json:"schedulerConfig,omitempty"`
+	KVs             []keyValue                           `json:"kvs"`
+}
+
+type keyValue struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// Service saves the snapshots to dir, and restores them.
+type Service struct {
+	dir          string
+	kv           clientv3.KV
+	schedService SchedulerService
+	watchers     WatcherService
+	jobs         JobLister
+	now          func() time.Time
+
+	// mu serializes the saves and the restores.
+	mu sync.Mutex
+}
+
+// NewService initializes Service.
+func NewService(dir string, kv clientv3.KV, schedService SchedulerService, watchers WatcherService, jobs JobLister) *Service {
+	return &Service{dir: dir, kv: kv, schedService: schedService, watchers: watchers, jobs: jobs, now: time.Now}
+}
+
+// Save saves the current state to the snapshot with the name.
+// The name is generated from the current time if it's empty.
+func (s *Service) Save(ctx context.Context, name string) (*Info, error) {
+	if name == "" {
+		name = "snapshot-" + s.now().UTC().Format("20060102-150405")
+	}
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(path); err == nil {
+		return nil, xerrors.Errorf("save snapshot %s: %w", name, ErrAlreadyExists)
+	}
+
+	resp, err := s.kv.Get(ctx, reset.EtcdPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, xerrors.Errorf("get all data in etcd: %w", err)
+	}
+	snap := snapshot{Revision: resp.Header.GetRevision(), KVs: make([]keyValue, 0, len(resp.Kvs))}
+	for _, kv := range resp.Kvs {
+		snap.KVs = append(snap.KVs, keyValue{Key: string(kv.Key), Value: kv.Value})
+	}
+	snap.SchedulerConfig, err = s.schedService.GetSchedulerConfig()
+	if err != nil {
+		return nil, xerrors.Errorf("get the scheduler config: %w", err)
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return nil, xerrors.Errorf("encode snapshot: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, xerrors.Errorf("create the snapshot directory: %w", err)
+	}
+	// The snapshot is written to the temporary file first so that a partial file is never listed.
+	tmp, err := os.CreateTemp(s.dir, "."+name+"-*")
+	if err != nil {
+		return nil, xerrors.Errorf("create a temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return nil, xerrors.Errorf("write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, xerrors.Errorf("close snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, xerrors.Errorf("rename snapshot: %w", err)
+	}
+
+	klog.Infof("saved snapshot %s with %d keys at revision %d", name, len(snap.KVs), snap.Revision)
+	return s.info(name, path)
+}
+
+// List returns the snapshots in the order of the names.
+func (s *Service) List() ([]Info, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Info{}, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("read the snapshot directory: %w", err)
+	}
+
+	infos := []Info{}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), fileExtension)
+		if e.IsDir() || !ok || !nameRegexp.MatchString(name) {
+			continue
+		}
+		info, err := s.info(name, filepath.Join(s.dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, *info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// Restore replaces the current state with the snapshot.
+// It stops the scheduler, disconnects the clients watching resources, replaces all the data in etcd,
+// and then restarts the scheduler with the scheduler config in the snapshot.
+// It returns ErrJobsInProgress if any job is pending or running.
+//
+// Once the data starts to be replaced, it isn't canceled with ctx, e.g., when the client disconnects or the request times out,
+// since etcd is left partially wiped otherwise. It's limited by restoreTimeout instead.
+func (s *Service) Restore(ctx context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs.List() {
+		if !j.Finished() {
+			return xerrors.Errorf("job %s is %s: %w", j.ID, j.State, ErrJobsInProgress)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return xerrors.Errorf("restore snapshot %s: %w", name, ErrNotFound)
+	}
+	if err != nil {
+		return xerrors.Errorf("read snapshot: %w", err)
+	}
+	snap := snapshot{}
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return xerrors.Errorf("decode snapshot %s: %w", name, err)
+	}
+
+	prevCfg, err := s.schedService.GetSchedulerConfig()
+	if err != nil {
+		return xerrors.Errorf("get the scheduler config: %w", err)
+	}
+	s.schedService.ShutdownScheduler()
+	for _, w := range s.watchers.Watchers() {
+		if err := s.watchers.Disconnect(w.ID); err != nil && !errors.Is(err, resourcewatcher.ErrWatcherNotFound) {
+			klog.Warningf("failed to disconnect watcher %s before the restore: %v", w.ID, err)
+		}
+	}
+
+	replaceCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), restoreTimeout)
+	defer cancel()
+	if err := s.replace(replaceCtx, snap.KVs); err != nil {
+		// The scheduler is restarted anyway so that the simulator keeps working with the data partially restored.
+		if rerr := s.restartScheduler(prevCfg); rerr != nil {
+			klog.Errorf("failed to restart the scheduler after the restore failed: %v", rerr)
+		}
+		return xerrors.Errorf("restore snapshot %s: %w", name, err)
+	}
+	if err := s.restartScheduler(snap.SchedulerConfig); err != nil {
+		return xerrors.Errorf("restart the scheduler: %w", err)
+	}
+
+	klog.Infof("restored snapshot %s with %d keys taken at revision %d", name, len(snap.KVs), snap.Revision)
+	return nil
+}
+
+// replace replaces all the data in etcd with kvs.
+func (s *Service) replace(ctx context.Context, kvs []keyValue) error {
+	if _, err := s.kv.Delete(ctx, reset.EtcdPrefix, clientv3.WithPrefix()); err != nil {
+		return xerrors.Errorf("delete all data in etcd: %w", err)
+	}
+	eg := util.NewErrGroupWithSemaphore(ctx)
+	for _, kv := range kvs {
+		kv := kv
+		if err := eg.Go(func() error {
+			if _, err := s.kv.Put(ctx, kv.Key, string(kv.Value)); err != nil {
+				return xerrors.Errorf("put %s: %w", kv.Key, err)
+			}
+			return nil
+		}); err != nil {
+			return xerrors.Errorf("start putting data: %w", err)
+		}
+	}
+	if err := eg.Wait(); err != nil {
+		return xerrors.Errorf("put data in etcd: %w", err)
+	}
+	return nil
+}
+
+// restartScheduler restarts the scheduler with cfg, or with the initial config if cfg is nil,
+// i.e., no config has been applied through the simulator when the snapshot is taken.
+func (s *Service) restartScheduler(cfg *configv1.KubeSchedulerConfiguration) error {
+	if cfg == nil {
+		return s.schedService.ResetScheduler()
+	}
+	return s.schedService.RestartScheduler(cfg)
+}
+
+func (s *Service) path(name string) (string, error) {
+	if !nameRegexp.MatchString(name) {
+		return "", xerrors.Errorf("%q must consist of alphanumerics, '.', '-' and '_', and start with an alphanumeric: %w", name, ErrInvalidName)
+	}
+	return filepath.Join(s.dir, name+fileExtension), nil
+}
+
+func (s *Service) info(name, path string) (*Info, error) {
+	st, err := os.Stat(path)
+	if err != nil {
+		return nil, xerrors.Errorf("stat snapshot %s: %w", name, err)
+	}
+	return &Info{Name: name, Size: st.Size(), CreatedAt: st.ModTime()}, nil
+}
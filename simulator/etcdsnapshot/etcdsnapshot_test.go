@@ -0,0 +1,310 @@
+package etcdsnapshot
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher"
+)
+
+// fakeKV is the in-memory etcd supporting Get, Put and Delete with or without WithPrefix.
+type fakeKV struct {
+	clientv3.KV
+
+	mu       sync.Mutex
+	data     map[string]string
+	revision int64
+	putErr   error
+	// onDelete is called after Delete if non-nil.
+	onDelete func()
+}
+
+func newFakeKV(data map[string]string) *fakeKV {
+	return &fakeKV{data: data, revision: 1}
+}
+
+func (f *fakeKV) keys(op clientv3.Op) []string {
+	key, end := string(op.KeyBytes()), string(op.RangeBytes())
+	var keys []string
+	for k := range f.data {
+		if k == key || (end != "" && k >= key && k < end) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (f *fakeKV) Get(_ context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp := &clientv3.GetResponse{Header: &pb.ResponseHeader{Revision: f.revision}}
+	for _, k := range f.keys(clientv3.OpGet(key, opts...)) {
+		resp.Kvs = append(resp.Kvs, &mvccpb.KeyValue{Key: []byte(k), Value: []byte(f.data[k])})
+	}
+	return resp, nil
+}
+
+func (f *fakeKV) Put(ctx context.Context, key, val string, _ ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// the etcd client fails with the canceled context.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.putErr != nil {
+		return nil, f.putErr
+	}
+	f.data[key] = val
+	f.revision++
+	return &clientv3.PutResponse{}, nil
+}
+
+func (f *fakeKV) Delete(_ context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, k := range f.keys(clientv3.OpDelete(key, opts...)) {
+		delete(f.data, k)
+	}
+	f.revision++
+	if f.onDelete != nil {
+		f.onDelete()
+	}
+	return &clientv3.DeleteResponse{}, nil
+}
+
+// fakeSchedulerService records the calls in order.
+type fakeSchedulerService struct {
+	cfg   *configv1.KubeSchedulerConfiguration
+	calls []string
+}
+
+func (s *fakeSchedulerService) GetSchedulerConfig() (*configv1.KubeSchedulerConfiguration, error) {
+	return s.cfg, nil
+}
+
+func (s *fakeSchedulerService) RestartScheduler(cfg *configv1.KubeSchedulerConfiguration) error {
+	s.calls = append(s.calls, "restart")
+	s.cfg = cfg
+	return nil
+}
+
+func (s *fakeSchedulerService) ResetScheduler() error {
+	s.calls = append(s.calls, "reset")
+	return nil
+}
+
+func (s *fakeSchedulerService) ShutdownScheduler() {
+	s.calls = append(s.calls, "shutdown")
+}
+
+type fakeWatcherService struct {
+	watchers     []resourcewatcher.Watcher
+	disconnected []string
+}
+
+func (w *fakeWatcherService) Watchers() []resourcewatcher.Watcher {
+	return w.watchers
+}
+
+func (w *fakeWatcherService) Disconnect(id string) error {
+	w.disconnected = append(w.disconnected, id)
+	return nil
+}
+
+type fakeJobLister []job.Job
+
+func (l fakeJobLister) List() []job.Job {
+	return l
+}
+
+func schedulerConfig(name string) *configv1.KubeSchedulerConfiguration {
+	return &configv1.KubeSchedulerConfiguration{Profiles: []configv1.KubeSchedulerProfile{{SchedulerName: &name}}}
+}
+
+func TestService_Save(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	kv := newFakeKV(map[string]string{
+		"/kube-scheduler-simulator/pods/default/pod1": "pod1",
+		"/kube-scheduler-simulator/nodes/node1":       "node1",
+		"/other/key":                                  "other",
+	})
+	s := NewService(dir, kv, &fakeSchedulerService{cfg: schedulerConfig("custom")}, &fakeWatcherService{}, fakeJobLister{})
+	s.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	got, err := s.Save(context.Background(), "before")
+	require.NoError(t, err)
+	assert.Equal(t, "before", got.Name)
+	assert.Positive(t, got.Size)
+
+	generated, err := s.Save(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "snapshot-20240102-030405", generated.Name)
+
+	_, err = s.Save(context.Background(), "before")
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	_, err = s.Save(context.Background(), "../escape")
+	assert.ErrorIs(t, err, ErrInvalidName)
+
+	// The files other than the snapshots are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("note"), 0o600))
+	list, err := s.List()
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "before", list[0].Name)
+	assert.Equal(t, "snapshot-20240102-030405", list[1].Name)
+}
+
+func TestService_ListWithoutDirectory(t *testing.T) {
+	t.Parallel()
+	s := NewService(filepath.Join(t.TempDir(), "none"), newFakeKV(map[string]string{}), &fakeSchedulerService{}, &fakeWatcherService{}, fakeJobLister{})
+	list, err := s.List()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestService_Restore(t *testing.T) {
+	t.Parallel()
+	saved := map[string]string{
+		"/kube-scheduler-simulator/pods/default/pod1": "pod1",
+		"/kube-scheduler-simulator/nodes/node1":       "node1",
+	}
+	tests := []struct {
+		name      string
+		savedCfg  *configv1.KubeSchedulerConfiguration
+		restore   string
+		jobs      fakeJobLister
+		putErr    error
+		wantErr   error
+		wantCalls []string
+		wantData  map[string]string
+	}{
+		{
+			name:      "restore the data and the scheduler config",
+			savedCfg:  schedulerConfig("saved"),
+			restore:   "saved",
+			wantCalls: []string{"shutdown", "restart"},
+			wantData: map[string]string{
+				"/kube-scheduler-simulator/pods/default/pod1": "pod1",
+				"/kube-scheduler-simulator/nodes/node1":       "node1",
+				"/other/key":                                  "other",
+			},
+		},
+		{
+			name:      "the scheduler is reset if no config is applied when the snapshot is taken",
+			restore:   "saved",
+			wantCalls: []string{"shutdown", "reset"},
+			wantData: map[string]string{
+				"/kube-scheduler-simulator/pods/default/pod1": "pod1",
+				"/kube-scheduler-simulator/nodes/node1":       "node1",
+				"/other/key":                                  "other",
+			},
+		},
+		{
+			name:     "conflict with the running job",
+			savedCfg: schedulerConfig("saved"),
+			restore:  "saved",
+			jobs:     fakeJobLister{{ID: "1", State: job.StateSucceeded}, {ID: "2", State: job.StateRunning}},
+			wantErr:  ErrJobsInProgress,
+		},
+		{
+			name:     "unknown snapshot",
+			savedCfg: schedulerConfig("saved"),
+			restore:  "unknown",
+			wantErr:  ErrNotFound,
+		},
+		{
+			name:      "the scheduler is restarted with the previous config if the restore fails",
+			savedCfg:  schedulerConfig("saved"),
+			restore:   "saved",
+			putErr:    errors.New("etcd is down"),
+			wantCalls: []string{"shutdown", "restart"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			data := map[string]string{"/other/key": "other"}
+			for k, v := range saved {
+				data[k] = v
+			}
+			kv := newFakeKV(data)
+			sched := &fakeSchedulerService{cfg: tt.savedCfg}
+			watchers := &fakeWatcherService{watchers: []resourcewatcher.Watcher{{ID: "w1"}, {ID: "w2"}}}
+			s := NewService(t.TempDir(), kv, sched, watchers, tt.jobs)
+			_, err := s.Save(context.Background(), "saved")
+			require.NoError(t, err)
+
+			// Change the state after the snapshot.
+			current := schedulerConfig("current")
+			sched.cfg = current
+			_, err = kv.Put(context.Background(), "/kube-scheduler-simulator/pods/default/pod2", "pod2")
+			require.NoError(t, err)
+			_, err = kv.Delete(context.Background(), "/kube-scheduler-simulator/nodes/node1")
+			require.NoError(t, err)
+			kv.putErr = tt.putErr
+
+			err = s.Restore(context.Background(), tt.restore)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, sched.calls, "the scheduler isn't stopped")
+				return
+			}
+			if tt.putErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantCalls, sched.calls)
+				assert.Equal(t, current, sched.cfg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCalls, sched.calls)
+			assert.Equal(t, []string{"w1", "w2"}, watchers.disconnected)
+			assert.Equal(t, tt.wantData, kv.data)
+			if tt.savedCfg != nil {
+				assert.Equal(t, tt.savedCfg, sched.cfg)
+			}
+		})
+	}
+}
+
+func TestService_Restore_canceled(t *testing.T) {
+	t.Parallel()
+	saved := map[string]string{
+		"/kube-scheduler-simulator/pods/default/pod1": "pod1",
+		"/kube-scheduler-simulator/nodes/node1":       "node1",
+	}
+	data := map[string]string{}
+	for k, v := range saved {
+		data[k] = v
+	}
+	kv := newFakeKV(data)
+	sched := &fakeSchedulerService{cfg: schedulerConfig("saved")}
+	s := NewService(t.TempDir(), kv, sched, &fakeWatcherService{}, fakeJobLister{})
+	_, err := s.Save(context.Background(), "saved")
+	require.NoError(t, err)
+
+	// The request is canceled, e.g., by the client disconnecting, right after the data is deleted.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kv.onDelete = cancel
+
+	require.NoError(t, s.Restore(ctx, "saved"))
+	assert.Equal(t, saved, kv.data, "etcd isn't left partially wiped")
+	assert.Equal(t, []string{"shutdown", "restart"}, sched.calls)
+}
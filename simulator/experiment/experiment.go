@@ -0,0 +1,276 @@
+// Package experiment isolates the resources of the users sharing one simulator into the experiments.
+//
+// An experiment is a set of the namespaces labeled with LabelKey and the ID of the experiment.
+// The resources created in the scope of the experiment are labeled in the same way,
+// so that the experiment can be watched, aggregated and torn down by the label.
+// The experiments aren't kept in memory, and they're found from the labels on the namespaces,
+// so that they survive the restart of the simulator.
+package experiment
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+)
+
+const (
+	// LabelKey is the label put on the namespaces and the resources of the experiment, whose value is the ID of the experiment.
+	LabelKey = "kube-scheduler-simulator.sigs.k8s.io/experiment"
+	// NameAnnotationKey is the annotation on the namespaces of the experiment, whose value is the name of the experiment.
+	NameAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/experiment-name"
+	// MaxNamespaces is the maximum number of the namespaces of an experiment.
+	MaxNamespaces = 20
+	// idPrefix is the prefix of the generated IDs.
+	idPrefix = "exp-"
+)
+
+var (
+	// ErrNotFound is returned when the experiment doesn't exist.
+	ErrNotFound = errors.New("experiment not found")
+	// ErrInvalidCreateOptions is returned when the given CreateOptions is invalid.
+	ErrInvalidCreateOptions = errors.New("invalid experiment creation options")
+	// ErrAlreadyExists is returned when any namespace of the new experiment already exists.
+	ErrAlreadyExists = errors.New("namespace already exists")
+)
+
+// ScopedGVRs is the resources labeled in the scope of the experiments, and deleted when the experiments are torn down,
+// in addition to the namespaces of the experiments.
+// resourceapplier deletes them in the reverse order of their dependencies.
+var ScopedGVRs = []schema.GroupVersionResource{
+	{Version: "v1", Resource: "pods"},
+	{Version: "v1", Resource: "persistentvolumeclaims"},
+	{Version: "v1", Resource: "persistentvolumes"},
+	{Version: "v1", Resource: "nodes"},
+}
+
+var namespacesGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// ResourceDeleter deletes the resources of the experiment.
+type ResourceDeleter interface {
+	DeleteAll(ctx context.Context, gvrs []schema.GroupVersionResource, opts resourceapplier.DeleteAllOptions) (map[schema.GroupVersionResource]int, error)
+}
+
+// Experiment is a set of the namespaces and the resources labeled with its ID.
+type Experiment struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	// Namespaces are the namespaces of the experiment in the order of the names.
+	Namespaces []string  `json:"namespaces"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// HasNamespace returns true if the namespace belongs to the experiment.
+func (e *Experiment) HasNamespace(namespace string) bool {
+	for _, ns := range e.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Labels returns the labels put on the resources of the experiment.
+func (e *Experiment) Labels() map[string]string {
+	return map[string]string{LabelKey: e.ID}
+}
+
+// Selector returns the label selector selecting the resources of the experiment.
+func (e *Experiment) Selector() labels.Selector {
+	return labels.SelectorFromSet(e.Labels())
+}
+
+// CreateOptions is the options for creating an experiment.
+type CreateOptions struct {
+	// Name is the human-readable name of the experiment. It doesn't need to be unique.
+	Name string
+	// Namespaces are the namespaces created for the experiment. The namespace named after the ID is created if empty.
+	Namespaces []string
+}
+
+// DeleteSummary is the result of tearing down an experiment.
+type DeleteSummary struct {
+	// Deleted is the number of the deleted resources of each resource, e.g., "pods".
+	Deleted map[string]int `json:"deleted"`
+}
+
+// Service creates the experiments, finds them from the labels on the namespaces, and tears them down.
+type Service struct {
+	client  clientset.Interface
+	deleter ResourceDeleter
+	newID   func() string
+}
+
+// NewService initializes Service.
+func NewService(client clientset.Interface, deleter ResourceDeleter) *Service {
+	return &Service{
+		client:  client,
+		deleter: deleter,
+		newID:   func() string { return idPrefix + utilrand.String(8) },
+	}
+}
+
+// Create creates the namespaces labeled with a new ID, and returns the experiment.
+// The namespaces created are deleted if any of them fails to be created.
+func (s *Service) Create(ctx context.Context, opts CreateOptions) (*Experiment, error) {
+	id := s.newID()
+	namespaces := opts.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{id}
+	}
+	if err := validateNamespaces(namespaces); err != nil {
+		return nil, err
+	}
+
+	created := make([]string, 0, len(namespaces))
+	for _, name := range namespaces {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{LabelKey: id},
+			Annotations: map[string]string{NameAnnotationKey: opts.Name},
+		}}
+		_, err := s.client.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+		if err == nil {
+			created = append(created, name)
+			continue
+		}
+		s.rollback(created)
+		if apierrors.IsAlreadyExists(err) {
+			return nil, xerrors.Errorf("create namespace %s: %w", name, ErrAlreadyExists)
+		}
+		return nil, xerrors.Errorf("create namespace %s: %w", name, err)
+	}
+
+	return s.Get(ctx, id)
+}
+
+// rollback deletes the namespaces created for the experiment which failed to be created.
+func (s *Service) rollback(namespaces []string) {
+	for _, name := range namespaces {
+		// The context of the request may be already canceled.
+		if err := s.client.CoreV1().Namespaces().Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			klog.Warningf("failed to delete namespace %s of the experiment failed to be created: %v", name, err)
+		}
+	}
+}
+
+func validateNamespaces(namespaces []string) error {
+	if len(namespaces) > MaxNamespaces {
+		return xerrors.Errorf("%d namespaces exceed the maximum %d: %w", len(namespaces), MaxNamespaces, ErrInvalidCreateOptions)
+	}
+	seen := map[string]bool{}
+	for _, name := range namespaces {
+		if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+			return xerrors.Errorf("invalid namespace %q: %s: %w", name, strings.Join(errs, ", "), ErrInvalidCreateOptions)
+		}
+		if seen[name] {
+			return xerrors.Errorf("duplicated namespace %q: %w", name, ErrInvalidCreateOptions)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// Get returns the experiment with the id.
+func (s *Service) Get(ctx context.Context, id string) (*Experiment, error) {
+	if errs := validation.IsValidLabelValue(id); id == "" || len(errs) > 0 {
+		return nil, xerrors.Errorf("get experiment %q: %w", id, ErrNotFound)
+	}
+	experiments, err := s.list(ctx, labels.SelectorFromSet(labels.Set{LabelKey: id}))
+	if err != nil {
+		return nil, err
+	}
+	if len(experiments) == 0 {
+		return nil, xerrors.Errorf("get experiment %s: %w", id, ErrNotFound)
+	}
+	return &experiments[0], nil
+}
+
+// List returns all the experiments in the order of the IDs.
+func (s *Service) List(ctx context.Context) ([]Experiment, error) {
+	selector, err := labels.Parse(LabelKey)
+	if err != nil {
+		return nil, xerrors.Errorf("parse the selector of the experiments: %w", err)
+	}
+	return s.list(ctx, selector)
+}
+
+func (s *Service) list(ctx context.Context, selector labels.Selector) ([]Experiment, error) {
+	list, err := s.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, xerrors.Errorf("list the namespaces of the experiments: %w", err)
+	}
+
+	experiments := map[string]*Experiment{}
+	for i := range list.Items {
+		ns := &list.Items[i]
+		if ns.DeletionTimestamp != nil {
+			// It's being torn down.
+			continue
+		}
+		id := ns.Labels[LabelKey]
+		e, ok := experiments[id]
+		if !ok {
+			e = &Experiment{ID: id, Name: ns.Annotations[NameAnnotationKey], CreatedAt: ns.CreationTimestamp.Time}
+			experiments[id] = e
+		}
+		e.Namespaces = append(e.Namespaces, ns.Name)
+		if ns.CreationTimestamp.Time.Before(e.CreatedAt) {
+			e.CreatedAt = ns.CreationTimestamp.Time
+		}
+	}
+
+	ret := make([]Experiment, 0, len(experiments))
+	for _, e := range experiments {
+		sort.Strings(e.Namespaces)
+		ret = append(ret, *e)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID < ret[j].ID })
+	return ret, nil
+}
+
+// Delete tears down the experiment, i.e., deletes all the resources and the namespaces labeled with the id.
+func (s *Service) Delete(ctx context.Context, id string) (*DeleteSummary, error) {
+	e, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	gvrs := append(append([]schema.GroupVersionResource{}, ScopedGVRs...), namespacesGVR)
+	deleted, err := s.deleter.DeleteAll(ctx, gvrs, resourceapplier.DeleteAllOptions{LabelSelector: e.Selector().String()})
+	if err != nil {
+		return nil, xerrors.Errorf("delete the resources of experiment %s: %w", id, err)
+	}
+	summary := &DeleteSummary{Deleted: make(map[string]int, len(deleted))}
+	for gvr, n := range deleted {
+		summary.Deleted[gvr.Resource] = n
+	}
+	return summary, nil
+}
+
+type contextKey struct{}
+
+// NewContext returns the context in the scope of the experiment.
+func NewContext(ctx context.Context, e *Experiment) context.Context {
+	return context.WithValue(ctx, contextKey{}, e)
+}
+
+// FromContext returns the experiment of the scope of ctx, or nil if it's not in the scope of any experiment.
+func FromContext(ctx context.Context) *Experiment {
+	e, _ := ctx.Value(contextKey{}).(*Experiment)
+	return e
+}
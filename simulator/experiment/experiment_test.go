@@ -0,0 +1,177 @@
+package experiment
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+)
+
+type fakeDeleter struct {
+	gotGVRs []schema.GroupVersionResource
+	gotOpts resourceapplier.DeleteAllOptions
+}
+
+func (d *fakeDeleter) DeleteAll(_ context.Context, gvrs []schema.GroupVersionResource, opts resourceapplier.DeleteAllOptions) (map[schema.GroupVersionResource]int, error) {
+	d.gotGVRs = gvrs
+	d.gotOpts = opts
+	deleted := map[schema.GroupVersionResource]int{}
+	for _, gvr := range gvrs {
+		deleted[gvr] = 1
+	}
+	return deleted, nil
+}
+
+func experimentNamespace(name, id string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        name,
+		Labels:      map[string]string{LabelKey: id},
+		Annotations: map[string]string{NameAnnotationKey: "name-of-" + id},
+	}}
+}
+
+func TestService_Create(t *testing.T) {
+	t.Parallel()
+	tooMany := make([]string, MaxNamespaces+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("ns-%d", i)
+	}
+
+	tests := []struct {
+		name              string
+		opts              CreateOptions
+		want              *Experiment
+		wantErr           error
+		wantNamespaces    []string
+		wantNotNamespaces []string
+	}{
+		{
+			name:           "create the namespaces given",
+			opts:           CreateOptions{Name: "binpacking", Namespaces: []string{"b", "a"}},
+			want:           &Experiment{ID: "exp-test", Name: "binpacking", Namespaces: []string{"a", "b"}},
+			wantNamespaces: []string{"a", "b"},
+		},
+		{
+			name:           "create the namespace named after the ID by default",
+			want:           &Experiment{ID: "exp-test", Namespaces: []string{"exp-test"}},
+			wantNamespaces: []string{"exp-test"},
+		},
+		{
+			name:    "invalid namespace",
+			opts:    CreateOptions{Namespaces: []string{"Invalid_Name"}},
+			wantErr: ErrInvalidCreateOptions,
+		},
+		{
+			name:    "duplicated namespaces",
+			opts:    CreateOptions{Namespaces: []string{"a", "a"}},
+			wantErr: ErrInvalidCreateOptions,
+		},
+		{
+			name:    "too many namespaces",
+			opts:    CreateOptions{Namespaces: tooMany},
+			wantErr: ErrInvalidCreateOptions,
+		},
+		{
+			name:              "the namespaces created are deleted if any namespace already exists",
+			opts:              CreateOptions{Namespaces: []string{"a", "existing"}},
+			wantErr:           ErrAlreadyExists,
+			wantNotNamespaces: []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			client := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "existing"}})
+			s := NewService(client, &fakeDeleter{})
+			s.newID = func() string { return "exp-test" }
+
+			got, err := s.Create(ctx, tt.opts)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				got.CreatedAt = tt.want.CreatedAt
+				assert.Equal(t, tt.want, got)
+			}
+
+			for _, name := range tt.wantNamespaces {
+				ns, err := client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+				require.NoError(t, err)
+				assert.Equal(t, "exp-test", ns.Labels[LabelKey])
+				assert.Equal(t, tt.opts.Name, ns.Annotations[NameAnnotationKey])
+			}
+			for _, name := range tt.wantNotNamespaces {
+				_, err := client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+				assert.Error(t, err, name)
+			}
+		})
+	}
+}
+
+func TestService_GetAndList(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	terminating := experimentNamespace("terminating", "exp-2")
+	terminating.DeletionTimestamp = &metav1.Time{}
+	client := fake.NewSimpleClientset(
+		experimentNamespace("b", "exp-1"),
+		experimentNamespace("a", "exp-1"),
+		experimentNamespace("c", "exp-0"),
+		terminating,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+	)
+	s := NewService(client, &fakeDeleter{})
+
+	got, err := s.Get(ctx, "exp-1")
+	require.NoError(t, err)
+	assert.Equal(t, &Experiment{ID: "exp-1", Name: "name-of-exp-1", Namespaces: []string{"a", "b"}}, got)
+
+	for _, id := range []string{"exp-2", "exp-unknown", "", "invalid id"} {
+		_, err = s.Get(ctx, id)
+		assert.ErrorIs(t, err, ErrNotFound, id)
+	}
+
+	list, err := s.List(ctx)
+	require.NoError(t, err)
+	ids := []string{}
+	for _, e := range list {
+		ids = append(ids, e.ID)
+	}
+	assert.Equal(t, []string{"exp-0", "exp-1"}, ids)
+}
+
+func TestService_Delete(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	deleter := &fakeDeleter{}
+	s := NewService(fake.NewSimpleClientset(experimentNamespace("a", "exp-1")), deleter)
+
+	got, err := s.Delete(ctx, "exp-1")
+	require.NoError(t, err)
+	assert.Equal(t, &DeleteSummary{Deleted: map[string]int{
+		"pods": 1, "persistentvolumeclaims": 1, "persistentvolumes": 1, "nodes": 1, "namespaces": 1,
+	}}, got)
+	assert.Equal(t, append(append([]schema.GroupVersionResource{}, ScopedGVRs...), namespacesGVR), deleter.gotGVRs)
+	assert.Equal(t, LabelKey+"=exp-1", deleter.gotOpts.LabelSelector)
+
+	_, err = s.Delete(ctx, "exp-unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestContext(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert.Nil(t, FromContext(ctx))
+	e := &Experiment{ID: "exp-1"}
+	assert.Same(t, e, FromContext(NewContext(ctx, e)))
+}
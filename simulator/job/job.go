@@ -0,0 +1,312 @@
+// Package job runs the long-running operations, e.g., exporting or resetting the resources, in background
+// so that the clients don't have to keep the requests open until they finish.
+// The jobs are kept only in memory, and lost when the simulator restarts.
+package job
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/klog/v2"
+)
+
+// Type is the kind of the operation the job runs.
+type Type string
+
+const (
+	TypeExport Type = "export"
+	TypeImport Type = "import"
+	TypeReset  Type = "reset"
+	TypeReplay Type = "replay"
+	// TypeScenario is the job running a scenario. It's submitted through the scenario API, not the job API.
+	TypeScenario Type = "scenario"
+	// TypeReschedule is the job rescheduling the scheduled Pods. It's submitted through the scheduler configuration API, not the job API.
+	TypeReschedule Type = "reschedule"
+)
+
+// DefaultExclusiveTypes are the types of which only one job can be pending or running at a time.
+var DefaultExclusiveTypes = []Type{TypeImport, TypeReset, TypeReplay, TypeScenario, TypeReschedule}
+
+// State is the state of the job.
+type State string
+
+const (
+	// StatePending means the job waits for the other jobs to finish.
+	StatePending State = "pending"
+	StateRunning State = "running"
+	// StateSucceeded, StateFailed and StateCancelled are the final states.
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+	StateCancelled State = "cancelled"
+)
+
+const (
+	// DefaultMaxRunning is the default number of the jobs which can run at a time.
+	DefaultMaxRunning = 4
+	// DefaultRetention is the default number of the finished jobs kept.
+	DefaultRetention = 100
+)
+
+var (
+	// ErrNotFound is returned when the job doesn't exist, or is already deleted by the retention.
+	ErrNotFound = errors.New("job not found")
+	// ErrConflict is returned when another job of the exclusive type is pending or running.
+	ErrConflict = errors.New("another job of the same type is in progress")
+	// ErrFinished is returned when cancelling the job already finished.
+	ErrFinished = errors.New("job already finished")
+)
+
+// Progress is the progress of the job reported by itself.
+type Progress struct {
+	// Done and Total are the number of the steps done and all the steps.
+	Done  int `json:"done"`
+	Total int `json:"total"`
+	// Message describes the current step.
+	Message string `json:"message,omitempty"`
+}
+
+// Job is the snapshot of the job.
+type Job struct {
+	ID         string      `json:"id"`
+	Type       Type        `json:"type"`
+	State      State       `json:"state"`
+	Progress   Progress    `json:"progress"`
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	StartedAt  *time.Time  `json:"startedAt,omitempty"`
+	FinishedAt *time.Time  `json:"finishedAt,omitempty"`
+}
+
+// Finished returns true if the job is in the final state.
+func (j *Job) Finished() bool {
+	return j.State == StateSucceeded || j.State == StateFailed || j.State == StateCancelled
+}
+
+// Func is the operation run by the job. It must stop when ctx is cancelled.
+// It can call report to update the progress, and its result is reported as Job.Result.
+type Func func(ctx context.Context, report func(Progress)) (interface{}, error)
+
+// Options is the options of Manager.
+type Options struct {
+	// MaxRunning is the number of the jobs which can run at a time. DefaultMaxRunning is used if zero.
+	MaxRunning int
+	// Retention is the number of the finished jobs kept. DefaultRetention is used if zero.
+	Retention int
+	// ExclusiveTypes are the types of which only one job can be pending or running at a time.
+	ExclusiveTypes []Type
+}
+
+// Manager runs the jobs in background.
+type Manager struct {
+	retention int
+	exclusive map[Type]bool
+	// slots limits the number of the running jobs.
+	slots chan struct{}
+	now   func() time.Time
+
+	mu   sync.Mutex
+	jobs map[string]*entry
+	// seq is the sequence number of the latest job submitted.
+	seq int64
+}
+
+type entry struct {
+	seq    int64
+	job    Job
+	cancel context.CancelFunc
+	// cancelled is true when Cancel or Shutdown is called.
+	cancelled bool
+	done      chan struct{}
+}
+
+// NewManager initializes Manager.
+func NewManager(opts Options) *Manager {
+	if opts.MaxRunning <= 0 {
+		opts.MaxRunning = DefaultMaxRunning
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = DefaultRetention
+	}
+	exclusive := make(map[Type]bool, len(opts.ExclusiveTypes))
+	for _, t := range opts.ExclusiveTypes {
+		exclusive[t] = true
+	}
+	return &Manager{
+		retention: opts.Retention,
+		exclusive: exclusive,
+		slots:     make(chan struct{}, opts.MaxRunning),
+		now:       time.Now,
+		jobs:      map[string]*entry{},
+	}
+}
+
+// Submit starts running fn in background as the job of typ.
+// It returns ErrConflict if typ is exclusive and another job of typ is pending or running.
+func (m *Manager) Submit(typ Type, fn Func) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.exclusive[typ] {
+		for _, e := range m.jobs {
+			if e.job.Type == typ && !e.job.Finished() {
+				return nil, xerrors.Errorf("job %s of %s is in progress: %w", e.job.ID, typ, ErrConflict)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.seq++
+	e := &entry{
+		seq:    m.seq,
+		job:    Job{ID: string(uuid.NewUUID()), Type: typ, State: StatePending, CreatedAt: m.now()},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	m.jobs[e.job.ID] = e
+	go m.run(ctx, e, fn)
+
+	j := e.job
+	return &j, nil
+}
+
+func (m *Manager) run(ctx context.Context, e *entry, fn Func) {
+	defer close(e.done)
+	defer e.cancel()
+
+	select {
+	case m.slots <- struct{}{}:
+		defer func() { <-m.slots }()
+	case <-ctx.Done():
+		m.finish(e, nil, ctx.Err())
+		return
+	}
+
+	m.mu.Lock()
+	now := m.now()
+	e.job.State = StateRunning
+	e.job.StartedAt = &now
+	m.mu.Unlock()
+
+	result, err := fn(ctx, func(p Progress) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		e.job.Progress = p
+	})
+	m.finish(e, result, err)
+}
+
+// finish moves the job to the final state, and deletes the finished jobs submitted first beyond the retention.
+func (m *Manager) finish(e *entry, result interface{}, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	e.job.FinishedAt = &now
+	switch {
+	case e.cancelled:
+		e.job.State = StateCancelled
+		if err != nil {
+			e.job.Error = err.Error()
+		}
+	case err != nil:
+		klog.Errorf("job %s of %s failed: %+v", e.job.ID, e.job.Type, err)
+		e.job.State = StateFailed
+		e.job.Error = err.Error()
+	default:
+		e.job.State = StateSucceeded
+		e.job.Result = result
+	}
+
+	finished := make([]*entry, 0, len(m.jobs))
+	for _, e := range m.jobs {
+		if e.job.Finished() {
+			finished = append(finished, e)
+		}
+	}
+	if len(finished) <= m.retention {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i].seq < finished[j].seq })
+	for _, e := range finished[:len(finished)-m.retention] {
+		delete(m.jobs, e.job.ID)
+	}
+}
+
+// Get returns the job with the id.
+func (m *Manager) Get(id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.jobs[id]
+	if !ok {
+		return nil, xerrors.Errorf("get job %s: %w", id, ErrNotFound)
+	}
+	j := e.job
+	return &j, nil
+}
+
+// List returns all the jobs kept, in the order they are submitted.
+func (m *Manager) List() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := make([]*entry, 0, len(m.jobs))
+	for _, e := range m.jobs {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].seq < entries[j].seq })
+	jobs := make([]Job, 0, len(entries))
+	for _, e := range entries {
+		jobs = append(jobs, e.job)
+	}
+	return jobs
+}
+
+// Cancel cancels the context of the job. The job becomes StateCancelled when its Func returns.
+// It returns ErrFinished if the job has already finished.
+func (m *Manager) Cancel(id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.jobs[id]
+	if !ok {
+		return nil, xerrors.Errorf("cancel job %s: %w", id, ErrNotFound)
+	}
+	if e.job.Finished() {
+		return nil, xerrors.Errorf("cancel job %s: %w", id, ErrFinished)
+	}
+	e.cancelled = true
+	e.cancel()
+	j := e.job
+	return &j, nil
+}
+
+// Shutdown cancels all the jobs pending or running, and waits for them to finish until ctx is done.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	dones := make([]chan struct{}, 0, len(m.jobs))
+	for _, e := range m.jobs {
+		if e.job.Finished() {
+			continue
+		}
+		e.cancelled = true
+		e.cancel()
+		dones = append(dones, e.done)
+	}
+	m.mu.Unlock()
+
+	for _, done := range dones {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return xerrors.Errorf("wait for the jobs to finish: %w", ctx.Err())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// slowJob is the fake job which reports the progress and waits until it's released or cancelled.
+type slowJob struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func newSlowJob() *slowJob {
+	return &slowJob{started: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (j *slowJob) run(ctx context.Context, report func(Progress)) (interface{}, error) {
+	report(Progress{Done: 1, Total: 2, Message: "waiting"})
+	close(j.started)
+	select {
+	case <-j.release:
+		return "done", nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// waitState waits until the job becomes the state.
+func waitState(t *testing.T, m *Manager, id string, state State) *Job {
+	t.Helper()
+	var got *Job
+	require.Eventually(t, func() bool {
+		j, err := m.Get(id)
+		require.NoError(t, err)
+		got = j
+		return j.State == state
+	}, wait.ForeverTestTimeout, time.Millisecond)
+	return got
+}
+
+func TestManager_lifecycle(t *testing.T) {
+	t.Parallel()
+	m := NewManager(Options{})
+	slow := newSlowJob()
+
+	submitted, err := m.Submit(TypeExport, slow.run)
+	require.NoError(t, err)
+	assert.Equal(t, StatePending, submitted.State)
+	assert.NotEmpty(t, submitted.ID)
+
+	<-slow.started
+	running := waitState(t, m, submitted.ID, StateRunning)
+	assert.Equal(t, Progress{Done: 1, Total: 2, Message: "waiting"}, running.Progress)
+	assert.NotNil(t, running.StartedAt)
+	assert.Nil(t, running.FinishedAt)
+
+	close(slow.release)
+	succeeded := waitState(t, m, submitted.ID, StateSucceeded)
+	assert.Equal(t, "done", succeeded.Result)
+	assert.NotNil(t, succeeded.FinishedAt)
+
+	_, err = m.Cancel(submitted.ID)
+	assert.ErrorIs(t, err, ErrFinished)
+	_, err = m.Get("unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestManager_failure(t *testing.T) {
+	t.Parallel()
+	m := NewManager(Options{})
+
+	submitted, err := m.Submit(TypeReset, func(context.Context, func(Progress)) (interface{}, error) {
+		return nil, xerrors.New("etcd is down")
+	})
+	require.NoError(t, err)
+
+	failed := waitState(t, m, submitted.ID, StateFailed)
+	assert.Equal(t, "etcd is down", failed.Error)
+	assert.Nil(t, failed.Result)
+}
+
+func TestManager_Cancel(t *testing.T) {
+	t.Parallel()
+	m := NewManager(Options{MaxRunning: 1})
+	running, pending := newSlowJob(), newSlowJob()
+
+	runningJob, err := m.Submit(TypeExport, running.run)
+	require.NoError(t, err)
+	<-running.started
+	// It waits for the running job since only one job can run at a time.
+	pendingJob, err := m.Submit(TypeExport, pending.run)
+	require.NoError(t, err)
+
+	_, err = m.Cancel(pendingJob.ID)
+	require.NoError(t, err)
+	cancelled := waitState(t, m, pendingJob.ID, StateCancelled)
+	assert.Nil(t, cancelled.StartedAt, "the pending job is cancelled before it starts")
+
+	_, err = m.Cancel(runningJob.ID)
+	require.NoError(t, err)
+	cancelled = waitState(t, m, runningJob.ID, StateCancelled)
+	assert.Equal(t, context.Canceled.Error(), cancelled.Error)
+	assert.NotNil(t, cancelled.StartedAt)
+}
+
+func TestManager_exclusiveTypes(t *testing.T) {
+	t.Parallel()
+	m := NewManager(Options{ExclusiveTypes: []Type{TypeReplay}})
+	replay, export := newSlowJob(), newSlowJob()
+
+	replayJob, err := m.Submit(TypeReplay, replay.run)
+	require.NoError(t, err)
+	_, err = m.Submit(TypeReplay, newSlowJob().run)
+	assert.ErrorIs(t, err, ErrConflict)
+
+	// The other types aren't affected.
+	exportJob, err := m.Submit(TypeExport, export.run)
+	require.NoError(t, err)
+	_, err = m.Submit(TypeExport, newSlowJob().run)
+	assert.NoError(t, err)
+
+	close(replay.release)
+	waitState(t, m, replayJob.ID, StateSucceeded)
+	_, err = m.Submit(TypeReplay, func(context.Context, func(Progress)) (interface{}, error) { return nil, nil })
+	assert.NoError(t, err, "another replay can start after the previous one finishes")
+
+	close(export.release)
+	waitState(t, m, exportJob.ID, StateSucceeded)
+	require.NoError(t, m.Shutdown(context.Background()))
+}
+
+func TestManager_retention(t *testing.T) {
+	t.Parallel()
+	m := NewManager(Options{Retention: 2})
+	noop := func(context.Context, func(Progress)) (interface{}, error) { return nil, nil }
+
+	ids := []string{}
+	for i := 0; i < 3; i++ {
+		j, err := m.Submit(TypeExport, noop)
+		require.NoError(t, err)
+		waitState(t, m, j.ID, StateSucceeded)
+		ids = append(ids, j.ID)
+	}
+
+	_, err := m.Get(ids[0])
+	assert.ErrorIs(t, err, ErrNotFound, "the oldest job is deleted")
+	got := []string{}
+	for _, j := range m.List() {
+		got = append(got, j.ID)
+	}
+	assert.Equal(t, ids[1:], got)
+}
+
+func TestManager_Shutdown(t *testing.T) {
+	t.Parallel()
+	m := NewManager(Options{})
+	slow := newSlowJob()
+
+	j, err := m.Submit(TypeReplay, slow.run)
+	require.NoError(t, err)
+	<-slow.started
+
+	require.NoError(t, m.Shutdown(context.Background()))
+	got, err := m.Get(j.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StateCancelled, got.State)
+}
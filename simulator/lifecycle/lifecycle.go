@@ -0,0 +1,277 @@
+// Package lifecycle manages the lifecycles of the long-running components of the simulator,
+// e.g., the syncer, the recorder and the replayer, in the same way.
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// State is the state of the component.
+type State string
+
+const (
+	// StateDisabled means the component isn't configured in the simulator. It can't be started.
+	StateDisabled State = "disabled"
+	// StateStopped means the component isn't running. It's either never started, stopped, or finished.
+	StateStopped State = "stopped"
+	// StateStarting means the component is starting, e.g., waiting for the informers to sync.
+	StateStarting State = "starting"
+	StateRunning  State = "running"
+	StatePaused   State = "paused"
+	// StateFailed means the component stopped with an error.
+	StateFailed State = "failed"
+)
+
+// Action changes the state of the component.
+type Action string
+
+const (
+	// ActionStart starts the stopped or failed component.
+	ActionStart Action = "start"
+	// ActionStop stops the starting, running or paused component.
+	ActionStop Action = "stop"
+	// ActionPause pauses the running component.
+	ActionPause Action = "pause"
+	// ActionResume resumes the paused component.
+	ActionResume Action = "resume"
+)
+
+var (
+	// ErrIllegalTransition is returned when the action isn't allowed in the current state.
+	ErrIllegalTransition = errors.New("illegal transition")
+	// ErrInvalidOptions is returned when the options to start the component are invalid.
+	ErrInvalidOptions = errors.New("invalid options")
+	// errStopped is returned from WaitResumed when the component is stopped while it's paused.
+	errStopped = errors.New("the component is stopped")
+)
+
+// Status is the status of the component.
+type Status struct {
+	State State `json:"state"`
+	// Error is the error which the component failed with.
+	Error string `json:"error,omitempty"`
+	// Since is the time when the component entered the state.
+	Since time.Time `json:"since"`
+}
+
+// RunFunc runs the component until ctx is cancelled, or until it finishes.
+// It calls ready once it has started, e.g., after the informers have synced.
+type RunFunc func(ctx context.Context, ready func()) error
+
+// PrepareFunc returns RunFunc running the component with the options given to Start.
+// It returns an error wrapping ErrInvalidOptions if the options are invalid.
+type PrepareFunc func(options json.RawMessage) (RunFunc, error)
+
+// Controller runs a component with the RunFunc from PrepareFunc, and changes its state on the actions.
+type Controller struct {
+	prepare PrepareFunc
+	now     func() time.Time
+
+	mu     sync.Mutex
+	status Status
+	// cancel cancels the context given to run. It's nil when the component isn't running.
+	cancel context.CancelFunc
+	// done is closed when run returns.
+	done chan struct{}
+	// resumed is closed unless the component is paused.
+	resumed chan struct{}
+	// stopping is closed when the component is requested to stop.
+	stopping chan struct{}
+}
+
+// NewController initializes Controller. The component is stopped at first.
+func NewController(prepare PrepareFunc) *Controller {
+	resumed := make(chan struct{})
+	close(resumed)
+	c := &Controller{prepare: prepare, now: time.Now, resumed: resumed}
+	c.status = Status{State: StateStopped, Since: c.now()}
+	return c
+}
+
+// setState changes the state. It must be called with mu held.
+func (c *Controller) setState(state State, err error) {
+	c.status = Status{State: state, Since: c.now()}
+	if err != nil {
+		c.status.Error = err.Error()
+	}
+}
+
+// illegal returns the error of the action not allowed in the current state. It must be called with mu held.
+func (c *Controller) illegal(action Action) error {
+	return xerrors.Errorf("cannot %s the %s component: %w", action, c.status.State, ErrIllegalTransition)
+}
+
+// Status returns the current status.
+func (c *Controller) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+// Start starts the component with the options in background.
+func (c *Controller) Start(options json.RawMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.status.State != StateStopped && c.status.State != StateFailed {
+		return c.illegal(ActionStart)
+	}
+	run, err := c.prepare(options)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	c.stopping = make(chan struct{})
+	c.setState(StateStarting, nil)
+
+	go func(done chan struct{}) {
+		defer close(done)
+		err := run(ctx, c.ready)
+		// The error is ignored if the component is stopped by Stop.
+		stopped := ctx.Err() != nil
+		cancel()
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.cancel = nil
+		c.resume()
+		if err != nil && !stopped {
+			c.setState(StateFailed, err)
+			return
+		}
+		c.setState(StateStopped, nil)
+	}(c.done)
+	return nil
+}
+
+// ready changes the starting component to running.
+func (c *Controller) ready() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.status.State == StateStarting {
+		c.setState(StateRunning, nil)
+	}
+}
+
+// Stop stops the component, and waits for it to stop.
+func (c *Controller) Stop() error {
+	c.mu.Lock()
+	if c.status.State != StateStarting && c.status.State != StateRunning && c.status.State != StatePaused {
+		defer c.mu.Unlock()
+		return c.illegal(ActionStop)
+	}
+	if c.cancel != nil {
+		c.cancel()
+		close(c.stopping)
+		c.cancel = nil
+	}
+	done := c.done
+	c.mu.Unlock()
+
+	<-done
+	return nil
+}
+
+// Pause pauses the running component. The component keeps running, but waits in WaitResumed until it's resumed.
+func (c *Controller) Pause() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.status.State != StateRunning {
+		return c.illegal(ActionPause)
+	}
+	c.resumed = make(chan struct{})
+	c.setState(StatePaused, nil)
+	return nil
+}
+
+// Resume resumes the paused component.
+func (c *Controller) Resume() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.status.State != StatePaused {
+		return c.illegal(ActionResume)
+	}
+	c.resume()
+	c.setState(StateRunning, nil)
+	return nil
+}
+
+// resume lets the callers of WaitResumed go. It must be called with mu held.
+func (c *Controller) resume() {
+	select {
+	case <-c.resumed:
+	default:
+		close(c.resumed)
+	}
+}
+
+// Paused returns true if the component is paused.
+func (c *Controller) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status.State == StatePaused
+}
+
+// WaitResumed waits while the component is paused.
+// It returns an error if the component is stopped while it's paused.
+func (c *Controller) WaitResumed() error {
+	c.mu.Lock()
+	resumed, stopping := c.resumed, c.stopping
+	c.mu.Unlock()
+
+	select {
+	case <-resumed:
+		return nil
+	case <-stopping:
+		return errStopped
+	}
+}
+
+// DecodeOptions decodes the options given to Start into v. The empty options are allowed.
+// It returns an error wrapping ErrInvalidOptions if the options have the unknown fields.
+func DecodeOptions(options json.RawMessage, v interface{}) error {
+	if len(bytes.TrimSpace(options)) == 0 || bytes.Equal(bytes.TrimSpace(options), []byte("null")) {
+		return nil
+	}
+	d := json.NewDecoder(bytes.NewReader(options))
+	d.DisallowUnknownFields()
+	if err := d.Decode(v); err != nil {
+		return xerrors.Errorf("decode options: %v: %w", err, ErrInvalidOptions)
+	}
+	return nil
+}
+
+// Disabled is the component which isn't configured in the simulator. All the actions are illegal.
+type Disabled struct{}
+
+// Status returns StateDisabled.
+func (Disabled) Status() Status {
+	return Status{State: StateDisabled}
+}
+
+func (Disabled) Start(json.RawMessage) error {
+	return xerrors.Errorf("cannot %s the %s component: %w", ActionStart, StateDisabled, ErrIllegalTransition)
+}
+
+func (Disabled) Stop() error {
+	return xerrors.Errorf("cannot %s the %s component: %w", ActionStop, StateDisabled, ErrIllegalTransition)
+}
+
+func (Disabled) Pause() error {
+	return xerrors.Errorf("cannot %s the %s component: %w", ActionPause, StateDisabled, ErrIllegalTransition)
+}
+
+func (Disabled) Resume() error {
+	return xerrors.Errorf("cannot %s the %s component: %w", ActionResume, StateDisabled, ErrIllegalTransition)
+}
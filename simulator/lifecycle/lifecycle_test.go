@@ -0,0 +1,144 @@
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// fakeComponent runs until it's stopped, or until finish is closed, waiting in WaitResumed for each tick.
+type fakeComponent struct {
+	*Controller
+	started chan struct{}
+	ticks   chan struct{}
+	ticked  chan struct{}
+	finish  chan error
+}
+
+func newFakeComponent() *fakeComponent {
+	f := &fakeComponent{started: make(chan struct{}, 1), ticks: make(chan struct{}), ticked: make(chan struct{}), finish: make(chan error, 1)}
+	f.Controller = NewController(func(options json.RawMessage) (RunFunc, error) {
+		if err := DecodeOptions(options, &struct{}{}); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, ready func()) error {
+			f.started <- struct{}{}
+			ready()
+			for {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case err := <-f.finish:
+					return err
+				case <-f.ticks:
+					if err := f.WaitResumed(); err != nil {
+						return err
+					}
+					f.ticked <- struct{}{}
+				}
+			}
+		}, nil
+	})
+	return f
+}
+
+func waitState(t *testing.T, c *Controller, state State) Status {
+	t.Helper()
+	var got Status
+	require.Eventually(t, func() bool {
+		got = c.Status()
+		return got.State == state
+	}, wait.ForeverTestTimeout, time.Millisecond)
+	return got
+}
+
+func TestController_transitions(t *testing.T) {
+	t.Parallel()
+	f := newFakeComponent()
+	assert.Equal(t, StateStopped, f.Status().State)
+	assert.ErrorIs(t, f.Stop(), ErrIllegalTransition)
+	assert.ErrorIs(t, f.Pause(), ErrIllegalTransition)
+	assert.ErrorIs(t, f.Resume(), ErrIllegalTransition)
+
+	require.NoError(t, f.Start(nil))
+	<-f.started
+	waitState(t, f.Controller, StateRunning)
+	assert.ErrorIs(t, f.Start(nil), ErrIllegalTransition, "the running component can't be started again")
+	assert.ErrorIs(t, f.Resume(), ErrIllegalTransition)
+
+	require.NoError(t, f.Pause())
+	assert.Equal(t, StatePaused, f.Status().State)
+	assert.ErrorIs(t, f.Pause(), ErrIllegalTransition)
+	f.ticks <- struct{}{}
+	select {
+	case <-f.ticked:
+		t.Fatal("the paused component goes on")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	require.NoError(t, f.Resume())
+	<-f.ticked
+	assert.Equal(t, StateRunning, f.Status().State)
+
+	require.NoError(t, f.Stop())
+	assert.Equal(t, StateStopped, f.Status().State)
+	assert.Empty(t, f.Status().Error, "the error from the cancelled context isn't a failure")
+
+	require.NoError(t, f.Start(json.RawMessage(`{}`)), "the stopped component can be started again")
+	<-f.started
+	waitState(t, f.Controller, StateRunning)
+	f.finish <- nil
+	waitState(t, f.Controller, StateStopped)
+}
+
+func TestController_failure(t *testing.T) {
+	t.Parallel()
+	f := newFakeComponent()
+
+	require.NoError(t, f.Start(nil))
+	<-f.started
+	f.finish <- xerrors.New("the target cluster is gone")
+	failed := waitState(t, f.Controller, StateFailed)
+	assert.Equal(t, "the target cluster is gone", failed.Error)
+
+	require.NoError(t, f.Start(nil), "the failed component can be started again")
+	<-f.started
+	assert.Empty(t, waitState(t, f.Controller, StateRunning).Error)
+	require.NoError(t, f.Stop())
+}
+
+func TestController_stopWhilePaused(t *testing.T) {
+	t.Parallel()
+	f := newFakeComponent()
+	require.NoError(t, f.Start(nil))
+	<-f.started
+	waitState(t, f.Controller, StateRunning)
+	require.NoError(t, f.Pause())
+
+	f.ticks <- struct{}{}
+	require.NoError(t, f.Stop(), "the component waiting in WaitResumed is stopped")
+	assert.Equal(t, StateStopped, f.Status().State)
+}
+
+func TestController_invalidOptions(t *testing.T) {
+	t.Parallel()
+	f := newFakeComponent()
+	assert.ErrorIs(t, f.Start(json.RawMessage(`{"unknown":true}`)), ErrInvalidOptions)
+	assert.Equal(t, StateStopped, f.Status().State)
+}
+
+func TestDisabled(t *testing.T) {
+	t.Parallel()
+	d := Disabled{}
+	assert.Equal(t, StateDisabled, d.Status().State)
+	assert.ErrorIs(t, d.Start(nil), ErrIllegalTransition)
+	assert.ErrorIs(t, d.Stop(), ErrIllegalTransition)
+	assert.ErrorIs(t, d.Pause(), ErrIllegalTransition)
+	assert.ErrorIs(t, d.Resume(), ErrIllegalTransition)
+}
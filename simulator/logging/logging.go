@@ -0,0 +1,172 @@
+// Package logging builds the loggers of the simulator from the config:
+// the format of the output, the verbosity, and the verbosity overriding it for each component.
+package logging
+
+import (
+	"flag"
+	"io"
+	"strconv"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/xerrors"
+	logsjson "k8s.io/component-base/logs/json"
+	"k8s.io/klog/v2"
+	"k8s.io/klog/v2/textlogger"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/oplog"
+)
+
+// The formats of the logs.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// maxVerbosity is the verbosity of the underlying logger writing the logs.
+// The logs are filtered with the verbosity of each logger before they reach it.
+const maxVerbosity = 127
+
+// Components are the components which can have their own verbosity.
+var Components = []string{oplog.ComponentSyncer, oplog.ComponentImporter, oplog.ComponentReplayer, oplog.ComponentRecorder, oplog.ComponentPodLifecycle}
+
+// Options configures the logs.
+type Options struct {
+	// Format is FormatText or FormatJSON. FormatText is used if it's empty.
+	Format string
+	// Verbosity is the verbosity of the logs, i.e., the info logs with the level up to it are written.
+	Verbosity int
+	// Components overrides Verbosity for the components, e.g., oplog.ComponentSyncer.
+	Components map[string]int
+}
+
+// Logging builds the root logger and the loggers of the components.
+type Logging struct {
+	// base writes the logs in the format without filtering them with the verbosity.
+	base klog.Logger
+	// buffer keeps the recent logs if it's non-nil.
+	buffer *oplog.Buffer
+	// verbosity is the verbosity of the root logger and the components without their own verbosity.
+	verbosity  atomic.Int64
+	components map[string]int
+	// klogFlags are the flags of klog. klog.V checks the verbosity of klog before the logger,
+	// which can be changed only through the flag.
+	klogFlags *flag.FlagSet
+	flush     func()
+}
+
+// New initializes Logging writing the logs to out in the format of opts.
+// The logs are added to buffer as well if it's non-nil.
+func New(opts Options, out io.Writer, buffer *oplog.Buffer) (*Logging, error) {
+	l := &Logging{buffer: buffer, components: opts.Components, flush: func() {}}
+	switch opts.Format {
+	case "", FormatText:
+		l.base = textlogger.NewLogger(textlogger.NewConfig(textlogger.Output(out), textlogger.Verbosity(maxVerbosity)))
+	case FormatJSON:
+		l.base, l.flush = newJSONLogger(out)
+	default:
+		return nil, xerrors.Errorf("unknown log format %q", opts.Format)
+	}
+
+	l.klogFlags = flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(l.klogFlags)
+	if err := l.SetVerbosity(opts.Verbosity); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// newJSONLogger returns the logger writing the logs in the JSON format of Kubernetes, and the function flushing them.
+func newJSONLogger(out io.Writer) (klog.Logger, func()) {
+	logger, control := logsjson.NewJSONLogger(maxVerbosity, zapcore.AddSync(out), nil, nil)
+	return logger, control.Flush
+}
+
+// Logger returns the root logger, which should be set to klog with klog.SetLoggerWithOptions.
+func (l *Logging) Logger() klog.Logger {
+	return l.wrap(klog.New(&levelSink{delegate: l.base.GetSink(), verbosity: l.Verbosity}))
+}
+
+// ComponentLogger returns the logger tagging the logs with component.
+// It writes the logs with the verbosity of the component if it's configured, and the one of the root logger otherwise.
+func (l *Logging) ComponentLogger(component string) klog.Logger {
+	verbosity := l.Verbosity
+	if v, ok := l.components[component]; ok {
+		verbosity = func() int { return v }
+	}
+	return l.wrap(klog.New(&levelSink{delegate: l.base.GetSink(), verbosity: verbosity})).WithName(component)
+}
+
+// wrap makes logger add the logs to the buffer as well.
+func (l *Logging) wrap(logger klog.Logger) klog.Logger {
+	if l.buffer == nil {
+		return logger
+	}
+	return oplog.NewLogger(l.buffer, logger, oplog.DefaultVerbosity)
+}
+
+// Verbosity returns the verbosity of the root logger.
+func (l *Logging) Verbosity() int {
+	return int(l.verbosity.Load())
+}
+
+// SetVerbosity changes the verbosity of the root logger and the components without their own verbosity.
+func (l *Logging) SetVerbosity(v int) error {
+	if err := l.klogFlags.Set("v", strconv.Itoa(v)); err != nil {
+		return xerrors.Errorf("set the verbosity of klog: %w", err)
+	}
+	l.verbosity.Store(int64(v))
+	return nil
+}
+
+// Flush writes the logs buffered by the logger.
+func (l *Logging) Flush() {
+	l.flush()
+}
+
+// levelSink is the LogSink writing the info logs with the level up to the verbosity with the delegate.
+type levelSink struct {
+	delegate  klog.LogSink
+	verbosity func() int
+}
+
+// callDepthLogSink is the LogSink which can skip the frames to find the caller.
+type callDepthLogSink interface {
+	WithCallDepth(depth int) klog.LogSink
+}
+
+func (s *levelSink) Init(info klog.RuntimeInfo) {
+	// the delegate is called from this sink.
+	info.CallDepth++
+	s.delegate.Init(info)
+}
+
+func (s *levelSink) Enabled(level int) bool {
+	return level <= s.verbosity() && s.delegate.Enabled(level)
+}
+
+func (s *levelSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	if level <= s.verbosity() {
+		s.delegate.Info(level, msg, keysAndValues...)
+	}
+}
+
+func (s *levelSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.delegate.Error(err, msg, keysAndValues...)
+}
+
+func (s *levelSink) WithValues(keysAndValues ...interface{}) klog.LogSink {
+	return &levelSink{delegate: s.delegate.WithValues(keysAndValues...), verbosity: s.verbosity}
+}
+
+func (s *levelSink) WithName(name string) klog.LogSink {
+	return &levelSink{delegate: s.delegate.WithName(name), verbosity: s.verbosity}
+}
+
+func (s *levelSink) WithCallDepth(depth int) klog.LogSink {
+	d, ok := s.delegate.(callDepthLogSink)
+	if !ok {
+		return s
+	}
+	return &levelSink{delegate: d.WithCallDepth(depth), verbosity: s.verbosity}
+}
@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/oplog"
+)
+
+// lines returns the non-empty lines written to out.
+func lines(out *bytes.Buffer) []string {
+	ret := []string{}
+	for _, l := range strings.Split(out.String(), "\n") {
+		if l != "" {
+			ret = append(ret, l)
+		}
+	}
+	return ret
+}
+
+//nolint:paralleltest // cannot use t.Parallel because New changes the verbosity of klog.
+func TestLogging_ComponentLogger(t *testing.T) {
+	out := &bytes.Buffer{}
+	l, err := New(Options{Verbosity: 1, Components: map[string]int{oplog.ComponentSyncer: 4, oplog.ComponentImporter: 0}}, out, nil)
+	require.NoError(t, err)
+
+	l.Logger().V(1).Info("root v1")
+	l.Logger().V(2).Info("root v2")
+	l.ComponentLogger(oplog.ComponentSyncer).V(4).Info("syncer v4")
+	l.ComponentLogger(oplog.ComponentSyncer).V(5).Info("syncer v5")
+	l.ComponentLogger(oplog.ComponentImporter).V(1).Info("importer v1")
+	l.ComponentLogger(oplog.ComponentImporter).Error(errors.New("failed"), "importer error")
+	l.ComponentLogger(oplog.ComponentRecorder).V(1).Info("recorder v1")
+
+	got := lines(out)
+	require.Len(t, got, 4, "the logs: %v", got)
+	assert.Contains(t, got[0], "root v1")
+	assert.Contains(t, got[1], `"syncer v4"`)
+	assert.Contains(t, got[1], `logger="syncer"`)
+	assert.Contains(t, got[2], "importer error", "the errors are written regardless of the verbosity")
+	assert.Contains(t, got[3], "recorder v1", "the component without its own verbosity follows the root logger")
+
+	out.Reset()
+	require.NoError(t, l.SetVerbosity(3))
+	l.Logger().V(3).Info("root v3")
+	l.ComponentLogger(oplog.ComponentRecorder).V(3).Info("recorder v3")
+	l.ComponentLogger(oplog.ComponentImporter).V(3).Info("importer v3")
+	l.ComponentLogger(oplog.ComponentSyncer).V(4).Info("syncer v4")
+	got = lines(out)
+	require.Len(t, got, 3, "the logs: %v", got)
+	assert.Contains(t, got[0], "root v3")
+	assert.Contains(t, got[1], "recorder v3")
+	assert.Contains(t, got[2], "syncer v4", "the component keeps its own verbosity")
+}
+
+//nolint:paralleltest // cannot use t.Parallel because New changes the verbosity of klog.
+func TestLogging_JSON(t *testing.T) {
+	out := &bytes.Buffer{}
+	l, err := New(Options{Format: FormatJSON, Components: map[string]int{oplog.ComponentSyncer: 2}}, out, nil)
+	require.NoError(t, err)
+
+	l.ComponentLogger(oplog.ComponentSyncer).V(2).Info("synced", "pod", "default/pod-1")
+	l.ComponentLogger(oplog.ComponentSyncer).V(3).Info("not written")
+	l.Logger().Error(errors.New("failed"), "root error")
+	l.Flush()
+
+	got := lines(out)
+	require.Len(t, got, 2, "the logs: %v", got)
+	var info map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(got[0]), &info))
+	assert.Equal(t, "synced", info["msg"])
+	assert.Equal(t, "syncer", info["logger"])
+	assert.Equal(t, "default/pod-1", info["pod"])
+	assert.InDelta(t, 2, info["v"], 0)
+
+	var errLog map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(got[1]), &errLog))
+	assert.Equal(t, "root error", errLog["msg"])
+	assert.Equal(t, "failed", errLog["err"])
+}
+
+//nolint:paralleltest // cannot use t.Parallel because New changes the verbosity of klog.
+func TestLogging_buffer(t *testing.T) {
+	buffer := oplog.NewBuffer(10)
+	l, err := New(Options{}, &bytes.Buffer{}, buffer)
+	require.NoError(t, err)
+
+	l.ComponentLogger(oplog.ComponentReplayer).V(2).Info("replayed")
+	entries := buffer.Entries(oplog.Filter{}, 0)
+	require.Len(t, entries, 1, "the buffer keeps the logs up to its own verbosity")
+	assert.Equal(t, oplog.ComponentReplayer, entries[0].Component)
+	assert.Equal(t, "replayed", entries[0].Message)
+}
+
+//nolint:paralleltest // cannot use t.Parallel because New changes the verbosity of klog.
+func TestNew_unknownFormat(t *testing.T) {
+	_, err := New(Options{Format: "yaml"}, &bytes.Buffer{}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,191 @@
+// Package nodefailure injects the failures of the nodes in the simulator, e.g., to see how the Pods are rescheduled.
+// Since the simulator doesn't run kube-controller-manager, it does what the node lifecycle controller does on the failures instead.
+package nodefailure
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"golang.org/x/xerrors"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// failureReason is the reason of the Ready condition of the failed nodes.
+	failureReason = "SimulatedNodeFailure"
+	// recoveryReason is the reason of the Ready condition of the recovered nodes.
+	recoveryReason = "SimulatedNodeRecovery"
+)
+
+// ErrNodeNotFound is returned when the node doesn't exist.
+var ErrNodeNotFound = errors.New("node not found")
+
+// FailOptions is the options of the failure of a node.
+type FailOptions struct {
+	// NoExecute adds the NoExecute taint of node.kubernetes.io/not-ready as well as the NoSchedule one.
+	NoExecute bool `json:"noExecute,omitempty"`
+	// EvictPods deletes the Pods on the node which don't tolerate the NoExecute taint,
+	// as the node lifecycle controller does, so that the user or the replayer can recreate them.
+	// The Pods tolerating the taint only for TolerationSeconds are deleted as well since the time doesn't pass in the simulator.
+	EvictPods bool `json:"evictPods,omitempty"`
+}
+
+// Result is the node after the failure or the recovery.
+type Result struct {
+	Node *v1.Node `json:"node"`
+	// EvictedPods is the Pods deleted from the node, as "namespace/name".
+	EvictedPods []string `json:"evictedPods,omitempty"`
+}
+
+// Service injects the failures of the nodes.
+type Service struct {
+	client clientset.Interface
+}
+
+// NewService initializes Service.
+func NewService(client clientset.Interface) *Service {
+	return &Service{client: client}
+}
+
+// Fail makes the node NotReady, and taints it with the NoSchedule taint of node.kubernetes.io/not-ready
+// so that no Pod is scheduled to it, as the node lifecycle controller does when the kubelet stops working.
+// It returns an error wrapping ErrNodeNotFound if the node doesn't exist.
+func (s *Service) Fail(ctx context.Context, name string, opts FailOptions) (*Result, error) {
+	effects := []v1.TaintEffect{v1.TaintEffectNoSchedule}
+	if opts.NoExecute {
+		effects = append(effects, v1.TaintEffectNoExecute)
+	}
+	node, err := s.update(ctx, name, v1.ConditionFalse, failureReason, "The failure of the node is injected by the simulator.", func(taints []v1.Taint) []v1.Taint {
+		now := metav1.Now()
+		for _, effect := range effects {
+			taints = addTaint(taints, v1.Taint{Key: v1.TaintNodeNotReady, Effect: effect, TimeAdded: &now})
+		}
+		return taints
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Node: node}
+	if opts.EvictPods {
+		result.EvictedPods, err = s.evictPods(ctx, name)
+		if err != nil {
+			return nil, xerrors.Errorf("evict pods on node %s: %w", name, err)
+		}
+	}
+	return result, nil
+}
+
+// Recover makes the node Ready, and removes the taints of node.kubernetes.io/not-ready.
+// The evicted Pods aren't recreated.
+// It returns an error wrapping ErrNodeNotFound if the node doesn't exist.
+func (s *Service) Recover(ctx context.Context, name string) (*Result, error) {
+	node, err := s.update(ctx, name, v1.ConditionTrue, recoveryReason, "The node is recovered by the simulator.", func(taints []v1.Taint) []v1.Taint {
+		ret := make([]v1.Taint, 0, len(taints))
+		for _, t := range taints {
+			if t.Key != v1.TaintNodeNotReady {
+				ret = append(ret, t)
+			}
+		}
+		return ret
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Node: node}, nil
+}
+
+// update changes the taints of the node with mutateTaints, and then its Ready condition to ready.
+func (s *Service) update(ctx context.Context, name string, ready v1.ConditionStatus, reason, message string, mutateTaints func([]v1.Taint) []v1.Taint) (*v1.Node, error) {
+	var node *v1.Node
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		n, err := s.client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		n.Spec.Taints = mutateTaints(n.Spec.Taints)
+		n, err = s.client.CoreV1().Nodes().Update(ctx, n, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+
+		setCondition(&n.Status, v1.NodeCondition{Type: v1.NodeReady, Status: ready, Reason: reason, Message: message})
+		node, err = s.client.CoreV1().Nodes().UpdateStatus(ctx, n, metav1.UpdateOptions{})
+		return err
+	})
+	if apierrors.IsNotFound(err) {
+		return nil, xerrors.Errorf("node %s: %w", name, ErrNodeNotFound)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("update node %s: %w", name, err)
+	}
+	return node, nil
+}
+
+// evictPods deletes the Pods on the node which don't tolerate the NoExecute taint of node.kubernetes.io/not-ready forever.
+// It returns the deleted Pods as "namespace/name".
+func (s *Service) evictPods(ctx context.Context, nodeName string) ([]string, error) {
+	pods, err := s.client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, xerrors.Errorf("list pods: %w", err)
+	}
+	taint := v1.Taint{Key: v1.TaintNodeNotReady, Effect: v1.TaintEffectNoExecute}
+	evicted := []string{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != nodeName || pod.DeletionTimestamp != nil || toleratesForever(pod.Spec.Tolerations, &taint) {
+			continue
+		}
+		err := s.client.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{Preconditions: metav1.NewUIDPreconditions(string(pod.UID))})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, xerrors.Errorf("delete pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		evicted = append(evicted, pod.Namespace+"/"+pod.Name)
+	}
+	sort.Strings(evicted)
+	return evicted, nil
+}
+
+// toleratesForever returns true if any of tolerations tolerates taint without TolerationSeconds.
+func toleratesForever(tolerations []v1.Toleration, taint *v1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].TolerationSeconds == nil && tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// addTaint adds taint to taints unless the taint with the same key and effect exists.
+func addTaint(taints []v1.Taint, taint v1.Taint) []v1.Taint {
+	for _, t := range taints {
+		if t.MatchTaint(&taint) {
+			return taints
+		}
+	}
+	return append(taints, taint)
+}
+
+// setCondition sets condition to status, replacing the one of the same type.
+// The transition time is changed only if the status of the condition is changed.
+func setCondition(status *v1.NodeStatus, condition v1.NodeCondition) {
+	now := metav1.Now()
+	condition.LastHeartbeatTime = now
+	condition.LastTransitionTime = now
+	for i := range status.Conditions {
+		if status.Conditions[i].Type != condition.Type {
+			continue
+		}
+		if status.Conditions[i].Status == condition.Status {
+			condition.LastTransitionTime = status.Conditions[i].LastTransitionTime
+		}
+		status.Conditions[i] = condition
+		return
+	}
+	status.Conditions = append(status.Conditions, condition)
+}
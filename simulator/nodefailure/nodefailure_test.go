@@ -0,0 +1,160 @@
+package nodefailure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func newNode(taints ...v1.Taint) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       v1.NodeSpec{Taints: taints},
+		Status: v1.NodeStatus{Conditions: []v1.NodeCondition{
+			{Type: v1.NodeMemoryPressure, Status: v1.ConditionFalse},
+			{Type: v1.NodeReady, Status: v1.ConditionTrue},
+		}},
+	}
+}
+
+func newPod(name, nodeName string, tolerations ...v1.Toleration) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       v1.PodSpec{NodeName: nodeName, Tolerations: tolerations},
+	}
+}
+
+func readyCondition(t *testing.T, node *v1.Node) v1.NodeCondition {
+	t.Helper()
+	for _, c := range node.Status.Conditions {
+		if c.Type == v1.NodeReady {
+			return c
+		}
+	}
+	t.Fatalf("node %s doesn't have the Ready condition", node.Name)
+	return v1.NodeCondition{}
+}
+
+// taintEffects returns the effects of the taints with key.
+func taintEffects(node *v1.Node, key string) []v1.TaintEffect {
+	var effects []v1.TaintEffect
+	for _, t := range node.Spec.Taints {
+		if t.Key == key {
+			effects = append(effects, t.Effect)
+		}
+	}
+	return effects
+}
+
+func TestService_Fail(t *testing.T) {
+	t.Parallel()
+	custom := v1.Taint{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule}
+	tests := []struct {
+		name            string
+		opts            FailOptions
+		objects         []runtime.Object
+		wantEffects     []v1.TaintEffect
+		wantEvictedPods []string
+		wantRemaining   []string
+	}{
+		{
+			name:          "the node becomes NotReady and unschedulable, and its pods remain",
+			objects:       []runtime.Object{newNode(custom), newPod("pod-1", "node-1")},
+			wantEffects:   []v1.TaintEffect{v1.TaintEffectNoSchedule},
+			wantRemaining: []string{"pod-1"},
+		},
+		{
+			name:        "the NoExecute taint is added with noExecute",
+			opts:        FailOptions{NoExecute: true},
+			objects:     []runtime.Object{newNode(custom)},
+			wantEffects: []v1.TaintEffect{v1.TaintEffectNoSchedule, v1.TaintEffectNoExecute},
+		},
+		{
+			name: "the pods not tolerating the taint forever are evicted with evictPods",
+			opts: FailOptions{NoExecute: true, EvictPods: true},
+			objects: []runtime.Object{
+				newNode(custom),
+				newPod("pod-1", "node-1"),
+				newPod("tolerating", "node-1", v1.Toleration{Key: v1.TaintNodeNotReady, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoExecute}),
+				newPod("tolerating-for-a-while", "node-1", v1.Toleration{Key: v1.TaintNodeNotReady, Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoExecute, TolerationSeconds: ptr.To[int64](300)}),
+				newPod("other-node", "node-2"),
+				newPod("unscheduled", ""),
+			},
+			wantEffects:     []v1.TaintEffect{v1.TaintEffectNoSchedule, v1.TaintEffectNoExecute},
+			wantEvictedPods: []string{"default/pod-1", "default/tolerating-for-a-while"},
+			wantRemaining:   []string{"other-node", "tolerating", "unscheduled"},
+		},
+		{
+			name:        "the taints already added aren't duplicated",
+			objects:     []runtime.Object{newNode(custom, v1.Taint{Key: v1.TaintNodeNotReady, Effect: v1.TaintEffectNoSchedule})},
+			wantEffects: []v1.TaintEffect{v1.TaintEffectNoSchedule},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			client := fake.NewSimpleClientset(tt.objects...)
+			s := NewService(client)
+
+			result, err := s.Fail(ctx, "node-1", tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEvictedPods, result.EvictedPods)
+
+			node, err := client.CoreV1().Nodes().Get(ctx, "node-1", metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, node, result.Node)
+			ready := readyCondition(t, node)
+			assert.Equal(t, v1.ConditionFalse, ready.Status)
+			assert.Equal(t, failureReason, ready.Reason)
+			assert.Len(t, node.Status.Conditions, 2, "the other conditions are kept")
+			assert.Equal(t, tt.wantEffects, taintEffects(node, v1.TaintNodeNotReady))
+			assert.Contains(t, node.Spec.Taints, custom, "the other taints are kept")
+
+			pods, err := client.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+			require.NoError(t, err)
+			var remaining []string
+			for _, p := range pods.Items {
+				remaining = append(remaining, p.Name)
+			}
+			assert.ElementsMatch(t, tt.wantRemaining, remaining)
+		})
+	}
+}
+
+func TestService_Recover(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	custom := v1.Taint{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule}
+	client := fake.NewSimpleClientset(newNode(custom))
+	s := NewService(client)
+	_, err := s.Fail(ctx, "node-1", FailOptions{NoExecute: true})
+	require.NoError(t, err)
+
+	result, err := s.Recover(ctx, "node-1")
+	require.NoError(t, err)
+	ready := readyCondition(t, result.Node)
+	assert.Equal(t, v1.ConditionTrue, ready.Status)
+	assert.Equal(t, recoveryReason, ready.Reason)
+	assert.Equal(t, []v1.Taint{custom}, result.Node.Spec.Taints)
+	assert.Empty(t, result.EvictedPods)
+}
+
+func TestService_nodeNotFound(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	s := NewService(fake.NewSimpleClientset())
+
+	_, err := s.Fail(ctx, "node-1", FailOptions{})
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+	_, err = s.Recover(ctx, "node-1")
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+}
@@ -0,0 +1,141 @@
+package oneshotimporter
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DiffReport describes the differences between the target cluster and the simulator.
+type DiffReport struct {
+	// Resources has the differences per resource, in the order of the resources imported.
+	Resources []ResourceDiff `json:"resources"`
+}
+
+// ResourceDiff describes the differences of a resource, e.g. "pods", between the target cluster and the simulator.
+type ResourceDiff struct {
+	Resource string `json:"resource"`
+	// MissingInSimulator has the objects which exist only in the target cluster, as "namespace/name" or "name".
+	MissingInSimulator []string `json:"missingInSimulator,omitempty"`
+	// MissingInSource has the objects which exist only in the simulator, as "namespace/name" or "name".
+	MissingInSource []string `json:"missingInSource,omitempty"`
+	// Different has the objects which exist in both but whose contents are different.
+	Different []ObjectDiff `json:"different,omitempty"`
+}
+
+// ObjectDiff describes the difference of an object between the target cluster and the simulator.
+type ObjectDiff struct {
+	Name string `json:"name"`
+	// Diff is the human-readable difference, where "-" is the target cluster and "+" is the simulator.
+	Diff string `json:"diff"`
+}
+
+// InSync returns true if no differences are found.
+func (r *DiffReport) InSync() bool {
+	for _, d := range r.Resources {
+		if len(d.MissingInSimulator) != 0 || len(d.MissingInSource) != 0 || len(d.Different) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff compares the resources in the target cluster and the ones in the simulator.
+// Objects are matched by namespace and name, and compared without their status and metadata other than labels.
+// The objects from the target cluster are compared after being mutated in the same way as importing,
+// and the ones filtered out on importing are ignored.
+func (s *Service) Diff(ctx context.Context) (*DiffReport, error) {
+	report := &DiffReport{Resources: make([]ResourceDiff, 0, len(s.gvrs))}
+	for _, gvr := range s.gvrs {
+		d, err := s.diffResource(ctx, gvr)
+		if err != nil {
+			return nil, xerrors.Errorf("diff resource %s: %w", gvr.String(), err)
+		}
+		report.Resources = append(report.Resources, *d)
+	}
+
+	return report, nil
+}
+
+func (s *Service) diffResource(ctx context.Context, gvr schema.GroupVersionResource) (*ResourceDiff, error) {
+	srcResources, err := s.listResources(ctx, gvr, ImportOptions{})
+	if err != nil {
+		return nil, xerrors.Errorf("list resources in the target cluster: %w", err)
+	}
+	destResources, err := s.resouceApplierService.Clients().DynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, xerrors.Errorf("list resources in the simulator: %w", err)
+	}
+
+	src := map[string]*unstructured.Unstructured{}
+	for i := range srcResources.Items {
+		prepared, ok, err := s.resouceApplierService.PrepareForCreating(ctx, srcResources.Items[i].DeepCopy())
+		if err != nil {
+			return nil, xerrors.Errorf("prepare %s for comparison: %w", objectKey(&srcResources.Items[i]), err)
+		}
+		if !ok {
+			// It's not supposed to be imported.
+			continue
+		}
+		src[objectKey(prepared)] = prepared
+	}
+	dest := map[string]*unstructured.Unstructured{}
+	for i := range destResources.Items {
+		dest[objectKey(&destResources.Items[i])] = &destResources.Items[i]
+	}
+
+	d := &ResourceDiff{Resource: gvr.GroupResource().String()}
+	for _, key := range sortedKeys(src) {
+		destObj, ok := dest[key]
+		if !ok {
+			d.MissingInSimulator = append(d.MissingInSimulator, key)
+			continue
+		}
+		if diff := cmp.Diff(normalizeForDiff(src[key]), normalizeForDiff(destObj)); diff != "" {
+			d.Different = append(d.Different, ObjectDiff{Name: key, Diff: diff})
+		}
+	}
+	for _, key := range sortedKeys(dest) {
+		if _, ok := src[key]; !ok {
+			d.MissingInSource = append(d.MissingInSource, key)
+		}
+	}
+
+	return d, nil
+}
+
+// normalizeForDiff returns the content of the object to compare,
+// which drops the status and the metadata except labels because they are different between clusters anyway.
+func normalizeForDiff(obj *unstructured.Unstructured) map[string]interface{} {
+	normalized := obj.DeepCopy().Object
+	delete(normalized, "status")
+	delete(normalized, "metadata")
+	if labels := obj.GetLabels(); len(labels) != 0 {
+		normalized["metadata"] = map[string]interface{}{"labels": labels}
+	}
+	return normalized
+}
+
+// objectKey returns "namespace/name" for a namespaced object and "name" for a cluster-scoped one.
+func objectKey(obj *unstructured.Unstructured) string {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return obj.GetNamespace() + "/" + obj.GetName()
+	}
+	return key
+}
+
+func sortedKeys(m map[string]*unstructured.Unstructured) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,133 @@
+package oneshotimporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/klog/v2"
+	scheduling "k8s.io/kubernetes/pkg/apis/scheduling/v1"
+	storage "k8s.io/kubernetes/pkg/apis/storage/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+)
+
+func TestService_Diff(t *testing.T) {
+	t.Parallel()
+
+	podGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	nodeGVR := schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+
+	tests := []struct {
+		name       string
+		drift      func(t *testing.T, destClient dynamic.Interface)
+		wantInSync bool
+		wantPods   ResourceDiff
+		wantNodes  ResourceDiff
+	}{
+		{
+			name:       "no differences right after importing",
+			drift:      func(_ *testing.T, _ dynamic.Interface) {},
+			wantInSync: true,
+			wantPods:   ResourceDiff{Resource: "pods"},
+			wantNodes:  ResourceDiff{Resource: "nodes"},
+		},
+		{
+			name: "report missing and different objects",
+			drift: func(t *testing.T, destClient dynamic.Interface) {
+				ctx := context.Background()
+				// pod2 is missing in the simulator.
+				assert.NoError(t, destClient.Resource(podGVR).Namespace("default").Delete(ctx, "pod2", metav1.DeleteOptions{}))
+				// pod4 is missing in the target cluster.
+				_, err := destClient.Resource(podGVR).Namespace("default").Create(ctx, podWithNameAndLabel("pod4", nil), metav1.CreateOptions{})
+				assert.NoError(t, err)
+				// pod3 has a different image.
+				pod3, err := destClient.Resource(podGVR).Namespace("default").Get(ctx, "pod3", metav1.GetOptions{})
+				assert.NoError(t, err)
+				assert.NoError(t, unstructured.SetNestedSlice(pod3.Object, []interface{}{
+					map[string]interface{}{"name": "test-container", "image": "another-image"},
+				}, "spec", "containers"))
+				_, err = destClient.Resource(podGVR).Namespace("default").Update(ctx, pod3, metav1.UpdateOptions{})
+				assert.NoError(t, err)
+				// node has a different label.
+				node, err := destClient.Resource(nodeGVR).Get(ctx, "node", metav1.GetOptions{})
+				assert.NoError(t, err)
+				node.SetLabels(map[string]string{"zone": "b"})
+				// The annotation is ignored.
+				node.SetAnnotations(map[string]string{"foo": "bar"})
+				_, err = destClient.Resource(nodeGVR).Update(ctx, node, metav1.UpdateOptions{})
+				assert.NoError(t, err)
+			},
+			wantInSync: false,
+			wantPods: ResourceDiff{
+				Resource:           "pods",
+				MissingInSimulator: []string{"default/pod2"},
+				MissingInSource:    []string{"default/pod4"},
+				Different:          []ObjectDiff{{Name: "default/pod3"}},
+			},
+			wantNodes: ResourceDiff{
+				Resource:  "nodes",
+				Different: []ObjectDiff{{Name: "node"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := runtime.NewScheme()
+			v1.AddToScheme(s)
+			storage.AddToScheme(s)
+			scheduling.AddToScheme(s)
+			srcClient := fake.NewSimpleDynamicClient(s)
+			destClient := fake.NewSimpleDynamicClient(s)
+			applier := resourceapplier.New(destClient, mapper, resourceapplier.Options{})
+			oneshotImporter := NewService(srcClient, applier, nil, klog.Background())
+
+			node := nodeWithName("node")
+			node.SetLabels(map[string]string{"zone": "a"})
+			_, err := srcClient.Resource(nodeGVR).Create(context.Background(), node, metav1.CreateOptions{})
+			assert.NoError(t, err)
+			for _, name := range []string{"pod1", "pod2", "pod3"} {
+				_, err := srcClient.Resource(podGVR).Namespace("default").Create(context.Background(), podWithNameAndLabel(name, nil), metav1.CreateOptions{})
+				assert.NoError(t, err)
+			}
+			_, err = oneshotImporter.ImportClusterResources(context.Background(), ImportOptions{})
+			assert.NoError(t, err)
+
+			tt.drift(t, destClient)
+			report, err := oneshotImporter.Diff(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantInSync, report.InSync())
+			for _, d := range report.Resources {
+				var want ResourceDiff
+				switch d.Resource {
+				case "pods":
+					want = tt.wantPods
+				case "nodes":
+					want = tt.wantNodes
+				default:
+					assert.Equal(t, ResourceDiff{Resource: d.Resource}, d)
+					continue
+				}
+				assert.Equal(t, want.MissingInSimulator, d.MissingInSimulator)
+				assert.Equal(t, want.MissingInSource, d.MissingInSource)
+				assert.Len(t, d.Different, len(want.Different))
+				for i := range want.Different {
+					assert.Equal(t, want.Different[i].Name, d.Different[i].Name)
+					assert.NotEmpty(t, d.Different[i].Diff)
+				}
+			}
+		})
+	}
+}
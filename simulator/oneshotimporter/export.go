@@ -0,0 +1,131 @@
+package oneshotimporter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcefilter"
+)
+
+// statusPreservedGVRs is a set of GVRs whose status is kept when exporting,
+// because the status is taken into account when the resources are imported or scheduled.
+var statusPreservedGVRs = map[schema.GroupVersionResource]bool{
+	{Group: "", Version: "v1", Resource: "nodes"}:                  true,
+	{Group: "", Version: "v1", Resource: "persistentvolumes"}:      true,
+	{Group: "", Version: "v1", Resource: "persistentvolumeclaims"}: true,
+}
+
+// ExportClusterResources gets resources from the target cluster
+// and writes them to dir instead of applying them to the simulator.
+// Resources of each GVR are written to one JSON file, which can be imported later with ImportFromFiles.
+func (s *Service) ExportClusterResources(ctx context.Context, dir string, opts ImportOptions) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return xerrors.Errorf("create directory %s: %w", dir, err)
+	}
+
+	for _, gvr := range s.gvrs {
+		resources, err := s.listResources(ctx, gvr, opts)
+		if err != nil {
+			return xerrors.Errorf("export resource %s: %w", gvr.String(), err)
+		}
+
+		items := make([]unstructured.Unstructured, 0, len(resources.Items))
+		for i := range resources.Items {
+			// The phase of pods is dropped on exporting, so completed pods have to be filtered out here.
+			if !opts.IncludeCompletedPods && resourcefilter.IsCompletedOrTerminatingPod(&resources.Items[i]) {
+				continue
+			}
+			sanitizeForExport(gvr, &resources.Items[i])
+			items = append(items, resources.Items[i])
+		}
+		resources.Items = items
+		// The metadata of the list (e.g., resourceVersion) is meaningless outside the target cluster.
+		resources.SetResourceVersion("")
+		resources.SetContinue("")
+
+		data, err := resources.MarshalJSON()
+		if err != nil {
+			return xerrors.Errorf("marshal resources %s: %w", gvr.String(), err)
+		}
+		if err := os.WriteFile(exportFilePath(dir, gvr), data, 0o600); err != nil {
+			return xerrors.Errorf("write resources %s: %w", gvr.String(), err)
+		}
+		s.logger.Info("Exported resources", "resource", gvr.String(), "count", len(resources.Items))
+	}
+
+	return nil
+}
+
+// ImportFromFiles applies resources written by ExportClusterResources in dir to the simulator.
+// GVRs whose file doesn't exist in dir are skipped.
+func (s *Service) ImportFromFiles(ctx context.Context, dir string, opts ImportOptions) (*ImportSummary, error) {
+	opts, err := completeImportOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&opts.LabelSelector)
+	if err != nil {
+		return nil, xerrors.Errorf("convert label selector: %w", err)
+	}
+
+	recorder := newSummaryRecorder()
+	for _, gvr := range s.gvrs {
+		resources, err := readExportFile(exportFilePath(dir, gvr), selector, opts.Namespaces)
+		if errors.Is(err, os.ErrNotExist) {
+			s.logger.V(2).Info("Skipped to import resources because the file isn't found", "resource", gvr.String())
+			continue
+		}
+		if err != nil {
+			return recorder.result(), xerrors.Errorf("read resources %s: %w", gvr.String(), err)
+		}
+
+		if err := s.applyResources(ctx, resources, opts, recorder); err != nil {
+			return recorder.result(), xerrors.Errorf("import resource %s: %w", gvr.String(), err)
+		}
+	}
+
+	return recorder.result(), nil
+}
+
+// readExportFile reads the resources written by ExportClusterResources and filters them with the selector and the namespaces.
+func readExportFile(path string, selector labels.Selector, namespaces []string) ([]unstructured.Unstructured, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &unstructured.UnstructuredList{}
+	if err := list.UnmarshalJSON(data); err != nil {
+		return nil, xerrors.Errorf("unmarshal %s: %w", path, err)
+	}
+
+	resources := make([]unstructured.Unstructured, 0, len(list.Items))
+	for _, r := range list.Items {
+		if selector.Matches(labels.Set(r.GetLabels())) && matchNamespaces(&r, namespaces) {
+			resources = append(resources, r)
+		}
+	}
+
+	return resources, nil
+}
+
+// sanitizeForExport removes the fields which are useless outside the target cluster.
+func sanitizeForExport(gvr schema.GroupVersionResource, resource *unstructured.Unstructured) {
+	resource.SetManagedFields(nil)
+	if !statusPreservedGVRs[gvr] {
+		unstructured.RemoveNestedField(resource.Object, "status")
+	}
+}
+
+// exportFilePath returns the path to the file which has the resources of the GVR.
+func exportFilePath(dir string, gvr schema.GroupVersionResource) string {
+	return filepath.Join(dir, gvr.GroupResource().String()+".json")
+}
@@ -0,0 +1,170 @@
+package oneshotimporter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/klog/v2"
+	scheduling "k8s.io/kubernetes/pkg/apis/scheduling/v1"
+	storage "k8s.io/kubernetes/pkg/apis/storage/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+)
+
+func TestService_ExportClusterResources_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                 string
+		labelSelector        metav1.LabelSelector
+		srcObjects           []*unstructured.Unstructured
+		wantImportedObjects  []*unstructured.Unstructured
+		wantNotImportedNames []string
+	}{
+		{
+			name: "export and import all resources",
+			srcObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("pod", map[string]string{"app": "test"}),
+				podWithNameAndLabel("pod2", nil),
+				nodeWithName("node"),
+			},
+			wantImportedObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("pod", map[string]string{"app": "test"}),
+				podWithNameAndLabel("pod2", nil),
+				nodeWithName("node"),
+			},
+		},
+		{
+			name: "export resources filtered with label selector",
+			labelSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "test"},
+			},
+			srcObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("pod", map[string]string{"app": "test"}),
+				podWithNameAndLabel("pod2", nil),
+			},
+			wantImportedObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("pod", map[string]string{"app": "test"}),
+			},
+			wantNotImportedNames: []string{"pod2"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := runtime.NewScheme()
+			v1.AddToScheme(s)
+			storage.AddToScheme(s)
+			scheduling.AddToScheme(s)
+			srcClient := fake.NewSimpleDynamicClient(s)
+			destClient := fake.NewSimpleDynamicClient(s)
+			// directDestClient is the destination of the direct import, which the import from files should match.
+			directDestClient := fake.NewSimpleDynamicClient(s)
+			exporter := NewService(srcClient, resourceapplier.New(directDestClient, mapper, resourceapplier.Options{}), nil, klog.Background())
+			importer := NewService(nil, resourceapplier.New(destClient, mapper, resourceapplier.Options{}), nil, klog.Background())
+			for _, obj := range tt.srcObjects {
+				gvr, err := findGVR(obj)
+				assert.NoError(t, err)
+				_, err = srcClient.Resource(gvr).Namespace(obj.GetNamespace()).Create(context.Background(), obj, metav1.CreateOptions{})
+				assert.NoError(t, err)
+			}
+			dir := t.TempDir()
+
+			err := exporter.ExportClusterResources(context.Background(), dir, ImportOptions{LabelSelector: tt.labelSelector})
+			assert.NoError(t, err)
+			summary, err := importer.ImportFromFiles(context.Background(), dir, ImportOptions{})
+			assert.NoError(t, err)
+			_, err = exporter.ImportClusterResources(context.Background(), ImportOptions{LabelSelector: tt.labelSelector})
+			assert.NoError(t, err)
+
+			assert.Equal(t, len(tt.wantImportedObjects), summary.Created)
+			assert.Equal(t, 0, summary.Failed)
+			for _, want := range tt.wantImportedObjects {
+				gvr, err := findGVR(want)
+				assert.NoError(t, err)
+				got, err := destClient.Resource(gvr).Namespace(want.GetNamespace()).Get(context.Background(), want.GetName(), metav1.GetOptions{})
+				assert.NoError(t, err)
+				directlyImported, err := directDestClient.Resource(gvr).Namespace(want.GetNamespace()).Get(context.Background(), want.GetName(), metav1.GetOptions{})
+				assert.NoError(t, err)
+				assert.Equal(t, want.GetLabels(), got.GetLabels())
+				// The run IDs are different between the two imports.
+				assert.NotEmpty(t, got.GetAnnotations()[ImportRunIDAnnotationKey])
+				unstructured.RemoveNestedField(got.Object, "metadata", "annotations", ImportRunIDAnnotationKey)
+				unstructured.RemoveNestedField(directlyImported.Object, "metadata", "annotations", ImportRunIDAnnotationKey)
+				assert.Equal(t, directlyImported.Object, got.Object)
+			}
+			for _, name := range tt.wantNotImportedNames {
+				_, err := destClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "pods"}).Namespace("default").Get(context.Background(), name, metav1.GetOptions{})
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestService_ExportClusterResources_Sanitize(t *testing.T) {
+	t.Parallel()
+
+	s := runtime.NewScheme()
+	v1.AddToScheme(s)
+	storage.AddToScheme(s)
+	scheduling.AddToScheme(s)
+	srcClient := fake.NewSimpleDynamicClient(s)
+	exporter := NewService(srcClient, resourceapplier.New(fake.NewSimpleDynamicClient(s), mapper, resourceapplier.Options{}), nil, klog.Background())
+
+	pod := podWithNameAndLabel("pod", nil)
+	pod.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl"}})
+	pod.Object["status"] = map[string]interface{}{"phase": "Running"}
+	node := nodeWithName("node")
+	node.Object["status"] = map[string]interface{}{"allocatable": map[string]interface{}{"cpu": "4"}}
+	for _, obj := range []*unstructured.Unstructured{pod, node} {
+		gvr, err := findGVR(obj)
+		assert.NoError(t, err)
+		_, err = srcClient.Resource(gvr).Namespace(obj.GetNamespace()).Create(context.Background(), obj, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	dir := t.TempDir()
+
+	err := exporter.ExportClusterResources(context.Background(), dir, ImportOptions{})
+	assert.NoError(t, err)
+
+	selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{})
+	assert.NoError(t, err)
+	pods, err := readExportFile(filepath.Join(dir, "pods.json"), selector, nil)
+	assert.NoError(t, err)
+	assert.Len(t, pods, 1)
+	assert.Nil(t, pods[0].GetManagedFields())
+	assert.NotContains(t, pods[0].Object, "status")
+	nodes, err := readExportFile(filepath.Join(dir, "nodes.json"), selector, nil)
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 1)
+	assert.Equal(t, node.Object["status"], nodes[0].Object["status"])
+	_, err = os.Stat(filepath.Join(dir, "priorityclasses.scheduling.k8s.io.json"))
+	assert.NoError(t, err)
+}
+
+func nodeWithName(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Node",
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+			"spec": map[string]interface{}{
+				"unschedulable": false,
+			},
+		},
+	}
+}
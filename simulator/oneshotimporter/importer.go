@@ -1,18 +1,29 @@
 package oneshotimporter
 
+//go:generate mockgen -destination=./mock_$GOPACKAGE/scheduler.go . SchedulerService
+
 import (
 	"context"
-	"fmt"
+	"errors"
+	"os"
 	"sync"
 
 	"golang.org/x/xerrors"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/klog/v2"
+	configv1 "k8s.io/kube-scheduler/config/v1"
 
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcefilter"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
 )
 
 // Service has two ReplicateServices.
@@ -21,7 +32,29 @@ import (
 type Service struct {
 	srcDynamicClient      dynamic.Interface
 	resouceApplierService *resourceapplier.Service
+	schedulerService      SchedulerService
 	gvrs                  []schema.GroupVersionResource
+	// manifestsPath is the path to the manifests used as the source instead of the target cluster.
+	manifestsPath string
+
+	// statusMu guards status and recorder.
+	statusMu sync.Mutex
+	// status is the status of the latest import.
+	status ImportStatus
+	// recorder records the outcome of the latest import.
+	recorder *summaryRecorder
+	// cancel cancels the latest import started by StartImport, and done is closed when it finishes.
+	// They are nil until StartImport starts any import.
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	logger klog.Logger
+}
+
+// SchedulerService is used to apply the scheduler configuration imported from the target cluster.
+type SchedulerService interface {
+	GetSchedulerConfig() (*configv1.KubeSchedulerConfiguration, error)
+	RestartScheduler(cfg *configv1.KubeSchedulerConfiguration) error
 }
 
 // DefaultGVRs is a list of GroupVersionResource that we import.
@@ -36,8 +69,157 @@ var DefaultGVRs = []schema.GroupVersionResource{
 	{Group: "", Version: "v1", Resource: "pods"},
 }
 
-// NewService initializes Service.
-func NewService(srcClient dynamic.Interface, resourceApplier *resourceapplier.Service) *Service {
+const (
+	// DefaultSchedulerConfigMapNamespace is the namespace of the ConfigMap which has the scheduler configuration by default.
+	DefaultSchedulerConfigMapNamespace = "kube-system"
+	// DefaultSchedulerConfigMapName is the name of the ConfigMap which has the scheduler configuration by default.
+	DefaultSchedulerConfigMapName = "kube-scheduler-config"
+)
+
+const (
+	// ImportRunIDAnnotationKey is the annotation key for the ID of the import which applied the resource.
+	ImportRunIDAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/import-run-id"
+	// SourceUIDAnnotationKey is the annotation key for the UID of the source object in the target cluster.
+	SourceUIDAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/source-uid"
+	// SourceResourceVersionAnnotationKey is the annotation key for the resourceVersion of the source object in the target cluster.
+	SourceResourceVersionAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/source-resource-version"
+)
+
+var configMapGVR = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"}
+
+var (
+	// ErrImportInProgress is returned when an import is requested while another import is running.
+	ErrImportInProgress = errors.New("another import is in progress")
+	// ErrInvalidImportOptions is returned when the given ImportOptions is invalid.
+	ErrInvalidImportOptions = errors.New("invalid import options")
+	// ErrTooManyResources is returned when the target cluster has more resources to import than the threshold.
+	ErrTooManyResources = errors.New("too many resources to import")
+)
+
+// DefaultResourceCountThreshold is the default maximum number of resources imported without ImportOptions.Force.
+const DefaultResourceCountThreshold = 5000
+
+// ConflictPolicy decides what the importer does when a resource already exists in the simulator.
+type ConflictPolicy string
+
+const (
+	// ConflictFail treats an already existing resource as a failure.
+	ConflictFail ConflictPolicy = "Fail"
+	// ConflictSkip ignores an already existing resource and counts it as skipped.
+	ConflictSkip ConflictPolicy = "Skip"
+	// ConflictUpdate updates an already existing resource with the one from the target cluster.
+	ConflictUpdate ConflictPolicy = "Update"
+)
+
+// ImportOptions configures a single import.
+type ImportOptions struct {
+	// LabelSelector is used to determine which resources from the target cluster should be imported.
+	LabelSelector metav1.LabelSelector
+	// Namespaces limits namespaced resources and namespaces to import to the given namespaces.
+	// Cluster-scoped resources other than namespaces are always imported.
+	// If empty, resources in all namespaces are imported.
+	Namespaces []string
+	// OnConflict decides what to do when a resource already exists in the simulator.
+	// If empty, ConflictFail is used.
+	OnConflict ConflictPolicy
+	// Strict makes the import abort on the first resource which fails to be imported.
+	// Otherwise, failures are collected into ImportSummary and the import continues.
+	Strict bool
+	// IncludeCompletedPods makes the import include pods in phase Succeeded or Failed and pods being deleted,
+	// which are skipped by default because they are never scheduled.
+	IncludeCompletedPods bool
+	// Resume makes the import skip resources already imported from the same version of the source objects,
+	// and update the ones whose source objects have been changed since then.
+	// It's used to rerun an import which failed halfway through. It takes precedence over OnConflict.
+	Resume bool
+	// ResourceCountThreshold is the maximum number of resources to import.
+	// Before applying anything, the importer counts the resources to import,
+	// and aborts with ErrTooManyResources if the total exceeds it, unless Force is true.
+	// If zero, DefaultResourceCountThreshold is used.
+	ResourceCountThreshold int
+	// Concurrency is the maximum number of resources applied to the simulator concurrently.
+	// If zero, resourceapplier.DefaultWorkers is used.
+	Concurrency int
+	// ForceNodeReady sets the Ready condition of imported nodes to True,
+	// so that pods can be scheduled on them even if they aren't ready in the target cluster.
+	ForceNodeReady bool
+	// Force makes the import proceed even if the number of resources exceeds ResourceCountThreshold.
+	Force bool
+
+	// ImportSchedulerConfig indicates whether the scheduler configuration is also imported from the target cluster.
+	// The profiles and extenders in it replace the ones in the simulator, and the scheduler is restarted.
+	ImportSchedulerConfig bool
+	// SchedulerConfigPath is the path to a scheduler configuration file to import.
+	// If set, it's used instead of the ConfigMap in the target cluster.
+	SchedulerConfigPath string
+	// SchedulerConfigMapNamespace is the namespace of the ConfigMap which has the scheduler configuration.
+	// If empty, DefaultSchedulerConfigMapNamespace is used.
+	SchedulerConfigMapNamespace string
+	// SchedulerConfigMapName is the name of the ConfigMap which has the scheduler configuration.
+	// If empty, DefaultSchedulerConfigMapName is used.
+	SchedulerConfigMapName string
+	// SchedulerConfigMapKey is the key in the ConfigMap which has the scheduler configuration.
+	// If empty, the ConfigMap must have only one key.
+	SchedulerConfigMapKey string
+}
+
+// ImportSummary describes the outcome of an import.
+type ImportSummary struct {
+	// RunID is the ID of the import, which is recorded on the imported resources.
+	RunID string `json:"runID"`
+	// Expected is the total number of resources to import, counted before applying anything.
+	Expected int `json:"expected"`
+	// ExpectedByResource is the number of resources to import per resource, e.g. "pods".
+	ExpectedByResource map[string]int `json:"expectedByResource,omitempty"`
+	// Created is the number of resources newly created in the simulator.
+	Created int `json:"created"`
+	// Updated is the number of already existing resources updated in the simulator.
+	Updated int `json:"updated"`
+	// Skipped is the number of already existing resources left untouched.
+	Skipped int `json:"skipped"`
+	// Filtered is the number of resources not imported by the filters, e.g., completed pods.
+	Filtered int `json:"filtered"`
+	// Failed is the number of resources which couldn't be imported.
+	Failed int `json:"failed"`
+	// Errors has the errors of the resources which couldn't be imported.
+	Errors []error `json:"-"`
+}
+
+// ImportState represents the state of an import.
+type ImportState string
+
+const (
+	// ImportStateIdle means no import has been run yet.
+	ImportStateIdle ImportState = "Idle"
+	// ImportStateRunning means an import is running.
+	ImportStateRunning ImportState = "Running"
+	// ImportStateSucceeded means the latest import has finished successfully.
+	ImportStateSucceeded ImportState = "Succeeded"
+	// ImportStateFailed means the latest import has finished with an error.
+	ImportStateFailed ImportState = "Failed"
+)
+
+// ImportStatus describes the progress of the latest import.
+type ImportStatus struct {
+	State      ImportState  `json:"state"`
+	StartedAt  *metav1.Time `json:"startedAt,omitempty"`
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+	// Summary is the outcome of the import so far.
+	Summary *ImportSummary `json:"summary,omitempty"`
+	// Errors has the errors of the resources which couldn't be imported.
+	Errors []string `json:"errors,omitempty"`
+	// Error is the error which aborted the import.
+	Error string `json:"error,omitempty"`
+}
+
+// summaryRecorder records the outcome of each resource concurrently imported.
+type summaryRecorder struct {
+	mu      sync.Mutex
+	summary ImportSummary
+}
+
+// NewService initializes Service. logger is used for the logs of the importer.
+func NewService(srcClient dynamic.Interface, resourceApplier *resourceapplier.Service, schedulerService SchedulerService, logger klog.Logger) *Service {
 	gvrs := DefaultGVRs
 	if resourceApplier.GVRsToSync != nil {
 		gvrs = resourceApplier.GVRsToSync
@@ -46,50 +228,631 @@ func NewService(srcClient dynamic.Interface, resourceApplier *resourceapplier.Se
 	return &Service{
 		srcDynamicClient:      srcClient,
 		resouceApplierService: resourceApplier,
+		schedulerService:      schedulerService,
 		gvrs:                  gvrs,
+		status:                ImportStatus{State: ImportStateIdle},
+		logger:                logger,
 	}
 }
 
 // ImportClusterResources gets resources from the target cluster via exportService
 // and then apply those resources to the simulator.
-// Note: this method doesn't handle scheduler configuration.
-// If you want to use the scheduler configuration along with the imported resources on the simulator,
-// you need to set the path of the scheduler configuration file to `kubeSchedulerConfigPath` value in the Simulator Server Configuration.
-func (s *Service) ImportClusterResources(ctx context.Context, labelSelector metav1.LabelSelector) error {
+// When opts.ImportSchedulerConfig is true, the scheduler configuration of the target cluster is applied
+// after all resources are imported.
+func (s *Service) ImportClusterResources(ctx context.Context, opts ImportOptions) (*ImportSummary, error) {
+	opts, err := completeImportOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := newSummaryRecorder()
+	if err := s.begin(recorder); err != nil {
+		return nil, err
+	}
+	err = s.importClusterResources(ctx, opts, recorder)
+	s.finish(err)
+
+	return recorder.result(), err
+}
+
+// StartImport starts ImportClusterResources in background and returns immediately.
+// The progress can be checked with ImportStatus.
+// It returns ErrImportInProgress if another import is running.
+func (s *Service) StartImport(opts ImportOptions) error {
+	opts, err := completeImportOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	recorder := newSummaryRecorder()
+	if err := s.begin(recorder); err != nil {
+		return err
+	}
+	// The import outlives the request which starts it, and is cancelled only by Shutdown.
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	s.statusMu.Lock()
+	s.cancel, s.done = cancel, done
+	s.statusMu.Unlock()
+	go func() {
+		defer close(done)
+		defer cancel()
+		err := s.importClusterResources(ctx, opts, recorder)
+		if err != nil {
+			s.logger.Error(err, "failed to import resources from the target cluster")
+		}
+		s.finish(err)
+	}()
+
+	return nil
+}
+
+// Shutdown cancels the import started by StartImport if it's running, and waits for it to finish until ctx is done.
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.statusMu.Lock()
+	cancel, done := s.cancel, s.done
+	s.statusMu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return xerrors.Errorf("wait for the import to finish: %w", ctx.Err())
+	}
+}
+
+// ImportStatus returns the status of the latest import.
+func (s *Service) ImportStatus() ImportStatus {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	status := s.status
+	if s.recorder != nil {
+		status.Summary = s.recorder.result()
+		for _, err := range status.Summary.Errors {
+			status.Errors = append(status.Errors, err.Error())
+		}
+	}
+	return status
+}
+
+// Healthz returns an error while importing resources,
+// since the simulator doesn't have all the resources of the target cluster yet.
+func (s *Service) Healthz() error {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	if s.status.State == ImportStateRunning {
+		return xerrors.New("importing resources from the target cluster")
+	}
+	return nil
+}
+
+func (s *Service) importClusterResources(ctx context.Context, opts ImportOptions, recorder *summaryRecorder) error {
+	if err := s.preflight(ctx, opts, recorder); err != nil {
+		return xerrors.Errorf("preflight: %w", err)
+	}
+
 	for _, gvr := range s.gvrs {
-		if err := s.importResource(ctx, gvr, labelSelector); err != nil {
+		if err := ctx.Err(); err != nil {
+			return xerrors.Errorf("import cancelled: %w", err)
+		}
+		if err := s.importResource(ctx, gvr, opts, recorder); err != nil {
 			return xerrors.Errorf("import resource %s: %w", gvr.String(), err)
 		}
 	}
 
+	if opts.ImportSchedulerConfig {
+		if err := s.importSchedulerConfig(ctx, opts); err != nil {
+			return xerrors.Errorf("import scheduler configuration: %w", err)
+		}
+	}
+
 	return nil
 }
 
-func (s *Service) importResource(ctx context.Context, gvr schema.GroupVersionResource, labelSelector metav1.LabelSelector) error {
-	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+// begin marks an import as running so that only one import runs at a time.
+func (s *Service) begin(recorder *summaryRecorder) error {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	if s.status.State == ImportStateRunning {
+		return ErrImportInProgress
+	}
+	now := metav1.Now()
+	s.status = ImportStatus{State: ImportStateRunning, StartedAt: &now}
+	s.recorder = recorder
+	return nil
+}
+
+// finish marks the running import as finished with the given error.
+func (s *Service) finish(err error) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	now := metav1.Now()
+	s.status.FinishedAt = &now
+	s.status.State = ImportStateSucceeded
 	if err != nil {
-		return xerrors.Errorf("convert label selector: %w", err)
+		s.status.State = ImportStateFailed
+		s.status.Error = err.Error()
+	}
+}
+
+// importSchedulerConfig reads the scheduler configuration from the file or the ConfigMap in the target cluster,
+// and restarts the scheduler with it.
+func (s *Service) importSchedulerConfig(ctx context.Context, opts ImportOptions) error {
+	var data []byte
+	if opts.SchedulerConfigPath != "" {
+		d, err := os.ReadFile(opts.SchedulerConfigPath)
+		if err != nil {
+			return xerrors.Errorf("read scheduler config file: %w", err)
+		}
+		data = d
+	} else {
+		d, err := s.getSchedulerConfigFromConfigMap(ctx, opts)
+		if apierrors.IsNotFound(err) {
+			// The scheduler configuration is explicitly requested, so the import must not look successful without it.
+			return xerrors.Errorf("the ConfigMap of the scheduler configuration isn't found in the target cluster: %w", err)
+		}
+		if err != nil {
+			return xerrors.Errorf("get scheduler config from ConfigMap: %w", err)
+		}
+		data = d
+	}
+
+	importedCfg, err := config.DecodeSchedulerCfg(data)
+	if err != nil {
+		return xerrors.Errorf("decode scheduler config: %w", err)
+	}
+
+	if s.schedulerService == nil {
+		s.logger.Info("skipped to import the scheduler configuration because the scheduler isn't available")
+		return nil
+	}
+	currentCfg, err := s.schedulerService.GetSchedulerConfig()
+	if errors.Is(err, scheduler.ErrServiceDisabled) {
+		s.logger.Info("skipped to import the scheduler configuration because the scheduler is running externally")
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("get current scheduler config: %w", err)
+	}
+
+	// Only profiles and extenders are taken from the target cluster,
+	// the rest of the configuration (e.g., ClientConnection) depends on the simulator's environment.
+	cfg := &configv1.KubeSchedulerConfiguration{}
+	if currentCfg != nil {
+		cfg = currentCfg.DeepCopy()
+	}
+	cfg.Profiles = importedCfg.Profiles
+	cfg.Extenders = importedCfg.Extenders
+	if err := s.schedulerService.RestartScheduler(cfg); err != nil {
+		return xerrors.Errorf("restart scheduler: %w", err)
+	}
+
+	return nil
+}
+
+// getSchedulerConfigFromConfigMap gets the scheduler configuration stored in the ConfigMap in the target cluster.
+func (s *Service) getSchedulerConfigFromConfigMap(ctx context.Context, opts ImportOptions) ([]byte, error) {
+	namespace := opts.SchedulerConfigMapNamespace
+	if namespace == "" {
+		namespace = DefaultSchedulerConfigMapNamespace
+	}
+	name := opts.SchedulerConfigMapName
+	if name == "" {
+		name = DefaultSchedulerConfigMapName
+	}
+
+	cm, err := s.getSourceResource(ctx, configMapGVR, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := unstructured.NestedStringMap(cm.Object, "data")
+	if err != nil {
+		return nil, xerrors.Errorf("get data of ConfigMap %s/%s: %w", namespace, name, err)
+	}
+
+	key := opts.SchedulerConfigMapKey
+	if key == "" {
+		if len(data) != 1 {
+			return nil, xerrors.Errorf("ConfigMap %s/%s has %d keys, specify the key which has the scheduler config", namespace, name, len(data))
+		}
+		for k := range data {
+			key = k
+		}
+	}
+	cfg, ok := data[key]
+	if !ok {
+		return nil, xerrors.Errorf("ConfigMap %s/%s doesn't have the key %q", namespace, name, key)
+	}
+
+	return []byte(cfg), nil
+}
+
+func (s *Service) importResource(ctx context.Context, gvr schema.GroupVersionResource, opts ImportOptions, recorder *summaryRecorder) error {
+	resources, err := s.listResources(ctx, gvr, opts)
+	if err != nil {
+		return err
+	}
+
+	return s.applyResources(ctx, resources.Items, opts, recorder)
+}
+
+// preflight counts the resources to import and records them as the expected numbers in the summary.
+// It returns ErrTooManyResources if the total exceeds the threshold and opts.Force is false.
+func (s *Service) preflight(ctx context.Context, opts ImportOptions, recorder *summaryRecorder) error {
+	counts := make(map[string]int, len(s.gvrs))
+	total := 0
+	for _, gvr := range s.gvrs {
+		n, err := s.countResources(ctx, gvr, opts)
+		if err != nil {
+			return xerrors.Errorf("count resource %s: %w", gvr.String(), err)
+		}
+		counts[gvr.GroupResource().String()] = n
+		total += n
+	}
+
+	recorder.record(func(summary *ImportSummary) {
+		summary.Expected = total
+		summary.ExpectedByResource = counts
+	})
+
+	threshold := opts.ResourceCountThreshold
+	if threshold == 0 {
+		threshold = DefaultResourceCountThreshold
+	}
+	if total <= threshold {
+		return nil
+	}
+	if opts.Force {
+		s.logger.Info("importing resources exceeding the threshold", "total", total, "threshold", threshold, "counts", counts)
+		return nil
+	}
+	return xerrors.Errorf("%d resources exceed the threshold %d (%v), set Force to import them anyway: %w", total, threshold, counts, ErrTooManyResources)
+}
+
+// countResources counts the resources of the given GVR to import.
+// It lists only one resource and uses remainingItemCount if the target cluster returns it,
+// and falls back to listing all resources otherwise.
+func (s *Service) countResources(ctx context.Context, gvr schema.GroupVersionResource, opts ImportOptions) (int, error) {
+	// remainingItemCount counts resources in all namespaces, so it cannot be used with the namespace filter.
+	if len(opts.Namespaces) == 0 && s.manifestsPath == "" {
+		selector, err := metav1.LabelSelectorAsSelector(&opts.LabelSelector)
+		if err != nil {
+			return 0, xerrors.Errorf("convert label selector: %w", err)
+		}
+		resources, err := s.srcDynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{
+			LabelSelector: selector.String(),
+			Limit:         1,
+		})
+		if err != nil {
+			return 0, xerrors.Errorf("list resources: %w", err)
+		}
+		if resources.GetRemainingItemCount() != nil {
+			return len(resources.Items) + int(*resources.GetRemainingItemCount()), nil
+		}
+		if resources.GetContinue() == "" {
+			return len(resources.Items), nil
+		}
+	}
+
+	resources, err := s.listResources(ctx, gvr, opts)
+	if err != nil {
+		return 0, err
+	}
+	return len(resources.Items), nil
+}
+
+// listResources lists the resources of the given GVR in the target cluster,
+// which match the label selector and the namespaces in opts.
+func (s *Service) listResources(ctx context.Context, gvr schema.GroupVersionResource, opts ImportOptions) (*unstructured.UnstructuredList, error) {
+	if s.manifestsPath != "" {
+		return s.listManifests(gvr, opts)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&opts.LabelSelector)
+	if err != nil {
+		return nil, xerrors.Errorf("convert label selector: %w", err)
 	}
 
 	resources, err := s.srcDynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{
 		LabelSelector: selector.String(),
 	})
 	if err != nil {
-		return xerrors.Errorf("list resources: %w", err)
+		return nil, xerrors.Errorf("list resources: %w", err)
 	}
 
-	var wg sync.WaitGroup
-	for _, resource := range resources.Items {
-		wg.Add(1)
-		fmt.Printf("importing resource: %s\n", resource.GetName())
-		go func(r *unstructured.Unstructured) {
-			defer wg.Done()
-			if err := s.resouceApplierService.Create(ctx, r); err != nil {
-				klog.Warningf("failed to import resource: %v", err)
-			}
-		}(&resource)
+	items := make([]unstructured.Unstructured, 0, len(resources.Items))
+	for i := range resources.Items {
+		if matchNamespaces(&resources.Items[i], opts.Namespaces) {
+			items = append(items, resources.Items[i])
+		}
+	}
+	resources.Items = items
+
+	return resources, nil
+}
+
+// applyResources applies the given resources to the simulator concurrently.
+func (s *Service) applyResources(ctx context.Context, resources []unstructured.Unstructured, opts ImportOptions, recorder *summaryRecorder) error {
+	results, err := s.resouceApplierService.ApplyAll(ctx, resources, resourceapplier.ApplyAllOptions{
+		Workers: opts.Concurrency,
+		// In strict mode, the rest of the resources aren't applied after the first failure.
+		StopOnError: opts.Strict,
+		Apply: func(ctx context.Context, r *unstructured.Unstructured) error {
+			s.logger.V(4).Info("Importing resource", "kind", r.GetKind(), "resource", klog.KObj(r))
+			return s.applyResource(ctx, r, opts, recorder)
+		},
+	})
+
+	var firstErr error
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		resultErr := xerrors.Errorf("import %s %s: %w", result.Resource.GetKind(), klog.KObj(result.Resource), result.Err)
+		recorder.record(func(summary *ImportSummary) {
+			summary.Failed++
+			summary.Errors = append(summary.Errors, resultErr)
+		})
+		if firstErr == nil {
+			firstErr = resultErr
+		}
+		if !opts.Strict {
+			s.logger.Info("failed to import resource", "err", resultErr)
+		}
+	}
+	if err != nil {
+		return firstErr
 	}
-	wg.Wait()
 
 	return nil
 }
+
+// applyResource creates the resource in the simulator and,
+// if it already exists, handles the conflict according to opts.OnConflict.
+func (s *Service) applyResource(ctx context.Context, resource *unstructured.Unstructured, opts ImportOptions, recorder *summaryRecorder) error {
+	if !opts.IncludeCompletedPods && resourcefilter.IsCompletedOrTerminatingPod(resource) {
+		s.logger.V(2).Info("Skipped to import the completed or terminating pod", "pod", klog.KObj(resource))
+		recorder.record(func(summary *ImportSummary) { summary.Filtered++ })
+		return nil
+	}
+	annotateImportedResource(resource, recorder.runID())
+	// The applier may mutate the resource, so keep the original one in case we need to update it afterward.
+	original := resource.DeepCopy()
+	if opts.Resume {
+		resumed, err := s.resumeResource(ctx, original, opts, recorder)
+		if err != nil {
+			return err
+		}
+		if resumed {
+			return nil
+		}
+	}
+
+	err := s.resouceApplierService.Create(ctx, resource)
+	if err == nil {
+		if err := s.importNodeStatus(ctx, original, opts); err != nil {
+			return err
+		}
+		recorder.record(func(summary *ImportSummary) { summary.Created++ })
+		return nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	switch opts.OnConflict {
+	case ConflictSkip:
+		s.logger.V(2).Info("Skipped to import resource because it already exists", "resource", klog.KObj(resource))
+		recorder.record(func(summary *ImportSummary) { summary.Skipped++ })
+		return nil
+	case ConflictUpdate:
+		if err := s.resouceApplierService.Update(ctx, original.DeepCopy()); err != nil {
+			return xerrors.Errorf("update already existing resource: %w", err)
+		}
+		if err := s.importNodeStatus(ctx, original, opts); err != nil {
+			return err
+		}
+		recorder.record(func(summary *ImportSummary) { summary.Updated++ })
+		return nil
+	default:
+		return err
+	}
+}
+
+// resumeResource handles the resource which may have been imported by a previous import.
+// It skips the resource if the one in the simulator is imported from the same version of the source object,
+// and updates it if the source object has been changed since then.
+// It returns false if the resource doesn't exist in the simulator yet.
+func (s *Service) resumeResource(ctx context.Context, resource *unstructured.Unstructured, opts ImportOptions, recorder *summaryRecorder) (bool, error) {
+	existing, err := s.resouceApplierService.Get(ctx, resource)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, xerrors.Errorf("get already existing resource: %w", err)
+	}
+
+	annotations := existing.GetAnnotations()
+	if annotations[SourceUIDAnnotationKey] == string(resource.GetUID()) &&
+		annotations[SourceResourceVersionAnnotationKey] == resource.GetResourceVersion() {
+		s.logger.V(2).Info("Skipped to import resource because it's already imported", "resource", klog.KObj(resource))
+		recorder.record(func(summary *ImportSummary) { summary.Skipped++ })
+		return true, nil
+	}
+
+	if err := s.resouceApplierService.Update(ctx, resource.DeepCopy()); err != nil {
+		return false, xerrors.Errorf("update already existing resource: %w", err)
+	}
+	if err := s.importNodeStatus(ctx, resource, opts); err != nil {
+		return false, err
+	}
+	recorder.record(func(summary *ImportSummary) { summary.Updated++ })
+	return true, nil
+}
+
+// annotateImportedResource records the import run and the source object on the resource to import,
+// so that a later import can tell whether the resource is already imported.
+func annotateImportedResource(resource *unstructured.Unstructured, runID string) {
+	annotations := resource.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ImportRunIDAnnotationKey] = runID
+	annotations[SourceUIDAnnotationKey] = string(resource.GetUID())
+	annotations[SourceResourceVersionAnnotationKey] = resource.GetResourceVersion()
+	resource.SetAnnotations(annotations)
+}
+
+// importNodeStatus copies the status of the node from the target cluster to the simulator,
+// because the status, including capacity and allocatable, is dropped when the node is created,
+// and then no pods can be scheduled on the node.
+// It does nothing for resources other than nodes.
+func (s *Service) importNodeStatus(ctx context.Context, node *unstructured.Unstructured, opts ImportOptions) error {
+	if node.GroupVersionKind().GroupKind() != (schema.GroupKind{Kind: "Node"}) {
+		return nil
+	}
+
+	if opts.ForceNodeReady {
+		if err := forceNodeReady(node); err != nil {
+			return xerrors.Errorf("force node ready: %w", err)
+		}
+	}
+
+	err := s.resouceApplierService.UpdateStatus(ctx, node)
+	if apierrors.IsNotFound(err) {
+		// The node may be filtered out by the applier.
+		s.logger.V(2).Info("Skipped to import node status because the node isn't found in the simulator", "node", klog.KObj(node))
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("import node status: %w", err)
+	}
+
+	return nil
+}
+
+// forceNodeReady sets the Ready condition of the node to True.
+func forceNodeReady(node *unstructured.Unstructured) error {
+	var n v1.Node
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(node.UnstructuredContent(), &n); err != nil {
+		return err
+	}
+
+	ready := v1.NodeCondition{
+		Type:               v1.NodeReady,
+		Status:             v1.ConditionTrue,
+		Reason:             "KubeletReady",
+		Message:            "forced to be ready by the simulator",
+		LastHeartbeatTime:  metav1.Now(),
+		LastTransitionTime: metav1.Now(),
+	}
+	found := false
+	for i := range n.Status.Conditions {
+		if n.Status.Conditions[i].Type == v1.NodeReady {
+			n.Status.Conditions[i] = ready
+			found = true
+		}
+	}
+	if !found {
+		n.Status.Conditions = append(n.Status.Conditions, ready)
+	}
+
+	status, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&n.Status)
+	if err != nil {
+		return err
+	}
+	return unstructured.SetNestedField(node.Object, status, "status")
+}
+
+// completeImportOptions fills the default values of opts and validates it.
+func completeImportOptions(opts ImportOptions) (ImportOptions, error) {
+	if opts.OnConflict == "" {
+		opts.OnConflict = ConflictFail
+	}
+	if err := validateConflictPolicy(opts.OnConflict); err != nil {
+		return opts, err
+	}
+	if _, err := metav1.LabelSelectorAsSelector(&opts.LabelSelector); err != nil {
+		return opts, xerrors.Errorf("convert label selector: %v: %w", err, ErrInvalidImportOptions)
+	}
+	if opts.Concurrency < 0 {
+		return opts, xerrors.Errorf("negative concurrency %d: %w", opts.Concurrency, ErrInvalidImportOptions)
+	}
+	if opts.ResourceCountThreshold < 0 {
+		return opts, xerrors.Errorf("negative resource count threshold %d: %w", opts.ResourceCountThreshold, ErrInvalidImportOptions)
+	}
+	return opts, nil
+}
+
+func validateConflictPolicy(p ConflictPolicy) error {
+	switch p {
+	case ConflictFail, ConflictSkip, ConflictUpdate:
+		return nil
+	default:
+		return xerrors.Errorf("unknown conflict policy %q: %w", p, ErrInvalidImportOptions)
+	}
+}
+
+// matchNamespaces checks whether the resource belongs to one of the namespaces.
+// Cluster-scoped resources other than namespaces always match.
+func matchNamespaces(resource *unstructured.Unstructured, namespaces []string) bool {
+	if len(namespaces) == 0 {
+		return true
+	}
+
+	namespace := resource.GetNamespace()
+	if namespace == "" {
+		if resource.GetKind() != "Namespace" {
+			return true
+		}
+		namespace = resource.GetName()
+	}
+	for _, ns := range namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// newSummaryRecorder initializes summaryRecorder with a new run ID.
+func newSummaryRecorder() *summaryRecorder {
+	return &summaryRecorder{summary: ImportSummary{RunID: string(uuid.NewUUID())}}
+}
+
+// runID returns the ID of the import.
+func (r *summaryRecorder) runID() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.summary.RunID
+}
+
+// record updates the summary with fn.
+func (r *summaryRecorder) record(fn func(summary *ImportSummary)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(&r.summary)
+}
+
+// result returns a copy of the summary recorded so far.
+func (r *summaryRecorder) result() *ImportSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	summary := r.summary
+	summary.Errors = append([]error(nil), r.summary.Errors...)
+	if r.summary.ExpectedByResource != nil {
+		summary.ExpectedByResource = make(map[string]int, len(r.summary.ExpectedByResource))
+		for k, v := range r.summary.ExpectedByResource {
+			summary.ExpectedByResource[k] = v
+		}
+	}
+	return &summary
+}
@@ -2,20 +2,35 @@ package oneshotimporter
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/xerrors"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/restmapper"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/klog/v2"
+	configv1 "k8s.io/kube-scheduler/config/v1"
 	scheduling "k8s.io/kubernetes/pkg/apis/scheduling/v1"
 	storage "k8s.io/kubernetes/pkg/apis/storage/v1"
+	"k8s.io/utils/ptr"
 
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/oneshotimporter/mock_oneshotimporter"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
 )
 
 func TestService_ImportClusterResources(t *testing.T) {
@@ -75,7 +90,7 @@ func TestService_ImportClusterResources(t *testing.T) {
 			srcClient := fake.NewSimpleDynamicClient(s)
 			destClient := fake.NewSimpleDynamicClient(s)
 			applier := resourceapplier.New(destClient, mapper, resourceapplier.Options{})
-			oneshotImporter := NewService(srcClient, applier)
+			oneshotImporter := NewService(srcClient, applier, nil, klog.Background())
 			for _, obj := range tt.srcObjects {
 				gvr, err := findGVR(obj)
 				assert.NoError(t, err)
@@ -83,7 +98,7 @@ func TestService_ImportClusterResources(t *testing.T) {
 				assert.NoError(t, err)
 			}
 
-			err := oneshotImporter.ImportClusterResources(context.Background(), tt.labelSelector)
+			_, err := oneshotImporter.ImportClusterResources(context.Background(), ImportOptions{LabelSelector: tt.labelSelector})
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -108,6 +123,410 @@ func TestService_ImportClusterResources(t *testing.T) {
 	}
 }
 
+func TestService_ImportClusterResources_OnConflict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		onConflict  ConflictPolicy
+		srcObjects  []*unstructured.Unstructured
+		destObjects []*unstructured.Unstructured
+		wantLabels  map[string]map[string]string
+		wantSummary ImportSummary
+		wantErr     bool
+	}{
+		{
+			name:       "Skip leaves already existing resources untouched",
+			onConflict: ConflictSkip,
+			srcObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("pod", map[string]string{"from": "src"}),
+				podWithNameAndLabel("pod2", map[string]string{"from": "src"}),
+			},
+			destObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("pod", map[string]string{"from": "dest"}),
+			},
+			wantLabels: map[string]map[string]string{
+				"pod":  {"from": "dest"},
+				"pod2": {"from": "src"},
+			},
+			wantSummary: ImportSummary{Created: 1, Skipped: 1},
+		},
+		{
+			name:       "Update overwrites already existing resources",
+			onConflict: ConflictUpdate,
+			srcObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("pod", map[string]string{"from": "src"}),
+				podWithNameAndLabel("pod2", map[string]string{"from": "src"}),
+			},
+			destObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("pod", map[string]string{"from": "dest"}),
+			},
+			wantLabels: map[string]map[string]string{
+				"pod":  {"from": "src"},
+				"pod2": {"from": "src"},
+			},
+			wantSummary: ImportSummary{Created: 1, Updated: 1},
+		},
+		{
+			name:       "Fail counts already existing resources as failed",
+			onConflict: ConflictFail,
+			srcObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("pod", map[string]string{"from": "src"}),
+				podWithNameAndLabel("pod2", map[string]string{"from": "src"}),
+			},
+			destObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("pod", map[string]string{"from": "dest"}),
+			},
+			wantLabels: map[string]map[string]string{
+				"pod":  {"from": "dest"},
+				"pod2": {"from": "src"},
+			},
+			wantSummary: ImportSummary{Created: 1, Failed: 1},
+		},
+		{
+			name:       "empty policy behaves as Fail",
+			onConflict: "",
+			srcObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("pod", map[string]string{"from": "src"}),
+			},
+			destObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("pod", map[string]string{"from": "dest"}),
+			},
+			wantLabels: map[string]map[string]string{
+				"pod": {"from": "dest"},
+			},
+			wantSummary: ImportSummary{Failed: 1},
+		},
+		{
+			name:       "unknown policy is rejected",
+			onConflict: "Unknown",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := runtime.NewScheme()
+			v1.AddToScheme(s)
+			storage.AddToScheme(s)
+			scheduling.AddToScheme(s)
+			srcClient := fake.NewSimpleDynamicClient(s)
+			destClient := fake.NewSimpleDynamicClient(s)
+			applier := resourceapplier.New(destClient, mapper, resourceapplier.Options{})
+			oneshotImporter := NewService(srcClient, applier, nil, klog.Background())
+			for _, obj := range tt.srcObjects {
+				gvr, err := findGVR(obj)
+				assert.NoError(t, err)
+				_, err = srcClient.Resource(gvr).Namespace(obj.GetNamespace()).Create(context.Background(), obj, metav1.CreateOptions{})
+				assert.NoError(t, err)
+			}
+			for _, obj := range tt.destObjects {
+				gvr, err := findGVR(obj)
+				assert.NoError(t, err)
+				_, err = destClient.Resource(gvr).Namespace(obj.GetNamespace()).Create(context.Background(), obj, metav1.CreateOptions{})
+				assert.NoError(t, err)
+			}
+
+			summary, err := oneshotImporter.ImportClusterResources(context.Background(), ImportOptions{OnConflict: tt.onConflict})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSummary.Created, summary.Created)
+			assert.Equal(t, tt.wantSummary.Updated, summary.Updated)
+			assert.Equal(t, tt.wantSummary.Skipped, summary.Skipped)
+			assert.Equal(t, tt.wantSummary.Failed, summary.Failed)
+			assert.Len(t, summary.Errors, summary.Failed)
+			podGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+			for name, want := range tt.wantLabels {
+				got, err := destClient.Resource(podGVR).Namespace("default").Get(context.Background(), name, metav1.GetOptions{})
+				assert.NoError(t, err)
+				assert.Equal(t, want, got.GetLabels())
+			}
+		})
+	}
+}
+
+func TestService_ImportClusterResources_Strict(t *testing.T) {
+	t.Parallel()
+
+	podGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	// failingMutation fails to import the pods named "broken-*".
+	failingMutation := func(_ context.Context, resource *unstructured.Unstructured, _ *resourceapplier.Clients) (*unstructured.Unstructured, error) {
+		if strings.HasPrefix(resource.GetName(), "broken-") {
+			return nil, xerrors.New("injected failure")
+		}
+		return resource, nil
+	}
+
+	tests := []struct {
+		name            string
+		strict          bool
+		srcObjects      []*unstructured.Unstructured
+		importedObjects []string
+		wantFailed      int
+		wantErr         bool
+	}{
+		{
+			name:   "non-strict mode collects failures and continues",
+			strict: false,
+			srcObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("pod", nil),
+				podWithNameAndLabel("broken-pod", nil),
+				podWithNameAndLabel("broken-pod2", nil),
+				podWithNameAndLabel("pod2", nil),
+			},
+			importedObjects: []string{"pod", "pod2"},
+			wantFailed:      2,
+			wantErr:         false,
+		},
+		{
+			name:   "strict mode aborts on the failure",
+			strict: true,
+			srcObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("broken-pod", nil),
+			},
+			wantFailed: 1,
+			wantErr:    true,
+		},
+		{
+			name:   "strict mode succeeds without failures",
+			strict: true,
+			srcObjects: []*unstructured.Unstructured{
+				podWithNameAndLabel("pod", nil),
+				podWithNameAndLabel("pod2", nil),
+			},
+			importedObjects: []string{"pod", "pod2"},
+			wantFailed:      0,
+			wantErr:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := runtime.NewScheme()
+			v1.AddToScheme(s)
+			storage.AddToScheme(s)
+			scheduling.AddToScheme(s)
+			srcClient := fake.NewSimpleDynamicClient(s)
+			destClient := fake.NewSimpleDynamicClient(s)
+			applier := resourceapplier.New(destClient, mapper, resourceapplier.Options{
+				MutateBeforeCreating: map[schema.GroupVersionResource][]resourceapplier.MutatingFunction{
+					podGVR: {failingMutation},
+				},
+			})
+			oneshotImporter := NewService(srcClient, applier, nil, klog.Background())
+			for _, obj := range tt.srcObjects {
+				_, err := srcClient.Resource(podGVR).Namespace(obj.GetNamespace()).Create(context.Background(), obj, metav1.CreateOptions{})
+				assert.NoError(t, err)
+			}
+
+			summary, err := oneshotImporter.ImportClusterResources(context.Background(), ImportOptions{Strict: tt.strict})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantFailed, summary.Failed)
+			assert.Len(t, summary.Errors, tt.wantFailed)
+			for _, name := range tt.importedObjects {
+				_, err := destClient.Resource(podGVR).Namespace("default").Get(context.Background(), name, metav1.GetOptions{})
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestService_ImportClusterResources_SchedulerConfig(t *testing.T) {
+	t.Parallel()
+
+	schedulerCfgYaml := `
+apiVersion: kubescheduler.config.k8s.io/v1
+kind: KubeSchedulerConfiguration
+profiles:
+- schedulerName: imported-scheduler
+extenders:
+- urlPrefix: http://extender.example.com
+  filterVerb: filter
+`
+	parallelism := int32(8)
+	currentCfg := &configv1.KubeSchedulerConfiguration{Parallelism: &parallelism}
+	assertImportedCfg := func(t *testing.T, cfg *configv1.KubeSchedulerConfiguration) {
+		t.Helper()
+		assert.Equal(t, &parallelism, cfg.Parallelism)
+		assert.Len(t, cfg.Profiles, 1)
+		assert.Equal(t, "imported-scheduler", *cfg.Profiles[0].SchedulerName)
+		assert.Len(t, cfg.Extenders, 1)
+		assert.Equal(t, "http://extender.example.com", cfg.Extenders[0].URLPrefix)
+	}
+
+	tests := []struct {
+		name                     string
+		opts                     func(t *testing.T) ImportOptions
+		configMap                *unstructured.Unstructured
+		prepareEachServiceMockFn func(t *testing.T, ss *mock_oneshotimporter.MockSchedulerService)
+		wantErr                  bool
+	}{
+		{
+			name: "restart scheduler with the config in the default ConfigMap",
+			opts: func(_ *testing.T) ImportOptions {
+				return ImportOptions{ImportSchedulerConfig: true}
+			},
+			configMap: configMapWithData("kube-system", "kube-scheduler-config", map[string]interface{}{"config.yaml": schedulerCfgYaml}),
+			prepareEachServiceMockFn: func(t *testing.T, ss *mock_oneshotimporter.MockSchedulerService) {
+				t.Helper()
+				ss.EXPECT().GetSchedulerConfig().Return(currentCfg, nil)
+				ss.EXPECT().RestartScheduler(gomock.Any()).DoAndReturn(func(cfg *configv1.KubeSchedulerConfiguration) error {
+					assertImportedCfg(t, cfg)
+					return nil
+				})
+			},
+		},
+		{
+			name: "restart scheduler with the config in the specified ConfigMap and key",
+			opts: func(_ *testing.T) ImportOptions {
+				return ImportOptions{
+					ImportSchedulerConfig:       true,
+					SchedulerConfigMapNamespace: "scheduler",
+					SchedulerConfigMapName:      "config",
+					SchedulerConfigMapKey:       "scheduler.yaml",
+				}
+			},
+			configMap: configMapWithData("scheduler", "config", map[string]interface{}{
+				"scheduler.yaml": schedulerCfgYaml,
+				"other.yaml":     "foo",
+			}),
+			prepareEachServiceMockFn: func(t *testing.T, ss *mock_oneshotimporter.MockSchedulerService) {
+				t.Helper()
+				ss.EXPECT().GetSchedulerConfig().Return(currentCfg, nil)
+				ss.EXPECT().RestartScheduler(gomock.Any()).DoAndReturn(func(cfg *configv1.KubeSchedulerConfiguration) error {
+					assertImportedCfg(t, cfg)
+					return nil
+				})
+			},
+		},
+		{
+			name: "restart scheduler with the config in the file",
+			opts: func(t *testing.T) ImportOptions {
+				t.Helper()
+				path := filepath.Join(t.TempDir(), "scheduler.yaml")
+				assert.NoError(t, os.WriteFile(path, []byte(schedulerCfgYaml), 0o600))
+				return ImportOptions{ImportSchedulerConfig: true, SchedulerConfigPath: path}
+			},
+			prepareEachServiceMockFn: func(t *testing.T, ss *mock_oneshotimporter.MockSchedulerService) {
+				t.Helper()
+				ss.EXPECT().GetSchedulerConfig().Return(currentCfg, nil)
+				ss.EXPECT().RestartScheduler(gomock.Any()).DoAndReturn(func(cfg *configv1.KubeSchedulerConfiguration) error {
+					assertImportedCfg(t, cfg)
+					return nil
+				})
+			},
+		},
+		{
+			name: "do nothing when ImportSchedulerConfig is false",
+			opts: func(_ *testing.T) ImportOptions {
+				return ImportOptions{}
+			},
+			configMap:                configMapWithData("kube-system", "kube-scheduler-config", map[string]interface{}{"config.yaml": schedulerCfgYaml}),
+			prepareEachServiceMockFn: func(_ *testing.T, _ *mock_oneshotimporter.MockSchedulerService) {},
+		},
+		{
+			name: "fail when the ConfigMap is not found",
+			opts: func(_ *testing.T) ImportOptions {
+				return ImportOptions{ImportSchedulerConfig: true}
+			},
+			prepareEachServiceMockFn: func(_ *testing.T, _ *mock_oneshotimporter.MockSchedulerService) {},
+			wantErr:                  true,
+		},
+		{
+			name: "fail when the key isn't specified and the ConfigMap has multiple keys",
+			opts: func(_ *testing.T) ImportOptions {
+				return ImportOptions{ImportSchedulerConfig: true}
+			},
+			configMap: configMapWithData("kube-system", "kube-scheduler-config", map[string]interface{}{
+				"config.yaml": schedulerCfgYaml,
+				"other.yaml":  "foo",
+			}),
+			prepareEachServiceMockFn: func(_ *testing.T, _ *mock_oneshotimporter.MockSchedulerService) {},
+			wantErr:                  true,
+		},
+		{
+			name: "skip without error when the scheduler service is disabled",
+			opts: func(_ *testing.T) ImportOptions {
+				return ImportOptions{ImportSchedulerConfig: true}
+			},
+			configMap: configMapWithData("kube-system", "kube-scheduler-config", map[string]interface{}{"config.yaml": schedulerCfgYaml}),
+			prepareEachServiceMockFn: func(_ *testing.T, ss *mock_oneshotimporter.MockSchedulerService) {
+				ss.EXPECT().GetSchedulerConfig().Return(nil, scheduler.ErrServiceDisabled)
+			},
+		},
+		{
+			name: "fail when GetSchedulerConfig fails",
+			opts: func(_ *testing.T) ImportOptions {
+				return ImportOptions{ImportSchedulerConfig: true}
+			},
+			configMap: configMapWithData("kube-system", "kube-scheduler-config", map[string]interface{}{"config.yaml": schedulerCfgYaml}),
+			prepareEachServiceMockFn: func(_ *testing.T, ss *mock_oneshotimporter.MockSchedulerService) {
+				ss.EXPECT().GetSchedulerConfig().Return(nil, xerrors.New("get failed"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "fail when RestartScheduler fails",
+			opts: func(_ *testing.T) ImportOptions {
+				return ImportOptions{ImportSchedulerConfig: true}
+			},
+			configMap: configMapWithData("kube-system", "kube-scheduler-config", map[string]interface{}{"config.yaml": schedulerCfgYaml}),
+			prepareEachServiceMockFn: func(_ *testing.T, ss *mock_oneshotimporter.MockSchedulerService) {
+				ss.EXPECT().GetSchedulerConfig().Return(currentCfg, nil)
+				ss.EXPECT().RestartScheduler(gomock.Any()).Return(xerrors.New("restart failed"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockSchedulerSvc := mock_oneshotimporter.NewMockSchedulerService(ctrl)
+			tt.prepareEachServiceMockFn(t, mockSchedulerSvc)
+
+			s := runtime.NewScheme()
+			v1.AddToScheme(s)
+			storage.AddToScheme(s)
+			scheduling.AddToScheme(s)
+			srcClient := fake.NewSimpleDynamicClient(s)
+			destClient := fake.NewSimpleDynamicClient(s)
+			applier := resourceapplier.New(destClient, mapper, resourceapplier.Options{})
+			oneshotImporter := NewService(srcClient, applier, mockSchedulerSvc, klog.Background())
+			if tt.configMap != nil {
+				_, err := srcClient.Resource(configMapGVR).Namespace(tt.configMap.GetNamespace()).Create(context.Background(), tt.configMap, metav1.CreateOptions{})
+				assert.NoError(t, err)
+			}
+
+			_, err := oneshotImporter.ImportClusterResources(context.Background(), tt.opts(t))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
 var mapper = restmapper.NewDiscoveryRESTMapper([]*restmapper.APIGroupResources{
 	{
 		Group: metav1.APIGroup{
@@ -145,6 +564,20 @@ func findGVR(obj *unstructured.Unstructured) (schema.GroupVersionResource, error
 	return m.Resource, nil
 }
 
+func configMapWithData(namespace, name string, data map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": namespace,
+			},
+			"data": data,
+		},
+	}
+}
+
 func podWithNameAndLabel(name string, labels map[string]string) *unstructured.Unstructured {
 	pod := &unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -171,3 +604,412 @@ func podWithNameAndLabel(name string, labels map[string]string) *unstructured.Un
 
 	return pod
 }
+
+func TestService_StartImport(t *testing.T) {
+	t.Parallel()
+
+	podGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	s := runtime.NewScheme()
+	v1.AddToScheme(s)
+	storage.AddToScheme(s)
+	scheduling.AddToScheme(s)
+	srcClient := fake.NewSimpleDynamicClient(s)
+	destClient := fake.NewSimpleDynamicClient(s)
+	applier := resourceapplier.New(destClient, mapper, resourceapplier.Options{})
+	oneshotImporter := NewService(srcClient, applier, nil, klog.Background())
+
+	pod := podWithNameAndLabel("pod", nil)
+	otherPod := podWithNameAndLabel("other-pod", nil)
+	otherPod.SetNamespace("other")
+	for _, obj := range []*unstructured.Unstructured{pod, otherPod} {
+		_, err := srcClient.Resource(podGVR).Namespace(obj.GetNamespace()).Create(context.Background(), obj, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, ImportStateIdle, oneshotImporter.ImportStatus().State)
+
+	err := oneshotImporter.StartImport(ImportOptions{OnConflict: "Unknown"})
+	assert.ErrorIs(t, err, ErrInvalidImportOptions)
+
+	err = oneshotImporter.StartImport(ImportOptions{Namespaces: []string{"default"}})
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return oneshotImporter.ImportStatus().State != ImportStateRunning
+	}, 5*time.Second, 10*time.Millisecond)
+
+	status := oneshotImporter.ImportStatus()
+	assert.Equal(t, ImportStateSucceeded, status.State)
+	assert.NotNil(t, status.StartedAt)
+	assert.NotNil(t, status.FinishedAt)
+	assert.Equal(t, 1, status.Summary.Created)
+	_, err = destClient.Resource(podGVR).Namespace("default").Get(context.Background(), "pod", metav1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = destClient.Resource(podGVR).Namespace("other").Get(context.Background(), "other-pod", metav1.GetOptions{})
+	assert.Error(t, err)
+}
+
+func TestService_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	s := runtime.NewScheme()
+	v1.AddToScheme(s)
+	storage.AddToScheme(s)
+	scheduling.AddToScheme(s)
+	srcClient := fake.NewSimpleDynamicClient(s)
+	started, release := make(chan struct{}), make(chan struct{})
+	// The fake client doesn't watch the context, so block the first list until the import is cancelled.
+	srcClient.PrependReactor("list", "namespaces", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		close(started)
+		<-release
+		return false, nil, nil
+	})
+	applier := resourceapplier.New(fake.NewSimpleDynamicClient(s), mapper, resourceapplier.Options{})
+	oneshotImporter := NewService(srcClient, applier, nil, klog.Background())
+	assert.NoError(t, oneshotImporter.Shutdown(context.Background()), "nothing to shut down")
+
+	assert.NoError(t, oneshotImporter.StartImport(ImportOptions{}))
+	<-started
+	expired, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, oneshotImporter.Shutdown(expired), context.Canceled, "the import is still running")
+
+	close(release)
+	assert.NoError(t, oneshotImporter.Shutdown(context.Background()))
+	status := oneshotImporter.ImportStatus()
+	assert.Equal(t, ImportStateFailed, status.State)
+	assert.Contains(t, status.Error, "import cancelled")
+}
+
+func TestService_begin(t *testing.T) {
+	t.Parallel()
+
+	s := &Service{status: ImportStatus{State: ImportStateIdle}}
+	assert.NoError(t, s.begin(&summaryRecorder{}))
+	assert.ErrorIs(t, s.begin(&summaryRecorder{}), ErrImportInProgress)
+	s.finish(xerrors.New("failed"))
+	status := s.ImportStatus()
+	assert.Equal(t, ImportStateFailed, status.State)
+	assert.Equal(t, "failed", status.Error)
+	assert.NoError(t, s.begin(&summaryRecorder{}))
+}
+
+func TestService_ImportClusterResources_Preflight(t *testing.T) {
+	t.Parallel()
+
+	podGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	// largeNodeListReactor simulates a cluster with 10000 nodes, which returns remainingItemCount for a paginated list.
+	// The fake client doesn't pass the limit to reactors, so it always returns the first page.
+	largeNodeListReactor := func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		list := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "v1", "kind": "NodeList"}}
+		list.SetRemainingItemCount(ptr.To[int64](9999))
+		list.SetContinue("continue")
+		list.Items = []unstructured.Unstructured{*nodeWithName("node-0")}
+		return true, list, nil
+	}
+
+	tests := []struct {
+		name                 string
+		opts                 ImportOptions
+		srcPods              int
+		largeCluster         bool
+		wantExpected         int
+		wantExpectedPods     int
+		wantExpectedNodes    int
+		wantCreated          int
+		wantTooManyResources bool
+	}{
+		{
+			name:             "import resources within the threshold",
+			opts:             ImportOptions{ResourceCountThreshold: 3},
+			srcPods:          3,
+			wantExpected:     3,
+			wantExpectedPods: 3,
+			wantCreated:      3,
+		},
+		{
+			name:                 "abort when resources exceed the threshold",
+			opts:                 ImportOptions{ResourceCountThreshold: 2},
+			srcPods:              3,
+			wantExpected:         3,
+			wantExpectedPods:     3,
+			wantCreated:          0,
+			wantTooManyResources: true,
+		},
+		{
+			name:             "import resources exceeding the threshold with Force",
+			opts:             ImportOptions{ResourceCountThreshold: 2, Force: true},
+			srcPods:          3,
+			wantExpected:     3,
+			wantExpectedPods: 3,
+			wantCreated:      3,
+		},
+		{
+			name:                 "abort on a large cluster with the default threshold",
+			opts:                 ImportOptions{},
+			srcPods:              1,
+			largeCluster:         true,
+			wantExpected:         10001,
+			wantExpectedPods:     1,
+			wantExpectedNodes:    10000,
+			wantCreated:          0,
+			wantTooManyResources: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := runtime.NewScheme()
+			v1.AddToScheme(s)
+			storage.AddToScheme(s)
+			scheduling.AddToScheme(s)
+			srcClient := fake.NewSimpleDynamicClient(s)
+			if tt.largeCluster {
+				srcClient.PrependReactor("list", "nodes", largeNodeListReactor)
+			}
+			destClient := fake.NewSimpleDynamicClient(s)
+			applier := resourceapplier.New(destClient, mapper, resourceapplier.Options{})
+			oneshotImporter := NewService(srcClient, applier, nil, klog.Background())
+			for i := 0; i < tt.srcPods; i++ {
+				_, err := srcClient.Resource(podGVR).Namespace("default").Create(context.Background(), podWithNameAndLabel(fmt.Sprintf("pod-%d", i), nil), metav1.CreateOptions{})
+				assert.NoError(t, err)
+			}
+
+			summary, err := oneshotImporter.ImportClusterResources(context.Background(), tt.opts)
+
+			if tt.wantTooManyResources {
+				assert.ErrorIs(t, err, ErrTooManyResources)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantExpected, summary.Expected)
+			assert.Equal(t, tt.wantExpectedPods, summary.ExpectedByResource["pods"])
+			assert.Equal(t, tt.wantExpectedNodes, summary.ExpectedByResource["nodes"])
+			assert.Equal(t, tt.wantCreated, summary.Created)
+			pods, err := destClient.Resource(podGVR).Namespace("default").List(context.Background(), metav1.ListOptions{})
+			assert.NoError(t, err)
+			assert.Len(t, pods.Items, tt.wantCreated)
+		})
+	}
+}
+
+func TestService_ImportClusterResources_NodeStatus(t *testing.T) {
+	t.Parallel()
+
+	nodeGVR := schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+	// dropStatusOnCreate simulates kube-apiserver, which ignores the status on creation.
+	dropStatusOnCreate := func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj, ok := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		if ok {
+			unstructured.RemoveNestedField(obj.Object, "status")
+		}
+		return false, nil, nil
+	}
+
+	tests := []struct {
+		name           string
+		forceNodeReady bool
+		wantReady      v1.ConditionStatus
+	}{
+		{
+			name:           "import the node status as it is",
+			forceNodeReady: false,
+			wantReady:      v1.ConditionFalse,
+		},
+		{
+			name:           "import the node status with the Ready condition forced to True",
+			forceNodeReady: true,
+			wantReady:      v1.ConditionTrue,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := runtime.NewScheme()
+			v1.AddToScheme(s)
+			storage.AddToScheme(s)
+			scheduling.AddToScheme(s)
+			srcClient := fake.NewSimpleDynamicClient(s)
+			destClient := fake.NewSimpleDynamicClient(s)
+			destClient.PrependReactor("create", "nodes", dropStatusOnCreate)
+			applier := resourceapplier.New(destClient, mapper, resourceapplier.Options{})
+			oneshotImporter := NewService(srcClient, applier, nil, klog.Background())
+
+			node := nodeWithName("node")
+			node.Object["status"] = map[string]interface{}{
+				"capacity":    map[string]interface{}{"cpu": "4", "memory": "16Gi", "pods": "110"},
+				"allocatable": map[string]interface{}{"cpu": "3800m", "memory": "15Gi", "pods": "110"},
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Ready", "status": "False"},
+				},
+				"nodeInfo": map[string]interface{}{"kubeletVersion": "v1.32.0"},
+			}
+			_, err := srcClient.Resource(nodeGVR).Create(context.Background(), node, metav1.CreateOptions{})
+			assert.NoError(t, err)
+
+			summary, err := oneshotImporter.ImportClusterResources(context.Background(), ImportOptions{ForceNodeReady: tt.forceNodeReady})
+			assert.NoError(t, err)
+			assert.Equal(t, 1, summary.Created)
+
+			got, err := destClient.Resource(nodeGVR).Get(context.Background(), "node", metav1.GetOptions{})
+			assert.NoError(t, err)
+			var gotNode v1.Node
+			assert.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(got.UnstructuredContent(), &gotNode))
+			assert.Equal(t, "3800m", gotNode.Status.Allocatable.Cpu().String())
+			assert.Equal(t, "4", gotNode.Status.Capacity.Cpu().String())
+			assert.Equal(t, "v1.32.0", gotNode.Status.NodeInfo.KubeletVersion)
+			assert.Len(t, gotNode.Status.Conditions, 1)
+			assert.Equal(t, tt.wantReady, gotNode.Status.Conditions[0].Status)
+		})
+	}
+}
+
+func TestService_ImportClusterResources_Resume(t *testing.T) {
+	t.Parallel()
+
+	podGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+	s := runtime.NewScheme()
+	v1.AddToScheme(s)
+	storage.AddToScheme(s)
+	scheduling.AddToScheme(s)
+	srcClient := fake.NewSimpleDynamicClient(s)
+	destClient := fake.NewSimpleDynamicClient(s)
+	for i := 0; i < 5; i++ {
+		pod := podWithNameAndLabel(fmt.Sprintf("pod-%d", i), nil)
+		pod.SetUID(types.UID(fmt.Sprintf("uid-%d", i)))
+		_, err := srcClient.Resource(podGVR).Namespace("default").Create(context.Background(), pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+
+	// The first import is interrupted after 3 pods are imported.
+	var created int32
+	interruptingMutation := func(_ context.Context, resource *unstructured.Unstructured, _ *resourceapplier.Clients) (*unstructured.Unstructured, error) {
+		if atomic.AddInt32(&created, 1) > 3 {
+			return nil, xerrors.New("injected failure")
+		}
+		return resource, nil
+	}
+	interruptedApplier := resourceapplier.New(destClient, mapper, resourceapplier.Options{
+		MutateBeforeCreating: map[schema.GroupVersionResource][]resourceapplier.MutatingFunction{
+			podGVR: {interruptingMutation},
+		},
+	})
+	summary, err := NewService(srcClient, interruptedApplier, nil, klog.Background()).ImportClusterResources(context.Background(), ImportOptions{Strict: true, Concurrency: 1})
+	assert.Error(t, err)
+	assert.Equal(t, 3, summary.Created)
+
+	// pod-0 is changed in the target cluster after the first import.
+	pod0, err := srcClient.Resource(podGVR).Namespace("default").Get(context.Background(), "pod-0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	pod0.SetLabels(map[string]string{"changed": "true"})
+	pod0.SetResourceVersion("2")
+	_, err = srcClient.Resource(podGVR).Namespace("default").Update(context.Background(), pod0, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	destClient.ClearActions()
+	applier := resourceapplier.New(destClient, mapper, resourceapplier.Options{})
+	summary, err = NewService(srcClient, applier, nil, klog.Background()).ImportClusterResources(context.Background(), ImportOptions{Resume: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.Created)
+	assert.Equal(t, 1, summary.Updated)
+	assert.Equal(t, 2, summary.Skipped)
+	assert.Equal(t, 0, summary.Failed)
+	// Every pod is created exactly once across the two imports.
+	createdNames := map[string]int{}
+	for _, action := range destClient.Actions() {
+		if createAction, ok := action.(k8stesting.CreateAction); ok && action.GetVerb() == "create" && action.GetResource() == podGVR {
+			createdNames[createAction.GetObject().(*unstructured.Unstructured).GetName()]++
+		}
+	}
+	assert.Equal(t, map[string]int{"pod-3": 1, "pod-4": 1}, createdNames)
+	for i := 0; i < 5; i++ {
+		got, err := destClient.Resource(podGVR).Namespace("default").Get(context.Background(), fmt.Sprintf("pod-%d", i), metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("uid-%d", i), got.GetAnnotations()[SourceUIDAnnotationKey])
+		assert.Equal(t, summary.RunID == got.GetAnnotations()[ImportRunIDAnnotationKey], i == 0 || i >= 3)
+	}
+	got, err := destClient.Resource(podGVR).Namespace("default").Get(context.Background(), "pod-0", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "true", got.GetLabels()["changed"])
+}
+
+func TestService_ImportClusterResources_CompletedPods(t *testing.T) {
+	t.Parallel()
+
+	podGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	podWithPhase := func(name string, phase v1.PodPhase, terminating bool) *unstructured.Unstructured {
+		pod := podWithNameAndLabel(name, nil)
+		pod.Object["status"] = map[string]interface{}{"phase": string(phase)}
+		if terminating {
+			now := metav1.Now()
+			pod.SetDeletionTimestamp(&now)
+		}
+		return pod
+	}
+
+	tests := []struct {
+		name                 string
+		includeCompletedPods bool
+		wantImported         []string
+		wantFiltered         int
+	}{
+		{
+			name:         "skip completed and terminating pods by default",
+			wantImported: []string{"pending", "running"},
+			wantFiltered: 3,
+		},
+		{
+			name:                 "import completed and terminating pods with IncludeCompletedPods",
+			includeCompletedPods: true,
+			wantImported:         []string{"pending", "running", "succeeded", "failed", "terminating"},
+			wantFiltered:         0,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := runtime.NewScheme()
+			v1.AddToScheme(s)
+			storage.AddToScheme(s)
+			scheduling.AddToScheme(s)
+			srcClient := fake.NewSimpleDynamicClient(s)
+			destClient := fake.NewSimpleDynamicClient(s)
+			applier := resourceapplier.New(destClient, mapper, resourceapplier.Options{})
+			oneshotImporter := NewService(srcClient, applier, nil, klog.Background())
+			for _, pod := range []*unstructured.Unstructured{
+				podWithPhase("pending", v1.PodPending, false),
+				podWithPhase("running", v1.PodRunning, false),
+				podWithPhase("succeeded", v1.PodSucceeded, false),
+				podWithPhase("failed", v1.PodFailed, false),
+				podWithPhase("terminating", v1.PodRunning, true),
+			} {
+				_, err := srcClient.Resource(podGVR).Namespace("default").Create(context.Background(), pod, metav1.CreateOptions{})
+				assert.NoError(t, err)
+			}
+
+			summary, err := oneshotImporter.ImportClusterResources(context.Background(), ImportOptions{IncludeCompletedPods: tt.includeCompletedPods})
+
+			assert.NoError(t, err)
+			assert.Equal(t, len(tt.wantImported), summary.Created)
+			assert.Equal(t, tt.wantFiltered, summary.Filtered)
+			pods, err := destClient.Resource(podGVR).Namespace("default").List(context.Background(), metav1.ListOptions{})
+			assert.NoError(t, err)
+			got := []string{}
+			for _, p := range pods.Items {
+				got = append(got, p.GetName())
+			}
+			assert.ElementsMatch(t, tt.wantImported, got)
+		})
+	}
+}
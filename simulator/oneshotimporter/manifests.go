@@ -0,0 +1,230 @@
+package oneshotimporter
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+)
+
+// NewFromManifests initializes Service which imports resources from static manifests instead of a live cluster.
+// path is a directory of YAML or JSON files, or a tarball (.tar, .tar.gz or .tgz) of them,
+// e.g., written by ExportClusterResources or `kubectl get -o yaml`.
+// Each file can have multiple YAML documents and List objects.
+func NewFromManifests(path string, resourceApplier *resourceapplier.Service, logger klog.Logger) *Service {
+	s := NewService(nil, resourceApplier, nil, logger)
+	s.manifestsPath = path
+	return s
+}
+
+// listManifests lists the resources of the given GVR in the manifests,
+// which match the label selector and the namespaces in opts.
+func (s *Service) listManifests(gvr schema.GroupVersionResource, opts ImportOptions) (*unstructured.UnstructuredList, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&opts.LabelSelector)
+	if err != nil {
+		return nil, xerrors.Errorf("convert label selector: %w", err)
+	}
+
+	manifests, err := loadManifests(s.manifestsPath)
+	if err != nil {
+		return nil, xerrors.Errorf("load manifests: %w", err)
+	}
+
+	list := &unstructured.UnstructuredList{}
+	for i := range manifests {
+		r := &manifests[i]
+		gvk := r.GroupVersionKind()
+		mapping, err := s.resouceApplierService.Clients().RestMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil || mapping.Resource != gvr {
+			continue
+		}
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace && r.GetNamespace() == "" {
+			// Manifests written by hand often omit the namespace.
+			r.SetNamespace(metav1.NamespaceDefault)
+		}
+		if selector.Matches(labels.Set(r.GetLabels())) && matchNamespaces(r, opts.Namespaces) {
+			list.Items = append(list.Items, *r)
+		}
+	}
+
+	return list, nil
+}
+
+// getManifest gets the resource with the given GVR, namespace and name from the manifests.
+// It returns a NotFound error if it's not found as the dynamic client does.
+func (s *Service) getManifest(gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	list, err := s.listManifests(gvr, ImportOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		if list.Items[i].GetNamespace() == namespace && list.Items[i].GetName() == name {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, apierrors.NewNotFound(gvr.GroupResource(), name)
+}
+
+// getSourceResource gets the resource from the target cluster, or the manifests if Service is initialized with them.
+func (s *Service) getSourceResource(ctx context.Context, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	if s.manifestsPath != "" {
+		return s.getManifest(gvr, namespace, name)
+	}
+	return s.srcDynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// loadManifests reads all resources in the directory or the tarball at path.
+func loadManifests(path string) ([]unstructured.Unstructured, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		var resources []unstructured.Unstructured
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !isManifestFile(p) {
+				return nil
+			}
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			rs, err := decodeManifests(data)
+			if err != nil {
+				return xerrors.Errorf("decode %s: %w", p, err)
+			}
+			resources = append(resources, rs...)
+			return nil
+		})
+		return resources, err
+	}
+
+	if isTarball(path) {
+		return loadTarball(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeManifests(data)
+}
+
+// loadTarball reads all resources in the tarball, which can be gzipped.
+func loadTarball(path string) ([]unstructured.Unstructured, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if !strings.HasSuffix(path, ".tar") {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, xerrors.Errorf("read gzip: %w", err)
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	var resources []unstructured.Unstructured
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("read tar: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || !isManifestFile(header.Name) {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, xerrors.Errorf("read %s: %w", header.Name, err)
+		}
+		rs, err := decodeManifests(data)
+		if err != nil {
+			return nil, xerrors.Errorf("decode %s: %w", header.Name, err)
+		}
+		resources = append(resources, rs...)
+	}
+
+	return resources, nil
+}
+
+// decodeManifests decodes multi-document YAML or JSON, expanding List objects into their items.
+func decodeManifests(data []byte) ([]unstructured.Unstructured, error) {
+	var resources []unstructured.Unstructured
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		obj := map[string]interface{}{}
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if len(obj) == 0 {
+			// empty document
+			continue
+		}
+
+		u := &unstructured.Unstructured{Object: obj}
+		if !u.IsList() {
+			resources = append(resources, *u)
+			continue
+		}
+		list, err := u.ToList()
+		if err != nil {
+			return nil, xerrors.Errorf("convert to list: %w", err)
+		}
+		for _, item := range list.Items {
+			if item.GetKind() == "" && strings.HasSuffix(list.GetKind(), "List") {
+				// The items of a typed list, e.g., PodList, don't have apiVersion and kind.
+				item.SetAPIVersion(list.GetAPIVersion())
+				item.SetKind(strings.TrimSuffix(list.GetKind(), "List"))
+			}
+			resources = append(resources, item)
+		}
+	}
+
+	return resources, nil
+}
+
+func isManifestFile(path string) bool {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+func isTarball(path string) bool {
+	return strings.HasSuffix(path, ".tar") || strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz")
+}
@@ -0,0 +1,182 @@
+package oneshotimporter
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/klog/v2"
+	scheduling "k8s.io/kubernetes/pkg/apis/scheduling/v1"
+	storage "k8s.io/kubernetes/pkg/apis/storage/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+)
+
+func TestNewFromManifests_ImportClusterResources(t *testing.T) {
+	t.Parallel()
+
+	podGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	nodeGVR := schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+
+	tests := []struct {
+		name          string
+		path          func(t *testing.T) string
+		labelSelector metav1.LabelSelector
+		wantPods      []string
+		wantNodes     []string
+	}{
+		{
+			name:      "import resources from a directory",
+			path:      func(_ *testing.T) string { return "testdata/manifests" },
+			wantPods:  []string{"default/pod-1", "other/pod-2"},
+			wantNodes: []string{"node-1", "node-2"},
+		},
+		{
+			name:          "import resources filtered with label selector",
+			path:          func(_ *testing.T) string { return "testdata/manifests" },
+			labelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "test"}},
+			wantPods:      []string{"default/pod-1"},
+			wantNodes:     []string{"node-1"},
+		},
+		{
+			name:      "import resources from a tarball",
+			path:      func(t *testing.T) string { return writeTarball(t, "testdata/manifests") },
+			wantPods:  []string{"default/pod-1", "other/pod-2"},
+			wantNodes: []string{"node-1", "node-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := runtime.NewScheme()
+			v1.AddToScheme(s)
+			storage.AddToScheme(s)
+			scheduling.AddToScheme(s)
+			destClient := fake.NewSimpleDynamicClient(s)
+			importer := NewFromManifests(tt.path(t), resourceapplier.New(destClient, mapper, resourceapplier.Options{}), klog.Background())
+
+			summary, err := importer.ImportClusterResources(context.Background(), ImportOptions{LabelSelector: tt.labelSelector})
+
+			assert.NoError(t, err)
+			assert.Equal(t, len(tt.wantPods)+len(tt.wantNodes), summary.Created)
+			assert.Equal(t, 0, summary.Failed)
+			pods, err := destClient.Resource(podGVR).List(context.Background(), metav1.ListOptions{})
+			assert.NoError(t, err)
+			gotPods := []string{}
+			for _, p := range pods.Items {
+				gotPods = append(gotPods, p.GetNamespace()+"/"+p.GetName())
+			}
+			assert.ElementsMatch(t, tt.wantPods, gotPods)
+			nodes, err := destClient.Resource(nodeGVR).List(context.Background(), metav1.ListOptions{})
+			assert.NoError(t, err)
+			gotNodes := []string{}
+			for _, n := range nodes.Items {
+				gotNodes = append(gotNodes, n.GetName())
+				if n.GetName() == "node-1" {
+					cpu, _, _ := unstructured.NestedString(n.Object, "status", "allocatable", "cpu")
+					assert.Equal(t, "4", cpu)
+				}
+			}
+			assert.ElementsMatch(t, tt.wantNodes, gotNodes)
+		})
+	}
+}
+
+func TestNewFromManifests_ExportedFiles(t *testing.T) {
+	t.Parallel()
+
+	s := runtime.NewScheme()
+	v1.AddToScheme(s)
+	storage.AddToScheme(s)
+	scheduling.AddToScheme(s)
+	srcClient := fake.NewSimpleDynamicClient(s)
+	destClient := fake.NewSimpleDynamicClient(s)
+	podGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	_, err := srcClient.Resource(podGVR).Namespace("default").Create(context.Background(), podWithNameAndLabel("pod", nil), metav1.CreateOptions{})
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	exporter := NewService(srcClient, resourceapplier.New(fake.NewSimpleDynamicClient(s), mapper, resourceapplier.Options{}), nil, klog.Background())
+	assert.NoError(t, exporter.ExportClusterResources(context.Background(), dir, ImportOptions{}))
+
+	summary, err := NewFromManifests(dir, resourceapplier.New(destClient, mapper, resourceapplier.Options{}), klog.Background()).ImportClusterResources(context.Background(), ImportOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Created)
+	_, err = destClient.Resource(podGVR).Namespace("default").Get(context.Background(), "pod", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func Test_decodeManifests(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`
+apiVersion: v1
+kind: PodList
+items:
+- metadata:
+    name: pod-1
+---
+# empty document
+---
+{"apiVersion": "v1", "kind": "Node", "metadata": {"name": "node-1"}}
+`)
+
+	got, err := decodeManifests(data)
+
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "Pod", got[0].GetKind())
+	assert.Equal(t, "v1", got[0].GetAPIVersion())
+	assert.Equal(t, "pod-1", got[0].GetName())
+	assert.Equal(t, "Node", got[1].GetKind())
+	assert.Equal(t, "node-1", got[1].GetName())
+}
+
+// writeTarball writes the files in dir to a gzipped tarball, and returns the path to it.
+func writeTarball(t *testing.T, dir string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "manifests.tar.gz")
+	f, err := os.Create(path)
+	assert.NoError(t, err)
+	defer f.Close()
+	gw := gzip.NewWriter(f)
+	defer gw.Close()
+	tw := tar.NewWriter(gw)
+	defer tw.Close()
+
+	err = filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: rel, Mode: 0o600, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	assert.NoError(t, err)
+
+	return path
+}
@@ -0,0 +1,181 @@
+// Package oplog keeps the recent logs of the simulator in memory, so that the users can read them on the web UI
+// without shelling into the container, e.g., to see why the import failed.
+package oplog
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultCapacity is the default number of the entries kept in Buffer.
+	DefaultCapacity = 1000
+	// DefaultVerbosity is the default verbosity of the info logs kept in Buffer.
+	DefaultVerbosity = 2
+	// subscriptionSize is the number of the entries which can be pending for each subscription.
+	subscriptionSize = 100
+)
+
+// The components tagging their logs. The logs of the other components have no component.
+const (
+	ComponentSyncer       = "syncer"
+	ComponentImporter     = "importer"
+	ComponentReplayer     = "replayer"
+	ComponentRecorder     = "recorder"
+	ComponentPodLifecycle = "podlifecycle"
+)
+
+// The levels of the entries.
+const (
+	LevelInfo  = "info"
+	LevelError = "error"
+)
+
+// Entry is a log entry.
+type Entry struct {
+	Time  time.Time `json:"time"`
+	Level string    `json:"level"`
+	// V is the verbosity of the info log.
+	V         int    `json:"v,omitempty"`
+	Component string `json:"component,omitempty"`
+	Message   string `json:"message"`
+	Error     string `json:"error,omitempty"`
+	// Fields are the key-value pairs of the structured log, formatted as strings.
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Filter selects the entries.
+type Filter struct {
+	// Level selects only the errors if it's LevelError, and all the entries otherwise.
+	Level string
+	// Components selects the entries of any of them. All the entries are selected if it's empty.
+	Components []string
+}
+
+func (f *Filter) matches(e *Entry) bool {
+	if f.Level == LevelError && e.Level != LevelError {
+		return false
+	}
+	if len(f.Components) == 0 {
+		return true
+	}
+	for _, c := range f.Components {
+		if c == e.Component {
+			return true
+		}
+	}
+	return false
+}
+
+// Logger returns the logger tagging the logs with the component.
+func Logger(component string) klog.Logger {
+	return klog.Background().WithName(component)
+}
+
+// Buffer is the ring buffer keeping the latest entries.
+type Buffer struct {
+	mu sync.Mutex
+	// entries is the ring of the entries. The oldest one is at start when it's full.
+	entries []Entry
+	start   int
+	size    int
+	subs    map[*Subscription]bool
+}
+
+// NewBuffer initializes Buffer keeping the latest capacity entries.
+func NewBuffer(capacity int) *Buffer {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Buffer{entries: make([]Entry, capacity), subs: map[*Subscription]bool{}}
+}
+
+// Add adds the entry, dropping the oldest one if it's full, and sends it to the subscriptions.
+func (b *Buffer) Add(e Entry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.size < len(b.entries) {
+		b.entries[(b.start+b.size)%len(b.entries)] = e
+		b.size++
+	} else {
+		b.entries[b.start] = e
+		b.start = (b.start + 1) % len(b.entries)
+	}
+
+	for s := range b.subs {
+		if !s.filter.matches(&e) {
+			continue
+		}
+		select {
+		case s.c <- e:
+		default:
+			// don't block the logging for the slow subscriber.
+			s.dropped++
+		}
+	}
+}
+
+// Entries returns the latest limit entries selected by f, from the oldest one.
+// All the entries kept are returned if limit is zero.
+func (b *Buffer) Entries(f Filter, limit int) []Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ret := []Entry{}
+	for i := 0; i < b.size; i++ {
+		e := b.entries[(b.start+i)%len(b.entries)]
+		if f.matches(&e) {
+			ret = append(ret, e)
+		}
+	}
+	if limit > 0 && len(ret) > limit {
+		ret = ret[len(ret)-limit:]
+	}
+	return ret
+}
+
+// Subscribe returns the subscription receiving the entries selected by f added from now on.
+// It must be closed when it's no longer used.
+func (b *Buffer) Subscribe(f Filter) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := &Subscription{buffer: b, filter: f, c: make(chan Entry, subscriptionSize)}
+	b.subs[s] = true
+	return s
+}
+
+// Subscription receives the entries added to Buffer.
+type Subscription struct {
+	buffer *Buffer
+	filter Filter
+	c      chan Entry
+	// dropped is the number of the entries dropped since the subscriber didn't receive them in time.
+	dropped int
+}
+
+// C returns the channel to receive the entries. It's closed when the subscription is closed.
+func (s *Subscription) C() <-chan Entry {
+	return s.c
+}
+
+// Dropped returns the number of the entries dropped since the subscriber didn't receive them in time.
+func (s *Subscription) Dropped() int {
+	s.buffer.mu.Lock()
+	defer s.buffer.mu.Unlock()
+	return s.dropped
+}
+
+// Close stops the subscription.
+func (s *Subscription) Close() {
+	s.buffer.mu.Lock()
+	defer s.buffer.mu.Unlock()
+
+	if s.buffer.subs[s] {
+		delete(s.buffer.subs, s)
+		close(s.c)
+	}
+}
@@ -0,0 +1,135 @@
+package oplog
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func messages(entries []Entry) []string {
+	ret := []string{}
+	for _, e := range entries {
+		ret = append(ret, e.Message)
+	}
+	return ret
+}
+
+func TestBuffer_bounds(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		capacity int
+		added    int
+		limit    int
+		want     []string
+	}{
+		{
+			name:     "not full",
+			capacity: 3,
+			added:    2,
+			want:     []string{"0", "1"},
+		},
+		{
+			name:     "the oldest entries are dropped when it's full",
+			capacity: 3,
+			added:    5,
+			want:     []string{"2", "3", "4"},
+		},
+		{
+			name:     "limit returns the latest entries",
+			capacity: 3,
+			added:    5,
+			limit:    2,
+			want:     []string{"3", "4"},
+		},
+		{
+			name:     "limit larger than the entries",
+			capacity: 3,
+			added:    1,
+			limit:    2,
+			want:     []string{"0"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			b := NewBuffer(tt.capacity)
+			for i := 0; i < tt.added; i++ {
+				b.Add(Entry{Level: LevelInfo, Message: fmt.Sprint(i)})
+			}
+			assert.Equal(t, tt.want, messages(b.Entries(Filter{}, tt.limit)))
+		})
+	}
+}
+
+func TestBuffer_Entries_filter(t *testing.T) {
+	t.Parallel()
+	b := NewBuffer(DefaultCapacity)
+	b.Add(Entry{Level: LevelInfo, Component: ComponentSyncer, Message: "synced"})
+	b.Add(Entry{Level: LevelError, Component: ComponentSyncer, Message: "sync failed"})
+	b.Add(Entry{Level: LevelError, Component: ComponentImporter, Message: "import failed"})
+	b.Add(Entry{Level: LevelInfo, Message: "started"})
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{
+			name:   "no filter",
+			filter: Filter{},
+			want:   []string{"synced", "sync failed", "import failed", "started"},
+		},
+		{
+			name:   "errors only",
+			filter: Filter{Level: LevelError},
+			want:   []string{"sync failed", "import failed"},
+		},
+		{
+			name:   "components",
+			filter: Filter{Components: []string{ComponentSyncer, ComponentReplayer}},
+			want:   []string{"synced", "sync failed"},
+		},
+		{
+			name:   "errors of the component",
+			filter: Filter{Level: LevelError, Components: []string{ComponentImporter}},
+			want:   []string{"import failed"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, messages(b.Entries(tt.filter, 0)))
+		})
+	}
+}
+
+func TestBuffer_Subscribe(t *testing.T) {
+	t.Parallel()
+	b := NewBuffer(DefaultCapacity)
+	b.Add(Entry{Level: LevelError, Message: "before subscribing"})
+
+	s := b.Subscribe(Filter{Level: LevelError})
+	b.Add(Entry{Level: LevelInfo, Message: "filtered out"})
+	b.Add(Entry{Level: LevelError, Message: "received"})
+	got := <-s.C()
+	assert.Equal(t, "received", got.Message)
+
+	for i := 0; i < subscriptionSize+1; i++ {
+		b.Add(Entry{Level: LevelError})
+	}
+	assert.Equal(t, 1, s.Dropped(), "Add doesn't block when the subscriber is slow")
+
+	s.Close()
+	s.Close()
+	n := 0
+	for range s.C() {
+		n++
+	}
+	assert.Equal(t, subscriptionSize, n)
+	require.NotPanics(t, func() { b.Add(Entry{Level: LevelError}) })
+}
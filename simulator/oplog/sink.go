@@ -0,0 +1,105 @@
+package oplog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// sink is the LogSink adding the logs to Buffer as well as writing them with the delegate.
+type sink struct {
+	buffer   *Buffer
+	delegate klog.LogSink
+	// verbosity is the maximum verbosity of the info logs added to buffer.
+	verbosity int
+	// names are the names given by WithName. The first one is the component.
+	names  []string
+	values []interface{}
+	now    func() time.Time
+}
+
+// callDepthLogSink is the LogSink which can skip the frames to find the caller, e.g., the one of klog's text logger.
+type callDepthLogSink interface {
+	WithCallDepth(depth int) klog.LogSink
+}
+
+// NewLogger returns the logger adding the logs up to verbosity to buffer, and writing them with delegate as well.
+// The first name given by WithName is the component of the logs.
+func NewLogger(buffer *Buffer, delegate klog.Logger, verbosity int) klog.Logger {
+	return klog.New(&sink{buffer: buffer, delegate: delegate.GetSink(), verbosity: verbosity, now: time.Now})
+}
+
+func (s *sink) Init(info klog.RuntimeInfo) {
+	// the delegate is called from this sink.
+	info.CallDepth++
+	s.delegate.Init(info)
+}
+
+func (s *sink) Enabled(level int) bool {
+	return level <= s.verbosity || s.delegate.Enabled(level)
+}
+
+func (s *sink) Info(level int, msg string, keysAndValues ...interface{}) {
+	if level <= s.verbosity {
+		s.buffer.Add(s.entry(LevelInfo, level, nil, msg, keysAndValues))
+	}
+	if s.delegate.Enabled(level) {
+		s.delegate.Info(level, msg, keysAndValues...)
+	}
+}
+
+func (s *sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.buffer.Add(s.entry(LevelError, 0, err, msg, keysAndValues))
+	s.delegate.Error(err, msg, keysAndValues...)
+}
+
+func (s *sink) WithValues(keysAndValues ...interface{}) klog.LogSink {
+	c := *s
+	c.values = append(append([]interface{}{}, s.values...), keysAndValues...)
+	c.delegate = s.delegate.WithValues(keysAndValues...)
+	return &c
+}
+
+func (s *sink) WithName(name string) klog.LogSink {
+	c := *s
+	c.names = append(append([]string{}, s.names...), name)
+	c.delegate = s.delegate.WithName(name)
+	return &c
+}
+
+func (s *sink) WithCallDepth(depth int) klog.LogSink {
+	d, ok := s.delegate.(callDepthLogSink)
+	if !ok {
+		return s
+	}
+	c := *s
+	c.delegate = d.WithCallDepth(depth)
+	return &c
+}
+
+func (s *sink) entry(level string, v int, err error, msg string, keysAndValues []interface{}) Entry {
+	e := Entry{Time: s.now(), Level: level, V: v, Message: msg}
+	if len(s.names) > 0 {
+		e.Component = s.names[0]
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	kvs := append(append([]interface{}{}, s.values...), keysAndValues...)
+	if len(kvs) > 0 {
+		e.Fields = make(map[string]string, len(kvs)/2)
+	}
+	for i := 0; i < len(kvs); i += 2 {
+		key := fmt.Sprint(kvs[i])
+		if i+1 == len(kvs) {
+			e.Fields[key] = "(MISSING)"
+			break
+		}
+		e.Fields[key] = fmt.Sprint(kvs[i+1])
+	}
+	// klog gives the message with the trailing newline when it's printed with the format.
+	e.Message = strings.TrimSuffix(e.Message, "\n")
+	return e
+}
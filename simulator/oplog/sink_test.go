@@ -0,0 +1,74 @@
+package oplog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"k8s.io/klog/v2"
+)
+
+// fakeSink counts the logs written with it.
+type fakeSink struct {
+	verbosity int
+	logs      *int
+}
+
+func (s *fakeSink) Init(klog.RuntimeInfo)                  {}
+func (s *fakeSink) Enabled(level int) bool                 { return level <= s.verbosity }
+func (s *fakeSink) Info(int, string, ...interface{})       { *s.logs++ }
+func (s *fakeSink) Error(error, string, ...interface{})    { *s.logs++ }
+func (s *fakeSink) WithValues(...interface{}) klog.LogSink { return s }
+func (s *fakeSink) WithName(string) klog.LogSink           { return s }
+func (s *fakeSink) WithCallDepth(int) klog.LogSink         { return s }
+
+func TestNewLogger(t *testing.T) {
+	t.Parallel()
+	b := NewBuffer(DefaultCapacity)
+	delegated := 0
+	logger := NewLogger(b, klog.New(&fakeSink{verbosity: 0, logs: &delegated}), 2)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	logger.GetSink().(*sink).now = func() time.Time { return now }
+
+	syncer := logger.WithName(ComponentSyncer).WithName("pod").WithValues("namespace", "default")
+	syncer.Info("synced", "name", "pod-1")
+	syncer.V(2).Info("detail")
+	syncer.V(3).Info("too verbose")
+	syncer.Error(xerrors.New("conflict"), "failed to sync")
+	logger.Info("no component", "dangling")
+
+	want := []Entry{
+		{
+			Time:      now,
+			Level:     LevelInfo,
+			Component: ComponentSyncer,
+			Message:   "synced",
+			Fields:    map[string]string{"namespace": "default", "name": "pod-1"},
+		},
+		{
+			Time:      now,
+			Level:     LevelInfo,
+			V:         2,
+			Component: ComponentSyncer,
+			Message:   "detail",
+			Fields:    map[string]string{"namespace": "default"},
+		},
+		{
+			Time:      now,
+			Level:     LevelError,
+			Component: ComponentSyncer,
+			Message:   "failed to sync",
+			Error:     "conflict",
+			Fields:    map[string]string{"namespace": "default"},
+		},
+		{
+			Time:    now,
+			Level:   LevelInfo,
+			Message: "no component",
+			Fields:  map[string]string{"dangling": "(MISSING)"},
+		},
+	}
+	assert.Equal(t, want, b.Entries(Filter{}, 0))
+	assert.Equal(t, 3, delegated, "only the logs enabled in the delegate are written with it")
+}
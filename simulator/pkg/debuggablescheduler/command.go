@@ -1,65 +1,214 @@
 package debuggablescheduler
 
 import (
+	"sync"
+
 	"github.com/spf13/cobra"
 	"golang.org/x/xerrors"
+	configv1 "k8s.io/kube-scheduler/config/v1"
 	"k8s.io/kubernetes/cmd/kube-scheduler/app"
 	"k8s.io/kubernetes/pkg/scheduler/framework/runtime"
 
+	simulatorconfig "sigs.k8s.io/kube-scheduler-simulator/simulator/config"
 	simulatorschedulerconfig "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/config"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/resultstore"
 )
 
 func NewSchedulerCommand(opts ...Option) (*cobra.Command, func(), error) {
-	opt := &options{pluginExtender: map[string]plugin.PluginExtenderInitializer{}, outOfTreeRegistry: map[string]runtime.PluginFactory{}}
-	for _, o := range opts {
-		o(opt)
-	}
+	opt := newOptions(opts)
 
 	if opt.outOfTreeRegistry != nil {
 		simulatorschedulerconfig.SetOutOfTreeRegistries(opt.outOfTreeRegistry)
 	}
 
-	configs, err := NewConfigs()
+	configs, err := newConfigs(opt)
 	if err != nil {
 		return nil, nil, xerrors.Errorf("failed to NewConfigs(): %w", err)
 	}
 
-	// Extender service must be initialized using `KubeSchedulerConfiguration.Extenders` config which is not override for simulator (before calling OverrideExtendersCfgToSimulator()).
-	// The override will be do within CreateOptions().
-	extenderService, err := extender.New(configs.clientSet, configs.versioned.Extenders, configs.sharedStore)
-	if err != nil {
-		return nil, nil, xerrors.Errorf("failed to New Extender service: %w", err)
+	return newSchedulerCommand(configs, opt)
+}
+
+// newSchedulerCommand starts the components which the scheduler depends on, and creates the scheduler command.
+// The returned cancel func tears all of them down; it stops the informer and shuts the proxy server down so that the port is released.
+// So NewSchedulerCommand can be called again in the same process after the cancel func is called,
+// e.g., to restart the scheduler with another config.
+func newSchedulerCommand(configs Configs, opt *options) (*cobra.Command, func(), error) {
+	if opt.extenderProxyHost != "" {
+		configs.proxy.Host = opt.extenderProxyHost
+	}
+	if opt.extenderProxyPort != 0 {
+		configs.proxy.Port = opt.extenderProxyPort
+	}
+	configs.proxy.CertFile = opt.extenderProxyCertFile
+	configs.proxy.KeyFile = opt.extenderProxyKeyFile
+
+	configs.disableExtenderProxy = opt.withoutExtenderProxy
+	configs.strictPluginValidation = opt.strictPluginValidation
+
+	if opt.pluginMetricsEnabled {
+		plugin.RegisterMetrics()
+	}
+
+	var extenderService *extender.Service
+	if configs.extenderProxyEnabled() {
+		// Extender service must be initialized using `KubeSchedulerConfiguration.Extenders` config which is not override for simulator (before calling OverrideExtendersCfgToSimulator()).
+		// The override will be do within CreateOptions().
+		var err error
+		var extenderOpts []extender.Option
+		if opt.extenderRawExchangeEnabled {
+			extenderOpts = append(extenderOpts, extender.WithRawExchange(opt.extenderRawExchangeMaxSize, opt.extenderRawExchangeNodeNamesOnly))
+		}
+		if len(opt.extenderInjections) != 0 {
+			extenderOpts = append(extenderOpts, extender.WithInjections(opt.extenderInjections))
+		}
+		extenderService, err = extender.New(configs.clientSet, configs.versioned.Extenders, configs.sharedStore, extenderOpts...)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to New Extender service: %w", err)
+		}
+	}
+
+	resultHook := opt.resultHook
+	var resultCache *ResultCache
+	if opt.schedulingResultsAPIEnabled {
+		resultCache = NewResultCache(opt.schedulingResultsCacheSize)
+		resultHook = chainResultHooks(resultHook, resultCache.Add)
+	}
+	closeRecorderFn := func() {}
+	if opt.resultRecorderPath != "" {
+		r, err := newResultRecorder(opt.resultRecorderPath, opt.resultRecorderMaxSize, opt.resultRecorderMaxBackups)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("start result recorder: %w", err)
+		}
+		resultHook = chainResultHooks(resultHook, r.Record)
+		closeRecorderFn = r.Close
 	}
 
-	schedulerOpts, cancelFn, err := CreateOptions(configs, opt.pluginExtender)
+	schedulerOpts, cancelFn, err := CreateOptions(configs, opt.pluginExtender, opt.profilePluginExtender, resultHook)
 	if err != nil {
-		return nil, cancelFn, err
+		if cancelFn != nil {
+			cancelFn()
+		}
+		closeRecorderFn()
+		return nil, nil, err
 	}
-	// Launch the proxy HTTP server for Extender, which is used to store the Extender's results.
-	s := NewExtenderServer(extenderService)
-	shutdownFn, err := s.Start(configs.port)
+	if extenderService != nil {
+		// Merge the scores of the prioritize extenders into the final scores of the plugins,
+		// so that the total scores add up to what the scheduler ranks the nodes with.
+		if store, ok := configs.sharedStore.GetResultStore(plugin.ResultStoreKey); ok {
+			if r, ok := store.(extender.ScoreRecorder); ok {
+				extenderService.SetScoreRecorder(r)
+			}
+		}
+	}
+	shutdownFn, err := startServer(configs, extenderService, resultCache)
 	if err != nil {
+		cancelFn()
+		closeRecorderFn()
 		return nil, nil, xerrors.Errorf("start extender proxy server: %w", err)
 	}
 
+	var once sync.Once
 	cancel := func() {
-		cancelFn()
-		shutdownFn()
+		once.Do(func() {
+			// Stop the informer first not to update Pods with the results anymore.
+			cancelFn()
+			shutdownFn()
+			closeRecorderFn()
+		})
 	}
 	command := app.NewSchedulerCommand(schedulerOpts...)
 
 	return command, cancel, nil
 }
 
+// startServer launches the HTTP server for the proxy for Extender, which is used to store the Extender's results,
+// and for the scheduling results API if resultCache is non-nil.
+// It doesn't launch the server if neither of them is enabled, and the returned func does nothing then.
+func startServer(configs Configs, extenderService *extender.Service, resultCache *ResultCache) (func(), error) {
+	if !configs.extenderProxyEnabled() {
+		// The proxy APIs aren't registered without the service.
+		extenderService = nil
+		if resultCache == nil {
+			return func() {}, nil
+		}
+	}
+	s := NewExtenderServer(extenderService)
+	if resultCache != nil {
+		s.RouteSchedulingResults(resultCache)
+	}
+	return s.Start(configs.proxy)
+}
+
+// chainResultHooks returns the hook calling all the given hooks in order. nil hooks are ignored.
+func chainResultHooks(hooks ...plugin.ResultHook) plugin.ResultHook {
+	return func(podNamespace, podName string, result SchedulingResult) {
+		for _, h := range hooks {
+			if h != nil {
+				h(podNamespace, podName, result)
+			}
+		}
+	}
+}
+
 type options struct {
 	outOfTreeRegistry runtime.Registry
 	pluginExtender    map[string]plugin.PluginExtenderInitializer
+	// profilePluginExtender is profile name → plugin name → plugin extenders.
+	profilePluginExtender plugin.ProfilePluginExtenders
+	resultHook            plugin.ResultHook
+
+	extenderProxyHost     string
+	extenderProxyPort     int
+	extenderProxyCertFile string
+	extenderProxyKeyFile  string
+	withoutExtenderProxy  bool
+
+	extenderRawExchangeEnabled       bool
+	extenderRawExchangeMaxSize       int
+	extenderRawExchangeNodeNamesOnly bool
+
+	extenderInjections map[int]extender.Injection
+
+	schedulingResultsAPIEnabled bool
+	schedulingResultsCacheSize  int
+
+	pluginMetricsEnabled bool
+
+	resultRecorderPath       string
+	resultRecorderMaxSize    int64
+	resultRecorderMaxBackups int
+
+	resultStore             string
+	resultStoreHistoryLimit int
+
+	resultFilter []string
+
+	strictPluginValidation bool
+
+	schedulerConfig     *configv1.KubeSchedulerConfiguration
+	schedulerConfigPath string
+
+	featureGates map[string]bool
+
+	randomSeed *int64
 }
 
+// SchedulingResult is the per-plugin results of a scheduling attempt passed to the hook registered with WithResultHook.
+type SchedulingResult = resultstore.SchedulingResult
+
 type Option func(opt *options)
 
+func newOptions(opts []Option) *options {
+	opt := &options{pluginExtender: map[string]plugin.PluginExtenderInitializer{}, profilePluginExtender: plugin.ProfilePluginExtenders{}, outOfTreeRegistry: map[string]runtime.PluginFactory{}}
+	for _, o := range opts {
+		o(opt)
+	}
+	return opt
+}
+
 // WithPlugin creates an Option based on plugin name and factory.
 func WithPlugin(pluginName string, factory runtime.PluginFactory) Option {
 	return func(opt *options) {
@@ -73,3 +222,207 @@ func WithPluginExtenders(pluginName string, e plugin.PluginExtenderInitializer)
 		opt.pluginExtender[pluginName] = e
 	}
 }
+
+// WithPluginExtendersForProfile creates an Option based on profile name, plugin name and plugin extenders.
+// The plugin extenders are applied to the plugin only in the profile whose schedulerName is profileName,
+// and take precedence over the ones registered with WithPluginExtenders.
+func WithPluginExtendersForProfile(profileName, pluginName string, e plugin.PluginExtenderInitializer) Option {
+	return func(opt *options) {
+		if opt.profilePluginExtender[profileName] == nil {
+			opt.profilePluginExtender[profileName] = map[string]plugin.PluginExtenderInitializer{}
+		}
+		opt.profilePluginExtender[profileName][pluginName] = e
+	}
+}
+
+// WithResultHook creates an Option to register the hook which receives the per-plugin results of each scheduling attempt,
+// e.g., to push them to your metrics system.
+// The hook is called in another goroutine after the results are reflected on the Pod, so it doesn't block the scheduling.
+// Results are dropped if the hook is too slow to catch up with the scheduling.
+func WithResultHook(hook func(podNamespace, podName string, result SchedulingResult)) Option {
+	return func(opt *options) {
+		opt.resultHook = hook
+	}
+}
+
+// WithExtenderProxyAddress creates an Option to change the address which the proxy server for Extenders listens on.
+// By default, it listens on all addresses with the port given by the `proxyPort` flag.
+func WithExtenderProxyAddress(host string, port int) Option {
+	return func(opt *options) {
+		opt.extenderProxyHost = host
+		opt.extenderProxyPort = port
+	}
+}
+
+// WithSimulatorPorts creates an Option to make the proxy server for Extenders listen on ports.extenderProxy of the simulator config
+// when the scheduler is embedded in the simulator. It has no effect if ports.extenderProxy isn't configured.
+func WithSimulatorPorts(ports simulatorconfig.Ports) Option {
+	return func(opt *options) {
+		if !ports.ExtenderProxy.Enabled() {
+			return
+		}
+		opt.extenderProxyHost = ports.ExtenderProxy.Address
+		opt.extenderProxyPort = ports.ExtenderProxy.Port
+	}
+}
+
+// WithExtenderProxyTLS creates an Option to make the proxy server for Extenders serve TLS with the given certificate and key.
+// The scheduler trusts the certificate when it sends requests to the proxy server.
+func WithExtenderProxyTLS(certFile, keyFile string) Option {
+	return func(opt *options) {
+		opt.extenderProxyCertFile = certFile
+		opt.extenderProxyKeyFile = keyFile
+	}
+}
+
+// WithoutExtenderProxy creates an Option to disable the proxy server for Extenders.
+// The scheduler sends the requests to Extenders directly, and the results of Extenders aren't recorded then.
+// Note that the proxy server is disabled anyway if the scheduler configuration has no Extenders.
+func WithoutExtenderProxy() Option {
+	return func(opt *options) {
+		opt.withoutExtenderProxy = true
+	}
+}
+
+// WithExtenderRawExchange creates an Option to record the raw requests from the scheduler to Extenders and the responses to them
+// in the `extender-raw-exchange` annotation of the Pod, for debugging Extenders.
+// The request and the response longer than maxSize bytes are truncated. extender.DefaultRawExchangeMaxSize is used if maxSize isn't positive.
+// If nodeNamesOnly is true, the node lists are recorded with the node names only.
+// It's disabled by default since the payloads can be large, and has no effect if the proxy for Extenders is disabled.
+func WithExtenderRawExchange(maxSize int, nodeNamesOnly bool) Option {
+	return func(opt *options) {
+		opt.extenderRawExchangeEnabled = true
+		opt.extenderRawExchangeMaxSize = maxSize
+		opt.extenderRawExchangeNodeNamesOnly = nodeNamesOnly
+	}
+}
+
+// WithExtenderInjections creates an Option to inject the artificial latencies and failures to the requests to Extenders
+// before the proxy forwards them, keyed by the index of the Extender in the scheduler configuration.
+// The injections can also be changed at runtime with `PUT /api/v1/extender/injections/:id` on the proxy server.
+// It has no effect if the proxy for Extenders is disabled.
+func WithExtenderInjections(injections map[int]extender.Injection) Option {
+	return func(opt *options) {
+		opt.extenderInjections = injections
+	}
+}
+
+// WithSchedulingResultsAPI creates an Option to serve `GET /api/v1/schedulingresults` on the same server as the proxy for Extenders,
+// which returns the latest scheduling results of the recently scheduled Pods.
+// The results of cacheSize Pods at most are kept in memory. DefaultResultCacheSize is used if cacheSize isn't positive.
+// The server is launched even if the proxy for Extenders is disabled.
+func WithSchedulingResultsAPI(cacheSize int) Option {
+	return func(opt *options) {
+		opt.schedulingResultsAPIEnabled = true
+		opt.schedulingResultsCacheSize = cacheSize
+	}
+}
+
+// WithPluginMetrics creates an Option to export the histograms of how long each plugin takes at PreFilter, Filter, PostFilter and Score,
+// as kube_scheduler_simulator_plugin_execution_duration_seconds on the scheduler's /metrics endpoint.
+// The histograms are labeled by plugin and extension_point, and observe all scheduling attempts unlike the scheduler's sampled plugin metrics.
+func WithPluginMetrics() Option {
+	return func(opt *options) {
+		opt.pluginMetricsEnabled = true
+	}
+}
+
+// WithResultRecorder creates an Option to append the per-plugin results of each scheduling attempt to the given file,
+// one ResultRecord per line in JSON Lines format, so that you can analyze the plugins' behavior offline.
+// The file grows unboundedly unless WithResultRecorderRotation is also given.
+func WithResultRecorder(path string) Option {
+	return func(opt *options) {
+		opt.resultRecorderPath = path
+	}
+}
+
+// WithResultRecorderRotation creates an Option to rotate the file given to WithResultRecorder before it exceeds maxSize bytes.
+// The rotated files are renamed to <path>.1, <path>.2, ..., and maxBackups of them are kept.
+// recorder.DefaultMaxBackups is used if maxBackups isn't positive.
+func WithResultRecorderRotation(maxSize int64, maxBackups int) Option {
+	return func(opt *options) {
+		opt.resultRecorderMaxSize = maxSize
+		opt.resultRecorderMaxBackups = maxBackups
+	}
+}
+
+const (
+	// ResultStoreAnnotation is the result store which reflects the results on the Pod's annotations. It's the default.
+	ResultStoreAnnotation = "annotation"
+	// ResultStoreCRD is the result store which writes the results to the SchedulingResult custom resource
+	// in the kube-scheduler-simulator.sigs.k8s.io group, which has the same name and namespace as the Pod.
+	ResultStoreCRD = "crd"
+)
+
+// WithResultStore creates an Option to choose where the results of each scheduling attempt are written,
+// ResultStoreAnnotation or ResultStoreCRD.
+// With ResultStoreCRD, the Pods are left untouched, and the CRD is applied on startup if it's missing.
+// NewSchedulerCommand fails with any other value.
+func WithResultStore(store string) Option {
+	return func(opt *options) {
+		opt.resultStore = store
+	}
+}
+
+// WithResultStoreHistoryLimit creates an Option to keep the results of the latest limit scheduling attempts in a SchedulingResult.
+// It only takes effect with WithResultStore(ResultStoreCRD). resultcrd.DefaultHistoryLimit is used if limit isn't positive.
+func WithResultStoreHistoryLimit(limit int) Option {
+	return func(opt *options) {
+		opt.resultStoreHistoryLimit = limit
+	}
+}
+
+// WithResultFilter creates an Option to store only the results of the given plugins, omitting the others'.
+// The selected node and the custom results are always stored. An empty pluginNames stores the results of all plugins.
+// It takes precedence over `--resultFilter` flag, which takes the comma-separated plugin names.
+func WithResultFilter(pluginNames []string) Option {
+	return func(opt *options) {
+		opt.resultFilter = append([]string{}, pluginNames...)
+	}
+}
+
+// WithStrictPluginValidation creates an Option to make NewSchedulerCommand fail with PluginRegistrationError
+// when the plugins registered by WithPlugin aren't enabled in any profile, or the enabled plugins aren't registered.
+// Otherwise, the mismatches are only logged as a warning.
+func WithStrictPluginValidation() Option {
+	return func(opt *options) {
+		opt.strictPluginValidation = true
+	}
+}
+
+// WithSchedulerConfig creates an Option to use the given scheduler config instead of loading it from file.
+// It takes precedence over WithSchedulerConfigPath and `--config` flag,
+// but NewSchedulerCommand fails if it's used together with WithSchedulerConfigPath.
+func WithSchedulerConfig(cfg *configv1.KubeSchedulerConfiguration) Option {
+	return func(opt *options) {
+		opt.schedulerConfig = cfg
+	}
+}
+
+// WithSchedulerConfigPath creates an Option to load the scheduler config from the given file.
+// It takes precedence over `--config` flag,
+// but NewSchedulerCommand fails if it's used together with WithSchedulerConfig.
+func WithSchedulerConfigPath(path string) Option {
+	return func(opt *options) {
+		opt.schedulerConfigPath = path
+	}
+}
+
+// WithFeatureGates creates an Option to set the feature gates of the scheduler, e.g., to test the alpha features.
+// It takes precedence over the feature gates file written by the simulator next to the scheduler config file.
+// NewSchedulerCommand fails with the list of the valid gates if any of the gates is unknown.
+func WithFeatureGates(gates map[string]bool) Option {
+	return func(opt *options) {
+		opt.featureGates = gates
+	}
+}
+
+// WithRandomSeed creates an Option to make the scheduler deterministic with seed,
+// e.g., to get the same placements every time the same scenario is run.
+// It takes precedence over the random seed file written by the simulator next to the scheduler config file.
+// See applyRandomSeed for how the scheduler becomes deterministic.
+func WithRandomSeed(seed int64) Option {
+	return func(opt *options) {
+		opt.randomSeed = &seed
+	}
+}
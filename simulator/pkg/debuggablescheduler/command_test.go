@@ -0,0 +1,219 @@
+package debuggablescheduler
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/component-base/featuregate"
+	v1 "k8s.io/kube-scheduler/config/v1"
+
+	simulatorconfig "sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
+	simulatorschedulerconfig "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/storereflector"
+)
+
+func Test_startServer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                 string
+		extenders            []v1.Extender
+		disableExtenderProxy bool
+		wantListener         bool
+	}{
+		{
+			name:         "no listener is opened when there are no extenders",
+			wantListener: false,
+		},
+		{
+			name:                 "no listener is opened when the proxy is disabled",
+			extenders:            []v1.Extender{{URLPrefix: "http://example.com"}},
+			disableExtenderProxy: true,
+			wantListener:         false,
+		},
+		{
+			name:         "the proxy listens when there are extenders",
+			extenders:    []v1.Extender{{URLPrefix: "http://example.com"}},
+			wantListener: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			port := freePort(t)
+			configs := Configs{
+				versioned:            &v1.KubeSchedulerConfiguration{Extenders: tt.extenders},
+				proxy:                ExtenderServerConfig{Host: "127.0.0.1", Port: port},
+				disableExtenderProxy: tt.disableExtenderProxy,
+			}
+
+			shutdownFn, err := startServer(configs, nil, nil)
+			require.NoError(t, err)
+			require.NotNil(t, shutdownFn)
+			defer shutdownFn()
+
+			l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+			if tt.wantListener {
+				assert.Error(t, err, "the port should be used by the proxy")
+				return
+			}
+			require.NoError(t, err, "the port should not be used")
+			l.Close()
+		})
+	}
+}
+
+// freePort returns a port which nothing listens on.
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	addr, ok := l.Addr().(*net.TCPAddr)
+	require.True(t, ok)
+	return addr.Port
+}
+
+// Test_newSchedulerCommand_Restart isn't run in parallel
+// because the scheduler config which the running scheduler uses is overridden globally until it's canceled.
+//
+//nolint:paralleltest
+func Test_newSchedulerCommand_Restart(t *testing.T) {
+	port := freePort(t)
+	newConfigs := func() Configs {
+		versioned, err := simulatorschedulerconfig.DefaultSchedulerConfig()
+		require.NoError(t, err)
+		versioned.Extenders = []v1.Extender{{URLPrefix: "http://example.com", FilterVerb: "filter"}}
+		versioned, err = scheduler.ConvertConfigurationForSimulator(versioned)
+		require.NoError(t, err)
+		internalCfg, err := scheduler.ConvertSchedulerConfigToInternalConfig(versioned)
+		require.NoError(t, err)
+		return Configs{
+			versioned:   versioned,
+			internalCfg: internalCfg,
+			clientSet:   fake.NewSimpleClientset(),
+			sharedStore: storereflector.New(),
+		}
+	}
+	opt := newOptions([]Option{WithExtenderProxyAddress("127.0.0.1", port)})
+
+	for i := 0; i < 2; i++ {
+		command, cancel, err := newSchedulerCommand(newConfigs(), opt)
+		require.NoError(t, err, "the scheduler command should be created in the attempt %d", i)
+		assert.NotNil(t, command)
+		_, err = net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+		assert.Error(t, err, "the proxy should listen on the port in the attempt %d", i)
+
+		cancel()
+		// calling cancel again does nothing.
+		cancel()
+		l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+		require.NoError(t, err, "the port should be released in the attempt %d", i)
+		l.Close()
+	}
+}
+
+func TestWithSimulatorPorts(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		ports    simulatorconfig.Ports
+		wantHost string
+		wantPort int
+	}{
+		{
+			name:     "the proxy port of the simulator",
+			ports:    simulatorconfig.Ports{API: simulatorconfig.Listener{Port: 1212}, ExtenderProxy: simulatorconfig.Listener{Address: "127.0.0.1", Port: 1213}},
+			wantHost: "127.0.0.1",
+			wantPort: 1213,
+		},
+		{
+			name:     "the proxy port isn't configured",
+			ports:    simulatorconfig.Ports{API: simulatorconfig.Listener{Port: 1212}},
+			wantHost: "10.0.0.1",
+			wantPort: 8080,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opt := newOptions([]Option{WithExtenderProxyAddress("10.0.0.1", 8080), WithSimulatorPorts(tt.ports)})
+			assert.Equal(t, tt.wantHost, opt.extenderProxyHost)
+			assert.Equal(t, tt.wantPort, opt.extenderProxyPort)
+		})
+	}
+}
+
+//nolint:paralleltest // cannot use t.Parallel because applyFeatureGates changes the feature gates of the process.
+func Test_applyFeatureGates(t *testing.T) {
+	const gate = "DynamicResourceAllocation"
+	t.Cleanup(func() {
+		require.NoError(t, utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{gate: false}))
+	})
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "scheduler.yaml")
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		file    string
+		want    bool
+		wantErr string
+	}{
+		{
+			name: "the gates of the option",
+			opts: []Option{WithFeatureGates(map[string]bool{gate: true})},
+			want: true,
+		},
+		{
+			name: "the gates in the file written by the simulator",
+			file: gate + ": true\n",
+			want: true,
+		},
+		{
+			name: "the option takes precedence over the file",
+			opts: []Option{WithFeatureGates(map[string]bool{gate: false})},
+			file: gate + ": true\n",
+			want: false,
+		},
+		{
+			name: "without the gates",
+			want: false,
+		},
+		{
+			name:    "unknown gate",
+			opts:    []Option{WithFeatureGates(map[string]bool{"NoSuchFeature": true})},
+			wantErr: "the valid gates are [",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, utilfeature.DefaultMutableFeatureGate.SetFromMap(map[string]bool{gate: false}))
+			path := simulatorschedulerconfig.FeatureGatesPath(configFile)
+			require.NoError(t, os.RemoveAll(path))
+			if tt.file != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.file), 0o600))
+			}
+
+			err := applyFeatureGates(newOptions(tt.opts), configFile)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, utilfeature.DefaultFeatureGate.Enabled(featuregate.Feature(gate)))
+		})
+	}
+}
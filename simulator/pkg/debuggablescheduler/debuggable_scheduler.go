@@ -3,9 +3,14 @@ package debuggablescheduler
 import (
 	"context"
 	"flag"
+	"math/rand"
+	"net"
 	"os"
+	"strings"
+	"sync"
 
 	"golang.org/x/xerrors"
+	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -13,27 +18,82 @@ import (
 	_ "k8s.io/component-base/logs/json/register" // for JSON log format registration
 	_ "k8s.io/component-base/metrics/prometheus/clientgo"
 	_ "k8s.io/component-base/metrics/prometheus/version" // for version metric registration
+	"k8s.io/klog/v2"
 	v1 "k8s.io/kube-scheduler/config/v1"
 	"k8s.io/kubernetes/cmd/kube-scheduler/app"
 	"k8s.io/kubernetes/pkg/scheduler/apis/config"
 	"k8s.io/kubernetes/pkg/scheduler/apis/config/scheme"
 	configv1 "k8s.io/kubernetes/pkg/scheduler/apis/config/v1"
 	"k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	"k8s.io/utils/ptr"
 
 	simulatorconfig "sigs.k8s.io/kube-scheduler-simulator/simulator/config"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
 	simulatorschedulerconfig "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/config"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/resultcrd"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/storereflector"
 )
 
 type Configs struct {
 	versioned   *v1.KubeSchedulerConfiguration
 	internalCfg *config.KubeSchedulerConfiguration
-	clientSet   *clientset.Clientset
+	clientSet   clientset.Interface
 	sharedStore storereflector.Reflector
-	port        int
+	// proxy is the address and TLS config of the proxy server for Extenders.
+	proxy ExtenderServerConfig
+	// disableExtenderProxy disables the proxy server for Extenders.
+	disableExtenderProxy bool
+	// resultFilter is the names of the plugins whose results are stored. The results of all plugins are stored if it's empty.
+	resultFilter []string
+	// strictPluginValidation makes CreateOptions fail when the registered out-of-tree plugins don't match the enabled ones.
+	strictPluginValidation bool
+	// randomSeed is the seed the random numbers of the scheduler are seeded with. The scheduler is random if it's nil.
+	randomSeed *int64
+}
+
+// extenderProxyEnabled returns true if the requests to Extenders should go through the proxy server.
+// The proxy server is needless when there's no Extender.
+func (c Configs) extenderProxyEnabled() bool {
+	return !c.disableExtenderProxy && len(c.versioned.Extenders) != 0
+}
+
+var (
+	defineFlagsOnce sync.Once
+	// flags defined in the upstream scheduler
+	configFileFlag *string
+	masterFlag     *string
+	// proxyPortFlag indicates port number of the proxy server for Extenders.
+	// This flag is debuggable_scheduler's own.
+	proxyPortFlag *int
+	// resultFilterFlag is the comma-separated names of the plugins whose results are stored.
+	// This flag is debuggable_scheduler's own.
+	resultFilterFlag *string
+)
+
+// parseFlags parses the flags and returns the values of config, master, proxyPort and resultFilter.
+// The flags are defined only once so that it can be called again, e.g., when the scheduler is restarted.
+func parseFlags() (string, string, int, []string) {
+	defineFlagsOnce.Do(func() {
+		configFileFlag = flag.String("config", "", "")
+		masterFlag = flag.String("master", "", "")
+		proxyPortFlag = flag.Int("proxyPort", 1212, "")
+		resultFilterFlag = flag.String("resultFilter", "", "")
+	})
+	flag.Parse()
+	return *configFileFlag, *masterFlag, *proxyPortFlag, parsePluginNames(*resultFilterFlag)
+}
+
+// parsePluginNames splits the comma-separated plugin names, ignoring the empty ones.
+func parsePluginNames(s string) []string {
+	var ret []string
+	for _, n := range strings.Split(s, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			ret = append(ret, n)
+		}
+	}
+	return ret
 }
 
 // NewConfigs loads flags and initializes kube scheduler configuration and clientSet.
@@ -43,16 +103,30 @@ type Configs struct {
 // - converts it for enabling wrapped plugins.
 // - reads the kubeConfig and creates clientSet to enables storereflector to communicates with the api-server.
 // - initialize the store reflector.
-func NewConfigs() (Configs, error) {
-	// flags defined in the upstream scheduler
-	configFile := flag.String("config", "", "")
-	master := flag.String("master", "", "")
-	// port indicates port number of the proxy server for Extenders.
-	// This flag is debuggable_scheduler's own.
-	port := flag.Int("proxyPort", 1212, "")
-	flag.Parse()
+//
+// The scheduler config can be injected with WithSchedulerConfig or WithSchedulerConfigPath in opts.
+// See resolveKubeSchedulerConfig for the precedence.
+func NewConfigs(opts ...Option) (Configs, error) {
+	return newConfigs(newOptions(opts))
+}
 
-	versionedcfg, err := loadKubeSchedulerConfig(configFile)
+func newConfigs(opt *options) (Configs, error) {
+	configFile, master, port, resultFilter := parseFlags()
+	if opt.resultFilter != nil {
+		// WithResultFilter takes precedence over the flag.
+		resultFilter = opt.resultFilter
+	}
+
+	// The feature gates must be set before the scheduler config is loaded because the defaulting depends on them.
+	if err := applyFeatureGates(opt, configFile); err != nil {
+		return Configs{}, xerrors.Errorf("apply feature gates: %w", err)
+	}
+	randomSeed, err := applyRandomSeed(opt, configFile)
+	if err != nil {
+		return Configs{}, xerrors.Errorf("apply random seed: %w", err)
+	}
+
+	versionedcfg, err := resolveKubeSchedulerConfig(opt, configFile)
 	if err != nil {
 		return Configs{}, xerrors.Errorf("load scheduler config: %w", err)
 	}
@@ -73,61 +147,158 @@ func NewConfigs() (Configs, error) {
 		return Configs{}, xerrors.Errorf("convert scheduler config to internal one: %w", err)
 	}
 
-	clientSet, err := loadKubeConfig(master, internalCfg)
+	kubeConfig, err := loadKubeConfig(&master, internalCfg)
 	if err != nil {
 		return Configs{}, xerrors.Errorf("load kubeconfig: %w", err)
 	}
+	clientSet, err := clientset.NewForConfig(kubeConfig)
+	if err != nil {
+		return Configs{}, xerrors.Errorf("creates a new Clientset for kubeconfig: %w", err)
+	}
+
+	sharedStore, err := newSharedStore(kubeConfig, opt)
+	if err != nil {
+		return Configs{}, xerrors.Errorf("initialize the store reflector: %w", err)
+	}
 
 	return Configs{
-		versioned:   versioned,
-		internalCfg: internalCfg,
-		clientSet:   clientSet,
-		sharedStore: storereflector.New(),
-		port:        *port,
+		versioned:    versioned,
+		internalCfg:  internalCfg,
+		clientSet:    clientSet,
+		sharedStore:  sharedStore,
+		proxy:        ExtenderServerConfig{Port: port},
+		resultFilter: resultFilter,
+		randomSeed:   randomSeed,
 	}, nil
 }
 
+// newSharedStore initializes the store reflector which writes the results to the place specified by WithResultStore.
+// When the results are written to the SchedulingResult custom resources, it applies the CRD first if it's missing.
+func newSharedStore(kubeConfig *restclient.Config, opt *options) (storereflector.Reflector, error) {
+	switch opt.resultStore {
+	case "", ResultStoreAnnotation:
+		return storereflector.New(), nil
+	case ResultStoreCRD:
+		dynamicClient, err := dynamic.NewForConfig(kubeConfig)
+		if err != nil {
+			return nil, xerrors.Errorf("creates a new dynamic client for kubeconfig: %w", err)
+		}
+		if err := resultcrd.EnsureCRD(context.Background(), dynamicClient); err != nil {
+			return nil, xerrors.Errorf("apply the CRD of SchedulingResult: %w", err)
+		}
+		return storereflector.New(storereflector.WithResultWriter(resultcrd.NewWriter(dynamicClient, opt.resultStoreHistoryLimit))), nil
+	default:
+		return nil, xerrors.Errorf("unknown result store %q: it must be %q or %q", opt.resultStore, ResultStoreAnnotation, ResultStoreCRD)
+	}
+}
+
 // CreateOptions creates the option which can be help with running the external scheduler
 // and resister the storereflector to informer.
 // Then, here makes the defaulting func of the KubeSchedulerConfig always returns the converted one.
 // We can let the scheduler use the converted configuration under any circumstances because the scheduler will always use this defaulting func to load the configuration.
-func CreateOptions(configs Configs, pluginExtender map[string]plugin.PluginExtenderInitializer) ([]app.Option, func(), error) {
-	// Override the Extenders config so that the connection is directed to the simulator server.
-	extender.OverrideExtendersCfgToSimulator(configs.versioned, configs.port)
+// profilePluginExtender is applied to the plugins in the specific profiles, taking precedence over pluginExtender.
+// resultHook is optional; if it's non-nil, it receives the results of each scheduling attempt.
+// It also cross-checks the registered out-of-tree plugins against the plugins enabled in all profiles,
+// and warns the mismatches, or fails with PluginRegistrationError if WithStrictPluginValidation is given.
+func CreateOptions(configs Configs, pluginExtender map[string]plugin.PluginExtenderInitializer, profilePluginExtender plugin.ProfilePluginExtenders, resultHook plugin.ResultHook) ([]app.Option, func(), error) {
+	if err := validatePluginRegistration(configs.versioned, simulatorschedulerconfig.InTreeRegistries(), simulatorschedulerconfig.OutOfTreeRegistries(), configs.strictPluginValidation); err != nil {
+		return nil, nil, xerrors.Errorf("validate plugin registration: %w", err)
+	}
+
+	if configs.extenderProxyEnabled() {
+		// Override the Extenders config so that the connection is directed to the proxy server.
+		overrideExtendersCfgToProxy(configs.versioned, configs.proxy)
+	}
 
-	opts, err := CreateOptionForPlugin(pluginExtender, configs.sharedStore, configs.internalCfg)
+	opts, err := CreateOptionForPlugin(pluginExtender, profilePluginExtender, configs.sharedStore, configs.internalCfg, resultHook, configs.resultFilter)
 	if err != nil {
 		return nil, nil, xerrors.Errorf("CreateOptionForPlugin: %w", err)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	cancel := func() {
+		cancelCtx()
+		// Restore the defaulting func so that the next config is loaded as is, e.g., when the scheduler is restarted.
+		setSchedulerConfigOverride(nil, nil)
+	}
 	if err := configs.sharedStore.ResisterResultSavingToInformer(configs.clientSet, ctx.Done()); err != nil {
 		return nil, cancel, xerrors.Errorf("ResisterResultSavingToInformer of sharedStore: %w", err)
 	}
 
-	// black magic: We need to use the scheduler config converted for the simulator in the external scheduler.
-	// Here, we overwrite the defaulting func for KubeSchedulerConfiguration,
-	// so that user's config will be replaced with the one we created here
-	// when the scheduler loads the scheduler config
-	// or when loading the default scheduler config.
-	scheme.Scheme.AddTypeDefaultingFunc(&v1.KubeSchedulerConfiguration{}, func(obj interface{}) {
-		c, ok := obj.(*v1.KubeSchedulerConfiguration)
-		if !ok {
-			panic("unexpected type")
-		}
-		configv1.SetObjectDefaults_KubeSchedulerConfiguration(c)
-		c.Profiles = configs.versioned.Profiles
-		c.Extenders = configs.versioned.Extenders
-	})
+	var parallelism *int32
+	if configs.randomSeed != nil {
+		// The Nodes found feasible first are scored when percentageOfNodesToScore is less than 100%,
+		// and which ones are found first depends on the timing of the goroutines filtering the Nodes in parallel.
+		parallelism = ptr.To[int32](1)
+	}
+	setSchedulerConfigOverride(configs.versioned, parallelism)
 
 	return opts, cancel, nil
 }
 
+var (
+	registerDefaultingFuncOnce sync.Once
+	schedulerConfigOverrideMu  sync.RWMutex
+	schedulerConfigOverride    *v1.KubeSchedulerConfiguration
+	parallelismOverride        *int32
+)
+
+// setSchedulerConfigOverride makes the scheduler use the profiles and the extenders in cfg, and parallelism if it's non-nil,
+// regardless of the scheduler config it loads. nil restores the normal defaulting.
+//
+// black magic: We need to use the scheduler config converted for the simulator in the external scheduler.
+// Here, we overwrite the defaulting func for KubeSchedulerConfiguration,
+// so that user's config will be replaced with the one we created here
+// when the scheduler loads the scheduler config
+// or when loading the default scheduler config.
+func setSchedulerConfigOverride(cfg *v1.KubeSchedulerConfiguration, parallelism *int32) {
+	registerDefaultingFuncOnce.Do(func() {
+		scheme.Scheme.AddTypeDefaultingFunc(&v1.KubeSchedulerConfiguration{}, func(obj interface{}) {
+			c, ok := obj.(*v1.KubeSchedulerConfiguration)
+			if !ok {
+				panic("unexpected type")
+			}
+			configv1.SetObjectDefaults_KubeSchedulerConfiguration(c)
+
+			schedulerConfigOverrideMu.RLock()
+			defer schedulerConfigOverrideMu.RUnlock()
+			if schedulerConfigOverride != nil {
+				c.Profiles = schedulerConfigOverride.Profiles
+				c.Extenders = schedulerConfigOverride.Extenders
+			}
+			if parallelismOverride != nil {
+				c.Parallelism = ptr.To(*parallelismOverride)
+			}
+		})
+	})
+
+	schedulerConfigOverrideMu.Lock()
+	defer schedulerConfigOverrideMu.Unlock()
+	schedulerConfigOverride = cfg
+	parallelismOverride = parallelism
+}
+
+// overrideExtendersCfgToProxy rewrites the Extenders config so that the scheduler sends the requests to the proxy server.
+func overrideExtendersCfgToProxy(cfg *v1.KubeSchedulerConfiguration, proxy ExtenderServerConfig) {
+	host := proxy.Host
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		// The proxy server listens on all addresses.
+		host = "localhost"
+	}
+	var tlsConfig *v1.ExtenderTLSConfig
+	if proxy.TLSEnabled() {
+		// Trust the proxy server's certificate itself so that self-signed certificates can be used.
+		tlsConfig = &v1.ExtenderTLSConfig{CAFile: proxy.CertFile}
+	}
+	extender.OverrideExtendersCfgToProxy(cfg, host, proxy.Port, tlsConfig)
+}
+
 // CreateOptionForPlugin creates Option for in/out of tree plugins.
 // It does create the wrapped plugin registries and return the registries as app.Option.
-func CreateOptionForPlugin(pluginExtender map[string]plugin.PluginExtenderInitializer, sharedStore storereflector.Reflector, internalCfg *config.KubeSchedulerConfiguration) ([]app.Option, error) {
+// Only the results of the plugins in resultFilter are stored unless it's empty.
+func CreateOptionForPlugin(pluginExtender map[string]plugin.PluginExtenderInitializer, profilePluginExtender plugin.ProfilePluginExtenders, sharedStore storereflector.Reflector, internalCfg *config.KubeSchedulerConfiguration, resultHook plugin.ResultHook, resultFilter []string) ([]app.Option, error) {
 	// loads in/out of tree plugins and wraps it for debuggable.
-	registry, err := plugin.NewRegistry(sharedStore, internalCfg, pluginExtender)
+	registry, err := plugin.NewRegistry(sharedStore, internalCfg, pluginExtender, profilePluginExtender, resultHook, resultFilter)
 	if err != nil {
 		return nil, xerrors.Errorf("convert scheduler config to apply: %w", err)
 	}
@@ -135,6 +306,113 @@ func CreateOptionForPlugin(pluginExtender map[string]plugin.PluginExtenderInitia
 	return generateWithPluginOptions(registry), nil
 }
 
+// ErrConflictingSchedulerConfig is returned when both WithSchedulerConfig and WithSchedulerConfigPath are given.
+var ErrConflictingSchedulerConfig = xerrors.New("only one of WithSchedulerConfig and WithSchedulerConfigPath can be specified")
+
+// resolveKubeSchedulerConfig decides the scheduler config to use in the following order of precedence:
+// 1. the in-memory config given by WithSchedulerConfig.
+// 2. the config file given by WithSchedulerConfigPath.
+// 3. the config file given by `--config` flag.
+// 4. the default config.
+// It returns ErrConflictingSchedulerConfig if both WithSchedulerConfig and WithSchedulerConfigPath are given,
+// because it's likely a mistake.
+func resolveKubeSchedulerConfig(opt *options, configFlag string) (*v1.KubeSchedulerConfiguration, error) {
+	if opt.schedulerConfig != nil && opt.schedulerConfigPath != "" {
+		return nil, ErrConflictingSchedulerConfig
+	}
+	if opt.schedulerConfig != nil {
+		cfg := opt.schedulerConfig.DeepCopy()
+		// Fill the defaults as the decoder does when loading the config from file.
+		scheme.Scheme.Default(cfg)
+		return cfg, nil
+	}
+	configFile := configFlag
+	if opt.schedulerConfigPath != "" {
+		configFile = opt.schedulerConfigPath
+	}
+	return loadKubeSchedulerConfig(&configFile)
+}
+
+// applyFeatureGates sets the feature gates given by WithFeatureGates to the scheduler.
+// Without it, the gates are loaded from the feature gates file next to the scheduler config file, if any,
+// which the simulator writes from its schedulerFeatureGates config.
+// It fails with the list of the valid gates if any of the gates is unknown.
+func applyFeatureGates(opt *options, configFlag string) error {
+	gates := opt.featureGates
+	if gates == nil {
+		configFile := configFlag
+		if opt.schedulerConfigPath != "" {
+			configFile = opt.schedulerConfigPath
+		}
+		if configFile == "" {
+			return nil
+		}
+		var err error
+		gates, err = simulatorschedulerconfig.LoadFeatureGates(simulatorschedulerconfig.FeatureGatesPath(configFile))
+		if err != nil {
+			return xerrors.Errorf("load feature gates: %w", err)
+		}
+	}
+	if len(gates) == 0 {
+		return nil
+	}
+	if err := simulatorschedulerconfig.SetFeatureGates(gates); err != nil {
+		return err
+	}
+	klog.InfoS("Set the feature gates of the scheduler", "featureGates", gates)
+	return nil
+}
+
+// ErrRandomSeedDisabled is returned when the random numbers can't be seeded.
+var ErrRandomSeedDisabled = xerrors.New("the random numbers can't be seeded: build the scheduler with //go:debug randseednop=0 in the main package")
+
+// applyRandomSeed makes the scheduler deterministic with the seed given by WithRandomSeed.
+// Without it, the seed is loaded from the random seed file next to the scheduler config file, if any,
+// which the simulator writes from its schedulerRandomSeed config. It returns the seed applied, or nil if there's none.
+//
+// The scheduler breaks the ties of the scores with the global random numbers of math/rand, which are seeded here,
+// and CreateOptions makes the scheduler filter the Nodes one by one so that the same Nodes are always scored.
+// The global random numbers can be seeded only when the program is built with randseednop=0 since Go 1.24,
+// and ErrRandomSeedDisabled is returned otherwise.
+func applyRandomSeed(opt *options, configFlag string) (*int64, error) {
+	seed := opt.randomSeed
+	if seed == nil {
+		configFile := configFlag
+		if opt.schedulerConfigPath != "" {
+			configFile = opt.schedulerConfigPath
+		}
+		if configFile == "" {
+			return nil, nil
+		}
+		var err error
+		seed, err = simulatorschedulerconfig.LoadRandomSeed(simulatorschedulerconfig.RandomSeedPath(configFile))
+		if err != nil {
+			return nil, xerrors.Errorf("load random seed: %w", err)
+		}
+		if seed == nil {
+			return nil, nil
+		}
+	}
+	if err := seedRandomNumbers(*seed); err != nil {
+		return nil, err
+	}
+	klog.InfoS("Seeded the random numbers of the scheduler", "seed", *seed)
+	return seed, nil
+}
+
+// seedRandomNumbers seeds the global random numbers of math/rand, and checks that they're actually seeded.
+func seedRandomNumbers(seed int64) error {
+	//nolint:staticcheck // the scheduler uses the global random numbers, which can't be replaced.
+	rand.Seed(seed)
+	if rand.Int63() != rand.New(rand.NewSource(seed)).Int63() { //nolint:gosec // not for security.
+		return ErrRandomSeedDisabled
+	}
+	// Seed again since the check consumes the first number.
+	//nolint:staticcheck // the scheduler uses the global random numbers, which can't be replaced.
+	rand.Seed(seed)
+	return nil
+}
+
 // loadKubeSchedulerConfig loads specified scheduler config or default one.
 func loadKubeSchedulerConfig(configFile *string) (*v1.KubeSchedulerConfiguration, error) {
 	var versionedcfg *v1.KubeSchedulerConfiguration
@@ -154,7 +432,7 @@ func loadKubeSchedulerConfig(configFile *string) (*v1.KubeSchedulerConfiguration
 }
 
 // loadKubeConfig loads kubeConfig.
-func loadKubeConfig(master *string, internalCfg *config.KubeSchedulerConfiguration) (*clientset.Clientset, error) {
+func loadKubeConfig(master *string, internalCfg *config.KubeSchedulerConfiguration) (*restclient.Config, error) {
 	kubeconfig, err := simulatorconfig.GetKubeClientConfig()
 	if err != nil {
 		return nil, xerrors.Errorf("get kubeconfig: %w", err)
@@ -165,11 +443,7 @@ func loadKubeConfig(master *string, internalCfg *config.KubeSchedulerConfigurati
 			return nil, xerrors.Errorf("get kubeconfig specified in config: %w", err)
 		}
 	}
-	clientSet, err := clientset.NewForConfig(kubeconfig)
-	if err != nil {
-		return nil, xerrors.Errorf("creates a new Clientset for kubeconfig: %w", err)
-	}
-	return clientSet, nil
+	return kubeconfig, nil
 }
 
 // createKubeConfig creates a kubeConfig from the given config and masterOverride.
@@ -0,0 +1,121 @@
+package debuggablescheduler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/kube-scheduler/config/v1"
+	"k8s.io/utils/ptr"
+)
+
+func Test_resolveKubeSchedulerConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pathConfig := writeSchedulerConfig(t, dir, "path-scheduler")
+	flagConfig := writeSchedulerConfig(t, dir, "flag-scheduler")
+	inMemory := &v1.KubeSchedulerConfiguration{
+		Profiles: []v1.KubeSchedulerProfile{{SchedulerName: ptr.To("in-memory-scheduler")}},
+	}
+
+	tests := []struct {
+		name              string
+		opts              []Option
+		configFlag        string
+		wantSchedulerName string
+		wantErr           error
+	}{
+		{
+			name:              "in-memory config takes precedence over the flag",
+			opts:              []Option{WithSchedulerConfig(inMemory)},
+			configFlag:        flagConfig,
+			wantSchedulerName: "in-memory-scheduler",
+		},
+		{
+			name:              "path option takes precedence over the flag",
+			opts:              []Option{WithSchedulerConfigPath(pathConfig)},
+			configFlag:        flagConfig,
+			wantSchedulerName: "path-scheduler",
+		},
+		{
+			name:              "the flag is used without the options",
+			configFlag:        flagConfig,
+			wantSchedulerName: "flag-scheduler",
+		},
+		{
+			name:              "the default config is used without the options and the flag",
+			wantSchedulerName: "default-scheduler",
+		},
+		{
+			name:    "fail if both in-memory config and path are given",
+			opts:    []Option{WithSchedulerConfig(inMemory), WithSchedulerConfigPath(pathConfig)},
+			wantErr: ErrConflictingSchedulerConfig,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := resolveKubeSchedulerConfig(newOptions(tt.opts), tt.configFlag)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got.Profiles, 1)
+			assert.Equal(t, tt.wantSchedulerName, *got.Profiles[0].SchedulerName)
+			// defaults are filled regardless of where the config comes from.
+			assert.NotNil(t, got.PercentageOfNodesToScore)
+		})
+	}
+
+	// The in-memory config given by users isn't modified.
+	assert.Nil(t, inMemory.PercentageOfNodesToScore)
+}
+
+func writeSchedulerConfig(t *testing.T, dir, schedulerName string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, schedulerName+".yaml")
+	cfg := `apiVersion: kubescheduler.config.k8s.io/v1
+kind: KubeSchedulerConfiguration
+profiles:
+- schedulerName: ` + schedulerName + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(cfg), 0o600))
+	return path
+}
+
+func Test_parsePluginNames(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		s    string
+		want []string
+	}{
+		{
+			name: "split the comma-separated names",
+			s:    "NodeResourcesFit, MyPlugin",
+			want: []string{"NodeResourcesFit", "MyPlugin"},
+		},
+		{
+			name: "ignore the empty names",
+			s:    ",NodeResourcesFit,,",
+			want: []string{"NodeResourcesFit"},
+		},
+		{
+			name: "empty string",
+			s:    "",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, parsePluginNames(tt.s))
+		})
+	}
+}
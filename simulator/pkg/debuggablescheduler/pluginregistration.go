@@ -0,0 +1,87 @@
+package debuggablescheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	v1 "k8s.io/kube-scheduler/config/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin"
+)
+
+// PluginRegistrationError is returned from CreateOptions under WithStrictPluginValidation
+// when the registered out-of-tree plugins don't match the plugins enabled in the scheduler config.
+type PluginRegistrationError struct {
+	// Unused is the names of the out-of-tree plugins which are registered, but not enabled in any profile.
+	Unused []string
+	// Unregistered is the names of the plugins which are enabled in any profile, but not registered.
+	Unregistered []string
+}
+
+func (e *PluginRegistrationError) Error() string {
+	msgs := []string{}
+	if len(e.Unused) != 0 {
+		msgs = append(msgs, fmt.Sprintf("registered but not enabled in any profile: [%s]", strings.Join(e.Unused, ", ")))
+	}
+	if len(e.Unregistered) != 0 {
+		msgs = append(msgs, fmt.Sprintf("enabled but not registered: [%s]", strings.Join(e.Unregistered, ", ")))
+	}
+	return "plugin registration mismatch: " + strings.Join(msgs, "; ")
+}
+
+// validatePluginRegistration cross-checks the out-of-tree registry against the plugins enabled in all profiles of cfg,
+// which is the config converted for the simulator.
+// It only logs the mismatches as a warning unless strict is true, and returns PluginRegistrationError then.
+func validatePluginRegistration(cfg *v1.KubeSchedulerConfiguration, inTree, outOfTree runtime.Registry, strict bool) error {
+	unused, unregistered := checkPluginRegistration(cfg, inTree, outOfTree)
+	if len(unused) == 0 && len(unregistered) == 0 {
+		return nil
+	}
+	err := &PluginRegistrationError{Unused: unused, Unregistered: unregistered}
+	if strict {
+		return err
+	}
+	klog.Warningf("!!! %s. Check the plugin names given to WithPlugin and the ones enabled in the KubeSchedulerConfiguration !!!", err.Error())
+	return nil
+}
+
+// checkPluginRegistration returns the names of the out-of-tree plugins which aren't enabled in any profile
+// and the names of the enabled plugins which are neither in-tree nor out-of-tree ones, both sorted.
+func checkPluginRegistration(cfg *v1.KubeSchedulerConfiguration, inTree, outOfTree runtime.Registry) ([]string, []string) {
+	enabled := sets.New[string]()
+	for _, p := range cfg.Profiles {
+		if p.Plugins == nil {
+			continue
+		}
+		for _, set := range []v1.PluginSet{
+			p.Plugins.PreEnqueue, p.Plugins.QueueSort, p.Plugins.PreFilter, p.Plugins.Filter, p.Plugins.PostFilter,
+			p.Plugins.PreScore, p.Plugins.Score, p.Plugins.Reserve, p.Plugins.Permit, p.Plugins.PreBind, p.Plugins.Bind,
+			p.Plugins.PostBind, p.Plugins.MultiPoint,
+		} {
+			for _, e := range set.Enabled {
+				enabled.Insert(plugin.OriginalPluginName(e.Name))
+			}
+		}
+	}
+
+	var unused, unregistered []string
+	for name := range outOfTree {
+		if !enabled.Has(name) {
+			unused = append(unused, name)
+		}
+	}
+	for name := range enabled {
+		_, isInTree := inTree[name]
+		_, isOutOfTree := outOfTree[name]
+		if !isInTree && !isOutOfTree {
+			unregistered = append(unregistered, name)
+		}
+	}
+	sort.Strings(unused)
+	sort.Strings(unregistered)
+	return unused, unregistered
+}
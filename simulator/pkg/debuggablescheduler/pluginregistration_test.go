@@ -0,0 +1,89 @@
+package debuggablescheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/kube-scheduler/config/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+)
+
+func Test_validatePluginRegistration(t *testing.T) {
+	t.Parallel()
+
+	inTree := runtime.Registry{"NodeResourcesFit": nil, "PrioritySort": nil}
+	// cfg returns the config converted for the simulator, which enables the given plugins in the profiles.
+	cfg := func(profiles ...[]string) *v1.KubeSchedulerConfiguration {
+		c := &v1.KubeSchedulerConfiguration{}
+		for _, enabled := range profiles {
+			pls := &v1.Plugins{QueueSort: v1.PluginSet{Enabled: []v1.Plugin{{Name: "PrioritySort"}}}}
+			for _, name := range enabled {
+				pls.MultiPoint.Enabled = append(pls.MultiPoint.Enabled, v1.Plugin{Name: name + "Wrapped"})
+			}
+			c.Profiles = append(c.Profiles, v1.KubeSchedulerProfile{Plugins: pls})
+		}
+		return c
+	}
+
+	tests := []struct {
+		name      string
+		cfg       *v1.KubeSchedulerConfiguration
+		outOfTree runtime.Registry
+		strict    bool
+		wantErr   *PluginRegistrationError
+	}{
+		{
+			name:      "all registered plugins are enabled",
+			cfg:       cfg([]string{"NodeResourcesFit", "MyPlugin"}),
+			outOfTree: runtime.Registry{"MyPlugin": nil},
+			strict:    true,
+		},
+		{
+			name:      "the plugin enabled only in one of the profiles is used",
+			cfg:       cfg([]string{"NodeResourcesFit"}, []string{"MyPlugin"}),
+			outOfTree: runtime.Registry{"MyPlugin": nil},
+			strict:    true,
+		},
+		{
+			name:      "registered but not enabled",
+			cfg:       cfg([]string{"NodeResourcesFit"}),
+			outOfTree: runtime.Registry{"MyPlugin": nil, "AnotherPlugin": nil},
+			strict:    true,
+			wantErr:   &PluginRegistrationError{Unused: []string{"AnotherPlugin", "MyPlugin"}},
+		},
+		{
+			name:      "enabled but not registered, e.g., misspelled",
+			cfg:       cfg([]string{"NodeResourcesFit", "MyPlugn"}),
+			outOfTree: runtime.Registry{"MyPlugin": nil},
+			strict:    true,
+			wantErr:   &PluginRegistrationError{Unused: []string{"MyPlugin"}, Unregistered: []string{"MyPlugn"}},
+		},
+		{
+			name:      "only warn the mismatches without strict",
+			cfg:       cfg([]string{"NodeResourcesFit", "MyPlugn"}),
+			outOfTree: runtime.Registry{"MyPlugin": nil},
+			strict:    false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validatePluginRegistration(tt.cfg, inTree, tt.outOfTree, tt.strict)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var got *PluginRegistrationError
+			require.ErrorAs(t, err, &got)
+			assert.Equal(t, tt.wantErr, got)
+		})
+	}
+}
+
+func TestPluginRegistrationError_Error(t *testing.T) {
+	t.Parallel()
+	err := &PluginRegistrationError{Unused: []string{"MyPlugin"}, Unregistered: []string{"MyPlugn", "Other"}}
+	assert.Equal(t, "plugin registration mismatch: registered but not enabled in any profile: [MyPlugin]; enabled but not registered: [MyPlugn, Other]", err.Error())
+}
@@ -0,0 +1,191 @@
+//go:debug randseednop=0
+
+package debuggablescheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/events"
+	kubescheduler "k8s.io/kubernetes/pkg/scheduler"
+	"k8s.io/kubernetes/pkg/scheduler/profile"
+	"k8s.io/utils/ptr"
+
+	simulatorschedulerconfig "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/config"
+)
+
+//nolint:paralleltest // cannot use t.Parallel because applyRandomSeed seeds the random numbers of the process.
+func Test_applyRandomSeed(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "scheduler.yaml")
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		configFlag string
+		file       string
+		want       *int64
+		wantErr    bool
+	}{
+		{
+			name:       "the seed of the option",
+			opts:       []Option{WithRandomSeed(1)},
+			configFlag: configFile,
+			want:       ptr.To[int64](1),
+		},
+		{
+			name:       "the seed in the file written by the simulator",
+			configFlag: configFile,
+			file:       "seed: 2\n",
+			want:       ptr.To[int64](2),
+		},
+		{
+			name:       "the option takes precedence over the file",
+			opts:       []Option{WithRandomSeed(3)},
+			configFlag: configFile,
+			file:       "seed: 2\n",
+			want:       ptr.To[int64](3),
+		},
+		{
+			name:       "without the seed",
+			configFlag: configFile,
+		},
+		{
+			name: "without the config file",
+		},
+		{
+			name:       "invalid file",
+			configFlag: configFile,
+			file:       "seed: two\n",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := simulatorschedulerconfig.RandomSeedPath(configFile)
+			require.NoError(t, os.RemoveAll(path))
+			if tt.file != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.file), 0o600))
+			}
+
+			got, err := applyRandomSeed(newOptions(tt.opts), tt.configFlag)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+//nolint:paralleltest // cannot use t.Parallel because the scheduler uses the random numbers of the process.
+func TestRandomSeed_deterministicPlacements(t *testing.T) {
+	// All the nodes tie for the first pod, and the rest of the pods tie for the nodes with the fewest pods,
+	// so the placements depend on how the ties are broken.
+	first := schedulePods(t, 42)
+	second := schedulePods(t, 42)
+	assert.Len(t, first, 8)
+	assert.Equal(t, first, second)
+}
+
+// schedulePods runs the scheduler seeded with seed, and schedules the pods one by one to the identical nodes.
+// It returns the nodes of the pods keyed by their names.
+func schedulePods(t *testing.T, seed int64) map[string]string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := fake.NewSimpleClientset()
+	var mu sync.Mutex
+	placements := map[string]string{}
+	// The fake client doesn't bind the pods.
+	client.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "binding" {
+			return false, nil, nil
+		}
+		binding, ok := action.(k8stesting.CreateAction).GetObject().(*corev1.Binding)
+		if !ok {
+			return true, nil, fmt.Errorf("unexpected object %T", action.(k8stesting.CreateAction).GetObject())
+		}
+		obj, err := client.Tracker().Get(corev1.SchemeGroupVersion.WithResource("pods"), binding.Namespace, binding.Name)
+		if err != nil {
+			return true, nil, err
+		}
+		pod := obj.(*corev1.Pod).DeepCopy()
+		pod.Spec.NodeName = binding.Target.Name
+		if err := client.Tracker().Update(corev1.SchemeGroupVersion.WithResource("pods"), pod, binding.Namespace); err != nil {
+			return true, nil, err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		placements[binding.Name] = binding.Target.Name
+		return true, binding, nil
+	})
+	for i := 0; i < 4; i++ {
+		_, err := client.CoreV1().Nodes().Create(ctx, node(fmt.Sprintf("node%d", i)), metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	broadcaster := events.NewBroadcaster(&events.EventSinkImpl{Interface: client.EventsV1()})
+	sched, err := kubescheduler.New(ctx, client, informerFactory, nil, profile.NewRecorderFactory(broadcaster), kubescheduler.WithParallelism(1))
+	require.NoError(t, err)
+	informerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+
+	_, err = applyRandomSeed(newOptions([]Option{WithRandomSeed(seed)}), "")
+	require.NoError(t, err)
+	go sched.Run(ctx)
+
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("pod%d", i)
+		_, err := client.CoreV1().Pods("default").Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name)},
+			Spec:       corev1.PodSpec{SchedulerName: corev1.DefaultSchedulerName, Containers: []corev1.Container{{Name: "c", Image: "image"}}},
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+		// The pods are scheduled one by one so that the order of the scheduling is deterministic.
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			_, ok := placements[name]
+			return ok
+		}, 10*time.Second, 10*time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	ret := make(map[string]string, len(placements))
+	for k, v := range placements {
+		ret[k] = v
+	}
+	return ret
+}
+
+func node(name string) *corev1.Node {
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     corev1.NodeStatus{Capacity: resources, Allocatable: resources},
+	}
+}
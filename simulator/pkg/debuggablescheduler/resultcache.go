@@ -0,0 +1,110 @@
+package debuggablescheduler
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DefaultResultCacheSize is the default number of the scheduling results kept in ResultCache.
+const DefaultResultCacheSize = 1000
+
+// SchedulingResultEntry is the latest scheduling result of a Pod kept in ResultCache.
+type SchedulingResultEntry struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	// Timestamp is when the result was recorded.
+	Timestamp time.Time        `json:"timestamp"`
+	Result    SchedulingResult `json:"result"`
+}
+
+// ResultCache keeps the latest scheduling results of the most recently scheduled Pods in memory.
+// The least recently scheduled Pod's result is evicted when the cache is full.
+type ResultCache struct {
+	mu   sync.Mutex
+	size int
+	// entries is ordered from the most recently recorded one.
+	entries *list.List
+	index   map[string]*list.Element
+	now     func() time.Time
+}
+
+// NewResultCache initializes ResultCache which keeps the results of size Pods at most.
+func NewResultCache(size int) *ResultCache {
+	if size <= 0 {
+		size = DefaultResultCacheSize
+	}
+	return &ResultCache{
+		size:    size,
+		entries: list.New(),
+		index:   map[string]*list.Element{},
+		now:     time.Now,
+	}
+}
+
+// Add records the result of the Pod.
+// It can be used as the hook passed to WithResultHook.
+func (c *ResultCache) Add(podNamespace, podName string, result SchedulingResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := SchedulingResultEntry{Namespace: podNamespace, Pod: podName, Timestamp: c.now(), Result: result}
+	k := podNamespace + "/" + podName
+	if e, ok := c.index[k]; ok {
+		e.Value = entry
+		c.entries.MoveToFront(e)
+		return
+	}
+
+	c.index[k] = c.entries.PushFront(entry)
+	if c.entries.Len() > c.size {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		//nolint:forcetypeassert // entries only has SchedulingResultEntry.
+		o := oldest.Value.(SchedulingResultEntry)
+		delete(c.index, o.Namespace+"/"+o.Pod)
+	}
+}
+
+// ResultFilter is the conditions to filter the results in ResultCache.
+// Empty fields match all results.
+type ResultFilter struct {
+	Namespace string
+	Pod       string
+	// Node matches the results whose selected node is Node.
+	Node string
+	// Since matches the results recorded at or after Since.
+	Since time.Time
+}
+
+func (f ResultFilter) match(e SchedulingResultEntry) bool {
+	if f.Namespace != "" && f.Namespace != e.Namespace {
+		return false
+	}
+	if f.Pod != "" && f.Pod != e.Pod {
+		return false
+	}
+	if f.Node != "" && f.Node != e.Result.SelectedNode {
+		return false
+	}
+	if !f.Since.IsZero() && e.Timestamp.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// List returns the results matching the filter, from the most recently recorded one.
+func (c *ResultCache) List(filter ResultFilter) []SchedulingResultEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ret := []SchedulingResultEntry{}
+	for e := c.entries.Front(); e != nil; e = e.Next() {
+		//nolint:forcetypeassert // entries only has SchedulingResultEntry.
+		entry := e.Value.(SchedulingResultEntry)
+		if filter.match(entry) {
+			ret = append(ret, entry)
+		}
+	}
+	return ret
+}
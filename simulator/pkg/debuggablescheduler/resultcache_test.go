@@ -0,0 +1,119 @@
+package debuggablescheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultCache_Add(t *testing.T) {
+	t.Parallel()
+
+	type add struct {
+		namespace, pod, node string
+	}
+	tests := []struct {
+		name     string
+		size     int
+		adds     []add
+		wantPods []string
+	}{
+		{
+			name:     "keep all results within the size",
+			size:     3,
+			adds:     []add{{"default", "pod1", "node1"}, {"default", "pod2", "node1"}},
+			wantPods: []string{"pod2", "pod1"},
+		},
+		{
+			name:     "evict the least recently scheduled pod when the cache is full",
+			size:     2,
+			adds:     []add{{"default", "pod1", "node1"}, {"default", "pod2", "node1"}, {"default", "pod3", "node1"}},
+			wantPods: []string{"pod3", "pod2"},
+		},
+		{
+			name:     "rescheduled pod is kept as the most recent one",
+			size:     2,
+			adds:     []add{{"default", "pod1", "node1"}, {"default", "pod2", "node1"}, {"default", "pod1", "node2"}, {"default", "pod3", "node1"}},
+			wantPods: []string{"pod3", "pod1"},
+		},
+		{
+			name:     "default size is used if the size isn't positive",
+			size:     0,
+			adds:     []add{{"default", "pod1", "node1"}},
+			wantPods: []string{"pod1"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := NewResultCache(tt.size)
+			for _, a := range tt.adds {
+				c.Add(a.namespace, a.pod, SchedulingResult{SelectedNode: a.node})
+			}
+
+			got := c.List(ResultFilter{})
+			pods := make([]string, 0, len(got))
+			for _, e := range got {
+				pods = append(pods, e.Pod)
+			}
+			assert.Equal(t, tt.wantPods, pods)
+			assert.Len(t, c.index, len(tt.wantPods))
+		})
+	}
+}
+
+func TestResultCache_List(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewResultCache(10)
+	now := base
+	c.now = func() time.Time { return now }
+	c.Add("default", "pod1", SchedulingResult{SelectedNode: "node1"})
+	now = base.Add(time.Minute)
+	c.Add("default", "pod2", SchedulingResult{SelectedNode: "node2"})
+	now = base.Add(2 * time.Minute)
+	c.Add("kube-system", "pod1", SchedulingResult{SelectedNode: "node1"})
+
+	tests := []struct {
+		name   string
+		filter ResultFilter
+		want   []SchedulingResultEntry
+	}{
+		{
+			name:   "filter by namespace and pod",
+			filter: ResultFilter{Namespace: "default", Pod: "pod1"},
+			want:   []SchedulingResultEntry{{Namespace: "default", Pod: "pod1", Timestamp: base, Result: SchedulingResult{SelectedNode: "node1"}}},
+		},
+		{
+			name:   "filter by node",
+			filter: ResultFilter{Node: "node1"},
+			want: []SchedulingResultEntry{
+				{Namespace: "kube-system", Pod: "pod1", Timestamp: base.Add(2 * time.Minute), Result: SchedulingResult{SelectedNode: "node1"}},
+				{Namespace: "default", Pod: "pod1", Timestamp: base, Result: SchedulingResult{SelectedNode: "node1"}},
+			},
+		},
+		{
+			name:   "filter by since",
+			filter: ResultFilter{Since: base.Add(time.Minute)},
+			want: []SchedulingResultEntry{
+				{Namespace: "kube-system", Pod: "pod1", Timestamp: base.Add(2 * time.Minute), Result: SchedulingResult{SelectedNode: "node1"}},
+				{Namespace: "default", Pod: "pod2", Timestamp: base.Add(time.Minute), Result: SchedulingResult{SelectedNode: "node2"}},
+			},
+		},
+		{
+			name:   "no result matches",
+			filter: ResultFilter{Pod: "pod3"},
+			want:   []SchedulingResultEntry{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, c.List(tt.filter))
+		})
+	}
+}
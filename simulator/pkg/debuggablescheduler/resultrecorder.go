@@ -0,0 +1,96 @@
+package debuggablescheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/recorder"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/resultstore"
+)
+
+// ResultRecord is a line written to the file given to WithResultRecorder.
+type ResultRecord struct {
+	// Pod is the key of the Pod in the format of namespace/name.
+	Pod string `json:"pod"`
+	// Timestamp is when the scheduling attempt was finished.
+	Timestamp time.Time `json:"timestamp"`
+	// FailureReason is why the Pod couldn't be scheduled. It's empty if a node was selected for the Pod.
+	FailureReason string           `json:"failureReason,omitempty"`
+	Result        SchedulingResult `json:"result"`
+}
+
+// resultRecorder writes the scheduling results to the file via recorder.JSONLWriter.
+type resultRecorder struct {
+	w   *recorder.JSONLWriter
+	now func() time.Time
+}
+
+func newResultRecorder(path string, maxSize int64, maxBackups int) (*resultRecorder, error) {
+	w, err := recorder.NewJSONLWriter(path, recorder.JSONLWriterOptions{MaxSize: maxSize, MaxBackups: maxBackups})
+	if err != nil {
+		return nil, xerrors.Errorf("open the result record file: %w", err)
+	}
+	return &resultRecorder{w: w, now: time.Now}, nil
+}
+
+// Record writes the result of the Pod as a line.
+// It's used as the hook to receive the results.
+func (r *resultRecorder) Record(podNamespace, podName string, result SchedulingResult) {
+	record := ResultRecord{
+		Pod:       podNamespace + "/" + podName,
+		Timestamp: r.now(),
+		Result:    result,
+	}
+	if result.SelectedNode == "" {
+		record.FailureReason = failureReason(result.Filter)
+	}
+
+	if err := r.w.Write(&record); err != nil {
+		klog.Errorf("failed to record the scheduling result of %s: %+v", record.Pod, err)
+	}
+}
+
+// Close closes the file.
+func (r *resultRecorder) Close() {
+	if err := r.w.Close(); err != nil {
+		klog.Errorf("failed to close the result record file: %+v", err)
+	}
+}
+
+// failureReason summarizes the rejections by the Filter plugins in the same way as the scheduler's FitError,
+// e.g., "0/3 nodes are available: 1 node(s) didn't match Pod's node affinity/selector, 2 Insufficient cpu.".
+func failureReason(filter map[string]map[string]string) string {
+	if len(filter) == 0 {
+		return "no node was evaluated by the Filter plugins"
+	}
+
+	reasons := map[string]int{}
+	feasible := 0
+	for _, plugins := range filter {
+		passed := true
+		for _, reason := range plugins {
+			if reason != resultstore.PassedFilterMessage {
+				reasons[reason]++
+				passed = false
+			}
+		}
+		if passed {
+			feasible++
+		}
+	}
+	if feasible > 0 {
+		return fmt.Sprintf("%d/%d nodes passed the Filter plugins, but the Pod wasn't scheduled on any of them", feasible, len(filter))
+	}
+
+	strs := make([]string, 0, len(reasons))
+	for reason, n := range reasons {
+		strs = append(strs, fmt.Sprintf("%d %s", n, reason))
+	}
+	sort.Strings(strs)
+	return fmt.Sprintf("0/%d nodes are available: %s.", len(filter), strings.Join(strs, ", "))
+}
@@ -0,0 +1,177 @@
+package debuggablescheduler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/resultstore"
+)
+
+// fakeNodeNameFilter only accepts the nodes in allowed.
+type fakeNodeNameFilter struct {
+	allowed map[string]bool
+}
+
+func (fakeNodeNameFilter) Name() string { return "fakeNodeNameFilter" }
+
+func (f fakeNodeNameFilter) Filter(_ context.Context, _ *framework.CycleState, _ *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	if f.allowed[nodeInfo.Node().Name] {
+		return nil
+	}
+	return framework.NewStatus(framework.Unschedulable, "node(s) not allowed")
+}
+
+func Test_resultRecorder(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	nodes := []string{"node1", "node2"}
+
+	tests := []struct {
+		name    string
+		allowed map[string]bool
+		want    ResultRecord
+	}{
+		{
+			name:    "record the selected node",
+			allowed: map[string]bool{"node2": true},
+			want: ResultRecord{
+				Pod:       "default/pod1",
+				Timestamp: timestamp,
+				Result: SchedulingResult{
+					SelectedNode: "node2",
+					Filter: map[string]map[string]string{
+						"node1": {"fakeNodeNameFilter": "node(s) not allowed"},
+						"node2": {"fakeNodeNameFilter": resultstore.PassedFilterMessage},
+					},
+					Score:      map[string]map[string]int64{},
+					FinalScore: map[string]map[string]int64{},
+					TotalScore: map[string]int64{},
+				},
+			},
+		},
+		{
+			name:    "record the failure reason",
+			allowed: map[string]bool{},
+			want: ResultRecord{
+				Pod:           "default/pod1",
+				Timestamp:     timestamp,
+				FailureReason: "0/2 nodes are available: 2 node(s) not allowed.",
+				Result: SchedulingResult{
+					Filter: map[string]map[string]string{
+						"node1": {"fakeNodeNameFilter": "node(s) not allowed"},
+						"node2": {"fakeNodeNameFilter": "node(s) not allowed"},
+					},
+					Score:      map[string]map[string]int64{},
+					FinalScore: map[string]map[string]int64{},
+					TotalScore: map[string]int64{},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "results.jsonl")
+			r, err := newResultRecorder(path, 0, 0)
+			require.NoError(t, err)
+			r.now = func() time.Time { return timestamp }
+
+			// Schedule the Pod with the wrapped plugin.
+			store := resultstore.New(nil)
+			p := plugin.NewWrappedPlugin(store, fakeNodeNameFilter{allowed: tt.allowed})
+			fp, ok := p.(framework.FilterPlugin)
+			require.True(t, ok)
+			rp, ok := p.(framework.ReservePlugin)
+			require.True(t, ok)
+			pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+			selected := ""
+			for _, n := range nodes {
+				nodeInfo := framework.NewNodeInfo()
+				nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: n}})
+				if fp.Filter(context.Background(), nil, pod, nodeInfo).IsSuccess() {
+					selected = n
+				}
+			}
+			if selected != "" {
+				require.True(t, rp.Reserve(context.Background(), nil, pod, selected).IsSuccess())
+			}
+
+			result, ok := store.GetSchedulingResult(pod.Namespace, pod.Name)
+			require.True(t, ok)
+			r.Record(pod.Namespace, pod.Name, result)
+			r.Close()
+
+			f, err := os.Open(path)
+			require.NoError(t, err)
+			defer f.Close()
+			var got []ResultRecord
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				var record ResultRecord
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+				got = append(got, record)
+			}
+			require.NoError(t, scanner.Err())
+			require.Len(t, got, 1)
+			// The durations vary from run to run, so only their existence is checked.
+			assert.Contains(t, got[0].Result.Duration, "Filter")
+			got[0].Result.Duration = nil
+			assert.Equal(t, tt.want, got[0])
+		})
+	}
+}
+
+func Test_failureReason(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		filter map[string]map[string]string
+		want   string
+	}{
+		{
+			name: "summarize the rejections of all nodes",
+			filter: map[string]map[string]string{
+				"node1": {"NodeResourcesFit": "Insufficient cpu", "NodeAffinity": resultstore.PassedFilterMessage},
+				"node2": {"NodeResourcesFit": "Insufficient cpu"},
+				"node3": {"NodeAffinity": "node(s) didn't match Pod's node affinity/selector"},
+			},
+			want: "0/3 nodes are available: 1 node(s) didn't match Pod's node affinity/selector, 2 Insufficient cpu.",
+		},
+		{
+			name: "some nodes passed the filter",
+			filter: map[string]map[string]string{
+				"node1": {"NodeResourcesFit": resultstore.PassedFilterMessage},
+				"node2": {"NodeResourcesFit": "Insufficient cpu"},
+			},
+			want: "1/2 nodes passed the Filter plugins, but the Pod wasn't scheduled on any of them",
+		},
+		{
+			name:   "no filter results",
+			filter: map[string]map[string]string{},
+			want:   "no node was evaluated by the Filter plugins",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, failureReason(tt.filter))
+		})
+	}
+}
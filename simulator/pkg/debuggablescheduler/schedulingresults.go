@@ -0,0 +1,41 @@
+package debuggablescheduler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+)
+
+// schedulingResultsHandler is a handler to get the scheduling results kept in ResultCache.
+type schedulingResultsHandler struct {
+	cache *ResultCache
+}
+
+func newSchedulingResultsHandler(cache *ResultCache) *schedulingResultsHandler {
+	return &schedulingResultsHandler{cache: cache}
+}
+
+// List returns the results matching the query parameters, from the most recently recorded one.
+// All parameters are optional:
+// - namespace, pod: the Pod of the results.
+// - node: the selected node of the results.
+// - since: the results recorded at or after this time, in RFC3339 format.
+func (h *schedulingResultsHandler) List(c echo.Context) error {
+	filter := ResultFilter{
+		Namespace: c.QueryParam("namespace"),
+		Pod:       c.QueryParam("pod"),
+		Node:      c.QueryParam("node"),
+	}
+	if since := c.QueryParam("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			klog.Errorf("failed to parse since: %+v", err)
+			return echo.NewHTTPError(http.StatusBadRequest, "since must be in RFC3339 format")
+		}
+		filter.Since = t
+	}
+
+	return c.JSON(http.StatusOK, h.cache.List(filter))
+}
@@ -0,0 +1,104 @@
+package debuggablescheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_schedulingResultsHandler_List(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewResultCache(10)
+	now := base
+	cache.now = func() time.Time { return now }
+	cache.Add("default", "pod1", SchedulingResult{
+		SelectedNode: "node1",
+		Filter:       map[string]map[string]string{"node1": {"NodeResourcesFit": "passed"}},
+		Score:        map[string]map[string]int64{"node1": {"NodeResourcesFit": 50}},
+		FinalScore:   map[string]map[string]int64{"node1": {"NodeResourcesFit": 50}},
+	})
+	now = base.Add(time.Minute)
+	cache.Add("default", "pod2", SchedulingResult{SelectedNode: "node2"})
+
+	tests := []struct {
+		name       string
+		query      string
+		wantCode   int
+		wantPods   []string
+		wantResult *SchedulingResult
+	}{
+		{
+			name:     "list all results",
+			wantCode: http.StatusOK,
+			wantPods: []string{"pod2", "pod1"},
+		},
+		{
+			name:     "get the result of the pod",
+			query:    "?namespace=default&pod=pod1",
+			wantCode: http.StatusOK,
+			wantPods: []string{"pod1"},
+			wantResult: &SchedulingResult{
+				SelectedNode: "node1",
+				Filter:       map[string]map[string]string{"node1": {"NodeResourcesFit": "passed"}},
+				Score:        map[string]map[string]int64{"node1": {"NodeResourcesFit": 50}},
+				FinalScore:   map[string]map[string]int64{"node1": {"NodeResourcesFit": 50}},
+			},
+		},
+		{
+			name:     "filter by node and since",
+			query:    "?node=node2&since=2024-01-01T00:00:30Z",
+			wantCode: http.StatusOK,
+			wantPods: []string{"pod2"},
+		},
+		{
+			name:     "no result matches",
+			query:    "?node=node3",
+			wantCode: http.StatusOK,
+			wantPods: []string{},
+		},
+		{
+			name:     "invalid since",
+			query:    "?since=yesterday",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/schedulingresults"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := newSchedulingResultsHandler(cache).List(c)
+			if tt.wantCode != http.StatusOK {
+				var he *echo.HTTPError
+				require.ErrorAs(t, err, &he)
+				assert.Equal(t, tt.wantCode, he.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var got []SchedulingResultEntry
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			pods := make([]string, 0, len(got))
+			for _, e := range got {
+				pods = append(pods, e.Pod)
+			}
+			assert.Equal(t, tt.wantPods, pods)
+			if tt.wantResult != nil {
+				assert.Equal(t, *tt.wantResult, got[0].Result)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package debuggablescheduler
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"net"
 	"net/http"
 	"strconv"
 	"time"
@@ -10,6 +12,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/labstack/gommon/log"
+	"golang.org/x/xerrors"
 
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/server"
@@ -23,28 +26,72 @@ type ExtenderServer struct {
 
 // NewExtenderServer initialize ExtenderServer.
 // This server is used as a proxy server to store Extender results.
+// The proxy APIs aren't registered if service is nil.
 func NewExtenderServer(service *extender.Service) ExtenderServer {
 	e := echo.New()
 	e.Use(middleware.Logger())
 
-	extenderHandler := handler.NewExtenderHandler(service)
 	// register apis
 	v1 := e.Group("/api/v1")
-	server.RouteExtender(v1, extenderHandler)
+	if service != nil {
+		extenderHandler := handler.NewExtenderHandler(service)
+		server.RouteExtender(v1, extenderHandler)
+		server.RouteExtenderInjection(v1, handler.NewExtenderInjectionHandler(service))
+	}
 	s := ExtenderServer{e: e}
 	s.e.Logger.SetLevel(log.INFO)
 	return s
 }
 
+// RouteSchedulingResults registers the API to get the scheduling results kept in the cache.
+func (s *ExtenderServer) RouteSchedulingResults(cache *ResultCache) {
+	s.e.GET("/api/v1/schedulingresults", newSchedulingResultsHandler(cache).List)
+}
+
+// ExtenderServerConfig is the configuration of the address and TLS which ExtenderServer serves on.
+type ExtenderServerConfig struct {
+	// Host is the address to bind. Empty means all addresses.
+	Host string
+	Port int
+	// CertFile and KeyFile are the paths of the certificate and the key for TLS.
+	// ExtenderServer serves plaintext HTTP if they're empty.
+	CertFile string
+	KeyFile  string
+}
+
+// TLSEnabled returns true if ExtenderServer serves TLS.
+func (c ExtenderServerConfig) TLSEnabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
 // Start starts ExtenderServer.
-func (s *ExtenderServer) Start(port int) (
+// It binds the address before returning so that the caller gets an error if the address cannot be used.
+func (s *ExtenderServer) Start(cfg ExtenderServerConfig) (
 	func(), // function for shutdown
 	error,
 ) {
 	e := s.e
 
+	l, err := net.Listen("tcp", net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)))
+	if err != nil {
+		return nil, xerrors.Errorf("listen on %s:%d: %w", cfg.Host, cfg.Port, err)
+	}
+	server := e.Server
+	if cfg.TLSEnabled() {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			l.Close()
+			return nil, xerrors.Errorf("load the certificate and the key for TLS: %w", err)
+		}
+		server = e.TLSServer
+		server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+		e.TLSListener = tls.NewListener(l, server.TLSConfig)
+	} else {
+		e.Listener = l
+	}
+
 	go func() {
-		if err := e.Start(":" + strconv.Itoa(port)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := e.StartServer(server); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			e.Logger.Fatalf("failed to start server successfully: %v", err)
 		}
 	}()
@@ -54,6 +101,11 @@ func (s *ExtenderServer) Start(port int) (
 		if err := e.Shutdown(ctx); err != nil {
 			e.Logger.Warnf("failed to shutdown simulator server successfully: %v", err)
 		}
+		// Shutdown doesn't close the listener if the server hasn't started serving yet.
+		// Close it anyway so that the port is surely released when this func returns.
+		if err := l.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			e.Logger.Warnf("failed to close the listener: %v", err)
+		}
 	}
 
 	return shutdownFn, nil
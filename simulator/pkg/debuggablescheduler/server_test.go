@@ -0,0 +1,125 @@
+package debuggablescheduler
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/storereflector"
+)
+
+func TestExtenderServer_Start(t *testing.T) {
+	t.Parallel()
+
+	certFile, keyFile, certPool := writeSelfSignedCert(t)
+
+	tests := []struct {
+		name    string
+		cfg     ExtenderServerConfig
+		tls     bool
+		wantErr bool
+	}{
+		{
+			name: "serve plaintext HTTP on the given address",
+			cfg:  ExtenderServerConfig{Host: "127.0.0.1"},
+		},
+		{
+			name: "serve TLS with the given certificate",
+			cfg:  ExtenderServerConfig{Host: "127.0.0.1", CertFile: certFile, KeyFile: keyFile},
+			tls:  true,
+		},
+		{
+			name:    "fail if the certificate cannot be loaded",
+			cfg:     ExtenderServerConfig{Host: "127.0.0.1", CertFile: filepath.Join(t.TempDir(), "not-found.crt"), KeyFile: keyFile},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, err := extender.New(nil, nil, storereflector.New())
+			require.NoError(t, err)
+			s := NewExtenderServer(service)
+			shutdownFn, err := s.Start(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			client := &http.Client{Timeout: 5 * time.Second}
+			var addr net.Addr
+			scheme := "http"
+			if tt.tls {
+				addr = s.e.TLSListenerAddr()
+				scheme = "https"
+				client.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS12}}
+			} else {
+				addr = s.e.ListenerAddr()
+			}
+			require.NotNil(t, addr)
+			url := scheme + "://" + addr.String() + "/api/v1/extender/filter/0"
+
+			resp, err := client.Get(url)
+			require.NoError(t, err)
+			resp.Body.Close()
+			// The extender APIs only accept POST.
+			assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+			shutdownFn()
+			_, err = client.Get(url) //nolint:bodyclose // the request fails.
+			assert.Error(t, err, "the server should be shut down")
+		})
+	}
+}
+
+// writeSelfSignedCert writes a self-signed certificate for 127.0.0.1 and its key to files,
+// and returns the paths of them and the pool which trusts the certificate.
+func writeSelfSignedCert(t *testing.T) (string, string, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "debuggable-scheduler-test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return certFile, keyFile, pool
+}
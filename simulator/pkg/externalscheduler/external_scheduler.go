@@ -75,7 +75,7 @@ func CreateOptionForOutOfTreePlugin(outOfTreePluginRegistry runtime.Registry, pl
 
 	sharedStore := storereflector.New()
 
-	registry, err := plugin.NewRegistry(sharedStore, internalCfg, pluginExtender)
+	registry, err := plugin.NewRegistry(sharedStore, internalCfg, pluginExtender, nil, nil, nil)
 	if err != nil {
 		return nil, nil, xerrors.Errorf("convert scheduler config to apply: %w", err)
 	}
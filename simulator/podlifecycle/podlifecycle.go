@@ -0,0 +1,289 @@
+// Package podlifecycle acts as the kubelets of the nodes in the simulator:
+// it makes the Pods bound to the nodes Running, and makes them Succeeded after the durations in their annotations,
+// so that the experiments involving the lifetimes of the Pods, e.g., a node filling up and draining over time, can be done.
+package podlifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"golang.org/x/xerrors"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/lifecycle"
+)
+
+// RunDurationAnnotationKey is the annotation of the Pod which specifies how long the Pod runs before it succeeds, e.g., "5m".
+// The Pods without it keep running.
+const RunDurationAnnotationKey = "simulator/run-duration"
+
+const (
+	defaultRunningDelay = time.Second
+	// retryInterval is the interval to retry updating the Pod after it fails.
+	retryInterval = 5 * time.Second
+)
+
+// Options is the options of the pod lifecycle simulator.
+type Options struct {
+	// RunningDelay is the delay before the Pods bound to the nodes become Running. Its default value is 1s.
+	RunningDelay *time.Duration
+	// DeleteSucceededPods deletes the Pods after they succeed.
+	// The Succeeded Pods already don't request the resources of the nodes to the scheduler,
+	// but they remain in the simulator unless they're deleted.
+	DeleteSucceededPods bool
+}
+
+// Service makes the Pods bound to the nodes Running, and Succeeded after the durations in their annotations.
+type Service struct {
+	// Controller manages the lifecycle of the pod lifecycle simulator started through the API.
+	// The Pods aren't changed while it's paused, and the ones due meanwhile are changed after it's resumed.
+	*lifecycle.Controller
+	client              clientset.Interface
+	runningDelay        time.Duration
+	deleteSucceededPods bool
+	clock               clock.WithTicker
+	logger              klog.Logger
+}
+
+// New initializes Service. logger is used for the logs of the pod lifecycle simulator.
+func New(client clientset.Interface, options Options, logger klog.Logger) *Service {
+	return newService(client, options, clock.RealClock{}, logger)
+}
+
+func newService(client clientset.Interface, options Options, clk clock.WithTicker, logger klog.Logger) *Service {
+	runningDelay := defaultRunningDelay
+	if options.RunningDelay != nil {
+		runningDelay = *options.RunningDelay
+	}
+	s := &Service{
+		client:              client,
+		runningDelay:        runningDelay,
+		deleteSucceededPods: options.DeleteSucceededPods,
+		clock:               clk,
+		logger:              logger,
+	}
+	s.Controller = lifecycle.NewController(s.prepare)
+	return s
+}
+
+// prepare returns the function to run the pod lifecycle simulator until it's stopped. It takes no options.
+func (s *Service) prepare(options json.RawMessage) (lifecycle.RunFunc, error) {
+	if err := lifecycle.DecodeOptions(options, &struct{}{}); err != nil {
+		return nil, err
+	}
+	return s.run, nil
+}
+
+// run changes the phases of the Pods when they're due, until ctx is cancelled.
+func (s *Service) run(ctx context.Context, ready func()) error {
+	s.logger.Info("Starting the pod lifecycle simulator")
+
+	queue := workqueue.NewTypedDelayingQueueWithConfig(workqueue.TypedDelayingQueueConfig[string]{Clock: s.clock})
+	defer queue.ShutDown()
+	infFact := informers.NewSharedInformerFactory(s.client, 0)
+	defer infFact.Shutdown()
+	podInformer := infFact.Core().V1().Pods()
+	enqueue := func(obj interface{}) {
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			s.logger.Error(err, "Failed to get the key of the pod")
+			return
+		}
+		queue.Add(key)
+	}
+	_, err := podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueue,
+		UpdateFunc: func(_, obj interface{}) { enqueue(obj) },
+	})
+	if err != nil {
+		return xerrors.Errorf("add event handler: %w", err)
+	}
+	infFact.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), podInformer.Informer().HasSynced) {
+		// ctx is cancelled.
+		return nil
+	}
+	ready()
+	s.logger.Info("Pod lifecycle simulator started")
+
+	go func() {
+		<-ctx.Done()
+		queue.ShutDown()
+	}()
+	for s.processNext(ctx, queue, podInformer.Lister()) {
+	}
+	return nil
+}
+
+// processNext changes the phase of the next Pod in queue. It returns false when it should stop.
+func (s *Service) processNext(ctx context.Context, queue workqueue.TypedDelayingInterface[string], lister corelisters.PodLister) bool {
+	key, shutdown := queue.Get()
+	if shutdown {
+		return false
+	}
+	defer queue.Done(key)
+	if err := s.WaitResumed(); err != nil {
+		return false
+	}
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		s.logger.Error(err, "Failed to split the key of the pod", "key", key)
+		return true
+	}
+	pod, err := lister.Pods(namespace).Get(name)
+	if err != nil {
+		// The Pod is deleted.
+		return true
+	}
+	after, err := s.sync(ctx, pod)
+	if err != nil {
+		s.logger.Error(err, "Failed to change the phase of the pod", "pod", klog.KObj(pod))
+		after = retryInterval
+	}
+	if after > 0 {
+		queue.AddAfter(key, after)
+	}
+	return true
+}
+
+// sync changes the phase of pod if it's due.
+// It returns the duration after which pod is due, or 0 if there's nothing to do with pod anymore.
+func (s *Service) sync(ctx context.Context, pod *v1.Pod) (time.Duration, error) {
+	if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil {
+		return 0, nil
+	}
+	now := s.clock.Now()
+	switch pod.Status.Phase {
+	case v1.PodPending, "":
+		if wait := boundTime(pod).Add(s.runningDelay).Sub(now); wait > 0 {
+			return wait, nil
+		}
+		if _, err := s.client.CoreV1().Pods(pod.Namespace).UpdateStatus(ctx, runningPod(pod, now), metav1.UpdateOptions{}); err != nil {
+			return 0, xerrors.Errorf("update the pod to Running: %w", err)
+		}
+		s.logger.V(4).Info("Pod is running", "pod", klog.KObj(pod), "node", pod.Spec.NodeName)
+		return 0, nil
+	case v1.PodRunning:
+		d, ok := s.runDuration(pod)
+		if !ok || pod.Status.StartTime == nil {
+			return 0, nil
+		}
+		if wait := pod.Status.StartTime.Add(d).Sub(now); wait > 0 {
+			return wait, nil
+		}
+		if _, err := s.client.CoreV1().Pods(pod.Namespace).UpdateStatus(ctx, succeededPod(pod, now), metav1.UpdateOptions{}); err != nil {
+			return 0, xerrors.Errorf("update the pod to Succeeded: %w", err)
+		}
+		s.logger.V(4).Info("Pod succeeded", "pod", klog.KObj(pod), "node", pod.Spec.NodeName)
+		// The updated Pod is enqueued again to be deleted.
+		return 0, nil
+	case v1.PodSucceeded:
+		if !s.deleteSucceededPods {
+			return 0, nil
+		}
+		err := s.client.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{Preconditions: metav1.NewUIDPreconditions(string(pod.UID))})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return 0, xerrors.Errorf("delete the succeeded pod: %w", err)
+		}
+		return 0, nil
+	default:
+		return 0, nil
+	}
+}
+
+// runDuration returns the duration in RunDurationAnnotationKey of pod.
+// It returns false if pod doesn't have it, or it's invalid.
+func (s *Service) runDuration(pod *v1.Pod) (time.Duration, bool) {
+	v, ok := pod.Annotations[RunDurationAnnotationKey]
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		s.logger.Error(err, "Ignored the invalid annotation of the pod", "pod", klog.KObj(pod), "annotation", RunDurationAnnotationKey, "value", v)
+		return 0, false
+	}
+	return d, true
+}
+
+// boundTime returns when pod is bound to the node,
+// i.e., when the PodScheduled condition becomes true, or when pod is created with the node.
+func boundTime(pod *v1.Pod) time.Time {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == v1.PodScheduled && c.Status == v1.ConditionTrue && !c.LastTransitionTime.IsZero() {
+			return c.LastTransitionTime.Time
+		}
+	}
+	return pod.CreationTimestamp.Time
+}
+
+// runningPod returns pod started at now with all the containers running and ready.
+func runningPod(pod *v1.Pod, now time.Time) *v1.Pod {
+	p := pod.DeepCopy()
+	t := metav1.NewTime(now)
+	p.Status.Phase = v1.PodRunning
+	p.Status.StartTime = &t
+	for _, c := range []v1.PodConditionType{v1.PodScheduled, v1.PodInitialized, v1.ContainersReady, v1.PodReady} {
+		setCondition(&p.Status, v1.PodCondition{Type: c, Status: v1.ConditionTrue, LastTransitionTime: t})
+	}
+	p.Status.ContainerStatuses = make([]v1.ContainerStatus, 0, len(pod.Spec.Containers))
+	for _, c := range pod.Spec.Containers {
+		p.Status.ContainerStatuses = append(p.Status.ContainerStatuses, v1.ContainerStatus{
+			Name:    c.Name,
+			Image:   c.Image,
+			Ready:   true,
+			Started: ptr.To(true),
+			State:   v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: t}},
+		})
+	}
+	return p
+}
+
+// succeededPod returns pod finished at now with all the containers terminated successfully.
+func succeededPod(pod *v1.Pod, now time.Time) *v1.Pod {
+	p := pod.DeepCopy()
+	t := metav1.NewTime(now)
+	p.Status.Phase = v1.PodSucceeded
+	for _, c := range []v1.PodConditionType{v1.ContainersReady, v1.PodReady} {
+		setCondition(&p.Status, v1.PodCondition{Type: c, Status: v1.ConditionFalse, Reason: "PodCompleted", LastTransitionTime: t})
+	}
+	for i := range p.Status.ContainerStatuses {
+		cs := &p.Status.ContainerStatuses[i]
+		startedAt := t
+		if cs.State.Running != nil {
+			startedAt = cs.State.Running.StartedAt
+		}
+		cs.Ready = false
+		cs.Started = ptr.To(false)
+		cs.State = v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed", StartedAt: startedAt, FinishedAt: t}}
+	}
+	return p
+}
+
+// setCondition sets condition to status, replacing the one of the same type.
+// The transition time isn't changed if the status of the condition isn't changed.
+func setCondition(status *v1.PodStatus, condition v1.PodCondition) {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type != condition.Type {
+			continue
+		}
+		if status.Conditions[i].Status == condition.Status {
+			condition.LastTransitionTime = status.Conditions[i].LastTransitionTime
+		}
+		status.Conditions[i] = condition
+		return
+	}
+	status.Conditions = append(status.Conditions, condition)
+}
@@ -0,0 +1,149 @@
+package podlifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/lifecycle"
+)
+
+func newPod(name, nodeName string, created time.Time, annotations map[string]string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name), CreationTimestamp: metav1.NewTime(created), Annotations: annotations},
+		Spec:       v1.PodSpec{NodeName: nodeName, Containers: []v1.Container{{Name: "container", Image: "image"}}},
+		Status:     v1.PodStatus{Phase: v1.PodPending},
+	}
+}
+
+// startService starts the pod lifecycle simulator with the fake clock, and stops it when the test finishes.
+func startService(t *testing.T, client *fake.Clientset, clk *testingclock.FakeClock, options Options) {
+	t.Helper()
+	s := newService(client, options, clk, klog.Background())
+	require.NoError(t, s.Start(nil))
+	t.Cleanup(func() { _ = s.Stop() })
+	require.Eventually(t, func() bool { return s.Status().State == lifecycle.StateRunning }, wait.ForeverTestTimeout, 10*time.Millisecond)
+}
+
+// eventuallyPhase waits until the pod gets phase, stepping clk forward a little so that the Pods due are processed.
+func eventuallyPhase(t *testing.T, client *fake.Clientset, clk *testingclock.FakeClock, name string, phase v1.PodPhase) *v1.Pod {
+	t.Helper()
+	var pod *v1.Pod
+	require.Eventually(t, func() bool {
+		clk.Step(time.Millisecond)
+		var err error
+		pod, err = client.CoreV1().Pods("default").Get(context.Background(), name, metav1.GetOptions{})
+		return err == nil && pod.Status.Phase == phase
+	}, wait.ForeverTestTimeout, 10*time.Millisecond)
+	return pod
+}
+
+// neverPhase asserts the pod doesn't get phase for a while.
+func neverPhase(t *testing.T, client *fake.Clientset, name string, phase v1.PodPhase) {
+	t.Helper()
+	assert.Never(t, func() bool {
+		pod, err := client.CoreV1().Pods("default").Get(context.Background(), name, metav1.GetOptions{})
+		return err == nil && pod.Status.Phase == phase
+	}, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestService_transitions(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := testingclock.NewFakeClock(start)
+	client := fake.NewSimpleClientset(
+		newPod("bound", "node-1", start, map[string]string{RunDurationAnnotationKey: "5m"}),
+		newPod("forever", "node-1", start, nil),
+		newPod("unscheduled", "", start, map[string]string{RunDurationAnnotationKey: "5m"}),
+	)
+	startService(t, client, clk, Options{RunningDelay: ptr.To(10 * time.Second)})
+
+	clk.Step(5 * time.Second)
+	neverPhase(t, client, "bound", v1.PodRunning)
+
+	clk.Step(5 * time.Second)
+	pod := eventuallyPhase(t, client, clk, "bound", v1.PodRunning)
+	require.NotNil(t, pod.Status.StartTime)
+	assert.False(t, pod.Status.StartTime.Time.Before(start.Add(10*time.Second)), "the pod runs after the delay")
+	require.Len(t, pod.Status.ContainerStatuses, 1)
+	assert.NotNil(t, pod.Status.ContainerStatuses[0].State.Running)
+	eventuallyPhase(t, client, clk, "forever", v1.PodRunning)
+
+	clk.Step(4 * time.Minute)
+	neverPhase(t, client, "bound", v1.PodSucceeded)
+
+	clk.Step(time.Minute)
+	pod = eventuallyPhase(t, client, clk, "bound", v1.PodSucceeded)
+	require.Len(t, pod.Status.ContainerStatuses, 1)
+	require.NotNil(t, pod.Status.ContainerStatuses[0].State.Terminated)
+	assert.Equal(t, "Completed", pod.Status.ContainerStatuses[0].State.Terminated.Reason)
+
+	for _, name := range []string{"forever", "unscheduled"} {
+		pod, err := client.CoreV1().Pods("default").Get(context.Background(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.NotEqual(t, v1.PodSucceeded, pod.Status.Phase, "the pods without the annotation or the node don't succeed")
+	}
+	pod, err := client.CoreV1().Pods("default").Get(context.Background(), "unscheduled", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, v1.PodPending, pod.Status.Phase, "the pods not bound to any node don't run")
+}
+
+func TestService_deleteSucceededPods(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := testingclock.NewFakeClock(start)
+	client := fake.NewSimpleClientset(newPod("pod", "node-1", start, map[string]string{RunDurationAnnotationKey: "1m"}))
+	startService(t, client, clk, Options{RunningDelay: ptr.To(time.Duration(0)), DeleteSucceededPods: true})
+
+	eventuallyPhase(t, client, clk, "pod", v1.PodRunning)
+	clk.Step(time.Minute)
+	require.Eventually(t, func() bool {
+		clk.Step(time.Millisecond)
+		_, err := client.CoreV1().Pods("default").Get(context.Background(), "pod", metav1.GetOptions{})
+		return apierrors.IsNotFound(err)
+	}, wait.ForeverTestTimeout, 10*time.Millisecond)
+}
+
+func Test_boundTime(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	scheduled := created.Add(time.Minute)
+	tests := []struct {
+		name       string
+		conditions []v1.PodCondition
+		want       time.Time
+	}{
+		{
+			name: "the pod bound by the scheduler",
+			conditions: []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(scheduled)},
+			},
+			want: scheduled,
+		},
+		{
+			name: "the pod created with the node",
+			want: created,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			pod := newPod("pod", "node-1", created, nil)
+			pod.Status.Conditions = tt.conditions
+			assert.Equal(t, tt.want, boundTime(pod))
+		})
+	}
+}
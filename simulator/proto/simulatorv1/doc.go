@@ -0,0 +1,5 @@
+// Package simulatorv1 is the generated code of the simulator's gRPC API defined in simulator.proto.
+// Run `go generate` in this directory with protoc, protoc-gen-go and protoc-gen-go-grpc after changing simulator.proto.
+package simulatorv1
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative simulatorv1/simulator.proto
@@ -0,0 +1,223 @@
+// Package ratelimit limits the rate of the requests to the simulator's API for each client with the token buckets,
+// so that a buggy script can't destabilize the simulator by sending the mutating requests too often.
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"golang.org/x/xerrors"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// DefaultRequestsPerSecond is the default rate of the mutating requests allowed for each client.
+	DefaultRequestsPerSecond = 10
+	// DefaultBurst is the default number of the mutating requests each client can send at once.
+	DefaultBurst = 20
+)
+
+// ErrInvalidOptions is returned when Options is invalid.
+var ErrInvalidOptions = errors.New("invalid rate limit options")
+
+// Limit is the token bucket of each client.
+// The bucket is refilled with RequestsPerSecond tokens per second up to Burst tokens, and each request takes a token.
+type Limit struct {
+	// RequestsPerSecond is the rate of the requests allowed. The requests are unlimited if it's zero.
+	RequestsPerSecond int32
+	// Burst is the number of the requests which can be sent at once. RequestsPerSecond is used if it's zero.
+	Burst int32
+}
+
+func (l *Limit) validate() error {
+	if l.RequestsPerSecond < 0 || l.Burst < 0 {
+		return xerrors.Errorf("requestsPerSecond and burst must not be negative: %w", ErrInvalidOptions)
+	}
+	return nil
+}
+
+func (l *Limit) unlimited() bool {
+	return l.RequestsPerSecond == 0
+}
+
+func (l *Limit) burst() int {
+	if l.Burst == 0 {
+		return int(l.RequestsPerSecond)
+	}
+	return int(l.Burst)
+}
+
+// RouteLimit overrides the limit of the route.
+type RouteLimit struct {
+	// Method is the method of the route, e.g., POST.
+	Method string
+	// Path is the path of the route registered in the server, e.g., /api/v1/pods/bulk or /api/v1/jobs/:id.
+	Path string
+	Limit
+}
+
+// Options configures the limits.
+type Options struct {
+	// Default is applied to the mutating requests, i.e., the ones other than GET, HEAD and OPTIONS.
+	// The other requests, including the ones watching resources, are unlimited unless Routes limit them.
+	Default Limit
+	// Routes override Default for the routes.
+	Routes []RouteLimit
+}
+
+// DefaultOptions returns the options used when the limits aren't configured.
+func DefaultOptions() *Options {
+	return &Options{Default: Limit{RequestsPerSecond: DefaultRequestsPerSecond, Burst: DefaultBurst}}
+}
+
+// Validate returns an error wrapping ErrInvalidOptions if o is invalid.
+func (o *Options) Validate() error {
+	if err := o.Default.validate(); err != nil {
+		return xerrors.Errorf("default: %w", err)
+	}
+	routes := map[string]bool{}
+	for i, r := range o.Routes {
+		if r.Method == "" || !strings.HasPrefix(r.Path, "/") {
+			return xerrors.Errorf("routes[%d]: method and the path starting with / are required: %w", i, ErrInvalidOptions)
+		}
+		if routes[routeKey(r.Method, r.Path)] {
+			return xerrors.Errorf("routes[%d]: duplicated route %s %s: %w", i, r.Method, r.Path, ErrInvalidOptions)
+		}
+		routes[routeKey(r.Method, r.Path)] = true
+		if err := r.validate(); err != nil {
+			return xerrors.Errorf("routes[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// isMutating returns true if the request with the method mutates something.
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// bucket is the token bucket of a client for a route.
+type bucket struct {
+	limiter *rate.Limiter
+	// full is the time when the bucket becomes full if no request takes a token.
+	full time.Time
+}
+
+// Limiter decides whether each request is allowed.
+type Limiter struct {
+	clock clock.PassiveClock
+
+	mu     sync.Mutex
+	opts   *Options
+	routes map[string]Limit
+	// buckets are the buckets keyed by the route key, and then by the client.
+	buckets map[string]map[string]*bucket
+	// lastSweep is the last time when the full buckets are deleted.
+	lastSweep time.Time
+}
+
+// sweepInterval is the interval to delete the full buckets, which are the same as the new ones.
+const sweepInterval = time.Minute
+
+// New initializes Limiter. The options must be validated in advance.
+func New(opts *Options, clk clock.PassiveClock) *Limiter {
+	l := &Limiter{clock: clk, lastSweep: clk.Now()}
+	l.ApplyConfig(opts)
+	return l
+}
+
+// ApplyConfig replaces the limits with the ones in opts. The options must be validated in advance.
+// The buckets are reset so that the new limits are applied to the following requests right away.
+func (l *Limiter) ApplyConfig(opts *Options) {
+	routes := map[string]Limit{}
+	for _, r := range opts.Routes {
+		routes[routeKey(r.Method, r.Path)] = r.Limit
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.opts = opts
+	l.routes = routes
+	l.buckets = map[string]map[string]*bucket{}
+}
+
+// limit returns the limit of the route.
+// It must be called with mu held.
+func (l *Limiter) limit(method, path string) Limit {
+	if limit, ok := l.routes[routeKey(method, path)]; ok {
+		return limit
+	}
+	if isMutating(method) {
+		return l.opts.Default
+	}
+	return Limit{}
+}
+
+// Allow takes a token from the bucket of the client for the route of method and path.
+// If the bucket is empty, it returns false with the duration after which the client can retry.
+func (l *Limiter) Allow(client, method, path string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := l.limit(method, path)
+	if limit.unlimited() {
+		return true, 0
+	}
+
+	now := l.clock.Now()
+	l.sweep(now)
+	key := routeKey(method, path)
+	if l.buckets[key] == nil {
+		l.buckets[key] = map[string]*bucket{}
+	}
+	b, ok := l.buckets[key][client]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), limit.burst())}
+		l.buckets[key][client] = b
+	}
+
+	r := b.limiter.ReserveN(now, 1)
+	if !r.OK() {
+		// It never happens since the burst is at least one.
+		return false, 0
+	}
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		return false, delay
+	}
+	b.full = now.Add(time.Duration(math.Ceil(float64(time.Second) * (float64(limit.burst()) - b.limiter.TokensAt(now)) / float64(limit.RequestsPerSecond))))
+	return true, 0
+}
+
+// sweep deletes the buckets which have become full, so that the buckets of the clients gone don't stay forever.
+// It must be called with mu held.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, buckets := range l.buckets {
+		for client, b := range buckets {
+			if !now.Before(b.full) {
+				delete(buckets, client)
+			}
+		}
+		if len(buckets) == 0 {
+			delete(l.buckets, key)
+		}
+	}
+}
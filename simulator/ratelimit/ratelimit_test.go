@@ -0,0 +1,179 @@
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	t.Parallel()
+	clk := testingclock.NewFakePassiveClock(time.Now())
+	l := New(&Options{Default: Limit{RequestsPerSecond: 2, Burst: 3}}, clk)
+
+	for i := 0; i < 3; i++ {
+		ok, _ := l.Allow("client-a", http.MethodPost, "/api/v1/pods/bulk")
+		assert.True(t, ok, "the burst is allowed")
+	}
+	ok, retryAfter := l.Allow("client-a", http.MethodPost, "/api/v1/pods/bulk")
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	ok, _ = l.Allow("client-b", http.MethodPost, "/api/v1/pods/bulk")
+	assert.True(t, ok, "each client has its own bucket")
+	ok, _ = l.Allow("client-a", http.MethodPost, "/api/v1/nodes/bulk")
+	assert.True(t, ok, "each route has its own bucket")
+	for i := 0; i < 10; i++ {
+		ok, _ = l.Allow("client-a", http.MethodGet, "/api/v1/pods/bulk")
+		assert.True(t, ok, "the reads are unlimited")
+	}
+
+	clk.SetTime(clk.Now().Add(500 * time.Millisecond))
+	ok, _ = l.Allow("client-a", http.MethodPost, "/api/v1/pods/bulk")
+	assert.True(t, ok, "a token is refilled")
+	ok, _ = l.Allow("client-a", http.MethodPost, "/api/v1/pods/bulk")
+	assert.False(t, ok, "only one token is refilled")
+
+	clk.SetTime(clk.Now().Add(time.Hour))
+	for i := 0; i < 3; i++ {
+		ok, _ := l.Allow("client-a", http.MethodPost, "/api/v1/pods/bulk")
+		assert.True(t, ok, "the bucket is refilled up to the burst")
+	}
+	ok, _ = l.Allow("client-a", http.MethodPost, "/api/v1/pods/bulk")
+	assert.False(t, ok, "the bucket isn't refilled beyond the burst")
+}
+
+func TestLimiter_Allow_routes(t *testing.T) {
+	t.Parallel()
+	clk := testingclock.NewFakePassiveClock(time.Now())
+	l := New(&Options{
+		Default: Limit{RequestsPerSecond: 1},
+		Routes: []RouteLimit{
+			{Method: "post", Path: "/api/v1/import", Limit: Limit{}},
+			{Method: http.MethodGet, Path: "/api/v1/export", Limit: Limit{RequestsPerSecond: 1}},
+		},
+	}, clk)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   []bool
+	}{
+		{
+			name:   "default",
+			method: http.MethodPut,
+			path:   "/api/v1/reset",
+			want:   []bool{true, false},
+		},
+		{
+			name:   "unlimited by the override",
+			method: http.MethodPost,
+			path:   "/api/v1/import",
+			want:   []bool{true, true, true},
+		},
+		{
+			name:   "read limited by the override",
+			method: http.MethodGet,
+			path:   "/api/v1/export",
+			want:   []bool{true, false},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := []bool{}
+			for range tt.want {
+				ok, _ := l.Allow("client", tt.method, tt.path)
+				got = append(got, ok)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLimiter_sweep(t *testing.T) {
+	t.Parallel()
+	clk := testingclock.NewFakePassiveClock(time.Now())
+	l := New(&Options{Default: Limit{RequestsPerSecond: 1, Burst: 2}}, clk)
+
+	l.Allow("gone", http.MethodPost, "/api/v1/pods/bulk")
+	clk.SetTime(clk.Now().Add(sweepInterval))
+	l.Allow("active", http.MethodPost, "/api/v1/pods/bulk")
+
+	assert.Len(t, l.buckets, 1)
+	assert.NotContains(t, l.buckets[routeKey(http.MethodPost, "/api/v1/pods/bulk")], "gone", "the full bucket is deleted")
+}
+
+func TestLimiter_ApplyConfig(t *testing.T) {
+	t.Parallel()
+	clk := testingclock.NewFakePassiveClock(time.Now())
+	l := New(&Options{Default: Limit{RequestsPerSecond: 1, Burst: 1}}, clk)
+
+	ok, _ := l.Allow("client", http.MethodPost, "/api/v1/pods/bulk")
+	assert.True(t, ok)
+	ok, _ = l.Allow("client", http.MethodPost, "/api/v1/pods/bulk")
+	assert.False(t, ok)
+
+	l.ApplyConfig(&Options{Default: Limit{RequestsPerSecond: 1, Burst: 2}, Routes: []RouteLimit{
+		{Method: http.MethodPost, Path: "/api/v1/jobs", Limit: Limit{}},
+	}})
+	for i := 0; i < 2; i++ {
+		ok, _ = l.Allow("client", http.MethodPost, "/api/v1/pods/bulk")
+		assert.True(t, ok, "the new burst is applied to the client limited before")
+	}
+	ok, _ = l.Allow("client", http.MethodPost, "/api/v1/pods/bulk")
+	assert.False(t, ok)
+	for i := 0; i < 5; i++ {
+		ok, _ = l.Allow("client", http.MethodPost, "/api/v1/jobs")
+		assert.True(t, ok, "the new route is unlimited")
+	}
+}
+
+func TestOptions_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		opts    *Options
+		wantErr bool
+	}{
+		{
+			name: "default",
+			opts: DefaultOptions(),
+		},
+		{
+			name:    "negative rate",
+			opts:    &Options{Default: Limit{RequestsPerSecond: -1}},
+			wantErr: true,
+		},
+		{
+			name:    "route without path",
+			opts:    &Options{Routes: []RouteLimit{{Method: http.MethodPost}}},
+			wantErr: true,
+		},
+		{
+			name: "duplicated routes",
+			opts: &Options{Routes: []RouteLimit{
+				{Method: http.MethodPost, Path: "/api/v1/import"},
+				{Method: "post", Path: "/api/v1/import"},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.opts.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidOptions)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
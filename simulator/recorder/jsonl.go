@@ -0,0 +1,144 @@
+package recorder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// DefaultMaxBackups is the default number of the rotated files kept by JSONLWriter.
+const DefaultMaxBackups = 3
+
+// JSONLWriterOptions is the options for JSONLWriter.
+type JSONLWriterOptions struct {
+	// Truncate truncates the file if it already exists. Otherwise, the values are appended to the file.
+	Truncate bool
+	// MaxSize is the size in bytes which the file can grow up to.
+	// The file is rotated before it exceeds MaxSize; the file isn't rotated if it's zero.
+	MaxSize int64
+	// MaxBackups is the number of the rotated files to keep. DefaultMaxBackups is used if it's zero.
+	MaxBackups int
+}
+
+// JSONLWriter writes values to the file in JSON Lines format.
+// On rotation, the file is renamed to <path>.1, the older ones are shifted to <path>.2, <path>.3, ...,
+// and the ones beyond MaxBackups are removed.
+type JSONLWriter struct {
+	mu         sync.Mutex
+	path       string
+	file       *os.File
+	size       int64
+	maxSize    int64
+	maxBackups int
+}
+
+// NewJSONLWriter opens the file and initializes JSONLWriter.
+func NewJSONLWriter(path string, options JSONLWriterOptions) (*JSONLWriter, error) {
+	maxBackups := options.MaxBackups
+	if maxBackups <= 0 {
+		maxBackups = DefaultMaxBackups
+	}
+	w := &JSONLWriter{
+		path:       path,
+		maxSize:    options.MaxSize,
+		maxBackups: maxBackups,
+	}
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if options.Truncate {
+		flag |= os.O_TRUNC
+	}
+	if err := w.open(flag); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *JSONLWriter) open(flag int) error {
+	f, err := os.OpenFile(w.path, flag, 0o644) //nolint:gosec // the file is meant to be read by users.
+	if err != nil {
+		return xerrors.Errorf("failed to open %s: %w", w.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return xerrors.Errorf("failed to stat %s: %w", w.path, err)
+	}
+	w.file = f
+	w.size = info.Size()
+	return nil
+}
+
+// Write writes each value as a line.
+// All values are written to the same file; the file is rotated before writing them if needed.
+func (w *JSONLWriter) Write(values ...interface{}) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	content := make([]byte, 0)
+	for _, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return xerrors.Errorf("failed to marshal value: %w", err)
+		}
+
+		content = append(content, b...)
+		content = append(content, '\n')
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(content)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return xerrors.Errorf("failed to rotate %s: %w", w.path, err)
+		}
+	}
+
+	n, err := w.file.Write(content)
+	w.size += int64(n)
+	if err != nil {
+		return xerrors.Errorf("failed to write values: %w", err)
+	}
+
+	return nil
+}
+
+// rotate renames the current file to <path>.1 after shifting the older ones, and opens a new file.
+// It must be called with w.mu held.
+func (w *JSONLWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return xerrors.Errorf("failed to close the file: %w", err)
+	}
+
+	if err := os.Remove(backupPath(w.path, w.maxBackups)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return xerrors.Errorf("failed to remove the oldest file: %w", err)
+	}
+	for i := w.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(backupPath(w.path, i), backupPath(w.path, i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return xerrors.Errorf("failed to shift the rotated file: %w", err)
+		}
+	}
+	if err := os.Rename(w.path, backupPath(w.path, 1)); err != nil {
+		return xerrors.Errorf("failed to rename the file: %w", err)
+	}
+
+	return w.open(os.O_WRONLY | os.O_CREATE | os.O_TRUNC)
+}
+
+func backupPath(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
+
+// Close closes the file.
+func (w *JSONLWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.file.Close()
+}
@@ -0,0 +1,88 @@
+package recorder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLWriter(t *testing.T) {
+	t.Parallel()
+
+	type value struct {
+		N int `json:"n"`
+	}
+	// Each line is `{"n":X}\n` (8 bytes) with a single digit X.
+	tests := []struct {
+		name     string
+		existing string
+		options  JSONLWriterOptions
+		writes   [][]interface{}
+		// want is the content of the file and the rotated files, from the current one.
+		want []string
+	}{
+		{
+			name:     "append to the existing file",
+			existing: "{\"n\":0}\n",
+			writes:   [][]interface{}{{value{N: 1}, value{N: 2}}, {value{N: 3}}},
+			want:     []string{"{\"n\":0}\n{\"n\":1}\n{\"n\":2}\n{\"n\":3}\n"},
+		},
+		{
+			name:     "truncate the existing file",
+			existing: "{\"n\":0}\n",
+			options:  JSONLWriterOptions{Truncate: true},
+			writes:   [][]interface{}{{value{N: 1}}},
+			want:     []string{"{\"n\":1}\n"},
+		},
+		{
+			name:    "rotate the file before it exceeds MaxSize",
+			options: JSONLWriterOptions{MaxSize: 16},
+			writes:  [][]interface{}{{value{N: 1}, value{N: 2}}, {value{N: 3}}, {value{N: 4}, value{N: 5}}},
+			want:    []string{"{\"n\":4}\n{\"n\":5}\n", "{\"n\":3}\n", "{\"n\":1}\n{\"n\":2}\n"},
+		},
+		{
+			name:    "remove the rotated files beyond MaxBackups",
+			options: JSONLWriterOptions{MaxSize: 8, MaxBackups: 1},
+			writes:  [][]interface{}{{value{N: 1}}, {value{N: 2}}, {value{N: 3}}},
+			want:    []string{"{\"n\":3}\n", "{\"n\":2}\n"},
+		},
+		{
+			name:    "write the values exceeding MaxSize to the same file",
+			options: JSONLWriterOptions{MaxSize: 8},
+			writes:  [][]interface{}{{value{N: 1}, value{N: 2}}},
+			want:    []string{"{\"n\":1}\n{\"n\":2}\n"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "record.jsonl")
+			if tt.existing != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.existing), 0o600))
+			}
+
+			w, err := NewJSONLWriter(path, tt.options)
+			require.NoError(t, err)
+			for _, values := range tt.writes {
+				require.NoError(t, w.Write(values...))
+			}
+			require.NoError(t, w.Close())
+
+			for i, want := range tt.want {
+				p := path
+				if i > 0 {
+					p = backupPath(path, i)
+				}
+				got, err := os.ReadFile(p)
+				require.NoError(t, err)
+				require.Equal(t, want, string(got))
+			}
+			_, err = os.Stat(backupPath(path, len(tt.want)))
+			require.ErrorIs(t, err, os.ErrNotExist, "unexpected rotated file")
+		})
+	}
+}
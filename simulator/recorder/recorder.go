@@ -3,7 +3,6 @@ package recorder
 import (
 	"context"
 	"encoding/json"
-	"os"
 	"sync"
 	"time"
 
@@ -14,7 +13,9 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/lifecycle"
 )
 
 type Event string
@@ -28,12 +29,18 @@ var (
 const defaultPollInterval = 5 * time.Second
 
 type Service struct {
+	// Controller manages the lifecycle of the recorder started through the API.
+	// The events aren't recorded while it's paused.
+	*lifecycle.Controller
 	client       dynamic.Interface
 	gvrs         []schema.GroupVersionResource
 	path         string
 	records      []Record
 	recordsMutex sync.Mutex
 	pollInterval time.Duration
+	// optionsMutex guards gvrs and pollInterval, which can be changed with ApplyConfig.
+	optionsMutex sync.Mutex
+	logger       klog.Logger
 }
 
 type Record struct {
@@ -58,7 +65,24 @@ type Options struct {
 	FlushInterval *time.Duration
 }
 
-func New(client dynamic.Interface, options Options) *Service {
+// New initializes Service. logger is used for the logs of the recorder.
+func New(client dynamic.Interface, options Options, logger klog.Logger) *Service {
+	s := &Service{
+		client:       client,
+		path:         options.RecordFile,
+		records:      make([]Record, 0),
+		recordsMutex: sync.Mutex{},
+		logger:       logger,
+	}
+	s.ApplyConfig(options)
+	s.Controller = lifecycle.NewController(s.prepare)
+	return s
+}
+
+// ApplyConfig changes the resources recorded and the flush interval to the ones in options.
+// They take effect when the recorder is started next time. RecordFile in options is ignored
+// since the file can be changed when the recorder is started through the API.
+func (s *Service) ApplyConfig(options Options) {
 	gvrs := DefaultGVRs
 	if options.GVRs != nil {
 		gvrs = options.GVRs
@@ -69,27 +93,62 @@ func New(client dynamic.Interface, options Options) *Service {
 		pollInterval = *options.FlushInterval
 	}
 
-	return &Service{
-		client:       client,
-		gvrs:         gvrs,
-		path:         options.RecordFile,
-		records:      make([]Record, 0),
-		recordsMutex: sync.Mutex{},
-		pollInterval: pollInterval,
+	s.optionsMutex.Lock()
+	defer s.optionsMutex.Unlock()
+	s.gvrs = gvrs
+	s.pollInterval = pollInterval
+}
+
+// options returns the resources recorded and the flush interval.
+func (s *Service) options() ([]schema.GroupVersionResource, time.Duration) {
+	s.optionsMutex.Lock()
+	defer s.optionsMutex.Unlock()
+	return s.gvrs, s.pollInterval
+}
+
+// StartOptions are the options to start the recorder through the API.
+type StartOptions struct {
+	// RecordFile is the file to record the events in instead of the one in Options.
+	RecordFile string `json:"recordFile,omitempty"`
+}
+
+// prepare returns the function to record the events until the recorder is stopped.
+func (s *Service) prepare(options json.RawMessage) (lifecycle.RunFunc, error) {
+	opts := StartOptions{}
+	if err := lifecycle.DecodeOptions(options, &opts); err != nil {
+		return nil, err
 	}
+	path := s.path
+	if opts.RecordFile != "" {
+		path = opts.RecordFile
+	}
+	return func(ctx context.Context, ready func()) error {
+		if err := s.run(ctx, path); err != nil {
+			return err
+		}
+		ready()
+		<-ctx.Done()
+		return nil
+	}, nil
 }
 
 func (s *Service) Run(ctx context.Context) error {
+	return s.run(ctx, s.path)
+}
+
+// run records the events in the file at path until ctx is cancelled.
+func (s *Service) run(ctx context.Context, path string) error {
 	// create or recreate the file
-	f, err := os.Create(s.path)
+	w, err := NewJSONLWriter(path, JSONLWriterOptions{Truncate: true})
 	if err != nil {
 		return xerrors.Errorf("failed to create record file: %w", err)
 	}
 
-	go s.record(ctx, f)
+	gvrs, pollInterval := s.options()
+	go s.record(ctx, w, pollInterval)
 
 	infFact := dynamicinformer.NewFilteredDynamicSharedInformerFactory(s.client, 0, metav1.NamespaceAll, nil)
-	for _, gvr := range s.gvrs {
+	for _, gvr := range gvrs {
 		inf := infFact.ForResource(gvr).Informer()
 		_, err := inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
 			AddFunc:    func(obj interface{}) { s.recordEvent(obj, Add) },
@@ -107,9 +166,12 @@ func (s *Service) Run(ctx context.Context) error {
 }
 
 func (s *Service) recordEvent(obj interface{}, e Event) {
+	if s.Paused() {
+		return
+	}
 	unstructObj, ok := obj.(*unstructured.Unstructured)
 	if !ok {
-		klog.Error("Failed to convert runtime.Object to *unstructured.Unstructured")
+		s.logger.Error(nil, "Failed to convert runtime.Object to *unstructured.Unstructured")
 		return
 	}
 
@@ -138,58 +200,44 @@ func (s *Service) recordEvent(obj interface{}, e Event) {
 	s.recordsMutex.Unlock()
 }
 
-func (s *Service) record(ctx context.Context, file *os.File) {
-	defer file.Close()
+func (s *Service) record(ctx context.Context, w *JSONLWriter, pollInterval time.Duration) {
+	defer w.Close()
 
-	ticker := time.NewTicker(s.pollInterval)
+	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
-			if err := s.flushRecords(file); err != nil {
-				klog.Errorf("failed to flush records: %v", err)
+			if err := s.flushRecords(w); err != nil {
+				s.logger.Error(err, "failed to flush records")
 			}
 			return
 		case <-ticker.C:
-			if err := s.flushRecords(file); err != nil {
-				klog.Errorf("failed to flush records: %v", err)
+			if err := s.flushRecords(w); err != nil {
+				s.logger.Error(err, "failed to flush records")
 			}
 		}
 	}
 }
 
-func (s *Service) flushRecords(file *os.File) error {
+func (s *Service) flushRecords(w *JSONLWriter) error {
+	s.recordsMutex.Lock()
 	if len(s.records) == 0 {
+		s.recordsMutex.Unlock()
 		return nil
 	}
-
-	s.recordsMutex.Lock()
 	records := s.records
 	s.records = make([]Record, 0)
 	s.recordsMutex.Unlock()
 
-	if err := appendToFile(file, records); err != nil {
-		return xerrors.Errorf("failed to append record to file: %w", err)
+	values := make([]interface{}, 0, len(records))
+	for i := range records {
+		// Record must be passed as a pointer so that Resource is marshaled with its MarshalJSON.
+		values = append(values, &records[i])
 	}
-
-	return nil
-}
-
-func appendToFile(file *os.File, records []Record) error {
-	content := make([]byte, 0)
-	for _, record := range records {
-		b, err := json.Marshal(&record)
-		if err != nil {
-			return xerrors.Errorf("failed to marshal record: %w", err)
-		}
-
-		content = append(content, b...)
-		content = append(content, '\n')
-	}
-
-	if _, err := file.Write(content); err != nil {
-		return xerrors.Errorf("failed to write record: %w", err)
+	if err := w.Write(values...); err != nil {
+		return xerrors.Errorf("failed to append record to file: %w", err)
 	}
 
 	return nil
@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	dynamicFake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/restmapper"
+	"k8s.io/klog/v2"
 	appsv1 "k8s.io/kubernetes/pkg/apis/apps/v1"
 	schedulingv1 "k8s.io/kubernetes/pkg/apis/scheduling/v1"
 	storagev1 "k8s.io/kubernetes/pkg/apis/storage/v1"
@@ -257,7 +258,7 @@ func TestRecorder(t *testing.T) {
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 
-			service := New(client, Options{RecordFile: filePath, FlushInterval: ptr.To(100 * time.Millisecond)})
+			service := New(client, Options{RecordFile: filePath, FlushInterval: ptr.To(100 * time.Millisecond)}, klog.Background())
 			err := service.Run(ctx)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.Record() error = %v, wantErr %v", err, tt.wantErr)
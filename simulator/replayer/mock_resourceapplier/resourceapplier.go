@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: simulator/resourceapplier/resourceapplier.go
+// Source: simulator/replayer/replayer.go
 //
 // Generated by this command:
 //
-//	mockgen -source=simulator/resourceapplier/resourceapplier.go -destination=simulator/replayer/mock_resourceapplier/resourceapplier.go
+//	mockgen -source=simulator/replayer/replayer.go -destination=simulator/replayer/mock_resourceapplier/resourceapplier.go -package=mock_resourceapplier
 //
 
 // Package mock_resourceapplier is a generated GoMock package.
@@ -15,6 +15,7 @@ import (
 
 	gomock "go.uber.org/mock/gomock"
 	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	resourceapplier "sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
 )
 
 // MockResourceApplier is a mock of ResourceApplier interface.
@@ -41,6 +42,21 @@ func (m *MockResourceApplier) EXPECT() *MockResourceApplierMockRecorder {
 	return m.recorder
 }
 
+// ApplyAll mocks base method.
+func (m *MockResourceApplier) ApplyAll(ctx context.Context, resources []unstructured.Unstructured, opts resourceapplier.ApplyAllOptions) ([]resourceapplier.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyAll", ctx, resources, opts)
+	ret0, _ := ret[0].([]resourceapplier.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyAll indicates an expected call of ApplyAll.
+func (mr *MockResourceApplierMockRecorder) ApplyAll(ctx, resources, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyAll", reflect.TypeOf((*MockResourceApplier)(nil).ApplyAll), ctx, resources, opts)
+}
+
 // Create mocks base method.
 func (m *MockResourceApplier) Create(ctx context.Context, resource *unstructured.Unstructured) error {
 	m.ctrl.T.Helper()
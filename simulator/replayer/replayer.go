@@ -10,17 +10,24 @@ import (
 	"golang.org/x/xerrors"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/lifecycle"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/recorder"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
 )
 
 type Service struct {
+	// Controller manages the lifecycle of the replayer started through the API.
+	// The replay waits before the next event while it's paused.
+	*lifecycle.Controller
 	applier    ResourceApplier
 	recordFile string
+	logger     klog.Logger
 }
 
 type ResourceApplier interface {
+	ApplyAll(ctx context.Context, resources []unstructured.Unstructured, opts resourceapplier.ApplyAllOptions) ([]resourceapplier.Result, error)
 	Create(ctx context.Context, resource *unstructured.Unstructured) error
 	Update(ctx context.Context, resource *unstructured.Unstructured) error
 	Delete(ctx context.Context, resource *unstructured.Unstructured) error
@@ -30,12 +37,43 @@ type Options struct {
 	RecordFile string
 }
 
-func New(applier ResourceApplier, options Options) *Service {
-	return &Service{applier: applier, recordFile: options.RecordFile}
+// New initializes Service. logger is used for the logs of the replayer.
+func New(applier ResourceApplier, options Options, logger klog.Logger) *Service {
+	s := &Service{applier: applier, recordFile: options.RecordFile, logger: logger}
+	s.Controller = lifecycle.NewController(s.prepare)
+	return s
+}
+
+// StartOptions are the options to start the replayer through the API.
+type StartOptions struct {
+	// RecordFile is the file to replay the events from instead of the one in Options.
+	RecordFile string `json:"recordFile,omitempty"`
+}
+
+// prepare returns the function to replay the events until all of them are replayed or the replayer is stopped.
+func (s *Service) prepare(options json.RawMessage) (lifecycle.RunFunc, error) {
+	opts := StartOptions{}
+	if err := lifecycle.DecodeOptions(options, &opts); err != nil {
+		return nil, err
+	}
+	path := s.recordFile
+	if opts.RecordFile != "" {
+		path = opts.RecordFile
+	}
+	return func(ctx context.Context, ready func()) error {
+		ready()
+		return s.replay(ctx, path)
+	}, nil
 }
 
 func (s *Service) Replay(ctx context.Context) error {
-	file, err := os.Open(s.recordFile)
+	return s.replay(ctx, s.recordFile)
+}
+
+// replay replays the events recorded in the file at path.
+// The consecutive Add events are created together so that they're applied in the order of their dependencies.
+func (s *Service) replay(ctx context.Context, path string) error {
+	file, err := os.Open(path)
 	if err != nil {
 		return xerrors.Errorf("failed to read record file: %w", err)
 	}
@@ -43,6 +81,7 @@ func (s *Service) Replay(ctx context.Context) error {
 
 	reader := bufio.NewReader(file)
 
+	var added []unstructured.Unstructured
 	for {
 		record, err := s.loadRecordFromLine(reader)
 		if err != nil {
@@ -51,12 +90,23 @@ func (s *Service) Replay(ctx context.Context) error {
 		if record == nil {
 			break
 		}
+		if record.Event == recorder.Add {
+			added = append(added, record.Resource)
+			continue
+		}
 
+		if err := s.createResources(ctx, added); err != nil {
+			return xerrors.Errorf("failed to apply event: %w", err)
+		}
+		added = nil
 		if err := s.applyEvent(ctx, *record); err != nil {
 			return xerrors.Errorf("failed to apply event: %w", err)
 		}
 	}
 
+	if err := s.createResources(ctx, added); err != nil {
+		return xerrors.Errorf("failed to apply event: %w", err)
+	}
 	return nil
 }
 
@@ -77,27 +127,57 @@ func (s *Service) loadRecordFromLine(reader *bufio.Reader) (*recorder.Record, er
 	return record, nil
 }
 
+// createResources creates the resources of the Add events with ApplyAll.
+// They're created one by one, not to change the order of the recorded resources of the same rank, e.g., Pods.
+func (s *Service) createResources(ctx context.Context, resources []unstructured.Unstructured) error {
+	if len(resources) == 0 {
+		return nil
+	}
+	if err := s.WaitResumed(); err != nil {
+		return xerrors.Errorf("wait for the replayer to be resumed: %w", err)
+	}
+	_, err := s.applier.ApplyAll(ctx, resources, resourceapplier.ApplyAllOptions{
+		Workers:     1,
+		StopOnError: true,
+		Apply: func(ctx context.Context, resource *unstructured.Unstructured) error {
+			if err := s.applier.Create(ctx, resource); err != nil {
+				if !errors.IsAlreadyExists(err) {
+					return err
+				}
+				s.logger.Info("resource already exists", "err", err)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to create resource: %w", err)
+	}
+	return nil
+}
+
+// applyEvent applies the Update or Delete event with ApplyAll.
+// Each of them is applied alone since their order can't be changed.
 func (s *Service) applyEvent(ctx context.Context, record recorder.Record) error {
+	var apply resourceapplier.ApplyFunc
+	var action string
 	switch record.Event {
-	case recorder.Add:
-		if err := s.applier.Create(ctx, &record.Resource); err != nil {
-			if errors.IsAlreadyExists(err) {
-				klog.Warningf("resource already exists: %v", err)
-			} else {
-				return xerrors.Errorf("failed to create resource: %w", err)
-			}
-		}
 	case recorder.Update:
-		if err := s.applier.Update(ctx, &record.Resource); err != nil {
-			return xerrors.Errorf("failed to update resource: %w", err)
-		}
+		apply, action = s.applier.Update, "update"
 	case recorder.Delete:
-		if err := s.applier.Delete(ctx, &record.Resource); err != nil {
-			return xerrors.Errorf("failed to delete resource: %w", err)
-		}
+		apply, action = s.applier.Delete, "delete"
 	default:
 		return xerrors.Errorf("unknown event: %v", record.Event)
 	}
 
+	if err := s.WaitResumed(); err != nil {
+		return xerrors.Errorf("wait for the replayer to be resumed: %w", err)
+	}
+	_, err := s.applier.ApplyAll(ctx, []unstructured.Unstructured{record.Resource}, resourceapplier.ApplyAllOptions{
+		StopOnError: true,
+		Apply:       apply,
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to %s resource: %w", action, err)
+	}
 	return nil
 }
@@ -13,11 +13,42 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/recorder"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/replayer/mock_resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
 )
 
+func newResource(kind, name string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+			},
+		},
+	}
+}
+
+// expectApplyAll makes ApplyAll apply the resources in the given order with opts.Apply.
+func expectApplyAll(applier *mock_resourceapplier.MockResourceApplier) {
+	applier.EXPECT().ApplyAll(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, resources []unstructured.Unstructured, opts resourceapplier.ApplyAllOptions) ([]resourceapplier.Result, error) {
+			results := make([]resourceapplier.Result, 0, len(resources))
+			for i := range resources {
+				err := opts.Apply(ctx, &resources[i])
+				results = append(results, resourceapplier.Result{Resource: &resources[i], Err: err})
+				if err != nil && opts.StopOnError {
+					return results, err
+				}
+			}
+			return results, nil
+		}).AnyTimes()
+}
+
 func TestService_Replay(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -92,6 +123,34 @@ func TestService_Replay(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "the events are applied in the recorded order",
+			records: []recorder.Record{
+				{Event: recorder.Add, Resource: newResource("Pod", "pod-1")},
+				{Event: recorder.Update, Resource: newResource("Pod", "pod-1")},
+				{Event: recorder.Add, Resource: newResource("Pod", "pod-2")},
+				{Event: recorder.Delete, Resource: newResource("Pod", "pod-1")},
+			},
+			prepareMockFn: func(applier *mock_resourceapplier.MockResourceApplier) {
+				gomock.InOrder(
+					applier.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil),
+					applier.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil),
+					applier.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil),
+					applier.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name: "should return error if Delete raise an error",
+			records: []recorder.Record{
+				{Event: recorder.Delete, Resource: newResource("Pod", "pod-1")},
+			},
+			prepareMockFn: func(applier *mock_resourceapplier.MockResourceApplier) {
+				applier.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(xerrors.Errorf("failed to delete resource"))
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -102,6 +161,7 @@ func TestService_Replay(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockApplier := mock_resourceapplier.NewMockResourceApplier(ctrl)
+			expectApplyAll(mockApplier)
 			tt.prepareMockFn(mockApplier)
 
 			fileName := strings.ReplaceAll(tt.name, " ", "_") + ".jsonl"
@@ -122,7 +182,7 @@ func TestService_Replay(t *testing.T) {
 				t.Fatalf("failed to close temp file: %v", err)
 			}
 
-			service := New(mockApplier, Options{RecordFile: filePath})
+			service := New(mockApplier, Options{RecordFile: filePath}, klog.Background())
 
 			err = service.Replay(context.Background())
 			if (err != nil) != tt.wantErr {
@@ -132,6 +192,42 @@ func TestService_Replay(t *testing.T) {
 	}
 }
 
+func TestService_Replay_consecutiveAdds(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockApplier := mock_resourceapplier.NewMockResourceApplier(ctrl)
+	records := []recorder.Record{
+		{Event: recorder.Add, Resource: newResource("Pod", "pod-1")},
+		{Event: recorder.Add, Resource: newResource("Node", "node-1")},
+		{Event: recorder.Add, Resource: newResource("Pod", "pod-2")},
+	}
+	// The consecutive Add events are created by one ApplyAll in the recorded order of the same kind.
+	mockApplier.EXPECT().ApplyAll(gomock.Any(), []unstructured.Unstructured{records[0].Resource, records[1].Resource, records[2].Resource}, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ []unstructured.Unstructured, opts resourceapplier.ApplyAllOptions) ([]resourceapplier.Result, error) {
+			if opts.Workers != 1 || !opts.StopOnError {
+				t.Errorf("unexpected options: %+v", opts)
+			}
+			return nil, nil
+		})
+
+	filePath := path.Join(t.TempDir(), "record.jsonl")
+	tempFile, err := os.Create(filePath)
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	if err := writeRecordsToFile(tempFile, records); err != nil {
+		t.Fatalf("failed to marshal records: %v", err)
+	}
+	if err := tempFile.Close(); err != nil {
+		t.Fatalf("failed to close temp file: %v", err)
+	}
+
+	service := New(mockApplier, Options{RecordFile: filePath}, klog.Background())
+	if err := service.Replay(context.Background()); err != nil {
+		t.Errorf("Service.Replay() error = %v", err)
+	}
+}
+
 func writeRecordsToFile(file *os.File, records []recorder.Record) error {
 	for _, record := range records {
 		b, err := json.Marshal(&record)
@@ -0,0 +1,322 @@
+// Package reschedule reschedules the scheduled Pods, e.g., to see the effect of a new scheduler configuration on them.
+// The scheduler never moves the Pods already bound, so the Pods are deleted and recreated unbound instead.
+package reschedule
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
+)
+
+// KeepPlacementAnnotationKey is the annotation of the Pods which aren't rescheduled, regardless of its value.
+const KeepPlacementAnnotationKey = "simulator/keep-placement"
+
+const (
+	// DefaultPollInterval is the default interval to check whether the recreated Pods are scheduled.
+	DefaultPollInterval = time.Second
+	// DefaultTimeout is the default time to wait for the recreated Pods to be scheduled.
+	DefaultTimeout = 5 * time.Minute
+)
+
+// simulatorAnnotationPrefix is the prefix of the annotations of the scheduling results, which are dropped from the recreated Pods.
+const simulatorAnnotationPrefix = "kube-scheduler-simulator.sigs.k8s.io/"
+
+// JobManager runs the rescheduling as the background jobs.
+type JobManager interface {
+	Submit(typ job.Type, fn job.Func) (*job.Job, error)
+}
+
+// Options is the options of Service.
+type Options struct {
+	// PollInterval is the interval to check whether the recreated Pods are scheduled. DefaultPollInterval is used if zero.
+	PollInterval time.Duration
+	// Timeout is the time to wait for the recreated Pods to be scheduled. DefaultTimeout is used if zero.
+	// The Pods not scheduled by then are reported without the node after the rescheduling.
+	Timeout time.Duration
+}
+
+// Result is the placements of the Pods before and after the rescheduling, which is reported as job.Job.Result.
+type Result struct {
+	// Pods are the rescheduled Pods in the order of the namespaces and the names.
+	Pods []Placement `json:"pods"`
+	// Moved is the number of the Pods scheduled to the other nodes than before.
+	Moved int `json:"moved"`
+	// Unscheduled is the number of the Pods which aren't scheduled after the rescheduling.
+	Unscheduled int `json:"unscheduled"`
+	// Excluded is the number of the scheduled Pods which aren't rescheduled because of KeepPlacementAnnotationKey,
+	// or because they have finalizers, which keep them from being deleted.
+	Excluded int `json:"excluded"`
+}
+
+// Placement is the nodes of a Pod before and after the rescheduling.
+type Placement struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Before    string `json:"before"`
+	// After is empty if the Pod isn't scheduled after the rescheduling, e.g., it's unschedulable with the new configuration.
+	After string `json:"after,omitempty"`
+	// Changed is true if After is different from Before.
+	Changed bool `json:"changed"`
+}
+
+// Service reschedules the scheduled Pods as the jobs of job.TypeReschedule.
+type Service struct {
+	client       clientset.Interface
+	jobs         JobManager
+	pollInterval time.Duration
+	timeout      time.Duration
+}
+
+// NewService initializes Service.
+func NewService(client clientset.Interface, jobs JobManager, opts Options) *Service {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	return &Service{client: client, jobs: jobs, pollInterval: opts.PollInterval, timeout: opts.Timeout}
+}
+
+// Submit starts rescheduling all the scheduled Pods except the ones with KeepPlacementAnnotationKey in background,
+// and returns the job without waiting for it to finish. The job's result is *Result.
+// It returns job.ErrConflict if another rescheduling is in progress.
+func (s *Service) Submit() (*job.Job, error) {
+	j, err := s.jobs.Submit(job.TypeReschedule, func(ctx context.Context, report func(job.Progress)) (interface{}, error) {
+		return s.Reschedule(ctx, report)
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("submit the job of rescheduling: %w", err)
+	}
+	return j, nil
+}
+
+// Reschedule deletes the scheduled Pods except the ones with KeepPlacementAnnotationKey, recreates them unbound,
+// and waits for them to be scheduled or unschedulable. report is called with the progress of each phase.
+//
+// All the Pods are deleted before any of them is recreated, so that the scheduler places them without their old placements.
+// If it fails or ctx is canceled before all of them are recreated, the deleted ones are recreated even after ctx is canceled
+// so that no Pod is lost.
+// The Pods which have finished, i.e., Succeeded or Failed, aren't rescheduled.
+// The Pods with finalizers aren't rescheduled either, since they aren't deleted until something removes the finalizers.
+func (s *Service) Reschedule(ctx context.Context, report func(job.Progress)) (*Result, error) {
+	pods, err := s.client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, xerrors.Errorf("list pods: %w", err)
+	}
+	result := &Result{Pods: []Placement{}}
+	targets := []*corev1.Pod{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if _, ok := pod.Annotations[KeepPlacementAnnotationKey]; ok || len(pod.Finalizers) > 0 {
+			result.Excluded++
+			continue
+		}
+		targets = append(targets, pod)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Namespace != targets[j].Namespace {
+			return targets[i].Namespace < targets[j].Namespace
+		}
+		return targets[i].Name < targets[j].Name
+	})
+
+	total := len(targets)
+	for i, pod := range targets {
+		report(job.Progress{Done: i, Total: total, Message: "deleting the scheduled pods"})
+		if err := s.deletePod(ctx, pod); err != nil {
+			return nil, s.restore(ctx, targets, err)
+		}
+	}
+	for i, pod := range targets {
+		report(job.Progress{Done: i, Total: total, Message: "recreating the pods"})
+		if _, err := s.client.CoreV1().Pods(pod.Namespace).Create(ctx, unboundPod(pod), metav1.CreateOptions{}); err != nil {
+			return nil, s.restore(ctx, targets, xerrors.Errorf("recreate pod %s/%s: %w", pod.Namespace, pod.Name, err))
+		}
+	}
+
+	after, err := s.waitForSettled(ctx, targets, func(settled int) {
+		report(job.Progress{Done: settled, Total: total, Message: "waiting for the pods to be scheduled"})
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range targets {
+		p := Placement{Namespace: pod.Namespace, Name: pod.Name, Before: pod.Spec.NodeName, After: after[key(pod)]}
+		p.Changed = p.After != p.Before
+		switch {
+		case p.After == "":
+			result.Unscheduled++
+		case p.Changed:
+			result.Moved++
+		}
+		result.Pods = append(result.Pods, p)
+	}
+	report(job.Progress{Done: total, Total: total, Message: fmt.Sprintf("%d pods are rescheduled", total)})
+	return result, nil
+}
+
+// deletePod deletes pod immediately, and waits for it to disappear so that it can be recreated with the same name.
+func (s *Service) deletePod(ctx context.Context, pod *corev1.Pod) error {
+	err := s.client.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{
+		GracePeriodSeconds: ptr.To[int64](0),
+		Preconditions:      metav1.NewUIDPreconditions(string(pod.UID)),
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return xerrors.Errorf("delete pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	}
+	return s.waitForDeleted(ctx, pod)
+}
+
+// waitForDeleted waits for pod to disappear, or to be replaced with another Pod with the same name.
+func (s *Service) waitForDeleted(ctx context.Context, pod *corev1.Pod) error {
+	err := wait.PollUntilContextTimeout(ctx, s.pollInterval, s.timeout, true, func(ctx context.Context) (bool, error) {
+		p, err := s.client.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, xerrors.Errorf("get pod: %w", err)
+		}
+		return p.UID != pod.UID, nil
+	})
+	if err != nil {
+		return xerrors.Errorf("wait for pod %s/%s to be deleted: %w", pod.Namespace, pod.Name, err)
+	}
+	return nil
+}
+
+// restore recreates the Pods in pods which have been deleted but not recreated yet, after the rescheduling fails with cause.
+// It's done even after ctx is canceled, since the Pods are lost otherwise.
+// The Pods still existing aren't touched, and the ones being deleted are recreated after they disappear.
+// It returns the error wrapping cause, with the failures of the restoration if any.
+func (s *Service) restore(ctx context.Context, pods []*corev1.Pod, cause error) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.timeout)
+	defer cancel()
+
+	var failed []string
+	for _, pod := range pods {
+		if err := s.restorePod(ctx, pod); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return xerrors.Errorf("%w, and failed to recreate the deleted pods: %s", cause, strings.Join(failed, "; "))
+	}
+	return cause
+}
+
+// restorePod recreates pod unbound if it has been deleted.
+func (s *Service) restorePod(ctx context.Context, pod *corev1.Pod) error {
+	p, err := s.client.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return xerrors.Errorf("get pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	case p.UID != pod.UID:
+		// it's already recreated.
+		return nil
+	case p.DeletionTimestamp == nil:
+		// it hasn't been deleted.
+		return nil
+	default:
+		if err := s.waitForDeleted(ctx, pod); err != nil {
+			return err
+		}
+	}
+	if _, err := s.client.CoreV1().Pods(pod.Namespace).Create(ctx, unboundPod(pod), metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return xerrors.Errorf("recreate pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	}
+	return nil
+}
+
+// waitForSettled waits until all the recreated pods are scheduled or unschedulable, or the timeout expires.
+// It returns the nodes of the scheduled Pods by their keys.
+func (s *Service) waitForSettled(ctx context.Context, pods []*corev1.Pod, report func(settled int)) (map[string]string, error) {
+	targets := make(map[string]bool, len(pods))
+	for _, p := range pods {
+		targets[key(p)] = true
+	}
+	nodes := map[string]string{}
+	err := wait.PollUntilContextTimeout(ctx, s.pollInterval, s.timeout, true, func(ctx context.Context) (bool, error) {
+		list, err := s.client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, xerrors.Errorf("list pods: %w", err)
+		}
+		settled := 0
+		for i := range list.Items {
+			p := &list.Items[i]
+			if !targets[key(p)] {
+				continue
+			}
+			if p.Spec.NodeName != "" {
+				nodes[key(p)] = p.Spec.NodeName
+				settled++
+				continue
+			}
+			if unschedulable(p) {
+				settled++
+			}
+		}
+		report(settled)
+		return settled == len(targets), nil
+	})
+	if err != nil && ctx.Err() != nil {
+		return nil, xerrors.Errorf("wait for the pods to be scheduled: %w", ctx.Err())
+	}
+	// The Pods not scheduled in time are reported as unscheduled.
+	return nodes, nil
+}
+
+// unboundPod returns the copy of pod to recreate, without the node, the status and the results of the scheduling.
+func unboundPod(pod *corev1.Pod) *corev1.Pod {
+	p := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            pod.Name,
+			Namespace:       pod.Namespace,
+			Labels:          pod.Labels,
+			OwnerReferences: pod.OwnerReferences,
+		},
+		Spec: *pod.Spec.DeepCopy(),
+	}
+	for k, v := range pod.Annotations {
+		if strings.HasPrefix(k, simulatorAnnotationPrefix) {
+			continue
+		}
+		if p.Annotations == nil {
+			p.Annotations = map[string]string{}
+		}
+		p.Annotations[k] = v
+	}
+	p.Spec.NodeName = ""
+	return p
+}
+
+// unschedulable returns true if the scheduler has failed to schedule pod because no node fits it.
+func unschedulable(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
+			return true
+		}
+	}
+	return false
+}
+
+func key(pod *corev1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
@@ -0,0 +1,148 @@
+package reschedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
+)
+
+func TestService_Reschedule(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset(
+		pod("moved", "node1", nil, ""),
+		pod("stay", "node1", map[string]string{"kube-scheduler-simulator.sigs.k8s.io/result-history": "[]", "user": "value"}, ""),
+		pod("unschedulable", "node2", nil, ""),
+		pod("kept", "node1", map[string]string{KeepPlacementAnnotationKey: ""}, ""),
+		pod("pending", "", nil, ""),
+		pod("succeeded", "node2", nil, corev1.PodSucceeded),
+		withFinalizers(pod("finalizing", "node1", nil, ""), "example.com/protect"),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The placements with the new configuration. The pods not here aren't touched by the fake scheduler.
+	go fakeScheduler(ctx, client, map[string]string{"moved": "node2", "stay": "node1", "unschedulable": ""})
+
+	s := NewService(client, nil, Options{PollInterval: 10 * time.Millisecond, Timeout: 5 * time.Second})
+	var progress []job.Progress
+	got, err := s.Reschedule(ctx, func(p job.Progress) { progress = append(progress, p) })
+	require.NoError(t, err)
+
+	assert.Equal(t, &Result{
+		Pods: []Placement{
+			{Namespace: "default", Name: "moved", Before: "node1", After: "node2", Changed: true},
+			{Namespace: "default", Name: "stay", Before: "node1", After: "node1"},
+			{Namespace: "default", Name: "unschedulable", Before: "node2", Changed: true},
+		},
+		Moved:       1,
+		Unscheduled: 1,
+		Excluded:    2,
+	}, got)
+	require.NotEmpty(t, progress)
+	assert.Equal(t, job.Progress{Done: 3, Total: 3, Message: "3 pods are rescheduled"}, progress[len(progress)-1])
+
+	// The excluded pods are never recreated.
+	for _, name := range []string{"kept", "pending", "succeeded", "finalizing"} {
+		p, err := client.CoreV1().Pods("default").Get(ctx, name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, types.UID("uid-"+name), p.UID, name)
+	}
+	// The results of the old scheduling are dropped from the recreated pod.
+	stay, err := client.CoreV1().Pods("default").Get(ctx, "stay", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"user": "value"}, stay.Annotations)
+}
+
+func TestService_Reschedule_timeout(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset(pod("pod1", "node1", nil, ""))
+	// Nothing schedules the recreated pod.
+	s := NewService(client, nil, Options{PollInterval: 10 * time.Millisecond, Timeout: 100 * time.Millisecond})
+	got, err := s.Reschedule(context.Background(), func(job.Progress) {})
+	require.NoError(t, err)
+
+	assert.Equal(t, &Result{
+		Pods:        []Placement{{Namespace: "default", Name: "pod1", Before: "node1", Changed: true}},
+		Unscheduled: 1,
+	}, got)
+	p, err := client.CoreV1().Pods("default").Get(context.Background(), "pod1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, p.Spec.NodeName)
+}
+
+func TestService_Reschedule_canceled(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset(pod("pod1", "node1", nil, ""), pod("pod2", "node1", nil, ""), pod("pod3", "node2", nil, ""))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The job is canceled while the first pod is recreated, after all the pods are deleted.
+	canceled := false
+	client.PrependReactor("create", "pods", func(clienttesting.Action) (bool, runtime.Object, error) {
+		if canceled {
+			return false, nil, nil
+		}
+		canceled = true
+		cancel()
+		return true, nil, context.Canceled
+	})
+
+	s := NewService(client, nil, Options{PollInterval: 10 * time.Millisecond, Timeout: time.Second})
+	_, err := s.Reschedule(ctx, func(job.Progress) {})
+	require.ErrorIs(t, err, context.Canceled)
+
+	// No pod is lost, and all of them are recreated unbound.
+	for _, name := range []string{"pod1", "pod2", "pod3"} {
+		p, err := client.CoreV1().Pods("default").Get(context.Background(), name, metav1.GetOptions{})
+		require.NoError(t, err, name)
+		assert.Empty(t, p.Spec.NodeName, name)
+	}
+}
+
+// fakeScheduler binds the unbound pods in placements to their nodes, or marks them unschedulable if the node is empty,
+// until ctx is canceled.
+func fakeScheduler(ctx context.Context, client clientset.Interface, placements map[string]string) {
+	for ctx.Err() == nil {
+		pods, err := client.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return
+		}
+		for i := range pods.Items {
+			p := &pods.Items[i]
+			node, ok := placements[p.Name]
+			if !ok || p.Spec.NodeName != "" || len(p.Status.Conditions) > 0 {
+				continue
+			}
+			if node == "" {
+				p.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable}}
+			} else {
+				p.Spec.NodeName = node
+			}
+			_, _ = client.CoreV1().Pods("default").Update(ctx, p, metav1.UpdateOptions{})
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func withFinalizers(p *corev1.Pod, finalizers ...string) *corev1.Pod {
+	p.Finalizers = finalizers
+	return p
+}
+
+func pod(name, node string, annotations map[string]string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name), Annotations: annotations},
+		Spec:       corev1.PodSpec{NodeName: node},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
@@ -6,24 +6,37 @@ import (
 
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	clientset "k8s.io/client-go/kubernetes"
+	configv1 "k8s.io/kube-scheduler/config/v1"
 
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/experiment"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/util"
 )
 
 type SchedulerService interface {
+	GetSchedulerConfig() (*configv1.KubeSchedulerConfiguration, error)
+	RestartScheduler(cfg *configv1.KubeSchedulerConfiguration) error
 	ResetScheduler() error
 }
 
+type ResourceApplier interface {
+	DeleteAll(ctx context.Context, gvrs []schema.GroupVersionResource, opts resourceapplier.DeleteAllOptions) (map[schema.GroupVersionResource]int, error)
+}
+
 // Service cleans up resources stored in etcd.
 type Service struct {
 	// initialData has the all resource data that are fetched when reset service is initialized.
 	initialData map[string]string
 
-	etcdClient   *clientv3.Client
-	k8sClient    clientset.Interface
-	schedService SchedulerService
+	etcdClient      *clientv3.Client
+	k8sClient       clientset.Interface
+	resourceApplier ResourceApplier
+	schedService    SchedulerService
 }
 
 const EtcdPrefix = "/kube-scheduler-simulator"
@@ -33,13 +46,15 @@ const EtcdPrefix = "/kube-scheduler-simulator"
 func NewResetService(
 	etcdClient *clientv3.Client,
 	k8sClient clientset.Interface,
+	resourceApplier ResourceApplier,
 	schedService SchedulerService,
 ) (*Service, error) {
 	s := &Service{
-		initialData:  map[string]string{},
-		etcdClient:   etcdClient,
-		k8sClient:    k8sClient,
-		schedService: schedService,
+		initialData:     map[string]string{},
+		etcdClient:      etcdClient,
+		k8sClient:       k8sClient,
+		resourceApplier: resourceApplier,
+		schedService:    schedService,
 	}
 
 	result, err := etcdClient.Get(context.Background(), EtcdPrefix, clientv3.WithPrefix())
@@ -83,3 +98,84 @@ func (s *Service) Reset(ctx context.Context) error {
 	}
 	return nil
 }
+
+// KeepLabel is the label to keep the resource in Clean with CleanOptions.PreserveKept.
+const KeepLabel = "keep"
+
+// cleanedGVRs is the resources deleted by Clean.
+// resourceapplier deletes them in the reverse order of their dependencies.
+var cleanedGVRs = []schema.GroupVersionResource{
+	{Version: "v1", Resource: "pods"},
+	{Version: "v1", Resource: "persistentvolumeclaims"},
+	{Version: "v1", Resource: "persistentvolumes"},
+	{Version: "v1", Resource: "nodes"},
+	{Version: "v1", Resource: "namespaces"},
+}
+
+// systemNamespaces is the namespaces which Clean never deletes.
+// They're necessary for the cluster, and kube-apiserver creates them again anyway.
+var systemNamespaces = map[string]bool{
+	"default":         true,
+	"kube-system":     true,
+	"kube-public":     true,
+	"kube-node-lease": true,
+}
+
+// CleanOptions configures Clean.
+type CleanOptions struct {
+	// PreserveKept keeps the resources labeled with `keep=true`.
+	PreserveKept bool
+	// Experiment restricts the resources to the ones of the experiment with the ID.
+	// The namespaces of the experiment are kept, and the scheduler isn't restarted not to disturb the other experiments.
+	Experiment string
+}
+
+// CleanSummary is the result of Clean.
+type CleanSummary struct {
+	// Deleted is the number of the deleted resources of each resource, e.g., "pods".
+	Deleted map[string]int `json:"deleted"`
+}
+
+// Clean deletes all the Pods, PVCs, PVs, Nodes and Namespaces except the system ones,
+// and then restarts the scheduler with the current configuration so that its caches are clean.
+// Unlike Reset, the scheduler configuration is kept as it is.
+// With CleanOptions.Experiment, it deletes only the Pods, PVCs, PVs and Nodes of the experiment.
+func (s *Service) Clean(ctx context.Context, opts CleanOptions) (*CleanSummary, error) {
+	filter := func(_ context.Context, resource *unstructured.Unstructured, _ *resourceapplier.Clients) (bool, error) {
+		if resource.GetKind() == "Namespace" && systemNamespaces[resource.GetName()] {
+			return false, nil
+		}
+		if opts.PreserveKept && resource.GetLabels()[KeepLabel] == "true" {
+			return false, nil
+		}
+		return true, nil
+	}
+	gvrs, deleteOpts := cleanedGVRs, resourceapplier.DeleteAllOptions{Filter: filter}
+	if opts.Experiment != "" {
+		gvrs = experiment.ScopedGVRs
+		deleteOpts.LabelSelector = labels.SelectorFromSet(labels.Set{experiment.LabelKey: opts.Experiment}).String()
+	}
+	deleted, err := s.resourceApplier.DeleteAll(ctx, gvrs, deleteOpts)
+	if err != nil {
+		return nil, xerrors.Errorf("delete all resources: %w", err)
+	}
+	summary := &CleanSummary{Deleted: make(map[string]int, len(deleted))}
+	for gvr, n := range deleted {
+		summary.Deleted[gvr.Resource] = n
+	}
+	if opts.Experiment != "" {
+		return summary, nil
+	}
+
+	cfg, err := s.schedService.GetSchedulerConfig()
+	if errors.Is(err, scheduler.ErrServiceDisabled) {
+		return summary, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("get scheduler config: %w", err)
+	}
+	if err := s.schedService.RestartScheduler(cfg); err != nil && !errors.Is(err, scheduler.ErrServiceDisabled) {
+		return nil, xerrors.Errorf("restart scheduler: %w", err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,207 @@
+package reset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/restmapper"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/experiment"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
+)
+
+type fakeSchedulerService struct {
+	cfg        *configv1.KubeSchedulerConfiguration
+	getErr     error
+	restarted  []*configv1.KubeSchedulerConfiguration
+	restartErr error
+}
+
+func (s *fakeSchedulerService) GetSchedulerConfig() (*configv1.KubeSchedulerConfiguration, error) {
+	return s.cfg, s.getErr
+}
+
+func (s *fakeSchedulerService) RestartScheduler(cfg *configv1.KubeSchedulerConfiguration) error {
+	s.restarted = append(s.restarted, cfg)
+	return s.restartErr
+}
+
+func (s *fakeSchedulerService) ResetScheduler() error {
+	return nil
+}
+
+func TestService_Clean(t *testing.T) {
+	t.Parallel()
+	cfg := &configv1.KubeSchedulerConfiguration{}
+	tests := []struct {
+		name          string
+		opts          CleanOptions
+		schedService  *fakeSchedulerService
+		wantDeleted   map[string]int
+		wantRemaining map[string][]string
+		wantRestarted bool
+		wantErr       bool
+	}{
+		{
+			name:         "delete all the resources except the system namespaces, and restart the scheduler",
+			schedService: &fakeSchedulerService{cfg: cfg},
+			wantDeleted: map[string]int{
+				"pods":                   3,
+				"persistentvolumeclaims": 1,
+				"persistentvolumes":      1,
+				"nodes":                  3,
+				"namespaces":             2,
+			},
+			wantRemaining: map[string][]string{
+				"namespaces": {"default", "kube-system"},
+			},
+			wantRestarted: true,
+		},
+		{
+			name:         "keep the resources labeled with keep=true with PreserveKept",
+			opts:         CleanOptions{PreserveKept: true},
+			schedService: &fakeSchedulerService{cfg: cfg},
+			wantDeleted: map[string]int{
+				"pods":                   2,
+				"persistentvolumeclaims": 1,
+				"persistentvolumes":      1,
+				"nodes":                  2,
+				"namespaces":             2,
+			},
+			wantRemaining: map[string][]string{
+				"pods":       {"kept-pod"},
+				"nodes":      {"kept-node"},
+				"namespaces": {"default", "kube-system"},
+			},
+			wantRestarted: true,
+		},
+		{
+			name:         "delete only the resources of the experiment, and don't restart the scheduler",
+			opts:         CleanOptions{Experiment: "exp-1"},
+			schedService: &fakeSchedulerService{cfg: cfg},
+			wantDeleted: map[string]int{
+				"pods":                   1,
+				"persistentvolumeclaims": 0,
+				"persistentvolumes":      0,
+				"nodes":                  1,
+			},
+			wantRemaining: map[string][]string{
+				"pods":                   {"kept-pod", "pod1"},
+				"persistentvolumeclaims": {"pvc1"},
+				"persistentvolumes":      {"pv1"},
+				"nodes":                  {"kept-node", "node1"},
+				"namespaces":             {"default", "exp-ns", "kube-system", "ns1"},
+			},
+		},
+		{
+			name:         "don't restart the scheduler when the scheduler service is disabled",
+			schedService: &fakeSchedulerService{getErr: scheduler.ErrServiceDisabled},
+			wantDeleted: map[string]int{
+				"pods":                   3,
+				"persistentvolumeclaims": 1,
+				"persistentvolumes":      1,
+				"nodes":                  3,
+				"namespaces":             2,
+			},
+			wantRemaining: map[string][]string{
+				"namespaces": {"default", "kube-system"},
+			},
+		},
+		{
+			name:          "return an error when restarting the scheduler fails",
+			schedService:  &fakeSchedulerService{cfg: cfg, restartErr: xerrors.New("failed")},
+			wantRestarted: true,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, applier := prepareResourceApplier(t)
+			s := &Service{resourceApplier: applier, schedService: tt.schedService}
+
+			got, err := s.Clean(context.Background(), tt.opts)
+			if tt.wantRestarted {
+				assert.Equal(t, []*configv1.KubeSchedulerConfiguration{cfg}, tt.schedService.restarted)
+			} else {
+				assert.Empty(t, tt.schedService.restarted)
+			}
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDeleted, got.Deleted)
+			for _, gvr := range cleanedGVRs {
+				list, err := client.Resource(gvr).List(context.Background(), metav1.ListOptions{})
+				require.NoError(t, err)
+				var remaining []string
+				for _, r := range list.Items {
+					remaining = append(remaining, r.GetName())
+				}
+				assert.Equal(t, tt.wantRemaining[gvr.Resource], remaining, gvr.Resource)
+			}
+		})
+	}
+}
+
+// prepareResourceApplier returns the fake dynamic client with the resources to clean, and resourceapplier with it.
+func prepareResourceApplier(t *testing.T) (*dynamicFake.FakeDynamicClient, *resourceapplier.Service) {
+	t.Helper()
+	listKinds := map[schema.GroupVersionResource]string{}
+	var apiResources []metav1.APIResource
+	for _, r := range []metav1.APIResource{
+		{Name: "pods", Namespaced: true, Kind: "Pod"},
+		{Name: "persistentvolumeclaims", Namespaced: true, Kind: "PersistentVolumeClaim"},
+		{Name: "persistentvolumes", Kind: "PersistentVolume"},
+		{Name: "nodes", Kind: "Node"},
+		{Name: "namespaces", Kind: "Namespace"},
+	} {
+		listKinds[schema.GroupVersionResource{Version: "v1", Resource: r.Name}] = r.Kind + "List"
+		apiResources = append(apiResources, r)
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper([]*restmapper.APIGroupResources{
+		{
+			Group:              metav1.APIGroup{Versions: []metav1.GroupVersionForDiscovery{{Version: "v1"}}},
+			VersionedResources: map[string][]metav1.APIResource{"v1": apiResources},
+		},
+	})
+
+	objs := []runtime.Object{
+		object("Namespace", "", "default", nil),
+		object("Namespace", "", "kube-system", nil),
+		object("Namespace", "", "ns1", nil),
+		object("Node", "", "node1", nil),
+		object("Node", "", "kept-node", map[string]string{KeepLabel: "true"}),
+		object("PersistentVolume", "", "pv1", nil),
+		object("PersistentVolumeClaim", "ns1", "pvc1", nil),
+		object("Pod", "ns1", "pod1", nil),
+		object("Pod", "default", "kept-pod", map[string]string{KeepLabel: "true"}),
+		object("Namespace", "", "exp-ns", map[string]string{experiment.LabelKey: "exp-1"}),
+		object("Node", "", "exp-node", map[string]string{experiment.LabelKey: "exp-1"}),
+		object("Pod", "exp-ns", "exp-pod", map[string]string{experiment.LabelKey: "exp-1"}),
+	}
+	client := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objs...)
+	return client, resourceapplier.New(client, mapper, resourceapplier.Options{})
+}
+
+func object(kind, namespace, name string, labels map[string]string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetLabels(labels)
+	return u
+}
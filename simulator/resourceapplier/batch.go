@@ -0,0 +1,136 @@
+package resourceapplier
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DefaultWorkers is the default number of resources ApplyAll applies concurrently.
+const DefaultWorkers = 16
+
+// dependencyRanks decides the order in which ApplyAll applies resources.
+// Resources with a lower rank are applied before the ones with a higher rank,
+// e.g., PersistentVolumeClaims are applied before Pods referencing them.
+// Kinds not listed here are applied right before Pods.
+var dependencyRanks = map[schema.GroupKind]int{
+	{Group: "", Kind: "Namespace"}:                      0,
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}: 1,
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:     2,
+	{Group: "", Kind: "PersistentVolumeClaim"}:          3,
+	{Group: "", Kind: "Node"}:                           4,
+	{Group: "", Kind: "PersistentVolume"}:               5,
+	{Group: "", Kind: "Pod"}:                            7,
+}
+
+// unknownDependencyRank is the rank of the kinds not in dependencyRanks.
+const unknownDependencyRank = 6
+
+// ApplyFunc applies a single resource in ApplyAll.
+type ApplyFunc func(ctx context.Context, resource *unstructured.Unstructured) error
+
+// ApplyAllOptions configures ApplyAll.
+type ApplyAllOptions struct {
+	// Workers is the maximum number of resources applied concurrently.
+	// If zero, DefaultWorkers is used.
+	Workers int
+	// StopOnError makes ApplyAll stop applying the remaining resources on the first failure.
+	StopOnError bool
+	// Apply is used to apply each resource.
+	// If nil, Create is used.
+	Apply ApplyFunc
+}
+
+// Result is the result of applying a resource in ApplyAll.
+type Result struct {
+	Resource *unstructured.Unstructured
+	// Err is the error from applying the resource, nil if it's applied successfully.
+	Err error
+}
+
+// ApplyAll applies the resources in the order of their dependencies.
+// The resources with the same rank are applied concurrently by up to opts.Workers workers,
+// and the next rank is started after all of them are done.
+// It returns the results of the resources it tried to apply, in the order they are applied within each rank.
+// When opts.StopOnError is true, it stops on the first failure and returns the error as well.
+func (s *Service) ApplyAll(ctx context.Context, resources []unstructured.Unstructured, opts ApplyAllOptions) ([]Result, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	apply := opts.Apply
+	if apply == nil {
+		apply = s.Create
+	}
+
+	results := make([]Result, 0, len(resources))
+	for _, wave := range rankResources(resources) {
+		waveResults, err := applyWave(ctx, wave, workers, opts.StopOnError, apply)
+		results = append(results, waveResults...)
+		if err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// applyWave applies the resources concurrently with the given number of workers.
+func applyWave(ctx context.Context, resources []*unstructured.Unstructured, workers int, stopOnError bool, apply ApplyFunc) ([]Result, error) {
+	var mu sync.Mutex
+	results := make([]Result, 0, len(resources))
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(workers)
+	for _, r := range resources {
+		if stopOnError && egCtx.Err() != nil {
+			break
+		}
+		eg.Go(func() error {
+			err := apply(egCtx, r)
+			mu.Lock()
+			results = append(results, Result{Resource: r, Err: err})
+			mu.Unlock()
+			if stopOnError {
+				return err
+			}
+			return nil
+		})
+	}
+	err := eg.Wait()
+
+	return results, err
+}
+
+// rankResources groups the resources by their dependency ranks, in ascending order of the ranks.
+// The order of the resources within the same rank is kept.
+func rankResources(resources []unstructured.Unstructured) [][]*unstructured.Unstructured {
+	waves := map[int][]*unstructured.Unstructured{}
+	for i := range resources {
+		rank := dependencyRank(resources[i].GroupVersionKind().GroupKind())
+		waves[rank] = append(waves[rank], &resources[i])
+	}
+
+	ranks := make([]int, 0, len(waves))
+	for rank := range waves {
+		ranks = append(ranks, rank)
+	}
+	sort.Ints(ranks)
+
+	ranked := make([][]*unstructured.Unstructured, 0, len(ranks))
+	for _, rank := range ranks {
+		ranked = append(ranked, waves[rank])
+	}
+	return ranked
+}
+
+func dependencyRank(gk schema.GroupKind) int {
+	if rank, ok := dependencyRanks[gk]; ok {
+		return rank
+	}
+	return unknownDependencyRank
+}
@@ -0,0 +1,172 @@
+package resourceapplier
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestService_ApplyAll(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		resources   []unstructured.Unstructured
+		stopOnError bool
+		failOn      string
+		wantApplied []string
+		wantFailed  []string
+		wantErr     bool
+	}{
+		{
+			name: "apply resources in the order of their dependencies",
+			resources: []unstructured.Unstructured{
+				resourceWithKindAndName("", "Pod", "pod1"),
+				resourceWithKindAndName("", "Node", "node1"),
+				resourceWithKindAndName("", "Namespace", "ns1"),
+				resourceWithKindAndName("", "Pod", "pod2"),
+				resourceWithKindAndName("apps", "Deployment", "deploy1"),
+				resourceWithKindAndName("", "PersistentVolumeClaim", "pvc1"),
+				resourceWithKindAndName("storage.k8s.io", "StorageClass", "sc1"),
+			},
+			wantApplied: []string{"ns1", "sc1", "pvc1", "node1", "deploy1", "pod1", "pod2"},
+		},
+		{
+			name: "continue applying resources after a failure",
+			resources: []unstructured.Unstructured{
+				resourceWithKindAndName("", "Pod", "pod1"),
+				resourceWithKindAndName("", "Pod", "pod2"),
+				resourceWithKindAndName("", "Pod", "pod3"),
+			},
+			failOn:      "pod2",
+			wantApplied: []string{"pod1", "pod2", "pod3"},
+			wantFailed:  []string{"pod2"},
+		},
+		{
+			name: "stop applying resources on a failure with StopOnError",
+			resources: []unstructured.Unstructured{
+				resourceWithKindAndName("", "Node", "node1"),
+				resourceWithKindAndName("", "Pod", "pod1"),
+				resourceWithKindAndName("", "Pod", "pod2"),
+			},
+			stopOnError: true,
+			failOn:      "node1",
+			wantApplied: []string{"node1"},
+			wantFailed:  []string{"node1"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, mapper := prepare()
+			s := New(client, mapper, Options{})
+
+			var mu sync.Mutex
+			applied := []string{}
+			results, err := s.ApplyAll(context.Background(), tt.resources, ApplyAllOptions{
+				// Apply one by one to make the order deterministic.
+				Workers:     1,
+				StopOnError: tt.stopOnError,
+				Apply: func(_ context.Context, resource *unstructured.Unstructured) error {
+					mu.Lock()
+					defer mu.Unlock()
+					applied = append(applied, resource.GetName())
+					if resource.GetName() == tt.failOn {
+						return xerrors.New("injected failure")
+					}
+					return nil
+				},
+			})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantApplied, applied)
+			assert.Len(t, results, len(tt.wantApplied))
+			failed := []string{}
+			for _, r := range results {
+				if r.Err != nil {
+					failed = append(failed, r.Resource.GetName())
+				}
+			}
+			assert.ElementsMatch(t, tt.wantFailed, failed)
+		})
+	}
+}
+
+func TestService_ApplyAll_BoundedWorkers(t *testing.T) {
+	t.Parallel()
+
+	client, mapper := prepare()
+	s := New(client, mapper, Options{})
+
+	resources := make([]unstructured.Unstructured, 0, 20)
+	for i := 0; i < 20; i++ {
+		resources = append(resources, resourceWithKindAndName("", "Pod", "pod"))
+	}
+
+	var inFlight, maxInFlight int32
+	results, err := s.ApplyAll(context.Background(), resources, ApplyAllOptions{
+		Workers: 3,
+		Apply: func(_ context.Context, _ *unstructured.Unstructured) error {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				m := atomic.LoadInt32(&maxInFlight)
+				if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 20)
+	assert.LessOrEqual(t, maxInFlight, int32(3))
+}
+
+func TestService_ApplyAll_DefaultApply(t *testing.T) {
+	t.Parallel()
+
+	client, mapper := prepare()
+	s := New(client, mapper, Options{})
+
+	resources := []unstructured.Unstructured{
+		resourceWithKindAndName("", "Pod", "pod1"),
+		resourceWithKindAndName("", "Node", "node1"),
+	}
+
+	results, err := s.ApplyAll(context.Background(), resources, ApplyAllOptions{})
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	_, err = getResource(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, "pod1", "default", mapper, client)
+	assert.NoError(t, err)
+	_, err = getResource(schema.GroupVersionKind{Version: "v1", Kind: "Node"}, "node1", "", mapper, client)
+	assert.NoError(t, err)
+}
+
+func resourceWithKindAndName(group, kind, name string) unstructured.Unstructured {
+	r := unstructured.Unstructured{}
+	r.SetGroupVersionKind(schema.GroupVersionKind{Group: group, Version: "v1", Kind: kind})
+	r.SetName(name)
+	if kind == "Pod" {
+		r.SetNamespace("default")
+	}
+	return r
+}
@@ -0,0 +1,84 @@
+package resourceapplier
+
+import (
+	"context"
+	"sort"
+
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DeleteAllOptions configures DeleteAll.
+type DeleteAllOptions struct {
+	// Filter decides which resources are deleted.
+	// If it returns false, the resource is kept. If nil, all the resources are deleted.
+	Filter FilteringFunction
+	// LabelSelector restricts the resources to the ones with the matching labels. All the resources if empty.
+	// Unlike Filter, it's passed to kube-apiserver so that the other resources aren't listed.
+	LabelSelector string
+}
+
+// DeleteAll deletes all the resources of the given GVRs from the destination cluster.
+// The GVRs are processed in the reverse order of their dependencies,
+// e.g., Pods are deleted before the PersistentVolumeClaims, Nodes and Namespaces.
+// It returns the number of the deleted resources of each GVR.
+func (s *Service) DeleteAll(ctx context.Context, gvrs []schema.GroupVersionResource, opts DeleteAllOptions) (map[schema.GroupVersionResource]int, error) {
+	ranks := make(map[schema.GroupVersionResource]int, len(gvrs))
+	for _, gvr := range gvrs {
+		gvk, err := s.clients.RestMapper.KindFor(gvr)
+		if err != nil {
+			return nil, xerrors.Errorf("get the kind of %s: %w", gvr, err)
+		}
+		ranks[gvr] = dependencyRank(gvk.GroupKind())
+	}
+	ordered := append([]schema.GroupVersionResource{}, gvrs...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ranks[ordered[i]] > ranks[ordered[j]]
+	})
+
+	deleted := make(map[schema.GroupVersionResource]int, len(gvrs))
+	for _, gvr := range ordered {
+		n, err := s.deleteAllOf(ctx, gvr, opts)
+		deleted[gvr] = n
+		if err != nil {
+			return deleted, err
+		}
+	}
+
+	return deleted, nil
+}
+
+// deleteAllOf deletes all the resources of the gvr selected by opts, and returns the number of the deleted ones.
+func (s *Service) deleteAllOf(ctx context.Context, gvr schema.GroupVersionResource, opts DeleteAllOptions) (int, error) {
+	list, err := s.clients.DynamicClient.Resource(gvr).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: opts.LabelSelector})
+	if err != nil {
+		return 0, xerrors.Errorf("failed to list %s: %w", gvr, err)
+	}
+
+	deleted := 0
+	for i := range list.Items {
+		resource := &list.Items[i]
+		if opts.Filter != nil {
+			ok, err := opts.Filter(ctx, resource, s.clients)
+			if err != nil {
+				return deleted, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		err := s.clients.DynamicClient.Resource(gvr).Namespace(resource.GetNamespace()).Delete(ctx, resource.GetName(), metav1.DeleteOptions{})
+		if errors.IsNotFound(err) {
+			// It's already deleted, e.g., by someone else.
+			continue
+		}
+		if err != nil {
+			return deleted, xerrors.Errorf("failed to delete %s %s/%s: %w", gvr, resource.GetNamespace(), resource.GetName(), err)
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,105 @@
+package resourceapplier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestService_DeleteAll(t *testing.T) {
+	t.Parallel()
+
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	nodesGVR := schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+	keepFilter := func(_ context.Context, resource *unstructured.Unstructured, _ *Clients) (bool, error) {
+		return resource.GetLabels()["keep"] != "true", nil
+	}
+
+	tests := []struct {
+		name          string
+		filter        FilteringFunction
+		labelSelector string
+		wantDeleted   map[schema.GroupVersionResource]int
+		wantRemaining []string
+	}{
+		{
+			name:        "delete all the resources",
+			wantDeleted: map[schema.GroupVersionResource]int{podsGVR: 3, nodesGVR: 1},
+		},
+		{
+			name:          "keep the resources filtered out",
+			filter:        keepFilter,
+			wantDeleted:   map[schema.GroupVersionResource]int{podsGVR: 2, nodesGVR: 1},
+			wantRemaining: []string{"pod3"},
+		},
+		{
+			name:          "delete only the resources matching the label selector",
+			labelSelector: "keep=true",
+			wantDeleted:   map[schema.GroupVersionResource]int{podsGVR: 1, nodesGVR: 0},
+			wantRemaining: []string{"pod1", "pod2"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, mapper := prepare()
+			client := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+				podsGVR:  "PodList",
+				nodesGVR: "NodeList",
+			})
+			service := New(client, mapper, Options{})
+			ctx := context.Background()
+			for _, r := range []unstructured.Unstructured{
+				resourceWithKindAndName("", "Node", "node1"),
+				podWithLabels("default", "pod1", nil),
+				podWithLabels("ns1", "pod2", nil),
+				podWithLabels("default", "pod3", map[string]string{"keep": "true"}),
+			} {
+				r := r
+				require.NoError(t, service.Create(ctx, &r))
+			}
+			var deletedResources []string
+			client.PrependReactor("delete", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				deletedResources = append(deletedResources, action.GetResource().Resource)
+				return false, nil, nil
+			})
+
+			deleted, err := service.DeleteAll(ctx, []schema.GroupVersionResource{nodesGVR, podsGVR}, DeleteAllOptions{Filter: tt.filter, LabelSelector: tt.labelSelector})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDeleted, deleted)
+			if tt.wantDeleted[nodesGVR] > 0 {
+				// Pods are deleted before the Nodes which they're bound to.
+				assert.Equal(t, "nodes", deletedResources[len(deletedResources)-1])
+			}
+
+			pods, err := client.Resource(podsGVR).List(ctx, metav1.ListOptions{})
+			require.NoError(t, err)
+			var remaining []string
+			for _, p := range pods.Items {
+				remaining = append(remaining, p.GetName())
+			}
+			assert.Equal(t, tt.wantRemaining, remaining)
+			nodes, err := client.Resource(nodesGVR).List(ctx, metav1.ListOptions{})
+			require.NoError(t, err)
+			assert.Len(t, nodes.Items, 1-tt.wantDeleted[nodesGVR])
+		})
+	}
+}
+
+func podWithLabels(namespace, name string, labels map[string]string) unstructured.Unstructured {
+	r := resourceWithKindAndName("", "Pod", name)
+	r.SetNamespace(namespace)
+	r.SetLabels(labels)
+	return r
+}
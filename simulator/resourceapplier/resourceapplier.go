@@ -99,9 +99,40 @@ func (s *Service) Create(ctx context.Context, resource *unstructured.Unstructure
 	// Namespaces resources should be created within the namespace defined in the Unstructured object
 	namespace := resource.GetNamespace()
 
+	resource, ok, err := s.prepareForCreating(ctx, gvr, resource)
+	if !ok || err != nil {
+		return err
+	}
+
+	// Create the resource on the destination cluster using the dynamic client
+	_, err = s.clients.DynamicClient.Resource(gvr).Namespace(namespace).Create(
+		ctx,
+		resource,
+		metav1.CreateOptions{},
+	)
+	if err != nil {
+		return xerrors.Errorf("failed to create resource: %w", err)
+	}
+
+	return nil
+}
+
+// PrepareForCreating runs the filtering and mutating functions for creating on the resource,
+// and returns the resource which Create would create in the destination cluster.
+// It returns false if the resource is filtered out.
+func (s *Service) PrepareForCreating(ctx context.Context, resource *unstructured.Unstructured) (*unstructured.Unstructured, bool, error) {
+	gvr, err := s.findGVRForGVK(resource.GroupVersionKind())
+	if err != nil {
+		return nil, false, err
+	}
+
+	return s.prepareForCreating(ctx, gvr, resource)
+}
+
+func (s *Service) prepareForCreating(ctx context.Context, gvr schema.GroupVersionResource, resource *unstructured.Unstructured) (*unstructured.Unstructured, bool, error) {
 	// Run the filtering function for the resource.
 	if ok, err := s.filterResourceForCreating(ctx, gvr, resource, s.clients); !ok || err != nil {
-		return err
+		return nil, false, err
 	}
 
 	// When creating a resource on the destination cluster, we must remove the metadata such as UID and Generation.
@@ -109,22 +140,27 @@ func (s *Service) Create(ctx context.Context, resource *unstructured.Unstructure
 	resource = removeUnnecessaryMetadata(resource)
 
 	// Run the mutating function for the resource.
-	resource, err = s.mutateResourceForCreating(ctx, gvr, resource, s.clients)
+	resource, err := s.mutateResourceForCreating(ctx, gvr, resource, s.clients)
 	if err != nil {
-		return xerrors.Errorf("failed to mutate resource: %w", err)
+		return nil, false, xerrors.Errorf("failed to mutate resource: %w", err)
 	}
 
-	// Create the resource on the destination cluster using the dynamic client
-	_, err = s.clients.DynamicClient.Resource(gvr).Namespace(namespace).Create(
-		ctx,
-		resource,
-		metav1.CreateOptions{},
-	)
+	return resource, true, nil
+}
+
+// Get gets the resource with the same name as the given one from the destination cluster.
+func (s *Service) Get(ctx context.Context, resource *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	gvr, err := s.findGVRForGVK(resource.GroupVersionKind())
 	if err != nil {
-		return xerrors.Errorf("failed to create resource: %w", err)
+		return nil, err
 	}
 
-	return nil
+	return s.clients.DynamicClient.Resource(gvr).Namespace(resource.GetNamespace()).Get(ctx, resource.GetName(), metav1.GetOptions{})
+}
+
+// Clients returns the clients for the destination cluster.
+func (s *Service) Clients() *Clients {
+	return s.clients
 }
 
 func (s *Service) Update(ctx context.Context, resource *unstructured.Unstructured) error {
@@ -166,6 +202,41 @@ func (s *Service) Update(ctx context.Context, resource *unstructured.Unstructure
 	return nil
 }
 
+// UpdateStatus updates the status of the resource in the destination cluster with the status of the given resource.
+// The resource must already exist in the destination cluster.
+// It's needed because the status is dropped when the resource is created.
+func (s *Service) UpdateStatus(ctx context.Context, resource *unstructured.Unstructured) error {
+	status, found, err := unstructured.NestedFieldCopy(resource.Object, "status")
+	if err != nil {
+		return xerrors.Errorf("failed to get status: %w", err)
+	}
+	if !found {
+		return nil
+	}
+
+	// Extract the GroupVersionResource from the Unstructured object
+	gvk := resource.GroupVersionKind()
+	gvr, err := s.findGVRForGVK(gvk)
+	if err != nil {
+		return err
+	}
+
+	client := s.clients.DynamicClient.Resource(gvr).Namespace(resource.GetNamespace())
+	current, err := client.Get(ctx, resource.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return xerrors.Errorf("failed to get resource: %w", err)
+	}
+	if err := unstructured.SetNestedField(current.Object, status, "status"); err != nil {
+		return xerrors.Errorf("failed to set status: %w", err)
+	}
+
+	if _, err := client.UpdateStatus(ctx, current, metav1.UpdateOptions{}); err != nil {
+		return xerrors.Errorf("failed to update status: %w", err)
+	}
+
+	return nil
+}
+
 func (s *Service) Delete(
 	ctx context.Context,
 	resource *unstructured.Unstructured,
@@ -0,0 +1,26 @@
+// Package resourcefilter provides filters shared by the importer and the syncer
+// to decide which resources from a target cluster are applied to the simulator.
+package resourcefilter
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var podGK = schema.GroupKind{Group: "", Kind: "Pod"}
+
+// IsCompletedOrTerminatingPod checks whether the resource is a pod which has completed (Succeeded or Failed)
+// or is being deleted.
+// Such pods are never scheduled, and creating a pod with deletionTimestamp is rejected.
+func IsCompletedOrTerminatingPod(resource *unstructured.Unstructured) bool {
+	if resource.GroupVersionKind().GroupKind() != podGK {
+		return false
+	}
+	if resource.GetDeletionTimestamp() != nil {
+		return true
+	}
+
+	phase, _, _ := unstructured.NestedString(resource.Object, "status", "phase")
+	return phase == string(v1.PodSucceeded) || phase == string(v1.PodFailed)
+}
@@ -0,0 +1,49 @@
+package resourcefilter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestIsCompletedOrTerminatingPod(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		kind        string
+		phase       string
+		terminating bool
+		want        bool
+	}{
+		{name: "pending pod", kind: "Pod", phase: "Pending", want: false},
+		{name: "running pod", kind: "Pod", phase: "Running", want: false},
+		{name: "pod without phase", kind: "Pod", want: false},
+		{name: "succeeded pod", kind: "Pod", phase: "Succeeded", want: true},
+		{name: "failed pod", kind: "Pod", phase: "Failed", want: true},
+		{name: "terminating pod", kind: "Pod", phase: "Running", terminating: true, want: true},
+		{name: "terminating node", kind: "Node", terminating: true, want: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": tt.kind}}
+			r.SetName("resource")
+			if tt.phase != "" {
+				assert.NoError(t, unstructured.SetNestedField(r.Object, tt.phase, "status", "phase"))
+			}
+			if tt.terminating {
+				now := metav1.NewTime(time.Now())
+				r.SetDeletionTimestamp(&now)
+			}
+
+			assert.Equal(t, tt.want, IsCompletedOrTerminatingPod(r))
+		})
+	}
+}
@@ -0,0 +1,255 @@
+// Package resourcelist lists the resources in the simulator page by page,
+// pruning the objects to the requested fields so that the big clusters don't overwhelm the clients.
+package resourcelist
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// MaxLimit is the maximum of ListOptions.Limit.
+const MaxLimit = 5000
+
+// ErrInvalidListOptions is returned when the given ListOptions is invalid, e.g., the continue token is malformed.
+var ErrInvalidListOptions = errors.New("invalid list options")
+
+// ListOptions is the options to list the resources.
+type ListOptions struct {
+	// Resource is the resource to list, e.g., "pods".
+	// Group and Version are optional, and needed only when the resource name is ambiguous.
+	Group    string
+	Version  string
+	Resource string
+	// Namespace restricts the namespaced resources to the ones in it if non-empty.
+	Namespace     string
+	LabelSelector string
+	// Limit is the maximum number of the items returned. All the items are returned if zero.
+	Limit int64
+	// Continue is ListResult.Continue of the previous page to get the next page.
+	Continue string
+	// Fields are the dot-separated JSON paths of the fields to keep in the items, e.g., "metadata.name".
+	// The whole objects are returned if empty.
+	Fields []string
+}
+
+// ListResult is a page of the resources.
+type ListResult struct {
+	Items []map[string]interface{} `json:"items"`
+	// Continue is set when there are more items. Pass it as ListOptions.Continue to get them.
+	Continue        string `json:"continue,omitempty"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	// RemainingItemCount is the number of the items after this page, if kube-apiserver knows it.
+	RemainingItemCount *int64 `json:"remainingItemCount,omitempty"`
+}
+
+// Service lists the resources in the simulator.
+type Service struct {
+	client dynamic.Interface
+	mapper meta.RESTMapper
+}
+
+// NewService initializes Service.
+func NewService(client dynamic.Interface, mapper meta.RESTMapper) *Service {
+	return &Service{client: client, mapper: mapper}
+}
+
+// continueToken is the state of the pagination, encoded in ListResult.Continue.
+// It has the parameters of the first page to reject the token used for another list.
+type continueToken struct {
+	Resource      string `json:"r"`
+	Namespace     string `json:"ns,omitempty"`
+	LabelSelector string `json:"ls,omitempty"`
+	// Continue is the continue token of kube-apiserver.
+	Continue string `json:"c"`
+}
+
+// List lists the resources with opts, passing the pagination through to kube-apiserver.
+func (s *Service) List(ctx context.Context, opts ListOptions) (*ListResult, error) {
+	if opts.Limit < 0 || opts.Limit > MaxLimit {
+		return nil, xerrors.Errorf("limit %d out of range (0-%d): %w", opts.Limit, MaxLimit, ErrInvalidListOptions)
+	}
+	if _, err := labels.Parse(opts.LabelSelector); err != nil {
+		return nil, xerrors.Errorf("parse label selector: %v: %w", err, ErrInvalidListOptions)
+	}
+	fields, err := parseFields(opts.Fields)
+	if err != nil {
+		return nil, err
+	}
+	gvr, namespaced, err := s.resolve(schema.GroupVersionResource{Group: opts.Group, Version: opts.Version, Resource: opts.Resource})
+	if err != nil {
+		return nil, err
+	}
+	namespace := opts.Namespace
+	if !namespaced {
+		namespace = ""
+	}
+	token := continueToken{Resource: gvr.String(), Namespace: namespace, LabelSelector: opts.LabelSelector}
+	if opts.Continue != "" {
+		got, err := decodeContinue(opts.Continue)
+		if err != nil {
+			return nil, err
+		}
+		if got.Resource != token.Resource || got.Namespace != token.Namespace || got.LabelSelector != token.LabelSelector {
+			return nil, xerrors.Errorf("the continue token is for another list: %w", ErrInvalidListOptions)
+		}
+		token.Continue = got.Continue
+	}
+
+	list, err := s.client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: opts.LabelSelector,
+		Limit:         opts.Limit,
+		Continue:      token.Continue,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("list %s: %w", gvr, err)
+	}
+
+	result := &ListResult{
+		Items:              make([]map[string]interface{}, 0, len(list.Items)),
+		ResourceVersion:    list.GetResourceVersion(),
+		RemainingItemCount: list.GetRemainingItemCount(),
+	}
+	for _, item := range list.Items {
+		result.Items = append(result.Items, prune(item.Object, fields))
+	}
+	if c := list.GetContinue(); c != "" {
+		token.Continue = c
+		result.Continue, err = encodeContinue(token)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// resolve finds the resource and whether it's namespaced.
+func (s *Service) resolve(partial schema.GroupVersionResource) (schema.GroupVersionResource, bool, error) {
+	if partial.Resource == "" {
+		return schema.GroupVersionResource{}, false, xerrors.Errorf("resource is required: %w", ErrInvalidListOptions)
+	}
+	gvr, err := s.mapper.ResourceFor(partial)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, xerrors.Errorf("unknown resource %s: %v: %w", partial, err, ErrInvalidListOptions)
+	}
+	gvk, err := s.mapper.KindFor(gvr)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, xerrors.Errorf("get kind of %s: %w", gvr, err)
+	}
+	mapping, err := s.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, xerrors.Errorf("get REST mapping of %s: %w", gvk, err)
+	}
+	return gvr, mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+func encodeContinue(token continueToken) (string, error) {
+	b, err := json.Marshal(token)
+	if err != nil {
+		return "", xerrors.Errorf("encode continue token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeContinue(s string) (*continueToken, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, xerrors.Errorf("malformed continue token: %w", ErrInvalidListOptions)
+	}
+	token := &continueToken{}
+	if err := json.Unmarshal(b, token); err != nil || token.Resource == "" || token.Continue == "" {
+		return nil, xerrors.Errorf("malformed continue token: %w", ErrInvalidListOptions)
+	}
+	return token, nil
+}
+
+// fieldTree is the fields to keep, by the field name. A leaf, an empty tree, means keeping the whole value.
+type fieldTree map[string]fieldTree
+
+// parseFields parses the dot-separated paths into fieldTree. It returns nil if no path is given.
+func parseFields(paths []string) (fieldTree, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	root := fieldTree{}
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			return nil, xerrors.Errorf("empty field: %w", ErrInvalidListOptions)
+		}
+		node := root
+		names := strings.Split(path, ".")
+		for i, name := range names {
+			if name == "" {
+				return nil, xerrors.Errorf("malformed field %q: %w", path, ErrInvalidListOptions)
+			}
+			child, ok := node[name]
+			if ok && len(child) == 0 {
+				// the whole value is already kept by a shorter path.
+				break
+			}
+			if i == len(names)-1 {
+				// keep the whole value even if the longer paths are given.
+				node[name] = fieldTree{}
+				break
+			}
+			if !ok {
+				child = fieldTree{}
+				node[name] = child
+			}
+			node = child
+		}
+	}
+	return root, nil
+}
+
+// prune returns the copy of obj with only the fields in tree. The whole obj is returned if tree is nil.
+// The paths through the lists are applied to each element, e.g., "spec.containers.name" keeps the names of all the containers.
+func prune(obj map[string]interface{}, tree fieldTree) map[string]interface{} {
+	if tree == nil {
+		return obj
+	}
+	ret := map[string]interface{}{}
+	for name, subtree := range tree {
+		v, ok := obj[name]
+		if !ok {
+			continue
+		}
+		if pruned, ok := pruneValue(v, subtree); ok {
+			ret[name] = pruned
+		}
+	}
+	return ret
+}
+
+// pruneValue prunes v with tree. It returns false if v doesn't have any of the fields.
+func pruneValue(v interface{}, tree fieldTree) (interface{}, bool) {
+	if len(tree) == 0 {
+		return v, true
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		pruned := prune(v, tree)
+		return pruned, len(pruned) > 0
+	case []interface{}:
+		ret := make([]interface{}, 0, len(v))
+		for _, e := range v {
+			if pruned, ok := pruneValue(e, tree); ok {
+				ret = append(ret, pruned)
+			}
+		}
+		return ret, len(ret) > 0
+	default:
+		// a scalar doesn't have the fields.
+		return nil, false
+	}
+}
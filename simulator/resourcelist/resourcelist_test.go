@@ -0,0 +1,254 @@
+package resourcelist
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/restmapper"
+)
+
+var podsGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+func TestService_List_pagination(t *testing.T) {
+	t.Parallel()
+	s := NewService(newPaginatingClient(t, 7), mapper())
+
+	got := []string{}
+	opts := ListOptions{Resource: "pods", Namespace: "default", Limit: 3, Fields: []string{"metadata.name"}}
+	pages := 0
+	for {
+		page, err := s.List(context.Background(), opts)
+		require.NoError(t, err)
+		pages++
+		if page.Continue != "" {
+			require.NotNil(t, page.RemainingItemCount)
+			assert.Equal(t, int64(7-3*pages), *page.RemainingItemCount)
+		}
+		for _, item := range page.Items {
+			got = append(got, item["metadata"].(map[string]interface{})["name"].(string))
+		}
+		if page.Continue == "" {
+			break
+		}
+		opts.Continue = page.Continue
+	}
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{"pod-0", "pod-1", "pod-2", "pod-3", "pod-4", "pod-5", "pod-6"}, got)
+}
+
+func TestService_List_continueToken(t *testing.T) {
+	t.Parallel()
+	s := NewService(newPaginatingClient(t, 7), mapper())
+	first, err := s.List(context.Background(), ListOptions{Resource: "pods", Namespace: "default", Limit: 3})
+	require.NoError(t, err)
+	require.NotEmpty(t, first.Continue)
+	// The token is opaque, not the token of kube-apiserver as it is.
+	assert.NotEqual(t, "3", first.Continue)
+
+	tests := []struct {
+		name string
+		opts ListOptions
+	}{
+		{
+			name: "malformed token",
+			opts: ListOptions{Resource: "pods", Namespace: "default", Limit: 3, Continue: "3"},
+		},
+		{
+			name: "token for another namespace",
+			opts: ListOptions{Resource: "pods", Namespace: "kube-system", Limit: 3, Continue: first.Continue},
+		},
+		{
+			name: "token for another resource",
+			opts: ListOptions{Resource: "nodes", Limit: 3, Continue: first.Continue},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := s.List(context.Background(), tt.opts)
+			assert.ErrorIs(t, err, ErrInvalidListOptions)
+		})
+	}
+}
+
+func TestService_List_invalidOptions(t *testing.T) {
+	t.Parallel()
+	s := NewService(newPaginatingClient(t, 1), mapper())
+	for name, opts := range map[string]ListOptions{
+		"unknown resource": {Resource: "widgets"},
+		"negative limit":   {Resource: "pods", Limit: -1},
+		"too large limit":  {Resource: "pods", Limit: MaxLimit + 1},
+		"malformed field":  {Resource: "pods", Fields: []string{"metadata..name"}},
+		"malformed label":  {Resource: "pods", LabelSelector: "a in"},
+	} {
+		_, err := s.List(context.Background(), opts)
+		assert.ErrorIs(t, err, ErrInvalidListOptions, name)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	t.Parallel()
+	pod := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":      "pod-0",
+			"namespace": "default",
+			"labels":    map[string]interface{}{"app": "web"},
+		},
+		"spec": map[string]interface{}{
+			"nodeName": "node-1",
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "nginx", "resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "1"}}},
+				map[string]interface{}{"name": "sidecar", "image": "envoy"},
+			},
+		},
+		"status": map[string]interface{}{"phase": "Running"},
+	}
+	tests := []struct {
+		name   string
+		fields []string
+		want   map[string]interface{}
+	}{
+		{
+			name:   "nested paths",
+			fields: []string{"metadata.name", "spec.nodeName", "status.phase"},
+			want: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "pod-0"},
+				"spec":     map[string]interface{}{"nodeName": "node-1"},
+				"status":   map[string]interface{}{"phase": "Running"},
+			},
+		},
+		{
+			name:   "paths through the list",
+			fields: []string{"spec.containers.name", "spec.containers.resources.requests.cpu"},
+			want: map[string]interface{}{
+				"spec": map[string]interface{}{"containers": []interface{}{
+					map[string]interface{}{"name": "app", "resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "1"}}},
+					map[string]interface{}{"name": "sidecar"},
+				}},
+			},
+		},
+		{
+			name:   "the shorter path keeps the whole value",
+			fields: []string{"metadata.labels.app", "metadata", "kind"},
+			want: map[string]interface{}{
+				"kind":     "Pod",
+				"metadata": pod["metadata"],
+			},
+		},
+		{
+			name:   "missing fields",
+			fields: []string{"metadata.uid", "spec.nodeName.foo"},
+			want:   map[string]interface{}{},
+		},
+		{
+			name: "no fields",
+			want: pod,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tree, err := parseFields(tt.fields)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, prune(pod, tree))
+		})
+	}
+}
+
+// paginatingClient is the fake client having the pods in the default namespace,
+// which paginates the list as kube-apiserver does. The continue token of kube-apiserver is the index of the next pod.
+// The fake client of client-go can't be used as it is because it drops limit and continue.
+type paginatingClient struct {
+	dynamic.Interface
+	t    *testing.T
+	pods []unstructured.Unstructured
+}
+
+func newPaginatingClient(t *testing.T, n int) *paginatingClient {
+	t.Helper()
+	client := dynamicFake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podsGVR:                            "PodList",
+		{Version: "v1", Resource: "nodes"}: "NodeList",
+	})
+	pods := make([]unstructured.Unstructured, 0, n)
+	for i := 0; i < n; i++ {
+		pod := unstructured.Unstructured{}
+		pod.SetAPIVersion("v1")
+		pod.SetKind("Pod")
+		pod.SetNamespace("default")
+		pod.SetName(fmt.Sprintf("pod-%d", i))
+		pods = append(pods, pod)
+	}
+	return &paginatingClient{Interface: client, t: t, pods: pods}
+}
+
+func (c *paginatingClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	if gvr != podsGVR {
+		return c.Interface.Resource(gvr)
+	}
+	return &paginatingPods{NamespaceableResourceInterface: c.Interface.Resource(gvr), client: c}
+}
+
+type paginatingPods struct {
+	dynamic.NamespaceableResourceInterface
+	client *paginatingClient
+}
+
+func (p *paginatingPods) Namespace(string) dynamic.ResourceInterface {
+	return p
+}
+
+func (p *paginatingPods) List(_ context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	pods := p.client.pods
+	start := 0
+	if opts.Continue != "" {
+		var err error
+		start, err = strconv.Atoi(opts.Continue)
+		require.NoError(p.client.t, err)
+	}
+	end := len(pods)
+	if opts.Limit > 0 && start+int(opts.Limit) < end {
+		end = start + int(opts.Limit)
+	}
+	list := &unstructured.UnstructuredList{Items: pods[start:end]}
+	list.SetAPIVersion("v1")
+	list.SetKind("PodList")
+	list.SetResourceVersion("10")
+	if end < len(pods) {
+		list.SetContinue(strconv.Itoa(end))
+		remaining := int64(len(pods) - end)
+		list.SetRemainingItemCount(&remaining)
+	}
+	return list, nil
+}
+
+func mapper() meta.RESTMapper {
+	return restmapper.NewDiscoveryRESTMapper([]*restmapper.APIGroupResources{
+		{
+			Group: metav1.APIGroup{
+				Versions: []metav1.GroupVersionForDiscovery{{Version: "v1"}},
+			},
+			VersionedResources: map[string][]metav1.APIResource{
+				"v1": {
+					{Name: "pods", Namespaced: true, Kind: "Pod"},
+					{Name: "nodes", Namespaced: false, Kind: "Node"},
+				},
+			},
+		},
+	})
+}
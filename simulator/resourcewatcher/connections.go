@@ -0,0 +1,139 @@
+package resourcewatcher
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+)
+
+// ErrWatcherNotFound is returned when the client to disconnect isn't watching resources.
+var ErrWatcherNotFound = xerrors.New("watcher not found")
+
+// Watcher is the information of a client watching resources.
+type Watcher struct {
+	ID string `json:"id"`
+	// Identity is the identity of the authenticated client.
+	Identity    string      `json:"identity,omitempty"`
+	RemoteAddr  string      `json:"remoteAddr,omitempty"`
+	ConnectedAt metav1.Time `json:"connectedAt"`
+	// Namespace, LabelSelector, Kinds and GVRs are the filters and the additional resources given by the client.
+	Namespace     string            `json:"namespace,omitempty"`
+	LabelSelector string            `json:"labelSelector,omitempty"`
+	Kinds         []sw.ResourceKind `json:"kinds,omitempty"`
+	GVRs          []sw.ResourceKind `json:"gvrs,omitempty"`
+	// EventsSent is the number of the events written to the client.
+	EventsSent int64 `json:"eventsSent"`
+	// DroppedEvents is the number of the events dropped because the client is too slow.
+	DroppedEvents int64 `json:"droppedEvents"`
+	// QueueDepth is the number of the events pending to be written to the client.
+	QueueDepth int `json:"queueDepth"`
+}
+
+// connection is a client watching resources.
+type connection struct {
+	// watcher has the static information of the client.
+	watcher Watcher
+	queue   *queuedWriter
+	// disconnectCh is closed to disconnect the client forcibly.
+	disconnectCh chan struct{}
+}
+
+// connections keeps the clients watching resources.
+type connections struct {
+	mu    sync.Mutex
+	conns map[string]*connection
+}
+
+// newConnection creates the connection of the client watching with opts, which writes the events through queue.
+func newConnection(id string, opts WatchOptions, queue *queuedWriter, connectedAt metav1.Time) *connection {
+	w := Watcher{
+		ID:          id,
+		Identity:    opts.Identity,
+		RemoteAddr:  opts.RemoteAddr,
+		ConnectedAt: connectedAt,
+	}
+	if f := opts.Filter; f != nil {
+		w.Namespace = f.Namespace
+		if f.LabelSelector != nil && !f.LabelSelector.Empty() {
+			w.LabelSelector = f.LabelSelector.String()
+		}
+		w.Kinds = sets.List(f.Kinds)
+	}
+	for _, gvr := range opts.GVRs {
+		w.GVRs = append(w.GVRs, gvrKind(gvr))
+	}
+	return &connection{
+		watcher:      w,
+		queue:        queue,
+		disconnectCh: make(chan struct{}),
+	}
+}
+
+// add adds the connection. The connection must be removed with remove when the client is disconnected.
+func (c *connections) add(conn *connection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conns == nil {
+		c.conns = map[string]*connection{}
+	}
+	c.conns[conn.watcher.ID] = conn
+}
+
+// remove removes the connection of the id.
+func (c *connections) remove(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.conns, id)
+}
+
+// list returns the information of the clients in the order of connecting.
+func (c *connections) list() []Watcher {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ret := make([]Watcher, 0, len(c.conns))
+	for _, conn := range c.conns {
+		w := conn.watcher
+		w.EventsSent = conn.queue.Sent()
+		w.DroppedEvents = conn.queue.Dropped()
+		w.QueueDepth = conn.queue.Depth()
+		ret = append(ret, w)
+	}
+	// The IDs are assigned in ascending order.
+	sort.Slice(ret, func(i, j int) bool {
+		a, _ := strconv.ParseUint(ret[i].ID, 10, 64)
+		b, _ := strconv.ParseUint(ret[j].ID, 10, 64)
+		return a < b
+	})
+	return ret
+}
+
+// disconnect disconnects the client of the id forcibly.
+func (c *connections) disconnect(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	conn, ok := c.conns[id]
+	if !ok {
+		return xerrors.Errorf("disconnect %s: %w", id, ErrWatcherNotFound)
+	}
+	// The connection is removed here so that disconnectCh is closed only once.
+	delete(c.conns, id)
+	close(conn.disconnectCh)
+	return nil
+}
+
+// Watchers returns the information of the clients watching resources.
+func (s *Service) Watchers() []Watcher {
+	return s.connections.list()
+}
+
+// Disconnect disconnects the client watching resources of the id forcibly.
+// It returns ErrWatcherNotFound if the client isn't found.
+func (s *Service) Disconnect(id string) error {
+	return s.connections.disconnect(id)
+}
@@ -0,0 +1,87 @@
+package resourcewatcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/utils/clock"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+)
+
+func TestService_Watchers(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	connectedAt := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewService(nil, nil, nil, Options{})
+
+	// the two clients are connected with the mock streams.
+	got1 := make(chan queuedEvent, 2)
+	queue1 := newQueuedWriter(sw.NewStreamWriter(newQueueTestStream(ctrl, got1, nil, nil)), 10, OverflowCoalesce, 0, clock.RealClock{}, "1")
+	conn1 := newConnection("1", WatchOptions{
+		Identity:   "bearer-token",
+		RemoteAddr: "192.0.2.1",
+		Filter:     &Filter{Namespace: "default", LabelSelector: labels.SelectorFromSet(labels.Set{"app": "web"}), Kinds: sets.New(Pods)},
+		GVRs:       []schema.GroupVersionResource{widgetsGVR},
+	}, queue1, connectedAt)
+	got2 := make(chan queuedEvent, 2)
+	queue2 := newQueuedWriter(sw.NewStreamWriter(newQueueTestStream(ctrl, got2, nil, nil)), 10, OverflowCoalesce, 0, clock.RealClock{}, "2")
+	conn2 := newConnection("2", WatchOptions{RemoteAddr: "192.0.2.2"}, queue2, connectedAt)
+	// the clients are listed in the order of connecting regardless of the order of adding.
+	s.connections.add(conn2)
+	s.connections.add(conn1)
+	go func() { _ = queue1.run(ctx.Done()) }()
+	go func() { _ = queue2.run(ctx.Done()) }()
+
+	require.NoError(t, queue1.Write(&sw.WatchEvent{Kind: Pods, EventType: watch.Added, Obj: pod("pod1", "1")}))
+	require.NoError(t, queue1.Write(&sw.WatchEvent{Kind: Pods, EventType: watch.Added, Obj: pod("pod2", "2")}))
+	receive(t, got1, 2)
+	require.NoError(t, queue2.Write(&sw.WatchEvent{Kind: Pods, EventType: watch.Added, Obj: pod("pod1", "1")}))
+	receive(t, got2, 1)
+	// the counter is updated after the write returns.
+	require.Eventually(t, func() bool { return queue1.Sent() == 2 && queue2.Sent() == 1 }, wait.ForeverTestTimeout, 10*time.Millisecond)
+
+	assert.Equal(t, []Watcher{
+		{
+			ID:            "1",
+			Identity:      "bearer-token",
+			RemoteAddr:    "192.0.2.1",
+			ConnectedAt:   connectedAt,
+			Namespace:     "default",
+			LabelSelector: "app=web",
+			Kinds:         []sw.ResourceKind{Pods},
+			GVRs:          []sw.ResourceKind{"example.com/v1/widgets"},
+			EventsSent:    2,
+		},
+		{
+			ID:          "2",
+			RemoteAddr:  "192.0.2.2",
+			ConnectedAt: connectedAt,
+			EventsSent:  1,
+		},
+	}, s.Watchers())
+
+	// the disconnected client is removed from the list.
+	require.NoError(t, s.Disconnect("1"))
+	select {
+	case <-conn1.disconnectCh:
+	default:
+		t.Fatal("the client isn't told to disconnect")
+	}
+	watchers := s.Watchers()
+	require.Len(t, watchers, 1)
+	assert.Equal(t, "2", watchers[0].ID)
+	assert.ErrorIs(t, s.Disconnect("1"), ErrWatcherNotFound)
+}
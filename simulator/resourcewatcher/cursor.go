@@ -0,0 +1,105 @@
+package resourcewatcher
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+)
+
+// cursor keeps the last resource version of each resource kind sent to the client,
+// and attaches the encoded versions to each WatchEvent as the cursor
+// so that the client can resume watching from there with ParseCursor after reconnecting.
+type cursor struct {
+	mu       sync.Mutex
+	versions map[sw.ResourceKind]string
+}
+
+func newCursor(lrVersions *LastResourceVersions) *cursor {
+	return &cursor{versions: versionsOf(lrVersions)}
+}
+
+// versionsOf returns the non-empty versions in lrVersions keyed by the kind.
+func versionsOf(lrVersions *LastResourceVersions) map[sw.ResourceKind]string {
+	versions := map[sw.ResourceKind]string{}
+	for kind, v := range map[sw.ResourceKind]string{
+		Pods:       lrVersions.Pods,
+		Nodes:      lrVersions.Nodes,
+		Pvs:        lrVersions.Pvs,
+		Pvcs:       lrVersions.Pvcs,
+		Scs:        lrVersions.Scs,
+		Pcs:        lrVersions.Pcs,
+		Namespaces: lrVersions.Namespaces,
+	} {
+		if v != "" {
+			versions[kind] = v
+		}
+	}
+	return versions
+}
+
+// set updates the last resource version of kind.
+// The empty version removes kind from the cursor so that the kind is listed again on resume.
+func (c *cursor) set(kind sw.ResourceKind, version string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(kind, version)
+}
+
+func (c *cursor) setLocked(kind sw.ResourceKind, version string) {
+	if version == "" {
+		delete(c.versions, kind)
+		return
+	}
+	c.versions[kind] = version
+}
+
+// write updates the last resource version of kind if version isn't empty, and writes we with the updated cursor.
+// Updating and writing are done atomically so that the cursor received by the client never goes ahead of the events.
+func (c *cursor) write(writer StreamWriter, we *sw.WatchEvent, kind sw.ResourceKind, version string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if version != "" {
+		c.setLocked(kind, version)
+	}
+	encoded, err := encodeCursor(c.versions)
+	if err != nil {
+		return err
+	}
+	we.Cursor = encoded
+	return writer.Write(we)
+}
+
+// encodeCursor encodes the versions of each kind into the cursor.
+func encodeCursor(versions map[sw.ResourceKind]string) (string, error) {
+	encoded, err := json.Marshal(versions)
+	if err != nil {
+		return "", xerrors.Errorf("encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+// ParseCursor decodes the cursor attached to WatchEvent into LastResourceVersions.
+// The kinds not in the cursor have the empty version, and are listed again.
+func ParseCursor(s string) (*LastResourceVersions, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, xerrors.Errorf("decode cursor: %w", err)
+	}
+	versions := map[sw.ResourceKind]string{}
+	if err := json.Unmarshal(b, &versions); err != nil {
+		return nil, xerrors.Errorf("unmarshal cursor: %w", err)
+	}
+	return &LastResourceVersions{
+		Pods:       versions[Pods],
+		Nodes:      versions[Nodes],
+		Pvs:        versions[Pvs],
+		Pvcs:       versions[Pvcs],
+		Scs:        versions[Scs],
+		Pcs:        versions[Pcs],
+		Namespaces: versions[Namespaces],
+	}, nil
+}
@@ -0,0 +1,97 @@
+package resourcewatcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	restfake "k8s.io/client-go/rest/fake"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/mock_resourcewatcher"
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+)
+
+func TestCursor_resume(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockStreamWriter := mock_resourcewatcher.NewMockStreamWriter(ctrl)
+	var written []*sw.WatchEvent
+	mockStreamWriter.EXPECT().Write(gomock.Any()).DoAndReturn(func(we *sw.WatchEvent) error {
+		written = append(written, we)
+		return nil
+	}).AnyTimes()
+
+	// the client connected with the versions of pods.
+	c := newCursor(&LastResourceVersions{Pods: "10"})
+	nodeProxy := neweventProxy(mockStreamWriter, &restfake.RESTClient{}, Nodes, &corev1.Node{}, "")
+	nodeProxy.cursor = c
+
+	// the listed nodes don't advance the cursor until the list is done.
+	require.NoError(t, nodeProxy.sendListedItems([]runtime.Object{fakenode1}))
+	got, err := ParseCursor(written[0].Cursor)
+	require.NoError(t, err)
+	assert.Equal(t, &LastResourceVersions{Pods: "10"}, got)
+
+	// the watched events advance the cursor.
+	c.set(Nodes, "150")
+	fw := watch.NewFake()
+	go func() {
+		fw.Modify(fakenode2)
+		fw.Stop()
+	}()
+	// the handler returns an error when the watcher is stopped.
+	_ = nodeProxy.watchHandlerFunc(fw)(make(chan struct{}))
+	require.Len(t, written, 2)
+	got, err = ParseCursor(written[1].Cursor)
+	require.NoError(t, err)
+	assert.Equal(t, &LastResourceVersions{Pods: "10", Nodes: "200"}, got)
+
+	// the client reconnects with the last cursor, and the watch resumes from there without listing.
+	resumed := newCursor(got)
+	require.NoError(t, resumed.write(mockStreamWriter, &sw.WatchEvent{Kind: Pods, EventType: watch.Added}, Pods, "250"))
+	got, err = ParseCursor(written[2].Cursor)
+	require.NoError(t, err)
+	assert.Equal(t, &LastResourceVersions{Pods: "250", Nodes: "200"}, got)
+}
+
+func TestParseCursor_invalid(t *testing.T) {
+	t.Parallel()
+	_, err := ParseCursor("not a cursor")
+	assert.Error(t, err)
+}
+
+func TestEventProxyer_expiredResourceVersion(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockStreamWriter := mock_resourcewatcher.NewMockStreamWriter(ctrl)
+	proxy := neweventProxy(mockStreamWriter, &restfake.RESTClient{}, Nodes, &corev1.Node{}, "1")
+	proxy.cursor = newCursor(&LastResourceVersions{Nodes: "1", Pods: "10"})
+
+	fw := watch.NewFake()
+	go fw.Error(&metav1.Status{Status: metav1.StatusFailure, Code: http.StatusGone, Reason: metav1.StatusReasonExpired})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := proxy.watchAndHandleEvent(fw, ctx.Done())
+	assert.True(t, errors.Is(err, errResourceExpired), "watchAndHandleEvent should return errResourceExpired, but got %v", err)
+
+	// RESYNC_REQUIRED is sent, and the cursor no longer has the expired version.
+	mockStreamWriter.EXPECT().Write(gomock.Any()).DoAndReturn(func(we *sw.WatchEvent) error {
+		assert.Equal(t, Nodes, we.Kind)
+		assert.Equal(t, sw.ResyncRequired, we.EventType)
+		got, err := ParseCursor(we.Cursor)
+		require.NoError(t, err)
+		assert.Equal(t, &LastResourceVersions{Pods: "10"}, got)
+		return nil
+	})
+	require.NoError(t, proxy.requireResync())
+	assert.Equal(t, "", proxy.lastResourceVersion())
+}
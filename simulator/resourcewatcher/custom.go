@@ -0,0 +1,176 @@
+package resourcewatcher
+
+import (
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+)
+
+// ParseGVR parses the GVR in the form of `group/version/resource`, or `version/resource` for the core group.
+func ParseGVR(s string) (schema.GroupVersionResource, error) {
+	parts := strings.Split(s, "/")
+	for _, p := range parts {
+		if p == "" {
+			return schema.GroupVersionResource{}, xerrors.Errorf("invalid GVR %q, must be group/version/resource (e.g. example.com/v1/widgets) or version/resource for the core group", s)
+		}
+	}
+	switch len(parts) {
+	case 2:
+		return schema.GroupVersionResource{Version: parts[0], Resource: parts[1]}, nil
+	case 3:
+		return schema.GroupVersionResource{Group: parts[0], Version: parts[1], Resource: parts[2]}, nil
+	default:
+		return schema.GroupVersionResource{}, xerrors.Errorf("invalid GVR %q, must be group/version/resource (e.g. example.com/v1/widgets) or version/resource for the core group", s)
+	}
+}
+
+// gvrKind returns the ResourceKind of the events of the GVR, which is in the same form as ParseGVR takes.
+func gvrKind(gvr schema.GroupVersionResource) sw.ResourceKind {
+	if gvr.Group == "" {
+		return sw.ResourceKind(gvr.Version + "/" + gvr.Resource)
+	}
+	return sw.ResourceKind(gvr.Group + "/" + gvr.Version + "/" + gvr.Resource)
+}
+
+// customInformer is the informer of a GVR shared among the clients requesting it.
+type customInformer struct {
+	informer cache.SharedIndexInformer
+	stopCh   chan struct{}
+	// refs is the number of the clients using the informer.
+	refs int
+}
+
+// customInformers starts the informers of the GVRs requested by the clients on demand,
+// and stops each of them when the last client using it is disconnected.
+type customInformers struct {
+	mu        sync.Mutex
+	client    dynamic.Interface
+	informers map[schema.GroupVersionResource]*customInformer
+}
+
+func newCustomInformers(client dynamic.Interface) *customInformers {
+	return &customInformers{
+		client:    client,
+		informers: map[schema.GroupVersionResource]*customInformer{},
+	}
+}
+
+// watch writes the events of the GVR to writer, starting the informer of the GVR if it's not running.
+// The objects already in the informer are written as ADDED events first, and then the snapshot of the GVR is finished.
+// The returned function must be called to stop writing when the client is disconnected.
+func (m *customInformers) watch(gvr schema.GroupVersionResource, writer StreamWriter, snapshot *snapshotWriter) (func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ci, ok := m.informers[gvr]
+	if !ok {
+		ci = &customInformer{
+			informer: dynamicinformer.NewFilteredDynamicInformer(m.client, gvr, metav1.NamespaceAll, 0, cache.Indexers{}, nil).Informer(),
+			stopCh:   make(chan struct{}),
+		}
+		m.informers[gvr] = ci
+		go ci.informer.Run(ci.stopCh)
+	}
+	kind := gvrKind(gvr)
+	reg, err := ci.informer.AddEventHandler(newCustomEventHandler(kind, writer, snapshot))
+	if err != nil {
+		if ci.refs == 0 {
+			close(ci.stopCh)
+			delete(m.informers, gvr)
+		}
+		return nil, xerrors.Errorf("add event handler to the informer of %s: %w", gvr, err)
+	}
+	ci.refs++
+
+	stopCh := make(chan struct{})
+	go func() {
+		// The registration is synced when the handler has received all the objects in the informer.
+		if cache.WaitForCacheSync(stopCh, reg.HasSynced) {
+			finishSnapshot(snapshot, kind)
+		}
+	}()
+	return func() {
+		close(stopCh)
+		m.release(gvr, ci, reg)
+	}, nil
+}
+
+// release stops writing the events with reg, and stops the informer if no client uses it anymore.
+func (m *customInformers) release(gvr schema.GroupVersionResource, ci *customInformer, reg cache.ResourceEventHandlerRegistration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := ci.informer.RemoveEventHandler(reg); err != nil {
+		klog.Warningf("failed to remove event handler from the informer of %s: %v", gvr, err)
+	}
+	ci.refs--
+	if ci.refs > 0 {
+		return
+	}
+	close(ci.stopCh)
+	delete(m.informers, gvr)
+}
+
+// finishSnapshot finishes the snapshot of the custom resource kind, which has no version to resume from.
+// snapshot can be nil when the snapshot isn't delivered.
+func finishSnapshot(snapshot *snapshotWriter, kind sw.ResourceKind) {
+	if snapshot == nil {
+		return
+	}
+	// The error means the client is disconnected, which is handled by the queue.
+	if err := snapshot.finish(kind, ""); err != nil {
+		klog.V(4).Infof("failed to finish the snapshot of %s: %v", kind, err)
+	}
+}
+
+// newCustomEventHandler returns the handler writing the events of the informer to writer.
+// The snapshot of the kind is finished before the first event after the objects already in the informer.
+func newCustomEventHandler(kind sw.ResourceKind, writer StreamWriter, snapshot *snapshotWriter) cache.ResourceEventHandler {
+	write := func(eventType watch.EventType, obj interface{}) {
+		// The error means the client is disconnected, which is handled by the queue.
+		if err := writer.Write(&sw.WatchEvent{Kind: kind, EventType: eventType, Obj: obj}); err != nil {
+			klog.V(4).Infof("failed to write the event of %s: %v", kind, err)
+		}
+	}
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if !isInInitialList {
+				finishSnapshot(snapshot, kind)
+			}
+			write(watch.Added, obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			finishSnapshot(snapshot, kind)
+			write(watch.Modified, obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			finishSnapshot(snapshot, kind)
+			if d, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = d.Obj
+			}
+			write(watch.Deleted, obj)
+		},
+	}
+}
+
+// ValidateGVRs returns an error if any of the GVRs isn't served in the simulator.
+func (s *Service) ValidateGVRs(gvrs []schema.GroupVersionResource) error {
+	for _, gvr := range gvrs {
+		if _, err := s.restMapper.KindFor(gvr); err != nil {
+			if meta.IsNoMatchError(err) {
+				return xerrors.Errorf("unknown GVR %q, the resource isn't served in the simulator: %w", gvrKind(gvr), err)
+			}
+			return xerrors.Errorf("resolve the kind of %q: %w", gvrKind(gvr), err)
+		}
+	}
+	return nil
+}
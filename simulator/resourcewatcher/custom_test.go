@@ -0,0 +1,169 @@
+package resourcewatcher
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+)
+
+var widgetsGVR = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+
+func widget(name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("example.com/v1")
+	u.SetKind("Widget")
+	u.SetNamespace("default")
+	u.SetName(name)
+	return u
+}
+
+// eventRecorder records the names of the objects in the events written to it.
+type eventRecorder struct {
+	mu  sync.Mutex
+	got []queuedEvent
+}
+
+func (r *eventRecorder) Write(we *sw.WatchEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	accessor, err := meta.Accessor(we.Obj)
+	if err != nil {
+		return err
+	}
+	r.got = append(r.got, queuedEvent{EventType: we.EventType, Name: accessor.GetName()})
+	return nil
+}
+
+func (r *eventRecorder) events() []queuedEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]queuedEvent{}, r.got...)
+}
+
+func TestParseGVR(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		s       string
+		want    schema.GroupVersionResource
+		wantErr bool
+	}{
+		{
+			name: "group/version/resource",
+			s:    "example.com/v1/widgets",
+			want: widgetsGVR,
+		},
+		{
+			name: "version/resource for the core group",
+			s:    "v1/configmaps",
+			want: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+		},
+		{
+			name:    "only resource",
+			s:       "widgets",
+			wantErr: true,
+		},
+		{
+			name:    "empty part",
+			s:       "example.com//widgets",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseGVR(tt.s)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, sw.ResourceKind(tt.s), gvrKind(got))
+		})
+	}
+}
+
+func TestService_ValidateGVRs(t *testing.T) {
+	t.Parallel()
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}, meta.RESTScopeNamespace)
+	s := NewService(nil, nil, mapper, Options{})
+
+	assert.NoError(t, s.ValidateGVRs([]schema.GroupVersionResource{widgetsGVR}))
+	err := s.ValidateGVRs([]schema.GroupVersionResource{widgetsGVR, {Group: "example.com", Version: "v1", Resource: "gadgets"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "example.com/v1/gadgets")
+}
+
+func TestCustomInformers_watch(t *testing.T) {
+	t.Parallel()
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{widgetsGVR: "WidgetList"}, widget("widget1"))
+	m := newCustomInformers(client)
+	running := func() int {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		ci, ok := m.informers[widgetsGVR]
+		if !ok {
+			return 0
+		}
+		return ci.refs
+	}
+
+	// the two clients requesting the same GVR share one informer.
+	client1 := &eventRecorder{}
+	stop1, err := m.watch(widgetsGVR, client1, nil)
+	require.NoError(t, err)
+	client2 := &eventRecorder{}
+	stop2, err := m.watch(widgetsGVR, client2, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, running())
+	informer := m.informers[widgetsGVR].informer
+
+	// both clients receive the existing object and the new one.
+	_, err = client.Resource(widgetsGVR).Namespace("default").Create(context.Background(), widget("widget2"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	want := []queuedEvent{{EventType: watch.Added, Name: "widget1"}, {EventType: watch.Added, Name: "widget2"}}
+	for _, c := range []*eventRecorder{client1, client2} {
+		require.Eventually(t, func() bool { return len(c.events()) == len(want) }, wait.ForeverTestTimeout, 10*time.Millisecond)
+		assert.Equal(t, want, c.events())
+	}
+
+	// the informer keeps running while the other client is interested in it.
+	stop1()
+	assert.Equal(t, 1, running())
+	assert.False(t, informer.IsStopped())
+	require.NoError(t, client.Resource(widgetsGVR).Namespace("default").Delete(context.Background(), "widget1", metav1.DeleteOptions{}))
+	require.Eventually(t, func() bool { return len(client2.events()) == 3 }, wait.ForeverTestTimeout, 10*time.Millisecond)
+	assert.Equal(t, queuedEvent{EventType: watch.Deleted, Name: "widget1"}, client2.events()[2])
+	assert.Len(t, client1.events(), 2)
+
+	// the informer is stopped when the last client is disconnected.
+	stop2()
+	assert.Equal(t, 0, running())
+	require.Eventually(t, informer.IsStopped, wait.ForeverTestTimeout, 10*time.Millisecond)
+
+	// the new client starts the informer again.
+	client3 := &eventRecorder{}
+	stop3, err := m.watch(widgetsGVR, client3, nil)
+	require.NoError(t, err)
+	defer stop3()
+	assert.Equal(t, 1, running())
+	require.Eventually(t, func() bool { return len(client3.events()) == 1 }, wait.ForeverTestTimeout, 10*time.Millisecond)
+	assert.Equal(t, []queuedEvent{{EventType: watch.Added, Name: "widget2"}}, client3.events())
+}
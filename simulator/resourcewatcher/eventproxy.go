@@ -10,6 +10,7 @@ import (
 	"io"
 
 	"golang.org/x/xerrors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -25,7 +26,8 @@ import (
 // eventProxyer is an interface that allows handle events and errors.
 type eventProxyer interface {
 	listAndHandleItems(lw cache.Lister) error
-	watchAndHandleEvent(watcher watch.Interface, stopCh <-chan struct{})
+	watchAndHandleEvent(watcher watch.Interface, stopCh <-chan struct{}) error
+	requireResync() error
 	lastResourceVersion() string
 	resourceKind() sw.ResourceKind
 	restClient() cache.Getter
@@ -50,8 +52,17 @@ type eventProxy struct {
 	// lrv can be used to ensure that only events
 	// that have not yet been received are received when reconnecting.
 	lrv string
+	// cursor is shared by the proxies writing to the same client, and attaches the cursor to each event.
+	// It can be nil not to attach the cursor.
+	cursor *cursor
+	// snapshot is told when the listed items are all written, so that the client knows it has the current objects.
+	// It can be nil not to deliver the snapshot.
+	snapshot *snapshotWriter
 }
 
+// errResourceExpired is returned when the lastResourceVersion is too old to watch from.
+var errResourceExpired = xerrors.New("resource version is too old")
+
 func neweventProxy(sw StreamWriter, c cache.Getter, r sw.ResourceKind, o runtime.Object, lrv string) *eventProxy {
 	return &eventProxy{
 		writer: sw,
@@ -76,6 +87,15 @@ func (p *eventProxy) listAndHandleItems(lw cache.Lister) error {
 		return xerrors.Errorf("call ListItemsHandle: %w", err)
 	}
 	p.lrv = lrv
+	// The cursor isn't updated by each listed item, because the items aren't ordered by the resource version.
+	if p.cursor != nil {
+		p.cursor.set(p.r, lrv)
+	}
+	if p.snapshot != nil {
+		if err := p.snapshot.finish(p.r, lrv); err != nil {
+			return xerrors.Errorf("finish snapshot: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -94,24 +114,54 @@ func extractListItem(list runtime.Object) ([]runtime.Object, string, error) {
 
 // watchAndHandleEvent prepares a handler for the wacher and runs the handler
 // until the stopCh is closed.
-func (p *eventProxy) watchAndHandleEvent(watcher watch.Interface, stopCh <-chan struct{}) {
+// It returns errResourceExpired if the lastResourceVersion is too old and the resync is required.
+func (p *eventProxy) watchAndHandleEvent(watcher watch.Interface, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	handleFunc := p.watchHandlerFunc(watcher)
+	var expiredErr error
 	run := func(stopCh <-chan struct{}) {
-		if err := handleFunc(stopCh); err != nil {
+		err := handleFunc(stopCh)
+		if errors.Is(err, errResourceExpired) {
+			expiredErr = err
+			return
+		}
+		if err != nil {
 			p.watchErrorHandler(err)
 		}
 	}
 	var wg wait.Group
 	wg.StartWithChannel(stopCh, run)
 	wg.Wait()
+	return expiredErr
+}
+
+// requireResync tells the client that the objects of the resource must be resynced,
+// and clears the lastResourceVersion so that the resource is listed again.
+func (p *eventProxy) requireResync() error {
+	p.lrv = ""
+	if p.cursor != nil {
+		p.cursor.set(p.r, "")
+	}
+	if err := p.write(&sw.WatchEvent{Kind: p.r, EventType: sw.ResyncRequired}, ""); err != nil {
+		return xerrors.Errorf("call Write to require resync: %w", err)
+	}
+	return nil
+}
+
+// write writes the event to the client with the cursor updated to version.
+// The empty version doesn't update the cursor.
+func (p *eventProxy) write(we *sw.WatchEvent, version string) error {
+	if p.cursor == nil {
+		return p.writer.Write(we)
+	}
+	return p.cursor.write(p.writer, we, p.r, version)
 }
 
 // sendListedItems sends results of list as "ADDED" event to the client.
 // This method will be expected to call before starting the watch.
 func (p *eventProxy) sendListedItems(items []runtime.Object) error {
 	for _, item := range items {
-		if err := p.writer.Write(&sw.WatchEvent{Kind: p.r, EventType: watch.Added, Obj: item}); err != nil {
+		if err := p.write(&sw.WatchEvent{Kind: p.r, EventType: watch.Added, Obj: item}, ""); err != nil {
 			return xerrors.Errorf("call Write to return list item %#v: %w", item, err)
 		}
 	}
@@ -135,6 +185,13 @@ func (p *eventProxy) watchHandlerFunc(watcher watch.Interface) func(stopCh <-cha
 				if !ok {
 					return xerrors.New("closed channel")
 				}
+				if event.Type == watch.Error {
+					// The error event has metav1.Status, which isn't metav1.Object.
+					if err := apierrors.FromObject(event.Object); apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+						return xerrors.Errorf("%s: %w", p.resourceKind(), errResourceExpired)
+					}
+					return xerrors.Errorf("%s: get an error watch event %#v", p.resourceKind(), event.Object)
+				}
 				obj, ok := event.Object.(metav1.Object)
 				if !ok {
 					return xerrors.Errorf("failed to cast type from %T to metav1.Object", event.Object)
@@ -142,15 +199,16 @@ func (p *eventProxy) watchHandlerFunc(watcher watch.Interface) func(stopCh <-cha
 				var writingErr error
 				switch event.Type {
 				case watch.Added:
-					writingErr = p.writer.Write(&sw.WatchEvent{Kind: p.r, EventType: watch.Added, Obj: obj})
+					writingErr = p.write(&sw.WatchEvent{Kind: p.r, EventType: watch.Added, Obj: obj}, obj.GetResourceVersion())
 				case watch.Modified:
-					writingErr = p.writer.Write(&sw.WatchEvent{Kind: p.r, EventType: watch.Modified, Obj: obj})
+					writingErr = p.write(&sw.WatchEvent{Kind: p.r, EventType: watch.Modified, Obj: obj}, obj.GetResourceVersion())
 				case watch.Deleted:
-					writingErr = p.writer.Write(&sw.WatchEvent{Kind: p.r, EventType: watch.Deleted, Obj: obj})
+					writingErr = p.write(&sw.WatchEvent{Kind: p.r, EventType: watch.Deleted, Obj: obj}, obj.GetResourceVersion())
 				case watch.Bookmark:
 					// A `Bookmark` means watch has synced here, just update the resourceVersion
-				case watch.Error:
-					return xerrors.Errorf("%s: get an error watch event %#v", p.resourceKind(), obj)
+					if p.cursor != nil {
+						p.cursor.set(p.r, obj.GetResourceVersion())
+					}
 				default:
 					return xerrors.Errorf("%s: unsupported event type %v, object %#v", p.resourceKind(), event.Type, obj)
 				}
@@ -0,0 +1,120 @@
+package resourcewatcher
+
+import (
+	"sync"
+
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+)
+
+// namespacedKinds is the kinds of the namespaced resources.
+var namespacedKinds = sets.New(Pods, Pvcs)
+
+// AllKinds is all the kinds of resources which the Service watches.
+var AllKinds = sets.New(Pods, Nodes, Pvs, Pvcs, Scs, Pcs, Namespaces)
+
+// Filter restricts the events sent to a client.
+type Filter struct {
+	// Namespace restricts the namespaced resources to the namespace.
+	// The cluster-scoped resources aren't restricted by it. All namespaces if empty.
+	Namespace string
+	// LabelSelector restricts the resources to the ones with the matching labels. Everything if nil.
+	LabelSelector labels.Selector
+	// Kinds restricts the kinds of resources to watch. All kinds if empty.
+	Kinds sets.Set[sw.ResourceKind]
+}
+
+// watches returns true if the kind is watched with the filter.
+func (f *Filter) watches(kind sw.ResourceKind) bool {
+	return f == nil || f.Kinds.Len() == 0 || f.Kinds.Has(kind)
+}
+
+// matches returns true if the object of the kind passes the filter.
+func (f *Filter) matches(kind sw.ResourceKind, obj interface{}) (bool, error) {
+	if f == nil {
+		return true, nil
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false, xerrors.Errorf("get accessor of %s: %w", kind, err)
+	}
+	if f.Namespace != "" && namespacedKinds.Has(kind) && accessor.GetNamespace() != f.Namespace {
+		return false, nil
+	}
+	if f.LabelSelector != nil && !f.LabelSelector.Matches(labels.Set(accessor.GetLabels())) {
+		return false, nil
+	}
+	return true, nil
+}
+
+type objectKey struct {
+	kind sw.ResourceKind
+	types.NamespacedName
+}
+
+// filteredWriter writes only the events of the objects passing the filter to the client.
+// It keeps which objects the client has, so that the client is told to delete the object
+// when the object stops passing the filter by the update, e.g., the labels are changed.
+type filteredWriter struct {
+	mu     sync.Mutex
+	writer StreamWriter
+	filter *Filter
+	// sent has the objects which are sent to the client and not deleted yet.
+	sent sets.Set[objectKey]
+}
+
+func newFilteredWriter(writer StreamWriter, filter *Filter) *filteredWriter {
+	return &filteredWriter{
+		writer: writer,
+		filter: filter,
+		sent:   sets.New[objectKey](),
+	}
+}
+
+// Write writes we to the client if the object passes the filter.
+// The event type is changed to ADDED or DELETED if the object starts or stops passing the filter by the update.
+func (w *filteredWriter) Write(we *sw.WatchEvent) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if we.EventType == sw.ResyncRequired {
+		// The client discards all the objects of the kind.
+		for k := range w.sent {
+			if k.kind == we.Kind {
+				w.sent.Delete(k)
+			}
+		}
+		return w.writer.Write(we)
+	}
+
+	matched, err := w.filter.matches(we.Kind, we.Obj)
+	if err != nil {
+		return err
+	}
+	key, _ := keyOf(we)
+	sent := w.sent.Has(key)
+
+	switch {
+	case we.EventType == watch.Deleted:
+		if !sent && !matched {
+			return nil
+		}
+		w.sent.Delete(key)
+	case matched:
+		if !sent && we.EventType == watch.Modified {
+			we.EventType = watch.Added
+		}
+		w.sent.Insert(key)
+	case sent:
+		we.EventType = watch.Deleted
+		w.sent.Delete(key)
+	default:
+		return nil
+	}
+	return w.writer.Write(we)
+}
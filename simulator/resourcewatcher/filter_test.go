@@ -0,0 +1,130 @@
+package resourcewatcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter/mock_streamwriter"
+)
+
+// receivedEvent is the event decoded from the stream with the name of the object.
+type receivedEvent struct {
+	Kind      sw.ResourceKind
+	EventType watch.EventType
+	Name      string
+}
+
+// newRecordingStream returns the mock ResponseStream which records the events written to it.
+func newRecordingStream(ctrl *gomock.Controller, mu *sync.Mutex, got *[]receivedEvent) *mock_streamwriter.MockResponseStream {
+	stream := mock_streamwriter.NewMockResponseStream(ctrl)
+	stream.EXPECT().Write(gomock.Any()).DoAndReturn(func(p []byte) (int, error) {
+		var e struct {
+			Kind      sw.ResourceKind
+			EventType watch.EventType
+			Obj       struct {
+				Metadata metav1.ObjectMeta `json:"metadata"`
+			}
+		}
+		if err := json.NewDecoder(bytes.NewReader(p)).Decode(&e); err != nil {
+			return 0, err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		*got = append(*got, receivedEvent{Kind: e.Kind, EventType: e.EventType, Name: e.Obj.Metadata.Name})
+		return len(p), nil
+	}).AnyTimes()
+	stream.EXPECT().Flush().AnyTimes()
+	return stream
+}
+
+func TestFilteredWriter_Write(t *testing.T) {
+	t.Parallel()
+	podA := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-a", Namespace: "default", Labels: map[string]string{"app": "web"}}}
+	podB := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-b", Namespace: "kube-system", Labels: map[string]string{"app": "web"}}}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"app": "db"}}}
+	podAUpdated := podA.DeepCopy()
+	podAUpdated.Labels["app"] = "db"
+	podC := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-c", Namespace: "default"}}
+	events := []*sw.WatchEvent{
+		{Kind: Pods, EventType: watch.Added, Obj: podA},
+		{Kind: Pods, EventType: watch.Added, Obj: podB},
+		{Kind: Nodes, EventType: watch.Added, Obj: node},
+		// pod-a stops matching app=web.
+		{Kind: Pods, EventType: watch.Modified, Obj: podAUpdated},
+		// pod-c never matches app=web.
+		{Kind: Pods, EventType: watch.Deleted, Obj: podC},
+	}
+
+	tests := []struct {
+		name   string
+		filter *Filter
+		want   []receivedEvent
+	}{
+		{
+			name:   "namespace filter doesn't restrict the cluster-scoped resources",
+			filter: &Filter{Namespace: "default"},
+			want: []receivedEvent{
+				{Kind: Pods, EventType: watch.Added, Name: "pod-a"},
+				{Kind: Nodes, EventType: watch.Added, Name: "node-1"},
+				{Kind: Pods, EventType: watch.Modified, Name: "pod-a"},
+				{Kind: Pods, EventType: watch.Deleted, Name: "pod-c"},
+			},
+		},
+		{
+			name:   "the object stopped matching the label selector is deleted",
+			filter: &Filter{LabelSelector: labels.SelectorFromSet(labels.Set{"app": "web"})},
+			want: []receivedEvent{
+				{Kind: Pods, EventType: watch.Added, Name: "pod-a"},
+				{Kind: Pods, EventType: watch.Added, Name: "pod-b"},
+				{Kind: Pods, EventType: watch.Deleted, Name: "pod-a"},
+			},
+		},
+	}
+
+	// The two clients with the different filters receive the same events concurrently.
+	ctrl := gomock.NewController(t)
+	var mu sync.Mutex
+	got := make([][]receivedEvent, len(tests))
+	writers := make([]*filteredWriter, len(tests))
+	for i, tt := range tests {
+		writers[i] = newFilteredWriter(sw.NewStreamWriter(newRecordingStream(ctrl, &mu, &got[i])), tt.filter)
+	}
+	var wg sync.WaitGroup
+	for _, w := range writers {
+		wg.Add(1)
+		go func(w *filteredWriter) {
+			defer wg.Done()
+			for _, e := range events {
+				// copy the event since the writer may change its type.
+				e := *e
+				assert.NoError(t, w.Write(&e))
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	for i, tt := range tests {
+		assert.Equal(t, tt.want, got[i], tt.name)
+	}
+}
+
+func TestFilter_watches(t *testing.T) {
+	t.Parallel()
+	var nilFilter *Filter
+	assert.True(t, nilFilter.watches(Pods))
+	assert.True(t, (&Filter{}).watches(Pods))
+	f := &Filter{Kinds: sets.New(Pods, Nodes)}
+	assert.True(t, f.watches(Nodes))
+	assert.False(t, f.watches(Namespaces))
+}
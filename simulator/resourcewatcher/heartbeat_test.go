@@ -0,0 +1,109 @@
+package resourcewatcher
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclock "k8s.io/utils/clock/testing"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter/mock_streamwriter"
+)
+
+func TestService_sendHeartbeats(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name                        string
+		prepareResponseStreamMockFn func(rs *mock_streamwriter.MockResponseStream, written chan<- []byte)
+		wantErr                     bool
+	}{
+		{
+			name: "should write the heartbeat every interval",
+			prepareResponseStreamMockFn: func(rs *mock_streamwriter.MockResponseStream, written chan<- []byte) {
+				rs.EXPECT().Write(gomock.Any()).DoAndReturn(func(p []byte) (int, error) {
+					written <- append([]byte{}, p...)
+					return len(p), nil
+				}).Times(2)
+				rs.EXPECT().Flush().Times(2)
+			},
+			wantErr: false,
+		},
+		{
+			name: "should stop and return an error when the heartbeat fails",
+			prepareResponseStreamMockFn: func(rs *mock_streamwriter.MockResponseStream, _ chan<- []byte) {
+				rs.EXPECT().Write(gomock.Any()).Return(0, xerrors.New("broken pipe"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockResponseStream := mock_streamwriter.NewMockResponseStream(ctrl)
+			written := make(chan []byte, 2)
+			tt.prepareResponseStreamMockFn(mockResponseStream, written)
+
+			fakeClock := testingclock.NewFakeClock(start)
+			s := NewService(fake.NewSimpleClientset(), nil, nil, Options{HeartbeatInterval: 30 * time.Second})
+			s.clock = fakeClock
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			errCh := make(chan error, 1)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				s.sendHeartbeats(sw.NewStreamWriter(mockResponseStream), ctx.Done(), cancel, errCh)
+			}()
+
+			require.Eventually(t, fakeClock.HasWaiters, wait.ForeverTestTimeout, 10*time.Millisecond)
+			if tt.wantErr {
+				fakeClock.Step(30 * time.Second)
+				select {
+				case err := <-errCh:
+					assert.Error(t, err)
+				case <-time.After(wait.ForeverTestTimeout):
+					t.Fatal("the error isn't returned")
+				}
+				// the watch is canceled to tear down the connection.
+				<-ctx.Done()
+				<-done
+				return
+			}
+
+			// nothing is written before the interval passes.
+			fakeClock.Step(29 * time.Second)
+			select {
+			case <-written:
+				t.Fatal("the heartbeat is written before the interval")
+			case <-time.After(100 * time.Millisecond):
+			}
+			for i := 1; i <= 2; i++ {
+				fakeClock.Step(30 * time.Second)
+				select {
+				case p := <-written:
+					var hb sw.Heartbeat
+					require.NoError(t, json.Unmarshal(p, &hb))
+					assert.Equal(t, sw.HeartbeatEventType, hb.EventType)
+					assert.True(t, hb.Timestamp.After(start))
+				case <-time.After(wait.ForeverTestTimeout):
+					t.Fatalf("the heartbeat %d isn't written", i)
+				}
+			}
+			cancel()
+			<-done
+			assert.Empty(t, errCh)
+		})
+	}
+}
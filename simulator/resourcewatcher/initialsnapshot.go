@@ -0,0 +1,100 @@
+package resourcewatcher
+
+import (
+	"sync"
+
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+)
+
+// snapshotWriter delivers the current objects of the watched kinds as the initial snapshot
+// between SNAPSHOT_BEGIN and SNAPSHOT_END, followed by the live events.
+// The events of a kind are regarded as the snapshot until finish is called for the kind,
+// and the live events of the kinds finished earlier are held until SNAPSHOT_END so that they're sent in order after it.
+type snapshotWriter struct {
+	mu     sync.Mutex
+	writer StreamWriter
+	// pending has the kinds whose snapshot isn't finished yet.
+	pending sets.Set[sw.ResourceKind]
+	counts  map[sw.ResourceKind]int
+	// versions has the version of each kind where the snapshot is taken, which makes the cursor of SNAPSHOT_END.
+	versions map[sw.ResourceKind]string
+	// held has the live events written during the snapshot.
+	held []*sw.WatchEvent
+	done bool
+}
+
+// newSnapshotWriter writes SNAPSHOT_BEGIN and returns the snapshotWriter delivering the snapshot of the kinds.
+// lrVersions is the versions given by the client, which the kinds not listed again are resumed from.
+func newSnapshotWriter(writer StreamWriter, kinds []sw.ResourceKind, lrVersions *LastResourceVersions) (*snapshotWriter, error) {
+	w := &snapshotWriter{
+		writer:   writer,
+		pending:  sets.New(kinds...),
+		counts:   map[sw.ResourceKind]int{},
+		versions: versionsOf(lrVersions),
+	}
+	for _, kind := range kinds {
+		w.counts[kind] = 0
+	}
+	if err := writer.Write(&sw.WatchEvent{EventType: sw.SnapshotBegin}); err != nil {
+		return nil, xerrors.Errorf("write SNAPSHOT_BEGIN: %w", err)
+	}
+	return w, nil
+}
+
+// Write writes we as a part of the snapshot if the snapshot of the kind isn't finished,
+// holds it until SNAPSHOT_END if the snapshot of the other kinds isn't finished, or writes it as a live event otherwise.
+func (w *snapshotWriter) Write(we *sw.WatchEvent) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch {
+	case w.done:
+		return w.writer.Write(we)
+	case w.pending.Has(we.Kind):
+		if we.EventType == watch.Added {
+			w.counts[we.Kind]++
+		}
+		return w.writer.Write(we)
+	default:
+		w.held = append(w.held, we)
+		return nil
+	}
+}
+
+// finish finishes the snapshot of the kind taken at the version, which can be empty if the kind has no version.
+// When the snapshots of all the kinds are finished, it writes SNAPSHOT_END and the held events.
+// It does nothing after SNAPSHOT_END is written, e.g., when the kind is listed again for resync.
+func (w *snapshotWriter) finish(kind sw.ResourceKind, version string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done || !w.pending.Has(kind) {
+		return nil
+	}
+	w.pending.Delete(kind)
+	if version != "" {
+		w.versions[kind] = version
+	}
+	if w.pending.Len() > 0 {
+		return nil
+	}
+
+	w.done = true
+	cursor, err := encodeCursor(w.versions)
+	if err != nil {
+		return err
+	}
+	if err := w.writer.Write(&sw.WatchEvent{EventType: sw.SnapshotEnd, Obj: &sw.SnapshotSummary{Counts: w.counts}, Cursor: cursor}); err != nil {
+		return xerrors.Errorf("write SNAPSHOT_END: %w", err)
+	}
+	held := w.held
+	w.held = nil
+	for _, we := range held {
+		if err := w.writer.Write(we); err != nil {
+			return xerrors.Errorf("write the event held during the snapshot: %w", err)
+		}
+	}
+	return nil
+}
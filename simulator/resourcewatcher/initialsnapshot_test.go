@@ -0,0 +1,117 @@
+package resourcewatcher
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+)
+
+// snapshotRecorder records the events written to it including SNAPSHOT_BEGIN and SNAPSHOT_END.
+type snapshotRecorder struct {
+	mu  sync.Mutex
+	got []*sw.WatchEvent
+}
+
+func (r *snapshotRecorder) Write(we *sw.WatchEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.got = append(r.got, we)
+	return nil
+}
+
+func (r *snapshotRecorder) events() []*sw.WatchEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*sw.WatchEvent{}, r.got...)
+}
+
+func node(name string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func TestSnapshotWriter(t *testing.T) {
+	t.Parallel()
+	rw := &snapshotRecorder{}
+	w, err := newSnapshotWriter(rw, []sw.ResourceKind{Pods, Nodes, Pvs}, &LastResourceVersions{Pvs: "3"})
+	require.NoError(t, err)
+	// the resource resumed from the given version has nothing in the snapshot.
+	require.NoError(t, w.finish(Pvs, "3"))
+
+	require.NoError(t, w.Write(&sw.WatchEvent{Kind: Pods, EventType: watch.Added, Obj: pod("pod1", "1")}))
+	require.NoError(t, w.finish(Nodes, "5"))
+	// the live event of the finished kind is held until the snapshot of the other kinds is finished.
+	require.NoError(t, w.Write(&sw.WatchEvent{Kind: Nodes, EventType: watch.Added, Obj: node("node1")}))
+	require.NoError(t, w.Write(&sw.WatchEvent{Kind: Pvs, EventType: watch.Deleted, Obj: &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv1"}}}))
+	require.NoError(t, w.Write(&sw.WatchEvent{Kind: Pods, EventType: watch.Added, Obj: pod("pod2", "2")}))
+	assert.Len(t, rw.events(), 3, "the held events must not be written before SNAPSHOT_END")
+
+	require.NoError(t, w.finish(Pods, "10"))
+	require.NoError(t, w.Write(&sw.WatchEvent{Kind: Pods, EventType: watch.Modified, Obj: pod("pod1", "11")}))
+	// finishing the kind listed again for resync does nothing.
+	require.NoError(t, w.finish(Pods, "12"))
+
+	got := rw.events()
+	gotTypes := make([]watch.EventType, 0, len(got))
+	for _, we := range got {
+		gotTypes = append(gotTypes, we.EventType)
+	}
+	assert.Equal(t, []watch.EventType{
+		sw.SnapshotBegin,
+		watch.Added,
+		watch.Added,
+		sw.SnapshotEnd,
+		// the held events follow SNAPSHOT_END in the order they're written.
+		watch.Added,
+		watch.Deleted,
+		watch.Modified,
+	}, gotTypes)
+	assert.Equal(t, "pod2", got[2].Obj.(*corev1.Pod).Name)
+	assert.Equal(t, "node1", got[4].Obj.(*corev1.Node).Name)
+
+	end := got[3]
+	assert.Equal(t, &sw.SnapshotSummary{Counts: map[sw.ResourceKind]int{Pods: 2, Nodes: 0, Pvs: 0}}, end.Obj)
+	versions, err := ParseCursor(end.Cursor)
+	require.NoError(t, err)
+	assert.Equal(t, &LastResourceVersions{Pods: "10", Nodes: "5", Pvs: "3"}, versions)
+}
+
+func TestCustomInformers_watch_snapshot(t *testing.T) {
+	t.Parallel()
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{widgetsGVR: "WidgetList"}, widget("widget1"), widget("widget2"))
+	m := newCustomInformers(client)
+	rw := &snapshotRecorder{}
+	kind := gvrKind(widgetsGVR)
+	snapshot, err := newSnapshotWriter(rw, []sw.ResourceKind{kind}, &LastResourceVersions{})
+	require.NoError(t, err)
+	stop, err := m.watch(widgetsGVR, snapshot, snapshot)
+	require.NoError(t, err)
+	defer stop()
+
+	require.Eventually(t, func() bool { return len(rw.events()) == 4 }, wait.ForeverTestTimeout, 10*time.Millisecond)
+	_, err = client.Resource(widgetsGVR).Namespace("default").Create(context.Background(), widget("widget3"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(rw.events()) == 5 }, wait.ForeverTestTimeout, 10*time.Millisecond)
+
+	got := rw.events()
+	assert.Equal(t, sw.SnapshotBegin, got[0].EventType)
+	assert.Equal(t, watch.Added, got[1].EventType)
+	assert.Equal(t, watch.Added, got[2].EventType)
+	assert.Equal(t, sw.SnapshotEnd, got[3].EventType)
+	assert.Equal(t, &sw.SnapshotSummary{Counts: map[sw.ResourceKind]int{kind: 2}}, got[3].Obj)
+	// the object created after the snapshot is sent as the live event.
+	assert.Equal(t, watch.Added, got[4].EventType)
+	assert.Equal(t, kind, got[4].Kind)
+}
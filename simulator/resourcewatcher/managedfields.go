@@ -0,0 +1,31 @@
+package resourcewatcher
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+)
+
+// managedFieldsStripper strips managedFields from the objects in the events, which are bulky and useless for the clients.
+type managedFieldsStripper struct {
+	writer StreamWriter
+}
+
+// Write writes we to the client without managedFields of the object.
+func (w *managedFieldsStripper) Write(we *sw.WatchEvent) error {
+	obj, ok := we.Obj.(runtime.Object)
+	if !ok {
+		return w.writer.Write(we)
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil || len(accessor.GetManagedFields()) == 0 {
+		return w.writer.Write(we)
+	}
+	// The object may be shared with the other clients, so it must not be modified.
+	obj = obj.DeepCopyObject()
+	accessor, _ = meta.Accessor(obj)
+	accessor.SetManagedFields(nil)
+	we.Obj = obj
+	return w.writer.Write(we)
+}
@@ -0,0 +1,30 @@
+package resourcewatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+)
+
+func TestManagedFieldsStripper_Write(t *testing.T) {
+	t.Parallel()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:          "pod1",
+		ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply}},
+	}}
+	rw := &recordingWriter{}
+	w := &managedFieldsStripper{writer: rw}
+	require.NoError(t, w.Write(&sw.WatchEvent{Kind: Pods, EventType: watch.Added, Obj: pod}))
+	require.Len(t, rw.got, 1)
+	got := rw.got[0].Obj.(*corev1.Pod)
+	assert.Equal(t, "pod1", got.Name)
+	assert.Empty(t, got.ManagedFields)
+	// the original object isn't modified.
+	assert.Len(t, pod.ManagedFields, 1)
+}
@@ -0,0 +1,54 @@
+package resourcewatcher
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	queueDepth = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "kube_scheduler_simulator",
+			Name:           "watcher_queue_depth",
+			Help:           "Number of the events pending to be sent to a client watching resources.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"client"})
+
+	droppedEvents = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "kube_scheduler_simulator",
+			Name:           "watcher_dropped_events_total",
+			Help:           "Number of the events dropped because a client watching resources is too slow.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"client", "reason"})
+
+	activeConnections = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      "kube_scheduler_simulator",
+			Name:           "watcher_connections",
+			Help:           "Number of the clients watching resources by the identity of the client.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"identity"})
+
+	writtenEvents = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      "kube_scheduler_simulator",
+			Name:           "watcher_events_written_total",
+			Help:           "Number of the events written to the clients watching resources.",
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	registerMetricsOnce sync.Once
+)
+
+// RegisterMetrics registers the metrics of the clients watching resources to the legacy registry.
+func RegisterMetrics() {
+	registerMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(queueDepth, droppedEvents, activeConnections, writtenEvents)
+	})
+}
@@ -71,6 +71,20 @@ func (mr *MockeventProxyerMockRecorder) listAndHandleItems(lw any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "listAndHandleItems", reflect.TypeOf((*MockeventProxyer)(nil).listAndHandleItems), lw)
 }
 
+// requireResync mocks base method.
+func (m *MockeventProxyer) requireResync() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "requireResync")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// requireResync indicates an expected call of requireResync.
+func (mr *MockeventProxyerMockRecorder) requireResync() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "requireResync", reflect.TypeOf((*MockeventProxyer)(nil).requireResync))
+}
+
 // resourceKind mocks base method.
 func (m *MockeventProxyer) resourceKind() streamwriter.ResourceKind {
 	m.ctrl.T.Helper()
@@ -100,9 +114,11 @@ func (mr *MockeventProxyerMockRecorder) restClient() *gomock.Call {
 }
 
 // watchAndHandleEvent mocks base method.
-func (m *MockeventProxyer) watchAndHandleEvent(watcher watch.Interface, stopCh <-chan struct{}) {
+func (m *MockeventProxyer) watchAndHandleEvent(watcher watch.Interface, stopCh <-chan struct{}) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "watchAndHandleEvent", watcher, stopCh)
+	ret := m.ctrl.Call(m, "watchAndHandleEvent", watcher, stopCh)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // watchAndHandleEvent indicates an expected call of watchAndHandleEvent.
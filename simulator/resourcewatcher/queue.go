@@ -0,0 +1,319 @@
+package resourcewatcher
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/utils/clock"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+)
+
+// OverflowPolicy decides what to do when the queue of a client overflows.
+type OverflowPolicy string
+
+const (
+	// OverflowCoalesce drops the pending MODIFIED event of the same object as the new MODIFIED event,
+	// so that only the latest update is sent. The client is disconnected if there is no event to coalesce.
+	OverflowCoalesce OverflowPolicy = "Coalesce"
+	// OverflowDisconnect disconnects the client with the TOO_SLOW message.
+	OverflowDisconnect OverflowPolicy = "Disconnect"
+)
+
+// DefaultQueueSize is the default number of events which can be pending for a client.
+const DefaultQueueSize = 1000
+
+// errTooSlow is returned when the client is disconnected because it can't keep up with the events.
+var errTooSlow = xerrors.New("client is too slow to receive events")
+
+// queuedWriter queues the events and writes them to the client in its own goroutine,
+// so that a slow client doesn't block watching the resources.
+// The queue is bounded, and the overflow is handled with the OverflowPolicy.
+// If batchInterval is set, the events are written as one frame every interval instead of each by each.
+type queuedWriter struct {
+	mu            sync.Mutex
+	writer        *sw.StreamWriter
+	size          int
+	policy        OverflowPolicy
+	batchInterval time.Duration
+	clock         clock.WithTicker
+	// clientID is used for the metrics.
+	clientID string
+
+	pending []*sw.WatchEvent
+	// notify is signaled when an event is queued or the writer is closed.
+	notify chan struct{}
+	// err is set when the writer no longer accepts the events.
+	err     error
+	dropped int64
+	// sent is the number of the events written to the client.
+	sent int64
+}
+
+func newQueuedWriter(writer *sw.StreamWriter, size int, policy OverflowPolicy, batchInterval time.Duration, clock clock.WithTicker, clientID string) *queuedWriter {
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	if policy == "" {
+		policy = OverflowCoalesce
+	}
+	return &queuedWriter{
+		writer:        writer,
+		size:          size,
+		policy:        policy,
+		batchInterval: batchInterval,
+		clock:         clock,
+		clientID:      clientID,
+		notify:        make(chan struct{}, 1),
+	}
+}
+
+// Write queues we without waiting for it to be written to the client.
+// It returns an error if the client has been disconnected.
+func (q *queuedWriter) Write(we *sw.WatchEvent) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.err != nil {
+		return q.err
+	}
+	if len(q.pending) < q.size {
+		q.pending = append(q.pending, we)
+	} else if !q.coalesce(we) {
+		q.disconnect()
+		return q.err
+	}
+	queueDepth.WithLabelValues(q.clientID).Set(float64(len(q.pending)))
+	q.signal()
+	return nil
+}
+
+// coalesce replaces the pending event of the same object as we with we, if the policy allows it.
+// It must be called with q.mu held.
+func (q *queuedWriter) coalesce(we *sw.WatchEvent) bool {
+	if q.policy != OverflowCoalesce || we.EventType != watch.Modified {
+		return false
+	}
+	key, ok := keyOf(we)
+	if !ok {
+		return false
+	}
+	for _, p := range q.pending {
+		if p.EventType != watch.Added && p.EventType != watch.Modified {
+			continue
+		}
+		if k, ok := keyOf(p); !ok || k != key {
+			continue
+		}
+		// The pending event keeps its type and cursor, since the client hasn't received the object
+		// or the events before the new one yet.
+		p.Obj = we.Obj
+		p.SchedulingResults = we.SchedulingResults
+		q.dropped++
+		droppedEvents.WithLabelValues(q.clientID, "coalesced").Inc()
+		return true
+	}
+	return false
+}
+
+// disconnect discards the pending events, and makes the writer send the TOO_SLOW message and stop.
+// It must be called with q.mu held.
+func (q *queuedWriter) disconnect() {
+	q.dropped += int64(len(q.pending)) + 1
+	droppedEvents.WithLabelValues(q.clientID, "disconnected").Add(float64(len(q.pending)) + 1)
+	q.pending = []*sw.WatchEvent{{EventType: sw.TooSlow}}
+	q.err = errTooSlow
+	q.signal()
+}
+
+// signal wakes up run. It must be called with q.mu held.
+func (q *queuedWriter) signal() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// run writes the queued events to the client until stopCh is closed.
+// It returns an error when writing fails or the client is disconnected by the overflow.
+func (q *queuedWriter) run(stopCh <-chan struct{}) error {
+	// The metrics of the disconnected client are no longer useful.
+	defer func() {
+		queueDepth.Delete(map[string]string{"client": q.clientID})
+		droppedEvents.Delete(map[string]string{"client": q.clientID, "reason": "coalesced"})
+		droppedEvents.Delete(map[string]string{"client": q.clientID, "reason": "disconnected"})
+	}()
+	if q.batchInterval > 0 {
+		return q.runBatches(stopCh)
+	}
+	for {
+		select {
+		case <-stopCh:
+			return nil
+		case <-q.notify:
+		}
+		for {
+			q.mu.Lock()
+			if len(q.pending) == 0 {
+				q.mu.Unlock()
+				break
+			}
+			we := q.pending[0]
+			q.pending[0] = nil
+			q.pending = q.pending[1:]
+			queueDepth.WithLabelValues(q.clientID).Set(float64(len(q.pending)))
+			q.mu.Unlock()
+
+			if err := q.writer.Write(we); err != nil {
+				q.mu.Lock()
+				q.err = err
+				q.mu.Unlock()
+				return xerrors.Errorf("write the queued event: %w", err)
+			}
+			q.written(1)
+			if we.EventType == sw.TooSlow {
+				return errTooSlow
+			}
+		}
+	}
+}
+
+// runBatches writes the events queued in each batchInterval as one frame until stopCh is closed.
+// The TOO_SLOW message is written without waiting for the interval.
+func (q *queuedWriter) runBatches(stopCh <-chan struct{}) error {
+	ticker := q.clock.NewTicker(q.batchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return nil
+		case <-ticker.C():
+		case <-q.notify:
+			q.mu.Lock()
+			disconnected := q.err != nil
+			q.mu.Unlock()
+			if !disconnected {
+				continue
+			}
+		}
+		q.mu.Lock()
+		pending := q.pending
+		q.pending = nil
+		queueDepth.WithLabelValues(q.clientID).Set(0)
+		q.mu.Unlock()
+
+		frame := coalesceFrame(pending)
+		if len(frame) == 0 {
+			continue
+		}
+		if err := q.writer.WriteBatch(frame); err != nil {
+			q.mu.Lock()
+			q.err = err
+			q.mu.Unlock()
+			return xerrors.Errorf("write the queued events: %w", err)
+		}
+		q.written(len(frame))
+		if frame[len(frame)-1].EventType == sw.TooSlow {
+			return errTooSlow
+		}
+	}
+}
+
+// coalesceFrame coalesces the events of the same object in a frame, so that only the latest update survives.
+// The object added and deleted in the frame isn't included at all, since the client has never seen it.
+// The coalesced event is moved to the position of the latest one to keep the cursors in the frame ordered.
+func coalesceFrame(events []*sw.WatchEvent) []*sw.WatchEvent {
+	frame := make([]*sw.WatchEvent, 0, len(events))
+	// last has the index in frame of the last event of each object.
+	last := map[objectKey]int{}
+	for _, we := range events {
+		if we.EventType == sw.ResyncRequired {
+			// The events before it are discarded by the client anyway, but they aren't coalesced across it for simplicity.
+			for k := range last {
+				if k.kind == we.Kind {
+					delete(last, k)
+				}
+			}
+		}
+		key, ok := keyOf(we)
+		if !ok {
+			frame = append(frame, we)
+			continue
+		}
+		i, found := last[key]
+		if !found {
+			last[key] = len(frame)
+			frame = append(frame, we)
+			continue
+		}
+		prev := frame[i]
+		switch {
+		case prev.EventType == watch.Added && we.EventType == watch.Modified:
+			frame[i] = nil
+			last[key] = len(frame)
+			added := *we
+			added.EventType = watch.Added
+			frame = append(frame, &added)
+		case prev.EventType == watch.Added && we.EventType == watch.Deleted:
+			frame[i] = nil
+			delete(last, key)
+		case prev.EventType == watch.Modified && (we.EventType == watch.Modified || we.EventType == watch.Deleted):
+			frame[i] = nil
+			last[key] = len(frame)
+			frame = append(frame, we)
+		default:
+			// e.g., the object is deleted and created again.
+			last[key] = len(frame)
+			frame = append(frame, we)
+		}
+	}
+
+	ret := frame[:0]
+	for _, we := range frame {
+		if we != nil {
+			ret = append(ret, we)
+		}
+	}
+	return ret
+}
+
+// Depth returns the number of the pending events.
+func (q *queuedWriter) Depth() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// written records that n events are written to the client.
+func (q *queuedWriter) written(n int) {
+	q.mu.Lock()
+	q.sent += int64(n)
+	q.mu.Unlock()
+	writtenEvents.Add(float64(n))
+}
+
+// Sent returns the number of the events written to the client.
+func (q *queuedWriter) Sent() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.sent
+}
+
+// Dropped returns the number of the events dropped by the overflow.
+func (q *queuedWriter) Dropped() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+// keyOf returns the key of the object in we.
+func keyOf(we *sw.WatchEvent) (objectKey, bool) {
+	accessor, err := meta.Accessor(we.Obj)
+	if err != nil {
+		return objectKey{}, false
+	}
+	return objectKey{kind: we.Kind, NamespacedName: types.NamespacedName{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}}, true
+}
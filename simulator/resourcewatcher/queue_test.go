@@ -0,0 +1,335 @@
+package resourcewatcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/utils/clock"
+	testingclock "k8s.io/utils/clock/testing"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter/mock_streamwriter"
+)
+
+// queuedEvent is the event decoded from the stream with the name and the resource version of the object.
+type queuedEvent struct {
+	EventType       watch.EventType
+	Name            string
+	ResourceVersion string
+}
+
+// newQueueTestStream returns the mock ResponseStream recording the written events.
+// If block is non-nil, the first Write signals entered and waits for block to be closed.
+func newQueueTestStream(ctrl *gomock.Controller, got chan<- queuedEvent, entered chan<- struct{}, block <-chan struct{}) *mock_streamwriter.MockResponseStream {
+	stream := mock_streamwriter.NewMockResponseStream(ctrl)
+	var once sync.Once
+	stream.EXPECT().Write(gomock.Any()).DoAndReturn(func(p []byte) (int, error) {
+		if block != nil {
+			once.Do(func() {
+				close(entered)
+				<-block
+			})
+		}
+		var e struct {
+			EventType watch.EventType
+			Obj       struct {
+				Metadata metav1.ObjectMeta `json:"metadata"`
+			}
+		}
+		if err := json.NewDecoder(bytes.NewReader(p)).Decode(&e); err != nil {
+			return 0, err
+		}
+		got <- queuedEvent{EventType: e.EventType, Name: e.Obj.Metadata.Name, ResourceVersion: e.Obj.Metadata.ResourceVersion}
+		return len(p), nil
+	}).AnyTimes()
+	stream.EXPECT().Flush().AnyTimes()
+	return stream
+}
+
+func pod(name, rv string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", ResourceVersion: rv}}
+}
+
+func receive(t *testing.T, ch <-chan queuedEvent, n int) []queuedEvent {
+	t.Helper()
+	var ret []queuedEvent
+	for i := 0; i < n; i++ {
+		select {
+		case e := <-ch:
+			ret = append(ret, e)
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatalf("only %d events are received, want %d", len(ret), n)
+		}
+	}
+	return ret
+}
+
+func TestQueuedWriter_slowClient(t *testing.T) {
+	t.Parallel()
+	events := []*sw.WatchEvent{
+		{Kind: Pods, EventType: watch.Added, Obj: pod("pod1", "1")},
+		{Kind: Pods, EventType: watch.Added, Obj: pod("pod2", "2")},
+		{Kind: Pods, EventType: watch.Modified, Obj: pod("pod1", "3")},
+		// the queue of the slow client overflows from here.
+		{Kind: Pods, EventType: watch.Modified, Obj: pod("pod1", "4")},
+		{Kind: Pods, EventType: watch.Modified, Obj: pod("pod2", "5")},
+	}
+	all := []queuedEvent{
+		{EventType: watch.Added, Name: "pod1", ResourceVersion: "1"},
+		{EventType: watch.Added, Name: "pod2", ResourceVersion: "2"},
+		{EventType: watch.Modified, Name: "pod1", ResourceVersion: "3"},
+		{EventType: watch.Modified, Name: "pod1", ResourceVersion: "4"},
+		{EventType: watch.Modified, Name: "pod2", ResourceVersion: "5"},
+	}
+
+	tests := []struct {
+		name        string
+		policy      OverflowPolicy
+		wantSlow    []queuedEvent
+		wantDropped int64
+		wantErr     error
+	}{
+		{
+			name:   "the intermediate updates are coalesced",
+			policy: OverflowCoalesce,
+			wantSlow: []queuedEvent{
+				{EventType: watch.Added, Name: "pod1", ResourceVersion: "1"},
+				// the pending ADDED event has the latest object.
+				{EventType: watch.Added, Name: "pod2", ResourceVersion: "5"},
+				{EventType: watch.Modified, Name: "pod1", ResourceVersion: "4"},
+			},
+			wantDropped: 2,
+		},
+		{
+			name:   "the slow client is disconnected",
+			policy: OverflowDisconnect,
+			wantSlow: []queuedEvent{
+				{EventType: watch.Added, Name: "pod1", ResourceVersion: "1"},
+				{EventType: sw.TooSlow},
+			},
+			wantDropped: 3,
+			wantErr:     errTooSlow,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			slowGot := make(chan queuedEvent, len(events)+1)
+			entered := make(chan struct{})
+			block := make(chan struct{})
+			slow := newQueuedWriter(sw.NewStreamWriter(newQueueTestStream(ctrl, slowGot, entered, block)), 2, tt.policy, 0, clock.RealClock{}, "slow")
+			fastGot := make(chan queuedEvent, len(events))
+			fast := newQueuedWriter(sw.NewStreamWriter(newQueueTestStream(ctrl, fastGot, nil, nil)), 2, tt.policy, 0, clock.RealClock{}, "fast")
+			slowErr := make(chan error, 1)
+			go func() { slowErr <- slow.run(ctx.Done()) }()
+			go func() { _ = fast.run(ctx.Done()) }()
+
+			for i, e := range events {
+				e2 := *e
+				err := slow.Write(&e2)
+				if i == 0 {
+					// wait for the slow client to be stuck in writing the first event.
+					<-entered
+				}
+				if i >= 3 && tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				} else {
+					assert.NoError(t, err)
+				}
+				// the fast client receives each event even while the slow client is stuck.
+				e3 := *e
+				require.NoError(t, fast.Write(&e3))
+				assert.Equal(t, all[i], receive(t, fastGot, 1)[0])
+			}
+
+			assert.Equal(t, tt.wantDropped, slow.Dropped())
+			close(block)
+			assert.Equal(t, tt.wantSlow, receive(t, slowGot, len(tt.wantSlow)))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, <-slowErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCoalesceFrame(t *testing.T) {
+	t.Parallel()
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", ResourceVersion: "9"}}
+	tests := []struct {
+		name   string
+		events []*sw.WatchEvent
+		want   []*sw.WatchEvent
+	}{
+		{
+			name: "only the latest update survives",
+			events: []*sw.WatchEvent{
+				{Kind: Pods, EventType: watch.Modified, Obj: pod("pod1", "1"), Cursor: "1"},
+				{Kind: Pods, EventType: watch.Modified, Obj: pod("pod2", "2"), Cursor: "2"},
+				{Kind: Pods, EventType: watch.Modified, Obj: pod("pod1", "3"), Cursor: "3"},
+			},
+			want: []*sw.WatchEvent{
+				{Kind: Pods, EventType: watch.Modified, Obj: pod("pod2", "2"), Cursor: "2"},
+				{Kind: Pods, EventType: watch.Modified, Obj: pod("pod1", "3"), Cursor: "3"},
+			},
+		},
+		{
+			name: "the added object keeps ADDED with the latest object",
+			events: []*sw.WatchEvent{
+				{Kind: Pods, EventType: watch.Added, Obj: pod("pod1", "1"), Cursor: "1"},
+				{Kind: Pods, EventType: watch.Modified, Obj: pod("pod1", "2"), Cursor: "2"},
+				{Kind: Pods, EventType: watch.Modified, Obj: pod("pod1", "3"), Cursor: "3"},
+			},
+			want: []*sw.WatchEvent{
+				{Kind: Pods, EventType: watch.Added, Obj: pod("pod1", "3"), Cursor: "3"},
+			},
+		},
+		{
+			name: "ADDED followed by DELETED collapses to nothing",
+			events: []*sw.WatchEvent{
+				{Kind: Pods, EventType: watch.Added, Obj: pod("pod1", "1"), Cursor: "1"},
+				{Kind: Nodes, EventType: watch.Modified, Obj: node, Cursor: "2"},
+				{Kind: Pods, EventType: watch.Modified, Obj: pod("pod1", "3"), Cursor: "3"},
+				{Kind: Pods, EventType: watch.Deleted, Obj: pod("pod1", "4"), Cursor: "4"},
+			},
+			want: []*sw.WatchEvent{
+				{Kind: Nodes, EventType: watch.Modified, Obj: node, Cursor: "2"},
+			},
+		},
+		{
+			name: "MODIFIED followed by DELETED results in DELETED",
+			events: []*sw.WatchEvent{
+				{Kind: Pods, EventType: watch.Modified, Obj: pod("pod1", "1"), Cursor: "1"},
+				{Kind: Pods, EventType: watch.Deleted, Obj: pod("pod1", "2"), Cursor: "2"},
+			},
+			want: []*sw.WatchEvent{
+				{Kind: Pods, EventType: watch.Deleted, Obj: pod("pod1", "2"), Cursor: "2"},
+			},
+		},
+		{
+			name: "the object deleted and created again isn't coalesced",
+			events: []*sw.WatchEvent{
+				{Kind: Pods, EventType: watch.Deleted, Obj: pod("pod1", "1"), Cursor: "1"},
+				{Kind: Pods, EventType: watch.Added, Obj: pod("pod1", "2"), Cursor: "2"},
+			},
+			want: []*sw.WatchEvent{
+				{Kind: Pods, EventType: watch.Deleted, Obj: pod("pod1", "1"), Cursor: "1"},
+				{Kind: Pods, EventType: watch.Added, Obj: pod("pod1", "2"), Cursor: "2"},
+			},
+		},
+		{
+			name: "the events aren't coalesced across RESYNC_REQUIRED",
+			events: []*sw.WatchEvent{
+				{Kind: Pods, EventType: watch.Added, Obj: pod("pod1", "1"), Cursor: "1"},
+				{Kind: Pods, EventType: sw.ResyncRequired},
+				{Kind: Pods, EventType: watch.Deleted, Obj: pod("pod1", "2"), Cursor: "2"},
+			},
+			want: []*sw.WatchEvent{
+				{Kind: Pods, EventType: watch.Added, Obj: pod("pod1", "1"), Cursor: "1"},
+				{Kind: Pods, EventType: sw.ResyncRequired},
+				{Kind: Pods, EventType: watch.Deleted, Obj: pod("pod1", "2"), Cursor: "2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, coalesceFrame(tt.events))
+		})
+	}
+}
+
+func TestQueuedWriter_batch(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	frames := make(chan []queuedEvent, 3)
+	stream := mock_streamwriter.NewMockResponseStream(ctrl)
+	stream.EXPECT().Write(gomock.Any()).DoAndReturn(func(p []byte) (int, error) {
+		var es []struct {
+			EventType watch.EventType
+			Obj       struct {
+				Metadata metav1.ObjectMeta `json:"metadata"`
+			}
+		}
+		if err := json.Unmarshal(p, &es); err != nil {
+			return 0, err
+		}
+		frame := make([]queuedEvent, 0, len(es))
+		for _, e := range es {
+			frame = append(frame, queuedEvent{EventType: e.EventType, Name: e.Obj.Metadata.Name, ResourceVersion: e.Obj.Metadata.ResourceVersion})
+		}
+		frames <- frame
+		return len(p), nil
+	}).AnyTimes()
+	stream.EXPECT().Flush().AnyTimes()
+
+	fakeClock := testingclock.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	q := newQueuedWriter(sw.NewStreamWriter(stream), 10, OverflowCoalesce, 200*time.Millisecond, fakeClock, "batch")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- q.run(ctx.Done()) }()
+	require.Eventually(t, fakeClock.HasWaiters, wait.ForeverTestTimeout, 10*time.Millisecond)
+
+	noFrame := func() {
+		t.Helper()
+		select {
+		case f := <-frames:
+			t.Fatalf("unexpected frame is written: %v", f)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	receiveFrame := func() []queuedEvent {
+		t.Helper()
+		select {
+		case f := <-frames:
+			return f
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatal("the frame isn't written")
+		}
+		return nil
+	}
+
+	require.NoError(t, q.Write(&sw.WatchEvent{Kind: Pods, EventType: watch.Added, Obj: pod("pod1", "1")}))
+	require.NoError(t, q.Write(&sw.WatchEvent{Kind: Pods, EventType: watch.Added, Obj: pod("pod2", "2")}))
+	require.NoError(t, q.Write(&sw.WatchEvent{Kind: Pods, EventType: watch.Modified, Obj: pod("pod1", "3")}))
+	// nothing is written before the interval passes.
+	noFrame()
+	fakeClock.Step(200 * time.Millisecond)
+	assert.Equal(t, []queuedEvent{
+		{EventType: watch.Added, Name: "pod2", ResourceVersion: "2"},
+		{EventType: watch.Added, Name: "pod1", ResourceVersion: "3"},
+	}, receiveFrame())
+
+	// the events in the next interval are written as the next frame.
+	require.NoError(t, q.Write(&sw.WatchEvent{Kind: Pods, EventType: watch.Added, Obj: pod("pod3", "4")}))
+	require.NoError(t, q.Write(&sw.WatchEvent{Kind: Pods, EventType: watch.Modified, Obj: pod("pod2", "5")}))
+	require.NoError(t, q.Write(&sw.WatchEvent{Kind: Pods, EventType: watch.Deleted, Obj: pod("pod3", "6")}))
+	fakeClock.Step(200 * time.Millisecond)
+	assert.Equal(t, []queuedEvent{
+		{EventType: watch.Modified, Name: "pod2", ResourceVersion: "5"},
+	}, receiveFrame())
+
+	// the empty frame isn't written.
+	fakeClock.Step(200 * time.Millisecond)
+	noFrame()
+
+	cancel()
+	assert.NoError(t, <-done)
+}
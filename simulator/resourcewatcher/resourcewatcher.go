@@ -4,17 +4,27 @@ package resourcewatcher
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"time"
 
 	"golang.org/x/xerrors"
 	corev1 "k8s.io/api/core/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	watchtools "k8s.io/client-go/tools/watch"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 
 	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
 )
@@ -45,37 +55,166 @@ type StreamWriter interface {
 	Write(we *sw.WatchEvent) error
 }
 
+// Options is the options for Service.
+type Options struct {
+	// HeartbeatInterval is the interval to send the heartbeat to each client to keep the idle stream alive.
+	// The heartbeat isn't sent if zero.
+	HeartbeatInterval time.Duration
+	// QueueSize is the number of events which can be pending for each client.
+	// DefaultQueueSize is used if zero.
+	QueueSize int
+	// OverflowPolicy decides what to do when the events pending for a client exceed QueueSize.
+	// OverflowCoalesce is used if empty.
+	OverflowPolicy OverflowPolicy
+}
+
+// WatchOptions is the options for each client of ListWatch.
+type WatchOptions struct {
+	// Filter restricts the events sent to the client. All events are sent if nil.
+	Filter *Filter
+	// BatchInterval is the interval to write the events to the client as one frame.
+	// Each event is written as soon as it's notified if zero.
+	BatchInterval time.Duration
+	// StripResultAnnotations strips the scheduling result annotations from the Pods,
+	// since the results are sent as SchedulingResults of the events.
+	StripResultAnnotations bool
+	// KeepManagedFields keeps managedFields of the objects, which are stripped by default since they're bulky.
+	KeepManagedFields bool
+	// GVRs is the additional resources to watch, e.g. the custom resources.
+	// They must be validated with ValidateGVRs.
+	GVRs []schema.GroupVersionResource
+	// Identity is the identity of the authenticated client, which is attached to the logs and the metrics.
+	// It's empty if the client isn't authenticated.
+	Identity string
+	// RemoteAddr is the address of the client shown in Watchers.
+	RemoteAddr string
+}
+
 // Service watches simulator's resources.
 type Service struct {
-	client clientset.Interface
+	client            clientset.Interface
+	restMapper        meta.RESTMapper
+	customInformers   *customInformers
+	heartbeatInterval time.Duration
+	queueSize         int
+	overflowPolicy    OverflowPolicy
+	clock             clock.WithTicker
+	// lastClientID is used to identify each client in the metrics.
+	lastClientID atomic.Uint64
+	// connections keeps the clients watching resources for Watchers and Disconnect.
+	connections connections
 }
 
 // NewService initializes Service.
-func NewService(client clientset.Interface) *Service {
+func NewService(client clientset.Interface, dynamicClient dynamic.Interface, restMapper meta.RESTMapper, options Options) *Service {
 	return &Service{
-		client: client,
+		client:            client,
+		restMapper:        restMapper,
+		customInformers:   newCustomInformers(dynamicClient),
+		heartbeatInterval: options.HeartbeatInterval,
+		queueSize:         options.QueueSize,
+		overflowPolicy:    options.OverflowPolicy,
+		clock:             clock.RealClock{},
 	}
 }
 
 // ListWatch watches each simulator's resources and send notified events to the frontend continuously.
-func (s *Service) ListWatch(ctx context.Context, stream sw.ResponseStream, lrVersions *LastResourceVersions) error {
-	sw := sw.NewStreamWriter(stream)
-	proxies := []*eventProxy{
-		neweventProxy(sw, s.client.CoreV1().RESTClient(), Pods, &corev1.Pod{}, lrVersions.Pods),
-		neweventProxy(sw, s.client.CoreV1().RESTClient(), Nodes, &corev1.Node{}, lrVersions.Nodes),
-		neweventProxy(sw, s.client.CoreV1().RESTClient(), Pvs, &corev1.PersistentVolume{}, lrVersions.Pvs),
-		neweventProxy(sw, s.client.CoreV1().RESTClient(), Pvcs, &corev1.PersistentVolumeClaim{}, lrVersions.Pvcs),
-		neweventProxy(sw, s.client.StorageV1().RESTClient(), Scs, &storagev1.StorageClass{}, lrVersions.Scs),
-		neweventProxy(sw, s.client.SchedulingV1().RESTClient(), Pcs, &schedulingv1.PriorityClass{}, lrVersions.Pcs),
-		neweventProxy(sw, s.client.CoreV1().RESTClient(), Namespaces, &corev1.Namespace{}, lrVersions.Namespaces),
+// The events sent are restricted and batched with opts.
+func (s *Service) ListWatch(ctx context.Context, stream sw.ResponseStream, lrVersions *LastResourceVersions, opts WatchOptions) error {
+	clientID := strconv.FormatUint(s.lastClientID.Add(1), 10)
+	klog.Infof("client %s (identity %q) starts watching resources", clientID, opts.Identity)
+	defer klog.Infof("client %s (identity %q) stops watching resources", clientID, opts.Identity)
+	activeConnections.WithLabelValues(opts.Identity).Inc()
+	defer activeConnections.WithLabelValues(opts.Identity).Dec()
+
+	streamWriter := sw.NewStreamWriter(stream)
+	// The events are written through the queue so that this client being slow doesn't block watching.
+	queue := newQueuedWriter(streamWriter, s.queueSize, s.overflowPolicy, opts.BatchInterval, s.clock, clientID)
+	conn := newConnection(clientID, opts, queue, metav1.NewTime(s.clock.Now()))
+	s.connections.add(conn)
+	defer s.connections.remove(clientID)
+	var writer StreamWriter = queue
+	if !opts.KeepManagedFields {
+		writer = &managedFieldsStripper{writer: writer}
+	}
+	writer = newResultsWriter(writer, opts.StripResultAnnotations)
+	filter := opts.Filter
+	var kinds []sw.ResourceKind
+	for _, kind := range sets.List(AllKinds) {
+		if filter.watches(kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	for _, gvr := range opts.GVRs {
+		kinds = append(kinds, gvrKind(gvr))
+	}
+	snapshot, err := newSnapshotWriter(writer, kinds, lrVersions)
+	if err != nil {
+		return xerrors.Errorf("start snapshot: %w", err)
+	}
+	writer = snapshot
+	if filter != nil {
+		writer = newFilteredWriter(writer, filter)
+	}
+	allProxies := []*eventProxy{
+		neweventProxy(writer, s.client.CoreV1().RESTClient(), Pods, &corev1.Pod{}, lrVersions.Pods),
+		neweventProxy(writer, s.client.CoreV1().RESTClient(), Nodes, &corev1.Node{}, lrVersions.Nodes),
+		neweventProxy(writer, s.client.CoreV1().RESTClient(), Pvs, &corev1.PersistentVolume{}, lrVersions.Pvs),
+		neweventProxy(writer, s.client.CoreV1().RESTClient(), Pvcs, &corev1.PersistentVolumeClaim{}, lrVersions.Pvcs),
+		neweventProxy(writer, s.client.StorageV1().RESTClient(), Scs, &storagev1.StorageClass{}, lrVersions.Scs),
+		neweventProxy(writer, s.client.SchedulingV1().RESTClient(), Pcs, &schedulingv1.PriorityClass{}, lrVersions.Pcs),
+		neweventProxy(writer, s.client.CoreV1().RESTClient(), Namespaces, &corev1.Namespace{}, lrVersions.Namespaces),
+	}
+	c := newCursor(lrVersions)
+	proxies := make([]*eventProxy, 0, len(allProxies))
+	for _, p := range allProxies {
+		if !filter.watches(p.resourceKind()) {
+			continue
+		}
+		p.cursor = c
+		p.snapshot = snapshot
+		proxies = append(proxies, p)
+	}
+	for _, gvr := range opts.GVRs {
+		stop, err := s.customInformers.watch(gvr, writer, snapshot)
+		if err != nil {
+			return xerrors.Errorf("watch %s: %w", gvr, err)
+		}
+		defer stop()
+	}
+	for _, p := range proxies {
+		// The resource resumed from the given version isn't listed, so it has nothing to send in the snapshot.
+		if p.lastResourceVersion() != "" {
+			if err := snapshot.finish(p.resourceKind(), p.lastResourceVersion()); err != nil {
+				return xerrors.Errorf("finish snapshot: %w", err)
+			}
+		}
 	}
 	runctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	for _, p := range proxies {
 		go s.run(p, runctx.Done(), cancel)
 	}
+	// errCh receives the errors to tear down the connection from the queue and the heartbeat.
+	errCh := make(chan error, 2)
+	go func() {
+		if err := queue.run(runctx.Done()); err != nil {
+			errCh <- xerrors.Errorf("write events: %w", err)
+			cancel()
+		}
+	}()
+	if s.heartbeatInterval > 0 {
+		go s.sendHeartbeats(streamWriter, runctx.Done(), cancel, errCh)
+	}
 
 	select {
+	case err := <-errCh:
+		// The write fails when the connection is dead, or the client is too slow.
+		// Stop watching promptly, rather than waiting for the next event.
+		return err
+	case <-conn.disconnectCh:
+		klog.Infof("client %s (identity %q) is disconnected forcibly", clientID, opts.Identity)
+		return nil
 	case <-runctx.Done():
 		// ruuctx monitors s.Run (ListAndWatch) for each resource.
 		// If some error occurs in the process before starting the watch,
@@ -89,6 +228,25 @@ func (s *Service) ListWatch(ctx context.Context, stream sw.ResponseStream, lrVer
 	}
 }
 
+// sendHeartbeats sends the heartbeat to the writer every heartbeatInterval until stopCh is closed.
+// If it fails, the error is sent to errCh and cancel is called to stop watching.
+func (s *Service) sendHeartbeats(writer *sw.StreamWriter, stopCh <-chan struct{}, cancel context.CancelFunc, errCh chan<- error) {
+	ticker := s.clock.NewTicker(s.heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C():
+			if err := writer.WriteHeartbeat(s.clock.Now()); err != nil {
+				errCh <- xerrors.Errorf("send heartbeat: %w", err)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
 // run runs doListAndWatch method.
 // If an error is returned, call cancel to abort ListAndWatch of other resources being processed in parallel.
 func (s *Service) run(p *eventProxy, stopCh <-chan struct{}, cancel context.CancelFunc) {
@@ -103,20 +261,30 @@ func (s *Service) run(p *eventProxy, stopCh <-chan struct{}, cancel context.Canc
 // ListAndWatch runs list and watch on the target resource. The list is not always ran
 // This method returns error unless an error occurs in the watch. If an error occurs in the watch,
 // it outputs a log and re-run the watch.
+// If the lastResourceVersion is too old to watch from, it tells the client to resync and runs list and watch again.
 func (s *Service) doListAndWatch(p eventProxyer, stopCh <-chan struct{}) error {
 	lw := createListWatch(p)
-	// If the lastResourceVersion isn't specified by client, call the list and return the result as ADDED event first.
-	if p.lastResourceVersion() == "" {
-		if err := p.listAndHandleItems(lw); err != nil {
-			return xerrors.Errorf("call listAndHandleItems for %s: %w", p.resourceKind(), err)
+	for {
+		// If the lastResourceVersion isn't specified by client, call the list and return the result as ADDED event first.
+		if p.lastResourceVersion() == "" {
+			if err := p.listAndHandleItems(lw); err != nil {
+				return xerrors.Errorf("call listAndHandleItems for %s: %w", p.resourceKind(), err)
+			}
+		}
+		watcher, err := createWatcher(p, lw)
+		if err != nil {
+			return xerrors.Errorf("call createWatcher for %s: %w", p.resourceKind(), err)
+		}
+		err = p.watchAndHandleEvent(watcher, stopCh)
+		watcher.Stop()
+		if !errors.Is(err, errResourceExpired) {
+			return nil
+		}
+		klog.Infof("resync %s since the resource version is too old: %v", p.resourceKind(), err)
+		if err := p.requireResync(); err != nil {
+			return xerrors.Errorf("call requireResync for %s: %w", p.resourceKind(), err)
 		}
 	}
-	watcher, err := createWatcher(p, lw)
-	if err != nil {
-		return xerrors.Errorf("call createWatcher for %s: %w", p.resourceKind(), err)
-	}
-	p.watchAndHandleEvent(watcher, stopCh)
-	return nil
 }
 
 // createListWatch creates and returns ListWatch.
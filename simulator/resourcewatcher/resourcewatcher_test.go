@@ -127,6 +127,29 @@ func TestService_doListAndWatch(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "should require resync and list again when the lastResourceVersion is expired",
+			prepareFakeClientSetFn: func() *fake.Clientset {
+				return fake.NewSimpleClientset()
+			},
+			prepareFakeRestClientFn: func() *restfake.RESTClient {
+				return &restfake.RESTClient{}
+			},
+			prepareeventProxyerMockFn: func(p *MockeventProxyer, getter cache.Getter) {
+				p.EXPECT().restClient().Return(getter)
+				p.EXPECT().resourceKind().Return(Pods).AnyTimes()
+				gomock.InOrder(
+					p.EXPECT().lastResourceVersion().Return("1").Times(2),
+					p.EXPECT().watchAndHandleEvent(gomock.Any(), gomock.Any()).Return(errResourceExpired),
+					p.EXPECT().requireResync().Return(nil),
+					p.EXPECT().lastResourceVersion().Return(""),
+					p.EXPECT().listAndHandleItems(gomock.Any()).Return(nil),
+					p.EXPECT().lastResourceVersion().Return("2"),
+					p.EXPECT().watchAndHandleEvent(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+			wantErr: false,
+		},
 		{
 			name: "should return an error when listAndHandleItems method return an error",
 			prepareFakeClientSetFn: func() *fake.Clientset {
@@ -151,7 +174,7 @@ func TestService_doListAndWatch(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			mockProxy := NewMockeventProxyer(ctrl)
 			fakeClientSet := tt.prepareFakeClientSetFn()
-			s := NewService(fakeClientSet)
+			s := NewService(fakeClientSet, nil, nil, Options{})
 			fakeRestClient := tt.prepareFakeRestClientFn()
 			tt.prepareeventProxyerMockFn(mockProxy, fakeRestClient)
 
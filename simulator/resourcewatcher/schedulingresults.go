@@ -0,0 +1,98 @@
+package resourcewatcher
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+	extenderannotation "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender/annotation"
+	pluginannotation "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/annotation"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/storereflector"
+)
+
+// resultAnnotationKeys is the keys of the annotations which the simulator puts the scheduling results on the Pods.
+var resultAnnotationKeys = []string{
+	pluginannotation.PreFilterStatusResultAnnotationKey,
+	pluginannotation.PreFilterResultAnnotationKey,
+	pluginannotation.FilterResultAnnotationKey,
+	pluginannotation.PostFilterResultAnnotationKey,
+	pluginannotation.PreScoreResultAnnotationKey,
+	pluginannotation.ScoreResultAnnotationKey,
+	pluginannotation.FinalScoreResultAnnotationKey,
+	pluginannotation.TotalScoreResultAnnotationKey,
+	pluginannotation.ReserveResultAnnotationKey,
+	pluginannotation.PermitStatusResultAnnotationKey,
+	pluginannotation.PermitTimeoutResultAnnotationKey,
+	pluginannotation.PreBindResultAnnotationKey,
+	pluginannotation.BindResultAnnotationKey,
+	pluginannotation.PreemptionResultAnnotationKey,
+	pluginannotation.PluginDurationAnnotationKey,
+	pluginannotation.QueueingHintResultAnnotationKey,
+	pluginannotation.SelectedNodeAnnotationKey,
+	extenderannotation.ExtenderFilterResultAnnotationKey,
+	extenderannotation.ExtenderPrioritizeResultAnnotationKey,
+	extenderannotation.ExtenderPreemptResultAnnotationKey,
+	extenderannotation.ExtenderBindResultAnnotationKey,
+	extenderannotation.ExtenderIgnoredResultAnnotationKey,
+	extenderannotation.ExtenderSkippedResultAnnotationKey,
+	extenderannotation.ExtenderInjectedResultAnnotationKey,
+	extenderannotation.ExtenderRawExchangeAnnotationKey,
+	storereflector.ResultsHistoryAnnotation,
+}
+
+// schedulingResults parses the scheduling results in the annotations of the Pod.
+// The annotation which isn't valid JSON, e.g. the selected node name, is kept as a JSON string.
+// It returns nil if the Pod has no results.
+func schedulingResults(pod *corev1.Pod) sw.SchedulingResults {
+	var results sw.SchedulingResults
+	for _, key := range resultAnnotationKeys {
+		v, ok := pod.Annotations[key]
+		if !ok {
+			continue
+		}
+		if results == nil {
+			results = sw.SchedulingResults{}
+		}
+		if json.Valid([]byte(v)) {
+			results[key] = json.RawMessage(v)
+			continue
+		}
+		// Marshaling a string never fails.
+		s, _ := json.Marshal(v)
+		results[key] = s
+	}
+	return results
+}
+
+// resultsWriter puts the scheduling results of the Pods in the events as SchedulingResults,
+// and strips the result annotations from the Pods if strip is true.
+type resultsWriter struct {
+	writer StreamWriter
+	strip  bool
+}
+
+func newResultsWriter(writer StreamWriter, strip bool) *resultsWriter {
+	return &resultsWriter{
+		writer: writer,
+		strip:  strip,
+	}
+}
+
+// Write writes we to the client with the scheduling results if it's the event of a Pod.
+func (w *resultsWriter) Write(we *sw.WatchEvent) error {
+	pod, ok := we.Obj.(*corev1.Pod)
+	if !ok {
+		return w.writer.Write(we)
+	}
+	we.SchedulingResults = schedulingResults(pod)
+	if w.strip && we.SchedulingResults != nil {
+		// The object may be shared with the other clients, so it must not be modified.
+		pod = pod.DeepCopy()
+		for _, key := range resultAnnotationKeys {
+			delete(pod.Annotations, key)
+		}
+		we.Obj = pod
+	}
+	return w.writer.Write(we)
+}
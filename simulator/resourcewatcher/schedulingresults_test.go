@@ -0,0 +1,103 @@
+package resourcewatcher
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+	pluginannotation "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/annotation"
+)
+
+// recordingWriter records the events written to it.
+type recordingWriter struct {
+	got []*sw.WatchEvent
+}
+
+func (w *recordingWriter) Write(we *sw.WatchEvent) error {
+	w.got = append(w.got, we)
+	return nil
+}
+
+func TestResultsWriter_Write(t *testing.T) {
+	t.Parallel()
+	annotations := map[string]string{
+		pluginannotation.FilterResultAnnotationKey: `{"node1":{"NodeName":"passed"}}`,
+		// the annotation which isn't valid JSON.
+		pluginannotation.SelectedNodeAnnotationKey: "node1",
+		"user-annotation":                          "value",
+	}
+	tests := []struct {
+		name            string
+		obj             interface{}
+		strip           bool
+		wantResults     map[string]interface{}
+		wantAnnotations map[string]string
+	}{
+		{
+			name:  "the results are parsed and the annotations are kept",
+			obj:   &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Annotations: annotations}},
+			strip: false,
+			wantResults: map[string]interface{}{
+				pluginannotation.FilterResultAnnotationKey: map[string]interface{}{"node1": map[string]interface{}{"NodeName": "passed"}},
+				pluginannotation.SelectedNodeAnnotationKey: "node1",
+			},
+			wantAnnotations: annotations,
+		},
+		{
+			name:  "the result annotations are stripped",
+			obj:   &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Annotations: annotations}},
+			strip: true,
+			wantResults: map[string]interface{}{
+				pluginannotation.FilterResultAnnotationKey: map[string]interface{}{"node1": map[string]interface{}{"NodeName": "passed"}},
+				pluginannotation.SelectedNodeAnnotationKey: "node1",
+			},
+			wantAnnotations: map[string]string{"user-annotation": "value"},
+		},
+		{
+			name:            "the pod without results has no results",
+			obj:             &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Annotations: map[string]string{"user-annotation": "value"}}},
+			strip:           true,
+			wantResults:     nil,
+			wantAnnotations: map[string]string{"user-annotation": "value"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rw := &recordingWriter{}
+			w := newResultsWriter(rw, tt.strip)
+			require.NoError(t, w.Write(&sw.WatchEvent{Kind: Pods, EventType: watch.Added, Obj: tt.obj}))
+			require.Len(t, rw.got, 1)
+
+			// check the results as the client decodes them.
+			b, err := json.Marshal(rw.got[0])
+			require.NoError(t, err)
+			var decoded struct {
+				SchedulingResults map[string]interface{} `json:"schedulingResults"`
+				Obj               corev1.Pod
+			}
+			require.NoError(t, json.Unmarshal(b, &decoded))
+			assert.Equal(t, tt.wantResults, decoded.SchedulingResults)
+			assert.Equal(t, tt.wantAnnotations, decoded.Obj.Annotations)
+			// the original object isn't modified.
+			assert.Len(t, annotations, 3)
+		})
+	}
+}
+
+func TestResultsWriter_Write_notPod(t *testing.T) {
+	t.Parallel()
+	rw := &recordingWriter{}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Annotations: map[string]string{pluginannotation.SelectedNodeAnnotationKey: "node1"}}}
+	require.NoError(t, newResultsWriter(rw, true).Write(&sw.WatchEvent{Kind: Nodes, EventType: watch.Added, Obj: node}))
+	require.Len(t, rw.got, 1)
+	assert.Nil(t, rw.got[0].SchedulingResults)
+	assert.Same(t, node, rw.got[0].Obj)
+}
@@ -0,0 +1,88 @@
+package streamwriter
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// GzipEncoding is the Content-Encoding of the stream compressed with gzip.
+	GzipEncoding = "gzip"
+	// DeflateEncoding is the Content-Encoding of the stream compressed with deflate.
+	// It's the zlib format (RFC 1950) in HTTP, not the raw deflate (RFC 1951).
+	DeflateEncoding = "deflate"
+)
+
+// compressor is the writer of gzip or deflate.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// CompressedStream is a ResponseStream which compresses the data written to the underlying ResponseStream.
+// Each Flush flushes the compressed data written so far, so that each WatchEvent arrives at the client promptly.
+type CompressedStream struct {
+	mu     sync.Mutex
+	stream ResponseStream
+	writer compressor
+	// err is the error in the last Flush.
+	// http.Flusher can't return an error, so it's returned from the next Write instead.
+	err error
+}
+
+// NewCompressedStream creates a CompressedStream compressing the data with the encoding, GzipEncoding or DeflateEncoding.
+func NewCompressedStream(stream ResponseStream, encoding string) (*CompressedStream, error) {
+	var writer compressor
+	switch encoding {
+	case GzipEncoding:
+		writer = gzip.NewWriter(stream)
+	case DeflateEncoding:
+		writer = zlib.NewWriter(stream)
+	default:
+		return nil, xerrors.Errorf("unsupported encoding %q", encoding)
+	}
+	return &CompressedStream{
+		stream: stream,
+		writer: writer,
+	}, nil
+}
+
+// Write compresses p. The compressed data may be buffered until Flush is called.
+func (s *CompressedStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	return s.writer.Write(p)
+}
+
+// Flush writes the compressed data buffered to the underlying ResponseStream and flushes it.
+func (s *CompressedStream) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	if err := s.writer.Flush(); err != nil {
+		s.err = xerrors.Errorf("flush the compressed data: %w", err)
+		return
+	}
+	s.stream.Flush()
+}
+
+// Close writes the end of the compressed stream and flushes it.
+// The underlying ResponseStream isn't closed.
+func (s *CompressedStream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.writer.Close(); err != nil {
+		return xerrors.Errorf("close the compressed stream: %w", err)
+	}
+	s.stream.Flush()
+	return nil
+}
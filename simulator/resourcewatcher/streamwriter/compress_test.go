@@ -0,0 +1,105 @@
+package streamwriter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter/mock_streamwriter"
+)
+
+func TestCompressedStream(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		encoding     string
+		decompressor func(r io.Reader) (io.Reader, error)
+	}{
+		{
+			name:     "gzip",
+			encoding: GzipEncoding,
+			decompressor: func(r io.Reader) (io.Reader, error) {
+				return gzip.NewReader(r)
+			},
+		},
+		{
+			name:     "deflate",
+			encoding: DeflateEncoding,
+			decompressor: func(r io.Reader) (io.Reader, error) {
+				return zlib.NewReader(r)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			var buf bytes.Buffer
+			flushed := 0
+			rs := mock_streamwriter.NewMockResponseStream(ctrl)
+			rs.EXPECT().Write(gomock.Any()).DoAndReturn(buf.Write).AnyTimes()
+			rs.EXPECT().Flush().Do(func() { flushed++ }).AnyTimes()
+
+			stream, err := NewCompressedStream(rs, tt.encoding)
+			require.NoError(t, err)
+			writer := NewStreamWriter(stream)
+			// the client reads the stream concurrently with the writes.
+			pr, pw := io.Pipe()
+			decoded := make(chan WatchEvent)
+			go func() {
+				r, err := tt.decompressor(pr)
+				if err != nil {
+					close(decoded)
+					return
+				}
+				dec := json.NewDecoder(r)
+				for {
+					var we WatchEvent
+					if err := dec.Decode(&we); err != nil {
+						close(decoded)
+						return
+					}
+					decoded <- we
+				}
+			}()
+
+			for i, name := range []string{"pod1", "pod2"} {
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+				require.NoError(t, writer.Write(&WatchEvent{Kind: Pods, EventType: watch.Added, Obj: pod}))
+				// each event is flushed to the client once, and decodable without waiting for the end of the stream.
+				assert.Equal(t, i+1, flushed)
+				_, err := pw.Write(buf.Bytes())
+				require.NoError(t, err)
+				buf.Reset()
+				we, ok := <-decoded
+				require.True(t, ok, "the event %d isn't decodable", i)
+				assert.Equal(t, watch.Added, we.EventType)
+				assert.Equal(t, name, we.Obj.(map[string]interface{})["metadata"].(map[string]interface{})["name"])
+			}
+			require.NoError(t, stream.Close())
+			_, err = pw.Write(buf.Bytes())
+			require.NoError(t, err)
+			require.NoError(t, pw.Close())
+			_, ok := <-decoded
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestNewCompressedStream_unsupported(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	_, err := NewCompressedStream(mock_streamwriter.NewMockResponseStream(ctrl), "br")
+	assert.Error(t, err)
+}
@@ -6,20 +6,59 @@ import (
 	"io"
 	"net/http"
 	"sync"
+	"time"
 
 	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/watch"
 )
 
 // ResourceKind represents k8s resource name.
 type ResourceKind string
 
+// ResyncRequired is the EventType to tell the client that the resource version given for the Kind is too old to resume watching.
+// The client should discard the objects of the Kind it has, since all of them are sent as ADDED events again after this event.
+const ResyncRequired watch.EventType = "RESYNC_REQUIRED"
+
+// TooSlow is the EventType to tell the client that it's disconnected because it can't keep up with the events.
+const TooSlow watch.EventType = "TOO_SLOW"
+
+// SnapshotBegin is the EventType to tell the client that the current objects are sent as ADDED events from here.
+const SnapshotBegin watch.EventType = "SNAPSHOT_BEGIN"
+
+// SnapshotEnd is the EventType to tell the client that it has all the current objects, and the live events follow.
+// Obj of the event is SnapshotSummary, and Cursor of the event is the position to resume watching from.
+const SnapshotEnd watch.EventType = "SNAPSHOT_END"
+
+// SnapshotSummary is the summary of the initial snapshot sent with the SNAPSHOT_END event.
+type SnapshotSummary struct {
+	// Counts is the number of the objects of each kind sent in the snapshot.
+	Counts map[ResourceKind]int
+}
+
+// HeartbeatEventType is the EventType of the heartbeat sent periodically to keep the idle stream alive.
+const HeartbeatEventType watch.EventType = "HEARTBEAT"
+
+// Heartbeat is the lightweight message sent periodically to keep the idle stream alive.
+type Heartbeat struct {
+	EventType watch.EventType
+	Timestamp metav1.Time
+}
+
+// SchedulingResults is the scheduling results of a Pod parsed from its annotations, keyed by the annotation key.
+type SchedulingResults map[string]json.RawMessage
+
 // WatchEvent represents an event notified by the watched apiserver.
 type WatchEvent struct {
 	Kind      ResourceKind
 	EventType watch.EventType
 	// Obj is an object included in the event notified by the watched apiserver.
 	Obj interface{}
+	// Cursor is the opaque position of this event in the stream.
+	// The client can resume watching from the next event by passing it when reconnecting.
+	Cursor string
+	// SchedulingResults has the scheduling results of the Pod in Obj. It's empty for the other kinds.
+	SchedulingResults SchedulingResults `json:"schedulingResults,omitempty"`
 }
 
 // StreamWriter operates a given stream to send a received WatchEvent to the frontend.
@@ -49,6 +88,29 @@ func (sw *StreamWriter) Write(we *WatchEvent) error {
 	return nil
 }
 
+// WriteBatch encodes the WatchEvents as one JSON array and pushes it to the frontend at once.
+func (sw *StreamWriter) WriteBatch(wes []*WatchEvent) error {
+	sw.Lock()
+	defer sw.Unlock()
+	if err := sw.encoder.Encode(wes); err != nil {
+		return xerrors.Errorf("encode WatchEvents: %w", err)
+	}
+	sw.stream.Flush()
+	return nil
+}
+
+// WriteHeartbeat pushes the heartbeat with the timestamp to the frontend.
+// The error means the connection is likely dead.
+func (sw *StreamWriter) WriteHeartbeat(now time.Time) error {
+	sw.Lock()
+	defer sw.Unlock()
+	if err := sw.encoder.Encode(&Heartbeat{EventType: HeartbeatEventType, Timestamp: metav1.NewTime(now)}); err != nil {
+		return xerrors.Errorf("encode a Heartbeat: %w", err)
+	}
+	sw.stream.Flush()
+	return nil
+}
+
 // ResponseStream is an interface that allows Server Push to a Service.
 type ResponseStream interface {
 	io.Writer
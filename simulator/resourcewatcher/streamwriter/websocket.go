@@ -0,0 +1,89 @@
+package streamwriter
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+	"golang.org/x/xerrors"
+)
+
+// WebSocketStream is a ResponseStream which sends the data written until each Flush as one WebSocket text message,
+// so that each WatchEvent written by StreamWriter arrives at the client as one message.
+// It's used instead of the chunked HTTP response where the proxies between the simulator and the client buffer the stream.
+type WebSocketStream struct {
+	// mu serializes the messages and the pings sent to the conn.
+	mu   sync.Mutex
+	conn *websocket.Conn
+	buf  bytes.Buffer
+	// err is the error in the last Flush.
+	// http.Flusher can't return an error, so it's returned from the next Write instead.
+	err error
+	// writeTimeout is the time limit to send a message or a ping.
+	writeTimeout time.Duration
+}
+
+// NewWebSocketStream creates a WebSocketStream sending messages through conn.
+// Sending each message or ping fails when it takes longer than writeTimeout, so that a dead connection is detected.
+// writeTimeout can be zero to send without the time limit.
+func NewWebSocketStream(conn *websocket.Conn, writeTimeout time.Duration) *WebSocketStream {
+	return &WebSocketStream{
+		conn:         conn,
+		writeTimeout: writeTimeout,
+	}
+}
+
+// Write buffers p until Flush is called.
+func (s *WebSocketStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	return s.buf.Write(p)
+}
+
+// Flush sends the buffered data as one text message.
+func (s *WebSocketStream) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil || s.buf.Len() == 0 {
+		return
+	}
+	defer s.buf.Reset()
+	if err := s.send(websocket.TextFrame, s.buf.Bytes()); err != nil {
+		s.err = xerrors.Errorf("send a message: %w", err)
+	}
+}
+
+// Ping sends a ping frame to keep the connection alive.
+// The client responds with a pong automatically.
+func (s *WebSocketStream) Ping() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.send(websocket.PingFrame, nil); err != nil {
+		s.err = xerrors.Errorf("send a ping: %w", err)
+		return s.err
+	}
+	return nil
+}
+
+// send writes a frame of payloadType to the conn.
+// It must be called with s.mu held, because PayloadType of the conn is shared by all frames.
+func (s *WebSocketStream) send(payloadType byte, p []byte) error {
+	if s.writeTimeout > 0 {
+		if err := s.conn.SetWriteDeadline(time.Now().Add(s.writeTimeout)); err != nil {
+			return xerrors.Errorf("set write deadline: %w", err)
+		}
+	}
+	s.conn.PayloadType = payloadType
+	defer func() { s.conn.PayloadType = websocket.TextFrame }()
+	if _, err := s.conn.Write(p); err != nil {
+		return xerrors.Errorf("write to websocket: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package streamwriter
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestWebSocketStream(t *testing.T) {
+	t.Parallel()
+	serverErr := make(chan error, 1)
+	server := httptest.NewServer(websocket.Server{Handler: func(conn *websocket.Conn) {
+		stream := NewWebSocketStream(conn, 0)
+		sw := NewStreamWriter(stream)
+		if err := stream.Ping(); err != nil {
+			serverErr <- err
+			return
+		}
+		if err := sw.Write(&dummyWatchEvent1); err != nil {
+			serverErr <- err
+			return
+		}
+		serverErr <- sw.Write(&WatchEvent{Kind: Pods, EventType: watch.Deleted, Obj: Pod1})
+	}})
+	defer server.Close()
+
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http"), "", server.URL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// each WatchEvent should arrive as one message, and the ping should be handled transparently.
+	var got WatchEvent
+	require.NoError(t, websocket.JSON.Receive(conn, &got))
+	assert.Equal(t, watch.Added, got.EventType)
+	require.NoError(t, websocket.JSON.Receive(conn, &got))
+	assert.Equal(t, watch.Deleted, got.EventType)
+	require.NoError(t, <-serverErr)
+}
@@ -0,0 +1,138 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+)
+
+// DefaultPollInterval is the default interval to check the Pods in the waitForScheduled step.
+const DefaultPollInterval = time.Second
+
+// ResourceApplier creates and deletes the resources in the simulator.
+type ResourceApplier interface {
+	ApplyAll(ctx context.Context, resources []unstructured.Unstructured, opts resourceapplier.ApplyAllOptions) ([]resourceapplier.Result, error)
+	Delete(ctx context.Context, resource *unstructured.Unstructured) error
+}
+
+// SchedulerService applies the scheduler configuration.
+type SchedulerService interface {
+	RestartScheduler(cfg *configv1.KubeSchedulerConfiguration) error
+}
+
+// Options is the options of Executor.
+type Options struct {
+	// PollInterval is the interval to check the Pods in the waitForScheduled step. DefaultPollInterval is used if zero.
+	PollInterval time.Duration
+}
+
+// Executor runs the steps of the scenarios.
+type Executor struct {
+	client       clientset.Interface
+	applier      ResourceApplier
+	scheduler    SchedulerService
+	pollInterval time.Duration
+}
+
+// NewExecutor initializes Executor.
+func NewExecutor(client clientset.Interface, applier ResourceApplier, scheduler SchedulerService, opts Options) *Executor {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	return &Executor{client: client, applier: applier, scheduler: scheduler, pollInterval: opts.PollInterval}
+}
+
+// Run runs the step, and returns the message describing the result.
+// The step must be validated by Scenario.Validate beforehand.
+func (e *Executor) Run(ctx context.Context, step *Step) (string, error) {
+	switch step.Type() {
+	case StepCreateResources:
+		return e.createResources(ctx, step.CreateResources)
+	case StepWaitForScheduled:
+		return e.waitForScheduled(ctx, step.WaitForScheduled)
+	case StepDeleteResources:
+		return e.deleteResources(ctx, step.DeleteResources)
+	case StepChangeSchedulerConfig:
+		if err := e.scheduler.RestartScheduler(step.ChangeSchedulerConfig.Config); err != nil {
+			return "", xerrors.Errorf("apply the scheduler config: %w", err)
+		}
+		return "applied the scheduler configuration", nil
+	case StepSleep:
+		return e.sleep(ctx, step.Sleep.Duration.Duration)
+	default:
+		return "", xerrors.Errorf("unknown step: %w", ErrInvalidScenario)
+	}
+}
+
+func (e *Executor) createResources(ctx context.Context, step *CreateResources) (string, error) {
+	// ApplyAll may modify the resources, e.g., removing the unnecessary metadata.
+	manifests := make([]unstructured.Unstructured, 0, len(step.Manifests))
+	for i := range step.Manifests {
+		manifests = append(manifests, *step.Manifests[i].DeepCopy())
+	}
+	if _, err := e.applier.ApplyAll(ctx, manifests, resourceapplier.ApplyAllOptions{StopOnError: true}); err != nil {
+		return "", xerrors.Errorf("create resources: %w", err)
+	}
+	return fmt.Sprintf("created %d resources", len(manifests)), nil
+}
+
+func (e *Executor) deleteResources(ctx context.Context, step *DeleteResources) (string, error) {
+	for i := range step.Manifests {
+		m := &step.Manifests[i]
+		if err := e.applier.Delete(ctx, m); err != nil {
+			return "", xerrors.Errorf("delete %s %s: %w", m.GetKind(), m.GetName(), err)
+		}
+	}
+	return fmt.Sprintf("deleted %d resources", len(step.Manifests)), nil
+}
+
+// waitForScheduled waits until at least step.Count Pods are selected, and all of them are bound to the nodes.
+func (e *Executor) waitForScheduled(ctx context.Context, step *WaitForScheduled) (string, error) {
+	count := step.Count
+	if count == 0 {
+		count = 1
+	}
+	timeout := step.Timeout.Duration
+	if timeout == 0 {
+		timeout = DefaultWaitTimeout
+	}
+
+	var selected, scheduled int
+	err := wait.PollUntilContextTimeout(ctx, e.pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		pods, err := e.client.CoreV1().Pods(step.Namespace).List(ctx, metav1.ListOptions{LabelSelector: step.Selector})
+		if err != nil {
+			return false, xerrors.Errorf("list pods: %w", err)
+		}
+		selected, scheduled = len(pods.Items), 0
+		for i := range pods.Items {
+			if pods.Items[i].Spec.NodeName != "" {
+				scheduled++
+			}
+		}
+		return selected >= count && scheduled == selected, nil
+	})
+	if err != nil {
+		return "", xerrors.Errorf("%d/%d Pods are scheduled (%d Pods are expected at least): %w", scheduled, selected, count, err)
+	}
+	return fmt.Sprintf("%d Pods are scheduled", scheduled), nil
+}
+
+func (e *Executor) sleep(ctx context.Context, d time.Duration) (string, error) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return fmt.Sprintf("slept for %s", d), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
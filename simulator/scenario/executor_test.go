@@ -0,0 +1,188 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+)
+
+type fakeApplier struct {
+	mu      sync.Mutex
+	applied []string
+	deleted []string
+	err     error
+}
+
+func (f *fakeApplier) ApplyAll(_ context.Context, resources []unstructured.Unstructured, _ resourceapplier.ApplyAllOptions) ([]resourceapplier.Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	for i := range resources {
+		f.applied = append(f.applied, resources[i].GetName())
+	}
+	return nil, nil
+}
+
+func (f *fakeApplier) Delete(_ context.Context, resource *unstructured.Unstructured) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.deleted = append(f.deleted, resource.GetName())
+	return nil
+}
+
+type fakeScheduler struct {
+	cfg *configv1.KubeSchedulerConfiguration
+	err error
+}
+
+func (f *fakeScheduler) RestartScheduler(cfg *configv1.KubeSchedulerConfiguration) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.cfg = cfg
+	return nil
+}
+
+func testPod(name, nodeName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: map[string]string{"app": "web"}},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+	}
+}
+
+func TestExecutor_Run(t *testing.T) {
+	t.Parallel()
+	errApply := errors.New("apply failed")
+	profile := "custom"
+	cfg := &configv1.KubeSchedulerConfiguration{Profiles: []configv1.KubeSchedulerProfile{{SchedulerName: &profile}}}
+
+	tests := []struct {
+		name        string
+		step        Step
+		objs        []runtime.Object
+		applierErr  error
+		schedErr    error
+		wantMessage string
+		wantErr     bool
+		// wantApplied and wantDeleted are the names of the resources passed to the applier.
+		wantApplied []string
+		wantDeleted []string
+		wantConfig  *configv1.KubeSchedulerConfiguration
+	}{
+		{
+			name:        "createResources",
+			step:        Step{CreateResources: &CreateResources{Manifests: []unstructured.Unstructured{manifest("Pod", "pod1"), manifest("Pod", "pod2")}}},
+			wantMessage: "created 2 resources",
+			wantApplied: []string{"pod1", "pod2"},
+		},
+		{
+			name:       "createResources fails",
+			step:       Step{CreateResources: &CreateResources{Manifests: []unstructured.Unstructured{manifest("Pod", "pod1")}}},
+			applierErr: errApply,
+			wantErr:    true,
+		},
+		{
+			name:        "deleteResources",
+			step:        Step{DeleteResources: &DeleteResources{Manifests: []unstructured.Unstructured{manifest("Pod", "pod1")}}},
+			wantMessage: "deleted 1 resources",
+			wantDeleted: []string{"pod1"},
+		},
+		{
+			name:       "deleteResources fails",
+			step:       Step{DeleteResources: &DeleteResources{Manifests: []unstructured.Unstructured{manifest("Pod", "pod1")}}},
+			applierErr: errApply,
+			wantErr:    true,
+		},
+		{
+			name:        "waitForScheduled",
+			step:        Step{WaitForScheduled: &WaitForScheduled{Namespace: "default", Selector: "app=web", Count: 2}},
+			objs:        []runtime.Object{testPod("pod1", "node1"), testPod("pod2", "node2")},
+			wantMessage: "2 Pods are scheduled",
+		},
+		{
+			name: "waitForScheduled times out with the unscheduled Pod",
+			step: Step{WaitForScheduled: &WaitForScheduled{
+				Namespace: "default",
+				Selector:  "app=web",
+				Timeout:   metav1.Duration{Duration: 50 * time.Millisecond},
+			}},
+			objs:    []runtime.Object{testPod("pod1", "node1"), testPod("pod2", "")},
+			wantErr: true,
+		},
+		{
+			name: "waitForScheduled times out with too few Pods",
+			step: Step{WaitForScheduled: &WaitForScheduled{
+				Namespace: "default",
+				Count:     3,
+				Timeout:   metav1.Duration{Duration: 50 * time.Millisecond},
+			}},
+			objs:    []runtime.Object{testPod("pod1", "node1")},
+			wantErr: true,
+		},
+		{
+			name:        "changeSchedulerConfig",
+			step:        Step{ChangeSchedulerConfig: &ChangeSchedulerConfig{Config: cfg}},
+			wantMessage: "applied the scheduler configuration",
+			wantConfig:  cfg,
+		},
+		{
+			name:     "changeSchedulerConfig fails",
+			step:     Step{ChangeSchedulerConfig: &ChangeSchedulerConfig{Config: cfg}},
+			schedErr: errors.New("invalid config"),
+			wantErr:  true,
+		},
+		{
+			name:        "sleep",
+			step:        Step{Sleep: &Sleep{Duration: metav1.Duration{Duration: 10 * time.Millisecond}}},
+			wantMessage: "slept for 10ms",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			applier := &fakeApplier{err: tt.applierErr}
+			sched := &fakeScheduler{err: tt.schedErr}
+			e := NewExecutor(fake.NewSimpleClientset(tt.objs...), applier, sched, Options{PollInterval: 10 * time.Millisecond})
+
+			got, err := e.Run(context.Background(), &tt.step)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMessage, got)
+			assert.Equal(t, tt.wantApplied, applier.applied)
+			assert.Equal(t, tt.wantDeleted, applier.deleted)
+			assert.Equal(t, tt.wantConfig, sched.cfg)
+		})
+	}
+}
+
+func TestExecutor_RunCancelled(t *testing.T) {
+	t.Parallel()
+	e := NewExecutor(fake.NewSimpleClientset(), &fakeApplier{}, &fakeScheduler{}, Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := e.Run(ctx, &Step{Sleep: &Sleep{Duration: metav1.Duration{Duration: time.Hour}}})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,165 @@
+// Package scenario runs the declarative experiments on the simulator, i.e., the ordered steps like creating the resources,
+// waiting for the Pods to be scheduled and changing the scheduler configuration.
+package scenario
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+)
+
+// DefaultWaitTimeout is the default timeout of the waitForScheduled step.
+const DefaultWaitTimeout = time.Minute
+
+// ErrInvalidScenario is returned when the scenario document is invalid.
+var ErrInvalidScenario = errors.New("invalid scenario")
+
+// Scenario is the declarative experiment. The steps are run in order, and the scenario stops on the first failed step.
+type Scenario struct {
+	Name  string `json:"name,omitempty"`
+	Steps []Step `json:"steps"`
+}
+
+// StepType is the kind of the step.
+type StepType string
+
+const (
+	StepCreateResources       StepType = "createResources"
+	StepWaitForScheduled      StepType = "waitForScheduled"
+	StepDeleteResources       StepType = "deleteResources"
+	StepChangeSchedulerConfig StepType = "changeSchedulerConfig"
+	StepSleep                 StepType = "sleep"
+)
+
+// Step is a step of the scenario. Exactly one of the fields other than Name must be set.
+type Step struct {
+	Name                  string                 `json:"name,omitempty"`
+	CreateResources       *CreateResources       `json:"createResources,omitempty"`
+	WaitForScheduled      *WaitForScheduled      `json:"waitForScheduled,omitempty"`
+	DeleteResources       *DeleteResources       `json:"deleteResources,omitempty"`
+	ChangeSchedulerConfig *ChangeSchedulerConfig `json:"changeSchedulerConfig,omitempty"`
+	Sleep                 *Sleep                 `json:"sleep,omitempty"`
+}
+
+// CreateResources creates the resources in the simulator.
+type CreateResources struct {
+	Manifests []unstructured.Unstructured `json:"manifests"`
+}
+
+// WaitForScheduled waits until all the Pods selected are scheduled.
+type WaitForScheduled struct {
+	// Namespace is the namespace of the Pods. The Pods in all the namespaces are selected if it's empty.
+	Namespace string `json:"namespace,omitempty"`
+	// Selector is the label selector of the Pods. All the Pods are selected if it's empty.
+	Selector string `json:"selector,omitempty"`
+	// Count is the number of the Pods which must be selected at least. It's 1 if zero.
+	Count int `json:"count,omitempty"`
+	// Timeout is DefaultWaitTimeout if zero.
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// DeleteResources deletes the resources identified by the manifests, i.e., by their apiVersion, kind, namespace and name.
+type DeleteResources struct {
+	Manifests []unstructured.Unstructured `json:"manifests"`
+}
+
+// ChangeSchedulerConfig applies the scheduler configuration, and restarts the scheduler.
+type ChangeSchedulerConfig struct {
+	Config *configv1.KubeSchedulerConfiguration `json:"config"`
+}
+
+// Sleep waits for the duration.
+type Sleep struct {
+	Duration metav1.Duration `json:"duration"`
+}
+
+// Type returns the type of the step. It's empty if no or more than one field is set.
+func (s *Step) Type() StepType {
+	var types []StepType
+	if s.CreateResources != nil {
+		types = append(types, StepCreateResources)
+	}
+	if s.WaitForScheduled != nil {
+		types = append(types, StepWaitForScheduled)
+	}
+	if s.DeleteResources != nil {
+		types = append(types, StepDeleteResources)
+	}
+	if s.ChangeSchedulerConfig != nil {
+		types = append(types, StepChangeSchedulerConfig)
+	}
+	if s.Sleep != nil {
+		types = append(types, StepSleep)
+	}
+	if len(types) != 1 {
+		return ""
+	}
+	return types[0]
+}
+
+// Validate returns an error wrapping ErrInvalidScenario if the scenario can't be run.
+func (s *Scenario) Validate() error {
+	if len(s.Steps) == 0 {
+		return xerrors.Errorf("no steps: %w", ErrInvalidScenario)
+	}
+	for i := range s.Steps {
+		if err := s.Steps[i].validate(); err != nil {
+			return xerrors.Errorf("step %d: %v: %w", i, err, ErrInvalidScenario)
+		}
+	}
+	return nil
+}
+
+func (s *Step) validate() error {
+	switch s.Type() {
+	case StepCreateResources:
+		return validateManifests(s.CreateResources.Manifests, true)
+	case StepDeleteResources:
+		return validateManifests(s.DeleteResources.Manifests, false)
+	case StepWaitForScheduled:
+		if _, err := labels.Parse(s.WaitForScheduled.Selector); err != nil {
+			return xerrors.Errorf("invalid selector: %w", err)
+		}
+		if s.WaitForScheduled.Count < 0 {
+			return xerrors.New("count must not be negative")
+		}
+		if s.WaitForScheduled.Timeout.Duration < 0 {
+			return xerrors.New("timeout must not be negative")
+		}
+	case StepChangeSchedulerConfig:
+		if s.ChangeSchedulerConfig.Config == nil {
+			return xerrors.New("config is required")
+		}
+	case StepSleep:
+		if s.Sleep.Duration.Duration <= 0 {
+			return xerrors.New("duration must be positive")
+		}
+	default:
+		return xerrors.Errorf("exactly one of %s, %s, %s, %s and %s must be set",
+			StepCreateResources, StepWaitForScheduled, StepDeleteResources, StepChangeSchedulerConfig, StepSleep)
+	}
+	return nil
+}
+
+// validateManifests checks the manifests have apiVersion, kind and name.
+// metadata.generateName is allowed instead of metadata.name if allowGenerateName is true.
+func validateManifests(manifests []unstructured.Unstructured, allowGenerateName bool) error {
+	if len(manifests) == 0 {
+		return xerrors.New("no manifests")
+	}
+	for i := range manifests {
+		m := &manifests[i]
+		if m.GetAPIVersion() == "" || m.GetKind() == "" {
+			return xerrors.Errorf("manifest %d: apiVersion and kind are required", i)
+		}
+		if m.GetName() == "" && (!allowGenerateName || m.GetGenerateName() == "") {
+			return xerrors.Errorf("manifest %d: metadata.name is required", i)
+		}
+	}
+	return nil
+}
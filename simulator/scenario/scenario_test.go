@@ -0,0 +1,99 @@
+package scenario
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+)
+
+func manifest(kind, name string) unstructured.Unstructured {
+	u := unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind(kind)
+	u.SetNamespace("default")
+	u.SetName(name)
+	return u
+}
+
+func TestScenario_Validate(t *testing.T) {
+	t.Parallel()
+	generated := manifest("Pod", "")
+	generated.SetGenerateName("pod-")
+	noKind := manifest("", "pod1")
+
+	tests := []struct {
+		name     string
+		scenario Scenario
+		wantErr  bool
+	}{
+		{
+			name: "valid scenario",
+			scenario: Scenario{Steps: []Step{
+				{CreateResources: &CreateResources{Manifests: []unstructured.Unstructured{manifest("Pod", "pod1"), generated}}},
+				{WaitForScheduled: &WaitForScheduled{Selector: "app=web", Count: 2}},
+				{ChangeSchedulerConfig: &ChangeSchedulerConfig{Config: &configv1.KubeSchedulerConfiguration{}}},
+				{Sleep: &Sleep{Duration: metav1.Duration{Duration: time.Second}}},
+				{DeleteResources: &DeleteResources{Manifests: []unstructured.Unstructured{manifest("Pod", "pod1")}}},
+			}},
+		},
+		{
+			name:     "no steps",
+			scenario: Scenario{},
+			wantErr:  true,
+		},
+		{
+			name:     "no field is set in the step",
+			scenario: Scenario{Steps: []Step{{Name: "empty"}}},
+			wantErr:  true,
+		},
+		{
+			name: "more than one field is set in the step",
+			scenario: Scenario{Steps: []Step{{
+				Sleep:            &Sleep{Duration: metav1.Duration{Duration: time.Second}},
+				WaitForScheduled: &WaitForScheduled{},
+			}}},
+			wantErr: true,
+		},
+		{
+			name:     "manifest without kind",
+			scenario: Scenario{Steps: []Step{{CreateResources: &CreateResources{Manifests: []unstructured.Unstructured{noKind}}}}},
+			wantErr:  true,
+		},
+		{
+			name:     "generateName can't identify the resource to delete",
+			scenario: Scenario{Steps: []Step{{DeleteResources: &DeleteResources{Manifests: []unstructured.Unstructured{generated}}}}},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid selector",
+			scenario: Scenario{Steps: []Step{{WaitForScheduled: &WaitForScheduled{Selector: "app in (web"}}}},
+			wantErr:  true,
+		},
+		{
+			name:     "no scheduler config",
+			scenario: Scenario{Steps: []Step{{ChangeSchedulerConfig: &ChangeSchedulerConfig{}}}},
+			wantErr:  true,
+		},
+		{
+			name:     "zero sleep",
+			scenario: Scenario{Steps: []Step{{Sleep: &Sleep{}}}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.scenario.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidScenario)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
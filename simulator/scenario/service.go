@@ -0,0 +1,189 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
+)
+
+// ErrNotFound is returned when the scenario doesn't exist, or its job is already deleted by the retention.
+var ErrNotFound = errors.New("scenario not found")
+
+// StepState is the state of the step.
+type StepState string
+
+const (
+	StepPending   StepState = "pending"
+	StepRunning   StepState = "running"
+	StepSucceeded StepState = "succeeded"
+	StepFailed    StepState = "failed"
+)
+
+// StepStatus is the status of a step.
+type StepStatus struct {
+	Name  string    `json:"name,omitempty"`
+	Type  StepType  `json:"type"`
+	State StepState `json:"state"`
+	// Message describes the result of the succeeded step, e.g., "3 Pods are scheduled".
+	Message    string     `json:"message,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Status is the status of the scenario. ID is the same as the ID of the job running it.
+type Status struct {
+	ID    string    `json:"id"`
+	Name  string    `json:"name,omitempty"`
+	State job.State `json:"state"`
+	Error string    `json:"error,omitempty"`
+	// FailedStep is the index of the step which the scenario failed at.
+	FailedStep *int         `json:"failedStep,omitempty"`
+	Steps      []StepStatus `json:"steps"`
+	CreatedAt  time.Time    `json:"createdAt"`
+	StartedAt  *time.Time   `json:"startedAt,omitempty"`
+	FinishedAt *time.Time   `json:"finishedAt,omitempty"`
+}
+
+// JobManager runs the scenarios as the background jobs.
+type JobManager interface {
+	Submit(typ job.Type, fn job.Func) (*job.Job, error)
+	Get(id string) (*job.Job, error)
+}
+
+// Service runs the scenarios as the jobs of job.TypeScenario, and keeps the status of their steps.
+type Service struct {
+	executor *Executor
+	jobs     JobManager
+	now      func() time.Time
+
+	mu sync.Mutex
+	// runs are the scenarios by the job IDs.
+	runs map[string]*run
+}
+
+type run struct {
+	name       string
+	steps      []StepStatus
+	failedStep *int
+}
+
+// NewService initializes Service.
+func NewService(executor *Executor, jobs JobManager) *Service {
+	return &Service{executor: executor, jobs: jobs, now: time.Now, runs: map[string]*run{}}
+}
+
+// Submit starts running the scenario in background, and returns its status without waiting for it to finish.
+// It returns an error wrapping ErrInvalidScenario if the scenario is invalid,
+// and job.ErrConflict if another scenario is in progress.
+func (s *Service) Submit(sc *Scenario) (*Status, error) {
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+
+	r := &run{name: sc.Name, steps: make([]StepStatus, len(sc.Steps))}
+	for i := range sc.Steps {
+		r.steps[i] = StepStatus{Name: sc.Steps[i].Name, Type: sc.Steps[i].Type(), State: StepPending}
+	}
+	j, err := s.jobs.Submit(job.TypeScenario, func(ctx context.Context, report func(job.Progress)) (interface{}, error) {
+		return nil, s.run(ctx, sc, r, report)
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("submit the job of the scenario: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune()
+	s.runs[j.ID] = r
+	return s.status(j, r), nil
+}
+
+// Get returns the status of the scenario with the id.
+func (s *Service) Get(id string) (*Status, error) {
+	j, err := s.jobs.Get(id)
+	if errors.Is(err, job.ErrNotFound) {
+		s.mu.Lock()
+		delete(s.runs, id)
+		s.mu.Unlock()
+		return nil, xerrors.Errorf("get scenario %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("get the job of the scenario: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.runs[id]
+	if !ok {
+		return nil, xerrors.Errorf("get scenario %s: %w", id, ErrNotFound)
+	}
+	return s.status(j, r), nil
+}
+
+// run runs the steps in order, and stops on the first failed step.
+func (s *Service) run(ctx context.Context, sc *Scenario, r *run, report func(job.Progress)) error {
+	for i := range sc.Steps {
+		step := &sc.Steps[i]
+		report(job.Progress{Done: i, Total: len(sc.Steps), Message: fmt.Sprintf("step %d: %s", i, step.Type())})
+		s.mu.Lock()
+		started := s.now()
+		r.steps[i].State = StepRunning
+		r.steps[i].StartedAt = &started
+		s.mu.Unlock()
+
+		msg, err := s.executor.Run(ctx, step)
+
+		s.mu.Lock()
+		finished := s.now()
+		r.steps[i].FinishedAt = &finished
+		if err != nil {
+			r.steps[i].State = StepFailed
+			r.steps[i].Error = err.Error()
+			r.failedStep = &i
+		} else {
+			r.steps[i].State = StepSucceeded
+			r.steps[i].Message = msg
+		}
+		s.mu.Unlock()
+		if err != nil {
+			return xerrors.Errorf("step %d (%s): %w", i, step.Type(), err)
+		}
+	}
+	report(job.Progress{Done: len(sc.Steps), Total: len(sc.Steps)})
+	return nil
+}
+
+// status returns the status of the scenario in the job j. It must be called with mu held.
+func (s *Service) status(j *job.Job, r *run) *Status {
+	ret := &Status{
+		ID:         j.ID,
+		Name:       r.name,
+		State:      j.State,
+		Error:      j.Error,
+		Steps:      append([]StepStatus{}, r.steps...),
+		CreatedAt:  j.CreatedAt,
+		StartedAt:  j.StartedAt,
+		FinishedAt: j.FinishedAt,
+	}
+	if r.failedStep != nil {
+		failed := *r.failedStep
+		ret.FailedStep = &failed
+	}
+	return ret
+}
+
+// prune forgets the scenarios whose jobs are deleted by the retention. It must be called with mu held.
+func (s *Service) prune() {
+	for id := range s.runs {
+		if _, err := s.jobs.Get(id); errors.Is(err, job.ErrNotFound) {
+			delete(s.runs, id)
+		}
+	}
+}
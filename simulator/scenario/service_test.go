@@ -0,0 +1,91 @@
+package scenario
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
+)
+
+func waitScenario(t *testing.T, s *Service, id string) *Status {
+	t.Helper()
+	var st *Status
+	require.Eventually(t, func() bool {
+		var err error
+		st, err = s.Get(id)
+		require.NoError(t, err)
+		return st.FinishedAt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	return st
+}
+
+func TestService_Submit(t *testing.T) {
+	t.Parallel()
+	sleep := Step{Name: "sleep", Sleep: &Sleep{Duration: metav1.Duration{Duration: time.Millisecond}}}
+	create := Step{Name: "create", CreateResources: &CreateResources{Manifests: []unstructured.Unstructured{manifest("Pod", "pod1")}}}
+
+	tests := []struct {
+		name           string
+		scenario       Scenario
+		applierErr     error
+		wantState      job.State
+		wantFailedStep *int
+		wantSteps      []StepState
+	}{
+		{
+			name:      "all the steps succeed",
+			scenario:  Scenario{Name: "ok", Steps: []Step{create, sleep}},
+			wantState: job.StateSucceeded,
+			wantSteps: []StepState{StepSucceeded, StepSucceeded},
+		},
+		{
+			name:           "the scenario stops on the failed step",
+			scenario:       Scenario{Name: "ng", Steps: []Step{sleep, create, sleep}},
+			applierErr:     errors.New("apply failed"),
+			wantState:      job.StateFailed,
+			wantFailedStep: func() *int { i := 1; return &i }(),
+			wantSteps:      []StepState{StepSucceeded, StepFailed, StepPending},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := NewExecutor(fake.NewSimpleClientset(), &fakeApplier{err: tt.applierErr}, &fakeScheduler{}, Options{})
+			s := NewService(e, job.NewManager(job.Options{ExclusiveTypes: job.DefaultExclusiveTypes}))
+
+			submitted, err := s.Submit(&tt.scenario)
+			require.NoError(t, err)
+			assert.Equal(t, tt.scenario.Name, submitted.Name)
+
+			got := waitScenario(t, s, submitted.ID)
+			assert.Equal(t, tt.wantState, got.State)
+			assert.Equal(t, tt.wantFailedStep, got.FailedStep)
+			require.Len(t, got.Steps, len(tt.wantSteps))
+			for i, want := range tt.wantSteps {
+				assert.Equal(t, want, got.Steps[i].State, "step %d", i)
+			}
+		})
+	}
+}
+
+func TestService_SubmitInvalid(t *testing.T) {
+	t.Parallel()
+	s := NewService(NewExecutor(fake.NewSimpleClientset(), &fakeApplier{}, &fakeScheduler{}, Options{}), job.NewManager(job.Options{}))
+	_, err := s.Submit(&Scenario{})
+	assert.ErrorIs(t, err, ErrInvalidScenario)
+}
+
+func TestService_GetNotFound(t *testing.T) {
+	t.Parallel()
+	s := NewService(NewExecutor(fake.NewSimpleClientset(), &fakeApplier{}, &fakeScheduler{}, Options{}), job.NewManager(job.Options{}))
+	_, err := s.Get("unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -3,8 +3,10 @@ package config
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"golang.org/x/xerrors"
 	"gopkg.in/yaml.v2"
@@ -35,6 +37,37 @@ func UpdateSchedulerConfig(cfg *v1.KubeSchedulerConfiguration) error {
 	if kubeSchedulerConfigPath == "" {
 		return xerrors.New("kubeSchedulerConfigPath isn't initialized, which is likely a bug in the simulator")
 	}
+	return writeSchedulerConfig(kubeSchedulerConfigPath, cfg)
+}
+
+// SecondarySchedulerConfigFileName returns the name of the config file of the secondary scheduler with the name.
+// The file is put next to the scheduler configuration file so that the secondary scheduler container can read it.
+func SecondarySchedulerConfigFileName(name string) string {
+	return "scheduler-" + name + ".yaml"
+}
+
+// UpdateSecondarySchedulerConfig writes the config of the secondary scheduler with the name next to kubeSchedulerConfigPath.
+func UpdateSecondarySchedulerConfig(name string, cfg *v1.KubeSchedulerConfiguration) error {
+	if kubeSchedulerConfigPath == "" {
+		return xerrors.New("kubeSchedulerConfigPath isn't initialized, which is likely a bug in the simulator")
+	}
+	return writeSchedulerConfig(filepath.Join(filepath.Dir(kubeSchedulerConfigPath), SecondarySchedulerConfigFileName(name)), cfg)
+}
+
+// RemoveSecondarySchedulerConfig removes the config file of the secondary scheduler with the name if it exists.
+func RemoveSecondarySchedulerConfig(name string) error {
+	if kubeSchedulerConfigPath == "" {
+		return nil
+	}
+	err := os.Remove(filepath.Join(filepath.Dir(kubeSchedulerConfigPath), SecondarySchedulerConfigFileName(name)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return xerrors.Errorf("remove the config of the secondary scheduler %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeSchedulerConfig writes cfg to path in YAML.
+func writeSchedulerConfig(path string, cfg *v1.KubeSchedulerConfiguration) error {
 	jsonData, err := json.Marshal(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal jsonData: %w", err)
@@ -52,7 +85,7 @@ func UpdateSchedulerConfig(cfg *v1.KubeSchedulerConfiguration) error {
 		return fmt.Errorf("failed to marshal yaml: %w", err)
 	}
 
-	if err := os.WriteFile(kubeSchedulerConfigPath, data, 0o600); err != nil {
+	if err := os.WriteFile(path, data, 0o600); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/xerrors"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/component-base/featuregate"
+	_ "k8s.io/kubernetes/pkg/features" // for the registration of the feature gates of Kubernetes.
+	"sigs.k8s.io/yaml"
+)
+
+// FeatureGatesFileName is the name of the file having the feature gates of the scheduler.
+// The file is put next to the scheduler configuration file so that the debuggable scheduler container can read it.
+const FeatureGatesFileName = "feature-gates.yaml"
+
+// ValidateFeatureGates checks that all the gates are known to the scheduler and can be set to the values.
+// It doesn't change the feature gates of this process.
+func ValidateFeatureGates(gates map[string]bool) error {
+	if len(gates) == 0 {
+		return nil
+	}
+	known := utilfeature.DefaultMutableFeatureGate.GetAll()
+	var unknown []string
+	for name := range gates {
+		if _, ok := known[featuregate.Feature(name)]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) != 0 {
+		sort.Strings(unknown)
+		return xerrors.Errorf("unknown feature gates %v, the valid gates are %v", unknown, KnownFeatureGates())
+	}
+	// Some gates are locked to the default value, which are rejected when setting them.
+	if err := utilfeature.DefaultMutableFeatureGate.DeepCopy().SetFromMap(gates); err != nil {
+		return xerrors.Errorf("set feature gates: %w", err)
+	}
+	return nil
+}
+
+// KnownFeatureGates returns the sorted names of the feature gates known to the scheduler.
+func KnownFeatureGates() []string {
+	known := utilfeature.DefaultMutableFeatureGate.GetAll()
+	names := make([]string, 0, len(known))
+	for name := range known {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetFeatureGates validates the gates and sets them to the feature gates of this process,
+// which the scheduler running in this process refers to.
+func SetFeatureGates(gates map[string]bool) error {
+	if err := ValidateFeatureGates(gates); err != nil {
+		return err
+	}
+	if err := utilfeature.DefaultMutableFeatureGate.SetFromMap(gates); err != nil {
+		return xerrors.Errorf("set feature gates: %w", err)
+	}
+	return nil
+}
+
+// FeatureGatesPath returns the path to the feature gates file next to the scheduler configuration file at schedulerCfgPath.
+func FeatureGatesPath(schedulerCfgPath string) string {
+	return filepath.Join(filepath.Dir(schedulerCfgPath), FeatureGatesFileName)
+}
+
+// UpdateFeatureGates writes the given feature gates to the file next to kubeSchedulerConfigPath.
+// The file is written even if gates is empty so that the gates written before are removed.
+func UpdateFeatureGates(gates map[string]bool) error {
+	if kubeSchedulerConfigPath == "" {
+		return xerrors.New("kubeSchedulerConfigPath isn't initialized, which is likely a bug in the simulator")
+	}
+	if gates == nil {
+		gates = map[string]bool{}
+	}
+	data, err := yaml.Marshal(gates)
+	if err != nil {
+		return xerrors.Errorf("marshal feature gates: %w", err)
+	}
+	if err := os.WriteFile(FeatureGatesPath(kubeSchedulerConfigPath), data, 0o600); err != nil {
+		return xerrors.Errorf("write feature gates file: %w", err)
+	}
+	return nil
+}
+
+// LoadFeatureGates reads the feature gates from the file at path.
+// It returns nil if the file doesn't exist.
+func LoadFeatureGates(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("read feature gates file: %w", err)
+	}
+	gates := map[string]bool{}
+	if err := yaml.UnmarshalStrict(data, &gates); err != nil {
+		return nil, xerrors.Errorf("decode feature gates file %s: %w", path, err)
+	}
+	return gates, nil
+}
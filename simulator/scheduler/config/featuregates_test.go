@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFeatureGates(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		gates   map[string]bool
+		wantErr []string
+	}{
+		{
+			name: "no gates",
+		},
+		{
+			name:  "known gates",
+			gates: map[string]bool{"DynamicResourceAllocation": true, "SchedulerQueueingHints": false},
+		},
+		{
+			name:    "unknown gates with the list of the valid gates",
+			gates:   map[string]bool{"NoSuchFeature": true, "DynamicResourceAllocation": true, "AnotherFeature": false},
+			wantErr: []string{"unknown feature gates [AnotherFeature NoSuchFeature]", "the valid gates are [", "DynamicResourceAllocation"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateFeatureGates(tt.gates)
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			for _, want := range tt.wantErr {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // cannot use t.Parallel because UpdateFeatureGates reads kubeSchedulerConfigPath.
+func TestUpdateFeatureGates(t *testing.T) {
+	dir := t.TempDir()
+	SetKubeSchedulerCfgPath(filepath.Join(dir, "scheduler.yaml"))
+	t.Cleanup(func() { SetKubeSchedulerCfgPath("") })
+
+	require.NoError(t, UpdateFeatureGates(map[string]bool{"DynamicResourceAllocation": true}))
+	got, err := LoadFeatureGates(filepath.Join(dir, FeatureGatesFileName))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"DynamicResourceAllocation": true}, got)
+
+	// The gates written before are removed.
+	require.NoError(t, UpdateFeatureGates(nil))
+	got, err = LoadFeatureGates(filepath.Join(dir, FeatureGatesFileName))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestLoadFeatureGates(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	got, err := LoadFeatureGates(filepath.Join(dir, "missing.yaml"))
+	require.NoError(t, err)
+	assert.Nil(t, got, "the missing file means no feature gates")
+
+	path := filepath.Join(dir, FeatureGatesFileName)
+	require.NoError(t, os.WriteFile(path, []byte("DynamicResourceAllocation: yes please\n"), 0o600))
+	_, err = LoadFeatureGates(path)
+	assert.Error(t, err)
+}
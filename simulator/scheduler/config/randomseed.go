@@ -0,0 +1,64 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+	"sigs.k8s.io/yaml"
+)
+
+// RandomSeedFileName is the name of the file having the seed of the random numbers of the scheduler.
+// The file is put next to the scheduler configuration file so that the debuggable scheduler container can read it.
+const RandomSeedFileName = "random-seed.yaml"
+
+// randomSeedFile is the content of the random seed file.
+type randomSeedFile struct {
+	Seed int64 `json:"seed"`
+}
+
+// RandomSeedPath returns the path to the random seed file next to the scheduler configuration file at schedulerCfgPath.
+func RandomSeedPath(schedulerCfgPath string) string {
+	return filepath.Join(filepath.Dir(schedulerCfgPath), RandomSeedFileName)
+}
+
+// UpdateRandomSeed writes the given seed to the file next to kubeSchedulerConfigPath.
+// The file is removed if seed is nil so that the seed written before isn't used.
+func UpdateRandomSeed(seed *int64) error {
+	if kubeSchedulerConfigPath == "" {
+		return xerrors.New("kubeSchedulerConfigPath isn't initialized, which is likely a bug in the simulator")
+	}
+	path := RandomSeedPath(kubeSchedulerConfigPath)
+	if seed == nil {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return xerrors.Errorf("remove random seed file: %w", err)
+		}
+		return nil
+	}
+	data, err := yaml.Marshal(randomSeedFile{Seed: *seed})
+	if err != nil {
+		return xerrors.Errorf("marshal random seed: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return xerrors.Errorf("write random seed file: %w", err)
+	}
+	return nil
+}
+
+// LoadRandomSeed reads the seed from the file at path.
+// It returns nil if the file doesn't exist.
+func LoadRandomSeed(path string) (*int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, xerrors.Errorf("read random seed file: %w", err)
+	}
+	f := randomSeedFile{}
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, xerrors.Errorf("decode random seed file %s: %w", path, err)
+	}
+	return &f.Seed, nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+//nolint:paralleltest // cannot use t.Parallel because UpdateRandomSeed reads kubeSchedulerConfigPath.
+func TestUpdateRandomSeed(t *testing.T) {
+	dir := t.TempDir()
+	SetKubeSchedulerCfgPath(filepath.Join(dir, "scheduler.yaml"))
+	t.Cleanup(func() { SetKubeSchedulerCfgPath("") })
+
+	require.NoError(t, UpdateRandomSeed(ptr.To[int64](42)))
+	got, err := LoadRandomSeed(filepath.Join(dir, RandomSeedFileName))
+	require.NoError(t, err)
+	assert.Equal(t, ptr.To[int64](42), got)
+
+	// The zero seed is distinguished from no seed.
+	require.NoError(t, UpdateRandomSeed(ptr.To[int64](0)))
+	got, err = LoadRandomSeed(filepath.Join(dir, RandomSeedFileName))
+	require.NoError(t, err)
+	assert.Equal(t, ptr.To[int64](0), got)
+
+	// The seed written before is removed.
+	require.NoError(t, UpdateRandomSeed(nil))
+	got, err = LoadRandomSeed(filepath.Join(dir, RandomSeedFileName))
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	// Removing the missing file isn't an error.
+	require.NoError(t, UpdateRandomSeed(nil))
+}
+
+func TestLoadRandomSeed_invalid(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), RandomSeedFileName)
+	require.NoError(t, os.WriteFile(path, []byte("seed: forty-two\n"), 0o600))
+	_, err := LoadRandomSeed(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"golang.org/x/xerrors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/scheme"
+	"k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+)
+
+// ValidateSchedulerConfig validates the scheduler configuration as kube-scheduler does after defaulting it,
+// and checks that all the enabled plugins are registered in the in-tree or out-of-tree registries, or are wasm plugins.
+// It returns an error only if the configuration can't be converted, e.g., the plugin args are broken.
+// The given configuration isn't modified.
+func ValidateSchedulerConfig(versioned *configv1.KubeSchedulerConfiguration) (field.ErrorList, error) {
+	versioned = versioned.DeepCopy()
+	scheme.Scheme.Default(versioned)
+	cfg := config.KubeSchedulerConfiguration{}
+	if err := scheme.Scheme.Convert(versioned, &cfg, nil); err != nil {
+		return nil, xerrors.Errorf("convert configuration: %w", err)
+	}
+	cfg.SetGroupVersionKind(configv1.SchemeGroupVersion.WithKind("KubeSchedulerConfiguration"))
+
+	var errs field.ErrorList
+	if agg := validation.ValidateKubeSchedulerConfiguration(&cfg); agg != nil {
+		for _, err := range utilerrors.Flatten(agg).Errors() {
+			var fieldErr *field.Error
+			if !xerrors.As(err, &fieldErr) {
+				fieldErr = field.Invalid(field.NewPath(""), nil, err.Error())
+			}
+			errs = append(errs, fieldErr)
+		}
+	}
+
+	wasmRegistry, err := getWasmRegistryFromUnversionedConfig(&cfg)
+	if err != nil {
+		return nil, xerrors.Errorf("get wasm plugins: %w", err)
+	}
+	inTree := InTreeRegistries()
+	registered := func(name string) bool {
+		_, inTreeOK := inTree[name]
+		_, outOfTreeOK := outOfTreeRegistries[name]
+		_, wasmOK := wasmRegistry[name]
+		return inTreeOK || outOfTreeOK || wasmOK
+	}
+	for i, profile := range cfg.Profiles {
+		if profile.Plugins == nil {
+			continue
+		}
+		path := field.NewPath("profiles").Index(i).Child("plugins")
+		for _, point := range extensionPoints(profile.Plugins) {
+			for j, p := range point.set.Enabled {
+				if !registered(p.Name) {
+					errs = append(errs, field.NotFound(path.Child(point.name, "enabled").Index(j).Child("name"), p.Name))
+				}
+			}
+		}
+	}
+
+	return errs, nil
+}
+
+type extensionPoint struct {
+	name string
+	set  config.PluginSet
+}
+
+// extensionPoints returns the plugin sets of all the extension points with the field names in the versioned configuration.
+func extensionPoints(p *config.Plugins) []extensionPoint {
+	return []extensionPoint{
+		{name: "preEnqueue", set: p.PreEnqueue},
+		{name: "queueSort", set: p.QueueSort},
+		{name: "preFilter", set: p.PreFilter},
+		{name: "filter", set: p.Filter},
+		{name: "postFilter", set: p.PostFilter},
+		{name: "preScore", set: p.PreScore},
+		{name: "score", set: p.Score},
+		{name: "reserve", set: p.Reserve},
+		{name: "permit", set: p.Permit},
+		{name: "preBind", set: p.PreBind},
+		{name: "bind", set: p.Bind},
+		{name: "postBind", set: p.PostBind},
+		{name: "multiPoint", set: p.MultiPoint},
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateSchedulerConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		cfg      func() *configv1.KubeSchedulerConfiguration
+		wantErrs []string
+	}{
+		{
+			name: "the default configuration is valid",
+			cfg: func() *configv1.KubeSchedulerConfiguration {
+				cfg, err := DefaultSchedulerConfig()
+				assert.NoError(t, err)
+				return cfg
+			},
+		},
+		{
+			name: "the configuration only with the profiles is valid since it's defaulted",
+			cfg: func() *configv1.KubeSchedulerConfiguration {
+				return &configv1.KubeSchedulerConfiguration{
+					Profiles: []configv1.KubeSchedulerProfile{{
+						SchedulerName: ptr.To("default-scheduler"),
+						Plugins: &configv1.Plugins{
+							Score: configv1.PluginSet{Disabled: []configv1.Plugin{{Name: "ImageLocality"}}},
+						},
+					}},
+				}
+			},
+		},
+		{
+			name: "the invalid values are reported with their field paths",
+			cfg: func() *configv1.KubeSchedulerConfiguration {
+				return &configv1.KubeSchedulerConfiguration{
+					Parallelism:              ptr.To[int32](-1),
+					PodInitialBackoffSeconds: ptr.To[int64](10),
+					PodMaxBackoffSeconds:     ptr.To[int64](1),
+				}
+			},
+			wantErrs: []string{"parallelism", "podMaxBackoffSeconds"},
+		},
+		{
+			name: "the unknown plugins are reported",
+			cfg: func() *configv1.KubeSchedulerConfiguration {
+				return &configv1.KubeSchedulerConfiguration{
+					Profiles: []configv1.KubeSchedulerProfile{{
+						SchedulerName: ptr.To("default-scheduler"),
+						Plugins: &configv1.Plugins{
+							Filter: configv1.PluginSet{Enabled: []configv1.Plugin{{Name: "NodeName"}, {Name: "NoSuchPlugin"}}},
+						},
+					}},
+				}
+			},
+			wantErrs: []string{"profiles[0].plugins.filter.enabled[1].name"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := tt.cfg()
+			want := cfg.DeepCopy()
+
+			errs, err := ValidateSchedulerConfig(cfg)
+			assert.NoError(t, err)
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Field)
+			}
+			assert.Equal(t, tt.wantErrs, got)
+			// the given configuration isn't modified.
+			assert.Equal(t, want, cfg)
+		})
+	}
+}
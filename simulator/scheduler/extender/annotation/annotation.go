@@ -9,4 +9,12 @@ const (
 	ExtenderPreemptResultAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/extender-preempt-result"
 	// ExtenderBindResultAnnotationKey has the binding result of extender.
 	ExtenderBindResultAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/extender-bind-result"
+	// ExtenderIgnoredResultAnnotationKey has the errors of the ignorable extenders, which were ignored in scheduling.
+	ExtenderIgnoredResultAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/extender-ignored-result"
+	// ExtenderSkippedResultAnnotationKey has the reasons why extender wasn't called for the pod.
+	ExtenderSkippedResultAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/extender-skipped-result"
+	// ExtenderInjectedResultAnnotationKey has the latencies and the failures injected to the requests to extender by the simulator.
+	ExtenderInjectedResultAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/extender-injected-result"
+	// ExtenderRawExchangeAnnotationKey has the raw requests to and responses from extender.
+	ExtenderRawExchangeAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/extender-raw-exchange"
 )
@@ -5,17 +5,18 @@ package extender
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
 	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/sets"
 	restclient "k8s.io/client-go/rest"
 	configv1 "k8s.io/kube-scheduler/config/v1"
 	extenderv1 "k8s.io/kube-scheduler/extender/v1"
-	"k8s.io/kubernetes/pkg/scheduler/framework"
 )
 
 const (
@@ -26,12 +27,26 @@ const (
 // Extender provides methods to call the actual extender's endpoint set by user.
 type Extender interface {
 	Name() string
+	IsIgnorable() bool
+	IsInterested(pod *corev1.Pod) bool
+	Weight() int64
 	Filter(args extenderv1.ExtenderArgs) (*extenderv1.ExtenderFilterResult, error)
 	Prioritize(args extenderv1.ExtenderArgs) (*extenderv1.HostPriorityList, error)
 	Preempt(args extenderv1.ExtenderPreemptionArgs) (*extenderv1.ExtenderPreemptionResult, error)
 	Bind(args extenderv1.ExtenderBindingArgs) (*extenderv1.ExtenderBindingResult, error)
 }
 
+// StatusError is returned when the extender server responds with the non-OK status code.
+type StatusError struct {
+	Action     string
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("failed %v with extender at URL %v, code %v", e.Action, e.URL, e.StatusCode)
+}
+
 type httpClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
@@ -46,12 +61,15 @@ type extender struct {
 	weight           int64
 	client           httpClient
 	nodeCacheCapable bool
+	ignorable        bool
 
 	// https://github.com/kubernetes/kubernetes/blob/fc04e732bb3e7198d2fa44efa5457c7c6f8c0f5b/pkg/scheduler/extender.go#L51
 	managedResources sets.Set[string]
 }
 
 // makeTransport makes http.Transport from the extender config.
+// The server's certificate is verified with the CA in TLSConfig, or the system's root CAs if it's not given,
+// and the verification is skipped only when TLSConfig.Insecure is explicitly set.
 func makeTransport(config *configv1.Extender) (http.RoundTripper, error) {
 	var cfg restclient.Config
 	if config.TLSConfig != nil {
@@ -64,15 +82,9 @@ func makeTransport(config *configv1.Extender) (http.RoundTripper, error) {
 		cfg.TLSClientConfig.KeyData = config.TLSConfig.KeyData
 		cfg.TLSClientConfig.CAData = config.TLSConfig.CAData
 	}
-	if config.EnableHTTPS {
-		hasCA := len(cfg.CAFile) > 0 || len(cfg.CAData) > 0
-		if !hasCA {
-			cfg.Insecure = true
-		}
-	}
 	tlsConfig, err := restclient.TLSConfigFor(&cfg)
 	if err != nil {
-		return nil, err
+		return nil, xerrors.Errorf("make TLS config: %w", err)
 	}
 	if tlsConfig != nil {
 		return utilnet.SetTransportDefaults(&http.Transport{
@@ -84,8 +96,9 @@ func makeTransport(config *configv1.Extender) (http.RoundTripper, error) {
 
 // newExtender creates an Extender object.
 func newExtender(config *configv1.Extender) (Extender, error) {
-	if config.HTTPTimeout.Duration.Nanoseconds() == 0 {
-		config.HTTPTimeout.Duration = DefaultExtenderTimeout
+	timeout := config.HTTPTimeout.Duration
+	if timeout == 0 {
+		timeout = DefaultExtenderTimeout
 	}
 
 	transport, err := makeTransport(config)
@@ -94,7 +107,7 @@ func newExtender(config *configv1.Extender) (Extender, error) {
 	}
 	client := &http.Client{
 		Transport: transport,
-		Timeout:   config.HTTPTimeout.Duration,
+		Timeout:   timeout,
 	}
 	managedResources := sets.New[string]()
 	for _, r := range config.ManagedResources {
@@ -109,6 +122,7 @@ func newExtender(config *configv1.Extender) (Extender, error) {
 		weight:           config.Weight,
 		client:           client,
 		nodeCacheCapable: config.NodeCacheCapable,
+		ignorable:        config.Ignorable,
 		managedResources: managedResources,
 	}, nil
 }
@@ -118,6 +132,49 @@ func (e *extender) Name() string {
 	return e.extenderURL
 }
 
+// IsIgnorable returns true if the scheduling should not fail when the extender returns an error or is not reachable.
+func (e *extender) IsIgnorable() bool {
+	return e.ignorable
+}
+
+// Weight returns the weight of the scores of the extender.
+func (e *extender) Weight() int64 {
+	return e.weight
+}
+
+// IsInterested returns true if the pod requests or limits at least one of the managed resources of the extender,
+// or the extender has no managed resources.
+// https://github.com/kubernetes/kubernetes/blob/fc04e732bb3e7198d2fa44efa5457c7c6f8c0f5b/pkg/scheduler/extender.go#L403
+func (e *extender) IsInterested(pod *corev1.Pod) bool {
+	if e.managedResources.Len() == 0 {
+		return true
+	}
+	if e.hasManagedResources(pod.Spec.Containers) {
+		return true
+	}
+	if e.hasManagedResources(pod.Spec.InitContainers) {
+		return true
+	}
+	return false
+}
+
+func (e *extender) hasManagedResources(containers []corev1.Container) bool {
+	for i := range containers {
+		container := &containers[i]
+		for resourceName := range container.Resources.Requests {
+			if e.managedResources.Has(string(resourceName)) {
+				return true
+			}
+		}
+		for resourceName := range container.Resources.Limits {
+			if e.managedResources.Has(string(resourceName)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Filter sends the request to the original extender server, and returns the response as is.
 func (e *extender) Filter(args extenderv1.ExtenderArgs) (*extenderv1.ExtenderFilterResult, error) {
 	var result extenderv1.ExtenderFilterResult
@@ -139,11 +196,7 @@ func (e *extender) Prioritize(args extenderv1.ExtenderArgs) (*extenderv1.HostPri
 	if err := e.send(e.prioritizeVerb, args, &result); err != nil {
 		return nil, xerrors.Errorf("send prioritize request: %w", err)
 	}
-	for i := range result {
-		// MaxExtenderPriority may diverge from the max priority used in the scheduler and defined by MaxNodeScore,
-		// therefore we need to scale the score returned by extenders to the score range used by the scheduler.
-		result[i].Score = result[i].Score * e.weight * (framework.MaxNodeScore / extenderv1.MaxExtenderPriority)
-	}
+	// The scores aren't weighted here because the scheduler applies the weight to the response.
 	return &result, nil
 }
 
@@ -193,7 +246,7 @@ func (e *extender) send(action string, args interface{}, result interface{}) err
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return xerrors.Errorf("failed %v with extender at URL %v, code %v", action, url, resp.StatusCode)
+		return &StatusError{Action: action, URL: url, StatusCode: resp.StatusCode}
 	}
 	return json.NewDecoder(resp.Body).Decode(result)
 }
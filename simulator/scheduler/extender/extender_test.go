@@ -1,17 +1,30 @@
 package extender
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"io"
 	"math"
+	"math/big"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	configv1 "k8s.io/kube-scheduler/config/v1"
 	extenderv1 "k8s.io/kube-scheduler/extender/v1"
 )
 
@@ -234,3 +247,186 @@ func TestHttpExtender_send(t *testing.T) {
 		})
 	}
 }
+
+// newCertificate generates the certificate and the key signed by parent, or self-signed one if parent is nil.
+// It returns the certificate and the key in PEM together with the parsed certificate.
+func newCertificate(t *testing.T, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) ([]byte, []byte, *x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), cert, key
+}
+
+func Test_newExtender_TLS(t *testing.T) {
+	t.Parallel()
+
+	// The CA which signs the client certificates accepted by the extender.
+	_, _, clientCA, clientCAKey := newCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "client-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+	clientCert, clientKey, _, _ := newCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "kube-scheduler-simulator"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, clientCA, clientCAKey)
+
+	// The extender requires the client certificate signed by clientCA.
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		//nolint:errcheck // it's just a stub.
+		w.Write([]byte(`{"NodeNames":["node1"]}`))
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCA)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	tests := []struct {
+		name      string
+		tlsConfig *configv1.ExtenderTLSConfig
+		wantErr   bool
+	}{
+		{
+			name:      "connect to the extender with the CA and the client certificate",
+			tlsConfig: &configv1.ExtenderTLSConfig{CAData: serverCA, CertData: clientCert, KeyData: clientKey},
+		},
+		{
+			name:      "skip the verification of the server certificate only when insecure is set",
+			tlsConfig: &configv1.ExtenderTLSConfig{Insecure: true, CertData: clientCert, KeyData: clientKey},
+		},
+		{
+			name:      "fail to verify the server certificate without the CA",
+			tlsConfig: &configv1.ExtenderTLSConfig{CertData: clientCert, KeyData: clientKey},
+			wantErr:   true,
+		},
+		{
+			name:      "fail without the client certificate",
+			tlsConfig: &configv1.ExtenderTLSConfig{CAData: serverCA},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, err := newExtender(&configv1.Extender{
+				URLPrefix:   server.URL,
+				FilterVerb:  "filter",
+				EnableHTTPS: true,
+				TLSConfig:   tt.tlsConfig,
+			})
+			require.NoError(t, err)
+
+			result, err := e.Filter(extenderv1.ExtenderArgs{})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []string{"node1"}, *result.NodeNames)
+		})
+	}
+}
+
+func Test_newExtender_HTTPTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		httpTimeout time.Duration
+		want        time.Duration
+	}{
+		{
+			name:        "use httpTimeout in the config",
+			httpTimeout: 10 * time.Second,
+			want:        10 * time.Second,
+		},
+		{
+			name: "use DefaultExtenderTimeout when httpTimeout isn't set",
+			want: DefaultExtenderTimeout,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, err := newExtender(&configv1.Extender{URLPrefix: "http://example.com", HTTPTimeout: metav1.Duration{Duration: tt.httpTimeout}})
+			require.NoError(t, err)
+			c, ok := e.(*extender).client.(*http.Client)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, c.Timeout)
+		})
+	}
+}
+
+func Test_extender_IsInterested(t *testing.T) {
+	t.Parallel()
+
+	gpu := corev1.ResourceList{"example.com/gpu": resource.MustParse("1")}
+	cpu := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}
+	tests := []struct {
+		name             string
+		managedResources []configv1.ExtenderManagedResource
+		pod              *corev1.Pod
+		want             bool
+	}{
+		{
+			name: "interested in any pod if the extender has no managed resources",
+			pod:  &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: cpu}}}}},
+			want: true,
+		},
+		{
+			name:             "interested in the pod requesting the managed resource",
+			managedResources: []configv1.ExtenderManagedResource{{Name: "example.com/gpu"}},
+			pod:              &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: gpu}}}}},
+			want:             true,
+		},
+		{
+			name:             "interested in the pod limiting the managed resource",
+			managedResources: []configv1.ExtenderManagedResource{{Name: "example.com/gpu", IgnoredByScheduler: true}},
+			pod:              &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{Limits: gpu}}}}},
+			want:             true,
+		},
+		{
+			name:             "interested in the pod whose init container requests the managed resource",
+			managedResources: []configv1.ExtenderManagedResource{{Name: "example.com/gpu"}},
+			pod:              &corev1.Pod{Spec: corev1.PodSpec{InitContainers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: gpu}}}}},
+			want:             true,
+		},
+		{
+			name:             "not interested in the pod requesting none of the managed resources",
+			managedResources: []configv1.ExtenderManagedResource{{Name: "example.com/gpu"}},
+			pod:              &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: cpu}}}}},
+			want:             false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, err := newExtender(&configv1.Extender{URLPrefix: "http://example.com", ManagedResources: tt.managedResources})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, e.IsInterested(tt.pod))
+		})
+	}
+}
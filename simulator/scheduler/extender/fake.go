@@ -0,0 +1,135 @@
+package extender
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+
+	"golang.org/x/xerrors"
+	"k8s.io/klog/v2"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+)
+
+const (
+	fakeFilterVerb     = "filter"
+	fakePrioritizeVerb = "prioritize"
+	fakePreemptVerb    = "preempt"
+	fakeBindVerb       = "bind"
+)
+
+// FakeExtenderHandlers has the callbacks which the fake extender calls on each request.
+// The verb whose callback is nil isn't served, and isn't set in the extender config.
+// When the callback returns an error, the fake extender responds with 500.
+type FakeExtenderHandlers struct {
+	Filter     func(args extenderv1.ExtenderArgs) (*extenderv1.ExtenderFilterResult, error)
+	Prioritize func(args extenderv1.ExtenderArgs) (*extenderv1.HostPriorityList, error)
+	Preempt    func(args extenderv1.ExtenderPreemptionArgs) (*extenderv1.ExtenderPreemptionResult, error)
+	Bind       func(args extenderv1.ExtenderBindingArgs) (*extenderv1.ExtenderBindingResult, error)
+}
+
+// FakeExtender is an in-process HTTP extender server which serves the requests with the user-supplied callbacks,
+// so that the extender semantics can be tried in the simulator without deploying a real extender.
+type FakeExtender struct {
+	handlers FakeExtenderHandlers
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewFake starts the fake extender listening on a random port of localhost.
+// The caller must call Close to stop it.
+func NewFake(handlers FakeExtenderHandlers) (*FakeExtender, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, xerrors.Errorf("listen for fake extender: %w", err)
+	}
+
+	f := &FakeExtender{
+		handlers: handlers,
+		listener: listener,
+	}
+	mux := http.NewServeMux()
+	if handlers.Filter != nil {
+		mux.HandleFunc("/"+fakeFilterVerb, serveFake(handlers.Filter))
+	}
+	if handlers.Prioritize != nil {
+		mux.HandleFunc("/"+fakePrioritizeVerb, serveFake(handlers.Prioritize))
+	}
+	if handlers.Preempt != nil {
+		mux.HandleFunc("/"+fakePreemptVerb, serveFake(handlers.Preempt))
+	}
+	if handlers.Bind != nil {
+		mux.HandleFunc("/"+fakeBindVerb, serveFake(handlers.Bind))
+	}
+	f.server = &http.Server{Handler: mux} //nolint:gosec // it's only for the local experiments.
+
+	go func() {
+		if err := f.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			klog.Errorf("fake extender stopped: %+v", err)
+		}
+	}()
+
+	return f, nil
+}
+
+// serveFake makes the http.HandlerFunc which decodes the request into Args, and responds with the result of fn.
+func serveFake[Args any, Result any](fn func(args Args) (*Result, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var args Args
+		if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err := fn(args)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			klog.Errorf("failed to write the response of fake extender: %+v", err)
+		}
+	}
+}
+
+// URL returns the URL prefix of the fake extender.
+func (f *FakeExtender) URL() string {
+	return "http://" + f.listener.Addr().String() + "/"
+}
+
+// Config returns the extender config to call the fake extender.
+// Only the verbs which have the callback are set, and the weight is 1.
+func (f *FakeExtender) Config() configv1.Extender {
+	cfg := configv1.Extender{
+		URLPrefix: f.URL(),
+	}
+	if f.handlers.Filter != nil {
+		cfg.FilterVerb = fakeFilterVerb
+	}
+	if f.handlers.Prioritize != nil {
+		cfg.PrioritizeVerb = fakePrioritizeVerb
+		cfg.Weight = 1
+	}
+	if f.handlers.Preempt != nil {
+		cfg.PreemptVerb = fakePreemptVerb
+	}
+	if f.handlers.Bind != nil {
+		cfg.BindVerb = fakeBindVerb
+	}
+	return cfg
+}
+
+// AddTo appends the fake extender to the extenders of the scheduler config.
+// It should be called before the config is overridden to go through the simulator's proxy.
+func (f *FakeExtender) AddTo(cfg *configv1.KubeSchedulerConfiguration) {
+	cfg.Extenders = append(cfg.Extenders, f.Config())
+}
+
+// Close stops the fake extender.
+func (f *FakeExtender) Close() error {
+	if err := f.server.Close(); err != nil {
+		return xerrors.Errorf("close fake extender: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package extender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+)
+
+func TestFakeExtender_rejectNode(t *testing.T) {
+	t.Parallel()
+
+	// The fake extender rejects node2, and passes the other nodes.
+	fake, err := NewFake(FakeExtenderHandlers{
+		Filter: func(args extenderv1.ExtenderArgs) (*extenderv1.ExtenderFilterResult, error) {
+			nodeNames := []string{}
+			failedNodes := extenderv1.FailedNodesMap{}
+			for _, n := range *args.NodeNames {
+				if n == "node2" {
+					failedNodes[n] = "node2 is rejected"
+					continue
+				}
+				nodeNames = append(nodeNames, n)
+			}
+			return &extenderv1.ExtenderFilterResult{NodeNames: &nodeNames, FailedNodes: failedNodes}, nil
+		},
+	})
+	require.NoError(t, err)
+	defer fake.Close()
+
+	cfg := &configv1.KubeSchedulerConfiguration{}
+	fake.AddTo(cfg)
+	require.Len(t, cfg.Extenders, 1)
+	e, err := newExtender(&cfg.Extenders[0])
+	require.NoError(t, err)
+
+	got, err := e.Filter(extenderv1.ExtenderArgs{
+		Pod:       &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}},
+		NodeNames: &[]string{"node1", "node2", "node3"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &extenderv1.ExtenderFilterResult{
+		NodeNames:   &[]string{"node1", "node3"},
+		FailedNodes: extenderv1.FailedNodesMap{"node2": "node2 is rejected"},
+	}, got)
+}
+
+func TestFakeExtender_Config(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		handlers FakeExtenderHandlers
+		want     func(url string) configv1.Extender
+	}{
+		{
+			name: "only the verbs with the callback are set",
+			handlers: FakeExtenderHandlers{
+				Prioritize: func(extenderv1.ExtenderArgs) (*extenderv1.HostPriorityList, error) {
+					return &extenderv1.HostPriorityList{}, nil
+				},
+				Bind: func(extenderv1.ExtenderBindingArgs) (*extenderv1.ExtenderBindingResult, error) {
+					return &extenderv1.ExtenderBindingResult{}, nil
+				},
+			},
+			want: func(url string) configv1.Extender {
+				return configv1.Extender{
+					URLPrefix:      url,
+					PrioritizeVerb: "prioritize",
+					Weight:         1,
+					BindVerb:       "bind",
+				}
+			},
+		},
+		{
+			name: "all verbs are set",
+			handlers: FakeExtenderHandlers{
+				Filter: func(extenderv1.ExtenderArgs) (*extenderv1.ExtenderFilterResult, error) {
+					return &extenderv1.ExtenderFilterResult{}, nil
+				},
+				Prioritize: func(extenderv1.ExtenderArgs) (*extenderv1.HostPriorityList, error) {
+					return &extenderv1.HostPriorityList{}, nil
+				},
+				Preempt: func(extenderv1.ExtenderPreemptionArgs) (*extenderv1.ExtenderPreemptionResult, error) {
+					return &extenderv1.ExtenderPreemptionResult{}, nil
+				},
+				Bind: func(extenderv1.ExtenderBindingArgs) (*extenderv1.ExtenderBindingResult, error) {
+					return &extenderv1.ExtenderBindingResult{}, nil
+				},
+			},
+			want: func(url string) configv1.Extender {
+				return configv1.Extender{
+					URLPrefix:      url,
+					FilterVerb:     "filter",
+					PrioritizeVerb: "prioritize",
+					Weight:         1,
+					PreemptVerb:    "preempt",
+					BindVerb:       "bind",
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			fake, err := NewFake(tt.handlers)
+			require.NoError(t, err)
+			defer fake.Close()
+
+			assert.Equal(t, tt.want(fake.URL()), fake.Config())
+		})
+	}
+}
+
+func TestFakeExtender_error(t *testing.T) {
+	t.Parallel()
+	fake, err := NewFake(FakeExtenderHandlers{
+		Prioritize: func(extenderv1.ExtenderArgs) (*extenderv1.HostPriorityList, error) {
+			return nil, xerrors.New("prioritize failed")
+		},
+	})
+	require.NoError(t, err)
+	defer fake.Close()
+
+	cfg := fake.Config()
+	e, err := newExtender(&cfg)
+	require.NoError(t, err)
+
+	_, err = e.Prioritize(extenderv1.ExtenderArgs{Pod: &corev1.Pod{}, NodeNames: &[]string{"node1"}})
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, 500, statusErr.StatusCode)
+}
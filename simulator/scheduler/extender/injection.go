@@ -0,0 +1,118 @@
+package extender
+
+import (
+	"math/rand"
+	"time"
+
+	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender/resultstore"
+)
+
+// ErrInjected is returned when the failure is injected to the request to the extender.
+var ErrInjected = xerrors.New("failure injected by the simulator")
+
+// ErrInvalidInjection is returned when the injection has an invalid value or the extender doesn't exist.
+var ErrInvalidInjection = xerrors.New("invalid injection")
+
+// Injection is the behavior which the simulator injects to the requests to an extender before forwarding them,
+// to see how the scheduling is affected by a slow or unstable extender without modifying the extender.
+type Injection struct {
+	// MinLatency and MaxLatency are the range of the artificial delay added before each request.
+	// The delay is chosen uniformly at random from the range. The delay is always MinLatency if MaxLatency is zero.
+	MinLatency metav1.Duration `json:"minLatency,omitempty"`
+	MaxLatency metav1.Duration `json:"maxLatency,omitempty"`
+	// ErrorRate is the probability from 0 to 1 that the request fails with ErrInjected without being forwarded.
+	ErrorRate float64 `json:"errorRate,omitempty"`
+}
+
+// WithInjections creates an Option to inject the behaviors to the requests to the extenders.
+// injections is keyed by the index of the extender in the scheduler configuration.
+func WithInjections(injections map[int]Injection) Option {
+	return func(s *Service) {
+		for id, injection := range injections {
+			s.injections[id] = injection
+		}
+	}
+}
+
+// WithInjectionSeed creates an Option to seed the random number generator which decides the injected behaviors,
+// so that the injection is reproducible.
+func WithInjectionSeed(seed int64) Option {
+	return func(s *Service) {
+		s.injectionRand = rand.New(rand.NewSource(seed)) //nolint:gosec // it's not for security.
+	}
+}
+
+// SetInjection replaces the injection to the extender at the index id.
+// The zero Injection stops the injection.
+func (s *Service) SetInjection(id int, injection Injection) error {
+	if err := s.validateInjection(id, injection); err != nil {
+		return err
+	}
+	s.injectionMu.Lock()
+	defer s.injectionMu.Unlock()
+	if injection == (Injection{}) {
+		delete(s.injections, id)
+		return nil
+	}
+	s.injections[id] = injection
+	return nil
+}
+
+// Injections returns the current injections keyed by the index of the extender.
+func (s *Service) Injections() map[int]Injection {
+	s.injectionMu.Lock()
+	defer s.injectionMu.Unlock()
+	ret := make(map[int]Injection, len(s.injections))
+	for id, injection := range s.injections {
+		ret[id] = injection
+	}
+	return ret
+}
+
+func (s *Service) validateInjection(id int, injection Injection) error {
+	if id < 0 || id >= len(s.extenders) {
+		return xerrors.Errorf("extender %d doesn't exist: %w", id, ErrInvalidInjection)
+	}
+	if injection.MinLatency.Duration < 0 || (injection.MaxLatency.Duration != 0 && injection.MaxLatency.Duration < injection.MinLatency.Duration) {
+		return xerrors.Errorf("minLatency must not be negative, and maxLatency must be zero or greater than or equal to minLatency: %w", ErrInvalidInjection)
+	}
+	if injection.ErrorRate < 0 || injection.ErrorRate > 1 {
+		return xerrors.Errorf("errorRate must be between 0 and 1: %w", ErrInvalidInjection)
+	}
+	return nil
+}
+
+// inject applies the injection to the request of the verb to the extender at the index id,
+// and stores what's injected as the result of the pod.
+// It returns ErrInjected if the failure is injected, and then the request must not be forwarded.
+func (s *Service) inject(id int, verb string, request interface{}) error {
+	s.injectionMu.Lock()
+	injection, ok := s.injections[id]
+	if !ok {
+		s.injectionMu.Unlock()
+		return nil
+	}
+	latency := injection.MinLatency.Duration
+	if d := injection.MaxLatency.Duration - injection.MinLatency.Duration; d > 0 {
+		latency += time.Duration(s.injectionRand.Int63n(int64(d) + 1))
+	}
+	failed := s.injectionRand.Float64() < injection.ErrorRate
+	s.injectionMu.Unlock()
+
+	result := resultstore.InjectedResult{}
+	if latency > 0 {
+		result.Latency = latency.String()
+		s.sleep(latency)
+	}
+	var err error
+	if failed {
+		err = ErrInjected
+		result.Error = err.Error()
+	}
+	namespace, podName := podOf(request)
+	s.store.AddInjectedResult(namespace, podName, verb, result, s.extenders[id].Name())
+	return err
+}
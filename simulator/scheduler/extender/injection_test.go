@@ -0,0 +1,156 @@
+package extender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender/mock_extender"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender/resultstore"
+)
+
+func TestService_Filter_injection(t *testing.T) {
+	t.Parallel()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+	// filterWithInjection calls Filter n times with the injection and the seed,
+	// and returns the injected results stored for each call and the latencies slept.
+	filterWithInjection := func(t *testing.T, injection Injection, seed int64, n int) ([]resultstore.InjectedResult, []time.Duration) {
+		t.Helper()
+		ctrl := gomock.NewController(t)
+		mStore := mock_extender.NewMockStore(ctrl)
+		mExtender := mock_extender.NewMockExtender(ctrl)
+		mExtender.EXPECT().IsInterested(pod).Return(true).AnyTimes()
+		mExtender.EXPECT().IsIgnorable().Return(true).AnyTimes()
+		mExtender.EXPECT().Name().Return("ext1").AnyTimes()
+		mExtender.EXPECT().Filter(gomock.Any()).Return(&extenderv1.ExtenderFilterResult{}, nil).AnyTimes()
+		mStore.EXPECT().AddFilterResult(gomock.Any(), gomock.Any(), "ext1").AnyTimes()
+		mStore.EXPECT().AddIgnoredResult(pod, "filter", ErrInjected, "ext1").AnyTimes()
+		var injected []resultstore.InjectedResult
+		mStore.EXPECT().AddInjectedResult("default", "pod1", "filter", gomock.Any(), "ext1").Do(
+			func(_, _, _ string, result resultstore.InjectedResult, _ string) {
+				injected = append(injected, result)
+			}).Times(n)
+
+		var slept []time.Duration
+		s := &Service{
+			client:     fake.NewSimpleClientset(),
+			extenders:  []Extender{mExtender},
+			store:      mStore,
+			injections: map[int]Injection{},
+			sleep:      func(d time.Duration) { slept = append(slept, d) },
+		}
+		WithInjectionSeed(seed)(s)
+		require.NoError(t, s.SetInjection(0, injection))
+		for i := 0; i < n; i++ {
+			_, err := s.Filter(0, extenderv1.ExtenderArgs{Pod: pod})
+			require.NoError(t, err)
+		}
+		return injected, slept
+	}
+
+	t.Run("inject the failure to all requests if errorRate is 1", func(t *testing.T) {
+		t.Parallel()
+		injected, slept := filterWithInjection(t, Injection{ErrorRate: 1}, 1, 3)
+		for _, r := range injected {
+			assert.Equal(t, resultstore.InjectedResult{Error: ErrInjected.Error()}, r)
+		}
+		assert.Empty(t, slept)
+	})
+	t.Run("inject the latencies within the range", func(t *testing.T) {
+		t.Parallel()
+		injection := Injection{MinLatency: metav1.Duration{Duration: 100 * time.Millisecond}, MaxLatency: metav1.Duration{Duration: 200 * time.Millisecond}}
+		injected, slept := filterWithInjection(t, injection, 1, 10)
+		require.Len(t, slept, 10)
+		for i, d := range slept {
+			assert.GreaterOrEqual(t, d, 100*time.Millisecond)
+			assert.LessOrEqual(t, d, 200*time.Millisecond)
+			assert.Equal(t, resultstore.InjectedResult{Latency: d.String()}, injected[i])
+		}
+	})
+	t.Run("inject the same behaviors with the same seed", func(t *testing.T) {
+		t.Parallel()
+		injection := Injection{MinLatency: metav1.Duration{Duration: time.Millisecond}, MaxLatency: metav1.Duration{Duration: time.Second}, ErrorRate: 0.5}
+		injected1, slept1 := filterWithInjection(t, injection, 42, 20)
+		injected2, slept2 := filterWithInjection(t, injection, 42, 20)
+		assert.Equal(t, injected1, injected2)
+		assert.Equal(t, slept1, slept2)
+		var failed int
+		for _, r := range injected1 {
+			if r.Error != "" {
+				failed++
+			}
+		}
+		// Both of the failures and the successes should be injected with the rate.
+		assert.NotZero(t, failed)
+		assert.NotEqual(t, 20, failed)
+	})
+}
+
+func TestService_SetInjection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		id        int
+		injection Injection
+		want      map[int]Injection
+		wantErr   bool
+	}{
+		{
+			name:      "set the injection",
+			id:        0,
+			injection: Injection{MinLatency: metav1.Duration{Duration: time.Second}, ErrorRate: 0.1},
+			want:      map[int]Injection{0: {MinLatency: metav1.Duration{Duration: time.Second}, ErrorRate: 0.1}, 1: {ErrorRate: 1}},
+		},
+		{
+			name:      "stop the injection with the empty injection",
+			id:        1,
+			injection: Injection{},
+			want:      map[int]Injection{0: {ErrorRate: 1}},
+		},
+		{
+			name:      "fail if the extender doesn't exist",
+			id:        2,
+			injection: Injection{ErrorRate: 0.1},
+			wantErr:   true,
+		},
+		{
+			name:      "fail if errorRate is greater than 1",
+			id:        0,
+			injection: Injection{ErrorRate: 1.5},
+			wantErr:   true,
+		},
+		{
+			name:      "fail if maxLatency is less than minLatency",
+			id:        0,
+			injection: Injection{MinLatency: metav1.Duration{Duration: time.Second}, MaxLatency: metav1.Duration{Duration: time.Millisecond}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			s := &Service{
+				extenders:  []Extender{mock_extender.NewMockExtender(ctrl), mock_extender.NewMockExtender(ctrl)},
+				injections: map[int]Injection{0: {ErrorRate: 1}, 1: {ErrorRate: 1}},
+			}
+			err := s.SetInjection(tt.id, tt.injection)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidInjection)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.Injections())
+		})
+	}
+}
@@ -13,7 +13,8 @@ import (
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
-	v1 "k8s.io/kube-scheduler/extender/v1"
+	v1 "k8s.io/api/core/v1"
+	v10 "k8s.io/kube-scheduler/extender/v1"
 )
 
 // MockExtender is a mock of Extender interface.
@@ -41,10 +42,10 @@ func (m *MockExtender) EXPECT() *MockExtenderMockRecorder {
 }
 
 // Bind mocks base method.
-func (m *MockExtender) Bind(args v1.ExtenderBindingArgs) (*v1.ExtenderBindingResult, error) {
+func (m *MockExtender) Bind(args v10.ExtenderBindingArgs) (*v10.ExtenderBindingResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Bind", args)
-	ret0, _ := ret[0].(*v1.ExtenderBindingResult)
+	ret0, _ := ret[0].(*v10.ExtenderBindingResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -56,10 +57,10 @@ func (mr *MockExtenderMockRecorder) Bind(args any) *gomock.Call {
 }
 
 // Filter mocks base method.
-func (m *MockExtender) Filter(args v1.ExtenderArgs) (*v1.ExtenderFilterResult, error) {
+func (m *MockExtender) Filter(args v10.ExtenderArgs) (*v10.ExtenderFilterResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Filter", args)
-	ret0, _ := ret[0].(*v1.ExtenderFilterResult)
+	ret0, _ := ret[0].(*v10.ExtenderFilterResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -70,6 +71,34 @@ func (mr *MockExtenderMockRecorder) Filter(args any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Filter", reflect.TypeOf((*MockExtender)(nil).Filter), args)
 }
 
+// IsIgnorable mocks base method.
+func (m *MockExtender) IsIgnorable() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsIgnorable")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsIgnorable indicates an expected call of IsIgnorable.
+func (mr *MockExtenderMockRecorder) IsIgnorable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsIgnorable", reflect.TypeOf((*MockExtender)(nil).IsIgnorable))
+}
+
+// IsInterested mocks base method.
+func (m *MockExtender) IsInterested(pod *v1.Pod) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsInterested", pod)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsInterested indicates an expected call of IsInterested.
+func (mr *MockExtenderMockRecorder) IsInterested(pod any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsInterested", reflect.TypeOf((*MockExtender)(nil).IsInterested), pod)
+}
+
 // Name mocks base method.
 func (m *MockExtender) Name() string {
 	m.ctrl.T.Helper()
@@ -85,10 +114,10 @@ func (mr *MockExtenderMockRecorder) Name() *gomock.Call {
 }
 
 // Preempt mocks base method.
-func (m *MockExtender) Preempt(args v1.ExtenderPreemptionArgs) (*v1.ExtenderPreemptionResult, error) {
+func (m *MockExtender) Preempt(args v10.ExtenderPreemptionArgs) (*v10.ExtenderPreemptionResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Preempt", args)
-	ret0, _ := ret[0].(*v1.ExtenderPreemptionResult)
+	ret0, _ := ret[0].(*v10.ExtenderPreemptionResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -100,10 +129,10 @@ func (mr *MockExtenderMockRecorder) Preempt(args any) *gomock.Call {
 }
 
 // Prioritize mocks base method.
-func (m *MockExtender) Prioritize(args v1.ExtenderArgs) (*v1.HostPriorityList, error) {
+func (m *MockExtender) Prioritize(args v10.ExtenderArgs) (*v10.HostPriorityList, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Prioritize", args)
-	ret0, _ := ret[0].(*v1.HostPriorityList)
+	ret0, _ := ret[0].(*v10.HostPriorityList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -113,3 +142,17 @@ func (mr *MockExtenderMockRecorder) Prioritize(args any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prioritize", reflect.TypeOf((*MockExtender)(nil).Prioritize), args)
 }
+
+// Weight mocks base method.
+func (m *MockExtender) Weight() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Weight")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// Weight indicates an expected call of Weight.
+func (mr *MockExtenderMockRecorder) Weight() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Weight", reflect.TypeOf((*MockExtender)(nil).Weight))
+}
@@ -15,6 +15,7 @@ import (
 	gomock "go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	v10 "k8s.io/kube-scheduler/extender/v1"
+	resultstore "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender/resultstore"
 )
 
 // MockStore is a mock of Store interface.
@@ -65,6 +66,30 @@ func (mr *MockStoreMockRecorder) AddFilterResult(args, result, hostName any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFilterResult", reflect.TypeOf((*MockStore)(nil).AddFilterResult), args, result, hostName)
 }
 
+// AddIgnoredResult mocks base method.
+func (m *MockStore) AddIgnoredResult(pod *v1.Pod, verb string, err error, hostName string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddIgnoredResult", pod, verb, err, hostName)
+}
+
+// AddIgnoredResult indicates an expected call of AddIgnoredResult.
+func (mr *MockStoreMockRecorder) AddIgnoredResult(pod, verb, err, hostName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddIgnoredResult", reflect.TypeOf((*MockStore)(nil).AddIgnoredResult), pod, verb, err, hostName)
+}
+
+// AddInjectedResult mocks base method.
+func (m *MockStore) AddInjectedResult(namespace, podName, verb string, result resultstore.InjectedResult, hostName string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddInjectedResult", namespace, podName, verb, result, hostName)
+}
+
+// AddInjectedResult indicates an expected call of AddInjectedResult.
+func (mr *MockStoreMockRecorder) AddInjectedResult(namespace, podName, verb, result, hostName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddInjectedResult", reflect.TypeOf((*MockStore)(nil).AddInjectedResult), namespace, podName, verb, result, hostName)
+}
+
 // AddPreemptResult mocks base method.
 func (m *MockStore) AddPreemptResult(args v10.ExtenderPreemptionArgs, result v10.ExtenderPreemptionResult, hostName string) {
 	m.ctrl.T.Helper()
@@ -89,6 +114,30 @@ func (mr *MockStoreMockRecorder) AddPrioritizeResult(args, result, hostName any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPrioritizeResult", reflect.TypeOf((*MockStore)(nil).AddPrioritizeResult), args, result, hostName)
 }
 
+// AddRawExchange mocks base method.
+func (m *MockStore) AddRawExchange(namespace, podName, verb string, exchange resultstore.RawExchange, hostName string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddRawExchange", namespace, podName, verb, exchange, hostName)
+}
+
+// AddRawExchange indicates an expected call of AddRawExchange.
+func (mr *MockStoreMockRecorder) AddRawExchange(namespace, podName, verb, exchange, hostName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRawExchange", reflect.TypeOf((*MockStore)(nil).AddRawExchange), namespace, podName, verb, exchange, hostName)
+}
+
+// AddSkippedResult mocks base method.
+func (m *MockStore) AddSkippedResult(pod *v1.Pod, verb, reason, hostName string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddSkippedResult", pod, verb, reason, hostName)
+}
+
+// AddSkippedResult indicates an expected call of AddSkippedResult.
+func (mr *MockStoreMockRecorder) AddSkippedResult(pod, verb, reason, hostName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSkippedResult", reflect.TypeOf((*MockStore)(nil).AddSkippedResult), pod, verb, reason, hostName)
+}
+
 // DeleteData mocks base method.
 func (m *MockStore) DeleteData(pod v1.Pod) {
 	m.ctrl.T.Helper()
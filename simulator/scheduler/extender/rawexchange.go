@@ -0,0 +1,111 @@
+package extender
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender/resultstore"
+)
+
+// DefaultRawExchangeMaxSize is the default max size in bytes of each of the recorded request and response.
+const DefaultRawExchangeMaxSize = 4096
+
+// Option configures Service.
+type Option func(s *Service)
+
+// WithRawExchange creates an Option to record the raw request from the scheduler and the response to it for each extender,
+// which is stored in the annotation of the pod.
+// The request and the response longer than maxSize bytes are truncated. DefaultRawExchangeMaxSize is used if maxSize isn't positive.
+// If nodeNamesOnly is true, the node lists in the filter and prioritize requests and the filter response are recorded with the node names only.
+func WithRawExchange(maxSize int, nodeNamesOnly bool) Option {
+	return func(s *Service) {
+		if maxSize <= 0 {
+			maxSize = DefaultRawExchangeMaxSize
+		}
+		s.rawExchange = &rawExchangeConfig{maxSize: maxSize, nodeNamesOnly: nodeNamesOnly}
+	}
+}
+
+type rawExchangeConfig struct {
+	maxSize       int
+	nodeNamesOnly bool
+}
+
+// recordRawExchange stores the request and the response of the verb to the extender if the recording is enabled.
+// The response is omitted if the extender failed.
+func (s *Service) recordRawExchange(verb string, request, response interface{}, e Extender) {
+	if s.rawExchange == nil {
+		return
+	}
+	namespace, podName := podOf(request)
+	exchange := resultstore.RawExchange{}
+	exchange.Request, exchange.Truncated = s.rawExchange.encode(request)
+	if resp, truncated := s.rawExchange.encode(response); resp != "null" {
+		exchange.Response = resp
+		exchange.Truncated = exchange.Truncated || truncated
+	}
+	s.store.AddRawExchange(namespace, podName, verb, exchange, e.Name())
+}
+
+// podOf returns the namespace and the name of the pod in the request.
+func podOf(request interface{}) (string, string) {
+	switch r := request.(type) {
+	case extenderv1.ExtenderArgs:
+		return r.Pod.Namespace, r.Pod.Name
+	case extenderv1.ExtenderPreemptionArgs:
+		return r.Pod.Namespace, r.Pod.Name
+	case extenderv1.ExtenderBindingArgs:
+		return r.PodNamespace, r.PodName
+	default:
+		return "", ""
+	}
+}
+
+// encode returns v in JSON, and whether it's truncated.
+func (c *rawExchangeConfig) encode(v interface{}) (string, bool) {
+	if c.nodeNamesOnly {
+		v = nodeNamesOnly(v)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		klog.Errorf("failed to encode the request or the response to the extender: %+v", err)
+		return "", false
+	}
+	if len(b) > c.maxSize {
+		return string(b[:c.maxSize]), true
+	}
+	return string(b), false
+}
+
+// nodeNamesOnly replaces the node list in v with the node names.
+func nodeNamesOnly(v interface{}) interface{} {
+	switch v := v.(type) {
+	case extenderv1.ExtenderArgs:
+		if v.Nodes != nil {
+			v.NodeNames = nodeNames(v.Nodes)
+			v.Nodes = nil
+		}
+		return v
+	case *extenderv1.ExtenderFilterResult:
+		if v != nil && v.Nodes != nil {
+			r := *v
+			r.NodeNames = nodeNames(v.Nodes)
+			r.Nodes = nil
+			return &r
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func nodeNames(nodes *corev1.NodeList) *[]string {
+	names := make([]string, 0, len(nodes.Items))
+	for i := range nodes.Items {
+		names = append(names, nodes.Items[i].Name)
+	}
+	return &names
+}
@@ -19,6 +19,26 @@ type Store interface {
 	AddPrioritizeResult(args extenderv1.ExtenderArgs, result extenderv1.HostPriorityList, hostName string)
 	AddPreemptResult(args extenderv1.ExtenderPreemptionArgs, result extenderv1.ExtenderPreemptionResult, hostName string)
 	AddBindResult(args extenderv1.ExtenderBindingArgs, result extenderv1.ExtenderBindingResult, hostName string)
+	AddIgnoredResult(pod *v1.Pod, verb string, err error, hostName string)
+	AddSkippedResult(pod *v1.Pod, verb, reason, hostName string)
+	AddRawExchange(namespace, podName, verb string, exchange RawExchange, hostName string)
+	AddInjectedResult(namespace, podName, verb string, result InjectedResult, hostName string)
+}
+
+// InjectedResult is the behavior injected to the request to extender by the simulator.
+type InjectedResult struct {
+	// Latency is the artificial delay added before the request.
+	Latency string `json:"latency,omitempty"`
+	// Error is the injected failure. The request isn't sent to extender if it's non-empty.
+	Error string `json:"error,omitempty"`
+}
+
+// RawExchange is the request sent to extender and the response from it in JSON.
+type RawExchange struct {
+	Request  string `json:"request"`
+	Response string `json:"response,omitempty"`
+	// Truncated is true if the request or the response is truncated due to its size.
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 // store has results of all extenders.
@@ -41,6 +61,22 @@ type result struct {
 	preempt map[string]extenderv1.ExtenderPreemptionResult
 
 	bind map[string]extenderv1.ExtenderBindingResult
+
+	// ignored has the errors of the ignorable extenders, keyed by the extender and the verb.
+	// It's initialized lazily since most extenders are not ignorable.
+	ignored map[string]map[string]string
+
+	// skipped has the reasons why the extenders weren't called, keyed by the extender and the verb.
+	// It's initialized lazily since most extenders are called for all pods.
+	skipped map[string]map[string]string
+
+	// rawExchange has the raw requests and responses keyed by the extender and the verb.
+	// It's initialized lazily since the recording is disabled by default.
+	rawExchange map[string]map[string]RawExchange
+
+	// injected has the behaviors injected to the requests keyed by the extender and the verb.
+	// It's initialized lazily since nothing is injected by default.
+	injected map[string]map[string]InjectedResult
 }
 
 func New() Store {
@@ -98,6 +134,26 @@ func (s *store) GetStoredResult(pod *v1.Pod) map[string]string {
 		return nil
 	}
 
+	if err := s.addIgnoredResultToMap(annotation, k); err != nil {
+		klog.Errorf("failed to add ignored result to the pod: %+v", err)
+		return nil
+	}
+
+	if err := s.addSkippedResultToMap(annotation, k); err != nil {
+		klog.Errorf("failed to add skipped result to the pod: %+v", err)
+		return nil
+	}
+
+	if err := s.addRawExchangeToMap(annotation, k); err != nil {
+		klog.Errorf("failed to add raw exchange to the pod: %+v", err)
+		return nil
+	}
+
+	if err := s.addInjectedResultToMap(annotation, k); err != nil {
+		klog.Errorf("failed to add injected result to the pod: %+v", err)
+		return nil
+	}
+
 	return annotation
 }
 
@@ -137,6 +193,54 @@ func (s *store) addBindResultToMap(anno map[string]string, k key) error {
 	return nil
 }
 
+func (s *store) addIgnoredResultToMap(anno map[string]string, k key) error {
+	if len(s.results[k].ignored) == 0 {
+		return nil
+	}
+	results, err := json.Marshal(s.results[k].ignored)
+	if err != nil {
+		return xerrors.Errorf("encode ignored results to json: %w", err)
+	}
+	anno[annotation.ExtenderIgnoredResultAnnotationKey] = string(results)
+	return nil
+}
+
+func (s *store) addSkippedResultToMap(anno map[string]string, k key) error {
+	if len(s.results[k].skipped) == 0 {
+		return nil
+	}
+	results, err := json.Marshal(s.results[k].skipped)
+	if err != nil {
+		return xerrors.Errorf("encode skipped results to json: %w", err)
+	}
+	anno[annotation.ExtenderSkippedResultAnnotationKey] = string(results)
+	return nil
+}
+
+func (s *store) addRawExchangeToMap(anno map[string]string, k key) error {
+	if len(s.results[k].rawExchange) == 0 {
+		return nil
+	}
+	results, err := json.Marshal(s.results[k].rawExchange)
+	if err != nil {
+		return xerrors.Errorf("encode raw exchanges to json: %w", err)
+	}
+	anno[annotation.ExtenderRawExchangeAnnotationKey] = string(results)
+	return nil
+}
+
+func (s *store) addInjectedResultToMap(anno map[string]string, k key) error {
+	if len(s.results[k].injected) == 0 {
+		return nil
+	}
+	results, err := json.Marshal(s.results[k].injected)
+	if err != nil {
+		return xerrors.Errorf("encode injected results to json: %w", err)
+	}
+	anno[annotation.ExtenderInjectedResultAnnotationKey] = string(results)
+	return nil
+}
+
 // AddFilterResult stores the filtering result.
 func (s *store) AddFilterResult(args extenderv1.ExtenderArgs, result extenderv1.ExtenderFilterResult, hostName string) {
 	s.mu.Lock()
@@ -185,6 +289,78 @@ func (s *store) AddBindResult(args extenderv1.ExtenderBindingArgs, result extend
 	s.results[k].bind[hostName] = result
 }
 
+// AddIgnoredResult stores the error of the ignorable extender which was ignored for the verb.
+func (s *store) AddIgnoredResult(pod *v1.Pod, verb string, err error, hostName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := newKey(pod.Namespace, pod.Name)
+	if _, ok := s.results[k]; !ok {
+		s.results[k] = newData()
+	}
+	if s.results[k].ignored == nil {
+		s.results[k].ignored = map[string]map[string]string{}
+	}
+	if _, ok := s.results[k].ignored[hostName]; !ok {
+		s.results[k].ignored[hostName] = map[string]string{}
+	}
+	s.results[k].ignored[hostName][verb] = err.Error()
+}
+
+// AddSkippedResult stores the reason why the extender wasn't called for the verb.
+func (s *store) AddSkippedResult(pod *v1.Pod, verb, reason, hostName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := newKey(pod.Namespace, pod.Name)
+	if _, ok := s.results[k]; !ok {
+		s.results[k] = newData()
+	}
+	if s.results[k].skipped == nil {
+		s.results[k].skipped = map[string]map[string]string{}
+	}
+	if _, ok := s.results[k].skipped[hostName]; !ok {
+		s.results[k].skipped[hostName] = map[string]string{}
+	}
+	s.results[k].skipped[hostName][verb] = reason
+}
+
+// AddRawExchange stores the raw request and response of the verb.
+func (s *store) AddRawExchange(namespace, podName, verb string, exchange RawExchange, hostName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := newKey(namespace, podName)
+	if _, ok := s.results[k]; !ok {
+		s.results[k] = newData()
+	}
+	if s.results[k].rawExchange == nil {
+		s.results[k].rawExchange = map[string]map[string]RawExchange{}
+	}
+	if _, ok := s.results[k].rawExchange[hostName]; !ok {
+		s.results[k].rawExchange[hostName] = map[string]RawExchange{}
+	}
+	s.results[k].rawExchange[hostName][verb] = exchange
+}
+
+// AddInjectedResult stores the behavior injected to the request of the verb.
+func (s *store) AddInjectedResult(namespace, podName, verb string, result InjectedResult, hostName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := newKey(namespace, podName)
+	if _, ok := s.results[k]; !ok {
+		s.results[k] = newData()
+	}
+	if s.results[k].injected == nil {
+		s.results[k].injected = map[string]map[string]InjectedResult{}
+	}
+	if _, ok := s.results[k].injected[hostName]; !ok {
+		s.results[k].injected[hostName] = map[string]InjectedResult{}
+	}
+	s.results[k].injected[hostName][verb] = result
+}
+
 // DeleteData deletes the data corresponding to the specified Pod.
 func (s *store) DeleteData(pod v1.Pod) {
 	s.mu.Lock()
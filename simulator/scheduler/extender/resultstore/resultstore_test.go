@@ -2,6 +2,7 @@ package resultstore
 
 import (
 	"encoding/json"
+	"errors"
 	"sync"
 	"testing"
 
@@ -997,6 +998,146 @@ func TestStore_AddBindResult(t *testing.T) {
 	}
 }
 
+func TestStore_AddIgnoredResult(t *testing.T) {
+	t.Parallel()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+	tests := []struct {
+		name           string
+		prepareResult  map[key]*result
+		wantResult     map[key]*result
+		wantAnnotation map[string]string
+	}{
+		{
+			name:          "success to add the result",
+			prepareResult: map[key]*result{},
+			wantResult: map[key]*result{
+				"default/pod1": {
+					filter:     map[string]extenderv1.ExtenderFilterResult{},
+					prioritize: map[string]extenderv1.HostPriorityList{},
+					preempt:    map[string]extenderv1.ExtenderPreemptionResult{},
+					bind:       map[string]extenderv1.ExtenderBindingResult{},
+					ignored: map[string]map[string]string{
+						"extenderserver": {"filter": "timeout"},
+					},
+				},
+			},
+			wantAnnotation: map[string]string{
+				annotation.ExtenderFilterResultAnnotationKey:     "{}",
+				annotation.ExtenderPrioritizeResultAnnotationKey: "{}",
+				annotation.ExtenderPreemptResultAnnotationKey:    "{}",
+				annotation.ExtenderBindResultAnnotationKey:       "{}",
+				annotation.ExtenderIgnoredResultAnnotationKey:    `{"extenderserver":{"filter":"timeout"}}`,
+			},
+		},
+		{
+			name: "add the result to the already stored data of the other verb",
+			prepareResult: map[key]*result{
+				"default/pod1": {
+					filter:     map[string]extenderv1.ExtenderFilterResult{},
+					prioritize: map[string]extenderv1.HostPriorityList{},
+					preempt:    map[string]extenderv1.ExtenderPreemptionResult{},
+					bind:       map[string]extenderv1.ExtenderBindingResult{},
+					ignored: map[string]map[string]string{
+						"extenderserver": {"prioritize": "connection refused"},
+					},
+				},
+			},
+			wantResult: map[key]*result{
+				"default/pod1": {
+					filter:     map[string]extenderv1.ExtenderFilterResult{},
+					prioritize: map[string]extenderv1.HostPriorityList{},
+					preempt:    map[string]extenderv1.ExtenderPreemptionResult{},
+					bind:       map[string]extenderv1.ExtenderBindingResult{},
+					ignored: map[string]map[string]string{
+						"extenderserver": {"filter": "timeout", "prioritize": "connection refused"},
+					},
+				},
+			},
+			wantAnnotation: map[string]string{
+				annotation.ExtenderFilterResultAnnotationKey:     "{}",
+				annotation.ExtenderPrioritizeResultAnnotationKey: "{}",
+				annotation.ExtenderPreemptResultAnnotationKey:    "{}",
+				annotation.ExtenderBindResultAnnotationKey:       "{}",
+				annotation.ExtenderIgnoredResultAnnotationKey:    `{"extenderserver":{"filter":"timeout","prioritize":"connection refused"}}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := &store{
+				mu:      new(sync.Mutex),
+				results: tt.prepareResult,
+			}
+			s.AddIgnoredResult(pod, "filter", errors.New("timeout"), "extenderserver")
+
+			assert.Equal(t, tt.wantResult, s.results)
+			assert.Equal(t, tt.wantAnnotation, s.GetStoredResult(pod))
+		})
+	}
+}
+
+func TestStore_AddSkippedResult(t *testing.T) {
+	t.Parallel()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+	s := &store{
+		mu:      new(sync.Mutex),
+		results: map[key]*result{},
+	}
+	s.AddSkippedResult(pod, "filter", "skipped: no managed resource", "extenderserver")
+
+	want := map[key]*result{
+		"default/pod1": {
+			filter:     map[string]extenderv1.ExtenderFilterResult{},
+			prioritize: map[string]extenderv1.HostPriorityList{},
+			preempt:    map[string]extenderv1.ExtenderPreemptionResult{},
+			bind:       map[string]extenderv1.ExtenderBindingResult{},
+			skipped: map[string]map[string]string{
+				"extenderserver": {"filter": "skipped: no managed resource"},
+			},
+		},
+	}
+	assert.Equal(t, want, s.results)
+	assert.Equal(t, map[string]string{
+		annotation.ExtenderFilterResultAnnotationKey:     "{}",
+		annotation.ExtenderPrioritizeResultAnnotationKey: "{}",
+		annotation.ExtenderPreemptResultAnnotationKey:    "{}",
+		annotation.ExtenderBindResultAnnotationKey:       "{}",
+		annotation.ExtenderSkippedResultAnnotationKey:    `{"extenderserver":{"filter":"skipped: no managed resource"}}`,
+	}, s.GetStoredResult(pod))
+}
+
+func TestStore_AddInjectedResult(t *testing.T) {
+	t.Parallel()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+	s := &store{
+		mu:      new(sync.Mutex),
+		results: map[key]*result{},
+	}
+	s.AddInjectedResult("default", "pod1", "filter", InjectedResult{Latency: "100ms", Error: "injected"}, "extenderserver")
+
+	want := map[key]*result{
+		"default/pod1": {
+			filter:     map[string]extenderv1.ExtenderFilterResult{},
+			prioritize: map[string]extenderv1.HostPriorityList{},
+			preempt:    map[string]extenderv1.ExtenderPreemptionResult{},
+			bind:       map[string]extenderv1.ExtenderBindingResult{},
+			injected: map[string]map[string]InjectedResult{
+				"extenderserver": {"filter": {Latency: "100ms", Error: "injected"}},
+			},
+		},
+	}
+	assert.Equal(t, want, s.results)
+	assert.Equal(t, map[string]string{
+		annotation.ExtenderFilterResultAnnotationKey:     "{}",
+		annotation.ExtenderPrioritizeResultAnnotationKey: "{}",
+		annotation.ExtenderPreemptResultAnnotationKey:    "{}",
+		annotation.ExtenderBindResultAnnotationKey:       "{}",
+		annotation.ExtenderInjectedResultAnnotationKey:   `{"extenderserver":{"filter":{"latency":"100ms","error":"injected"}}}`,
+	}, s.GetStoredResult(pod))
+}
+
 func TestStore_DeleteData(t *testing.T) {
 	t.Parallel()
 	podName := "pod1"
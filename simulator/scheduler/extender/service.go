@@ -3,12 +3,17 @@ package extender
 //go:generate mockgen -package=mock_$GOPACKAGE -source=./resultstore/resultstore.go -destination=./mock_$GOPACKAGE/resultstore.go
 
 import (
+	"math/rand"
+	"net"
 	"strconv"
+	"sync"
+	"time"
 
 	"golang.org/x/xerrors"
 	clientset "k8s.io/client-go/kubernetes"
 	configv1 "k8s.io/kube-scheduler/config/v1"
 	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender/resultstore"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/storereflector"
@@ -19,13 +24,35 @@ type Service struct {
 	client    clientset.Interface
 	extenders []Extender
 	store     resultstore.Store
+
+	// rawExchange is non-nil if the raw requests and responses are recorded.
+	rawExchange *rawExchangeConfig
+
+	// injectionMu protects injections and injectionRand.
+	injectionMu sync.Mutex
+	// injections has the behaviors injected to the requests, keyed by the index of the extender.
+	injections    map[int]Injection
+	injectionRand *rand.Rand
+	// sleep is replaced in tests not to wait for the injected latencies.
+	sleep func(time.Duration)
+
+	// scoreRecorder is optional; if it's non-nil, it receives the weighted scores of the prioritize extenders.
+	scoreRecorder ScoreRecorder
+}
+
+// ScoreRecorder receives the scores of the prioritize extenders to merge them into the final scores of the plugins.
+type ScoreRecorder interface {
+	AddExtenderScoreResult(namespace, podName, nodeName, extenderName string, score int64)
 }
 
 const ResultStoreKey = "ExtenderResultStoreKey"
 
+// skippedNoManagedResource is stored as the result of the extender which isn't interested in the pod.
+const skippedNoManagedResource = "skipped: no managed resource"
+
 // New initializes Service.
 // `extenderCfgs` expect to receive an untouched config file(set by user).
-func New(client clientset.Interface, extenderCfgs []configv1.Extender, storeReflector storereflector.Reflector) (*Service, error) {
+func New(client clientset.Interface, extenderCfgs []configv1.Extender, storeReflector storereflector.Reflector, opts ...Option) (*Service, error) {
 	extenders, err := createExtenders(extenderCfgs)
 	if err != nil {
 		return nil, xerrors.Errorf("create HTTPExtenders: %w", err)
@@ -33,41 +60,130 @@ func New(client clientset.Interface, extenderCfgs []configv1.Extender, storeRefl
 	store := resultstore.New()
 	// Register the result store of Extenders to the sharedStore.
 	storeReflector.AddResultStore(store, ResultStoreKey)
-	return &Service{
-		client:    client,
-		extenders: extenders,
-		store:     store,
-	}, nil
+	s := &Service{
+		client:        client,
+		extenders:     extenders,
+		store:         store,
+		injections:    map[int]Injection{},
+		injectionRand: rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec // it's not for security.
+		sleep:         time.Sleep,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	for id, injection := range s.injections {
+		if err := s.validateInjection(id, injection); err != nil {
+			return nil, xerrors.Errorf("validate the injection to extender: %w", err)
+		}
+	}
+	return s, nil
 }
 
 // Filter returns the result of the specified filter extender
 // and store it.
+// If the extender is ignorable, its failure is stored instead,
+// and the result as if the scheduler ignored the extender is returned.
+// The extender isn't called if the pod requests none of its managed resources.
 func (s *Service) Filter(id int, args extenderv1.ExtenderArgs) (*extenderv1.ExtenderFilterResult, error) {
-	result, err := s.extenders[id].Filter(args)
+	if !s.extenders[id].IsInterested(args.Pod) {
+		s.store.AddSkippedResult(args.Pod, "filter", skippedNoManagedResource, s.extenders[id].Name())
+		return &extenderv1.ExtenderFilterResult{Nodes: args.Nodes, NodeNames: args.NodeNames}, nil
+	}
+	var result *extenderv1.ExtenderFilterResult
+	err := s.inject(id, "filter", args)
+	if err == nil {
+		result, err = s.extenders[id].Filter(args)
+		s.recordRawExchange("filter", args, result, s.extenders[id])
+	}
 	if err != nil {
-		return nil, xerrors.Errorf("call filter of specified HTTPExtender: %w", err)
+		if !s.extenders[id].IsIgnorable() {
+			return nil, xerrors.Errorf("call filter of specified HTTPExtender: %w", err)
+		}
+		// The scheduler skips the ignorable extender on failure, that is, all nodes pass it.
+		s.store.AddIgnoredResult(args.Pod, "filter", err, s.extenders[id].Name())
+		return &extenderv1.ExtenderFilterResult{Nodes: args.Nodes, NodeNames: args.NodeNames}, nil
 	}
 	s.store.AddFilterResult(args, *result, s.extenders[id].Name())
 	return result, nil
 }
 
 // Prioritize returns the result of the specified prioritize extender
-// and store it.
+// and store it with the scores applied the weight of the extender.
+// The result is returned without the weight since the scheduler applies it.
+// If the extender is ignorable, its failure is stored instead,
+// and the result as if the scheduler ignored the extender is returned.
+// The extender isn't called if the pod requests none of its managed resources.
 func (s *Service) Prioritize(id int, args extenderv1.ExtenderArgs) (*extenderv1.HostPriorityList, error) {
-	result, err := s.extenders[id].Prioritize(args)
+	if !s.extenders[id].IsInterested(args.Pod) {
+		s.store.AddSkippedResult(args.Pod, "prioritize", skippedNoManagedResource, s.extenders[id].Name())
+		return &extenderv1.HostPriorityList{}, nil
+	}
+	var result *extenderv1.HostPriorityList
+	err := s.inject(id, "prioritize", args)
+	if err == nil {
+		result, err = s.extenders[id].Prioritize(args)
+		s.recordRawExchange("prioritize", args, result, s.extenders[id])
+	}
 	if err != nil {
-		return nil, xerrors.Errorf("call prioritize of specified HTTPExtender: %w", err)
+		if !s.extenders[id].IsIgnorable() {
+			return nil, xerrors.Errorf("call prioritize of specified HTTPExtender: %w", err)
+		}
+		// The ignorable extender doesn't contribute to the scores on failure.
+		s.store.AddIgnoredResult(args.Pod, "prioritize", err, s.extenders[id].Name())
+		return &extenderv1.HostPriorityList{}, nil
+	}
+	name := s.extenders[id].Name()
+	// The result of the extender is stored as is, and only the final scores have the weight applied.
+	s.store.AddPrioritizeResult(args, *result, name)
+	if s.scoreRecorder != nil {
+		for _, hp := range weightScores(*result, s.extenders[id].Weight()) {
+			s.scoreRecorder.AddExtenderScoreResult(args.Pod.Namespace, args.Pod.Name, hp.Host, name, hp.Score)
+		}
 	}
-	s.store.AddPrioritizeResult(args, *result, s.extenders[id].Name())
 	return result, nil
 }
 
+// SetScoreRecorder makes the Service pass the weighted scores of the prioritize extenders to r.
+// It's supposed to be called before the scheduling starts.
+func (s *Service) SetScoreRecorder(r ScoreRecorder) {
+	s.scoreRecorder = r
+}
+
+// weightScores returns the scores applied the weight of the extender as the scheduler does.
+func weightScores(scores extenderv1.HostPriorityList, weight int64) extenderv1.HostPriorityList {
+	ret := make(extenderv1.HostPriorityList, len(scores))
+	for i := range scores {
+		ret[i] = scores[i]
+		// MaxExtenderPriority may diverge from the max priority used in the scheduler and defined by MaxNodeScore,
+		// therefore we need to scale the score returned by extenders to the score range used by the scheduler.
+		ret[i].Score = scores[i].Score * weight * (framework.MaxNodeScore / extenderv1.MaxExtenderPriority)
+	}
+	return ret
+}
+
 // Preempt returns the result of the specified preempt extender
 // and store it.
+// If the extender is ignorable, its failure is stored instead,
+// and the result as if the scheduler ignored the extender is returned.
+// The extender isn't called if the pod requests none of its managed resources.
 func (s *Service) Preempt(id int, args extenderv1.ExtenderPreemptionArgs) (*extenderv1.ExtenderPreemptionResult, error) {
-	result, err := s.extenders[id].Preempt(args)
+	if !s.extenders[id].IsInterested(args.Pod) {
+		s.store.AddSkippedResult(args.Pod, "preempt", skippedNoManagedResource, s.extenders[id].Name())
+		return &extenderv1.ExtenderPreemptionResult{NodeNameToMetaVictims: metaVictims(args)}, nil
+	}
+	var result *extenderv1.ExtenderPreemptionResult
+	err := s.inject(id, "preempt", args)
+	if err == nil {
+		result, err = s.extenders[id].Preempt(args)
+		s.recordRawExchange("preempt", args, result, s.extenders[id])
+	}
 	if err != nil {
-		return nil, xerrors.Errorf("call preempt of specified HTTPExtender: %w", err)
+		if !s.extenders[id].IsIgnorable() {
+			return nil, xerrors.Errorf("call preempt of specified HTTPExtender: %w", err)
+		}
+		// The ignorable extender keeps all the candidates as they are on failure.
+		s.store.AddIgnoredResult(args.Pod, "preempt", err, s.extenders[id].Name())
+		return &extenderv1.ExtenderPreemptionResult{NodeNameToMetaVictims: metaVictims(args)}, nil
 	}
 	s.store.AddPreemptResult(args, *result, s.extenders[id].Name())
 	return result, nil
@@ -75,24 +191,63 @@ func (s *Service) Preempt(id int, args extenderv1.ExtenderPreemptionArgs) (*exte
 
 // Bind returns the result of the specified bind extender
 // and store it.
+// The failure of the request is also stored as the result with the error
+// so that the failed binding is visible on the Pod.
 func (s *Service) Bind(id int, args extenderv1.ExtenderBindingArgs) (*extenderv1.ExtenderBindingResult, error) {
-	result, err := s.extenders[id].Bind(args)
+	var result *extenderv1.ExtenderBindingResult
+	err := s.inject(id, "bind", args)
+	if err == nil {
+		result, err = s.extenders[id].Bind(args)
+		s.recordRawExchange("bind", args, result, s.extenders[id])
+	}
 	if err != nil {
+		s.store.AddBindResult(args, extenderv1.ExtenderBindingResult{Error: err.Error()}, s.extenders[id].Name())
 		return nil, xerrors.Errorf("call bind of specified HTTPExtender: %w", err)
 	}
 	s.store.AddBindResult(args, *result, s.extenders[id].Name())
 	return result, nil
 }
 
-// OverrideExtendersCfgToSimulator rewrites the scheduler config so that the extenders requests go through the simulator server.
-func OverrideExtendersCfgToSimulator(cfg *configv1.KubeSchedulerConfiguration, simulatorPort int) {
+// metaVictims returns the candidates in args as the form of the preemption result.
+func metaVictims(args extenderv1.ExtenderPreemptionArgs) map[string]*extenderv1.MetaVictims {
+	if args.NodeNameToMetaVictims != nil {
+		return args.NodeNameToMetaVictims
+	}
+	ret := make(map[string]*extenderv1.MetaVictims, len(args.NodeNameToVictims))
+	for nodeName, victims := range args.NodeNameToVictims {
+		meta := &extenderv1.MetaVictims{NumPDBViolations: victims.NumPDBViolations}
+		for _, p := range victims.Pods {
+			meta.Pods = append(meta.Pods, &extenderv1.MetaPod{UID: string(p.UID)})
+		}
+		ret[nodeName] = meta
+	}
+	return ret
+}
+
+// OverrideExtendersCfgToSimulator rewrites the scheduler config so that the extenders requests go through the simulator server
+// at simulatorAddress, which is in the form of host:port.
+func OverrideExtendersCfgToSimulator(cfg *configv1.KubeSchedulerConfiguration, simulatorAddress string) {
+	// NOTE: We do not plan to launch the "HTTPS" simulator server with echo on our project.
+	overrideExtendersCfg(cfg, "http://"+simulatorAddress+"/api/v1/extender/", nil)
+}
+
+// OverrideExtendersCfgToProxy rewrites the scheduler config so that the extenders requests go through the proxy server at host:port.
+// If tlsConfig is non-nil, the requests are sent with HTTPS using tlsConfig.
+func OverrideExtendersCfgToProxy(cfg *configv1.KubeSchedulerConfiguration, host string, port int, tlsConfig *configv1.ExtenderTLSConfig) {
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+	overrideExtendersCfg(cfg, scheme+"://"+net.JoinHostPort(host, strconv.Itoa(port))+"/api/v1/extender/", tlsConfig)
+}
+
+// overrideExtendersCfg rewrites the extenders in cfg so that their requests are sent to urlPrefix.
+func overrideExtendersCfg(cfg *configv1.KubeSchedulerConfiguration, urlPrefix string, tlsConfig *configv1.ExtenderTLSConfig) {
 	for i := range cfg.Extenders {
 		// i will be the extender's index. That index is specified by request param as `id`.
-		cfg.Extenders[i].EnableHTTPS = false
-		cfg.Extenders[i].TLSConfig = nil
-		// NOTE: We do not plan to launch the "HTTPS" simulator server with echo on our project.
-		// If you customize the server to use HTTPS with echo, you need to fix this line.
-		cfg.Extenders[i].URLPrefix = "http://localhost:" + strconv.Itoa(simulatorPort) + "/api/v1/extender/"
+		cfg.Extenders[i].EnableHTTPS = tlsConfig != nil
+		cfg.Extenders[i].TLSConfig = tlsConfig.DeepCopy()
+		cfg.Extenders[i].URLPrefix = urlPrefix
 		if cfg.Extenders[i].FilterVerb != "" {
 			cfg.Extenders[i].FilterVerb = "filter/" + strconv.Itoa(i)
 		}
@@ -7,6 +7,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 	configv1 "k8s.io/kube-scheduler/config/v1"
 	extenderv1 "k8s.io/kube-scheduler/extender/v1"
@@ -29,6 +31,7 @@ func TestService_Filter(t *testing.T) {
 				return fake.NewSimpleClientset()
 			},
 			prepareMockExtenderSetFn: func(m *mock_extender.MockExtender) {
+				m.EXPECT().IsInterested(nil).Return(true)
 				m.EXPECT().Filter(extenderv1.ExtenderArgs{}).Return(&extenderv1.ExtenderFilterResult{}, nil)
 				m.EXPECT().Name().Return("ext1")
 			},
@@ -43,12 +46,44 @@ func TestService_Filter(t *testing.T) {
 				return fake.NewSimpleClientset()
 			},
 			prepareMockExtenderSetFn: func(m *mock_extender.MockExtender) {
+				m.EXPECT().IsInterested(nil).Return(true)
 				m.EXPECT().Filter(extenderv1.ExtenderArgs{}).Return(nil, xerrors.New("failed"))
+				m.EXPECT().IsIgnorable().Return(false)
 			},
 			prepareMockStoreSetFn: func(_ *mock_extender.MockStore) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "store the error and return no error if the ignorable extender return an error",
+			prepareFakeClientSetFn: func() *fake.Clientset {
+				return fake.NewSimpleClientset()
+			},
+			prepareMockExtenderSetFn: func(m *mock_extender.MockExtender) {
+				m.EXPECT().IsInterested(nil).Return(true)
+				m.EXPECT().Filter(extenderv1.ExtenderArgs{}).Return(nil, xerrors.New("failed"))
+				m.EXPECT().IsIgnorable().Return(true)
+				m.EXPECT().Name().Return("ext1")
+			},
+			prepareMockStoreSetFn: func(m *mock_extender.MockStore) {
+				m.EXPECT().AddIgnoredResult(nil, "filter", gomock.Any(), "ext1")
+			},
+			wantErr: false,
+		},
+		{
+			name: "store the skip and return no error if the extender isn't interested in the pod",
+			prepareFakeClientSetFn: func() *fake.Clientset {
+				return fake.NewSimpleClientset()
+			},
+			prepareMockExtenderSetFn: func(m *mock_extender.MockExtender) {
+				m.EXPECT().IsInterested(nil).Return(false)
+				m.EXPECT().Name().Return("ext1")
+			},
+			prepareMockStoreSetFn: func(m *mock_extender.MockStore) {
+				m.EXPECT().AddSkippedResult(nil, "filter", "skipped: no managed resource", "ext1")
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -91,6 +126,7 @@ func TestService_Prioritize(t *testing.T) {
 				return fake.NewSimpleClientset()
 			},
 			prepareMockExtenderSetFn: func(m *mock_extender.MockExtender) {
+				m.EXPECT().IsInterested(nil).Return(true)
 				m.EXPECT().Prioritize(extenderv1.ExtenderArgs{}).Return(&extenderv1.HostPriorityList{}, nil)
 				m.EXPECT().Name().Return("ext1")
 			},
@@ -105,12 +141,44 @@ func TestService_Prioritize(t *testing.T) {
 				return fake.NewSimpleClientset()
 			},
 			prepareMockExtenderSetFn: func(m *mock_extender.MockExtender) {
+				m.EXPECT().IsInterested(nil).Return(true)
 				m.EXPECT().Prioritize(extenderv1.ExtenderArgs{}).Return(nil, xerrors.New("failed"))
+				m.EXPECT().IsIgnorable().Return(false)
 			},
 			prepareMockStoreSetFn: func(_ *mock_extender.MockStore) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "store the error and return no error if the ignorable extender return an error",
+			prepareFakeClientSetFn: func() *fake.Clientset {
+				return fake.NewSimpleClientset()
+			},
+			prepareMockExtenderSetFn: func(m *mock_extender.MockExtender) {
+				m.EXPECT().IsInterested(nil).Return(true)
+				m.EXPECT().Prioritize(extenderv1.ExtenderArgs{}).Return(nil, xerrors.New("failed"))
+				m.EXPECT().IsIgnorable().Return(true)
+				m.EXPECT().Name().Return("ext1")
+			},
+			prepareMockStoreSetFn: func(m *mock_extender.MockStore) {
+				m.EXPECT().AddIgnoredResult(nil, "prioritize", gomock.Any(), "ext1")
+			},
+			wantErr: false,
+		},
+		{
+			name: "store the skip and return no error if the extender isn't interested in the pod",
+			prepareFakeClientSetFn: func() *fake.Clientset {
+				return fake.NewSimpleClientset()
+			},
+			prepareMockExtenderSetFn: func(m *mock_extender.MockExtender) {
+				m.EXPECT().IsInterested(nil).Return(false)
+				m.EXPECT().Name().Return("ext1")
+			},
+			prepareMockStoreSetFn: func(m *mock_extender.MockStore) {
+				m.EXPECT().AddSkippedResult(nil, "prioritize", "skipped: no managed resource", "ext1")
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -138,6 +206,47 @@ func TestService_Prioritize(t *testing.T) {
 	}
 }
 
+// fakeScoreRecorder keeps the scores passed from Service.
+type fakeScoreRecorder struct {
+	scores map[string]map[string]int64
+}
+
+func (r *fakeScoreRecorder) AddExtenderScoreResult(_, _, nodeName, extenderName string, score int64) {
+	if r.scores[nodeName] == nil {
+		r.scores[nodeName] = map[string]int64{}
+	}
+	r.scores[nodeName][extenderName] = score
+}
+
+func TestService_Prioritize_scoreRecorder(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mStore := mock_extender.NewMockStore(ctrl)
+	mExtender := mock_extender.NewMockExtender(ctrl)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+	args := extenderv1.ExtenderArgs{Pod: pod}
+	mExtender.EXPECT().IsInterested(pod).Return(true)
+	mExtender.EXPECT().Prioritize(args).Return(&extenderv1.HostPriorityList{{Host: "node1", Score: 3}, {Host: "node2", Score: 10}}, nil)
+	mExtender.EXPECT().Name().Return("ext1")
+	mExtender.EXPECT().Weight().Return(int64(2))
+	// The result of the extender is stored without the weight.
+	mStore.EXPECT().AddPrioritizeResult(args, extenderv1.HostPriorityList{{Host: "node1", Score: 3}, {Host: "node2", Score: 10}}, "ext1")
+	r := &fakeScoreRecorder{scores: map[string]map[string]int64{}}
+
+	s := &Service{
+		extenders: []Extender{mExtender},
+		store:     mStore,
+	}
+	s.SetScoreRecorder(r)
+	got, err := s.Prioritize(0, args)
+
+	assert.NoError(t, err)
+	// The scores are returned as is since the scheduler applies the weight.
+	assert.Equal(t, &extenderv1.HostPriorityList{{Host: "node1", Score: 3}, {Host: "node2", Score: 10}}, got)
+	// score * weight * (MaxNodeScore / MaxExtenderPriority)
+	assert.Equal(t, map[string]map[string]int64{"node1": {"ext1": 60}, "node2": {"ext1": 200}}, r.scores)
+}
+
 func TestService_Preempt(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -153,6 +262,7 @@ func TestService_Preempt(t *testing.T) {
 				return fake.NewSimpleClientset()
 			},
 			prepareMockExtenderSetFn: func(m *mock_extender.MockExtender) {
+				m.EXPECT().IsInterested(nil).Return(true)
 				m.EXPECT().Preempt(extenderv1.ExtenderPreemptionArgs{}).Return(&extenderv1.ExtenderPreemptionResult{}, nil)
 				m.EXPECT().Name().Return("ext1")
 			},
@@ -167,12 +277,44 @@ func TestService_Preempt(t *testing.T) {
 				return fake.NewSimpleClientset()
 			},
 			prepareMockExtenderSetFn: func(m *mock_extender.MockExtender) {
+				m.EXPECT().IsInterested(nil).Return(true)
 				m.EXPECT().Preempt(extenderv1.ExtenderPreemptionArgs{}).Return(nil, xerrors.New("failed"))
+				m.EXPECT().IsIgnorable().Return(false)
 			},
 			prepareMockStoreSetFn: func(_ *mock_extender.MockStore) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "store the error and return no error if the ignorable extender return an error",
+			prepareFakeClientSetFn: func() *fake.Clientset {
+				return fake.NewSimpleClientset()
+			},
+			prepareMockExtenderSetFn: func(m *mock_extender.MockExtender) {
+				m.EXPECT().IsInterested(nil).Return(true)
+				m.EXPECT().Preempt(extenderv1.ExtenderPreemptionArgs{}).Return(nil, xerrors.New("failed"))
+				m.EXPECT().IsIgnorable().Return(true)
+				m.EXPECT().Name().Return("ext1")
+			},
+			prepareMockStoreSetFn: func(m *mock_extender.MockStore) {
+				m.EXPECT().AddIgnoredResult(nil, "preempt", gomock.Any(), "ext1")
+			},
+			wantErr: false,
+		},
+		{
+			name: "store the skip and return no error if the extender isn't interested in the pod",
+			prepareFakeClientSetFn: func() *fake.Clientset {
+				return fake.NewSimpleClientset()
+			},
+			prepareMockExtenderSetFn: func(m *mock_extender.MockExtender) {
+				m.EXPECT().IsInterested(nil).Return(false)
+				m.EXPECT().Name().Return("ext1")
+			},
+			prepareMockStoreSetFn: func(m *mock_extender.MockStore) {
+				m.EXPECT().AddSkippedResult(nil, "preempt", "skipped: no managed resource", "ext1")
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -230,8 +372,11 @@ func TestService_Bind(t *testing.T) {
 			},
 			prepareMockExtenderSetFn: func(m *mock_extender.MockExtender) {
 				m.EXPECT().Bind(extenderv1.ExtenderBindingArgs{}).Return(nil, xerrors.New("failed"))
+				m.EXPECT().Name().Return("ext1")
 			},
-			prepareMockStoreSetFn: func(_ *mock_extender.MockStore) {
+			prepareMockStoreSetFn: func(m *mock_extender.MockStore) {
+				// The failure is stored so that it's visible on the Pod.
+				m.EXPECT().AddBindResult(extenderv1.ExtenderBindingArgs{}, extenderv1.ExtenderBindingResult{Error: "failed"}, "ext1")
 			},
 			wantErr: true,
 		},
@@ -266,7 +411,6 @@ func TestService_OverrideExtendersCfgToSimulator(t *testing.T) {
 	t.Parallel()
 	target := configv1.KubeSchedulerConfiguration{}
 	es := make([]configv1.Extender, 2)
-	port := 80
 	for i := range es {
 		es[i].EnableHTTPS = true
 		es[i].TLSConfig = new(configv1.ExtenderTLSConfig)
@@ -278,7 +422,7 @@ func TestService_OverrideExtendersCfgToSimulator(t *testing.T) {
 	}
 	target.Extenders = es
 
-	OverrideExtendersCfgToSimulator(&target, port)
+	OverrideExtendersCfgToSimulator(&target, "127.0.0.1:80")
 
 	// OverrideExtendersCfgToSimulator changes all extender config included in KubeSchedulerConfiguration.
 	for i, e := range target.Extenders {
@@ -290,10 +434,34 @@ func TestService_OverrideExtendersCfgToSimulator(t *testing.T) {
 			t.Fatalf("TLSConfig = %v, expected = nil", e.TLSConfig)
 		}
 		// Rewrite settings for simulator.
-		assert.Equal(t, "http://localhost:80/api/v1/extender/", e.URLPrefix)
+		assert.Equal(t, "http://127.0.0.1:80/api/v1/extender/", e.URLPrefix)
 		assert.Equal(t, "filter/"+s, e.FilterVerb)
 		assert.Equal(t, "prioritize/"+s, e.PrioritizeVerb)
 		assert.Equal(t, "preempt/"+s, e.PreemptVerb)
 		assert.Equal(t, "bind/"+s, e.BindVerb)
 	}
 }
+
+func TestService_OverrideExtendersCfgToProxy(t *testing.T) {
+	t.Parallel()
+	target := configv1.KubeSchedulerConfiguration{}
+	es := make([]configv1.Extender, 2)
+	for i := range es {
+		es[i].URLPrefix = "http://example.com/"
+		es[i].FilterVerb = "f"
+		es[i].ManagedResources = []configv1.ExtenderManagedResource{{Name: "example.com/gpu", IgnoredByScheduler: true}}
+	}
+	target.Extenders = es
+	tlsConfig := &configv1.ExtenderTLSConfig{CAFile: "/path/to/tls.crt"}
+
+	OverrideExtendersCfgToProxy(&target, "10.0.0.1", 443, tlsConfig)
+
+	for i, e := range target.Extenders {
+		assert.Equal(t, true, e.EnableHTTPS)
+		assert.Equal(t, tlsConfig, e.TLSConfig)
+		assert.Equal(t, "https://10.0.0.1:443/api/v1/extender/", e.URLPrefix)
+		assert.Equal(t, "filter/"+strconv.Itoa(i), e.FilterVerb)
+		// The managed resources are kept so that the scheduler doesn't account the resources ignored by it.
+		assert.Equal(t, []configv1.ExtenderManagedResource{{Name: "example.com/gpu", IgnoredByScheduler: true}}, e.ManagedResources)
+	}
+}
@@ -15,6 +15,8 @@ const (
 	ScoreResultAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/score-result"
 	// FinalScoreResultAnnotationKey has the final score(= normalized and applied score plugin weight).
 	FinalScoreResultAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/finalscore-result"
+	// TotalScoreResultAnnotationKey has the total of the final scores of the plugins and the extenders for each node.
+	TotalScoreResultAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/totalscore-result"
 	// ReserveResultAnnotationKey has the reserve result.
 	ReserveResultAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/reserve-result"
 	// PermitStatusResultAnnotationKey has the permit result.
@@ -25,6 +27,12 @@ const (
 	PreBindResultAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/prebind-result"
 	// BindResultAnnotationKey has the prebind result.
 	BindResultAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/bind-result"
+	// PreemptionResultAnnotationKey has the outcome of the preemption by DefaultPreemption plugin.
+	PreemptionResultAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/preemption-result"
+	// PluginDurationAnnotationKey has how long each plugin took at each extension point.
+	PluginDurationAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/plugin-duration"
+	// QueueingHintResultAnnotationKey has the latest evaluations of the QueueingHints for the Pod.
+	QueueingHintResultAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/queueinghint-result"
 	// SelectedNodeAnnotationKey has the selected node name. It's filled when a Pod go through the Reserve phase.
 	SelectedNodeAnnotationKey = "kube-scheduler-simulator.sigs.k8s.io/selected-node"
 )
@@ -0,0 +1,71 @@
+package plugin
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// DurationStore is implemented by the Store which records how long each plugin takes.
+// The wrapped plugins measure the durations of the PreFilter, Filter, PostFilter and Score calls
+// only when their Store implements DurationStore or the metrics are registered by RegisterMetrics,
+// so that measuring doesn't add any overhead otherwise.
+type DurationStore interface {
+	AddPluginDuration(namespace, podName, extensionPoint, pluginName string, duration time.Duration)
+}
+
+var (
+	pluginExecutionDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem: "kube_scheduler_simulator",
+			Name:      "plugin_execution_duration_seconds",
+			Help:      "Duration for running a wrapped plugin at a specific extension point.",
+			// The same buckets as the scheduler's plugin_execution_duration_seconds.
+			Buckets:        metrics.ExponentialBuckets(0.00001, 1.5, 20),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"plugin", "extension_point"})
+
+	registerMetricsOnce sync.Once
+	metricsRegistered   atomic.Bool
+)
+
+// RegisterMetrics registers the histograms of the wrapped plugins' durations to the legacy registry,
+// which is served by the scheduler's /metrics endpoint.
+func RegisterMetrics() {
+	registerMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(pluginExecutionDuration)
+		metricsRegistered.Store(true)
+	})
+}
+
+// measuring reports whether the durations of the plugin should be measured.
+func (w *wrappedPlugin) measuring() bool {
+	return w.durationStore != nil || metricsRegistered.Load()
+}
+
+// startMeasuring returns the start time of the call, or zero time if it isn't measured.
+func (w *wrappedPlugin) startMeasuring() time.Time {
+	if !w.measuring() {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// recordDuration records the duration of the call of the plugin started at start. It does nothing if start is zero.
+func (w *wrappedPlugin) recordDuration(pod *v1.Pod, extensionPoint string, p framework.Plugin, start time.Time) {
+	if start.IsZero() {
+		return
+	}
+	d := time.Since(start)
+	if w.durationStore != nil {
+		w.durationStore.AddPluginDuration(pod.Namespace, pod.Name, extensionPoint, p.Name(), d)
+	}
+	// It's no-op if the metrics aren't registered.
+	pluginExecutionDuration.WithLabelValues(p.Name(), extensionPoint).Observe(d.Seconds())
+}
@@ -22,13 +22,25 @@ import (
 // ResultStoreKey represents key name of plugins results on sharedstore.
 const ResultStoreKey = "PluginResultStoreKey"
 
-func NewRegistry(sharedStore storereflector.Reflector, cfg *schedulerConfig.KubeSchedulerConfiguration, pluginExtenders map[string]PluginExtenderInitializer) (map[string]schedulerRuntime.PluginFactory, error) {
+// ProfilePluginExtenders is profile name → plugin name → PluginExtenderInitializer.
+type ProfilePluginExtenders map[string]map[string]PluginExtenderInitializer
+
+// NewRegistry creates the registry of the wrapped plugins.
+// pluginExtenders are applied to the plugins in all profiles,
+// and profilePluginExtenders are applied to the plugins in the specific profiles, taking precedence over pluginExtenders.
+// resultHook is optional; if it's non-nil, it receives the results of each scheduling attempt.
+// resultFilter is the names of the plugins whose results are stored; the results of all plugins are stored if it's empty.
+func NewRegistry(sharedStore storereflector.Reflector, cfg *schedulerConfig.KubeSchedulerConfiguration, pluginExtenders map[string]PluginExtenderInitializer, profilePluginExtenders ProfilePluginExtenders, resultHook ResultHook, resultFilter []string) (map[string]schedulerRuntime.PluginFactory, error) {
 	scorePluginWeight := getScorePluginWeight(cfg)
 	store := schedulingresultstore.New(scorePluginWeight)
+	store.SetResultFilter(resultFilter)
 	// Add the resultStore to the sharedStore to store the results and share it.
 	sharedStore.AddResultStore(store, ResultStoreKey)
+	if resultHook != nil {
+		RegisterResultHook(sharedStore, store, resultHook)
+	}
 
-	ret, err := newPluginFactories(store, pluginExtenders)
+	ret, err := newPluginFactories(store, pluginExtenders, profilePluginExtenders)
 	if err != nil {
 		return nil, xerrors.Errorf("New pluginFactories: %w", err)
 	}
@@ -36,7 +48,7 @@ func NewRegistry(sharedStore storereflector.Reflector, cfg *schedulerConfig.Kube
 	return ret, nil
 }
 
-func newPluginFactories(store *schedulingresultstore.Store, pluginExtenders map[string]PluginExtenderInitializer) (map[string]schedulerRuntime.PluginFactory, error) {
+func newPluginFactories(store *schedulingresultstore.Store, pluginExtenders map[string]PluginExtenderInitializer, profilePluginExtenders ProfilePluginExtenders) (map[string]schedulerRuntime.PluginFactory, error) {
 	intreeRegistries := config.InTreeRegistries()
 	outoftreeRegistries := config.OutOfTreeRegistries()
 	pls, err := config.RegisteredMultiPointPluginNames()
@@ -71,7 +83,7 @@ func newPluginFactories(store *schedulingresultstore.Store, pluginExtenders map[
 			}
 
 			opts := []Option{}
-			extender, ok := pluginExtenders[pluginname]
+			extender, ok := lookupPluginExtender(pluginExtenders, profilePluginExtenders, profileName(f), pluginname)
 			if ok {
 				opts = append(opts, WithExtendersOption(extender))
 			}
@@ -84,6 +96,26 @@ func newPluginFactories(store *schedulingresultstore.Store, pluginExtenders map[
 	return ret, nil
 }
 
+// lookupPluginExtender returns the PluginExtenderInitializer for the plugin in the profile.
+// The one registered for the profile takes precedence over the one registered for all profiles.
+func lookupPluginExtender(pluginExtenders map[string]PluginExtenderInitializer, profilePluginExtenders ProfilePluginExtenders, profile, pluginName string) (PluginExtenderInitializer, bool) {
+	if e, ok := profilePluginExtenders[profile][pluginName]; ok {
+		return e, true
+	}
+	e, ok := pluginExtenders[pluginName]
+	return e, ok
+}
+
+// profileName returns the name of the profile which the plugin is created for.
+// framework.Handle doesn't have ProfileName(), but the scheduler passes the framework, which has it, as the handle.
+func profileName(h framework.Handle) string {
+	f, ok := h.(interface{ ProfileName() string })
+	if !ok {
+		return ""
+	}
+	return f.ProfileName()
+}
+
 // NewPluginConfig converts []configv1.PluginConfig for simulator.
 // Passed []v1beta.PluginConfig overrides default config values.
 //
@@ -1,16 +1,24 @@
 package plugin
 
 import (
+	"context"
 	"encoding/json"
 	"sort"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	configv1 "k8s.io/kube-scheduler/config/v1"
 	schedulerConfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/nodename"
+
+	mock_plugin "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/mock"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/resultstore"
 )
 
 func TestConvertForSimulator(t *testing.T) {
@@ -1207,3 +1215,92 @@ func TestGetScorePluginWeight(t *testing.T) {
 		})
 	}
 }
+
+func Test_newPluginFactories_ProfilePluginExtenders(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	globalExtender := mock_plugin.NewMockFilterPluginExtender(ctrl)
+	profile1Extender := mock_plugin.NewMockFilterPluginExtender(ctrl)
+	profile2Extender := mock_plugin.NewMockFilterPluginExtender(ctrl)
+	initializer := func(e FilterPluginExtender) PluginExtenderInitializer {
+		return func(_ SimulatorHandle) PluginExtenders {
+			return PluginExtenders{FilterPluginExtender: e}
+		}
+	}
+
+	tests := []struct {
+		name                   string
+		pluginExtenders        map[string]PluginExtenderInitializer
+		profilePluginExtenders ProfilePluginExtenders
+		profile                string
+		want                   FilterPluginExtender
+	}{
+		{
+			name:            "the global extender is used for the profile without its own extender",
+			pluginExtenders: map[string]PluginExtenderInitializer{nodename.Name: initializer(globalExtender)},
+			profilePluginExtenders: ProfilePluginExtenders{
+				"profile1": {nodename.Name: initializer(profile1Extender)},
+			},
+			profile: "profile2",
+			want:    globalExtender,
+		},
+		{
+			name:            "the extender for the first profile takes precedence over the global one",
+			pluginExtenders: map[string]PluginExtenderInitializer{nodename.Name: initializer(globalExtender)},
+			profilePluginExtenders: ProfilePluginExtenders{
+				"profile1": {nodename.Name: initializer(profile1Extender)},
+				"profile2": {nodename.Name: initializer(profile2Extender)},
+			},
+			profile: "profile1",
+			want:    profile1Extender,
+		},
+		{
+			name:            "the extender for the second profile takes precedence over the global one",
+			pluginExtenders: map[string]PluginExtenderInitializer{nodename.Name: initializer(globalExtender)},
+			profilePluginExtenders: ProfilePluginExtenders{
+				"profile1": {nodename.Name: initializer(profile1Extender)},
+				"profile2": {nodename.Name: initializer(profile2Extender)},
+			},
+			profile: "profile2",
+			want:    profile2Extender,
+		},
+		{
+			name: "no extender is used if no extender is registered for the plugin",
+			profilePluginExtenders: ProfilePluginExtenders{
+				"profile1": {"OtherPlugin": initializer(profile1Extender)},
+			},
+			profile: "profile1",
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			registry, err := newPluginFactories(resultstore.New(nil), tt.pluginExtenders, tt.profilePluginExtenders)
+			require.NoError(t, err)
+			factory, ok := registry[pluginName(nodename.Name)]
+			require.True(t, ok)
+
+			p, err := factory(context.Background(), nil, fakeProfileHandle{profileName: tt.profile})
+			require.NoError(t, err)
+			wrapped, ok := p.(*wrappedPlugin)
+			require.True(t, ok)
+			if tt.want == nil {
+				assert.Nil(t, wrapped.filterPluginExtender)
+				return
+			}
+			// Check the identity because the mocks are equal in value.
+			assert.Same(t, tt.want, wrapped.filterPluginExtender)
+		})
+	}
+}
+
+// fakeProfileHandle is the framework.Handle of the profile, as the scheduler passes the framework as the handle.
+type fakeProfileHandle struct {
+	framework.Handle
+	profileName string
+}
+
+func (h fakeProfileHandle) ProfileName() string { return h.profileName }
@@ -0,0 +1,57 @@
+package plugin
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultpreemption"
+	"k8s.io/kubernetes/pkg/scheduler/framework/preemption"
+)
+
+// PreemptionStore is implemented by the Store which records the outcome of the preemption by DefaultPreemption plugin.
+// The wrapped DefaultPreemption plugin records the victims on each node evaluated in the dry run,
+// and the node nominated by the preemption, only when its Store implements PreemptionStore.
+type PreemptionStore interface {
+	AddPreemptionCandidate(namespace, podName, nodeName string, victims []string)
+	AddPreemptionResult(namespace, podName, nominatedNodeName string)
+}
+
+// preemptionRecorder intercepts preemption.Interface of DefaultPreemption plugin
+// to record the victims selected on each node in the dry run of the preemption.
+type preemptionRecorder struct {
+	preemption.Interface
+	store PreemptionStore
+}
+
+// SelectVictimsOnNode runs SelectVictimsOnNode of the original plugin and records the victims on the node.
+// It's called for each candidate node in parallel.
+func (r *preemptionRecorder) SelectVictimsOnNode(ctx context.Context, state *framework.CycleState,
+	pod *v1.Pod, nodeInfo *framework.NodeInfo, pdbs []*policy.PodDisruptionBudget,
+) ([]*v1.Pod, int, *framework.Status) {
+	victims, numViolatingVictim, s := r.Interface.SelectVictimsOnNode(ctx, state, pod, nodeInfo, pdbs)
+	if s.IsSuccess() {
+		keys := make([]string, 0, len(victims))
+		for _, v := range victims {
+			keys = append(keys, v.Namespace+"/"+v.Name)
+		}
+		r.store.AddPreemptionCandidate(pod.Namespace, pod.Name, nodeInfo.Node().Name, keys)
+	}
+	return victims, numViolatingVictim, s
+}
+
+// recordPreemption makes DefaultPreemption plugin record the victims in the dry run of the preemption to the store.
+// It returns false if p isn't DefaultPreemption plugin.
+// Note that the victims on the nominated node are recorded as they're selected in the dry run,
+// even if the extenders change them afterward.
+func recordPreemption(p framework.Plugin, s PreemptionStore) bool {
+	dp, ok := p.(*defaultpreemption.DefaultPreemption)
+	if !ok || dp.Evaluator == nil {
+		return false
+	}
+	if _, ok := dp.Evaluator.Interface.(*preemptionRecorder); !ok {
+		dp.Evaluator.Interface = &preemptionRecorder{Interface: dp.Evaluator.Interface, store: s}
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/events"
+	kubeschedulerconfigv1 "k8s.io/kube-scheduler/config/v1"
+	kubeschedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	configv1 "k8s.io/kubernetes/pkg/scheduler/apis/config/v1"
+	internalcache "k8s.io/kubernetes/pkg/scheduler/backend/cache"
+	internalqueue "k8s.io/kubernetes/pkg/scheduler/backend/queue"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultpreemption"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/feature"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/noderesources"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	schedulermetrics "k8s.io/kubernetes/pkg/scheduler/metrics"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	tf "k8s.io/kubernetes/pkg/scheduler/testing/framework"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/resultstore"
+)
+
+func Test_wrappedPlugin_PostFilter_DefaultPreemption(t *testing.T) {
+	t.Parallel()
+	// The scheduling queue needs the scheduler's metrics.
+	schedulermetrics.Register()
+
+	lowPriority, midPriority, highPriority := int32(0), int32(100), int32(1000)
+	nodeRes := map[v1.ResourceName]string{v1.ResourceCPU: "1", v1.ResourcePods: "10"}
+
+	tests := []struct {
+		name  string
+		pod   *v1.Pod
+		pods  []*v1.Pod
+		nodes []*v1.Node
+		// filteredNodesStatuses is the result of the Filter plugins.
+		filteredNodesStatuses *framework.NodeToStatus
+		wantStatusCode        framework.Code
+		want                  *resultstore.PreemptionResult
+	}{
+		{
+			name: "record the candidates on all evaluated nodes and the victims on the nominated node",
+			pod:  st.MakePod().Name("p").UID("p").Namespace(v1.NamespaceDefault).Priority(highPriority).Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj(),
+			pods: []*v1.Pod{
+				st.MakePod().Name("low1").UID("low1").Namespace(v1.NamespaceDefault).Node("node1").Priority(lowPriority).Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj(),
+				st.MakePod().Name("low2").UID("low2").Namespace(v1.NamespaceDefault).Node("node2").Priority(midPriority).Req(map[v1.ResourceName]string{v1.ResourceCPU: "500m"}).Obj(),
+				st.MakePod().Name("low3").UID("low3").Namespace(v1.NamespaceDefault).Node("node2").Priority(lowPriority).Req(map[v1.ResourceName]string{v1.ResourceCPU: "500m"}).Obj(),
+			},
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node1").Capacity(nodeRes).Obj(),
+				st.MakeNode().Name("node2").Capacity(nodeRes).Obj(),
+			},
+			filteredNodesStatuses: framework.NewNodeToStatus(map[string]*framework.Status{
+				"node1": framework.NewStatus(framework.Unschedulable),
+				"node2": framework.NewStatus(framework.Unschedulable),
+			}, framework.NewStatus(framework.UnschedulableAndUnresolvable)),
+			wantStatusCode: framework.Success,
+			want: &resultstore.PreemptionResult{
+				// node1 is chosen because its victims have the lower priority.
+				NominatedNode: "node1",
+				Candidates: map[string][]string{
+					"node1": {"default/low1"},
+					// The victims are ordered from the higher priority one.
+					"node2": {"default/low2", "default/low3"},
+				},
+				Victims: []string{"default/low1"},
+			},
+		},
+		{
+			name: "no result is recorded when no pod can be preempted",
+			pod:  st.MakePod().Name("p").UID("p").Namespace(v1.NamespaceDefault).Priority(lowPriority).Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj(),
+			pods: []*v1.Pod{
+				st.MakePod().Name("high1").UID("high1").Namespace(v1.NamespaceDefault).Node("node1").Priority(highPriority).Req(map[v1.ResourceName]string{v1.ResourceCPU: "1"}).Obj(),
+			},
+			nodes: []*v1.Node{
+				st.MakeNode().Name("node1").Capacity(nodeRes).Obj(),
+			},
+			filteredNodesStatuses: framework.NewNodeToStatus(map[string]*framework.Status{
+				"node1": framework.NewStatus(framework.Unschedulable),
+			}, framework.NewStatus(framework.UnschedulableAndUnresolvable)),
+			wantStatusCode: framework.Unschedulable,
+			want:           nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			objs := []v1.Pod{*tt.pod}
+			for _, p := range tt.pods {
+				objs = append(objs, *p)
+			}
+			cs := clientsetfake.NewClientset(&v1.PodList{Items: objs})
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+			podInformer := informerFactory.Core().V1().Pods().Informer()
+			require.NoError(t, podInformer.GetStore().Add(tt.pod))
+			for _, p := range tt.pods {
+				require.NoError(t, podInformer.GetStore().Add(p))
+			}
+
+			fh, err := tf.NewFramework(ctx, []tf.RegisterPluginFunc{
+				tf.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				tf.RegisterPluginAsExtensions(noderesources.Name, frameworkruntime.FactoryAdapter(feature.Features{}, noderesources.NewFit), "Filter", "PreFilter"),
+				tf.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			}, "",
+				frameworkruntime.WithClientSet(cs),
+				frameworkruntime.WithEventRecorder(&events.FakeRecorder{}),
+				frameworkruntime.WithInformerFactory(informerFactory),
+				frameworkruntime.WithPodNominator(internalqueue.NewSchedulingQueue(nil, informerFactory)),
+				frameworkruntime.WithSnapshotSharedLister(internalcache.NewSnapshot(tt.pods, tt.nodes)),
+				frameworkruntime.WithWaitingPods(frameworkruntime.NewWaitingPodsMap()),
+			)
+			require.NoError(t, err)
+			dp, err := defaultpreemption.New(ctx, defaultPreemptionArgs(), fh, feature.Features{})
+			require.NoError(t, err)
+
+			store := resultstore.New(nil)
+			pfp, ok := NewWrappedPlugin(store, dp).(framework.PostFilterPlugin)
+			require.True(t, ok)
+
+			state := framework.NewCycleState()
+			_, s, _ := fh.RunPreFilterPlugins(ctx, state, tt.pod)
+			require.True(t, s.IsSuccess())
+			_, s = pfp.PostFilter(ctx, state, tt.pod, tt.filteredNodesStatuses)
+			assert.Equal(t, tt.wantStatusCode, s.Code(), s.Message())
+
+			got, _ := store.GetSchedulingResult(tt.pod.Namespace, tt.pod.Name)
+			if tt.want == nil {
+				assert.Nil(t, got.Preemption)
+				return
+			}
+			assert.Equal(t, tt.want, got.Preemption)
+		})
+	}
+}
+
+func defaultPreemptionArgs() *kubeschedulerconfig.DefaultPreemptionArgs {
+	v1args := &kubeschedulerconfigv1.DefaultPreemptionArgs{}
+	configv1.SetDefaults_DefaultPreemptionArgs(v1args)
+	args := &kubeschedulerconfig.DefaultPreemptionArgs{}
+	//nolint:errcheck // the conversion never fails.
+	configv1.Convert_v1_DefaultPreemptionArgs_To_config_DefaultPreemptionArgs(v1args, args, nil)
+	return args
+}
@@ -0,0 +1,61 @@
+package plugin
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/resultstore"
+)
+
+// QueueingHintStore is implemented by the Store which records the evaluations of the QueueingHints.
+// The wrapped plugins record the evaluations of the QueueingHints of the original plugins only when their Store implements QueueingHintStore.
+type QueueingHintStore interface {
+	AddQueueingHintResult(namespace, podName string, result resultstore.QueueingHintResult)
+}
+
+// EventsToRegister returns the events which the original plugin registers, with the QueueingHints wrapped to record their evaluations.
+// If the original plugin doesn't implement framework.EnqueueExtensions, it returns all events like the scheduler does for such plugins,
+// so that the wrapped plugin is requeued in the same way as the original one.
+// The events without QueueingHint are returned as they are, which always requeue the Pod.
+func (w *wrappedPlugin) EventsToRegister(ctx context.Context) ([]framework.ClusterEventWithHint, error) {
+	if w.originalEnqueueExtensions == nil {
+		return framework.UnrollWildCardResource(), nil
+	}
+
+	events, err := w.originalEnqueueExtensions.EventsToRegister(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if w.queueingHintStore == nil {
+		return events, nil
+	}
+
+	ret := make([]framework.ClusterEventWithHint, 0, len(events))
+	for _, e := range events {
+		if e.QueueingHintFn != nil {
+			e.QueueingHintFn = w.recordQueueingHint(e.Event, e.QueueingHintFn)
+		}
+		ret = append(ret, e)
+	}
+	return ret, nil
+}
+
+// recordQueueingHint returns the QueueingHintFn which runs fn and records the evaluation for the Pod.
+func (w *wrappedPlugin) recordQueueingHint(event framework.ClusterEvent, fn framework.QueueingHintFn) framework.QueueingHintFn {
+	return func(logger klog.Logger, pod *v1.Pod, oldObj, newObj interface{}) (framework.QueueingHint, error) {
+		hint, err := fn(logger, pod, oldObj, newObj)
+		result := resultstore.QueueingHintResult{
+			Plugin: w.originalEnqueueExtensions.Name(),
+			Event:  event.Label(),
+			Hint:   hint.String(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		w.queueingHintStore.AddQueueingHintResult(pod.Namespace, pod.Name, result)
+		return hint, err
+	}
+}
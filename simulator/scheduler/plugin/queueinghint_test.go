@@ -0,0 +1,103 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	internalqueue "k8s.io/kubernetes/pkg/scheduler/backend/queue"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	schedulermetrics "k8s.io/kubernetes/pkg/scheduler/metrics"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/resultstore"
+)
+
+// fakeNodeLabelFilter only accepts the nodes with the "ready" label,
+// and requeues the Pod rejected by it only when such a node is added.
+type fakeNodeLabelFilter struct{}
+
+func (fakeNodeLabelFilter) Name() string { return "fakeNodeLabelFilter" }
+
+func (fakeNodeLabelFilter) Filter(_ context.Context, _ *framework.CycleState, _ *v1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	if _, ok := nodeInfo.Node().Labels["ready"]; ok {
+		return nil
+	}
+	return framework.NewStatus(framework.UnschedulableAndUnresolvable, "node(s) not ready")
+}
+
+func (fakeNodeLabelFilter) EventsToRegister(_ context.Context) ([]framework.ClusterEventWithHint, error) {
+	return []framework.ClusterEventWithHint{
+		{
+			Event: framework.ClusterEvent{Resource: framework.Node, ActionType: framework.Add},
+			QueueingHintFn: func(_ klog.Logger, _ *v1.Pod, _, newObj interface{}) (framework.QueueingHint, error) {
+				if _, ok := newObj.(*v1.Node).Labels["ready"]; ok {
+					return framework.Queue, nil
+				}
+				return framework.QueueSkip, nil
+			},
+		},
+	}, nil
+}
+
+func Test_wrappedPlugin_EventsToRegister(t *testing.T) {
+	t.Parallel()
+	// The scheduling queue needs the scheduler's metrics.
+	schedulermetrics.Register()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := klog.FromContext(ctx)
+
+	store := resultstore.New(nil)
+	wrapped := NewWrappedPlugin(store, fakeNodeLabelFilter{})
+	ext, ok := wrapped.(framework.EnqueueExtensions)
+	require.True(t, ok)
+	events, err := ext.EventsToRegister(ctx)
+	require.NoError(t, err)
+
+	// Register the QueueingHints of the wrapped plugin to the scheduling queue like the scheduler does.
+	hints := internalqueue.QueueingHintMap{}
+	for _, e := range events {
+		hints[e.Event] = append(hints[e.Event], &internalqueue.QueueingHintFunction{PluginName: wrapped.Name(), QueueingHintFn: e.QueueingHintFn})
+	}
+	q := internalqueue.NewTestQueue(ctx, nil, internalqueue.WithQueueingHintMapPerProfile(internalqueue.QueueingHintMapPerProfile{"default-scheduler": hints}))
+
+	// The Pod is rejected by the wrapped plugin.
+	pod := st.MakePod().Name("pod1").Namespace("default").UID("pod1").SchedulerName("default-scheduler").Obj()
+	q.Add(logger, pod)
+	pInfo, err := q.Pop(logger)
+	require.NoError(t, err)
+	pInfo.UnschedulablePlugins = sets.New(wrapped.Name())
+	require.NoError(t, q.AddUnschedulableIfNotPresent(logger, pInfo, q.SchedulingCycle()))
+
+	nodeAdd := framework.ClusterEvent{Resource: framework.Node, ActionType: framework.Add}
+	q.MoveAllToActiveOrBackoffQueue(logger, nodeAdd, nil, st.MakeNode().Name("node1").Obj(), nil)
+	_, stillUnschedulable := q.PendingPods()
+	q.MoveAllToActiveOrBackoffQueue(logger, nodeAdd, nil, st.MakeNode().Name("node2").Label("ready", "").Obj(), nil)
+
+	r, ok := store.GetSchedulingResult(pod.Namespace, pod.Name)
+	require.True(t, ok)
+	assert.Equal(t, []resultstore.QueueingHintResult{
+		{Plugin: "fakeNodeLabelFilter", Event: "NodeAdd", Hint: "QueueSkip"},
+		{Plugin: "fakeNodeLabelFilter", Event: "NodeAdd", Hint: "Queue"},
+	}, r.QueueingHints)
+	assert.Contains(t, stillUnschedulable, "unschedulablePods:1")
+	_, summary := q.PendingPods()
+	assert.NotContains(t, summary, "unschedulablePods:1")
+}
+
+func Test_wrappedPlugin_EventsToRegister_withoutEnqueueExtensions(t *testing.T) {
+	t.Parallel()
+
+	wrapped, ok := NewWrappedPlugin(resultstore.New(nil), fakeFilterPlugin{}).(framework.EnqueueExtensions)
+	require.True(t, ok)
+	events, err := wrapped.EventsToRegister(context.Background())
+	require.NoError(t, err)
+	// The wrapped plugin is interested in all events like the original plugin is regarded by the scheduler.
+	assert.Equal(t, framework.UnrollWildCardResource(), events)
+}
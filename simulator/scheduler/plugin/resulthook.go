@@ -0,0 +1,120 @@
+package plugin
+
+import (
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	schedulingresultstore "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/resultstore"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/storereflector"
+)
+
+const (
+	// ResultHookKey represents key name of the result hook on sharedstore.
+	ResultHookKey = "PluginResultHookKey"
+	// resultHookBufferSize is the number of results which can wait for the hook.
+	// Results are dropped when the buffer is full so that the hook never blocks the scheduling.
+	resultHookBufferSize = 1000
+)
+
+// ResultHook receives the per-plugin results of each scheduling attempt.
+type ResultHook func(podNamespace, podName string, result schedulingresultstore.SchedulingResult)
+
+type hookedResult struct {
+	namespace string
+	podName   string
+	result    schedulingresultstore.SchedulingResult
+}
+
+// resultHookDispatcher delivers the results in the Store to the ResultHook.
+// It's registered to the storereflector.Reflector as a ResultStore,
+// so that it takes the results when the Reflector reflects them on the Pod after a scheduling attempt,
+// and passes them to the hook in its own goroutine.
+// The goroutine only lives while there are queued results, so that it isn't leaked when the scheduler is stopped.
+type resultHookDispatcher struct {
+	store *schedulingresultstore.Store
+	hook  ResultHook
+
+	mu *sync.Mutex
+	// pending keeps the results taken in GetStoredResult until they're dispatched in DeleteData.
+	pending map[string]schedulingresultstore.SchedulingResult
+	queue   chan hookedResult
+	// running is true while the goroutine calling the hook is running.
+	running bool
+}
+
+// RegisterResultHook registers the hook to receive the results in the Store via the Reflector.
+func RegisterResultHook(sharedStore storereflector.Reflector, store *schedulingresultstore.Store, hook ResultHook) {
+	d := newResultHookDispatcher(store, hook)
+	sharedStore.AddResultStore(d, ResultHookKey)
+}
+
+func newResultHookDispatcher(store *schedulingresultstore.Store, hook ResultHook) *resultHookDispatcher {
+	return &resultHookDispatcher{
+		store:   store,
+		hook:    hook,
+		mu:      new(sync.Mutex),
+		pending: map[string]schedulingresultstore.SchedulingResult{},
+		queue:   make(chan hookedResult, resultHookBufferSize),
+	}
+}
+
+// GetStoredResult takes the result of the Pod from the Store.
+// It always returns nil because the dispatcher doesn't add anything on the Pod.
+func (d *resultHookDispatcher) GetStoredResult(pod *v1.Pod) map[string]string {
+	r, ok := d.store.GetSchedulingResult(pod.Namespace, pod.Name)
+	if !ok {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pending[pod.Namespace+"/"+pod.Name] = r
+	return nil
+}
+
+// DeleteData is called once the results are reflected on the Pod,
+// and it queues the result taken in GetStoredResult for the hook.
+func (d *resultHookDispatcher) DeleteData(pod v1.Pod) {
+	k := pod.Namespace + "/" + pod.Name
+	d.mu.Lock()
+	r, ok := d.pending[k]
+	delete(d.pending, k)
+	d.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	select {
+	case d.queue <- hookedResult{namespace: pod.Namespace, podName: pod.Name, result: r}:
+	default:
+		klog.Warningf("the result hook is too slow, dropped the scheduling result of %s", k)
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.running {
+		d.running = true
+		go d.drain()
+	}
+}
+
+// drain calls the hook with the queued results until the queue gets empty.
+func (d *resultHookDispatcher) drain() {
+	for {
+		select {
+		case r := <-d.queue:
+			d.hook(r.namespace, r.podName, r.result)
+		default:
+			d.mu.Lock()
+			if len(d.queue) == 0 {
+				d.running = false
+				d.mu.Unlock()
+				return
+			}
+			d.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/resultstore"
+)
+
+func Test_resultHookDispatcher(t *testing.T) {
+	t.Parallel()
+
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(node)
+
+	tests := []struct {
+		name     string
+		schedule func(t *testing.T, store *resultstore.Store)
+		wantHook bool
+		// wantMeasured is the extension points whose durations are recorded.
+		wantMeasured []string
+		want         resultstore.SchedulingResult
+	}{
+		{
+			name: "hook receives the results of the wrapped plugins",
+			schedule: func(t *testing.T, store *resultstore.Store) {
+				t.Helper()
+				ctx := context.Background()
+				fp, ok := NewWrappedPlugin(store, fakeFilterPlugin{}).(framework.FilterPlugin)
+				assert.True(t, ok)
+				sp, ok := NewWrappedPlugin(store, fakeScorePlugin{}).(framework.ScorePlugin)
+				assert.True(t, ok)
+				rp, ok := NewWrappedPlugin(store, fakeScorePlugin{}).(framework.ReservePlugin)
+				assert.True(t, ok)
+
+				assert.True(t, fp.Filter(ctx, nil, pod, nodeInfo).IsSuccess())
+				_, s := sp.Score(ctx, nil, pod, node.Name)
+				assert.True(t, s.IsSuccess())
+				assert.True(t, rp.Reserve(ctx, nil, pod, node.Name).IsSuccess())
+			},
+			wantHook:     true,
+			wantMeasured: []string{"Filter", "Score"},
+			want: resultstore.SchedulingResult{
+				SelectedNode: "node1",
+				Filter:       map[string]map[string]string{"node1": {"fakeFilterPlugin": resultstore.PassedFilterMessage}},
+				Score:        map[string]map[string]int64{"node1": {"fakeScorePlugin": 1}},
+				FinalScore:   map[string]map[string]int64{"node1": {"fakeScorePlugin": 2}},
+				TotalScore:   map[string]int64{"node1": 2},
+			},
+		},
+		{
+			name:     "hook isn't called when the pod has no result",
+			schedule: func(_ *testing.T, _ *resultstore.Store) {},
+			wantHook: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			store := resultstore.New(map[string]int32{"fakeScorePlugin": 2})
+			got := make(chan resultstore.SchedulingResult, 1)
+			d := newResultHookDispatcher(store, func(podNamespace, podName string, result resultstore.SchedulingResult) {
+				assert.Equal(t, pod.Namespace, podNamespace)
+				assert.Equal(t, pod.Name, podName)
+				got <- result
+			})
+
+			tt.schedule(t, store)
+			// The same as what the Reflector does when the scheduling attempt is finished.
+			assert.Nil(t, d.GetStoredResult(pod))
+			store.DeleteData(*pod)
+			d.DeleteData(*pod)
+
+			select {
+			case r := <-got:
+				assert.True(t, tt.wantHook, "the hook is called unexpectedly")
+				// The durations vary from run to run, so only their existence is checked.
+				assert.Len(t, r.Duration, len(tt.wantMeasured))
+				for _, ep := range tt.wantMeasured {
+					assert.Contains(t, r.Duration, ep)
+				}
+				r.Duration = nil
+				assert.Equal(t, tt.want, r)
+			case <-time.After(100 * time.Millisecond):
+				assert.False(t, tt.wantHook, "the hook isn't called")
+			}
+		})
+	}
+}
+
+func Test_resultHookDispatcher_DoesNotBlock(t *testing.T) {
+	t.Parallel()
+
+	store := resultstore.New(nil)
+	release := make(chan struct{})
+	// The hook is blocked until release is closed.
+	d := newResultHookDispatcher(store, func(_, _ string, _ resultstore.SchedulingResult) { <-release })
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < resultHookBufferSize+10; i++ {
+			store.AddSelectedNode(pod.Namespace, pod.Name, "node1")
+			d.GetStoredResult(pod)
+			d.DeleteData(*pod)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("dispatching results is blocked by the hook")
+	}
+	// The results exceeding the buffer are dropped.
+	assert.LessOrEqual(t, len(d.queue), resultHookBufferSize)
+
+	// The goroutine calling the hook exits once all results are passed to the hook.
+	close(release)
+	assert.Eventually(t, func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return !d.running && len(d.queue) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
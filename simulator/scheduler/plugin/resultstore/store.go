@@ -8,8 +8,10 @@ import (
 
 	"golang.org/x/xerrors"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/names"
 
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/annotation"
 )
@@ -21,6 +23,9 @@ type Store struct {
 
 	results           map[key]*result
 	scorePluginWeight map[string]int32
+	// resultFilter is the names of the plugins whose results are recorded.
+	// The results of all plugins are recorded if it's empty.
+	resultFilter sets.Set[string]
 }
 
 const (
@@ -32,6 +37,8 @@ const (
 	WaitMessage = "wait"
 	// PostFilterNominatedMessage is used when a postFilter plugin returns success.
 	PostFilterNominatedMessage = "preemption victim"
+	// QueueingHintHistoryLimit is the number of the latest QueueingHint evaluations kept for each Pod.
+	QueueingHintHistoryLimit = 10
 )
 
 // result has a scheduling result of pod.
@@ -49,6 +56,8 @@ type result struct {
 
 	// node name → plugin name → finalScore(string)
 	// This score is normalized and applied weight for each plugins.
+	// It also has the scores of the prioritize extenders keyed by the extender name,
+	// which are applied the weight and scaled to the score range of the plugins as the scheduler does.
 	finalScore map[string]map[string]string
 
 	// plugin name → pre filter status.
@@ -83,6 +92,20 @@ type result struct {
 	// plugin name → bind result(string)
 	bind map[string]string
 
+	// preemption is the outcome of the preemption by DefaultPreemption plugin.
+	// It's nil if the preemption wasn't tried.
+	preemption *PreemptionResult
+
+	// extension point → plugin name → duration
+	// The duration is the total of all calls in the scheduling attempt, e.g., the Filter calls for all nodes.
+	// It's initialized lazily because it's recorded only when the plugins are measured.
+	duration map[string]map[string]time.Duration
+
+	// queueingHints is the latest evaluations of the QueueingHints for the Pod, from the oldest one.
+	// At most QueueingHintHistoryLimit evaluations are kept.
+	// It's initialized lazily because it's recorded only while the Pod is waiting in the scheduling queue.
+	queueingHints []QueueingHintResult
+
 	// customResults has the user defined custom results.
 	// annotation key -> result(string)
 	customResults map[string]string
@@ -98,6 +121,27 @@ func New(scorePluginWeight map[string]int32) *Store {
 	return s
 }
 
+// SetResultFilter makes the Store record only the results of the given plugins and omit the others'.
+// The selected node and the custom results are always recorded.
+// An empty pluginNames makes the Store record the results of all plugins.
+// It's supposed to be called before the scheduling starts.
+func (s *Store) SetResultFilter(pluginNames []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(pluginNames) == 0 {
+		s.resultFilter = nil
+		return
+	}
+	s.resultFilter = sets.New(pluginNames...)
+}
+
+// omitted reports whether the results of the plugin shouldn't be recorded.
+// Note: we assume the store lock is already acquired.
+func (s *Store) omitted(pluginName string) bool {
+	return s.resultFilter != nil && !s.resultFilter.Has(pluginName)
+}
+
 // key is the key of result map on Store.
 // key is created from namespace and podName.
 type key string
@@ -171,6 +215,11 @@ func (s *Store) GetStoredResult(pod *v1.Pod) map[string]string {
 		return nil
 	}
 
+	if err := s.addTotalScoreResultToMap(annotation, k); err != nil {
+		klog.Errorf("failed to add total score result to pod: %+v", err)
+		return nil
+	}
+
 	if err := s.addReserveResultToMap(annotation, k); err != nil {
 		klog.Errorf("failed to add reserve result to pod: %+v", err)
 		return nil
@@ -191,6 +240,21 @@ func (s *Store) GetStoredResult(pod *v1.Pod) map[string]string {
 		return nil
 	}
 
+	if err := s.addPreemptionResultToMap(annotation, k); err != nil {
+		klog.Errorf("failed to add preemption result to pod: %+v", err)
+		return nil
+	}
+
+	if err := s.addPluginDurationToMap(annotation, k); err != nil {
+		klog.Errorf("failed to add plugin durations to pod: %+v", err)
+		return nil
+	}
+
+	if err := s.addQueueingHintResultToMap(annotation, k); err != nil {
+		klog.Errorf("failed to add queueing hint results to pod: %+v", err)
+		return nil
+	}
+
 	s.addCustomResultsToMap(annotation, k)
 	s.addSelectedNodeToPod(annotation, k)
 
@@ -409,6 +473,88 @@ func (s *Store) addFinalScoreResultToMap(anno map[string]string, k key) error {
 	return nil
 }
 
+func (s *Store) addTotalScoreResultToMap(anno map[string]string, k key) error {
+	totals := map[string]string{}
+	for node, total := range totalScores(parseScores(s.results[k].finalScore)) {
+		totals[node] = strconv.FormatInt(total, 10)
+	}
+	scores, err := json.Marshal(totals)
+	if err != nil {
+		return xerrors.Errorf("encode json to record total scores: %w", err)
+	}
+
+	anno[annotation.TotalScoreResultAnnotationKey] = string(scores)
+	return nil
+}
+
+// totalScores returns the total of the final scores for each node.
+func totalScores(finalScores map[string]map[string]int64) map[string]int64 {
+	ret := make(map[string]int64, len(finalScores))
+	for node, scores := range finalScores {
+		var total int64
+		for _, score := range scores {
+			total += score
+		}
+		ret[node] = total
+	}
+	return ret
+}
+
+// addPreemptionResultToMap adds the preemption result only if the preemption was tried.
+func (s *Store) addPreemptionResultToMap(anno map[string]string, k key) error {
+	_, ok := anno[annotation.PreemptionResultAnnotationKey]
+	if ok || s.results[k].preemption == nil {
+		return nil
+	}
+
+	result, err := json.Marshal(s.results[k].preemption)
+	if err != nil {
+		return xerrors.Errorf("encode json to record preemption result: %w", err)
+	}
+
+	anno[annotation.PreemptionResultAnnotationKey] = string(result)
+	return nil
+}
+
+// addPluginDurationToMap adds the durations only if any of them is recorded,
+// because they're recorded only when the plugins are measured.
+func (s *Store) addQueueingHintResultToMap(anno map[string]string, k key) error {
+	_, ok := anno[annotation.QueueingHintResultAnnotationKey]
+	if ok || len(s.results[k].queueingHints) == 0 {
+		return nil
+	}
+
+	result, err := json.Marshal(s.results[k].queueingHints)
+	if err != nil {
+		return xerrors.Errorf("encode json to record queueing hint results: %w", err)
+	}
+
+	anno[annotation.QueueingHintResultAnnotationKey] = string(result)
+	return nil
+}
+
+func (s *Store) addPluginDurationToMap(anno map[string]string, k key) error {
+	_, ok := anno[annotation.PluginDurationAnnotationKey]
+	if ok || len(s.results[k].duration) == 0 {
+		return nil
+	}
+
+	durations := make(map[string]map[string]string, len(s.results[k].duration))
+	for extensionPoint, plugins := range s.results[k].duration {
+		durations[extensionPoint] = make(map[string]string, len(plugins))
+		for p, d := range plugins {
+			durations[extensionPoint][p] = d.String()
+		}
+	}
+	result, err := json.Marshal(durations)
+	if err != nil {
+		return xerrors.Errorf("encode json to record plugin durations: %w", err)
+	}
+
+	anno[annotation.PluginDurationAnnotationKey] = string(result)
+	return nil
+}
+
 func (s *Store) addCustomResultsToMap(anno map[string]string, k key) {
 	for annokey, r := range s.results[k].customResults {
 		_, ok := anno[annokey]
@@ -424,6 +570,10 @@ func (s *Store) AddFilterResult(namespace, podName, nodeName, pluginName, reason
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.omitted(pluginName) {
+		return
+	}
+
 	k := newKey(namespace, podName)
 	if _, ok := s.results[k]; !ok {
 		s.results[k] = newData()
@@ -443,6 +593,10 @@ func (s *Store) AddPostFilterResult(namespace, podName, nominatedNodeName, plugi
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.omitted(pluginName) {
+		return
+	}
+
 	k := newKey(namespace, podName)
 	if _, ok := s.results[k]; !ok {
 		s.results[k] = newData()
@@ -462,6 +616,10 @@ func (s *Store) AddScoreResult(namespace, podName, nodeName, pluginName string,
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.omitted(pluginName) {
+		return
+	}
+
 	k := newKey(namespace, podName)
 	if _, ok := s.results[k]; !ok {
 		s.results[k] = newData()
@@ -482,6 +640,10 @@ func (s *Store) AddNormalizedScoreResult(namespace, podName, nodeName, pluginNam
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.omitted(pluginName) {
+		return
+	}
+
 	s.addNormalizedScoreResultWithoutLock(namespace, podName, nodeName, pluginName, normalizedscore)
 }
 
@@ -501,6 +663,25 @@ func (s *Store) addNormalizedScoreResultWithoutLock(namespace, podName, nodeName
 	s.results[k].finalScore[nodeName][pluginName] = strconv.FormatInt(finalscore, 10)
 }
 
+// AddExtenderScoreResult adds the score of the prioritize extender to the final scores.
+// The score must be already applied the weight of the extender and scaled to the score range of the plugins.
+// It's recorded regardless of the result filter since the extender isn't a plugin.
+func (s *Store) AddExtenderScoreResult(namespace, podName, nodeName, extenderName string, score int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := newKey(namespace, podName)
+	if _, ok := s.results[k]; !ok {
+		s.results[k] = newData()
+	}
+
+	if _, ok := s.results[k].finalScore[nodeName]; !ok {
+		s.results[k].finalScore[nodeName] = map[string]string{}
+	}
+
+	s.results[k].finalScore[nodeName][extenderName] = strconv.FormatInt(score, 10)
+}
+
 func (s *Store) applyWeightOnScore(pluginName string, score int64) int64 {
 	weight := s.scorePluginWeight[pluginName]
 	return score * int64(weight)
@@ -523,6 +704,10 @@ func (s *Store) AddPreFilterResult(namespace, podName, pluginName, reason string
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.omitted(pluginName) {
+		return
+	}
+
 	k := newKey(namespace, podName)
 	if _, ok := s.results[k]; !ok {
 		s.results[k] = newData()
@@ -538,6 +723,10 @@ func (s *Store) AddPreScoreResult(namespace, podName, pluginName, reason string)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.omitted(pluginName) {
+		return
+	}
+
 	k := newKey(namespace, podName)
 	if _, ok := s.results[k]; !ok {
 		s.results[k] = newData()
@@ -550,6 +739,10 @@ func (s *Store) AddPermitResult(namespace, podName, pluginName, status string, t
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.omitted(pluginName) {
+		return
+	}
+
 	k := newKey(namespace, podName)
 	if _, ok := s.results[k]; !ok {
 		s.results[k] = newData()
@@ -575,6 +768,10 @@ func (s *Store) AddReserveResult(namespace, podName, pluginName, status string)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.omitted(pluginName) {
+		return
+	}
+
 	k := newKey(namespace, podName)
 	if _, ok := s.results[k]; !ok {
 		s.results[k] = newData()
@@ -587,6 +784,10 @@ func (s *Store) AddBindResult(namespace, podName, pluginName, status string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.omitted(pluginName) {
+		return
+	}
+
 	k := newKey(namespace, podName)
 	if _, ok := s.results[k]; !ok {
 		s.results[k] = newData()
@@ -599,6 +800,10 @@ func (s *Store) AddPreBindResult(namespace, podName, pluginName, status string)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.omitted(pluginName) {
+		return
+	}
+
 	k := newKey(namespace, podName)
 	if _, ok := s.results[k]; !ok {
 		s.results[k] = newData()
@@ -607,6 +812,97 @@ func (s *Store) AddPreBindResult(namespace, podName, pluginName, status string)
 	s.results[k].prebind[pluginName] = status
 }
 
+// AddPreemptionCandidate adds the victims which have to be preempted to schedule the Pod on the node.
+// It's called for each node evaluated in the dry run of the preemption.
+func (s *Store) AddPreemptionCandidate(namespace, podName, nodeName string, victims []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.omitted(names.DefaultPreemption) {
+		return
+	}
+
+	k := newKey(namespace, podName)
+	if _, ok := s.results[k]; !ok {
+		s.results[k] = newData()
+	}
+	if s.results[k].preemption == nil {
+		s.results[k].preemption = newPreemptionResult()
+	}
+
+	s.results[k].preemption.Candidates[nodeName] = victims
+}
+
+// AddPreemptionResult adds the node nominated by the preemption.
+// The victims on the nominated node among the candidates are recorded as the selected victims.
+func (s *Store) AddPreemptionResult(namespace, podName, nominatedNodeName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.omitted(names.DefaultPreemption) {
+		return
+	}
+
+	k := newKey(namespace, podName)
+	if _, ok := s.results[k]; !ok {
+		s.results[k] = newData()
+	}
+	if s.results[k].preemption == nil {
+		s.results[k].preemption = newPreemptionResult()
+	}
+
+	p := s.results[k].preemption
+	p.NominatedNode = nominatedNodeName
+	p.Victims = append([]string{}, p.Candidates[nominatedNodeName]...)
+}
+
+// AddPluginDuration adds how long the plugin took at the extension point.
+// It's accumulated when the plugin is called several times in a scheduling attempt, e.g., Filter is called for each node.
+func (s *Store) AddPluginDuration(namespace, podName, extensionPoint, pluginName string, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.omitted(pluginName) {
+		return
+	}
+
+	k := newKey(namespace, podName)
+	if _, ok := s.results[k]; !ok {
+		s.results[k] = newData()
+	}
+	if s.results[k].duration == nil {
+		s.results[k].duration = map[string]map[string]time.Duration{}
+	}
+	if _, ok := s.results[k].duration[extensionPoint]; !ok {
+		s.results[k].duration[extensionPoint] = map[string]time.Duration{}
+	}
+
+	s.results[k].duration[extensionPoint][pluginName] += duration
+}
+
+// AddQueueingHintResult adds the evaluation of the QueueingHint for the Pod.
+// Only the latest QueueingHintHistoryLimit evaluations are kept.
+func (s *Store) AddQueueingHintResult(namespace, podName string, result QueueingHintResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.omitted(result.Plugin) {
+		return
+	}
+
+	k := newKey(namespace, podName)
+	if _, ok := s.results[k]; !ok {
+		s.results[k] = newData()
+	}
+
+	hints := append(s.results[k].queueingHints, result)
+	if len(hints) > QueueingHintHistoryLimit {
+		// drop the oldest ones.
+		hints = append([]QueueingHintResult{}, hints[len(hints)-QueueingHintHistoryLimit:]...)
+	}
+	s.results[k].queueingHints = hints
+}
+
 // AddCustomResult adds user defined data.
 // The results added through this func is reflected on the Pod's annotation eventually like other scheduling results.
 // This function is intended to be called from the plugin.PluginExtender; allow users to export some internal state on Pods for debugging purpose.
@@ -624,3 +920,132 @@ func (s *Store) AddCustomResult(namespace, podName, annotationKey, result string
 	}
 	s.results[k].customResults[annotationKey] = result
 }
+
+// SchedulingResult is a snapshot of the per-plugin results of a scheduling attempt of a Pod.
+type SchedulingResult struct {
+	// SelectedNode is the node selected for the Pod. It's empty if the Pod wasn't scheduled.
+	SelectedNode string `json:"selectedNode"`
+	// Filter is node name → plugin name → filtering result.
+	// The result is PassedFilterMessage when the node passed the filter, otherwise the reason of the rejection.
+	Filter map[string]map[string]string `json:"filter"`
+	// Score is node name → plugin name → score.
+	Score map[string]map[string]int64 `json:"score"`
+	// FinalScore is node name → plugin name → normalized and weighted score.
+	// It also has the weighted scores of the prioritize extenders keyed by the extender name.
+	FinalScore map[string]map[string]int64 `json:"finalScore"`
+	// TotalScore is node name → the total of FinalScore, which the scheduler ranks the nodes with.
+	TotalScore map[string]int64 `json:"totalScore"`
+	// Preemption is the outcome of the preemption by DefaultPreemption plugin. It's nil if the preemption wasn't tried.
+	Preemption *PreemptionResult `json:"preemption,omitempty"`
+	// Duration is extension point → plugin name → total duration of the calls in the attempt, in nanoseconds in JSON.
+	// It only has the PreFilter, Filter, PostFilter and Score extension points, and it's empty if the plugins aren't measured.
+	Duration map[string]map[string]time.Duration `json:"duration,omitempty"`
+	// QueueingHints is the latest evaluations of the QueueingHints for the Pod while it was waiting in the scheduling queue,
+	// from the oldest one. It's empty if the Pod wasn't requeued by any cluster event.
+	QueueingHints []QueueingHintResult `json:"queueingHints,omitempty"`
+}
+
+// QueueingHintResult is an evaluation of the QueueingHint of a plugin
+// for the Pod rejected by the plugin when a cluster event happened.
+type QueueingHintResult struct {
+	// Plugin is the name of the plugin whose QueueingHint was evaluated.
+	Plugin string `json:"plugin"`
+	// Event is the label of the cluster event, e.g., NodeAdd.
+	Event string `json:"event"`
+	// Hint is the result of the QueueingHint, Queue or QueueSkip.
+	Hint string `json:"hint"`
+	// Error is the error returned from the QueueingHint, if any. The scheduler requeues the Pod on the error.
+	Error string `json:"error,omitempty"`
+}
+
+// PreemptionResult is the outcome of the preemption for a Pod.
+// The Pods are represented as namespace/name.
+type PreemptionResult struct {
+	// NominatedNode is the node nominated for the Pod by the preemption. It's empty if no node was nominated.
+	NominatedNode string `json:"nominatedNode"`
+	// Candidates is node name → victims which have to be preempted to schedule the Pod on the node.
+	// It has all the nodes evaluated in the dry run of the preemption.
+	Candidates map[string][]string `json:"candidates"`
+	// Victims are the Pods preempted to make room on NominatedNode.
+	Victims []string `json:"victims"`
+}
+
+func newPreemptionResult() *PreemptionResult {
+	return &PreemptionResult{Candidates: map[string][]string{}, Victims: []string{}}
+}
+
+// DeepCopy returns a deep copy of the PreemptionResult.
+func (p *PreemptionResult) DeepCopy() *PreemptionResult {
+	if p == nil {
+		return nil
+	}
+	ret := &PreemptionResult{
+		NominatedNode: p.NominatedNode,
+		Candidates:    make(map[string][]string, len(p.Candidates)),
+		Victims:       append([]string{}, p.Victims...),
+	}
+	for node, victims := range p.Candidates {
+		ret.Candidates[node] = append([]string{}, victims...)
+	}
+	return ret
+}
+
+// GetSchedulingResult returns a snapshot of the stored result of the given Pod.
+// It returns false if the Store doesn't have any result of the Pod.
+func (s *Store) GetSchedulingResult(namespace, podName string) (SchedulingResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.results[newKey(namespace, podName)]
+	if !ok {
+		return SchedulingResult{}, false
+	}
+
+	filter := make(map[string]map[string]string, len(r.filter))
+	for node, plugins := range r.filter {
+		filter[node] = make(map[string]string, len(plugins))
+		for p, reason := range plugins {
+			filter[node][p] = reason
+		}
+	}
+
+	var duration map[string]map[string]time.Duration
+	if len(r.duration) != 0 {
+		duration = make(map[string]map[string]time.Duration, len(r.duration))
+		for extensionPoint, plugins := range r.duration {
+			duration[extensionPoint] = make(map[string]time.Duration, len(plugins))
+			for p, d := range plugins {
+				duration[extensionPoint][p] = d
+			}
+		}
+	}
+
+	finalScore := parseScores(r.finalScore)
+	return SchedulingResult{
+		SelectedNode:  r.selectedNode,
+		Filter:        filter,
+		Score:         parseScores(r.score),
+		FinalScore:    finalScore,
+		TotalScore:    totalScores(finalScore),
+		Preemption:    r.preemption.DeepCopy(),
+		Duration:      duration,
+		QueueingHints: append([]QueueingHintResult(nil), r.queueingHints...),
+	}, true
+}
+
+// parseScores converts the scores kept as string for the annotations back to int64.
+func parseScores(scores map[string]map[string]string) map[string]map[string]int64 {
+	ret := make(map[string]map[string]int64, len(scores))
+	for node, plugins := range scores {
+		ret[node] = make(map[string]int64, len(plugins))
+		for p, score := range plugins {
+			v, err := strconv.ParseInt(score, 10, 64)
+			if err != nil {
+				klog.Errorf("failed to parse the score %q of %s on %s: %+v", score, p, node, err)
+				continue
+			}
+			ret[node][p] = v
+		}
+	}
+	return ret
+}
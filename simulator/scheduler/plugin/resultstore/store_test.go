@@ -2,6 +2,7 @@ package resultstore
 
 import (
 	"encoding/json"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -744,6 +745,7 @@ func TestStore_GetStoredResult(t *testing.T) {
 					d, _ := json.Marshal(r)
 					return string(d)
 				}(),
+				annotation.TotalScoreResultAnnotationKey: `{"node0":"20","node1":"20"}`,
 				annotation.PostFilterResultAnnotationKey: func() string {
 					r := map[string]map[string]string{
 						"node0": {
@@ -804,6 +806,7 @@ func TestStore_GetStoredResult(t *testing.T) {
 				}(),
 				annotation.ScoreResultAnnotationKey:           "{}",
 				annotation.FinalScoreResultAnnotationKey:      "{}",
+				annotation.TotalScoreResultAnnotationKey:      "{}",
 				annotation.PostFilterResultAnnotationKey:      "{}",
 				annotation.SelectedNodeAnnotationKey:          "",
 				annotation.PreScoreResultAnnotationKey:        "{}",
@@ -1012,6 +1015,207 @@ func TestStore_AddSelectedNode(t *testing.T) {
 	}
 }
 
+func TestStore_AddPreemptionResult(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name              string
+		candidates        map[string][]string
+		nominatedNodeName string
+		want              *PreemptionResult
+		wantAnnotation    string
+	}{
+		{
+			name: "the victims on the nominated node are selected",
+			candidates: map[string][]string{
+				"node1": {"default/low1"},
+				"node2": {"default/low2", "default/low3"},
+			},
+			nominatedNodeName: "node2",
+			want: &PreemptionResult{
+				NominatedNode: "node2",
+				Candidates: map[string][]string{
+					"node1": {"default/low1"},
+					"node2": {"default/low2", "default/low3"},
+				},
+				Victims: []string{"default/low2", "default/low3"},
+			},
+			wantAnnotation: `{"nominatedNode":"node2","candidates":{"node1":["default/low1"],"node2":["default/low2","default/low3"]},"victims":["default/low2","default/low3"]}`,
+		},
+		{
+			name: "no node is nominated",
+			candidates: map[string][]string{
+				"node1": {"default/low1"},
+			},
+			want: &PreemptionResult{
+				Candidates: map[string][]string{
+					"node1": {"default/low1"},
+				},
+				Victims: []string{},
+			},
+			wantAnnotation: `{"nominatedNode":"","candidates":{"node1":["default/low1"]},"victims":[]}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := New(nil)
+			for node, victims := range tt.candidates {
+				s.AddPreemptionCandidate("default", "pod1", node, victims)
+			}
+			if tt.nominatedNodeName != "" {
+				s.AddPreemptionResult("default", "pod1", tt.nominatedNodeName)
+			}
+
+			r, ok := s.GetSchedulingResult("default", "pod1")
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, r.Preemption)
+			anno := s.GetStoredResult(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}})
+			assert.Equal(t, tt.wantAnnotation, anno[annotation.PreemptionResultAnnotationKey])
+		})
+	}
+}
+
+func TestStore_AddPluginDuration(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		extensionPoint string
+		pluginName     string
+		duration       time.Duration
+	}
+	tests := []struct {
+		name           string
+		args           []args
+		wantDuration   map[string]map[string]time.Duration
+		wantAnnotation string
+	}{
+		{
+			name: "accumulate the durations of the same plugin and extension point",
+			args: []args{
+				{extensionPoint: "Filter", pluginName: "plugin1", duration: time.Millisecond},
+				{extensionPoint: "Filter", pluginName: "plugin1", duration: 2 * time.Millisecond},
+				{extensionPoint: "Filter", pluginName: "plugin2", duration: time.Microsecond},
+				{extensionPoint: "Score", pluginName: "plugin1", duration: time.Second},
+			},
+			wantDuration: map[string]map[string]time.Duration{
+				"Filter": {"plugin1": 3 * time.Millisecond, "plugin2": time.Microsecond},
+				"Score":  {"plugin1": time.Second},
+			},
+			wantAnnotation: `{"Filter":{"plugin1":"3ms","plugin2":"1µs"},"Score":{"plugin1":"1s"}}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := New(nil)
+			for _, a := range tt.args {
+				s.AddPluginDuration("default", "pod1", a.extensionPoint, a.pluginName, a.duration)
+			}
+
+			r, ok := s.GetSchedulingResult("default", "pod1")
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantDuration, r.Duration)
+			anno := s.GetStoredResult(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}})
+			assert.Equal(t, tt.wantAnnotation, anno[annotation.PluginDurationAnnotationKey])
+		})
+	}
+}
+
+func TestStore_SetResultFilter(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name             string
+		resultFilter     []string
+		wantFilter       string
+		wantScore        string
+		wantSelectedNode string
+	}{
+		{
+			name:             "store only the results of the listed plugins",
+			resultFilter:     []string{"NodeResourcesFit", "MyPlugin"},
+			wantFilter:       `{"node1":{"MyPlugin":"passed","NodeResourcesFit":"passed"}}`,
+			wantScore:        `{"node1":{"NodeResourcesFit":"10"}}`,
+			wantSelectedNode: "node1",
+		},
+		{
+			name:             "store the results of all plugins with the empty filter",
+			resultFilter:     []string{},
+			wantFilter:       `{"node1":{"MyPlugin":"passed","NodeAffinity":"passed","NodeResourcesFit":"passed"}}`,
+			wantScore:        `{"node1":{"NodeAffinity":"20","NodeResourcesFit":"10"}}`,
+			wantSelectedNode: "node1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := New(map[string]int32{"NodeResourcesFit": 1, "NodeAffinity": 1})
+			s.SetResultFilter(tt.resultFilter)
+			for _, p := range []string{"NodeResourcesFit", "NodeAffinity", "MyPlugin"} {
+				s.AddFilterResult("default", "pod1", "node1", p, PassedFilterMessage)
+			}
+			s.AddScoreResult("default", "pod1", "node1", "NodeResourcesFit", 10)
+			s.AddScoreResult("default", "pod1", "node1", "NodeAffinity", 20)
+			s.AddSelectedNode("default", "pod1", "node1")
+
+			anno := s.GetStoredResult(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}})
+			assert.Equal(t, tt.wantFilter, anno[annotation.FilterResultAnnotationKey])
+			assert.Equal(t, tt.wantScore, anno[annotation.ScoreResultAnnotationKey])
+			assert.Equal(t, tt.wantSelectedNode, anno[annotation.SelectedNodeAnnotationKey])
+		})
+	}
+}
+
+func TestStore_AddExtenderScoreResult(t *testing.T) {
+	t.Parallel()
+	s := New(map[string]int32{"NodeResourcesFit": 1, "NodeAffinity": 3})
+	s.AddNormalizedScoreResult("default", "pod1", "node1", "NodeResourcesFit", 50)
+	s.AddNormalizedScoreResult("default", "pod1", "node1", "NodeAffinity", 20)
+	s.AddNormalizedScoreResult("default", "pod1", "node2", "NodeResourcesFit", 80)
+	s.AddNormalizedScoreResult("default", "pod1", "node2", "NodeAffinity", 0)
+	// The extender with weight 2 scored 3 on node1 and 1 on node2: score * weight * (MaxNodeScore / MaxExtenderPriority).
+	s.AddExtenderScoreResult("default", "pod1", "node1", "http://extender", 3*2*10)
+	s.AddExtenderScoreResult("default", "pod1", "node2", "http://extender", 1*2*10)
+
+	r, ok := s.GetSchedulingResult("default", "pod1")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]map[string]int64{
+		"node1": {"NodeResourcesFit": 50, "NodeAffinity": 60, "http://extender": 60},
+		"node2": {"NodeResourcesFit": 80, "NodeAffinity": 0, "http://extender": 20},
+	}, r.FinalScore)
+	// node1: 50 * 1 + 20 * 3 + 60 = 170, node2: 80 * 1 + 0 * 3 + 20 = 100
+	assert.Equal(t, map[string]int64{"node1": 170, "node2": 100}, r.TotalScore)
+
+	anno := s.GetStoredResult(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}})
+	assert.JSONEq(t, `{"node1":{"NodeResourcesFit":"50","NodeAffinity":"60","http://extender":"60"},"node2":{"NodeResourcesFit":"80","NodeAffinity":"0","http://extender":"20"}}`, anno[annotation.FinalScoreResultAnnotationKey])
+	assert.JSONEq(t, `{"node1":"170","node2":"100"}`, anno[annotation.TotalScoreResultAnnotationKey])
+}
+
+func TestStore_AddQueueingHintResult(t *testing.T) {
+	t.Parallel()
+	s := New(nil)
+	for i := 0; i < QueueingHintHistoryLimit+2; i++ {
+		hint := "QueueSkip"
+		if i%2 == 1 {
+			hint = "Queue"
+		}
+		s.AddQueueingHintResult("default", "pod1", QueueingHintResult{Plugin: "plugin1", Event: fmt.Sprintf("event%d", i), Hint: hint})
+	}
+
+	r, ok := s.GetSchedulingResult("default", "pod1")
+	assert.True(t, ok)
+	// The oldest ones are dropped.
+	assert.Len(t, r.QueueingHints, QueueingHintHistoryLimit)
+	assert.Equal(t, QueueingHintResult{Plugin: "plugin1", Event: "event2", Hint: "QueueSkip"}, r.QueueingHints[0])
+	assert.Equal(t, QueueingHintResult{Plugin: "plugin1", Event: fmt.Sprintf("event%d", QueueingHintHistoryLimit+1), Hint: "Queue"}, r.QueueingHints[QueueingHintHistoryLimit-1])
+
+	anno := s.GetStoredResult(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}})
+	got := []QueueingHintResult{}
+	assert.NoError(t, json.Unmarshal([]byte(anno[annotation.QueueingHintResultAnnotationKey]), &got))
+	assert.Equal(t, r.QueueingHints, got)
+}
+
 func TestStore_AddReserveResult(t *testing.T) {
 	t.Parallel()
 	type args struct {
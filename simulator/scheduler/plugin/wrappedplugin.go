@@ -2,11 +2,13 @@ package plugin
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	schedulermetrics "k8s.io/kubernetes/pkg/scheduler/metrics"
 
 	schedulingresultstore "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/resultstore"
 )
@@ -212,18 +214,26 @@ type wrappedPlugin struct {
 	// store records plugin's result.
 	// TODO: move store's logic to plugin extender.
 	store Store
-
-	originalPreEnqueuePlugin framework.PreEnqueuePlugin
-	originalPreFilterPlugin  framework.PreFilterPlugin
-	originalFilterPlugin     framework.FilterPlugin
-	originalPreScorePlugin   framework.PreScorePlugin
-	originalPostFilterPlugin framework.PostFilterPlugin
-	originalScorePlugin      framework.ScorePlugin
-	originalPermitPlugin     framework.PermitPlugin
-	originalReservePlugin    framework.ReservePlugin
-	originalPreBindPlugin    framework.PreBindPlugin
-	originalBindPlugin       framework.BindPlugin
-	originalPostBindPlugin   framework.PostBindPlugin
+	// durationStore records the durations of the plugin. It's nil if store doesn't implement DurationStore.
+	durationStore DurationStore
+	// preemptionStore records the outcome of the preemption. It's non-nil only if the original plugin is DefaultPreemption plugin
+	// and store implements PreemptionStore.
+	preemptionStore PreemptionStore
+	// queueingHintStore records the evaluations of the QueueingHints. It's nil if store doesn't implement QueueingHintStore.
+	queueingHintStore QueueingHintStore
+
+	originalEnqueueExtensions framework.EnqueueExtensions
+	originalPreEnqueuePlugin  framework.PreEnqueuePlugin
+	originalPreFilterPlugin   framework.PreFilterPlugin
+	originalFilterPlugin      framework.FilterPlugin
+	originalPreScorePlugin    framework.PreScorePlugin
+	originalPostFilterPlugin  framework.PostFilterPlugin
+	originalScorePlugin       framework.ScorePlugin
+	originalPermitPlugin      framework.PermitPlugin
+	originalReservePlugin     framework.ReservePlugin
+	originalPreBindPlugin     framework.PreBindPlugin
+	originalBindPlugin        framework.BindPlugin
+	originalPostBindPlugin    framework.PostBindPlugin
 
 	// plugin extenders
 	preFilterPluginExtender      PreFilterPluginExtender
@@ -247,6 +257,12 @@ func pluginName(pluginName string) string {
 	return pluginName + pluginSuffix
 }
 
+// OriginalPluginName returns the name of the original plugin from the name of the wrapped plugin.
+// It returns name as is if it isn't the name of the wrapped plugin.
+func OriginalPluginName(name string) string {
+	return strings.TrimSuffix(name, pluginSuffix)
+}
+
 // NewWrappedPlugin makes wrappedPlugin from score or/and filter plugin.
 //
 //nolint:funlen,cyclop
@@ -267,6 +283,15 @@ func NewWrappedPlugin(s Store, p framework.Plugin, opts ...Option) framework.Plu
 		name:  pName,
 		store: s,
 	}
+	if ds, ok := s.(DurationStore); ok {
+		plg.durationStore = ds
+	}
+	if ps, ok := s.(PreemptionStore); ok && recordPreemption(p, ps) {
+		plg.preemptionStore = ps
+	}
+	if qs, ok := s.(QueueingHintStore); ok {
+		plg.queueingHintStore = qs
+	}
 
 	extender := options.extenderInitializerOption(s)
 
@@ -304,6 +329,10 @@ func NewWrappedPlugin(s Store, p framework.Plugin, opts ...Option) framework.Plu
 		plg.postBindPluginExtender = extender.PostBindPluginExtender
 	}
 
+	eep, ok := p.(framework.EnqueueExtensions)
+	if ok {
+		plg.originalEnqueueExtensions = eep
+	}
 	peqp, ok := p.(framework.PreEnqueuePlugin)
 	if ok {
 		plg.originalPreEnqueuePlugin = peqp
@@ -430,7 +459,9 @@ func (w *wrappedPlugin) Score(ctx context.Context, state *framework.CycleState,
 		}
 	}
 
+	start := w.startMeasuring()
 	score, s := w.originalScorePlugin.Score(ctx, state, pod, nodeName)
+	w.recordDuration(pod, schedulermetrics.Score, w.originalScorePlugin, start)
 	if !s.IsSuccess() {
 		klog.Errorf("failed to run score plugin. Scores won't be recorded on Pod annotation: %v, %v", s.Code(), s.Message())
 	} else {
@@ -501,7 +532,9 @@ func (w *wrappedPlugin) PreFilter(ctx context.Context, state *framework.CycleSta
 		}
 	}
 
+	start := w.startMeasuring()
 	result, s := w.originalPreFilterPlugin.PreFilter(ctx, state, p)
+	w.recordDuration(p, schedulermetrics.PreFilter, w.originalPreFilterPlugin, start)
 	var msg string
 	if s.IsSuccess() {
 		msg = schedulingresultstore.SuccessMessage
@@ -532,7 +565,9 @@ func (w *wrappedPlugin) Filter(ctx context.Context, state *framework.CycleState,
 		}
 	}
 
+	start := w.startMeasuring()
 	s := w.originalFilterPlugin.Filter(ctx, state, pod, nodeInfo)
+	w.recordDuration(pod, schedulermetrics.Filter, w.originalFilterPlugin, start)
 	var msg string
 	if s.IsSuccess() {
 		msg = schedulingresultstore.PassedFilterMessage
@@ -559,7 +594,9 @@ func (w *wrappedPlugin) PostFilter(ctx context.Context, state *framework.CycleSt
 			return r, s
 		}
 	}
+	start := w.startMeasuring()
 	r, s := w.originalPostFilterPlugin.PostFilter(ctx, state, pod, filteredNodeStatusMap)
+	w.recordDuration(pod, schedulermetrics.PostFilter, w.originalPostFilterPlugin, start)
 	var nominatedNodeName string
 	if s.IsSuccess() {
 		nominatedNodeName = r.NominatedNodeName
@@ -575,6 +612,9 @@ func (w *wrappedPlugin) PostFilter(ctx context.Context, state *framework.CycleSt
 		})
 	}
 	w.store.AddPostFilterResult(pod.Namespace, pod.Name, nominatedNodeName, w.originalPostFilterPlugin.Name(), nodeNames)
+	if w.preemptionStore != nil && nominatedNodeName != "" {
+		w.preemptionStore.AddPreemptionResult(pod.Namespace, pod.Name, nominatedNodeName)
+	}
 
 	if w.postFilterPluginExtender != nil {
 		return w.postFilterPluginExtender.AfterPostFilter(ctx, state, pod, filteredNodeStatusMap, r, s)
@@ -42,6 +42,8 @@ func Test_NewWrappedPlugin(t *testing.T) {
 				originalFilterPlugin: fakeFilterPlugin{},
 				originalScorePlugin:  nil,
 				store:                store,
+				durationStore:        store,
+				queueingHintStore:    store,
 			},
 		},
 		{
@@ -56,6 +58,8 @@ func Test_NewWrappedPlugin(t *testing.T) {
 				originalPostFilterPlugin: fakePostFilterPlugin{},
 				originalScorePlugin:      nil,
 				store:                    store,
+				durationStore:            store,
+				queueingHintStore:        store,
 			},
 		},
 		{
@@ -69,6 +73,8 @@ func Test_NewWrappedPlugin(t *testing.T) {
 				originalFilterPlugin: nil,
 				originalScorePlugin:  fakeScorePlugin{},
 				store:                store,
+				durationStore:        store,
+				queueingHintStore:    store,
 			},
 		},
 		{
@@ -83,6 +89,8 @@ func Test_NewWrappedPlugin(t *testing.T) {
 				originalScorePlugin:      fakeWrappedPlugin{},
 				originalPostFilterPlugin: fakeWrappedPlugin{},
 				store:                    store,
+				durationStore:            store,
+				queueingHintStore:        store,
 			},
 		},
 	}
@@ -122,6 +130,8 @@ func Test_NewWrappedPlugin_WithPluginNameOption(t *testing.T) {
 				originalFilterPlugin: fakeFilterPlugin{},
 				originalScorePlugin:  nil,
 				store:                store,
+				durationStore:        store,
+				queueingHintStore:    store,
 			},
 		},
 	}
@@ -1920,6 +1930,46 @@ func Test_wrappedPlugin_PostBind(t *testing.T) {
 
 // fake plugins for test
 
+func Test_wrappedPlugin_recordsDuration(t *testing.T) {
+	t.Parallel()
+
+	const sleep = 10 * time.Millisecond
+	store := resultstore.New(nil)
+	p := NewWrappedPlugin(store, fakeSlowPlugin{sleep: sleep})
+	ctx := context.Background()
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+
+	//nolint:forcetypeassert // fakeSlowPlugin implements all of them.
+	_, s := p.(framework.PreFilterPlugin).PreFilter(ctx, nil, pod)
+	assert.True(t, s.IsSuccess())
+	for _, n := range []string{"node1", "node2"} {
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: n}})
+		//nolint:forcetypeassert // fakeSlowPlugin implements all of them.
+		assert.True(t, p.(framework.FilterPlugin).Filter(ctx, nil, pod, nodeInfo).IsSuccess())
+		//nolint:forcetypeassert // fakeSlowPlugin implements all of them.
+		_, s := p.(framework.ScorePlugin).Score(ctx, nil, pod, n)
+		assert.True(t, s.IsSuccess())
+	}
+	//nolint:forcetypeassert // fakeSlowPlugin implements all of them.
+	_, s = p.(framework.PostFilterPlugin).PostFilter(ctx, nil, pod, framework.NewDefaultNodeToStatus())
+	assert.True(t, s.IsSuccess())
+
+	r, ok := store.GetSchedulingResult(pod.Namespace, pod.Name)
+	assert.True(t, ok)
+	// The durations of the calls for all nodes are accumulated.
+	wantAtLeast := map[string]time.Duration{
+		"PreFilter":  sleep,
+		"Filter":     2 * sleep,
+		"Score":      2 * sleep,
+		"PostFilter": sleep,
+	}
+	assert.Len(t, r.Duration, len(wantAtLeast))
+	for extensionPoint, want := range wantAtLeast {
+		assert.GreaterOrEqual(t, r.Duration[extensionPoint]["fakeSlowPlugin"], want, extensionPoint)
+	}
+}
+
 type fakeFilterPlugin struct{}
 
 func (fakeFilterPlugin) Name() string { return "fakeFilterPlugin" }
@@ -2002,3 +2052,38 @@ func (fakeMustFailWrappedPlugin) NormalizeScore(_ context.Context, _ *framework.
 func (fakeMustFailWrappedPlugin) Score(_ context.Context, _ *framework.CycleState, _ *v1.Pod, _ string) (int64, *framework.Status) {
 	return 0, framework.AsStatus(errScore)
 }
+
+// fakeSlowPlugin sleeps in each extension point.
+type fakeSlowPlugin struct {
+	sleep time.Duration
+}
+
+func (fakeSlowPlugin) Name() string { return "fakeSlowPlugin" }
+
+func (pl fakeSlowPlugin) PreFilter(_ context.Context, _ *framework.CycleState, _ *v1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	time.Sleep(pl.sleep)
+	return nil, nil
+}
+
+func (fakeSlowPlugin) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+func (pl fakeSlowPlugin) Filter(_ context.Context, _ *framework.CycleState, _ *v1.Pod, _ *framework.NodeInfo) *framework.Status {
+	time.Sleep(pl.sleep)
+	return nil
+}
+
+func (pl fakeSlowPlugin) PostFilter(_ context.Context, _ *framework.CycleState, _ *v1.Pod, _ framework.NodeToStatusReader) (*framework.PostFilterResult, *framework.Status) {
+	time.Sleep(pl.sleep)
+	return &framework.PostFilterResult{NominatingInfo: &framework.NominatingInfo{}}, nil
+}
+
+func (pl fakeSlowPlugin) Score(_ context.Context, _ *framework.CycleState, _ *v1.Pod, _ string) (int64, *framework.Status) {
+	time.Sleep(pl.sleep)
+	return 0, nil
+}
+
+func (fakeSlowPlugin) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
@@ -0,0 +1,235 @@
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"golang.org/x/xerrors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+)
+
+const (
+	// failedSchedulingReason is the reason of the event the scheduler records when it fails to schedule a Pod.
+	failedSchedulingReason = "FailedScheduling"
+
+	// The defaults of PodInitialBackoffSeconds and PodMaxBackoffSeconds of KubeSchedulerConfiguration.
+	defaultPodInitialBackoff = 1 * time.Second
+	defaultPodMaxBackoff     = 10 * time.Second
+)
+
+// SchedulingQueue is the Pods waiting to be scheduled, grouped by the queue of the scheduler they're in.
+//
+// The scheduler runs in another process, so the queues are estimated
+// from the status and the FailedScheduling events of the Pods, when SchedulingQueue is called.
+type SchedulingQueue struct {
+	// ActiveQ is the Pods which will be tried to be scheduled next.
+	ActiveQ []QueuedPod `json:"activeQ"`
+	// BackoffQ is the Pods which failed with an error and wait for the backoff to expire.
+	BackoffQ []QueuedPod `json:"backoffQ"`
+	// UnschedulablePods is the Pods which were rejected by the plugins and wait for a cluster event to be retried.
+	UnschedulablePods []QueuedPod `json:"unschedulablePods"`
+}
+
+// QueuedPod is a Pod in the scheduling queue.
+type QueuedPod struct {
+	Namespace         string      `json:"namespace"`
+	Name              string      `json:"name"`
+	SchedulerName     string      `json:"schedulerName"`
+	Priority          int32       `json:"priority"`
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+	// Attempts is the number of the failed scheduling attempts.
+	Attempts int32 `json:"attempts"`
+	// LastFailureReason is the reason of the PodScheduled condition, i.e., Unschedulable or SchedulerError.
+	LastFailureReason string `json:"lastFailureReason,omitempty"`
+	// LastFailureMessage is the message of the latest FailedScheduling event, or of the PodScheduled condition.
+	LastFailureMessage string       `json:"lastFailureMessage,omitempty"`
+	LastFailureTime    *metav1.Time `json:"lastFailureTime,omitempty"`
+	// BackoffExpiration is when the Pod is moved to ActiveQ. It's set only for the Pods in BackoffQ.
+	BackoffExpiration *metav1.Time `json:"backoffExpiration,omitempty"`
+	NominatedNodeName string       `json:"nominatedNodeName,omitempty"`
+}
+
+// SchedulingQueue returns the Pods waiting to be scheduled by the schedulers of the current config,
+// grouped by the queue they're in and sorted in the order the scheduler pops them.
+func (s *Service) SchedulingQueue(ctx context.Context) (*SchedulingQueue, error) {
+	pods, err := s.clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, xerrors.Errorf("list pods: %w", err)
+	}
+	events, err := s.clientset.CoreV1().Events(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, xerrors.Errorf("list events: %w", err)
+	}
+	eventsByPod := map[types.UID][]v1.Event{}
+	for _, e := range events.Items {
+		if e.InvolvedObject.Kind == "Pod" && e.Reason == failedSchedulingReason {
+			eventsByPod[e.InvolvedObject.UID] = append(eventsByPod[e.InvolvedObject.UID], e)
+		}
+	}
+
+	s.mu.RLock()
+	cfg := s.currentSchedulerCfg
+	s.mu.RUnlock()
+	schedulerNames := profileSchedulerNames(cfg)
+	initialBackoff, maxBackoff := podBackoff(cfg)
+
+	now := s.now()
+	q := &SchedulingQueue{ActiveQ: []QueuedPod{}, BackoffQ: []QueuedPod{}, UnschedulablePods: []QueuedPod{}}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != "" || pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodPending || !schedulerNames[pod.Spec.SchedulerName] {
+			continue
+		}
+		p := toQueuedPod(pod, eventsByPod[pod.UID])
+		switch p.LastFailureReason {
+		case "":
+			q.ActiveQ = append(q.ActiveQ, p)
+		case v1.PodReasonUnschedulable:
+			q.UnschedulablePods = append(q.UnschedulablePods, p)
+		default:
+			// The scheduler puts the Pods failed with an error in backoffQ.
+			expiration := p.LastFailureTime.Add(backoffDuration(p.Attempts, initialBackoff, maxBackoff))
+			if !now.Before(expiration) {
+				q.ActiveQ = append(q.ActiveQ, p)
+				continue
+			}
+			p.BackoffExpiration = &metav1.Time{Time: expiration}
+			q.BackoffQ = append(q.BackoffQ, p)
+		}
+	}
+	sortQueuedPods(q.ActiveQ)
+	sortQueuedPods(q.BackoffQ)
+	sortQueuedPods(q.UnschedulablePods)
+	return q, nil
+}
+
+// toQueuedPod returns the pod in the queue with its failed attempts counted from events.
+func toQueuedPod(pod *v1.Pod, events []v1.Event) QueuedPod {
+	p := QueuedPod{
+		Namespace:         pod.Namespace,
+		Name:              pod.Name,
+		SchedulerName:     pod.Spec.SchedulerName,
+		CreationTimestamp: pod.CreationTimestamp,
+		NominatedNodeName: pod.Status.NominatedNodeName,
+	}
+	if pod.Spec.Priority != nil {
+		p.Priority = *pod.Spec.Priority
+	}
+
+	var lastEvent time.Time
+	for _, e := range events {
+		p.Attempts += eventCount(e)
+		if t := eventTime(e); !t.Before(lastEvent) {
+			lastEvent = t
+			p.LastFailureMessage = e.Message
+		}
+	}
+
+	for _, c := range pod.Status.Conditions {
+		if c.Type != v1.PodScheduled || c.Status != v1.ConditionFalse || c.Reason == "" {
+			continue
+		}
+		p.LastFailureReason = c.Reason
+		if p.LastFailureMessage == "" {
+			p.LastFailureMessage = c.Message
+		}
+		t := c.LastTransitionTime.Time
+		if lastEvent.After(t) {
+			t = lastEvent
+		}
+		p.LastFailureTime = &metav1.Time{Time: t}
+		// The events may be garbage-collected, but the condition tells the Pod failed at least once.
+		if p.Attempts == 0 {
+			p.Attempts = 1
+		}
+	}
+	return p
+}
+
+// eventCount returns how many times the event is observed.
+func eventCount(e v1.Event) int32 {
+	if e.Series != nil && e.Series.Count > 0 {
+		return e.Series.Count
+	}
+	if e.Count > 0 {
+		return e.Count
+	}
+	return 1
+}
+
+// eventTime returns when the event is observed last.
+func eventTime(e v1.Event) time.Time {
+	switch {
+	case e.Series != nil && !e.Series.LastObservedTime.IsZero():
+		return e.Series.LastObservedTime.Time
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.FirstTimestamp.Time
+	}
+}
+
+// profileSchedulerNames returns the scheduler names of the profiles in cfg.
+// The Pods of the other schedulers are never in the queue.
+func profileSchedulerNames(cfg *configv1.KubeSchedulerConfiguration) map[string]bool {
+	names := map[string]bool{}
+	if cfg != nil {
+		for _, p := range cfg.Profiles {
+			if p.SchedulerName != nil {
+				names[*p.SchedulerName] = true
+			}
+		}
+	}
+	if len(names) == 0 {
+		names[v1.DefaultSchedulerName] = true
+	}
+	return names
+}
+
+// podBackoff returns the initial and the max backoff of the Pods in cfg.
+func podBackoff(cfg *configv1.KubeSchedulerConfiguration) (time.Duration, time.Duration) {
+	initialBackoff, maxBackoff := defaultPodInitialBackoff, defaultPodMaxBackoff
+	if cfg != nil && cfg.PodInitialBackoffSeconds != nil {
+		initialBackoff = time.Duration(*cfg.PodInitialBackoffSeconds) * time.Second
+	}
+	if cfg != nil && cfg.PodMaxBackoffSeconds != nil {
+		maxBackoff = time.Duration(*cfg.PodMaxBackoffSeconds) * time.Second
+	}
+	return initialBackoff, maxBackoff
+}
+
+// backoffDuration returns the backoff after the attempts, in the same way as the scheduling queue of the scheduler:
+// it's doubled every attempt from initialBackoff, up to maxBackoff.
+func backoffDuration(attempts int32, initialBackoff, maxBackoff time.Duration) time.Duration {
+	d := initialBackoff
+	for i := int32(1); i < attempts; i++ {
+		if d > maxBackoff-d {
+			return maxBackoff
+		}
+		d += d
+	}
+	return d
+}
+
+// sortQueuedPods sorts the pods in the order of the PrioritySort plugin, i.e., the higher priority first,
+// and the older one first among the same priority.
+func sortQueuedPods(pods []QueuedPod) {
+	sort.SliceStable(pods, func(i, j int) bool {
+		if pods[i].Priority != pods[j].Priority {
+			return pods[i].Priority > pods[j].Priority
+		}
+		if !pods[i].CreationTimestamp.Equal(&pods[j].CreationTimestamp) {
+			return pods[i].CreationTimestamp.Before(&pods[j].CreationTimestamp)
+		}
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+}
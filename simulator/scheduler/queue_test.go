@@ -0,0 +1,184 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestService_SchedulingQueue(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) metav1.Time { return metav1.NewTime(now.Add(-d)) }
+	newPod := func(name string, created metav1.Time, mutate func(*v1.Pod)) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name), CreationTimestamp: created},
+			Spec:       v1.PodSpec{SchedulerName: v1.DefaultSchedulerName},
+			Status:     v1.PodStatus{Phase: v1.PodPending},
+		}
+		if mutate != nil {
+			mutate(pod)
+		}
+		return pod
+	}
+	failed := func(reason string, at metav1.Time) func(*v1.Pod) {
+		return func(pod *v1.Pod) {
+			pod.Status.Conditions = []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: reason, Message: "condition message", LastTransitionTime: at},
+			}
+		}
+	}
+	newEvent := func(pod string, count int32, message string, at metav1.Time) *v1.Event {
+		return &v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: pod + "." + message, Namespace: "default"},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "default", Name: pod, UID: types.UID(pod)},
+			Reason:         failedSchedulingReason,
+			Message:        message,
+			Count:          count,
+			LastTimestamp:  at,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		cfg     *configv1.KubeSchedulerConfiguration
+		objects []runtime.Object
+		want    *SchedulingQueue
+	}{
+		{
+			name: "the pods are grouped by the queue with the attempts and the last failure",
+			objects: []runtime.Object{
+				newPod("new", ago(time.Minute), nil),
+				newPod("unschedulable", ago(time.Hour), failed(v1.PodReasonUnschedulable, ago(50*time.Minute))),
+				newPod("backoff", ago(time.Hour), failed(v1.PodReasonSchedulerError, ago(time.Hour))),
+				newPod("backoff-expired", ago(time.Hour), failed(v1.PodReasonSchedulerError, ago(time.Hour))),
+				newPod("bound", ago(time.Hour), func(pod *v1.Pod) { pod.Spec.NodeName = "node-1" }),
+				newPod("other-scheduler", ago(time.Hour), func(pod *v1.Pod) { pod.Spec.SchedulerName = "other-scheduler" }),
+				newEvent("unschedulable", 2, "0/1 nodes are available: 1 Insufficient cpu.", ago(40*time.Minute)),
+				newEvent("unschedulable", 1, "0/1 nodes are available: 1 Insufficient memory.", ago(30*time.Minute)),
+				// The backoff after the 2nd attempt is 2s.
+				newEvent("backoff", 2, "error", ago(time.Second)),
+				newEvent("backoff-expired", 2, "error", ago(3*time.Second)),
+			},
+			want: &SchedulingQueue{
+				ActiveQ: []QueuedPod{
+					{
+						Namespace: "default", Name: "backoff-expired", SchedulerName: v1.DefaultSchedulerName, CreationTimestamp: ago(time.Hour),
+						Attempts: 2, LastFailureReason: v1.PodReasonSchedulerError, LastFailureMessage: "error", LastFailureTime: ptr.To(ago(3 * time.Second)),
+					},
+					{Namespace: "default", Name: "new", SchedulerName: v1.DefaultSchedulerName, CreationTimestamp: ago(time.Minute)},
+				},
+				BackoffQ: []QueuedPod{
+					{
+						Namespace: "default", Name: "backoff", SchedulerName: v1.DefaultSchedulerName, CreationTimestamp: ago(time.Hour),
+						Attempts: 2, LastFailureReason: v1.PodReasonSchedulerError, LastFailureMessage: "error", LastFailureTime: ptr.To(ago(time.Second)),
+						BackoffExpiration: ptr.To(metav1.NewTime(now.Add(time.Second))),
+					},
+				},
+				UnschedulablePods: []QueuedPod{
+					{
+						Namespace: "default", Name: "unschedulable", SchedulerName: v1.DefaultSchedulerName, CreationTimestamp: ago(time.Hour),
+						Attempts: 3, LastFailureReason: v1.PodReasonUnschedulable, LastFailureMessage: "0/1 nodes are available: 1 Insufficient memory.", LastFailureTime: ptr.To(ago(30 * time.Minute)),
+					},
+				},
+			},
+		},
+		{
+			name: "the pods are sorted by the priority and the creation timestamp, and the condition is used without the events",
+			objects: []runtime.Object{
+				newPod("old", ago(time.Hour), nil),
+				newPod("new", ago(time.Minute), nil),
+				newPod("high-priority", ago(time.Second), func(pod *v1.Pod) { pod.Spec.Priority = ptr.To[int32](100) }),
+				newPod("no-events", ago(time.Hour), failed(v1.PodReasonUnschedulable, ago(time.Minute))),
+			},
+			want: &SchedulingQueue{
+				ActiveQ: []QueuedPod{
+					{Namespace: "default", Name: "high-priority", SchedulerName: v1.DefaultSchedulerName, Priority: 100, CreationTimestamp: ago(time.Second)},
+					{Namespace: "default", Name: "old", SchedulerName: v1.DefaultSchedulerName, CreationTimestamp: ago(time.Hour)},
+					{Namespace: "default", Name: "new", SchedulerName: v1.DefaultSchedulerName, CreationTimestamp: ago(time.Minute)},
+				},
+				BackoffQ: []QueuedPod{},
+				UnschedulablePods: []QueuedPod{
+					{
+						Namespace: "default", Name: "no-events", SchedulerName: v1.DefaultSchedulerName, CreationTimestamp: ago(time.Hour),
+						Attempts: 1, LastFailureReason: v1.PodReasonUnschedulable, LastFailureMessage: "condition message", LastFailureTime: ptr.To(ago(time.Minute)),
+					},
+				},
+			},
+		},
+		{
+			name: "the scheduler names and the backoff are taken from the config",
+			cfg: &configv1.KubeSchedulerConfiguration{
+				PodInitialBackoffSeconds: ptr.To[int64](10),
+				PodMaxBackoffSeconds:     ptr.To[int64](15),
+				Profiles:                 []configv1.KubeSchedulerProfile{{SchedulerName: ptr.To("my-scheduler")}},
+			},
+			objects: []runtime.Object{
+				newPod("default-scheduler", ago(time.Hour), nil),
+				newPod("backoff", ago(time.Hour), func(pod *v1.Pod) {
+					pod.Spec.SchedulerName = "my-scheduler"
+					failed(v1.PodReasonSchedulerError, ago(time.Hour))(pod)
+				}),
+				// The backoff after the 3rd attempt is capped at 15s.
+				newEvent("backoff", 3, "error", ago(10*time.Second)),
+			},
+			want: &SchedulingQueue{
+				ActiveQ: []QueuedPod{},
+				BackoffQ: []QueuedPod{
+					{
+						Namespace: "default", Name: "backoff", SchedulerName: "my-scheduler", CreationTimestamp: ago(time.Hour),
+						Attempts: 3, LastFailureReason: v1.PodReasonSchedulerError, LastFailureMessage: "error", LastFailureTime: ptr.To(ago(10 * time.Second)),
+						BackoffExpiration: ptr.To(metav1.NewTime(now.Add(5 * time.Second))),
+					},
+				},
+				UnschedulablePods: []QueuedPod{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := NewSchedulerService(fake.NewSimpleClientset(tt.objects...), nil, nil, "", nil, nil)
+			s.now = func() time.Time { return now }
+			if tt.cfg != nil {
+				s.SetSchedulerConfig(tt.cfg)
+			}
+
+			got, err := s.SchedulingQueue(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_backoffDuration(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 4, want: 8 * time.Second},
+		{attempts: 5, want: 10 * time.Second},
+		{attempts: 100, want: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.want.String(), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, backoffDuration(tt.attempts, defaultPodInitialBackoff, defaultPodMaxBackoff))
+		})
+	}
+}
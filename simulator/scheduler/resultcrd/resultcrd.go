@@ -0,0 +1,163 @@
+// Package resultcrd writes the scheduling results to SchedulingResult custom resources
+// instead of the Pod's annotations.
+package resultcrd
+
+import (
+	"context"
+	_ "embed"
+	"time"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Group is the API group of SchedulingResult.
+	Group = "kube-scheduler-simulator.sigs.k8s.io"
+	// Version is the API version of SchedulingResult.
+	Version = "v1alpha1"
+	// Kind is the kind of SchedulingResult.
+	Kind = "SchedulingResult"
+	// DefaultHistoryLimit is the default number of the scheduling attempts kept in a SchedulingResult.
+	DefaultHistoryLimit = 10
+)
+
+// GVR is the GroupVersionResource of SchedulingResult.
+var GVR = schema.GroupVersionResource{Group: Group, Version: Version, Resource: "schedulingresults"}
+
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+//go:embed schedulingresult-crd.yaml
+var crdManifest []byte
+
+// EnsureCRD creates the CustomResourceDefinition of SchedulingResult if it doesn't exist.
+func EnsureCRD(ctx context.Context, client dynamic.Interface) error {
+	crd := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(crdManifest, &crd.Object); err != nil {
+		return xerrors.Errorf("decode the CRD manifest: %w", err)
+	}
+
+	_, err := client.Resource(crdGVR).Get(ctx, crd.GetName(), metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return xerrors.Errorf("get the CRD %s: %w", crd.GetName(), err)
+	}
+
+	klog.Info("create the CRD " + crd.GetName())
+	if _, err := client.Resource(crdGVR).Create(ctx, crd, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return xerrors.Errorf("create the CRD %s: %w", crd.GetName(), err)
+	}
+	return nil
+}
+
+// Writer writes the results of each scheduling attempt to the SchedulingResult which has the same name as the Pod.
+// It implements storereflector.ResultWriter.
+type Writer struct {
+	client dynamic.Interface
+	// historyLimit is the number of the scheduling attempts kept in a SchedulingResult.
+	historyLimit int
+	now          func() time.Time
+}
+
+// NewWriter creates Writer. historyLimit less than 1 means DefaultHistoryLimit.
+func NewWriter(client dynamic.Interface, historyLimit int) *Writer {
+	if historyLimit < 1 {
+		historyLimit = DefaultHistoryLimit
+	}
+	return &Writer{
+		client:       client,
+		historyLimit: historyLimit,
+		now:          time.Now,
+	}
+}
+
+// Write appends the results to the history of the pod's SchedulingResult, creating it if it doesn't exist.
+// The SchedulingResult is owned by the pod so that it's garbage collected with the pod.
+func (w *Writer) Write(ctx context.Context, pod *corev1.Pod, results map[string]string) error {
+	entry := map[string]interface{}{
+		"timestamp": w.now().UTC().Format(time.RFC3339),
+		"results":   toInterfaceMap(results),
+	}
+
+	obj, err := w.client.Resource(GVR).Namespace(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return xerrors.Errorf("get SchedulingResult: %w", err)
+		}
+		obj = newSchedulingResult(pod)
+		if err := unstructured.SetNestedSlice(obj.Object, []interface{}{entry}, "spec", "history"); err != nil {
+			return xerrors.Errorf("set history: %w", err)
+		}
+		if _, err := w.client.Resource(GVR).Namespace(pod.Namespace).Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				// Someone created it concurrently. Return it as a conflict so that it's retried.
+				return apierrors.NewConflict(GVR.GroupResource(), pod.Name, err)
+			}
+			return xerrors.Errorf("create SchedulingResult: %w", err)
+		}
+		return nil
+	}
+
+	history, _, err := unstructured.NestedSlice(obj.Object, "spec", "history")
+	if err != nil {
+		return xerrors.Errorf("get history: %w", err)
+	}
+	if uid, _, _ := unstructured.NestedString(obj.Object, "spec", "podUID"); uid != string(pod.UID) {
+		// The pod has been re-created with the same name; the past results are for another pod.
+		fresh := newSchedulingResult(pod)
+		fresh.SetResourceVersion(obj.GetResourceVersion())
+		obj = fresh
+		history = nil
+	}
+
+	history = append(history, entry)
+	if len(history) > w.historyLimit {
+		// drop the oldest ones since the newer ones are likely more important.
+		history = history[len(history)-w.historyLimit:]
+	}
+	if err := unstructured.SetNestedSlice(obj.Object, history, "spec", "history"); err != nil {
+		return xerrors.Errorf("set history: %w", err)
+	}
+
+	if _, err := w.client.Resource(GVR).Namespace(pod.Namespace).Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+		return xerrors.Errorf("update SchedulingResult: %w", err)
+	}
+	return nil
+}
+
+// newSchedulingResult returns the SchedulingResult for pod without any history.
+func newSchedulingResult(pod *corev1.Pod) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(GVR.GroupVersion().String())
+	obj.SetKind(Kind)
+	obj.SetName(pod.Name)
+	obj.SetNamespace(pod.Namespace)
+	obj.SetOwnerReferences([]metav1.OwnerReference{
+		{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Name:       pod.Name,
+			UID:        pod.UID,
+		},
+	})
+	//nolint:errcheck // it never fails with a string.
+	unstructured.SetNestedField(obj.Object, string(pod.UID), "spec", "podUID")
+	return obj
+}
+
+func toInterfaceMap(m map[string]string) map[string]interface{} {
+	ret := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		ret[k] = v
+	}
+	return ret
+}
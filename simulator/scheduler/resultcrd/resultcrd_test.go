@@ -0,0 +1,117 @@
+package resultcrd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestWriter_Write(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default", UID: "uid1"}}
+
+	tests := []struct {
+		name         string
+		historyLimit int
+		// prepareFn writes the results of the previous attempts.
+		prepareFn   func(t *testing.T, w *Writer)
+		wantHistory []string
+	}{
+		{
+			name:         "create SchedulingResult on the first attempt",
+			historyLimit: 3,
+			prepareFn:    func(_ *testing.T, _ *Writer) {},
+			wantHistory:  []string{"result-latest"},
+		},
+		{
+			name:         "append the results to the history",
+			historyLimit: 3,
+			prepareFn: func(t *testing.T, w *Writer) {
+				t.Helper()
+				require.NoError(t, w.Write(context.Background(), pod, map[string]string{"key": "result0"}))
+			},
+			wantHistory: []string{"result0", "result-latest"},
+		},
+		{
+			name:         "drop the oldest results when the history exceeds the limit",
+			historyLimit: 3,
+			prepareFn: func(t *testing.T, w *Writer) {
+				t.Helper()
+				for i := 0; i < 5; i++ {
+					require.NoError(t, w.Write(context.Background(), pod, map[string]string{"key": fmt.Sprintf("result%d", i)}))
+				}
+			},
+			wantHistory: []string{"result3", "result4", "result-latest"},
+		},
+		{
+			name:         "reset the history when the pod is re-created",
+			historyLimit: 3,
+			prepareFn: func(t *testing.T, w *Writer) {
+				t.Helper()
+				old := pod.DeepCopy()
+				old.UID = "uid0"
+				require.NoError(t, w.Write(context.Background(), old, map[string]string{"key": "result0"}))
+			},
+			wantHistory: []string{"result-latest"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{GVR: Kind + "List"})
+			w := NewWriter(client, tt.historyLimit)
+			w.now = func() time.Time { return timestamp }
+			tt.prepareFn(t, w)
+
+			require.NoError(t, w.Write(context.Background(), pod, map[string]string{"key": "result-latest"}))
+
+			got, err := client.Resource(GVR).Namespace(pod.Namespace).Get(context.Background(), pod.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			uid, _, _ := unstructured.NestedString(got.Object, "spec", "podUID")
+			assert.Equal(t, string(pod.UID), uid)
+			require.Len(t, got.GetOwnerReferences(), 1)
+			assert.Equal(t, pod.UID, got.GetOwnerReferences()[0].UID)
+
+			history, _, err := unstructured.NestedSlice(got.Object, "spec", "history")
+			require.NoError(t, err)
+			gotHistory := make([]string, 0, len(history))
+			for _, h := range history {
+				entry, ok := h.(map[string]interface{})
+				require.True(t, ok)
+				assert.Equal(t, timestamp.Format(time.RFC3339), entry["timestamp"])
+				result, _, _ := unstructured.NestedString(entry, "results", "key")
+				gotHistory = append(gotHistory, result)
+			}
+			assert.Equal(t, tt.wantHistory, gotHistory)
+		})
+	}
+}
+
+func TestEnsureCRD(t *testing.T) {
+	t.Parallel()
+
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{crdGVR: "CustomResourceDefinitionList"})
+	require.NoError(t, EnsureCRD(context.Background(), client))
+	// It does nothing if the CRD already exists.
+	require.NoError(t, EnsureCRD(context.Background(), client))
+
+	got, err := client.Resource(crdGVR).Get(context.Background(), "schedulingresults."+Group, metav1.GetOptions{})
+	require.NoError(t, err)
+	kind, _, _ := unstructured.NestedString(got.Object, "spec", "names", "kind")
+	assert.Equal(t, Kind, kind)
+	scope, _, _ := unstructured.NestedString(got.Object, "spec", "scope")
+	assert.Equal(t, "Namespaced", scope)
+}
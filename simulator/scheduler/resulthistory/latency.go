@@ -0,0 +1,165 @@
+package resulthistory
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/annotation"
+)
+
+// LatencyQuery filters the Pods whose latencies are aggregated.
+type LatencyQuery struct {
+	// Namespace and SchedulerName restrict the Pods to the ones with them if non-empty.
+	Namespace     string
+	SchedulerName string
+}
+
+// LatencyStats is the percentiles of the latencies of the Pods kept in Store.
+type LatencyStats struct {
+	// SchedulingLatency is the time from the creation of the Pods to their binding, of the bound Pods.
+	SchedulingLatency Percentiles `json:"schedulingLatency"`
+	// AttemptDuration is the total time the plugins took in each scheduling attempt.
+	AttemptDuration Percentiles `json:"attemptDuration"`
+}
+
+// Percentiles is the distribution of the durations, in nanoseconds in JSON.
+// The percentiles are computed with the nearest-rank method, and they're all zero if Count is zero.
+type Percentiles struct {
+	Count int           `json:"count"`
+	Mean  time.Duration `json:"mean"`
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	P95   time.Duration `json:"p95"`
+	P99   time.Duration `json:"p99"`
+	Max   time.Duration `json:"max"`
+}
+
+// Latency returns the percentiles of the latencies of the Pods matching q.
+// Only the Pods still kept in Store are aggregated.
+func (s *Store) Latency(q LatencyQuery) *LatencyStats {
+	var latencies, durations []time.Duration
+	s.mu.Lock()
+	for e := s.lru.Front(); e != nil; e = e.Next() {
+		h := e.Value.(*podHistory)
+		if (q.Namespace != "" && h.namespace != q.Namespace) || (q.SchedulerName != "" && h.schedulerName != q.SchedulerName) {
+			continue
+		}
+		if !h.bound.IsZero() {
+			latencies = append(latencies, h.latency())
+		}
+		for _, entry := range h.entries {
+			if entry.AttemptDuration > 0 {
+				durations = append(durations, entry.AttemptDuration)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	return &LatencyStats{SchedulingLatency: percentiles(latencies), AttemptDuration: percentiles(durations)}
+}
+
+// RecordBinding records that the pod is bound to the node at the time.
+// The binding recorded first is kept.
+func (s *Store) RecordBinding(pod *corev1.Pod, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := s.podHistory(pod)
+	if h.bound.IsZero() {
+		h.bound = at
+	}
+	s.evict()
+}
+
+// recordBindingFromUpdate records the binding of the Pod when its nodeName is set by the update.
+func (s *Store) recordBindingFromUpdate(oldObj, newObj interface{}) {
+	oldPod, ok := oldObj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	pod, ok := newObj.(*corev1.Pod)
+	if !ok || oldPod.Spec.NodeName != "" || pod.Spec.NodeName == "" {
+		return
+	}
+	s.RecordBinding(pod, s.now())
+}
+
+// latency returns the time from the creation of the Pod to its binding, or zero if it isn't bound yet.
+func (h *podHistory) latency() time.Duration {
+	if h.bound.IsZero() || h.created.IsZero() || h.bound.Before(h.created) {
+		return 0
+	}
+	return h.bound.Sub(h.created)
+}
+
+// boundTime returns when pod becomes PodScheduled, or now if it's unknown.
+func boundTime(pod *corev1.Pod, now time.Time) time.Time {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionTrue && !c.LastTransitionTime.IsZero() {
+			return c.LastTransitionTime.Time
+		}
+	}
+	return now
+}
+
+// attemptDuration returns the total time the plugins took in the attempt of results.
+func attemptDuration(results map[string]string) time.Duration {
+	v, ok := results[annotation.PluginDurationAnnotationKey]
+	if !ok {
+		return 0
+	}
+	// extension point → plugin name → duration
+	durations := map[string]map[string]string{}
+	if err := json.Unmarshal([]byte(v), &durations); err != nil {
+		klog.ErrorS(err, "cannot decode "+annotation.PluginDurationAnnotationKey)
+		return 0
+	}
+	var total time.Duration
+	for _, plugins := range durations {
+		for _, d := range plugins {
+			parsed, err := time.ParseDuration(d)
+			if err != nil {
+				klog.ErrorS(err, "cannot parse the duration of the plugin", "duration", d)
+				continue
+			}
+			total += parsed
+		}
+	}
+	return total
+}
+
+// percentiles returns the distribution of durations.
+func percentiles(durations []time.Duration) Percentiles {
+	if len(durations) == 0 {
+		return Percentiles{}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	var sum time.Duration
+	for _, d := range durations {
+		sum += d
+	}
+	return Percentiles{
+		Count: len(durations),
+		Mean:  sum / time.Duration(len(durations)),
+		P50:   percentile(durations, 50),
+		P90:   percentile(durations, 90),
+		P95:   percentile(durations, 95),
+		P99:   percentile(durations, 99),
+		Max:   durations[len(durations)-1],
+	}
+}
+
+// percentile returns the p-th percentile of sorted with the nearest-rank method,
+// i.e., the smallest value which p percent of the values are less than or equal to.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,168 @@
+package resulthistory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/annotation"
+)
+
+func TestStore_Latency(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := New(0, 0)
+	// The pods 1..10 are bound 1..10 seconds after they're created.
+	for i := 1; i <= 10; i++ {
+		p := pod("default", fmt.Sprintf("pod%d", i))
+		p.CreationTimestamp = metav1.NewTime(created)
+		p.Spec.SchedulerName = "default-scheduler"
+		s.Record(p, durationResults(time.Duration(i)*time.Millisecond, time.Duration(i)*time.Millisecond))
+		s.RecordBinding(p, created.Add(time.Duration(i)*time.Second))
+	}
+	// The pod of the secondary scheduler isn't bound yet.
+	secondary := pod("kube-system", "secondary")
+	secondary.CreationTimestamp = metav1.NewTime(created)
+	secondary.Spec.SchedulerName = "secondary"
+	s.Record(secondary, durationResults(100*time.Millisecond))
+	s.Record(secondary, results(""))
+
+	tests := []struct {
+		name  string
+		query LatencyQuery
+		want  *LatencyStats
+	}{
+		{
+			name:  "all",
+			query: LatencyQuery{},
+			want: &LatencyStats{
+				SchedulingLatency: Percentiles{Count: 10, Mean: 5500 * time.Millisecond, P50: 5 * time.Second, P90: 9 * time.Second, P95: 10 * time.Second, P99: 10 * time.Second, Max: 10 * time.Second},
+				// 2, 4, ..., 20ms and 100ms. The attempt without the durations isn't aggregated.
+				AttemptDuration: Percentiles{Count: 11, Mean: 210 * time.Millisecond / 11, P50: 12 * time.Millisecond, P90: 20 * time.Millisecond, P95: 100 * time.Millisecond, P99: 100 * time.Millisecond, Max: 100 * time.Millisecond},
+			},
+		},
+		{
+			name:  "filter by the scheduler name",
+			query: LatencyQuery{SchedulerName: "secondary"},
+			want: &LatencyStats{
+				AttemptDuration: Percentiles{Count: 1, Mean: 100 * time.Millisecond, P50: 100 * time.Millisecond, P90: 100 * time.Millisecond, P95: 100 * time.Millisecond, P99: 100 * time.Millisecond, Max: 100 * time.Millisecond},
+			},
+		},
+		{
+			name:  "filter by the namespace",
+			query: LatencyQuery{Namespace: "no-pods"},
+			want:  &LatencyStats{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, s.Latency(tt.query))
+		})
+	}
+}
+
+func TestStore_Query_latency(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := New(0, 0)
+	p := pod("default", "pod1")
+	p.CreationTimestamp = metav1.NewTime(created)
+	s.Record(p, durationResults(3*time.Millisecond))
+
+	got, err := s.Query(Query{})
+	require.NoError(t, err)
+	require.Len(t, got.Items, 1)
+	assert.Equal(t, 3*time.Millisecond, got.Items[0].AttemptDuration)
+	assert.Zero(t, got.Items[0].SchedulingLatency, "the pod isn't bound yet")
+
+	// The binding observed later is set to the entries recorded before.
+	s.RecordBinding(p, created.Add(1500*time.Millisecond))
+	// The second binding is ignored.
+	s.RecordBinding(p, created.Add(time.Hour))
+	got, err = s.Query(Query{})
+	require.NoError(t, err)
+	require.Len(t, got.Items, 1)
+	assert.Equal(t, 1500*time.Millisecond, got.Items[0].SchedulingLatency)
+
+	// The pod bound before its results are recorded takes the time from the PodScheduled condition.
+	bound := pod("default", "pod2")
+	bound.CreationTimestamp = metav1.NewTime(created)
+	bound.Spec.NodeName = "node1"
+	bound.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(2 * time.Second))}}
+	s.Record(bound, results("node1"))
+	got, err = s.Query(Query{Pod: "pod2"})
+	require.NoError(t, err)
+	require.Len(t, got.Items, 1)
+	assert.Equal(t, 2*time.Second, got.Items[0].SchedulingLatency)
+}
+
+func TestStore_RegisterRecordingToInformer_binding(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := fake.NewSimpleClientset()
+	s := New(0, 0)
+	s.now = func() time.Time { return created.Add(4 * time.Second) }
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, s.RegisterRecordingToInformer(client, ctx.Done()))
+
+	p := pod("default", "pod1")
+	p.CreationTimestamp = metav1.NewTime(created)
+	_, err := client.CoreV1().Pods("default").Create(ctx, p, metav1.CreateOptions{})
+	require.NoError(t, err)
+	p.Spec.NodeName = "node1"
+	_, err = client.CoreV1().Pods("default").Update(ctx, p, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return s.Latency(LatencyQuery{}).SchedulingLatency.Count == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 4*time.Second, s.Latency(LatencyQuery{}).SchedulingLatency.Max)
+}
+
+func Test_percentile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{name: "one value", sorted: []time.Duration{5}, p: 99, want: 5},
+		{name: "median of the even number of values is the lower one", sorted: []time.Duration{1, 2, 3, 4}, p: 50, want: 2},
+		{name: "median of the odd number of values", sorted: []time.Duration{1, 2, 3, 4, 5}, p: 50, want: 3},
+		{name: "p90 of 20 values", sorted: []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}, p: 90, want: 18},
+		{name: "p0 is the minimum", sorted: []time.Duration{1, 2, 3}, p: 0, want: 1},
+		{name: "p100 is the maximum", sorted: []time.Duration{1, 2, 3}, p: 100, want: 3},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, percentile(tt.sorted, tt.p))
+		})
+	}
+}
+
+// durationResults returns the results of an attempt in which the plugins took durations.
+func durationResults(durations ...time.Duration) map[string]string {
+	r := results("")
+	plugins := ""
+	for i, d := range durations {
+		if i > 0 {
+			plugins += ","
+		}
+		plugins += fmt.Sprintf(`"Plugin%d":%q`, i, d.String())
+	}
+	r[annotation.PluginDurationAnnotationKey] = `{"Filter":{` + plugins + `}}`
+	return r
+}
@@ -0,0 +1,311 @@
+// Package resulthistory keeps the scheduling results in memory, so that they can be queried
+// without parsing the Pods' annotations, even after the Pods are deleted.
+package resulthistory
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/annotation"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/storereflector"
+)
+
+const (
+	// DefaultPodCapacity is the default number of the Pods whose results are kept.
+	DefaultPodCapacity = 1000
+	// DefaultHistoryLimit is the default number of the scheduling attempts kept for each Pod.
+	DefaultHistoryLimit = 10
+	// DefaultQueryLimit is the number of the entries returned by Query when Query.Limit is zero.
+	DefaultQueryLimit = 100
+	// MaxQueryLimit is the maximum of Query.Limit.
+	MaxQueryLimit = 1000
+)
+
+// ErrInvalidQuery is returned when the given Query is invalid.
+var ErrInvalidQuery = errors.New("invalid scheduling results query")
+
+// Entry is the results of a scheduling attempt of a Pod.
+type Entry struct {
+	// ID increases in the order the entries are recorded. It's used for the pagination.
+	ID        int64     `json:"id"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	PodUID    types.UID `json:"podUID"`
+	// SchedulerName is the schedulerName of the Pod, which tells the scheduler the results come from
+	// when the secondary schedulers run side by side with the primary one.
+	SchedulerName string `json:"schedulerName,omitempty"`
+	// Node is the node selected in the attempt, empty if no node is selected.
+	Node      string    `json:"node,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	// AttemptDuration is the total time the plugins took in the attempt, in nanoseconds in JSON.
+	// It's zero if the scheduler doesn't record the durations of the plugins.
+	AttemptDuration time.Duration `json:"attemptDuration,omitempty"`
+	// SchedulingLatency is the time from the creation of the Pod to its binding, in nanoseconds in JSON.
+	// It's set to all the entries of the Pod once the Pod is bound.
+	SchedulingLatency time.Duration `json:"schedulingLatency,omitempty"`
+	// RandomSeed is the seed of the random numbers the scheduler runs with, which is set only when the scheduler is deterministic.
+	// The same Pods are scheduled to the same Nodes with the same seed, e.g., when the scenario is replayed.
+	RandomSeed *int64            `json:"randomSeed,omitempty"`
+	Results    map[string]string `json:"results"`
+}
+
+// Query filters and paginates the entries.
+type Query struct {
+	// Namespace, Pod, Node and SchedulerName restrict the entries to the ones with them if non-empty.
+	Namespace     string
+	Pod           string
+	Node          string
+	SchedulerName string
+	// Since restricts the entries to the ones recorded at or after it if non-zero.
+	Since time.Time
+	// Limit is the maximum number of the entries returned. DefaultQueryLimit is used if zero.
+	Limit int
+	// Continue is QueryResult.Continue of the previous query to get the next page.
+	Continue string
+}
+
+// QueryResult is a page of the entries matching Query, in the order they are recorded.
+type QueryResult struct {
+	Items []Entry `json:"items"`
+	// Continue is set when there are more entries. Pass it as Query.Continue to get them.
+	Continue string `json:"continue,omitempty"`
+}
+
+// podHistory is the entries of a Pod, the element of the LRU list.
+type podHistory struct {
+	uid     types.UID
+	entries []Entry
+	// namespace and schedulerName are the ones of the Pod, which the latencies are filtered by.
+	namespace     string
+	schedulerName string
+	// created and bound are when the Pod is created and bound. bound is zero until the binding is observed.
+	created time.Time
+	bound   time.Time
+}
+
+// Store keeps the results of the latest scheduling attempts of the Pods recently scheduled.
+// When it has more Pods than the capacity, the results of the least recently scheduled Pod are evicted.
+// It implements storereflector.ResultRecorder.
+type Store struct {
+	mu           sync.Mutex
+	podCapacity  int
+	historyLimit int
+	// lru has *podHistory, the most recently scheduled one at the front.
+	lru    *list.List
+	pods   map[types.UID]*list.Element
+	lastID int64
+	now    func() time.Time
+	// randomSeed is set to the entries recorded.
+	randomSeed *int64
+}
+
+// Option configures Store.
+type Option func(*Store)
+
+// WithRandomSeed makes Store record seed as the random seed of the scheduler with the entries.
+// It's nil if the scheduler isn't deterministic.
+func WithRandomSeed(seed *int64) Option {
+	return func(s *Store) {
+		s.randomSeed = seed
+	}
+}
+
+var _ storereflector.ResultRecorder = &Store{}
+
+// New creates Store. Non-positive podCapacity and historyLimit mean DefaultPodCapacity and DefaultHistoryLimit.
+func New(podCapacity, historyLimit int, opts ...Option) *Store {
+	if podCapacity <= 0 {
+		podCapacity = DefaultPodCapacity
+	}
+	if historyLimit <= 0 {
+		historyLimit = DefaultHistoryLimit
+	}
+	s := &Store{
+		podCapacity:  podCapacity,
+		historyLimit: historyLimit,
+		lru:          list.New(),
+		pods:         map[types.UID]*list.Element{},
+		now:          time.Now,
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// Record records the results of the latest scheduling attempt of the pod.
+func (s *Store) Record(pod *corev1.Pod, results map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	entry := Entry{
+		ID:              s.lastID,
+		Namespace:       pod.Namespace,
+		Pod:             pod.Name,
+		PodUID:          pod.UID,
+		SchedulerName:   pod.Spec.SchedulerName,
+		Node:            results[annotation.SelectedNodeAnnotationKey],
+		Timestamp:       s.now(),
+		AttemptDuration: attemptDuration(results),
+		RandomSeed:      s.randomSeed,
+		Results:         make(map[string]string, len(results)),
+	}
+	for k, v := range results {
+		entry.Results[k] = v
+	}
+
+	h := s.podHistory(pod)
+	s.lru.MoveToFront(s.pods[pod.UID])
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > s.historyLimit {
+		// drop the oldest ones since the newer ones are likely more important.
+		h.entries = h.entries[len(h.entries)-s.historyLimit:]
+	}
+	if h.bound.IsZero() && pod.Spec.NodeName != "" {
+		// The binding is done before the results are recorded, e.g., when the recording starts after the Pod is bound.
+		h.bound = boundTime(pod, s.now())
+	}
+
+	s.evict()
+}
+
+// podHistory returns the history of pod, adding it to the front of the LRU list if it doesn't exist.
+// s.mu must be held.
+func (s *Store) podHistory(pod *corev1.Pod) *podHistory {
+	e, ok := s.pods[pod.UID]
+	if !ok {
+		e = s.lru.PushFront(&podHistory{uid: pod.UID, namespace: pod.Namespace, schedulerName: pod.Spec.SchedulerName, created: pod.CreationTimestamp.Time})
+		s.pods[pod.UID] = e
+	}
+	return e.Value.(*podHistory)
+}
+
+// evict removes the least recently scheduled Pods while there are more Pods than the capacity. s.mu must be held.
+func (s *Store) evict() {
+	for s.lru.Len() > s.podCapacity {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.pods, oldest.Value.(*podHistory).uid)
+	}
+}
+
+// Query returns the entries matching q, in the order they are recorded.
+func (s *Store) Query(q Query) (*QueryResult, error) {
+	limit := q.Limit
+	if limit == 0 {
+		limit = DefaultQueryLimit
+	}
+	if limit < 0 || limit > MaxQueryLimit {
+		return nil, xerrors.Errorf("limit %d out of range (1-%d): %w", q.Limit, MaxQueryLimit, ErrInvalidQuery)
+	}
+	var after int64
+	if q.Continue != "" {
+		var err error
+		after, err = strconv.ParseInt(q.Continue, 10, 64)
+		if err != nil || after < 0 {
+			return nil, xerrors.Errorf("malformed continue token %q: %w", q.Continue, ErrInvalidQuery)
+		}
+	}
+
+	s.mu.Lock()
+	matched := []Entry{}
+	for e := s.lru.Front(); e != nil; e = e.Next() {
+		h := e.Value.(*podHistory)
+		for _, entry := range h.entries {
+			if entry.ID > after && q.matches(&entry) {
+				entry.SchedulingLatency = h.latency()
+				matched = append(matched, entry)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ID < matched[j].ID })
+	result := &QueryResult{Items: matched}
+	if len(matched) > limit {
+		result.Items = matched[:limit]
+		result.Continue = strconv.FormatInt(result.Items[limit-1].ID, 10)
+	}
+	return result, nil
+}
+
+func (q *Query) matches(e *Entry) bool {
+	if q.Namespace != "" && e.Namespace != q.Namespace {
+		return false
+	}
+	if q.Pod != "" && e.Pod != q.Pod {
+		return false
+	}
+	if q.Node != "" && e.Node != q.Node {
+		return false
+	}
+	if q.SchedulerName != "" && e.SchedulerName != q.SchedulerName {
+		return false
+	}
+	return q.Since.IsZero() || !e.Timestamp.Before(q.Since)
+}
+
+// RegisterRecordingToInformer registers the event handler to record the results which the scheduler
+// reflects on the Pods' annotations. It's needed when the scheduler runs in another process,
+// and doesn't record the results to the Store directly.
+// Only the latest entry of storereflector.ResultsHistoryAnnotation is recorded every time it's updated.
+// The bindings of the Pods are recorded as well when their nodeName is observed to be set.
+func (s *Store) RegisterRecordingToInformer(client clientset.Interface, stopCh <-chan struct{}) error {
+	informerFactory := scheduler.NewInformerFactory(client, 0)
+	_, err := informerFactory.Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			s.recordFromAnnotation(nil, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			s.recordBindingFromUpdate(oldObj, newObj)
+			s.recordFromAnnotation(oldObj, newObj)
+		},
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to AddEventHandler of Informer: %w", err)
+	}
+
+	informerFactory.Start(stopCh)
+	informerFactory.WaitForCacheSync(stopCh)
+
+	return nil
+}
+
+func (s *Store) recordFromAnnotation(oldObj, newObj interface{}) {
+	pod, ok := newObj.(*corev1.Pod)
+	if !ok {
+		klog.ErrorS(nil, "Cannot convert to *corev1.Pod", "obj", newObj)
+		return
+	}
+	history, ok := pod.Annotations[storereflector.ResultsHistoryAnnotation]
+	if !ok {
+		return
+	}
+	if oldPod, ok := oldObj.(*corev1.Pod); ok && oldPod.Annotations[storereflector.ResultsHistoryAnnotation] == history {
+		return
+	}
+
+	results := []map[string]string{}
+	if err := json.Unmarshal([]byte(history), &results); err != nil {
+		klog.ErrorS(err, "cannot decode "+storereflector.ResultsHistoryAnnotation, "pod", klog.KObj(pod))
+		return
+	}
+	if len(results) == 0 {
+		return
+	}
+	s.Record(pod, results[len(results)-1])
+}
@@ -0,0 +1,233 @@
+package resulthistory
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/annotation"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/storereflector"
+)
+
+func TestStore_Record(t *testing.T) {
+	t.Parallel()
+	s := New(2, 2)
+
+	s.Record(pod("default", "pod1"), results("node1"))
+	s.Record(pod("default", "pod2"), results("node2"))
+	// pod1 has more attempts than the history limit.
+	s.Record(pod("default", "pod1"), results(""))
+	s.Record(pod("default", "pod1"), results("node3"))
+	// pod2 is the least recently scheduled, and then it's evicted.
+	s.Record(pod("default", "pod3"), results("node1"))
+
+	got, err := s.Query(Query{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default/pod1@", "default/pod1@node3", "default/pod3@node1"}, summarize(got.Items))
+	assert.Equal(t, []int64{3, 4, 5}, ids(got.Items))
+	assert.Empty(t, got.Continue)
+}
+
+func TestStore_Record_randomSeed(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		opts []Option
+		want *int64
+	}{
+		{
+			name: "the seed of the deterministic scheduler",
+			opts: []Option{WithRandomSeed(ptr.To[int64](42))},
+			want: ptr.To[int64](42),
+		},
+		{
+			name: "no seed",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := New(0, 0, tt.opts...)
+			s.Record(pod("default", "pod1"), results("node1"))
+
+			got, err := s.Query(Query{})
+			require.NoError(t, err)
+			require.Len(t, got.Items, 1)
+			assert.Equal(t, tt.want, got.Items[0].RandomSeed)
+		})
+	}
+}
+
+func TestStore_Query(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := New(0, 0)
+	now := start
+	s.now = func() time.Time { return now }
+	s.Record(pod("default", "pod1"), results("node1"))
+	now = now.Add(time.Minute)
+	s.Record(pod("default", "pod2"), results("node2"))
+	now = now.Add(time.Minute)
+	s.Record(pod("kube-system", "pod1"), results("node1"))
+	now = now.Add(time.Minute)
+	s.Record(pod("default", "pod1"), results("node2"))
+	now = now.Add(time.Minute)
+	secondary := pod("default", "pod3")
+	secondary.Spec.SchedulerName = "secondary"
+	s.Record(secondary, results("node1"))
+
+	tests := []struct {
+		name    string
+		query   Query
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "all",
+			want: []string{"default/pod1@node1", "default/pod2@node2", "kube-system/pod1@node1", "default/pod1@node2", "default/pod3@node1"},
+		},
+		{
+			name:  "filter by the namespace",
+			query: Query{Namespace: "kube-system"},
+			want:  []string{"kube-system/pod1@node1"},
+		},
+		{
+			name:  "filter by the pod",
+			query: Query{Namespace: "default", Pod: "pod1"},
+			want:  []string{"default/pod1@node1", "default/pod1@node2"},
+		},
+		{
+			name:  "filter by the node",
+			query: Query{Node: "node2"},
+			want:  []string{"default/pod2@node2", "default/pod1@node2"},
+		},
+		{
+			name:  "filter by the scheduler name",
+			query: Query{SchedulerName: "secondary"},
+			want:  []string{"default/pod3@node1"},
+		},
+		{
+			name:  "filter by the time",
+			query: Query{Since: start.Add(2 * time.Minute)},
+			want:  []string{"kube-system/pod1@node1", "default/pod1@node2", "default/pod3@node1"},
+		},
+		{
+			name:    "negative limit",
+			query:   Query{Limit: -1},
+			wantErr: true,
+		},
+		{
+			name:    "malformed continue token",
+			query:   Query{Continue: "next"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := s.Query(tt.query)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidQuery)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, summarize(got.Items))
+		})
+	}
+}
+
+func TestStore_Query_pagination(t *testing.T) {
+	t.Parallel()
+	s := New(0, 0)
+	for i := 0; i < 5; i++ {
+		s.Record(pod("default", fmt.Sprintf("pod%d", i)), results("node1"))
+	}
+
+	got := []string{}
+	q := Query{Limit: 2}
+	pages := 0
+	for {
+		page, err := s.Query(q)
+		require.NoError(t, err)
+		pages++
+		got = append(got, summarize(page.Items)...)
+		if page.Continue == "" {
+			break
+		}
+		q.Continue = page.Continue
+	}
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{"default/pod0@node1", "default/pod1@node1", "default/pod2@node1", "default/pod3@node1", "default/pod4@node1"}, got)
+}
+
+func TestStore_RegisterRecordingToInformer(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset()
+	s := New(0, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, s.RegisterRecordingToInformer(client, ctx.Done()))
+
+	p := pod("default", "pod1")
+	p.Annotations = map[string]string{storereflector.ResultsHistoryAnnotation: `[{"kube-scheduler-simulator.sigs.k8s.io/selected-node":"node1"}]`}
+	_, err := client.CoreV1().Pods("default").Create(ctx, p, metav1.CreateOptions{})
+	require.NoError(t, err)
+	// The update not changing the results isn't recorded.
+	p.Labels = map[string]string{"foo": "bar"}
+	_, err = client.CoreV1().Pods("default").Update(ctx, p, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	p.Annotations[storereflector.ResultsHistoryAnnotation] = `[{"kube-scheduler-simulator.sigs.k8s.io/selected-node":"node1"},{"kube-scheduler-simulator.sigs.k8s.io/selected-node":"node2"}]`
+	_, err = client.CoreV1().Pods("default").Update(ctx, p, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		got, err := s.Query(Query{})
+		return err == nil && len(got.Items) == 2
+	}, wait.ForeverTestTimeout, 10*time.Millisecond)
+	// The results are kept after the Pod is deleted.
+	require.NoError(t, client.CoreV1().Pods("default").Delete(ctx, "pod1", metav1.DeleteOptions{}))
+	got, err := s.Query(Query{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default/pod1@node1", "default/pod1@node2"}, summarize(got.Items))
+}
+
+func pod(namespace, name string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(namespace + "/" + name)}}
+}
+
+func results(node string) map[string]string {
+	r := map[string]string{annotation.FilterResultAnnotationKey: "{}"}
+	if node != "" {
+		r[annotation.SelectedNodeAnnotationKey] = node
+	}
+	return r
+}
+
+// summarize returns "<namespace>/<pod>@<node>" of the entries.
+func summarize(entries []Entry) []string {
+	ret := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ret = append(ret, e.Namespace+"/"+e.Pod+"@"+e.Node)
+	}
+	return ret
+}
+
+func ids(entries []Entry) []int64 {
+	ret := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		ret = append(ret, e.ID)
+	}
+	return ret
+}
@@ -3,11 +3,16 @@ package scheduler
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
+	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
 	"golang.org/x/xerrors"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	clientset "k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
@@ -17,7 +22,6 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/apis/config/scheme"
 	apiconfigv1 "k8s.io/kubernetes/pkg/scheduler/apis/config/v1"
 
-	simulatorconfig "sigs.k8s.io/kube-scheduler-simulator/simulator/config"
 	simulatorschedconfig "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/config"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/storereflector"
@@ -27,6 +31,23 @@ import (
 type Service struct {
 	// function to shutdown scheduler.
 	shutdownfn func()
+	// restartfn restarts the scheduler with the config. It returns an error if the scheduler fails to start.
+	restartfn func(ctx context.Context, cfg *configv1.KubeSchedulerConfiguration) error
+	// startSecondaryfn starts the secondary scheduler with the name and the config.
+	startSecondaryfn func(ctx context.Context, name string, cfg *configv1.KubeSchedulerConfiguration) error
+	// stopSecondaryfn stops the secondary scheduler with the name.
+	stopSecondaryfn func(ctx context.Context, name string) error
+
+	// applyMu serializes applying the configs so that the rollback restores the config actually running.
+	applyMu sync.Mutex
+	// mu protects currentSchedulerCfg and generation.
+	mu sync.RWMutex
+	// generation is incremented every time a config is applied successfully.
+	generation int64
+	// secondaryMu protects secondaries.
+	secondaryMu sync.Mutex
+	// secondaries is the configs of the running secondary schedulers, keyed by their names.
+	secondaries map[string]*configv1.KubeSchedulerConfiguration
 
 	clientset           clientset.Interface
 	restclientCfg       *restclient.Config
@@ -34,7 +55,13 @@ type Service struct {
 	currentSchedulerCfg *configv1.KubeSchedulerConfiguration
 	extenderService     ExtenderService
 	sharedStore         storereflector.Reflector
-	simulatorPort       int
+	simulatorAddress    string
+	// featureGates are written along with the config every time the scheduler is restarted.
+	featureGates map[string]bool
+	// randomSeed is written along with the config every time the scheduler is restarted, which makes the scheduler deterministic.
+	randomSeed *int64
+	// now returns the current time, which is replaced in the tests.
+	now func() time.Time
 }
 
 type ExtenderService interface {
@@ -44,18 +71,64 @@ type ExtenderService interface {
 	Bind(id int, args extenderv1.ExtenderBindingArgs) (*extenderv1.ExtenderBindingResult, error)
 }
 
-var ErrServiceDisabled = errors.New("scheduler service is disabled")
+var (
+	ErrServiceDisabled = errors.New("scheduler service is disabled")
+	// ErrInvalidSchedulerConfig is returned when the config to apply is invalid. The scheduler isn't restarted then.
+	ErrInvalidSchedulerConfig = errors.New("invalid scheduler configuration")
+	// ErrSchedulerConfigRolledBack is returned when the scheduler fails to start with the new config,
+	// and it's restarted with the previous config.
+	ErrSchedulerConfigRolledBack = errors.New("the scheduler failed to start with the new configuration, and is rolled back to the previous one")
+)
+
+// ActiveSchedulerConfig is the config the scheduler is running with.
+type ActiveSchedulerConfig struct {
+	Config *configv1.KubeSchedulerConfiguration `json:"config"`
+	// Generation is incremented every time a config is applied successfully.
+	Generation int64 `json:"generation"`
+	// RandomSeed is the seed of the random numbers of the scheduler. It's nil if the scheduler isn't deterministic.
+	RandomSeed *int64 `json:"randomSeed,omitempty"`
+}
 
 // NewSchedulerService starts scheduler and return *Service.
-func NewSchedulerService(client clientset.Interface, restclientCfg *restclient.Config, initialSchedulerCfg *configv1.KubeSchedulerConfiguration, simulatorPort int) *Service {
+// featureGates are set to the scheduler when it's restarted.
+// randomSeed seeds the random numbers of the scheduler when it's restarted, and they're random if it's nil.
+func NewSchedulerService(client clientset.Interface, restclientCfg *restclient.Config, initialSchedulerCfg *configv1.KubeSchedulerConfiguration, simulatorAddress string, featureGates map[string]bool, randomSeed *int64) *Service {
 	// sharedStore has some resultstores which are referenced by Registry of Plugins and Extenders.
 	sharedStore := storereflector.New()
 
 	initCfg := initialSchedulerCfg.DeepCopy()
-	return &Service{clientset: client, restclientCfg: restclientCfg, initialSchedulerCfg: initCfg, sharedStore: sharedStore, simulatorPort: simulatorPort}
+	s := &Service{clientset: client, restclientCfg: restclientCfg, initialSchedulerCfg: initCfg, sharedStore: sharedStore, simulatorAddress: simulatorAddress, featureGates: featureGates, randomSeed: randomSeed, now: time.Now, secondaries: map[string]*configv1.KubeSchedulerConfiguration{}}
+	s.restartfn = s.restartDebuggableScheduler
+	s.startSecondaryfn = s.startSecondaryContainer
+	s.stopSecondaryfn = s.stopSecondaryContainer
+	return s
+}
+
+const (
+	// schedulerStartupWindow is how long the restarted scheduler must keep running to be regarded as started.
+	// The scheduler with the broken config exits soon after it starts, which isn't noticed right after the restart.
+	schedulerStartupWindow = 5 * time.Second
+	// schedulerStartupPollInterval is the interval to check the restarted scheduler in schedulerStartupWindow.
+	schedulerStartupPollInterval = 500 * time.Millisecond
+)
+
+// dockerClient is the part of the docker client used to restart the scheduler container, which is faked in the tests.
+type dockerClient interface {
+	ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error)
+	ContainerRestart(ctx context.Context, containerID string, options container.StopOptions) error
+	ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error)
 }
 
-func restartContainer(ctx context.Context, cli *client.Client, cfg *configv1.KubeSchedulerConfiguration) error {
+// restartDebuggableScheduler restarts the debuggable scheduler container with cfg, the feature gates and the random seed.
+func (s *Service) restartDebuggableScheduler(ctx context.Context, cfg *configv1.KubeSchedulerConfiguration) error {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return xerrors.Errorf("failed to create docker client: %w", err)
+	}
+	return restartContainer(ctx, cli, cfg, s.featureGates, s.randomSeed)
+}
+
+func restartContainer(ctx context.Context, cli dockerClient, cfg *configv1.KubeSchedulerConfiguration, featureGates map[string]bool, randomSeed *int64) error {
 	containers, err := cli.ContainerList(ctx, container.ListOptions{})
 	if err != nil {
 		return xerrors.Errorf("failed to get container list: %w", err)
@@ -67,44 +140,111 @@ func restartContainer(ctx context.Context, cli *client.Client, cfg *configv1.Kub
 		if err := simulatorschedconfig.UpdateSchedulerConfig(cfg); err != nil {
 			return xerrors.Errorf("read old scheduler.yaml: %w", err)
 		}
+		// The debuggable scheduler reads the feature gates from the file next to scheduler.yaml when it starts.
+		if err := simulatorschedconfig.UpdateFeatureGates(featureGates); err != nil {
+			return xerrors.Errorf("write the feature gates of the scheduler: %w", err)
+		}
+		if err := simulatorschedconfig.UpdateRandomSeed(randomSeed); err != nil {
+			return xerrors.Errorf("write the random seed of the scheduler: %w", err)
+		}
 
 		if err := cli.ContainerRestart(ctx, c.ID, container.StopOptions{}); err != nil {
 			return xerrors.Errorf("failed restart container: %w", err)
 		}
-		inspect, err := cli.ContainerInspect(ctx, c.ID)
-		if err != nil {
-			return xerrors.Errorf("failed get container inspect: %w", err)
-		}
-		if inspect.State.Status != "running" {
-			return xerrors.Errorf("restart container status is not running")
-		}
-		return nil
+		return waitForContainerStarted(ctx, cli, c.ID, schedulerStartupWindow, schedulerStartupPollInterval)
 	}
 
 	return xerrors.New("can not find simulator-scheduler, are you running the debuggable scheduler along with this simulator container?")
 }
 
+// waitForContainerStarted returns nil if the container keeps running without being restarted for window.
+// It checks the container every interval, and returns an error as soon as the container is found stopped or restarted.
+func waitForContainerStarted(ctx context.Context, cli dockerClient, id string, window, interval time.Duration) error {
+	inspect, err := cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return xerrors.Errorf("failed get container inspect: %w", err)
+	}
+	if !containerRunning(inspect) {
+		return xerrors.Errorf("restart container status is not running, status: %s", containerStatus(inspect))
+	}
+	startedAt := inspect.State.StartedAt
+
+	deadline := time.NewTimer(window)
+	defer deadline.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return xerrors.Errorf("wait for the container to start: %w", ctx.Err())
+		case <-deadline.C:
+			return nil
+		case <-ticker.C:
+			inspect, err := cli.ContainerInspect(ctx, id)
+			if err != nil {
+				return xerrors.Errorf("failed get container inspect: %w", err)
+			}
+			if !containerRunning(inspect) {
+				return xerrors.Errorf("the container stopped after the restart, status: %s", containerStatus(inspect))
+			}
+			// The container restarted by its restart policy has the new start time.
+			if inspect.State.StartedAt != startedAt {
+				return xerrors.Errorf("the container restarted again after the restart, exit code: %d", inspect.State.ExitCode)
+			}
+		}
+	}
+}
+
+// containerRunning returns true if the container is running.
+func containerRunning(inspect types.ContainerJSON) bool {
+	return inspect.ContainerJSONBase != nil && inspect.State != nil && inspect.State.Status == "running"
+}
+
+// containerStatus describes the status of the container for the errors.
+func containerStatus(inspect types.ContainerJSON) string {
+	if inspect.ContainerJSONBase == nil || inspect.State == nil {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s, exit code: %d", inspect.State.Status, inspect.State.ExitCode)
+}
+
 // RestartScheduler restarts the debuggable scheduler with a new config.
 // Specifically, it updates the config file, which is also mounted on the debuggable scheduler,
 // and then restart the debuggable scheduler.
+//
+// The config is validated first, and ErrInvalidSchedulerConfig is returned without restarting the scheduler if it's invalid.
+// If the scheduler fails to start with the new config, it's restarted with the previous config again,
+// and ErrSchedulerConfigRolledBack is returned. ActiveSchedulerConfig tells which config is running in any case.
 func (s *Service) RestartScheduler(cfg *configv1.KubeSchedulerConfiguration) error {
-	ctx := context.Background()
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	s.applyMu.Lock()
+	defer s.applyMu.Unlock()
+
+	errs, err := s.ValidateSchedulerConfig(cfg)
 	if err != nil {
-		return xerrors.Errorf("failed to create docker client: %w", err)
+		return xerrors.Errorf("%v: %w", err, ErrInvalidSchedulerConfig)
+	}
+	if len(errs) > 0 {
+		return xerrors.Errorf("%v: %w", errs.ToAggregate(), ErrInvalidSchedulerConfig)
 	}
 
-	oldCfg, err := simulatorconfig.GetSchedulerCfg()
+	oldCfg, err := s.GetSchedulerConfig()
 	if err != nil {
-		return xerrors.Errorf("read old scheduler.yaml: %w", err)
+		return xerrors.Errorf("get the current scheduler config: %w", err)
+	}
+	if oldCfg == nil {
+		// The config hasn't been applied via the service yet, so the scheduler is running with the initial one.
+		// It isn't read again since it can be fetched from the URL or the ConfigMap.
+		oldCfg = s.initialSchedulerCfg.DeepCopy()
 	}
 
-	if err := restartContainer(ctx, cli, cfg); err != nil {
+	ctx := context.Background()
+	if err := s.restartfn(ctx, cfg); err != nil {
 		klog.Errorf("failed to apply new scheduler config: %v", err)
 		// If failing restarting the container, we roll back to the old config.
-		if err := restartContainer(ctx, cli, oldCfg); err != nil {
-			return xerrors.Errorf("oldConfig restart failed: %w", err)
+		if rerr := s.restartfn(ctx, oldCfg); rerr != nil {
+			return xerrors.Errorf("oldConfig restart failed: %v, after the new config failed: %w", rerr, err)
 		}
+		return xerrors.Errorf("%v: %w", err, ErrSchedulerConfigRolledBack)
 	}
 	s.SetSchedulerConfig(cfg)
 	return nil
@@ -115,6 +255,7 @@ func (s *Service) ResetScheduler() error {
 }
 
 func (s *Service) ShutdownScheduler() {
+	s.stopSecondarySchedulers()
 	if s.shutdownfn != nil {
 		klog.Info("shutdown scheduler...")
 		s.shutdownfn()
@@ -122,11 +263,67 @@ func (s *Service) ShutdownScheduler() {
 }
 
 func (s *Service) GetSchedulerConfig() (*configv1.KubeSchedulerConfiguration, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	return s.currentSchedulerCfg, nil
 }
 
+// SetSchedulerConfig records cfg as the config the scheduler is running with, and increments the generation.
 func (s *Service) SetSchedulerConfig(cfg *configv1.KubeSchedulerConfiguration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.currentSchedulerCfg = cfg.DeepCopy()
+	s.generation++
+}
+
+// ActiveSchedulerConfig returns the config the scheduler is running with, and its generation.
+func (s *Service) ActiveSchedulerConfig() (*ActiveSchedulerConfig, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.currentSchedulerCfg == nil {
+		return nil, xerrors.New("the scheduler configuration hasn't been applied yet")
+	}
+	return &ActiveSchedulerConfig{Config: s.currentSchedulerCfg.DeepCopy(), Generation: s.generation, RandomSeed: s.randomSeed}, nil
+}
+
+// ValidateSchedulerConfig validates cfg without applying it to the scheduler.
+// It returns the invalid fields of cfg, and an error only if cfg can't be read.
+// The schedulerNames used by the running secondary schedulers are invalid
+// because both schedulers would compete for the same Pods otherwise.
+func (s *Service) ValidateSchedulerConfig(cfg *configv1.KubeSchedulerConfiguration) (field.ErrorList, error) {
+	errs, err := simulatorschedconfig.ValidateSchedulerConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s.secondaryMu.Lock()
+	defer s.secondaryMu.Unlock()
+	if len(cfg.Profiles) == 0 {
+		if _, ok := s.secondaries[v1.DefaultSchedulerName]; ok {
+			errs = append(errs, field.Invalid(field.NewPath("profiles"), nil, fmt.Sprintf("the default profile's schedulerName %q is used by the secondary scheduler", v1.DefaultSchedulerName)))
+		}
+		return errs, nil
+	}
+	for i, p := range cfg.Profiles {
+		if p.SchedulerName == nil {
+			continue
+		}
+		if _, ok := s.secondaries[*p.SchedulerName]; ok {
+			errs = append(errs, field.Invalid(field.NewPath("profiles").Index(i).Child("schedulerName"), *p.SchedulerName, "used by the secondary scheduler"))
+		}
+	}
+	return errs, nil
+}
+
+// Healthz returns an error if the scheduler isn't ready,
+// i.e., the scheduler configuration hasn't been applied yet when the simulator starts.
+func (s *Service) Healthz() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.currentSchedulerCfg == nil {
+		return xerrors.New("the scheduler configuration hasn't been applied yet")
+	}
+	return nil
 }
 
 // ExtenderService returns ExtenderService interface.
@@ -1,10 +1,17 @@
 package scheduler
 
 import (
+	"context"
 	"sort"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -516,6 +523,166 @@ func Test_convertConfigurationForSimulator(t *testing.T) {
 	}
 }
 
+func TestService_RestartScheduler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		// schedulerName is the name of the profile in the config to apply.
+		schedulerName string
+		parallelism   int32
+		// wantRestarted is the scheduler names of the configs the scheduler is restarted with.
+		wantRestarted  []string
+		wantErr        error
+		wantActive     string
+		wantGeneration int64
+	}{
+		{
+			name:           "swap the config",
+			schedulerName:  "new-scheduler",
+			wantRestarted:  []string{"new-scheduler"},
+			wantActive:     "new-scheduler",
+			wantGeneration: 2,
+		},
+		{
+			name:           "roll back to the previous config when the scheduler fails to start",
+			schedulerName:  "broken-scheduler",
+			wantRestarted:  []string{"broken-scheduler", v1.DefaultSchedulerName},
+			wantErr:        ErrSchedulerConfigRolledBack,
+			wantActive:     v1.DefaultSchedulerName,
+			wantGeneration: 1,
+		},
+		{
+			name:           "roll back to the previous config when the scheduler exits soon after the restart",
+			schedulerName:  "crashing-scheduler",
+			wantRestarted:  []string{"crashing-scheduler", v1.DefaultSchedulerName},
+			wantErr:        ErrSchedulerConfigRolledBack,
+			wantActive:     v1.DefaultSchedulerName,
+			wantGeneration: 1,
+		},
+		{
+			name:           "refuse the invalid config without restarting the scheduler",
+			schedulerName:  "new-scheduler",
+			parallelism:    -1,
+			wantRestarted:  []string{},
+			wantErr:        ErrInvalidSchedulerConfig,
+			wantActive:     v1.DefaultSchedulerName,
+			wantGeneration: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			initial, err := schedConfig.DefaultSchedulerConfig()
+			require.NoError(t, err)
+			s := NewSchedulerService(nil, nil, initial, "", nil, nil)
+			restarted := []string{}
+			s.restartfn = func(ctx context.Context, cfg *configv1.KubeSchedulerConfiguration) error {
+				name := *cfg.Profiles[0].SchedulerName
+				restarted = append(restarted, name)
+				switch name {
+				case "broken-scheduler":
+					return xerrors.New("the scheduler container isn't running")
+				case "crashing-scheduler":
+					// it's running right after the restart, but exits soon.
+					cli := &fakeDockerClient{states: []*types.ContainerState{
+						{Status: "running", Running: true, StartedAt: "2024-01-01T00:00:00Z"},
+						{Status: "exited", ExitCode: 1, StartedAt: "2024-01-01T00:00:00Z"},
+					}}
+					return waitForContainerStarted(ctx, cli, "scheduler", time.Second, 10*time.Millisecond)
+				}
+				return nil
+			}
+			s.SetSchedulerConfig(initial)
+
+			cfg := initial.DeepCopy()
+			cfg.Profiles[0].SchedulerName = ptr.To(tt.schedulerName)
+			if tt.parallelism != 0 {
+				cfg.Parallelism = ptr.To(tt.parallelism)
+			}
+			err = s.RestartScheduler(cfg)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantRestarted, restarted)
+
+			active, err := s.ActiveSchedulerConfig()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantActive, *active.Config.Profiles[0].SchedulerName)
+			assert.Equal(t, tt.wantGeneration, active.Generation)
+		})
+	}
+}
+
+// fakeDockerClient returns states in order from ContainerInspect, and the last one after that.
+type fakeDockerClient struct {
+	mu     sync.Mutex
+	states []*types.ContainerState
+}
+
+func (f *fakeDockerClient) ContainerList(_ context.Context, _ container.ListOptions) ([]types.Container, error) {
+	return []types.Container{{ID: "scheduler", Names: []string{"/simulator-scheduler"}}}, nil
+}
+
+func (f *fakeDockerClient) ContainerRestart(_ context.Context, _ string, _ container.StopOptions) error {
+	return nil
+}
+
+func (f *fakeDockerClient) ContainerInspect(_ context.Context, _ string) (types.ContainerJSON, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	state := f.states[0]
+	if len(f.states) > 1 {
+		f.states = f.states[1:]
+	}
+	return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: state}}, nil
+}
+
+func Test_waitForContainerStarted(t *testing.T) {
+	t.Parallel()
+	running := &types.ContainerState{Status: "running", Running: true, StartedAt: "2024-01-01T00:00:00Z"}
+	tests := []struct {
+		name    string
+		states  []*types.ContainerState
+		wantErr bool
+	}{
+		{
+			name:   "keep running",
+			states: []*types.ContainerState{running},
+		},
+		{
+			name:    "exit immediately",
+			states:  []*types.ContainerState{running, {Status: "exited", ExitCode: 1, StartedAt: running.StartedAt}},
+			wantErr: true,
+		},
+		{
+			name:    "restarted by the restart policy",
+			states:  []*types.ContainerState{running, running, {Status: "running", Running: true, StartedAt: "2024-01-01T00:00:01Z"}},
+			wantErr: true,
+		},
+		{
+			name:    "not running right after the restart",
+			states:  []*types.ContainerState{{Status: "restarting", Restarting: true}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cli := &fakeDockerClient{states: tt.states}
+			err := waitForContainerStarted(context.Background(), cli, "scheduler", 200*time.Millisecond, 10*time.Millisecond)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func configGeneratedFromDefault() configv1.KubeSchedulerConfiguration {
 	versioned, _ := schedConfig.DefaultSchedulerConfig()
 	cfg := versioned.DeepCopy()
@@ -0,0 +1,235 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+	"k8s.io/utils/ptr"
+
+	simulatorschedconfig "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/config"
+)
+
+const (
+	// primarySchedulerContainerName is the name of the debuggable scheduler container started along with the simulator.
+	primarySchedulerContainerName = "simulator-scheduler"
+	// secondarySchedulerContainerPrefix is the prefix of the names of the secondary scheduler containers.
+	secondarySchedulerContainerPrefix = primarySchedulerContainerName + "-"
+)
+
+var (
+	// ErrSecondarySchedulerExists is returned when the secondary scheduler with the same name is already running.
+	ErrSecondarySchedulerExists = errors.New("secondary scheduler already exists")
+	// ErrSecondarySchedulerNotFound is returned when the secondary scheduler with the name isn't running.
+	ErrSecondarySchedulerNotFound = errors.New("secondary scheduler not found")
+)
+
+// SecondaryScheduler is a scheduler running side by side with the primary one, e.g., to compare two configs.
+// It schedules only the Pods whose schedulerName is Name.
+type SecondaryScheduler struct {
+	Name   string                               `json:"name"`
+	Config *configv1.KubeSchedulerConfiguration `json:"config"`
+}
+
+// StartSecondaryScheduler starts another debuggable scheduler with cfg, which schedules the Pods whose schedulerName is name.
+// cfg must have at most one profile, and its schedulerName is overwritten with name
+// so that the secondary scheduler never competes with the primary one for the same Pods.
+// The results of the secondary scheduler are recorded with name as the scheduler name of the Pods.
+//
+// ErrInvalidSchedulerConfig is returned if name or cfg is invalid,
+// and ErrSecondarySchedulerExists is returned if the secondary scheduler with name is already running.
+func (s *Service) StartSecondaryScheduler(cfg *configv1.KubeSchedulerConfiguration, name string) error {
+	s.secondaryMu.Lock()
+	defer s.secondaryMu.Unlock()
+
+	if _, ok := s.secondaries[name]; ok {
+		return xerrors.Errorf("start secondary scheduler %s: %w", name, ErrSecondarySchedulerExists)
+	}
+	secondaryCfg, err := s.secondarySchedulerConfig(cfg, name)
+	if err != nil {
+		return err
+	}
+	if err := s.startSecondaryfn(context.Background(), name, secondaryCfg); err != nil {
+		return xerrors.Errorf("start secondary scheduler %s: %w", name, err)
+	}
+	s.secondaries[name] = secondaryCfg
+	return nil
+}
+
+// StopSecondaryScheduler stops the secondary scheduler with name.
+// ErrSecondarySchedulerNotFound is returned if it isn't running.
+func (s *Service) StopSecondaryScheduler(name string) error {
+	s.secondaryMu.Lock()
+	defer s.secondaryMu.Unlock()
+
+	if _, ok := s.secondaries[name]; !ok {
+		return xerrors.Errorf("stop secondary scheduler %s: %w", name, ErrSecondarySchedulerNotFound)
+	}
+	if err := s.stopSecondaryfn(context.Background(), name); err != nil {
+		return xerrors.Errorf("stop secondary scheduler %s: %w", name, err)
+	}
+	delete(s.secondaries, name)
+	return nil
+}
+
+// SecondarySchedulers returns the running secondary schedulers sorted by their names.
+func (s *Service) SecondarySchedulers() []SecondaryScheduler {
+	s.secondaryMu.Lock()
+	defer s.secondaryMu.Unlock()
+
+	ret := make([]SecondaryScheduler, 0, len(s.secondaries))
+	for name, cfg := range s.secondaries {
+		ret = append(ret, SecondaryScheduler{Name: name, Config: cfg.DeepCopy()})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// stopSecondarySchedulers stops all the secondary schedulers. It's called when the simulator is shut down.
+func (s *Service) stopSecondarySchedulers() {
+	s.secondaryMu.Lock()
+	defer s.secondaryMu.Unlock()
+
+	for name := range s.secondaries {
+		if err := s.stopSecondaryfn(context.Background(), name); err != nil {
+			klog.Errorf("failed to stop secondary scheduler %s: %+v", name, err)
+			continue
+		}
+		delete(s.secondaries, name)
+	}
+}
+
+// secondarySchedulerConfig validates name and cfg, and returns the config for the secondary scheduler with name.
+func (s *Service) secondarySchedulerConfig(cfg *configv1.KubeSchedulerConfiguration, name string) (*configv1.KubeSchedulerConfiguration, error) {
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return nil, xerrors.Errorf("invalid name %q: %v: %w", name, errs, ErrInvalidSchedulerConfig)
+	}
+	primaryCfg, err := s.GetSchedulerConfig()
+	if err != nil {
+		return nil, xerrors.Errorf("get the current scheduler config: %w", err)
+	}
+	if primaryCfg == nil {
+		primaryCfg = s.initialSchedulerCfg
+	}
+	if profileSchedulerNames(primaryCfg)[name] {
+		return nil, xerrors.Errorf("name %q is used by the primary scheduler: %w", name, ErrInvalidSchedulerConfig)
+	}
+	if cfg == nil {
+		return nil, xerrors.Errorf("config is empty: %w", ErrInvalidSchedulerConfig)
+	}
+	if len(cfg.Profiles) > 1 {
+		return nil, xerrors.Errorf("the secondary scheduler can have only one profile, but got %d: %w", len(cfg.Profiles), ErrInvalidSchedulerConfig)
+	}
+
+	ret := cfg.DeepCopy()
+	if len(ret.Profiles) == 0 {
+		ret.Profiles = []configv1.KubeSchedulerProfile{{}}
+	}
+	ret.Profiles[0].SchedulerName = ptr.To(name)
+	// Both schedulers would take the same lease otherwise.
+	ret.LeaderElection.LeaderElect = ptr.To(false)
+
+	// secondaryMu is held, so the config is validated without checking the running secondary schedulers.
+	errs, err := simulatorschedconfig.ValidateSchedulerConfig(ret)
+	if err != nil {
+		return nil, xerrors.Errorf("%v: %w", err, ErrInvalidSchedulerConfig)
+	}
+	if len(errs) > 0 {
+		return nil, xerrors.Errorf("%v: %w", errs.ToAggregate(), ErrInvalidSchedulerConfig)
+	}
+	return ret, nil
+}
+
+// startSecondaryContainer starts the secondary scheduler container with cfg,
+// which is created in the same way as the primary debuggable scheduler container except the config file.
+func (s *Service) startSecondaryContainer(ctx context.Context, name string, cfg *configv1.KubeSchedulerConfiguration) error {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return xerrors.Errorf("failed to create docker client: %w", err)
+	}
+	primary, err := cli.ContainerInspect(ctx, primarySchedulerContainerName)
+	if err != nil {
+		return xerrors.Errorf("can not find %s, are you running the debuggable scheduler along with this simulator container?: %w", primarySchedulerContainerName, err)
+	}
+
+	if err := simulatorschedconfig.UpdateSecondarySchedulerConfig(name, cfg); err != nil {
+		return xerrors.Errorf("write the config of the secondary scheduler: %w", err)
+	}
+	// The feature gates and the random seed are read from the files next to the config, which are shared with the primary scheduler.
+	if err := simulatorschedconfig.UpdateFeatureGates(s.featureGates); err != nil {
+		return xerrors.Errorf("write the feature gates of the scheduler: %w", err)
+	}
+	if err := simulatorschedconfig.UpdateRandomSeed(s.randomSeed); err != nil {
+		return xerrors.Errorf("write the random seed of the scheduler: %w", err)
+	}
+
+	containerCfg := *primary.Config
+	containerCfg.Hostname = ""
+	// The labels of docker compose are dropped so that the container isn't regarded as a replica of the primary one.
+	containerCfg.Labels = nil
+	containerCfg.Cmd = secondarySchedulerCmd(primary.Config.Cmd, name)
+	hostCfg := *primary.HostConfig
+	// The secondary scheduler doesn't publish any port not to conflict with the primary one.
+	hostCfg.PortBindings = nil
+	hostCfg.RestartPolicy = container.RestartPolicy{}
+	networkingCfg := &network.NetworkingConfig{EndpointsConfig: map[string]*network.EndpointSettings{}}
+	if primary.NetworkSettings != nil {
+		for n := range primary.NetworkSettings.Networks {
+			networkingCfg.EndpointsConfig[n] = &network.EndpointSettings{}
+		}
+	}
+
+	containerName := secondarySchedulerContainerPrefix + name
+	created, err := cli.ContainerCreate(ctx, &containerCfg, &hostCfg, networkingCfg, nil, containerName)
+	if err != nil {
+		return xerrors.Errorf("failed to create container %s: %w", containerName, err)
+	}
+	if err := cli.ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+		if rerr := cli.ContainerRemove(ctx, created.ID, container.RemoveOptions{Force: true}); rerr != nil {
+			klog.Errorf("failed to remove container %s: %+v", containerName, rerr)
+		}
+		return xerrors.Errorf("failed to start container %s: %w", containerName, err)
+	}
+	return nil
+}
+
+// stopSecondaryContainer removes the secondary scheduler container and its config file.
+func (s *Service) stopSecondaryContainer(ctx context.Context, name string) error {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return xerrors.Errorf("failed to create docker client: %w", err)
+	}
+	containerName := secondarySchedulerContainerPrefix + name
+	if err := cli.ContainerRemove(ctx, containerName, container.RemoveOptions{Force: true}); err != nil && !client.IsErrNotFound(err) {
+		return xerrors.Errorf("failed to remove container %s: %w", containerName, err)
+	}
+	if err := simulatorschedconfig.RemoveSecondarySchedulerConfig(name); err != nil {
+		return xerrors.Errorf("remove the config of the secondary scheduler: %w", err)
+	}
+	return nil
+}
+
+// secondarySchedulerCmd returns cmd of the primary scheduler with the --config flag pointing to the config of the secondary scheduler.
+// The config file is put in the same directory as the one of the primary scheduler.
+func secondarySchedulerCmd(cmd []string, name string) []string {
+	ret := make([]string, len(cmd))
+	copy(ret, cmd)
+	for i := range ret {
+		switch {
+		case ret[i] == "--config" && i+1 < len(ret):
+			ret[i+1] = path.Join(path.Dir(ret[i+1]), simulatorschedconfig.SecondarySchedulerConfigFileName(name))
+		case strings.HasPrefix(ret[i], "--config="):
+			ret[i] = "--config=" + path.Join(path.Dir(strings.TrimPrefix(ret[i], "--config=")), simulatorschedconfig.SecondarySchedulerConfigFileName(name))
+		}
+	}
+	return ret
+}
@@ -0,0 +1,183 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+	v1 "k8s.io/api/core/v1"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+	"k8s.io/utils/ptr"
+
+	schedConfig "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/config"
+)
+
+func TestService_SecondaryScheduler(t *testing.T) {
+	t.Parallel()
+	initial, err := schedConfig.DefaultSchedulerConfig()
+	require.NoError(t, err)
+	s := NewSchedulerService(nil, nil, initial, "", nil, nil)
+	s.SetSchedulerConfig(initial)
+	started := map[string]*configv1.KubeSchedulerConfiguration{}
+	s.startSecondaryfn = func(_ context.Context, name string, cfg *configv1.KubeSchedulerConfiguration) error {
+		if name == "broken-scheduler" {
+			return xerrors.New("the container isn't running")
+		}
+		started[name] = cfg
+		return nil
+	}
+	s.stopSecondaryfn = func(_ context.Context, name string) error {
+		delete(started, name)
+		return nil
+	}
+
+	cfg := initial.DeepCopy()
+	cfg.Profiles[0].Plugins.Score.Disabled = []configv1.Plugin{{Name: "ImageLocality"}}
+	require.NoError(t, s.StartSecondaryScheduler(cfg, "scheduler-b"))
+	// The profile is renamed so that the secondary scheduler doesn't take the Pods of the primary one.
+	require.Contains(t, started, "scheduler-b")
+	assert.Equal(t, "scheduler-b", *started["scheduler-b"].Profiles[0].SchedulerName)
+	assert.False(t, *started["scheduler-b"].LeaderElection.LeaderElect)
+	assert.Equal(t, v1.DefaultSchedulerName, *cfg.Profiles[0].SchedulerName, "the given config mustn't be changed")
+	// The primary scheduler keeps its config.
+	active, err := s.ActiveSchedulerConfig()
+	require.NoError(t, err)
+	assert.Equal(t, v1.DefaultSchedulerName, *active.Config.Profiles[0].SchedulerName)
+	assert.Equal(t, int64(1), active.Generation)
+
+	assert.ErrorIs(t, s.StartSecondaryScheduler(cfg, "scheduler-b"), ErrSecondarySchedulerExists)
+	assert.Error(t, s.StartSecondaryScheduler(cfg, "broken-scheduler"))
+	require.NoError(t, s.StartSecondaryScheduler(&configv1.KubeSchedulerConfiguration{}, "scheduler-a"))
+
+	got := s.SecondarySchedulers()
+	require.Len(t, got, 2)
+	assert.Equal(t, "scheduler-a", got[0].Name)
+	assert.Equal(t, "scheduler-a", *got[0].Config.Profiles[0].SchedulerName)
+	assert.Equal(t, "scheduler-b", got[1].Name)
+
+	require.NoError(t, s.StopSecondaryScheduler("scheduler-b"))
+	assert.NotContains(t, started, "scheduler-b")
+	assert.ErrorIs(t, s.StopSecondaryScheduler("scheduler-b"), ErrSecondarySchedulerNotFound)
+
+	s.ShutdownScheduler()
+	assert.Empty(t, started)
+	assert.Empty(t, s.SecondarySchedulers())
+}
+
+func TestService_StartSecondaryScheduler_invalid(t *testing.T) {
+	t.Parallel()
+	initial, err := schedConfig.DefaultSchedulerConfig()
+	require.NoError(t, err)
+	twoProfiles := initial.DeepCopy()
+	twoProfiles.Profiles = append(twoProfiles.Profiles, configv1.KubeSchedulerProfile{SchedulerName: ptr.To("another")})
+	invalid := initial.DeepCopy()
+	invalid.Parallelism = ptr.To[int32](-1)
+
+	tests := []struct {
+		name       string
+		schedName  string
+		cfg        *configv1.KubeSchedulerConfiguration
+		wantErrMsg string
+	}{
+		{
+			name:       "the name isn't a DNS label",
+			schedName:  "Scheduler_B",
+			cfg:        initial,
+			wantErrMsg: "invalid name",
+		},
+		{
+			name:       "the name is used by the primary scheduler",
+			schedName:  v1.DefaultSchedulerName,
+			cfg:        initial,
+			wantErrMsg: "used by the primary scheduler",
+		},
+		{
+			name:       "no config",
+			schedName:  "scheduler-b",
+			wantErrMsg: "config is empty",
+		},
+		{
+			name:       "more than one profile",
+			schedName:  "scheduler-b",
+			cfg:        twoProfiles,
+			wantErrMsg: "only one profile",
+		},
+		{
+			name:       "invalid config",
+			schedName:  "scheduler-b",
+			cfg:        invalid,
+			wantErrMsg: "parallelism",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := NewSchedulerService(nil, nil, initial, "", nil, nil)
+			s.startSecondaryfn = func(_ context.Context, _ string, _ *configv1.KubeSchedulerConfiguration) error {
+				t.Fatal("the secondary scheduler mustn't be started")
+				return nil
+			}
+			err := s.StartSecondaryScheduler(tt.cfg, tt.schedName)
+			assert.ErrorIs(t, err, ErrInvalidSchedulerConfig)
+			assert.ErrorContains(t, err, tt.wantErrMsg)
+			assert.Empty(t, s.SecondarySchedulers())
+		})
+	}
+}
+
+func TestService_RestartScheduler_secondarySchedulerName(t *testing.T) {
+	t.Parallel()
+	initial, err := schedConfig.DefaultSchedulerConfig()
+	require.NoError(t, err)
+	s := NewSchedulerService(nil, nil, initial, "", nil, nil)
+	s.startSecondaryfn = func(_ context.Context, _ string, _ *configv1.KubeSchedulerConfiguration) error { return nil }
+	s.restartfn = func(_ context.Context, _ *configv1.KubeSchedulerConfiguration) error {
+		t.Fatal("the primary scheduler mustn't be restarted")
+		return nil
+	}
+	require.NoError(t, s.StartSecondaryScheduler(initial, "scheduler-b"))
+
+	cfg := initial.DeepCopy()
+	cfg.Profiles = append(cfg.Profiles, configv1.KubeSchedulerProfile{SchedulerName: ptr.To("scheduler-b")})
+	errs, err := s.ValidateSchedulerConfig(cfg)
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "profiles[1].schedulerName", errs[0].Field)
+
+	err = s.RestartScheduler(cfg)
+	assert.ErrorIs(t, err, ErrInvalidSchedulerConfig)
+	assert.ErrorContains(t, err, "used by the secondary scheduler")
+	assert.Nil(t, s.currentSchedulerCfg)
+}
+
+func Test_secondarySchedulerCmd(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		cmd  []string
+		want []string
+	}{
+		{
+			name: "separated value",
+			cmd:  []string{"/scheduler", "--config", "/config/scheduler.yaml", "--master", "http://simulator-cluster:3131"},
+			want: []string{"/scheduler", "--config", "/config/scheduler-scheduler-b.yaml", "--master", "http://simulator-cluster:3131"},
+		},
+		{
+			name: "value with =",
+			cmd:  []string{"/scheduler", "--config=/config/scheduler.yaml"},
+			want: []string{"/scheduler", "--config=/config/scheduler-scheduler-b.yaml"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := secondarySchedulerCmd(tt.cmd, "scheduler-b")
+			assert.Equal(t, tt.want, got)
+			assert.NotEqual(t, tt.want, tt.cmd, "the given cmd mustn't be changed")
+		})
+	}
+}
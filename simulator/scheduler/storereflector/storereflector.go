@@ -21,6 +21,7 @@ import (
 
 type Reflector interface {
 	AddResultStore(store ResultStore, key string)
+	GetResultStore(key string) (ResultStore, bool)
 	ResisterResultSavingToInformer(client clientset.Interface, stopCh <-chan struct{}) error
 }
 
@@ -34,16 +35,54 @@ type ResultStore interface {
 	DeleteData(key corev1.Pod)
 }
 
+// ResultWriter writes the results of the scheduling somewhere other than the Pod's annotations.
+type ResultWriter interface {
+	// Write writes the results of the latest scheduling of the pod.
+	// The update is retried when it returns a conflict error.
+	Write(ctx context.Context, pod *corev1.Pod, results map[string]string) error
+}
+
+// ResultRecorder records the results of the scheduling in addition to the Pod's annotations or the ResultWriter.
+type ResultRecorder interface {
+	// Record records the results of the latest scheduling of the pod.
+	Record(pod *corev1.Pod, results map[string]string)
+}
+
 // store manages any ResultStore.
 // ResultStore stores any result that should be reflected to the Pod.
 type reflector struct {
 	resultStores map[string]ResultStore
+	// resultWriter is optional; if it's non-nil, the results are written with it instead of the Pod's annotations.
+	resultWriter ResultWriter
+	// resultRecorders receive the results after they're reflected on the Pod or written with the resultWriter.
+	resultRecorders []ResultRecorder
+}
+
+// Option configures the Reflector.
+type Option func(*reflector)
+
+// WithResultWriter makes the Reflector write the results with w instead of reflecting them on the Pod's annotations.
+func WithResultWriter(w ResultWriter) Option {
+	return func(r *reflector) {
+		r.resultWriter = w
+	}
 }
 
-func New() Reflector {
-	return &reflector{
+// WithResultRecorder makes the Reflector record the results with r as well.
+func WithResultRecorder(r ResultRecorder) Option {
+	return func(s *reflector) {
+		s.resultRecorders = append(s.resultRecorders, r)
+	}
+}
+
+func New(opts ...Option) Reflector {
+	r := &reflector{
 		resultStores: map[string]ResultStore{},
 	}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
 }
 
 // AddResultStore adds the ResultStore to the map.
@@ -51,6 +90,12 @@ func (s *reflector) AddResultStore(store ResultStore, key string) {
 	s.resultStores[key] = store
 }
 
+// GetResultStore returns the ResultStore added with the key.
+func (s *reflector) GetResultStore(key string) (ResultStore, bool) {
+	store, ok := s.resultStores[key]
+	return store, ok
+}
+
 // ResisterResultSavingToInformer registers the event handler to the informerFactory
 // to reflects all results on the pod annotation when the scheduling is finished.
 func (s *reflector) ResisterResultSavingToInformer(client clientset.Interface, stopCh <-chan struct{}) error {
@@ -110,6 +155,10 @@ func (s *reflector) storeAllResultToPodFunc(client clientset.Interface) func(int
 			// overwrite the Pod object so that we won't modify the copy from the shared informer.
 			pod = newPod
 
+			if s.resultWriter != nil {
+				return s.writeResults(ctx, pod)
+			}
+
 			// Call GetStoredResult of all ResultStore which is kept on the map
 			// to reflect all results to the pod annotation.
 			resultSet := map[string]string{}
@@ -153,6 +202,14 @@ func (s *reflector) storeAllResultToPodFunc(client clientset.Interface) func(int
 			return
 		}
 
+		if len(s.resultRecorders) != 0 {
+			if results := s.storedResults(pod); len(results) != 0 {
+				for _, r := range s.resultRecorders {
+					r.Record(pod, results)
+				}
+			}
+		}
+
 		for k := range s.resultStores {
 			// Delete the data from the Reflector only if it is successfully added on the pod's annotations.
 			s.resultStores[k].DeleteData(*pod)
@@ -160,6 +217,34 @@ func (s *reflector) storeAllResultToPodFunc(client clientset.Interface) func(int
 	}
 }
 
+// writeResults writes all results of the pod with the resultWriter.
+func (s *reflector) writeResults(ctx context.Context, pod *corev1.Pod) (bool, error) {
+	resultSet := s.storedResults(pod)
+	if len(resultSet) == 0 {
+		// no need to write anything.
+		return true, nil
+	}
+
+	if err := s.resultWriter.Write(ctx, pod, resultSet); err != nil {
+		if apierrors.IsConflict(err) {
+			return false, nil
+		}
+		return false, xerrors.Errorf("write results: %w", err)
+	}
+	return true, nil
+}
+
+// storedResults returns all results of the pod stored in the ResultStores.
+func (s *reflector) storedResults(pod *corev1.Pod) map[string]string {
+	resultSet := map[string]string{}
+	for k := range s.resultStores {
+		for k, v := range s.resultStores[k].GetStoredResult(pod) {
+			resultSet[k] = v
+		}
+	}
+	return resultSet
+}
+
 func updateResultHistory(p *corev1.Pod, m map[string]string) error {
 	a, ok := p.GetAnnotations()[ResultsHistoryAnnotation]
 	if !ok {
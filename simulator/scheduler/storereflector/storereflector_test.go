@@ -80,6 +80,81 @@ func TestReflector_storeAllResultToPodFunc(t *testing.T) {
 	}
 }
 
+// fakeResultWriter keeps the written results.
+type fakeResultWriter struct {
+	written map[string]string
+}
+
+func (w *fakeResultWriter) Write(_ context.Context, _ *corev1.Pod, results map[string]string) error {
+	w.written = results
+	return nil
+}
+
+func TestReflector_storeAllResultToPodFunc_WithResultWriter(t *testing.T) {
+	t.Parallel()
+
+	c := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod1",
+			Namespace: "default",
+		},
+	})
+	ctrl := gomock.NewController(t)
+	rs := mock_storereflector.NewMockResultStore(ctrl)
+	rs.EXPECT().GetStoredResult(gomock.Any()).Return(map[string]string{ExtenderFilterResultAnnotationKey: "some results"})
+	rs.EXPECT().DeleteData(gomock.Any())
+	w := &fakeResultWriter{}
+	r, ok := New(WithResultWriter(w)).(*reflector)
+	assert.True(t, ok)
+	r.AddResultStore(rs, ResultStoreKey)
+
+	fn := r.storeAllResultToPodFunc(c)
+	p, _ := c.CoreV1().Pods("default").Get(context.Background(), "pod1", metav1.GetOptions{})
+	fn(corev1.Pod{}, p)
+
+	assert.Equal(t, map[string]string{ExtenderFilterResultAnnotationKey: "some results"}, w.written)
+	// The Pod is left untouched.
+	updatedPod, _ := c.CoreV1().Pods("default").Get(context.Background(), "pod1", metav1.GetOptions{})
+	assert.Empty(t, updatedPod.Annotations)
+}
+
+// fakeResultRecorder keeps the recorded results by the Pod's name.
+type fakeResultRecorder struct {
+	recorded map[string]map[string]string
+}
+
+func (r *fakeResultRecorder) Record(pod *corev1.Pod, results map[string]string) {
+	r.recorded[pod.Name] = results
+}
+
+func TestReflector_storeAllResultToPodFunc_WithResultRecorder(t *testing.T) {
+	t.Parallel()
+
+	c := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod1",
+			Namespace: "default",
+		},
+	})
+	ctrl := gomock.NewController(t)
+	rs := mock_storereflector.NewMockResultStore(ctrl)
+	rs.EXPECT().GetStoredResult(gomock.Any()).Return(map[string]string{ExtenderFilterResultAnnotationKey: "some results"}).Times(2)
+	rs.EXPECT().DeleteData(gomock.Any())
+	recorder := &fakeResultRecorder{recorded: map[string]map[string]string{}}
+	r, ok := New(WithResultRecorder(recorder)).(*reflector)
+	assert.True(t, ok)
+	r.AddResultStore(rs, ResultStoreKey)
+
+	fn := r.storeAllResultToPodFunc(c)
+	p, _ := c.CoreV1().Pods("default").Get(context.Background(), "pod1", metav1.GetOptions{})
+	fn(corev1.Pod{}, p)
+
+	assert.Equal(t, map[string]map[string]string{"pod1": {ExtenderFilterResultAnnotationKey: "some results"}}, recorder.recorded)
+	// The results are reflected on the Pod as well.
+	updatedPod, _ := c.CoreV1().Pods("default").Get(context.Background(), "pod1", metav1.GetOptions{})
+	assert.Equal(t, "some results", updatedPod.Annotations[ExtenderFilterResultAnnotationKey])
+}
+
 func Test_updateResultHistory(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/xerrors"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/auth"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/handler"
+)
+
+// authMiddleware rejects the requests without any valid credential with 401,
+// and the mutating requests from the read-only clients with 403.
+func authMiddleware(authenticate auth.Authenticator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id, err := authenticate(c.Request())
+			if err != nil {
+				klog.V(3).InfoS("Rejected the unauthenticated request", "method", c.Request().Method, "path", c.Request().URL.Path, "err", err)
+				return echo.NewHTTPError(http.StatusUnauthorized, "a valid bearer token is required")
+			}
+			if !id.Allows(c.Request().Method) {
+				klog.V(3).InfoS("Rejected the request not allowed for the role", "method", c.Request().Method, "path", c.Request().URL.Path, "client", id.Name, "role", id.Role)
+				return echo.NewHTTPError(http.StatusForbidden, "the "+string(id.Role)+" client isn't allowed to "+c.Request().Method+" "+c.Request().URL.Path)
+			}
+			// The handlers, e.g., the ones watching resources, identify the client with it.
+			c.SetRequest(c.Request().WithContext(auth.WithIdentity(c.Request().Context(), id)))
+			return next(c)
+		}
+	}
+}
+
+// authMiddlewares returns the middlewares to authenticate /api/v1, /healthz and /readyz, and /metrics configured in opts.
+// They are empty if the authentication isn't configured for the routes.
+func authMiddlewares(opts *auth.Options) (api, healthz, metrics []echo.MiddlewareFunc) {
+	if opts == nil {
+		return nil, nil, nil
+	}
+	m := authMiddleware(auth.New(opts))
+	api = []echo.MiddlewareFunc{m}
+	if opts.AuthenticateHealthz {
+		healthz = []echo.MiddlewareFunc{m}
+	}
+	if opts.AuthenticateMetrics {
+		metrics = []echo.MiddlewareFunc{m}
+	}
+	return api, healthz, metrics
+}
+
+// watcherBearerTokenName is the name of the client authenticated with the deprecated watcherBearerToken.
+const watcherBearerTokenName = "bearer-token"
+
+// watchAuthenticator returns the authenticator for the connections to watch resources.
+// The one given to dic takes precedence. Otherwise, the connections are identified as the client
+// authenticated by the middleware of /api/v1 if cfg.Auth is configured,
+// or with the deprecated cfg.WatcherBearerToken as the static token.
+// It returns nil not to authenticate the connections if none of them is given.
+func watchAuthenticator(cfg *config.Config, dic *di.Container) handler.WatchAuthenticator {
+	if a := dic.WatchAuthenticator(); a != nil {
+		return a
+	}
+	if cfg.Auth != nil {
+		// authMiddleware has rejected the connections without any valid credential.
+		return func(r *http.Request) (string, error) {
+			id := auth.IdentityFrom(r.Context())
+			if id == nil {
+				return "", xerrors.Errorf("no client is authenticated: %w", auth.ErrUnauthenticated)
+			}
+			return id.Name, nil
+		}
+	}
+	if cfg.WatcherBearerToken == "" {
+		return nil
+	}
+	authenticate := auth.New(&auth.Options{Tokens: []auth.StaticToken{{Name: watcherBearerTokenName, Token: cfg.WatcherBearerToken, Role: auth.RoleReadOnly}}})
+	return func(r *http.Request) (string, error) {
+		id, err := authenticate(r)
+		if err != nil {
+			return "", err
+		}
+		return id.Name, nil
+	}
+}
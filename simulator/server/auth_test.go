@@ -0,0 +1,220 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/auth"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+func TestAuthMiddlewares(t *testing.T) {
+	t.Parallel()
+	api, healthz, metrics := authMiddlewares(&auth.Options{
+		Tokens: []auth.StaticToken{
+			{Name: "viewer", Token: "view-token", Role: auth.RoleReadOnly},
+			{Name: "admin", Token: "admin-token", Role: auth.RoleReadWrite},
+		},
+		AuthenticateMetrics: true,
+	})
+	e := echo.New()
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/metrics", ok, metrics...)
+	e.GET("/healthz", ok, healthz...)
+	v1 := e.Group("/api/v1", api...)
+	v1.GET("/schedulerconfiguration", ok)
+	v1.POST("/schedulerconfiguration", ok)
+	e.Group("/api/v1").POST("/extender/filter/:id", ok)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		token    string
+		wantCode int
+	}{
+		{
+			name:     "read-only client can GET",
+			method:   http.MethodGet,
+			path:     "/api/v1/schedulerconfiguration",
+			token:    "view-token",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "read-only client cannot POST",
+			method:   http.MethodPost,
+			path:     "/api/v1/schedulerconfiguration",
+			token:    "view-token",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "read-write client can POST",
+			method:   http.MethodPost,
+			path:     "/api/v1/schedulerconfiguration",
+			token:    "admin-token",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "client without token cannot GET",
+			method:   http.MethodGet,
+			path:     "/api/v1/schedulerconfiguration",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "client with unknown token cannot POST",
+			method:   http.MethodPost,
+			path:     "/api/v1/schedulerconfiguration",
+			token:    "unknown",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "the scheduler can call extenders without token",
+			method:   http.MethodPost,
+			path:     "/api/v1/extender/filter/0",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "healthz isn't authenticated by default",
+			method:   http.MethodGet,
+			path:     "/healthz",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "metrics is authenticated if configured",
+			method:   http.MethodGet,
+			path:     "/metrics",
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantCode == http.StatusForbidden {
+				var body map[string]string
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				assert.NotEmpty(t, body["message"])
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewares_disabled(t *testing.T) {
+	t.Parallel()
+	api, healthz, metrics := authMiddlewares(nil)
+	assert.Empty(t, api)
+	assert.Empty(t, healthz)
+	assert.Empty(t, metrics)
+}
+
+func TestWatchAuthenticator(t *testing.T) {
+	t.Parallel()
+	authOpts := &auth.Options{Tokens: []auth.StaticToken{{Name: "viewer", Token: "view-token", Role: auth.RoleReadOnly}}}
+	tests := []struct {
+		name         string
+		cfg          *config.Config
+		identity     *auth.Identity
+		token        string
+		wantNil      bool
+		wantIdentity string
+		wantErr      bool
+	}{
+		{
+			name:    "not authenticated if nothing is configured",
+			cfg:     &config.Config{},
+			wantNil: true,
+		},
+		{
+			name:         "identified as the client authenticated by the middleware",
+			cfg:          &config.Config{Auth: authOpts},
+			identity:     &auth.Identity{Name: "viewer", Role: auth.RoleReadOnly},
+			wantIdentity: "viewer",
+		},
+		{
+			name:    "rejected if the middleware hasn't authenticated the client",
+			cfg:     &config.Config{Auth: authOpts},
+			token:   "view-token",
+			wantErr: true,
+		},
+		{
+			name:    "watcherBearerToken is ignored if auth is configured",
+			cfg:     &config.Config{Auth: authOpts, WatcherBearerToken: "secret"},
+			token:   "secret",
+			wantErr: true,
+		},
+		{
+			name:         "accepted with watcherBearerToken",
+			cfg:          &config.Config{WatcherBearerToken: "secret"},
+			token:        "secret",
+			wantIdentity: watcherBearerTokenName,
+		},
+		{
+			name:    "rejected with the wrong token",
+			cfg:     &config.Config{WatcherBearerToken: "secret"},
+			token:   "wrong",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dic, _, _ := di.NewTestContainer(t)
+			authenticate := watchAuthenticator(tt.cfg, dic)
+			if tt.wantNil {
+				assert.Nil(t, authenticate)
+				return
+			}
+			require.NotNil(t, authenticate)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/listwatchresources", nil)
+			if tt.identity != nil {
+				req = req.WithContext(auth.WithIdentity(req.Context(), tt.identity))
+			}
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			got, err := authenticate(req)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIdentity, got)
+		})
+	}
+}
+
+func TestNewSimulatorServer_watchersAuthenticatedWithAuth(t *testing.T) {
+	t.Parallel()
+	dic, _, _ := di.NewTestContainer(t)
+	s, err := NewSimulatorServer(&config.Config{Auth: &auth.Options{
+		Tokens: []auth.StaticToken{{Name: "viewer", Token: "view-token", Role: auth.RoleReadOnly}},
+	}}, dic)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/watchers", nil)
+	req.Header.Set("Authorization", "Bearer view-token")
+	rec := httptest.NewRecorder()
+	s.e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	s.e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/watchers", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
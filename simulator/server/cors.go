@@ -0,0 +1,24 @@
+package server
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+)
+
+// corsMiddleware applies the CORS policy configured in cfg.
+// It answers the preflight requests to any route, even the ones without the handlers for OPTIONS,
+// before the requests are authenticated.
+func corsMiddleware(cfg *config.Config) echo.MiddlewareFunc {
+	corsCfg := middleware.CORSConfig{
+		AllowOrigins:     cfg.CorsAllowedOriginList,
+		AllowMethods:     cfg.CorsAllowedMethods,
+		AllowCredentials: cfg.CorsAllowCredentials,
+	}
+	if len(cfg.CorsAllowedOriginList) == 0 {
+		// echo allows all the origins if AllowOrigins is empty, but we allow only the same origin then.
+		corsCfg.AllowOriginFunc = func(string) (bool, error) { return false, nil }
+	}
+	return middleware.CORSWithConfig(corsCfg)
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/auth"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+)
+
+func TestCorsMiddleware(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		cfg    *config.Config
+		method string
+		origin string
+		// wantAllowOrigin is the Access-Control-Allow-Origin header, empty if the origin isn't allowed.
+		wantAllowOrigin      string
+		wantAllowCredentials string
+		wantAllowMethods     string
+		wantCode             int
+	}{
+		{
+			name:                 "allowed origin",
+			cfg:                  &config.Config{CorsAllowedOriginList: []string{"http://localhost:3000"}, CorsAllowedMethods: []string{http.MethodGet}, CorsAllowCredentials: true},
+			method:               http.MethodGet,
+			origin:               "http://localhost:3000",
+			wantAllowOrigin:      "http://localhost:3000",
+			wantAllowCredentials: "true",
+			wantCode:             http.StatusOK,
+		},
+		{
+			name:     "disallowed origin",
+			cfg:      &config.Config{CorsAllowedOriginList: []string{"http://localhost:3000"}, CorsAllowedMethods: []string{http.MethodGet}, CorsAllowCredentials: true},
+			method:   http.MethodGet,
+			origin:   "http://evil.example.com",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "only the same origin is allowed by default",
+			cfg:      &config.Config{},
+			method:   http.MethodGet,
+			origin:   "http://localhost:3000",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:             "preflight from the allowed origin",
+			cfg:              &config.Config{CorsAllowedOriginList: []string{"http://portal.example.com"}, CorsAllowedMethods: []string{http.MethodGet, http.MethodPost}},
+			method:           http.MethodOptions,
+			origin:           "http://portal.example.com",
+			wantAllowOrigin:  "http://portal.example.com",
+			wantAllowMethods: "GET,POST",
+			wantCode:         http.StatusNoContent,
+		},
+		{
+			name:     "preflight from the disallowed origin",
+			cfg:      &config.Config{CorsAllowedOriginList: []string{"http://portal.example.com"}, CorsAllowedMethods: []string{http.MethodGet, http.MethodPost}},
+			method:   http.MethodOptions,
+			origin:   "http://evil.example.com",
+			wantCode: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := echo.New()
+			e.Use(corsMiddleware(tt.cfg))
+			// The preflight requests are answered without the token.
+			api, _, _ := authMiddlewares(&auth.Options{Tokens: []auth.StaticToken{{Name: "admin", Token: "token", Role: auth.RoleReadWrite}}})
+			e.Group("/api/v1", api...).POST("/reset", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+			e.GET("/healthz", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+			path := "/healthz"
+			if tt.method == http.MethodOptions {
+				path = "/api/v1/reset"
+			}
+			req := httptest.NewRequest(tt.method, path, nil)
+			req.Header.Set(echo.HeaderOrigin, tt.origin)
+			if tt.method == http.MethodOptions {
+				req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantAllowOrigin, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+			assert.Equal(t, tt.wantAllowCredentials, rec.Header().Get(echo.HeaderAccessControlAllowCredentials))
+			assert.Equal(t, tt.wantAllowMethods, rec.Header().Get(echo.HeaderAccessControlAllowMethods))
+		})
+	}
+}
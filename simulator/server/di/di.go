@@ -4,38 +4,111 @@
 package di
 
 import (
+	"context"
+	"errors"
+	"sync"
+
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"golang.org/x/xerrors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
 	configv1 "k8s.io/kube-scheduler/config/v1"
 
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/bulknode"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/bulkpod"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/configreload"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/diagnostics"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/etcdsnapshot"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/experiment"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/lifecycle"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/logging"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/nodefailure"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/oneshotimporter"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/oplog"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/podlifecycle"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/recorder"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/replayer"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/reschedule"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/reset"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcelist"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scenario"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/resulthistory"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/snapshot"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/stats"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/syncer"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/whatif"
+)
+
+// The names of the components whose lifecycles are managed through the API.
+const (
+	ComponentSyncer       = "syncer"
+	ComponentRecorder     = "recorder"
+	ComponentReplayer     = "replayer"
+	ComponentPodLifecycle = "podlifecycle"
 )
 
 // Container saves and provides dependencies.
 type Container struct {
 	schedulerService               SchedulerService
-	snapshotService                SnapshotService
+	resourceApplierService         ResourceApplierService
 	resetService                   ResetService
 	oneshotClusterResourceImporter OneShotClusterResourceImporter
 	resourceSyncer                 ResourceSyncer
-	resourceWatcherService         ResourceWatcherService
 	replayService                  ReplayService
+	recorderService                RecorderService
+	logService                     LogService
+	configReloadService            ConfigReloadService
+	components                     map[string]LifecycleComponent
+	livenessChecks                 []HealthCheck
+	etcdClient                     *clientv3.Client
+	extraHandlers                  []ExtraHandler
+	watchAuthenticator             WatchAuthenticator
+
+	// The services below are constructed when they're accessed first.
+	snapshotService          *lazy[SnapshotService]
+	resourceWatcherService   *lazy[ResourceWatcherService]
+	bulkPodService           *lazy[BulkPodService]
+	bulkNodeService          *lazy[BulkNodeService]
+	nodeFailureService       *lazy[NodeFailureService]
+	schedulingResultsService *lazy[SchedulingResultsService]
+	diagnosticsService       *lazy[DiagnosticsService]
+	statsService             *lazy[StatsService]
+	whatIfService            *lazy[WhatIfService]
+	resourceListService      *lazy[ResourceListService]
+	jobManager               *lazy[JobManager]
+	scenarioService          *lazy[ScenarioService]
+	rescheduleService        *lazy[RescheduleService]
+	etcdSnapshotService      *lazy[EtcdSnapshotService]
+	experimentService        *lazy[ExperimentService]
+
+	closeOnce sync.Once
+	closeErr  error
 }
 
 // NewDIContainer initializes Container.
-// It initializes all service and puts to Container.
-// Only when externalImportEnabled is true, the simulator uses externalClient and creates ImportClusterResourceService.
+// It initializes the services and puts to Container. The services which the simulator doesn't depend on to start,
+// e.g., the ones only used by the APIs, are constructed when they're accessed first.
+// Call Close to tear down the container.
+// Only when externalDynamicClient or importManifestsPath is given, the simulator creates OneShotClusterResourceImporter.
+// If both are given, importManifestsPath is used.
+// If recordEnabled is true, the recorder records the resources in the simulator through dynamicClient,
+// and it's expected to be started with the simulator. Otherwise, the recorder records the resources
+// in the target cluster, and can be started through the API only when externalDynamicClient is given.
+// EtcdSnapshotService is created only when etcdSnapshotDir is given.
+// The syncer and the recorder register the functions applying their options to configReloadService.
+// schedulerFeatureGates are set to the scheduler every time it's restarted.
+// simulatorAddress is the host:port which the scheduler sends the requests for the extenders to.
+// The syncer, the recorder, the replayer, the pod lifecycle simulator and the importer write the logs with the loggers of their components in loggers.
+// It's a shorthand for NewDIContainerWithOptions without any Option.
 func NewDIContainer(
 	client clientset.Interface,
 	dynamicClient dynamic.Interface,
@@ -43,37 +116,134 @@ func NewDIContainer(
 	etcdclient *clientv3.Client,
 	restclientCfg *restclient.Config,
 	initialSchedulerCfg *configv1.KubeSchedulerConfiguration,
-	externalImportEnabled bool,
+	schedulerFeatureGates map[string]bool,
+	resourceSyncEnabled bool,
+	replayEnabled bool,
+	recordEnabled bool,
+	externalDynamicClient dynamic.Interface,
+	importManifestsPath string,
+	etcdSnapshotDir string,
+	simulatorAddress string,
+	resourceapplierOptions resourceapplier.Options,
+	syncerOptions syncer.Options,
+	replayerOptions replayer.Options,
+	recorderOptions recorder.Options,
+	resourceWatcherOptions resourcewatcher.Options,
+	logBuffer *oplog.Buffer,
+	configReloadService *configreload.Service,
+	loggers *logging.Logging,
+) (*Container, error) {
+	return NewDIContainerWithOptions(client, dynamicClient, restMapper, etcdclient, restclientCfg, initialSchedulerCfg, schedulerFeatureGates, resourceSyncEnabled, replayEnabled, recordEnabled, externalDynamicClient, importManifestsPath, etcdSnapshotDir, simulatorAddress, resourceapplierOptions, syncerOptions, replayerOptions, recorderOptions, resourceWatcherOptions, logBuffer, configReloadService, loggers)
+}
+
+// NewDIContainerWithOptions is NewDIContainer customizing the services with opts,
+// e.g., to register the mutators of the resources synced by the syncer.
+func NewDIContainerWithOptions(
+	client clientset.Interface,
+	dynamicClient dynamic.Interface,
+	restMapper meta.RESTMapper,
+	etcdclient *clientv3.Client,
+	restclientCfg *restclient.Config,
+	initialSchedulerCfg *configv1.KubeSchedulerConfiguration,
+	schedulerFeatureGates map[string]bool,
 	resourceSyncEnabled bool,
 	replayEnabled bool,
+	recordEnabled bool,
 	externalDynamicClient dynamic.Interface,
-	simulatorPort int,
+	importManifestsPath string,
+	etcdSnapshotDir string,
+	simulatorAddress string,
 	resourceapplierOptions resourceapplier.Options,
+	syncerOptions syncer.Options,
 	replayerOptions replayer.Options,
+	recorderOptions recorder.Options,
+	resourceWatcherOptions resourcewatcher.Options,
+	logBuffer *oplog.Buffer,
+	configReloadService *configreload.Service,
+	loggers *logging.Logging,
+	opts ...Option,
 ) (*Container, error) {
-	c := &Container{}
-
-	// initializes each service
-	c.schedulerService = scheduler.NewSchedulerService(client, restclientCfg, initialSchedulerCfg, simulatorPort)
-	var err error
-	c.resetService, err = reset.NewResetService(etcdclient, client, c.schedulerService)
-	if err != nil {
-		return nil, xerrors.Errorf("initialize reset service: %w", err)
+	o := newContainerOptions(opts)
+	c := &Container{livenessChecks: newLivenessChecks(client, etcdclient), logService: logBuffer, configReloadService: configReloadService, etcdClient: etcdclient, extraHandlers: o.extraHandlers, watchAuthenticator: o.watchAuthenticator}
+
+	// initializes the services which the other services or the lifecycles of the simulator depend on.
+	c.schedulerService = scheduler.NewSchedulerService(client, restclientCfg, initialSchedulerCfg, simulatorAddress, schedulerFeatureGates, o.schedulerRandomSeed)
+	resourceApplierService := resourceapplier.New(dynamicClient, restMapper, o.applierOptions(resourceapplierOptions))
+	c.resourceApplierService = resourceApplierService
+	if etcdclient != nil {
+		var err error
+		c.resetService, err = reset.NewResetService(etcdclient, client, resourceApplierService, c.schedulerService)
+		if err != nil {
+			return nil, xerrors.Errorf("initialize reset service: %w", err)
+		}
 	}
-	snapshotSvc := snapshot.NewService(client, c.schedulerService)
-	c.snapshotService = snapshotSvc
-	resourceApplierService := resourceapplier.New(dynamicClient, restMapper, resourceapplierOptions)
-	if externalImportEnabled {
-		c.oneshotClusterResourceImporter = oneshotimporter.NewService(externalDynamicClient, resourceApplierService)
+	if importManifestsPath != "" {
+		c.oneshotClusterResourceImporter = oneshotimporter.NewFromManifests(importManifestsPath, resourceApplierService, loggers.ComponentLogger(oplog.ComponentImporter))
+	} else if externalDynamicClient != nil {
+		c.oneshotClusterResourceImporter = oneshotimporter.NewService(externalDynamicClient, resourceApplierService, c.schedulerService, loggers.ComponentLogger(oplog.ComponentImporter))
+	}
+	c.components = map[string]LifecycleComponent{
+		ComponentSyncer:       lifecycle.Disabled{},
+		ComponentRecorder:     lifecycle.Disabled{},
+		ComponentReplayer:     lifecycle.Disabled{},
+		ComponentPodLifecycle: lifecycle.Disabled{},
 	}
 	if resourceSyncEnabled {
-		c.resourceSyncer = syncer.New(externalDynamicClient, resourceApplierService)
+		resourceSyncer := o.newResourceSyncer(externalDynamicClient, dynamicClient, restMapper, resourceapplierOptions, resourceApplierService, syncerOptions, loggers.ComponentLogger(oplog.ComponentSyncer))
+		c.resourceSyncer = resourceSyncer
+		c.components[ComponentSyncer] = resourceSyncer
+		configReloadService.Register(config.FieldSyncer, func(cfg *config.Config) error {
+			resourceSyncer.ApplyConfig(o.syncerOptions(cfg.Syncer, resourceApplierService.GVRsToSync))
+			return nil
+		})
+	}
+	if resourceRecorder := newResourceRecorder(recordEnabled, dynamicClient, externalDynamicClient, recorderOptions, loggers.ComponentLogger(oplog.ComponentRecorder)); resourceRecorder != nil {
+		c.recorderService = resourceRecorder
+		c.components[ComponentRecorder] = resourceRecorder
+		configReloadService.Register(config.FieldRecorder, func(cfg *config.Config) error {
+			resourceRecorder.ApplyConfig(cfg.Recorder)
+			return nil
+		})
 	}
-	c.resourceWatcherService = resourcewatcher.NewService(client)
 	if replayEnabled {
-		c.replayService = replayer.New(resourceApplierService, replayerOptions)
+		replayService := replayer.New(resourceApplierService, replayerOptions, loggers.ComponentLogger(oplog.ComponentReplayer))
+		c.replayService = replayService
+		c.components[ComponentReplayer] = replayService
+	}
+	if o.podLifecycle != nil {
+		c.components[ComponentPodLifecycle] = podlifecycle.New(client, *o.podLifecycle, loggers.ComponentLogger(oplog.ComponentPodLifecycle))
 	}
 
+	// The services below are constructed lazily since they aren't used unless their APIs are called.
+	c.schedulingResultsService = newLazy(func() SchedulingResultsService {
+		return resulthistory.New(resulthistory.DefaultPodCapacity, resulthistory.DefaultHistoryLimit, resulthistory.WithRandomSeed(o.schedulerRandomSeed))
+	})
+	c.diagnosticsService = newLazy(func() DiagnosticsService { return diagnostics.NewService(client) })
+	c.statsService = newLazy(func() StatsService { return stats.NewService(client) })
+	c.whatIfService = newLazy(func() WhatIfService { return whatif.NewService(client, c.schedulerService) })
+	c.bulkPodService = newLazy(func() BulkPodService { return bulkpod.NewService(resourceApplierService) })
+	c.bulkNodeService = newLazy(func() BulkNodeService { return bulknode.NewService(resourceApplierService) })
+	c.nodeFailureService = newLazy(func() NodeFailureService { return nodefailure.NewService(client) })
+	c.experimentService = newLazy(func() ExperimentService { return experiment.NewService(client, resourceApplierService) })
+	c.snapshotService = newLazy(func() SnapshotService { return snapshot.NewService(client, c.schedulerService) })
+	c.resourceListService = newLazy(func() ResourceListService { return resourcelist.NewService(dynamicClient, restMapper) })
+	c.jobManager = newLazy(func() JobManager { return job.NewManager(job.Options{ExclusiveTypes: job.DefaultExclusiveTypes}) })
+	c.scenarioService = newLazy(func() ScenarioService {
+		return scenario.NewService(scenario.NewExecutor(client, resourceApplierService, c.schedulerService, scenario.Options{}), c.jobManager.get())
+	})
+	c.rescheduleService = newLazy(func() RescheduleService {
+		return reschedule.NewService(client, c.jobManager.get(), reschedule.Options{})
+	})
+	c.resourceWatcherService = newLazy(func() ResourceWatcherService {
+		return resourcewatcher.NewService(client, dynamicClient, restMapper, resourceWatcherOptions)
+	})
+	c.etcdSnapshotService = newLazy(func() EtcdSnapshotService {
+		if etcdSnapshotDir == "" {
+			return nil
+		}
+		return etcdsnapshot.NewService(etcdSnapshotDir, etcdclient, c.schedulerService, c.resourceWatcherService.get(), c.jobManager.get())
+	})
+
 	return c, nil
 }
 
@@ -84,16 +254,17 @@ func (c *Container) SchedulerService() SchedulerService {
 
 // ExportService returns ExportService.
 func (c *Container) ExportService() SnapshotService {
-	return c.snapshotService
+	return c.snapshotService.get()
 }
 
 // ResetService returns ResetService.
+// Note: this service will return nil when the etcd client isn't given, e.g., in the container built by NewTestContainer.
 func (c *Container) ResetService() ResetService {
 	return c.resetService
 }
 
 // OneshotClusterResourceImporter returns OneshotClusterResourceImporter.
-// Note: this service will return nil when `externalImportEnabled` is false.
+// Note: this service will return nil when neither the kubeconfig for the target cluster nor importManifestsPath is configured.
 func (c *Container) OneshotClusterResourceImporter() OneShotClusterResourceImporter {
 	return c.oneshotClusterResourceImporter
 }
@@ -108,12 +279,202 @@ func (c *Container) ReplayService() ReplayService {
 	return c.replayService
 }
 
+// RecorderService returns RecorderService.
+// Note: this service will return nil when neither recordEnabled nor the kubeconfig for the target cluster is configured.
+func (c *Container) RecorderService() RecorderService {
+	return c.recorderService
+}
+
+// BulkPodService returns BulkPodService.
+func (c *Container) BulkPodService() BulkPodService {
+	return c.bulkPodService.get()
+}
+
+// BulkNodeService returns BulkNodeService.
+func (c *Container) BulkNodeService() BulkNodeService {
+	return c.bulkNodeService.get()
+}
+
+// NodeFailureService returns NodeFailureService.
+func (c *Container) NodeFailureService() NodeFailureService {
+	return c.nodeFailureService.get()
+}
+
+// SchedulingResultsService returns SchedulingResultsService.
+func (c *Container) SchedulingResultsService() SchedulingResultsService {
+	return c.schedulingResultsService.get()
+}
+
+// DiagnosticsService returns DiagnosticsService.
+func (c *Container) DiagnosticsService() DiagnosticsService {
+	return c.diagnosticsService.get()
+}
+
+// ResourceApplierService returns ResourceApplierService.
+func (c *Container) ResourceApplierService() ResourceApplierService {
+	return c.resourceApplierService
+}
+
+// StatsService returns StatsService.
+func (c *Container) StatsService() StatsService {
+	return c.statsService.get()
+}
+
+// WhatIfService returns WhatIfService.
+func (c *Container) WhatIfService() WhatIfService {
+	return c.whatIfService.get()
+}
+
+// ResourceListService returns ResourceListService.
+func (c *Container) ResourceListService() ResourceListService {
+	return c.resourceListService.get()
+}
+
+// JobManager returns JobManager.
+func (c *Container) JobManager() JobManager {
+	return c.jobManager.get()
+}
+
+// ExperimentService returns ExperimentService.
+func (c *Container) ExperimentService() ExperimentService {
+	return c.experimentService.get()
+}
+
+// ScenarioService returns ScenarioService.
+func (c *Container) ScenarioService() ScenarioService {
+	return c.scenarioService.get()
+}
+
+// RescheduleService returns RescheduleService.
+func (c *Container) RescheduleService() RescheduleService {
+	return c.rescheduleService.get()
+}
+
+// EtcdSnapshotService returns EtcdSnapshotService.
+// It returns nil when etcdSnapshotDir isn't configured.
+func (c *Container) EtcdSnapshotService() EtcdSnapshotService {
+	return c.etcdSnapshotService.get()
+}
+
+// LogService returns LogService.
+func (c *Container) LogService() LogService {
+	return c.logService
+}
+
+// ConfigReloadService returns ConfigReloadService.
+func (c *Container) ConfigReloadService() ConfigReloadService {
+	return c.configReloadService
+}
+
+// Components returns the components whose lifecycles are managed through the API, keyed by the names.
+// The components not configured in the simulator are disabled.
+func (c *Container) Components() map[string]LifecycleComponent {
+	return c.components
+}
+
+// ExtraHandlers returns the routes given by WithExtraHandler, which the simulator server serves in addition to the built-in routes.
+func (c *Container) ExtraHandlers() []ExtraHandler {
+	return c.extraHandlers
+}
+
+// WatchAuthenticator returns the authenticator of the connections to watch resources given by WithWatchAuthenticator.
+// It returns nil if it's not given, and then the built-in one is used.
+func (c *Container) WatchAuthenticator() WatchAuthenticator {
+	return c.watchAuthenticator
+}
+
 // ResourceWatcherService returns ResourceWatcherService.
 func (c *Container) ResourceWatcherService() ResourceWatcherService {
-	return c.resourceWatcherService
+	return c.resourceWatcherService.get()
 }
 
 // ExtenderService returns ExtenderService.
 func (c *Container) ExtenderService() ExtenderService {
 	return c.schedulerService.ExtenderService()
 }
+
+// ExtenderInjectionService returns ExtenderInjectionService.
+// It returns nil if ExtenderService doesn't support the injections, e.g., when no extender is proxied.
+func (c *Container) ExtenderInjectionService() ExtenderInjectionService {
+	s, _ := c.ExtenderService().(ExtenderInjectionService)
+	return s
+}
+
+// componentShutdownOrder is the order to stop the components in.
+// The syncer and the pod lifecycle simulator are stopped before the recorder
+// so that the events caused by them are recorded until they stop.
+var componentShutdownOrder = []string{ComponentSyncer, ComponentPodLifecycle, ComponentRecorder, ComponentReplayer}
+
+// Shutdown stops the background jobs started by the services, e.g., the import started on demand.
+// All of them are stopped even if some fail to stop, and the errors are aggregated.
+func (c *Container) Shutdown(ctx context.Context) error {
+	var errs []error
+	if jobManager, ok := c.jobManager.ifConstructed(); ok {
+		if err := jobManager.Shutdown(ctx); err != nil {
+			errs = append(errs, xerrors.Errorf("shutdown job manager: %w", err))
+		}
+	}
+	if c.oneshotClusterResourceImporter != nil {
+		if err := c.oneshotClusterResourceImporter.Shutdown(ctx); err != nil {
+			errs = append(errs, xerrors.Errorf("shutdown importer: %w", err))
+		}
+	}
+	for _, name := range componentShutdownOrder {
+		component, ok := c.components[name]
+		if !ok {
+			continue
+		}
+		switch component.Status().State {
+		case lifecycle.StateStarting, lifecycle.StateRunning, lifecycle.StatePaused:
+			// It may have been stopped after Status.
+			if err := component.Stop(); err != nil && !errors.Is(err, lifecycle.ErrIllegalTransition) {
+				errs = append(errs, xerrors.Errorf("stop %s: %w", name, err))
+			}
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// Close tears down the container in the reverse order of the dependencies:
+// the clients watching resources are disconnected, the background jobs and the components are stopped by Shutdown,
+// and then the scheduler is shut down and the etcd client is closed.
+// The services never accessed aren't constructed to be torn down.
+// It's safe to call Close more than once, and the calls after the first one return the same error.
+func (c *Container) Close(ctx context.Context) error {
+	c.closeOnce.Do(func() {
+		var errs []error
+		if watcherService, ok := c.resourceWatcherService.ifConstructed(); ok {
+			for _, w := range watcherService.Watchers() {
+				// The client may have been disconnected by itself after Watchers.
+				if err := watcherService.Disconnect(w.ID); err != nil && !errors.Is(err, resourcewatcher.ErrWatcherNotFound) {
+					errs = append(errs, xerrors.Errorf("disconnect watcher %s: %w", w.ID, err))
+				}
+			}
+		}
+		if err := c.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		c.schedulerService.ShutdownScheduler()
+		if c.etcdClient != nil {
+			if err := c.etcdClient.Close(); err != nil {
+				errs = append(errs, xerrors.Errorf("close etcd client: %w", err))
+			}
+		}
+		c.closeErr = utilerrors.NewAggregate(errs)
+	})
+	return c.closeErr
+}
+
+// newResourceRecorder builds the recorder recording the resources in the simulator through dynamicClient if recordEnabled is true,
+// or the ones in the target cluster through externalDynamicClient otherwise.
+// It returns nil if there's nothing to record.
+func newResourceRecorder(recordEnabled bool, dynamicClient, externalDynamicClient dynamic.Interface, opts recorder.Options, logger klog.Logger) *recorder.Service {
+	switch {
+	case recordEnabled:
+		return recorder.New(dynamicClient, opts, logger)
+	case externalDynamicClient != nil:
+		return recorder.New(externalDynamicClient, opts, logger)
+	default:
+		return nil
+	}
+}
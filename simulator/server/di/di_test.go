@@ -0,0 +1,205 @@
+package di
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/lifecycle"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/recorder"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
+)
+
+func Test_newResourceRecorder(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name               string
+		recordEnabled      bool
+		withExternalClient bool
+		wantNil            bool
+		wantRecordedPod    string
+	}{
+		{
+			name:            "recordEnabled records the resources in the simulator",
+			recordEnabled:   true,
+			wantRecordedPod: "simulator-pod",
+		},
+		{
+			name:               "recordEnabled takes precedence over the target cluster",
+			recordEnabled:      true,
+			withExternalClient: true,
+			wantRecordedPod:    "simulator-pod",
+		},
+		{
+			name:               "the recorder records the resources in the target cluster without recordEnabled",
+			withExternalClient: true,
+			wantRecordedPod:    "external-pod",
+		},
+		{
+			name:    "no recorder without recordEnabled and the target cluster",
+			wantNil: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := runtime.NewScheme()
+			require.NoError(t, v1.AddToScheme(s))
+			dynamicClient := dynamicFake.NewSimpleDynamicClient(s, newPod("simulator-pod"))
+			var externalDynamicClient dynamic.Interface
+			if tt.withExternalClient {
+				externalDynamicClient = dynamicFake.NewSimpleDynamicClient(s, newPod("external-pod"))
+			}
+			path := filepath.Join(t.TempDir(), "record.jsonl")
+			opts := recorder.Options{
+				GVRs:          []schema.GroupVersionResource{podsGVR},
+				RecordFile:    path,
+				FlushInterval: ptr.To(10 * time.Millisecond),
+			}
+
+			resourceRecorder := newResourceRecorder(tt.recordEnabled, dynamicClient, externalDynamicClient, opts, klog.Background())
+			if tt.wantNil {
+				assert.Nil(t, resourceRecorder)
+				return
+			}
+			require.NotNil(t, resourceRecorder)
+			require.NoError(t, resourceRecorder.Start(nil))
+			t.Cleanup(func() { _ = resourceRecorder.Stop() })
+
+			var recorded string
+			require.Eventually(t, func() bool {
+				data, err := os.ReadFile(path)
+				recorded = string(data)
+				return err == nil && len(data) != 0
+			}, wait.ForeverTestTimeout, 10*time.Millisecond)
+			assert.Contains(t, recorded, tt.wantRecordedPod)
+			if tt.recordEnabled {
+				assert.NotContains(t, recorded, "external-pod")
+			}
+		})
+	}
+}
+
+// newFakeComponent returns the component running until it's stopped.
+func newFakeComponent() *lifecycle.Controller {
+	return lifecycle.NewController(func(_ json.RawMessage) (lifecycle.RunFunc, error) {
+		return func(ctx context.Context, ready func()) error {
+			ready()
+			<-ctx.Done()
+			return nil
+		}, nil
+	})
+}
+
+func TestContainer_Close(t *testing.T) {
+	t.Parallel()
+	syncer := newFakeComponent()
+	require.NoError(t, syncer.Start(nil))
+	require.Eventually(t, func() bool { return syncer.Status().State == lifecycle.StateRunning }, wait.ForeverTestTimeout, 10*time.Millisecond)
+
+	var jobManagerConstructed, watcherConstructed bool
+	c := &Container{
+		schedulerService: scheduler.NewSchedulerService(fake.NewSimpleClientset(), nil, nil, "", nil, nil),
+		components: map[string]LifecycleComponent{
+			ComponentSyncer:   syncer,
+			ComponentRecorder: lifecycle.Disabled{},
+			ComponentReplayer: lifecycle.Disabled{},
+		},
+		jobManager: newLazy(func() JobManager {
+			jobManagerConstructed = true
+			return job.NewManager(job.Options{})
+		}),
+		resourceWatcherService: newLazy(func() ResourceWatcherService {
+			watcherConstructed = true
+			return resourcewatcher.NewService(fake.NewSimpleClientset(), nil, nil, resourcewatcher.Options{})
+		}),
+	}
+
+	require.NoError(t, c.Close(context.Background()))
+	assert.Equal(t, lifecycle.StateStopped, syncer.Status().State, "the started component is stopped")
+	assert.False(t, jobManagerConstructed, "the services never accessed aren't constructed to be closed")
+	assert.False(t, watcherConstructed, "the services never accessed aren't constructed to be closed")
+	assert.NoError(t, c.Close(context.Background()), "Close can be called again")
+}
+
+// failingComponent is the running component failing to stop.
+type failingComponent struct {
+	lifecycle.Disabled
+	stopErr error
+}
+
+func (f *failingComponent) Status() lifecycle.Status {
+	return lifecycle.Status{State: lifecycle.StateRunning}
+}
+
+func (f *failingComponent) Stop() error {
+	return f.stopErr
+}
+
+func TestContainer_Shutdown(t *testing.T) {
+	t.Parallel()
+	recorder := newFakeComponent()
+	require.NoError(t, recorder.Start(nil))
+	require.Eventually(t, func() bool { return recorder.Status().State == lifecycle.StateRunning }, wait.ForeverTestTimeout, 10*time.Millisecond)
+	syncerErr := errors.New("syncer is stuck")
+	replayerErr := errors.New("replayer is stuck")
+	c := &Container{
+		components: map[string]LifecycleComponent{
+			ComponentSyncer:   &failingComponent{stopErr: syncerErr},
+			ComponentRecorder: recorder,
+			ComponentReplayer: &failingComponent{stopErr: replayerErr},
+		},
+		jobManager: newLazy(func() JobManager { return job.NewManager(job.Options{}) }),
+	}
+
+	err := c.Shutdown(context.Background())
+	assert.ErrorIs(t, err, syncerErr)
+	assert.ErrorIs(t, err, replayerErr)
+	assert.Equal(t, lifecycle.StateStopped, recorder.Status().State, "the components after the failed one are stopped as well")
+}
+
+func TestNewTestContainer(t *testing.T) {
+	t.Parallel()
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "default"}}
+	c, client, dynamicClient := NewTestContainer(t, pod)
+	ctx := context.Background()
+
+	// The clientset and the dynamic client share the objects.
+	got, err := dynamicClient.Resource(podsGVR).Namespace("default").Get(ctx, "pod-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "pod-1", got.GetName())
+	results, err := c.ResourceApplierService().ApplyAll(ctx, []unstructured.Unstructured{*newPod("pod-2")}, resourceapplier.ApplyAllOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+	created, err := client.CoreV1().Pods("default").Get(ctx, "pod-2", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "container", created.Spec.Containers[0].Name)
+
+	for _, check := range c.LivenessChecks() {
+		assert.NoError(t, check.Check(ctx), check.Name)
+	}
+	assert.Nil(t, c.ResetService())
+}
@@ -0,0 +1,70 @@
+package di
+
+import (
+	"context"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"golang.org/x/xerrors"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+// HealthCheck is a named check of a component, which is served at /healthz or /readyz.
+type HealthCheck struct {
+	Name string
+	// Check returns an error if the component isn't healthy.
+	Check func(ctx context.Context) error
+}
+
+// LivenessChecks returns the checks whether the simulator is alive, served at /healthz.
+func (c *Container) LivenessChecks() []HealthCheck {
+	return c.livenessChecks
+}
+
+// ReadinessChecks returns the checks whether the simulator is ready to serve the requests, served at /readyz.
+// It includes the liveness checks.
+func (c *Container) ReadinessChecks() []HealthCheck {
+	checks := append([]HealthCheck{}, c.livenessChecks...)
+	checks = append(checks, HealthCheck{Name: "scheduler", Check: func(_ context.Context) error {
+		return c.schedulerService.Healthz()
+	}})
+	if c.oneshotClusterResourceImporter != nil {
+		checks = append(checks, HealthCheck{Name: "import", Check: func(_ context.Context) error {
+			return c.oneshotClusterResourceImporter.Healthz()
+		}})
+	}
+	if c.resourceSyncer != nil {
+		checks = append(checks, HealthCheck{Name: "sync", Check: func(_ context.Context) error {
+			return c.resourceSyncer.Healthz()
+		}})
+	}
+	return checks
+}
+
+// newLivenessChecks returns the checks of the etcd and the kube-apiserver which the simulator depends on.
+// The check of etcd is omitted if etcdclient is nil, e.g., in the container built by NewTestContainer.
+func newLivenessChecks(client clientset.Interface, etcdclient *clientv3.Client) []HealthCheck {
+	var checks []HealthCheck
+	if etcdclient != nil {
+		checks = append(checks, HealthCheck{Name: "etcd", Check: func(ctx context.Context) error {
+			// It's the same as `etcdctl endpoint health` does.
+			if _, err := etcdclient.Get(ctx, "health"); err != nil {
+				return xerrors.Errorf("get from etcd: %w", err)
+			}
+			return nil
+		}})
+	}
+	return append(checks, HealthCheck{Name: "kube-apiserver", Check: func(ctx context.Context) error {
+		restClient := client.Discovery().RESTClient()
+		if restClient == nil {
+			// The fake clientset has no REST client, and only serves the version.
+			if _, err := client.Discovery().ServerVersion(); err != nil {
+				return xerrors.Errorf("get the version of kube-apiserver: %w", err)
+			}
+			return nil
+		}
+		if err := restClient.Get().AbsPath("/readyz").Do(ctx).Error(); err != nil {
+			return xerrors.Errorf("call /readyz of kube-apiserver: %w", err)
+		}
+		return nil
+	}})
+}
@@ -1,26 +1,93 @@
 package di
 
+//go:generate mockgen -destination=./mock_$GOPACKAGE/importer.go . OneShotClusterResourceImporter
+//go:generate mockgen -destination=./mock_$GOPACKAGE/component.go . LifecycleComponent
+//go:generate mockgen -destination=./mock_$GOPACKAGE/reset.go . ResetService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/scheduler.go . SchedulerService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/snapshot.go . SnapshotService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/syncer.go . ResourceSyncer
+//go:generate mockgen -destination=./mock_$GOPACKAGE/recorder.go . RecorderService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/replay.go . ReplayService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/bulkpod.go . BulkPodService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/bulknode.go . BulkNodeService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/resourceapplier.go . ResourceApplierService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/schedulingresults.go . SchedulingResultsService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/diagnostics.go . DiagnosticsService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/nodefailure.go . NodeFailureService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/stats.go . StatsService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/whatif.go . WhatIfService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/job.go . JobManager
+//go:generate mockgen -destination=./mock_$GOPACKAGE/scenario.go . ScenarioService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/reschedule.go . RescheduleService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/experiment.go . ExperimentService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/etcdsnapshot.go . EtcdSnapshotService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/log.go . LogService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/configreload.go . ConfigReloadService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/resourcelist.go . ResourceListService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/resourcewatcher.go . ResourceWatcherService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/extender.go . ExtenderService
+//go:generate mockgen -destination=./mock_$GOPACKAGE/extenderinjection.go . ExtenderInjectionService
+
 import (
 	"context"
+	"encoding/json"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientset "k8s.io/client-go/kubernetes"
 	configv1 "k8s.io/kube-scheduler/config/v1"
 	extenderv1 "k8s.io/kube-scheduler/extender/v1"
 
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/bulknode"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/bulkpod"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/configreload"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/diagnostics"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/etcdsnapshot"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/experiment"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/lifecycle"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/nodefailure"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/oneshotimporter"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/oplog"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/reset"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcelist"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scenario"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/resulthistory"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/snapshot"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/stats"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/whatif"
 )
 
 // SchedulerService represents service for manage scheduler.
 type SchedulerService interface {
 	GetSchedulerConfig() (*configv1.KubeSchedulerConfiguration, error)
 	SetSchedulerConfig(cfg *configv1.KubeSchedulerConfiguration)
+	// RestartScheduler applies cfg, or rolls back to the previous config if the scheduler fails to start with cfg.
 	RestartScheduler(cfg *configv1.KubeSchedulerConfiguration) error
+	// ActiveSchedulerConfig returns the config the scheduler is running with and its generation.
+	ActiveSchedulerConfig() (*scheduler.ActiveSchedulerConfig, error)
+	// ValidateSchedulerConfig returns the invalid fields of cfg without applying it.
+	ValidateSchedulerConfig(cfg *configv1.KubeSchedulerConfiguration) (field.ErrorList, error)
 	ResetScheduler() error
 	ShutdownScheduler()
 	ExtenderService() scheduler.ExtenderService
+	// Healthz returns an error if the scheduler isn't ready.
+	Healthz() error
+	// SchedulingQueue returns the Pods waiting to be scheduled, grouped by the queue they're in.
+	SchedulingQueue(ctx context.Context) (*scheduler.SchedulingQueue, error)
+	// StartSecondaryScheduler starts another scheduler with cfg, which schedules the Pods whose schedulerName is name.
+	StartSecondaryScheduler(cfg *configv1.KubeSchedulerConfiguration, name string) error
+	// StopSecondaryScheduler stops the secondary scheduler with name.
+	StopSecondaryScheduler(name string) error
+	// SecondarySchedulers returns the running secondary schedulers.
+	SecondarySchedulers() []scheduler.SecondaryScheduler
 }
 
 // SnapshotService represents a service for exporting/importing resources on the simulator.
@@ -28,15 +95,29 @@ type SnapshotService interface {
 	Snap(ctx context.Context, opts ...snapshot.Option) (*snapshot.ResourcesForSnap, error)
 	Load(ctx context.Context, resources *snapshot.ResourcesForLoad, opts ...snapshot.Option) error
 	IgnoreErr() snapshot.Option
+	Sanitize() snapshot.Option
 }
 
 type ResetService interface {
 	Reset(ctx context.Context) error
+	// Clean deletes the resources instead of restoring the initial ones.
+	Clean(ctx context.Context, opts reset.CleanOptions) (*reset.CleanSummary, error)
 }
 
-// OneShotClusterResourceImporter represents a service to import resources from a target cluster when starting the simulator.
+// OneShotClusterResourceImporter represents a service to import resources from a target cluster
+// when starting the simulator or on demand.
 type OneShotClusterResourceImporter interface {
-	ImportClusterResources(ctx context.Context, labelSelector metav1.LabelSelector) error
+	ImportClusterResources(ctx context.Context, opts oneshotimporter.ImportOptions) (*oneshotimporter.ImportSummary, error)
+	// StartImport starts importing resources in background.
+	StartImport(opts oneshotimporter.ImportOptions) error
+	// ImportStatus returns the status of the latest import.
+	ImportStatus() oneshotimporter.ImportStatus
+	// Diff compares the resources in the target cluster and the ones in the simulator.
+	Diff(ctx context.Context) (*oneshotimporter.DiffReport, error)
+	// Healthz returns an error while importing resources.
+	Healthz() error
+	// Shutdown cancels the import started by StartImport and waits for it to finish.
+	Shutdown(ctx context.Context) error
 }
 
 // ResourceSyncer represents a service to constantly sync resources from a target cluster.
@@ -44,6 +125,8 @@ type ResourceSyncer interface {
 	// Run starts the resource syncer.
 	// It should be run until the context is canceled.
 	Run(ctx context.Context) error
+	// Healthz returns an error until the resources are synced first.
+	Healthz() error
 }
 
 // RecorderService represents a service to record events in a target cluster.
@@ -60,9 +143,143 @@ type ReplayService interface {
 	Replay(ctx context.Context) error
 }
 
+// LifecycleComponent represents a long-running component, e.g., the syncer, whose lifecycle is managed through the API.
+type LifecycleComponent interface {
+	Status() lifecycle.Status
+	// Start starts the component with the options in background.
+	Start(options json.RawMessage) error
+	// Stop stops the component and waits for it to stop.
+	Stop() error
+	Pause() error
+	Resume() error
+}
+
+// BulkPodService represents a service to create Pods in bulk.
+type BulkPodService interface {
+	Create(ctx context.Context, opts bulkpod.CreateOptions) (*bulkpod.CreateSummary, error)
+}
+
+// BulkNodeService represents a service to create Nodes in bulk.
+type BulkNodeService interface {
+	Create(ctx context.Context, opts bulknode.CreateOptions) (*bulknode.CreateSummary, error)
+}
+
+// ResourceApplierService represents a service to apply the resources to the simulator.
+type ResourceApplierService interface {
+	ApplyAll(ctx context.Context, resources []unstructured.Unstructured, opts resourceapplier.ApplyAllOptions) ([]resourceapplier.Result, error)
+}
+
+// SchedulingResultsService represents a service to keep and query the scheduling results.
+type SchedulingResultsService interface {
+	// RegisterRecordingToInformer starts recording the results reflected on the Pods.
+	RegisterRecordingToInformer(client clientset.Interface, stopCh <-chan struct{}) error
+	Query(q resulthistory.Query) (*resulthistory.QueryResult, error)
+	// Latency returns the percentiles of the scheduling latencies of the Pods matching q.
+	Latency(q resulthistory.LatencyQuery) *resulthistory.LatencyStats
+}
+
+// DiagnosticsService represents a service to diagnose the resources in the simulator.
+type DiagnosticsService interface {
+	// UnschedulablePods reports why the Pending Pods aren't scheduled.
+	UnschedulablePods(ctx context.Context) (*diagnostics.UnschedulableReport, error)
+}
+
+// NodeFailureService represents a service to inject the failures of the nodes.
+type NodeFailureService interface {
+	// Fail makes the node NotReady and unschedulable, and evicts its Pods if it's requested.
+	Fail(ctx context.Context, name string, opts nodefailure.FailOptions) (*nodefailure.Result, error)
+	// Recover makes the node Ready and schedulable again.
+	Recover(ctx context.Context, name string) (*nodefailure.Result, error)
+}
+
+// StatsService represents a service to aggregate the scheduling state for the capacity planning.
+type StatsService interface {
+	// Start starts the informers which the stats are computed from.
+	Start(stopCh <-chan struct{}) error
+	Scheduling(q stats.Query) (*stats.SchedulingStats, error)
+}
+
+// WhatIfService represents a service to evaluate where a Pod would be scheduled without creating it.
+type WhatIfService interface {
+	// Start starts the informers which the snapshots are taken from.
+	Start(stopCh <-chan struct{}) error
+	Schedule(ctx context.Context, pod *corev1.Pod) (*whatif.Result, error)
+	// ScheduleBatch returns the packing plan of the Pods of items evaluated one by one.
+	ScheduleBatch(ctx context.Context, items []whatif.BatchItem) (*whatif.BatchResult, error)
+}
+
+// JobManager represents a service to run the long-running operations in background.
+type JobManager interface {
+	// Submit starts fn as the job of typ.
+	Submit(typ job.Type, fn job.Func) (*job.Job, error)
+	Get(id string) (*job.Job, error)
+	List() []job.Job
+	Cancel(id string) (*job.Job, error)
+	// Shutdown cancels all the jobs and waits for them to finish.
+	Shutdown(ctx context.Context) error
+}
+
+// ScenarioService represents a service to run the scenarios, i.e., the declarative multi-step experiments, in background.
+type ScenarioService interface {
+	// Submit starts running the scenario as a job, and returns its status without waiting for it to finish.
+	Submit(sc *scenario.Scenario) (*scenario.Status, error)
+	Get(id string) (*scenario.Status, error)
+}
+
+// RescheduleService represents a service to reschedule the scheduled Pods in background.
+type RescheduleService interface {
+	// Submit starts rescheduling the Pods as a job, and returns it without waiting for it to finish.
+	Submit() (*job.Job, error)
+}
+
+// ExperimentService represents a service to isolate the resources of the users sharing the simulator into the experiments.
+type ExperimentService interface {
+	// Create creates the namespaces of a new experiment.
+	Create(ctx context.Context, opts experiment.CreateOptions) (*experiment.Experiment, error)
+	Get(ctx context.Context, id string) (*experiment.Experiment, error)
+	List(ctx context.Context) ([]experiment.Experiment, error)
+	// Delete tears down the experiment, i.e., deletes all the resources labeled with the id.
+	Delete(ctx context.Context, id string) (*experiment.DeleteSummary, error)
+}
+
+// EtcdSnapshotService represents a service to save the whole state of the simulator in etcd to the files, and restore it.
+type EtcdSnapshotService interface {
+	Save(ctx context.Context, name string) (*etcdsnapshot.Info, error)
+	List() ([]etcdsnapshot.Info, error)
+	// Restore stops the scheduler and the watchers, replaces the state with the snapshot, and restarts them.
+	Restore(ctx context.Context, name string) error
+}
+
+// LogService represents a service to read the recent logs of the simulator.
+type LogService interface {
+	// Entries returns the latest limit entries selected by f, from the oldest one.
+	Entries(f oplog.Filter, limit int) []oplog.Entry
+	// Subscribe returns the subscription receiving the entries selected by f added from now on.
+	Subscribe(f oplog.Filter) *oplog.Subscription
+}
+
+// ConfigReloadService represents a service to reload the config file of the simulator.
+type ConfigReloadService interface {
+	// Register registers fn called when the field of config.Config is changed by Reload.
+	Register(field string, fn configreload.ApplyFunc)
+	// Reload reloads the config file, and applies the fields changed.
+	Reload(ctx context.Context) (*configreload.Result, error)
+}
+
+// ResourceListService represents a service to list the resources page by page.
+type ResourceListService interface {
+	List(ctx context.Context, opts resourcelist.ListOptions) (*resourcelist.ListResult, error)
+}
+
 // ResourceWatcherService represents service for watch k8s resources.
 type ResourceWatcherService interface {
-	ListWatch(ctx context.Context, stream streamwriter.ResponseStream, lrVersions *resourcewatcher.LastResourceVersions) error
+	ListWatch(ctx context.Context, stream streamwriter.ResponseStream, lrVersions *resourcewatcher.LastResourceVersions, opts resourcewatcher.WatchOptions) error
+	// ValidateGVRs returns an error if any of the GVRs can't be watched.
+	ValidateGVRs(gvrs []schema.GroupVersionResource) error
+	// Watchers returns the clients watching resources.
+	Watchers() []resourcewatcher.Watcher
+	// Disconnect disconnects the client watching resources forcibly.
+	Disconnect(id string) error
 }
 
 // ExtenderService represents service for the extender of scheduler.
@@ -72,3 +289,9 @@ type ExtenderService interface {
 	Preempt(id int, args extenderv1.ExtenderPreemptionArgs) (*extenderv1.ExtenderPreemptionResult, error)
 	Bind(id int, args extenderv1.ExtenderBindingArgs) (*extenderv1.ExtenderBindingResult, error)
 }
+
+// ExtenderInjectionService represents service to inject the latencies and the failures to the requests to extenders.
+type ExtenderInjectionService interface {
+	Injections() map[int]extender.Injection
+	SetInjection(id int, injection extender.Injection) error
+}
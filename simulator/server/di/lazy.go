@@ -0,0 +1,38 @@
+package di
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// lazy constructs a service with newFn when it's accessed first,
+// so that the services never used by the simulator aren't constructed.
+type lazy[T any] struct {
+	once        sync.Once
+	newFn       func() T
+	value       T
+	constructed atomic.Bool
+}
+
+func newLazy[T any](newFn func() T) *lazy[T] {
+	return &lazy[T]{newFn: newFn}
+}
+
+// get returns the service, constructing it on the first call.
+func (l *lazy[T]) get() T {
+	l.once.Do(func() {
+		l.value = l.newFn()
+		l.constructed.Store(true)
+	})
+	return l.value
+}
+
+// ifConstructed returns the service and true only if it has been constructed,
+// e.g., to tear it down without constructing it.
+func (l *lazy[T]) ifConstructed() (T, bool) {
+	if !l.constructed.Load() {
+		var zero T
+		return zero, false
+	}
+	return l.value, true
+}
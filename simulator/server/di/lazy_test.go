@@ -0,0 +1,41 @@
+package di
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_lazy(t *testing.T) {
+	t.Parallel()
+	var calls atomic.Int32
+	l := newLazy(func() *int {
+		calls.Add(1)
+		v := 1
+		return &v
+	})
+
+	_, ok := l.ifConstructed()
+	assert.False(t, ok)
+	assert.Equal(t, int32(0), calls.Load(), "the service isn't constructed until it's accessed")
+
+	var wg sync.WaitGroup
+	got := make([]*int, 10)
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i] = l.get()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load(), "the service is constructed only once")
+	for _, v := range got {
+		assert.Same(t, got[0], v)
+	}
+	constructed, ok := l.ifConstructed()
+	assert.True(t, ok)
+	assert.Same(t, got[0], constructed)
+}
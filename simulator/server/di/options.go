@@ -0,0 +1,187 @@
+package di
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/podlifecycle"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/syncer"
+)
+
+// Option customizes the services built by NewDIContainerWithOptions.
+// It's for the programs embedding the simulator.
+type Option func(opts *containerOptions)
+
+type containerOptions struct {
+	// applierMutators mutate the resources applied to the simulator by any service.
+	applierMutators map[schema.GroupVersionResource][]resourceapplier.MutatingFunction
+	// syncerMutators mutate the resources synced by the syncer only.
+	syncerMutators map[schema.GroupVersionResource][]resourceapplier.MutatingFunction
+	// additionalSyncGVRs are synced in addition to the resources configured for the syncer.
+	additionalSyncGVRs []schema.GroupVersionResource
+	// extraHandlers are served by the simulator server in addition to the built-in routes.
+	extraHandlers []ExtraHandler
+	// podLifecycle is the options of the pod lifecycle simulator. It's disabled if nil.
+	podLifecycle *podlifecycle.Options
+	// schedulerRandomSeed seeds the random numbers of the scheduler. It's not seeded if nil.
+	schedulerRandomSeed *int64
+	// watchAuthenticator authenticates the connections to watch resources instead of the built-in one if non-nil.
+	watchAuthenticator WatchAuthenticator
+}
+
+// WatchAuthenticator authenticates the connection to watch resources.
+// It returns the identity of the client, which is attached to the logs and the metrics of the connection,
+// or an error to reject the connection.
+type WatchAuthenticator func(r *http.Request) (identity string, err error)
+
+// ExtraHandler is a route served by the simulator server in addition to the built-in routes,
+// e.g., the API of the custom analytics of the program embedding the simulator.
+type ExtraHandler struct {
+	Method string
+	// Path is the path under /api/v1, e.g., "/analytics/:name".
+	// The route is served with the same middlewares as the built-in routes under /api/v1, e.g., the authentication.
+	Path    string
+	Handler echo.HandlerFunc
+}
+
+func newContainerOptions(opts []Option) *containerOptions {
+	o := &containerOptions{
+		applierMutators: map[schema.GroupVersionResource][]resourceapplier.MutatingFunction{},
+		syncerMutators:  map[schema.GroupVersionResource][]resourceapplier.MutatingFunction{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithApplierMutator creates an Option to mutate the resources of gvr before they're created or updated in the simulator,
+// by any service applying the resources, e.g., the importer, the syncer and the replayer.
+// The mutators given for the same gvr run in the order they're given, after the mutators of resourceapplier.Options.
+func WithApplierMutator(gvr schema.GroupVersionResource, fn resourceapplier.MutatingFunction) Option {
+	return func(opts *containerOptions) {
+		opts.applierMutators[gvr] = append(opts.applierMutators[gvr], fn)
+	}
+}
+
+// WithSyncerMutator creates an Option to mutate the resources of gvr synced by the syncer,
+// before they're created or updated in the simulator. It runs after the mutators given by WithApplierMutator.
+func WithSyncerMutator(gvr schema.GroupVersionResource, fn resourceapplier.MutatingFunction) Option {
+	return func(opts *containerOptions) {
+		opts.syncerMutators[gvr] = append(opts.syncerMutators[gvr], fn)
+	}
+}
+
+// WithAdditionalSyncGVRs creates an Option to make the syncer sync gvrs in addition to the resources configured for it,
+// e.g., the custom resources which the plugins of the scheduler refer to.
+// They're kept when the config of the syncer is reloaded.
+func WithAdditionalSyncGVRs(gvrs ...schema.GroupVersionResource) Option {
+	return func(opts *containerOptions) {
+		opts.additionalSyncGVRs = append(opts.additionalSyncGVRs, gvrs...)
+	}
+}
+
+// WithExtraHandler creates an Option to serve h at method and path under /api/v1 in addition to the built-in routes.
+// The simulator server fails to start if the route conflicts with the built-in ones or the other extra handlers.
+func WithExtraHandler(method, path string, h echo.HandlerFunc) Option {
+	return func(opts *containerOptions) {
+		opts.extraHandlers = append(opts.extraHandlers, ExtraHandler{Method: method, Path: path, Handler: h})
+	}
+}
+
+// WithPodLifecycle creates an Option to enable the pod lifecycle simulator with opts.
+// It acts as the kubelets of the nodes in the simulator, and it's expected to be started with the simulator.
+func WithPodLifecycle(opts podlifecycle.Options) Option {
+	return func(o *containerOptions) {
+		o.podLifecycle = &opts
+	}
+}
+
+// WithSchedulerRandomSeed creates an Option to seed the random numbers of the scheduler with seed
+// every time it's restarted, which makes the scheduler deterministic.
+// The seed is recorded in the scheduling results as well.
+func WithSchedulerRandomSeed(seed int64) Option {
+	return func(o *containerOptions) {
+		o.schedulerRandomSeed = &seed
+	}
+}
+
+// WithWatchAuthenticator creates an Option to authenticate the connections to watch resources with fn,
+// instead of the built-in authenticator configured in the simulator config.
+func WithWatchAuthenticator(fn WatchAuthenticator) Option {
+	return func(o *containerOptions) {
+		o.watchAuthenticator = fn
+	}
+}
+
+// applierOptions returns applierOpts with the mutators given by WithApplierMutator.
+func (o *containerOptions) applierOptions(applierOpts resourceapplier.Options) resourceapplier.Options {
+	applierOpts.MutateBeforeCreating = mergeMutators(applierOpts.MutateBeforeCreating, o.applierMutators)
+	applierOpts.MutateBeforeUpdating = mergeMutators(applierOpts.MutateBeforeUpdating, o.applierMutators)
+	return applierOpts
+}
+
+// syncerOptions returns syncerOpts with the resources given by WithAdditionalSyncGVRs.
+// applierGVRs is the resources applied by the resource applier, which the syncer syncs if syncerOpts.GVRs is nil.
+func (o *containerOptions) syncerOptions(syncerOpts syncer.Options, applierGVRs []schema.GroupVersionResource) syncer.Options {
+	if len(o.additionalSyncGVRs) == 0 {
+		return syncerOpts
+	}
+	base := syncerOpts.GVRs
+	if base == nil {
+		base = applierGVRs
+	}
+	if base == nil {
+		base = syncer.DefaultGVRs
+	}
+	gvrs := append([]schema.GroupVersionResource{}, base...)
+	for _, gvr := range o.additionalSyncGVRs {
+		if !slices.Contains(gvrs, gvr) {
+			gvrs = append(gvrs, gvr)
+		}
+	}
+	syncerOpts.GVRs = gvrs
+	return syncerOpts
+}
+
+// newResourceSyncer builds the syncer syncing the resources from externalDynamicClient to dynamicClient.
+// The syncer has its own resource applier if any mutator is given by WithSyncerMutator,
+// so that they don't affect the resources applied by the other services.
+func (o *containerOptions) newResourceSyncer(
+	externalDynamicClient, dynamicClient dynamic.Interface,
+	restMapper meta.RESTMapper,
+	applierOpts resourceapplier.Options,
+	applier *resourceapplier.Service,
+	syncerOpts syncer.Options,
+	logger klog.Logger,
+) *syncer.Service {
+	if len(o.syncerMutators) != 0 {
+		applierOpts = o.applierOptions(applierOpts)
+		applierOpts.MutateBeforeCreating = mergeMutators(applierOpts.MutateBeforeCreating, o.syncerMutators)
+		applierOpts.MutateBeforeUpdating = mergeMutators(applierOpts.MutateBeforeUpdating, o.syncerMutators)
+		applier = resourceapplier.New(dynamicClient, restMapper, applierOpts)
+	}
+	return syncer.New(externalDynamicClient, applier, o.syncerOptions(syncerOpts, applier.GVRsToSync), logger)
+}
+
+// mergeMutators returns the new map having the mutators in base followed by the ones in additional for each GVR.
+func mergeMutators(base, additional map[schema.GroupVersionResource][]resourceapplier.MutatingFunction) map[schema.GroupVersionResource][]resourceapplier.MutatingFunction {
+	if len(additional) == 0 {
+		return base
+	}
+	merged := map[schema.GroupVersionResource][]resourceapplier.MutatingFunction{}
+	for gvr, fns := range base {
+		merged[gvr] = append(merged[gvr], fns...)
+	}
+	for gvr, fns := range additional {
+		merged[gvr] = append(merged[gvr], fns...)
+	}
+	return merged
+}
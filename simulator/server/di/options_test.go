@@ -0,0 +1,173 @@
+package di
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/klog/v2"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/lifecycle"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/podlifecycle"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/syncer"
+)
+
+var (
+	podsGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	cmGVR   = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+)
+
+// labelMutator returns the mutator adding the label key=value.
+func labelMutator(key, value string) resourceapplier.MutatingFunction {
+	return func(_ context.Context, resource *unstructured.Unstructured, _ *resourceapplier.Clients) (*unstructured.Unstructured, error) {
+		labels := resource.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[key] = value
+		resource.SetLabels(labels)
+		return resource, nil
+	}
+}
+
+func newPod(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"spec":       map[string]interface{}{"containers": []interface{}{map[string]interface{}{"name": "container"}}},
+	}}
+}
+
+func Test_containerOptions_syncerOptions(t *testing.T) {
+	t.Parallel()
+	nodesGVR := schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+	tests := []struct {
+		name        string
+		opts        []Option
+		syncerOpts  syncer.Options
+		applierGVRs []schema.GroupVersionResource
+		want        []schema.GroupVersionResource
+	}{
+		{
+			name:       "without the additional resources",
+			syncerOpts: syncer.Options{LabelSelector: "env=staging"},
+			want:       nil,
+		},
+		{
+			name:       "the additional resources follow the ones of the syncer",
+			opts:       []Option{WithAdditionalSyncGVRs(cmGVR, podsGVR)},
+			syncerOpts: syncer.Options{GVRs: []schema.GroupVersionResource{podsGVR}},
+			want:       []schema.GroupVersionResource{podsGVR, cmGVR},
+		},
+		{
+			name:        "the additional resources follow the ones of the resource applier",
+			opts:        []Option{WithAdditionalSyncGVRs(cmGVR)},
+			applierGVRs: []schema.GroupVersionResource{nodesGVR},
+			want:        []schema.GroupVersionResource{nodesGVR, cmGVR},
+		},
+		{
+			name: "the additional resources follow the default resources",
+			opts: []Option{WithAdditionalSyncGVRs(cmGVR)},
+			want: append(append([]schema.GroupVersionResource{}, syncer.DefaultGVRs...), cmGVR),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := newContainerOptions(tt.opts).syncerOptions(tt.syncerOpts, tt.applierGVRs)
+			assert.Equal(t, tt.want, got.GVRs)
+			assert.Equal(t, tt.syncerOpts.LabelSelector, got.LabelSelector)
+		})
+	}
+}
+
+func Test_containerOptions_newResourceSyncer(t *testing.T) {
+	t.Parallel()
+	s := runtime.NewScheme()
+	require.NoError(t, v1.AddToScheme(s))
+	src := dynamicFake.NewSimpleDynamicClient(s, newPod("pod-1"))
+	dest := dynamicFake.NewSimpleDynamicClient(s)
+	mapper := restmapper.NewDiscoveryRESTMapper([]*restmapper.APIGroupResources{
+		{
+			Group: metav1.APIGroup{Versions: []metav1.GroupVersionForDiscovery{{Version: "v1"}}},
+			VersionedResources: map[string][]metav1.APIResource{
+				"v1": {{Name: "pods", Namespaced: true, Kind: "Pod"}},
+			},
+		},
+	})
+
+	o := newContainerOptions([]Option{
+		WithApplierMutator(podsGVR, labelMutator("applier", "true")),
+		WithSyncerMutator(podsGVR, labelMutator("syncer", "true")),
+	})
+	applierOpts := resourceapplier.Options{GVRsToApply: []schema.GroupVersionResource{podsGVR}}
+	applier := resourceapplier.New(dest, mapper, o.applierOptions(applierOpts))
+	resourceSyncer := o.newResourceSyncer(src, dest, mapper, applierOpts, applier, syncer.Options{}, klog.Background())
+	require.NoError(t, resourceSyncer.Start(nil))
+	t.Cleanup(func() { _ = resourceSyncer.Stop() })
+
+	var synced *unstructured.Unstructured
+	require.Eventually(t, func() bool {
+		var err error
+		synced, err = dest.Resource(podsGVR).Namespace("default").Get(context.Background(), "pod-1", metav1.GetOptions{})
+		return err == nil
+	}, wait.ForeverTestTimeout, 10*time.Millisecond)
+	assert.Equal(t, map[string]string{"applier": "true", "syncer": "true"}, synced.GetLabels(), "the syncer uses both the applier's and its own mutators")
+
+	// The mutators of the syncer don't affect the resources applied by the other services.
+	prepared, ok, err := applier.PrepareForCreating(context.Background(), newPod("pod-2"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"applier": "true"}, prepared.GetLabels())
+}
+
+func TestWithPodLifecycle(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		opts         []Option
+		wantDisabled bool
+	}{
+		{
+			name:         "the pod lifecycle simulator is disabled by default",
+			wantDisabled: true,
+		},
+		{
+			name: "the pod lifecycle simulator is enabled with the option",
+			opts: []Option{WithPodLifecycle(podlifecycle.Options{})},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c, _, _ := NewTestContainerWithOptions(t, tt.opts)
+			_, disabled := c.Components()[ComponentPodLifecycle].(lifecycle.Disabled)
+			assert.Equal(t, tt.wantDisabled, disabled)
+		})
+	}
+}
+
+func TestWithSchedulerRandomSeed(t *testing.T) {
+	t.Parallel()
+	c, _, _ := NewTestContainerWithOptions(t, []Option{WithSchedulerRandomSeed(42)})
+	c.SchedulerService().SetSchedulerConfig(&configv1.KubeSchedulerConfiguration{})
+	active, err := c.SchedulerService().ActiveSchedulerConfig()
+	require.NoError(t, err)
+	assert.Equal(t, ptr.To[int64](42), active.RandomSeed)
+}
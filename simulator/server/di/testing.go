@@ -0,0 +1,131 @@
+package di
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/configreload"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/logging"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/oplog"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/recorder"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/replayer"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/syncer"
+)
+
+// NewTestContainer builds Container with the fake clients having objects, for the tests which can't run kube-apiserver.
+// The clientset and the dynamic client share the objects, so that the objects created with one are got with the other.
+// The returned clientset is for the assertions, and its Tracker has all the objects.
+//
+// The container has no etcd, so ResetService and EtcdSnapshotService are nil,
+// and the syncer, the recorder, the replayer, the pod lifecycle simulator and the importer are disabled.
+// The scheduler isn't started, and the container is closed when the test finishes.
+func NewTestContainer(t testing.TB, objects ...runtime.Object) (*Container, *fake.Clientset, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	return NewTestContainerWithOptions(t, nil, objects...)
+}
+
+// NewTestContainerWithOptions is NewTestContainer customizing the services with opts.
+func NewTestContainerWithOptions(t testing.TB, opts []Option, objects ...runtime.Object) (*Container, *fake.Clientset, *dynamicfake.FakeDynamicClient) {
+	t.Helper()
+	client := fake.NewSimpleClientset(objects...)
+	dynamicClient := newSharedFakeDynamicClient(client)
+	restMapper := testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme)
+
+	logBuffer := oplog.NewBuffer(oplog.DefaultCapacity)
+	loggers, err := logging.New(logging.Options{}, io.Discard, logBuffer)
+	if err != nil {
+		t.Fatalf("initialize logging: %v", err)
+	}
+
+	c, err := NewDIContainerWithOptions(client, dynamicClient, restMapper, nil, nil, &configv1.KubeSchedulerConfiguration{}, nil, false, false, false, nil, "", "", "",
+		resourceapplier.Options{}, syncer.Options{}, replayer.Options{}, recorder.Options{}, resourcewatcher.Options{},
+		logBuffer, configreload.NewService(&config.Config{}, nil), loggers, opts...)
+	if err != nil {
+		t.Fatalf("build the test container: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := c.Close(context.Background()); err != nil {
+			t.Errorf("close the test container: %v", err)
+		}
+	})
+	return c, client, dynamicClient
+}
+
+// newSharedFakeDynamicClient returns the fake dynamic client reading and writing the objects in the tracker of client.
+// The tracker of the returned client isn't used.
+func newSharedFakeDynamicClient(client *fake.Clientset) *dynamicfake.FakeDynamicClient {
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+	tracker := client.Tracker()
+	reaction := clienttesting.ObjectReaction(tracker)
+	dynamicClient.PrependReactor("*", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		// The typed clientset can't read the unstructured objects, so they're stored as the typed ones.
+		switch a := action.(type) {
+		case clienttesting.CreateActionImpl:
+			a.Object = toTyped(a.Object)
+			action = a
+		case clienttesting.UpdateActionImpl:
+			a.Object = toTyped(a.Object)
+			action = a
+		}
+		handled, obj, err := reaction(action)
+		// The dynamic client only converts the unstructured objects.
+		return handled, toUnstructured(obj), err
+	})
+	dynamicClient.PrependWatchReactor("*", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		w, err := tracker.Watch(action.GetResource(), action.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		// The dynamic informers expect the unstructured objects.
+		return true, watch.Filter(w, func(e watch.Event) (watch.Event, bool) {
+			e.Object = toUnstructured(e.Object)
+			return e, true
+		}), nil
+	})
+	return dynamicClient
+}
+
+// toTyped converts obj to the typed object if it's unstructured and its kind is known to the scheme of the clientset.
+func toTyped(obj runtime.Object) runtime.Object {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return obj
+	}
+	typed, err := scheme.Scheme.New(u.GroupVersionKind())
+	if err != nil {
+		return obj
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, typed); err != nil {
+		return obj
+	}
+	return typed
+}
+
+// toUnstructured converts obj to the unstructured object if it's typed.
+func toUnstructured(obj runtime.Object) runtime.Object {
+	if obj == nil {
+		return nil
+	}
+	if _, ok := obj.(runtime.Unstructured); ok {
+		return obj
+	}
+	u := &unstructured.Unstructured{}
+	if err := scheme.Scheme.Convert(obj, u, nil); err != nil {
+		return obj
+	}
+	return u
+}
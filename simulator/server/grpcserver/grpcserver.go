@@ -0,0 +1,123 @@
+// Package grpcserver serves the gRPC API of the simulator defined in proto/simulatorv1.
+// It's served alongside the REST API, on another port, with the same services in the DI container.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/auth"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/proto/simulatorv1"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// shutdownTimeout is the time to wait for the running RPCs, e.g., the watches, to finish on the shutdown.
+const shutdownTimeout = 10 * time.Second
+
+// NewGRPCServer initializes the gRPC server serving the simulator's gRPC API with the services in dic.
+// The RPCs are authenticated in the same way as the REST API if cfg.Auth is configured.
+func NewGRPCServer(cfg *config.Config, dic *di.Container) *grpc.Server {
+	var opts []grpc.ServerOption
+	if cfg.Auth != nil {
+		authenticate := auth.New(cfg.Auth)
+		opts = append(opts, grpc.UnaryInterceptor(unaryAuthInterceptor(authenticate)), grpc.StreamInterceptor(streamAuthInterceptor(authenticate)))
+	}
+	s := grpc.NewServer(opts...)
+	simulatorv1.RegisterSimulatorServer(s, NewServer(dic.WhatIfService(), dic.ResourceApplierService(), dic.SchedulingResultsService(), dic.ResourceWatcherService()))
+	return s
+}
+
+// Start starts serving s on the address of listener.
+// It returns the function to stop the server, which waits for the running RPCs up to shutdownTimeout.
+func Start(s *grpc.Server, listener config.Listener) (
+	func(), // function for shutdown
+	error,
+) {
+	lis, err := net.Listen("tcp", listener.HostPort())
+	if err != nil {
+		return nil, xerrors.Errorf("listen on %s: %w", listener.HostPort(), err)
+	}
+
+	go func() {
+		if err := s.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			klog.Fatalf("failed to start gRPC server successfully: %v", err)
+		}
+	}()
+
+	shutdownFn := func() {
+		stopped := make(chan struct{})
+		go func() {
+			s.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(shutdownTimeout):
+			klog.Warning("failed to stop gRPC server gracefully, closing the connections")
+			s.Stop()
+		}
+	}
+
+	return shutdownFn, nil
+}
+
+// mutatingMethods are the RPCs mutating the resources. The other RPCs are allowed for the read-only clients as well.
+var mutatingMethods = map[string]bool{
+	simulatorv1.Simulator_ApplyResources_FullMethodName: true,
+}
+
+// authorize authenticates the RPC with the bearer token in the authorization metadata,
+// and checks the client is allowed to call the method.
+func authorize(ctx context.Context, authenticate auth.Authenticator, fullMethod string) error {
+	// The authenticator reads the same header as the one in the REST API.
+	r := &http.Request{Method: http.MethodGet, Header: http.Header{}}
+	if mutatingMethods[fullMethod] {
+		r.Method = http.MethodPost
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, v := range md.Get("authorization") {
+			r.Header.Add("Authorization", v)
+		}
+	}
+	r = r.WithContext(ctx)
+
+	id, err := authenticate(r)
+	if err != nil {
+		klog.V(3).InfoS("Rejected the unauthenticated RPC", "method", fullMethod, "err", err)
+		return status.Error(codes.Unauthenticated, "a valid bearer token is required")
+	}
+	if !id.Allows(r.Method) {
+		klog.V(3).InfoS("Rejected the RPC not allowed for the role", "method", fullMethod, "client", id.Name, "role", id.Role)
+		return status.Errorf(codes.PermissionDenied, "the %s client isn't allowed to call %s", id.Role, fullMethod)
+	}
+	return nil
+}
+
+func unaryAuthInterceptor(authenticate auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorize(ctx, authenticate, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAuthInterceptor(authenticate auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(ss.Context(), authenticate, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,83 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/auth"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/proto/simulatorv1"
+)
+
+func TestAuthInterceptors(t *testing.T) {
+	t.Parallel()
+	authenticate := auth.New(&auth.Options{Tokens: []auth.StaticToken{
+		{Name: "viewer", Token: "read-token", Role: auth.RoleReadOnly},
+		{Name: "admin", Token: "write-token", Role: auth.RoleReadWrite},
+	}})
+	s := grpc.NewServer(grpc.UnaryInterceptor(unaryAuthInterceptor(authenticate)), grpc.StreamInterceptor(streamAuthInterceptor(authenticate)))
+	simulatorv1.RegisterSimulatorServer(s, NewServer(fakeWhatIfService{}, fakeResourceApplier{}, &fakeSchedulingResultsService{}, &fakeResourceWatcherService{}))
+	client := startTestServer(t, s)
+
+	tests := []struct {
+		name      string
+		token     string
+		wantQuery codes.Code
+		wantApply codes.Code
+		wantWatch codes.Code
+	}{
+		{
+			name:      "no token",
+			wantQuery: codes.Unauthenticated,
+			wantApply: codes.Unauthenticated,
+			wantWatch: codes.Unauthenticated,
+		},
+		{
+			name:      "invalid token",
+			token:     "invalid",
+			wantQuery: codes.Unauthenticated,
+			wantApply: codes.Unauthenticated,
+			wantWatch: codes.Unauthenticated,
+		},
+		{
+			name:      "read-only client can't apply resources",
+			token:     "read-token",
+			wantQuery: codes.OK,
+			wantApply: codes.PermissionDenied,
+			wantWatch: codes.OK,
+		},
+		{
+			name:      "read-write client",
+			token:     "write-token",
+			wantQuery: codes.OK,
+			wantApply: codes.OK,
+			wantWatch: codes.OK,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.token != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+tt.token)
+			}
+
+			_, err := client.QuerySchedulingResults(ctx, &simulatorv1.QuerySchedulingResultsRequest{})
+			assert.Equal(t, tt.wantQuery, status.Code(err))
+			_, err = client.ApplyResources(ctx, &simulatorv1.ApplyResourcesRequest{})
+			assert.Equal(t, tt.wantApply, status.Code(err))
+			stream, err := client.WatchResources(ctx, &simulatorv1.WatchResourcesRequest{})
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			assert.Equal(t, tt.wantWatch, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/proto/simulatorv1"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/resulthistory"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/whatif"
+)
+
+// Server implements simulatorv1.SimulatorServer with the same services as the REST API.
+type Server struct {
+	simulatorv1.UnimplementedSimulatorServer
+
+	whatIf            di.WhatIfService
+	resourceApplier   di.ResourceApplierService
+	schedulingResults di.SchedulingResultsService
+	resourceWatcher   di.ResourceWatcherService
+}
+
+// NewServer initializes Server.
+func NewServer(whatIf di.WhatIfService, resourceApplier di.ResourceApplierService, schedulingResults di.SchedulingResultsService, resourceWatcher di.ResourceWatcherService) *Server {
+	return &Server{
+		whatIf:            whatIf,
+		resourceApplier:   resourceApplier,
+		schedulingResults: schedulingResults,
+		resourceWatcher:   resourceWatcher,
+	}
+}
+
+// WhatIfPod runs the scheduling cycle of the Pod in the request, the same as POST /api/v1/whatif/pod.
+func (s *Server) WhatIfPod(ctx context.Context, req *simulatorv1.WhatIfPodRequest) (*simulatorv1.WhatIfPodResponse, error) {
+	pod := new(corev1.Pod)
+	if err := json.Unmarshal(req.GetPod(), pod); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "decode pod: %v", err)
+	}
+
+	ret, err := s.whatIf.Schedule(ctx, pod)
+	if errors.Is(err, whatif.ErrUnknownProfile) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, whatif.ErrNotSynced) {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to schedule the what-if pod: %+v", err)
+		return nil, status.Error(codes.Internal, "failed to schedule the pod")
+	}
+
+	return &simulatorv1.WhatIfPodResponse{
+		Schedulable:   ret.Schedulable,
+		SelectedNode:  ret.SelectedNode,
+		Message:       ret.Message,
+		FeasibleNodes: ret.FeasibleNodes,
+		Results:       ret.Results,
+	}, nil
+}
+
+// ApplyResources creates the resources in the request in the order of their dependencies.
+// The failures of each resource are returned in the results, not as the error of the RPC.
+func (s *Server) ApplyResources(ctx context.Context, req *simulatorv1.ApplyResourcesRequest) (*simulatorv1.ApplyResourcesResponse, error) {
+	resources := make([]unstructured.Unstructured, len(req.GetManifests()))
+	for i, m := range req.GetManifests() {
+		if err := resources[i].UnmarshalJSON(m); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "decode manifests[%d]: %v", i, err)
+		}
+	}
+
+	results, err := s.resourceApplier.ApplyAll(ctx, resources, resourceapplier.ApplyAllOptions{
+		Workers:     int(req.GetWorkers()),
+		StopOnError: req.GetStopOnError(),
+	})
+	if err != nil {
+		// The error is the first failure, which is in the results as well.
+		klog.Infof("stopped applying resources on the failure: %v", err)
+	}
+
+	resp := &simulatorv1.ApplyResourcesResponse{Results: make([]*simulatorv1.ApplyResult, 0, len(results))}
+	for _, r := range results {
+		ret := &simulatorv1.ApplyResult{
+			ApiVersion: r.Resource.GetAPIVersion(),
+			Kind:       r.Resource.GetKind(),
+			Namespace:  r.Resource.GetNamespace(),
+			Name:       r.Resource.GetName(),
+		}
+		if r.Err != nil {
+			ret.Error = r.Err.Error()
+		}
+		resp.Results = append(resp.Results, ret)
+	}
+	return resp, nil
+}
+
+// QuerySchedulingResults returns the scheduling results matching the request, the same as GET /api/v1/schedulingresults.
+func (s *Server) QuerySchedulingResults(_ context.Context, req *simulatorv1.QuerySchedulingResultsRequest) (*simulatorv1.QuerySchedulingResultsResponse, error) {
+	q := resulthistory.Query{
+		Namespace: req.GetNamespace(),
+		Pod:       req.GetPod(),
+		Node:      req.GetNode(),
+		Limit:     int(req.GetLimit()),
+		Continue:  req.GetContinue(),
+	}
+	if req.GetSince() != nil {
+		q.Since = req.GetSince().AsTime()
+	}
+
+	result, err := s.schedulingResults.Query(q)
+	if errors.Is(err, resulthistory.ErrInvalidQuery) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to query scheduling results: %+v", err)
+		return nil, status.Error(codes.Internal, "failed to query scheduling results")
+	}
+
+	resp := &simulatorv1.QuerySchedulingResultsResponse{
+		Items:    make([]*simulatorv1.SchedulingResult, 0, len(result.Items)),
+		Continue: result.Continue,
+	}
+	for _, e := range result.Items {
+		resp.Items = append(resp.Items, &simulatorv1.SchedulingResult{
+			Id:        e.ID,
+			Namespace: e.Namespace,
+			Pod:       e.Pod,
+			PodUid:    string(e.PodUID),
+			Node:      e.Node,
+			Timestamp: timestamppb.New(e.Timestamp),
+			Results:   e.Results,
+		})
+	}
+	return resp, nil
+}
+
+// WatchResources lists and watches the resources, the same as GET /api/v1/listwatchresources.
+// Each WatchEvent is sent as a message, and the stream continues until the client cancels it.
+func (s *Server) WatchResources(req *simulatorv1.WatchResourcesRequest, stream grpc.ServerStreamingServer[simulatorv1.WatchEvent]) error {
+	versions := &resourcewatcher.LastResourceVersions{}
+	if cursor := req.GetResourceVersion(); cursor != "" {
+		v, err := resourcewatcher.ParseCursor(cursor)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "invalid resource_version")
+		}
+		versions = v
+	}
+	filter, err := watchFilter(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	opts := resourcewatcher.WatchOptions{
+		Filter:                 filter,
+		StripResultAnnotations: req.GetStripResultAnnotations(),
+		KeepManagedFields:      req.GetKeepManagedFields(),
+	}
+	ctx := stream.Context()
+	if p, ok := peer.FromContext(ctx); ok {
+		opts.RemoteAddr = p.Addr.String()
+	}
+
+	if err := s.resourceWatcher.ListWatch(ctx, newEventStream(stream.Send), versions, opts); err != nil {
+		klog.Errorf("terminated to watch resources for %q: %+v", opts.RemoteAddr, err)
+		return status.Error(codes.Internal, "terminated to watch resources")
+	}
+	// ListWatch returns when the stream is canceled by the client.
+	return nil
+}
+
+// watchFilter gets the filter of the events in the request.
+func watchFilter(req *simulatorv1.WatchResourcesRequest) (*resourcewatcher.Filter, error) {
+	filter := &resourcewatcher.Filter{
+		Namespace: req.GetNamespace(),
+		Kinds:     sets.New[streamwriter.ResourceKind](),
+	}
+	if s := req.GetLabelSelector(); s != "" {
+		selector, err := labels.Parse(s)
+		if err != nil {
+			return nil, xerrors.Errorf("parse label_selector: %w", err)
+		}
+		filter.LabelSelector = selector
+	}
+	for _, k := range req.GetKinds() {
+		kind := streamwriter.ResourceKind(strings.TrimSpace(k))
+		if !resourcewatcher.AllKinds.Has(kind) {
+			return nil, xerrors.Errorf("unknown kind %q, must be one of %v", kind, sets.List(resourcewatcher.AllKinds))
+		}
+		filter.Kinds.Insert(kind)
+	}
+	return filter, nil
+}
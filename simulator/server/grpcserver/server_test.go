@@ -0,0 +1,347 @@
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/proto/simulatorv1"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/resulthistory"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/whatif"
+)
+
+type fakeWhatIfService struct{}
+
+func (fakeWhatIfService) Start(<-chan struct{}) error { return nil }
+
+func (fakeWhatIfService) ScheduleBatch(context.Context, []whatif.BatchItem) (*whatif.BatchResult, error) {
+	return &whatif.BatchResult{}, nil
+}
+
+func (fakeWhatIfService) Schedule(_ context.Context, pod *corev1.Pod) (*whatif.Result, error) {
+	switch pod.Spec.SchedulerName {
+	case "unknown":
+		return nil, whatif.ErrUnknownProfile
+	case "not-synced":
+		return nil, whatif.ErrNotSynced
+	}
+	return &whatif.Result{
+		Schedulable:   true,
+		SelectedNode:  "node1",
+		FeasibleNodes: []string{"node1", "node2"},
+		Results:       map[string]string{"kube-scheduler-simulator.sigs.k8s.io/selected-node": "node1"},
+	}, nil
+}
+
+// fakeResourceApplier fails to apply the resources named "invalid".
+type fakeResourceApplier struct{}
+
+func (fakeResourceApplier) ApplyAll(_ context.Context, resources []unstructured.Unstructured, _ resourceapplier.ApplyAllOptions) ([]resourceapplier.Result, error) {
+	results := make([]resourceapplier.Result, 0, len(resources))
+	for i := range resources {
+		r := resourceapplier.Result{Resource: &resources[i]}
+		if resources[i].GetName() == "invalid" {
+			r.Err = errors.New("invalid resource")
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+type fakeSchedulingResultsService struct {
+	got resulthistory.Query
+}
+
+func (*fakeSchedulingResultsService) RegisterRecordingToInformer(clientset.Interface, <-chan struct{}) error {
+	return nil
+}
+
+func (*fakeSchedulingResultsService) Latency(resulthistory.LatencyQuery) *resulthistory.LatencyStats {
+	return &resulthistory.LatencyStats{}
+}
+
+func (f *fakeSchedulingResultsService) Query(q resulthistory.Query) (*resulthistory.QueryResult, error) {
+	if q.Limit < 0 {
+		return nil, resulthistory.ErrInvalidQuery
+	}
+	f.got = q
+	return &resulthistory.QueryResult{
+		Items: []resulthistory.Entry{{
+			ID:        1,
+			Namespace: "default",
+			Pod:       "pod1",
+			PodUID:    "uid1",
+			Node:      "node1",
+			Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Results:   map[string]string{"key": "value"},
+		}},
+		Continue: "2",
+	}, nil
+}
+
+// fakeResourceWatcherService writes an event, a heartbeat and a batch of events in the same way as the resource watcher.
+type fakeResourceWatcherService struct {
+	got resourcewatcher.WatchOptions
+}
+
+func (f *fakeResourceWatcherService) ListWatch(ctx context.Context, stream streamwriter.ResponseStream, _ *resourcewatcher.LastResourceVersions, opts resourcewatcher.WatchOptions) error {
+	f.got = opts
+	sw := streamwriter.NewStreamWriter(stream)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+	if err := sw.Write(&streamwriter.WatchEvent{
+		Kind:              "pods",
+		EventType:         watch.Added,
+		Obj:               pod,
+		Cursor:            "cursor1",
+		SchedulingResults: streamwriter.SchedulingResults{"selected-node": json.RawMessage(`"node1"`)},
+	}); err != nil {
+		return err
+	}
+	if err := sw.WriteHeartbeat(time.Now()); err != nil {
+		return err
+	}
+	if err := sw.WriteBatch([]*streamwriter.WatchEvent{
+		{Kind: "nodes", EventType: watch.Added, Obj: &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}, Cursor: "cursor2"},
+		{Kind: "pods", EventType: watch.Deleted, Obj: pod, Cursor: "cursor3"},
+	}); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func (*fakeResourceWatcherService) ValidateGVRs([]schema.GroupVersionResource) error { return nil }
+
+func (*fakeResourceWatcherService) Watchers() []resourcewatcher.Watcher { return nil }
+
+func (*fakeResourceWatcherService) Disconnect(string) error { return nil }
+
+// startTestServer serves s over bufconn, and returns the client connected to it.
+func startTestServer(t *testing.T, s *grpc.Server) simulatorv1.SimulatorClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return simulatorv1.NewSimulatorClient(conn)
+}
+
+func newTestClient(t *testing.T, results *fakeSchedulingResultsService, watcher *fakeResourceWatcherService) simulatorv1.SimulatorClient {
+	t.Helper()
+	s := grpc.NewServer()
+	simulatorv1.RegisterSimulatorServer(s, NewServer(fakeWhatIfService{}, fakeResourceApplier{}, results, watcher))
+	return startTestServer(t, s)
+}
+
+func marshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return b
+}
+
+func TestServer_WhatIfPod(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t, &fakeSchedulingResultsService{}, &fakeResourceWatcherService{})
+
+	tests := []struct {
+		name     string
+		pod      []byte
+		want     *simulatorv1.WhatIfPodResponse
+		wantCode codes.Code
+	}{
+		{
+			name: "schedulable pod",
+			pod:  marshal(t, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1"}}),
+			want: &simulatorv1.WhatIfPodResponse{
+				Schedulable:   true,
+				SelectedNode:  "node1",
+				FeasibleNodes: []string{"node1", "node2"},
+				Results:       map[string]string{"kube-scheduler-simulator.sigs.k8s.io/selected-node": "node1"},
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "invalid pod",
+			pod:      []byte("{"),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown profile",
+			pod:      marshal(t, &corev1.Pod{Spec: corev1.PodSpec{SchedulerName: "unknown"}}),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "informers aren't synced",
+			pod:      marshal(t, &corev1.Pod{Spec: corev1.PodSpec{SchedulerName: "not-synced"}}),
+			wantCode: codes.Unavailable,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := client.WhatIfPod(context.Background(), &simulatorv1.WhatIfPodRequest{Pod: tt.pod})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.want != nil {
+				assert.Equal(t, tt.want.GetSelectedNode(), got.GetSelectedNode())
+				assert.Equal(t, tt.want.GetSchedulable(), got.GetSchedulable())
+				assert.Equal(t, tt.want.GetFeasibleNodes(), got.GetFeasibleNodes())
+				assert.Equal(t, tt.want.GetResults(), got.GetResults())
+			}
+		})
+	}
+}
+
+func TestServer_ApplyResources(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t, &fakeSchedulingResultsService{}, &fakeResourceWatcherService{})
+
+	got, err := client.ApplyResources(context.Background(), &simulatorv1.ApplyResourcesRequest{Manifests: [][]byte{
+		[]byte(`{"apiVersion":"v1","kind":"Node","metadata":{"name":"node1"}}`),
+		[]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"invalid","namespace":"default"}}`),
+	}})
+	require.NoError(t, err)
+	require.Len(t, got.GetResults(), 2)
+	assert.Equal(t, "Node", got.GetResults()[0].GetKind())
+	assert.Equal(t, "node1", got.GetResults()[0].GetName())
+	assert.Empty(t, got.GetResults()[0].GetError())
+	assert.Equal(t, "default", got.GetResults()[1].GetNamespace())
+	assert.Equal(t, "invalid resource", got.GetResults()[1].GetError())
+
+	_, err = client.ApplyResources(context.Background(), &simulatorv1.ApplyResourcesRequest{Manifests: [][]byte{[]byte("not json")}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_QuerySchedulingResults(t *testing.T) {
+	t.Parallel()
+	results := &fakeSchedulingResultsService{}
+	client := newTestClient(t, results, &fakeResourceWatcherService{})
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	got, err := client.QuerySchedulingResults(context.Background(), &simulatorv1.QuerySchedulingResultsRequest{
+		Namespace: "default",
+		Node:      "node1",
+		Since:     timestamppb.New(since),
+		Limit:     10,
+		Continue:  "1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, resulthistory.Query{Namespace: "default", Node: "node1", Since: since, Limit: 10, Continue: "1"}, results.got)
+	assert.Equal(t, "2", got.GetContinue())
+	require.Len(t, got.GetItems(), 1)
+	item := got.GetItems()[0]
+	assert.Equal(t, int64(1), item.GetId())
+	assert.Equal(t, "uid1", item.GetPodUid())
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), item.GetTimestamp().AsTime())
+	assert.Equal(t, map[string]string{"key": "value"}, item.GetResults())
+
+	_, err = client.QuerySchedulingResults(context.Background(), &simulatorv1.QuerySchedulingResultsRequest{Limit: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_WatchResources(t *testing.T) {
+	t.Parallel()
+	watcher := &fakeResourceWatcherService{}
+	client := newTestClient(t, &fakeSchedulingResultsService{}, watcher)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.WatchResources(ctx, &simulatorv1.WatchResourcesRequest{
+		Namespace:              "default",
+		LabelSelector:          "app=web",
+		Kinds:                  []string{"pods", "nodes"},
+		StripResultAnnotations: true,
+	})
+	require.NoError(t, err)
+
+	var got []*simulatorv1.WatchEvent
+	for i := 0; i < 4; i++ {
+		e, err := stream.Recv()
+		require.NoError(t, err)
+		got = append(got, e)
+	}
+
+	assert.Equal(t, "pods", got[0].GetKind())
+	assert.Equal(t, "ADDED", got[0].GetEventType())
+	assert.Equal(t, "cursor1", got[0].GetCursor())
+	assert.Equal(t, map[string][]byte{"selected-node": []byte(`"node1"`)}, got[0].GetSchedulingResults())
+	pod := &corev1.Pod{}
+	require.NoError(t, json.Unmarshal(got[0].GetObject(), pod))
+	assert.Equal(t, "pod1", pod.Name)
+
+	assert.Equal(t, string(streamwriter.HeartbeatEventType), got[1].GetEventType())
+	assert.Empty(t, got[1].GetObject())
+
+	assert.Equal(t, "nodes", got[2].GetKind())
+	assert.Equal(t, "cursor2", got[2].GetCursor())
+	assert.Equal(t, "DELETED", got[3].GetEventType())
+	assert.Equal(t, "cursor3", got[3].GetCursor())
+
+	assert.Equal(t, "default", watcher.got.Filter.Namespace)
+	assert.Equal(t, "app=web", watcher.got.Filter.LabelSelector.String())
+	assert.True(t, watcher.got.Filter.Kinds.Has("nodes"))
+	assert.True(t, watcher.got.StripResultAnnotations)
+}
+
+func TestServer_WatchResourcesInvalidRequest(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t, &fakeSchedulingResultsService{}, &fakeResourceWatcherService{})
+
+	tests := []struct {
+		name string
+		req  *simulatorv1.WatchResourcesRequest
+	}{
+		{
+			name: "invalid resource version",
+			req:  &simulatorv1.WatchResourcesRequest{ResourceVersion: "invalid"},
+		},
+		{
+			name: "invalid label selector",
+			req:  &simulatorv1.WatchResourcesRequest{LabelSelector: "app in (web"},
+		},
+		{
+			name: "unknown kind",
+			req:  &simulatorv1.WatchResourcesRequest{Kinds: []string{"deployments"}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stream, err := client.WatchResources(context.Background(), tt.req)
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package grpcserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/proto/simulatorv1"
+)
+
+// eventStream is streamwriter.ResponseStream sending the WatchEvents to the gRPC stream.
+// The resource watcher writes each WatchEvent, each batch of them, or each heartbeat as a JSON value followed by a flush,
+// so eventStream buffers the JSON and sends the messages decoded from it on the flush.
+// It's written by one goroutine at a time since streamwriter.StreamWriter serializes the writes.
+type eventStream struct {
+	send func(*simulatorv1.WatchEvent) error
+	buf  bytes.Buffer
+	// err is the error on the last flush, returned from the next write since Flush can't return it.
+	err error
+}
+
+func newEventStream(send func(*simulatorv1.WatchEvent) error) *eventStream {
+	return &eventStream{send: send}
+}
+
+// watchEvent is streamwriter.WatchEvent or streamwriter.Heartbeat decoded with the objects kept in JSON.
+type watchEvent struct {
+	Kind              string
+	EventType         string
+	Obj               json.RawMessage
+	Cursor            string
+	SchedulingResults map[string]json.RawMessage `json:"schedulingResults"`
+}
+
+func (s *eventStream) Write(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	return s.buf.Write(p)
+}
+
+func (s *eventStream) Flush() {
+	defer s.buf.Reset()
+	if s.err != nil {
+		return
+	}
+	s.err = s.sendBuffered()
+}
+
+// sendBuffered sends all the WatchEvents in the buffer. The batches of the WatchEvents are sent one by one.
+func (s *eventStream) sendBuffered() error {
+	dec := json.NewDecoder(&s.buf)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return xerrors.Errorf("decode WatchEvents: %w", err)
+		}
+
+		var events []watchEvent
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+			if err := json.Unmarshal(raw, &events); err != nil {
+				return xerrors.Errorf("decode a batch of WatchEvents: %w", err)
+			}
+		} else {
+			events = make([]watchEvent, 1)
+			if err := json.Unmarshal(raw, &events[0]); err != nil {
+				return xerrors.Errorf("decode a WatchEvent: %w", err)
+			}
+		}
+
+		for i := range events {
+			if err := s.send(toProto(&events[i])); err != nil {
+				return xerrors.Errorf("send a WatchEvent: %w", err)
+			}
+		}
+	}
+}
+
+func toProto(we *watchEvent) *simulatorv1.WatchEvent {
+	ret := &simulatorv1.WatchEvent{
+		Kind:      we.Kind,
+		EventType: we.EventType,
+		Cursor:    we.Cursor,
+	}
+	if len(we.Obj) > 0 && !bytes.Equal(we.Obj, []byte("null")) {
+		ret.Object = we.Obj
+	}
+	if len(we.SchedulingResults) > 0 {
+		ret.SchedulingResults = make(map[string][]byte, len(we.SchedulingResults))
+		for k, v := range we.SchedulingResults {
+			ret.SchedulingResults[k] = v
+		}
+	}
+	return ret
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/bulknode"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/experiment"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// BulkNodeHandler is handler for creating Nodes in bulk.
+type BulkNodeHandler struct {
+	service func() di.BulkNodeService
+}
+
+// BulkNodeRequest is the request to create Nodes in bulk.
+type BulkNodeRequest struct {
+	Template    *unstructured.Unstructured `json:"template"`
+	CloneFrom   string                     `json:"cloneFrom"`
+	Count       int                        `json:"count"`
+	NamePrefix  string                     `json:"namePrefix"`
+	Groups      []bulknode.Group           `json:"groups"`
+	Concurrency int                        `json:"concurrency"`
+}
+
+// NewBulkNodeHandler initializes BulkNodeHandler.
+func NewBulkNodeHandler(s func() di.BulkNodeService) *BulkNodeHandler {
+	return &BulkNodeHandler{service: s}
+}
+
+// Create creates Nodes from the template or the existing Node, and returns the summary of the creation.
+func (h *BulkNodeHandler) Create(c echo.Context) error {
+	req := new(BulkNodeRequest)
+	if err := bindJSONOrYAML(c, req); err != nil {
+		klog.Errorf("failed to bind bulk node request: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	opts := bulknode.CreateOptions{
+		Template:    req.Template,
+		CloneFrom:   req.CloneFrom,
+		Count:       req.Count,
+		NamePrefix:  req.NamePrefix,
+		Groups:      req.Groups,
+		Concurrency: req.Concurrency,
+	}
+	if e := experiment.FromContext(c.Request().Context()); e != nil {
+		opts.Labels = e.Labels()
+	}
+
+	summary, err := h.service().Create(c.Request().Context(), opts)
+	if errors.Is(err, bulknode.ErrInvalidCreateOptions) {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to create nodes in bulk: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/bulknode"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+type fakeBulkNodeService struct {
+	got bulknode.CreateOptions
+	err error
+}
+
+func (s *fakeBulkNodeService) Create(_ context.Context, opts bulknode.CreateOptions) (*bulknode.CreateSummary, error) {
+	s.got = opts
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &bulknode.CreateSummary{BatchID: "batch", Requested: opts.Count, Created: opts.Count}, nil
+}
+
+func TestBulkNodeHandler_Create(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		wantCode   int
+		wantOpts   bulknode.CreateOptions
+	}{
+		{
+			name:     "create nodes in the groups",
+			body:     `{"template":{"apiVersion":"v1","kind":"Node"},"namePrefix":"fleet","groups":[{"count":50,"capacity":{"cpu":"16"}},{"count":150,"capacity":{"cpu":"8"},"labels":{"size":"small"}}]}`,
+			wantCode: http.StatusOK,
+			wantOpts: bulknode.CreateOptions{
+				NamePrefix: "fleet",
+				Groups: []bulknode.Group{
+					{Count: 50, Capacity: v1.ResourceList{v1.ResourceCPU: resource.MustParse("16")}},
+					{Count: 150, Capacity: v1.ResourceList{v1.ResourceCPU: resource.MustParse("8")}, Labels: map[string]string{"size": "small"}},
+				},
+			},
+		},
+		{
+			name:     "clone the existing node",
+			body:     `{"cloneFrom":"node-a","count":3,"namePrefix":"clone","concurrency":2}`,
+			wantCode: http.StatusOK,
+			wantOpts: bulknode.CreateOptions{CloneFrom: "node-a", Count: 3, NamePrefix: "clone", Concurrency: 2},
+		},
+		{
+			name:       "invalid options",
+			body:       `{"count":3,"namePrefix":"fleet"}`,
+			serviceErr: xerrors.Errorf("no template: %w", bulknode.ErrInvalidCreateOptions),
+			wantCode:   http.StatusBadRequest,
+			wantOpts:   bulknode.CreateOptions{Count: 3, NamePrefix: "fleet"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			service := &fakeBulkNodeService{err: tt.serviceErr}
+			h := NewBulkNodeHandler(func() di.BulkNodeService { return service })
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			require.NoError(t, h.Create(echo.New().NewContext(req, rec)))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			service.got.Template = nil
+			assert.Equal(t, len(tt.wantOpts.Groups), len(service.got.Groups))
+			for i := range tt.wantOpts.Groups {
+				assert.Equal(t, tt.wantOpts.Groups[i].Count, service.got.Groups[i].Count)
+				assert.Equal(t, tt.wantOpts.Groups[i].Labels, service.got.Groups[i].Labels)
+				assert.True(t, tt.wantOpts.Groups[i].Capacity.Cpu().Equal(*service.got.Groups[i].Capacity.Cpu()))
+			}
+			tt.wantOpts.Groups, service.got.Groups = nil, nil
+			assert.Equal(t, tt.wantOpts, service.got)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/bulkpod"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/experiment"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// BulkPodHandler is handler for creating Pods in bulk.
+type BulkPodHandler struct {
+	service func() di.BulkPodService
+}
+
+// BulkPodRequest is the request to create Pods in bulk.
+type BulkPodRequest struct {
+	Template              *unstructured.Unstructured `json:"template"`
+	Count                 int                        `json:"count"`
+	NamePrefix            string                     `json:"namePrefix"`
+	Labels                map[string]string          `json:"labels"`
+	ResourceJitterPercent int                        `json:"resourceJitterPercent"`
+	Concurrency           int                        `json:"concurrency"`
+}
+
+// NewBulkPodHandler initializes BulkPodHandler.
+func NewBulkPodHandler(s func() di.BulkPodService) *BulkPodHandler {
+	return &BulkPodHandler{service: s}
+}
+
+// Create creates Pods from the template, and returns the summary of the creation.
+func (h *BulkPodHandler) Create(c echo.Context) error {
+	req := new(BulkPodRequest)
+	if err := bindJSONOrYAML(c, req); err != nil {
+		klog.Errorf("failed to bind bulk pod request: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	opts := bulkpod.CreateOptions{
+		Template:              req.Template,
+		Count:                 req.Count,
+		NamePrefix:            req.NamePrefix,
+		Labels:                req.Labels,
+		ResourceJitterPercent: req.ResourceJitterPercent,
+		Concurrency:           req.Concurrency,
+	}
+	if e := experiment.FromContext(c.Request().Context()); e != nil {
+		if err := scopeBulkPods(&opts, e); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	summary, err := h.service().Create(c.Request().Context(), opts)
+	if errors.Is(err, bulkpod.ErrInvalidCreateOptions) {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to create pods in bulk: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, summary)
+}
+
+// scopeBulkPods labels the Pods with the experiment, and creates them in the first namespace of the experiment
+// if the template doesn't have the namespace. The template in the other namespaces is rejected.
+func scopeBulkPods(opts *bulkpod.CreateOptions, e *experiment.Experiment) error {
+	if opts.Template == nil {
+		// The service rejects it.
+		return nil
+	}
+	template := opts.Template.DeepCopy()
+	if template.GetNamespace() == "" {
+		template.SetNamespace(e.Namespaces[0])
+	}
+	if !e.HasNamespace(template.GetNamespace()) {
+		return xerrors.Errorf("namespace %s isn't in experiment %s", template.GetNamespace(), e.ID)
+	}
+	opts.Template = template
+
+	labels := make(map[string]string, len(opts.Labels)+1)
+	for k, v := range opts.Labels {
+		labels[k] = v
+	}
+	for k, v := range e.Labels() {
+		labels[k] = v
+	}
+	opts.Labels = labels
+	return nil
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/bulkpod"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+type fakeBulkPodService struct {
+	got bulkpod.CreateOptions
+	err error
+}
+
+func (s *fakeBulkPodService) Create(_ context.Context, opts bulkpod.CreateOptions) (*bulkpod.CreateSummary, error) {
+	s.got = opts
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &bulkpod.CreateSummary{Requested: opts.Count, Created: opts.Count}, nil
+}
+
+func TestBulkPodHandler_Create(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		serviceErr  error
+		wantCode    int
+		wantOpts    bulkpod.CreateOptions
+	}{
+		{
+			name:        "create pods from the template in JSON",
+			contentType: echo.MIMEApplicationJSON,
+			body:        `{"template":{"apiVersion":"v1","kind":"Pod","spec":{"containers":[{"name":"app","image":"pause"}]}},"count":3,"namePrefix":"load","labels":{"index":"{{index}}"},"resourceJitterPercent":10}`,
+			wantCode:    http.StatusOK,
+			wantOpts: bulkpod.CreateOptions{
+				Count:                 3,
+				NamePrefix:            "load",
+				Labels:                map[string]string{"index": "{{index}}"},
+				ResourceJitterPercent: 10,
+			},
+		},
+		{
+			name:        "create pods from the template in YAML",
+			contentType: mimeApplicationYAML,
+			body: `template:
+  apiVersion: v1
+  kind: Pod
+  spec:
+    containers:
+    - name: app
+      image: pause
+count: 2
+namePrefix: load
+concurrency: 4
+`,
+			wantCode: http.StatusOK,
+			wantOpts: bulkpod.CreateOptions{Count: 2, NamePrefix: "load", Concurrency: 4},
+		},
+		{
+			name:        "invalid options",
+			contentType: echo.MIMEApplicationJSON,
+			body:        `{"template":{"apiVersion":"v1","kind":"Pod"},"count":0,"namePrefix":"load"}`,
+			serviceErr:  xerrors.Errorf("count 0 out of range: %w", bulkpod.ErrInvalidCreateOptions),
+			wantCode:    http.StatusBadRequest,
+			wantOpts:    bulkpod.CreateOptions{NamePrefix: "load"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			service := &fakeBulkPodService{err: tt.serviceErr}
+			h := NewBulkPodHandler(func() di.BulkPodService { return service })
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			rec := httptest.NewRecorder()
+			require.NoError(t, h.Create(echo.New().NewContext(req, rec)))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			require.NotNil(t, service.got.Template)
+			assert.Equal(t, "Pod", service.got.Template.GetKind())
+			service.got.Template = nil
+			assert.Equal(t, tt.wantOpts, service.got)
+			if tt.wantCode == http.StatusOK {
+				var summary bulkpod.CreateSummary
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summary))
+				assert.Equal(t, tt.wantOpts.Count, summary.Created)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/oneshotimporter"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// ClusterImportHandler is handler for importing resources from a target cluster on demand.
+type ClusterImportHandler struct {
+	service di.OneShotClusterResourceImporter
+}
+
+// ClusterImportRequest is the options for importing resources from a target cluster.
+type ClusterImportRequest struct {
+	Namespaces             []string             `json:"namespaces"`
+	LabelSelector          metav1.LabelSelector `json:"labelSelector"`
+	OnConflict             string               `json:"onConflict"`
+	Strict                 bool                 `json:"strict"`
+	Resume                 bool                 `json:"resume"`
+	IncludeCompletedPods   bool                 `json:"includeCompletedPods"`
+	ResourceCountThreshold int                  `json:"resourceCountThreshold"`
+	Force                  bool                 `json:"force"`
+	Concurrency            int                  `json:"concurrency"`
+	ForceNodeReady         bool                 `json:"forceNodeReady"`
+	ImportSchedulerConfig  bool                 `json:"importSchedulerConfig"`
+}
+
+// NewClusterImportHandler initializes ClusterImportHandler.
+// s can be nil when the kubeconfig for the target cluster isn't configured.
+func NewClusterImportHandler(s di.OneShotClusterResourceImporter) *ClusterImportHandler {
+	return &ClusterImportHandler{service: s}
+}
+
+// Import starts importing resources from the target cluster asynchronously.
+func (h *ClusterImportHandler) Import(c echo.Context) error {
+	if h.service == nil {
+		return c.JSON(http.StatusBadRequest, "Importing resources is disabled because the kubeconfig for your cluster isn't configured.")
+	}
+
+	req := new(ClusterImportRequest)
+	if err := c.Bind(req); err != nil {
+		klog.Errorf("failed to bind cluster import request: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	err := h.service.StartImport(oneshotimporter.ImportOptions{
+		Namespaces:             req.Namespaces,
+		LabelSelector:          req.LabelSelector,
+		OnConflict:             oneshotimporter.ConflictPolicy(req.OnConflict),
+		Strict:                 req.Strict,
+		Resume:                 req.Resume,
+		IncludeCompletedPods:   req.IncludeCompletedPods,
+		ResourceCountThreshold: req.ResourceCountThreshold,
+		Force:                  req.Force,
+		Concurrency:            req.Concurrency,
+		ForceNodeReady:         req.ForceNodeReady,
+		ImportSchedulerConfig:  req.ImportSchedulerConfig,
+	})
+	if errors.Is(err, oneshotimporter.ErrImportInProgress) {
+		return c.JSON(http.StatusConflict, err.Error())
+	}
+	if errors.Is(err, oneshotimporter.ErrInvalidImportOptions) {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to start importing resources: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
+
+// Diff returns the differences of resources between the target cluster and the simulator.
+func (h *ClusterImportHandler) Diff(c echo.Context) error {
+	if h.service == nil {
+		return c.JSON(http.StatusBadRequest, "Importing resources is disabled because the kubeconfig for your cluster isn't configured.")
+	}
+
+	report, err := h.service.Diff(c.Request().Context())
+	if err != nil {
+		klog.Errorf("failed to diff resources: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// Status returns the status of the latest import.
+func (h *ClusterImportHandler) Status(c echo.Context) error {
+	if h.service == nil {
+		return c.JSON(http.StatusBadRequest, "Importing resources is disabled because the kubeconfig for your cluster isn't configured.")
+	}
+
+	return c.JSON(http.StatusOK, h.service.ImportStatus())
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/oneshotimporter"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di/mock_di"
+)
+
+func TestClusterImportHandler_Import(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		body      string
+		prepareFn func(m *mock_di.MockOneShotClusterResourceImporter)
+		wantCode  int
+	}{
+		{
+			name: "start importing with the options",
+			body: `{"namespaces":["default"],"labelSelector":{"matchLabels":{"app":"web"}},"onConflict":"skip","concurrency":2}`,
+			prepareFn: func(m *mock_di.MockOneShotClusterResourceImporter) {
+				m.EXPECT().StartImport(oneshotimporter.ImportOptions{
+					Namespaces:    []string{"default"},
+					LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+					OnConflict:    oneshotimporter.ConflictPolicy("skip"),
+					Concurrency:   2,
+				}).Return(nil)
+			},
+			wantCode: http.StatusAccepted,
+		},
+		{
+			name: "another import is in progress",
+			body: `{}`,
+			prepareFn: func(m *mock_di.MockOneShotClusterResourceImporter) {
+				m.EXPECT().StartImport(gomock.Any()).Return(oneshotimporter.ErrImportInProgress)
+			},
+			wantCode: http.StatusConflict,
+		},
+		{
+			name: "invalid options",
+			body: `{"concurrency":-1}`,
+			prepareFn: func(m *mock_di.MockOneShotClusterResourceImporter) {
+				m.EXPECT().StartImport(gomock.Any()).Return(xerrors.Errorf("concurrency must not be negative: %w", oneshotimporter.ErrInvalidImportOptions))
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "failure of the service",
+			body: `{}`,
+			prepareFn: func(m *mock_di.MockOneShotClusterResourceImporter) {
+				m.EXPECT().StartImport(gomock.Any()).Return(xerrors.New("the cluster is unreachable"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			m := mock_di.NewMockOneShotClusterResourceImporter(ctrl)
+			tt.prepareFn(m)
+			e := echo.New()
+			e.POST("/api/v1/import/cluster", NewClusterImportHandler(m).Import)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/import/cluster", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+}
+
+func TestClusterImportHandler_Status(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	m := mock_di.NewMockOneShotClusterResourceImporter(ctrl)
+	m.EXPECT().ImportStatus().Return(oneshotimporter.ImportStatus{State: oneshotimporter.ImportStateRunning})
+	e := echo.New()
+	e.GET("/api/v1/import/cluster/status", NewClusterImportHandler(m).Status)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/import/cluster/status", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	got := oneshotimporter.ImportStatus{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, oneshotimporter.ImportStateRunning, got.State)
+}
+
+func TestClusterImportHandler_disabled(t *testing.T) {
+	t.Parallel()
+	h := NewClusterImportHandler(nil)
+	e := echo.New()
+	e.POST("/api/v1/import/cluster", h.Import)
+	e.GET("/api/v1/import/cluster/status", h.Status)
+	e.GET("/api/v1/import/diff", h.Diff)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/api/v1/import/cluster", strings.NewReader(`{}`)),
+		httptest.NewRequest(http.MethodGet, "/api/v1/import/cluster/status", nil),
+		httptest.NewRequest(http.MethodGet, "/api/v1/import/diff", nil),
+	} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, req.URL.Path)
+	}
+}
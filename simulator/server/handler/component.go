@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/lifecycle"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// ComponentHandler is handler for managing the lifecycles of the long-running components, e.g., the syncer.
+type ComponentHandler struct {
+	components map[string]di.LifecycleComponent
+}
+
+// Component is the status of a component.
+type Component struct {
+	Name string `json:"name"`
+	lifecycle.Status
+}
+
+// ComponentRequest is the request to change the state of a component.
+type ComponentRequest struct {
+	Action lifecycle.Action `json:"action"`
+	// Options are the options of the component given with the start action.
+	Options json.RawMessage `json:"options,omitempty"`
+}
+
+// componentConflict is the response to the action not allowed in the current state.
+type componentConflict struct {
+	Message string `json:"message"`
+	Component
+}
+
+// NewComponentHandler initializes ComponentHandler.
+func NewComponentHandler(components map[string]di.LifecycleComponent) *ComponentHandler {
+	return &ComponentHandler{components: components}
+}
+
+// List returns the status of all the components in the order of the names.
+func (h *ComponentHandler) List(c echo.Context) error {
+	names := make([]string, 0, len(h.components))
+	for name := range h.components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ret := make([]Component, 0, len(names))
+	for _, name := range names {
+		ret = append(ret, Component{Name: name, Status: h.components[name].Status()})
+	}
+	return c.JSON(http.StatusOK, ret)
+}
+
+// Get returns the status of the component.
+func (h *ComponentHandler) Get(c echo.Context) error {
+	name := c.Param("name")
+	component, ok := h.components[name]
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "unknown component "+name)
+	}
+	return c.JSON(http.StatusOK, Component{Name: name, Status: component.Status()})
+}
+
+// Update takes the action on the component, and returns its status after the action.
+// It returns 409 with the current status if the action isn't allowed in the current state.
+func (h *ComponentHandler) Update(c echo.Context) error {
+	name := c.Param("name")
+	component, ok := h.components[name]
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "unknown component "+name)
+	}
+	req := new(ComponentRequest)
+	if err := c.Bind(req); err != nil {
+		klog.Errorf("failed to bind component request: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+	if req.Action != lifecycle.ActionStart && len(req.Options) != 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "options are accepted only with the start action")
+	}
+
+	var err error
+	switch req.Action {
+	case lifecycle.ActionStart:
+		err = component.Start(req.Options)
+	case lifecycle.ActionStop:
+		err = component.Stop()
+	case lifecycle.ActionPause:
+		err = component.Pause()
+	case lifecycle.ActionResume:
+		err = component.Resume()
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "unknown action "+string(req.Action)+", must be start, stop, pause or resume")
+	}
+	if errors.Is(err, lifecycle.ErrIllegalTransition) {
+		return c.JSON(http.StatusConflict, componentConflict{Message: err.Error(), Component: Component{Name: name, Status: component.Status()}})
+	}
+	if errors.Is(err, lifecycle.ErrInvalidOptions) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to %s %s: %+v", req.Action, name, err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, Component{Name: name, Status: component.Status()})
+}
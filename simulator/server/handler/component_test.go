@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/lifecycle"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di/mock_di"
+)
+
+func TestComponentHandler_Update(t *testing.T) {
+	t.Parallel()
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	status := func(state lifecycle.State) lifecycle.Status {
+		return lifecycle.Status{State: state, Since: since}
+	}
+	tests := []struct {
+		name      string
+		component string
+		body      string
+		prepareFn func(m *mock_di.MockLifecycleComponent)
+		wantCode  int
+		wantState lifecycle.State
+	}{
+		{
+			name:      "start the syncer",
+			component: di.ComponentSyncer,
+			body:      `{"action":"start"}`,
+			prepareFn: func(m *mock_di.MockLifecycleComponent) {
+				m.EXPECT().Start(gomock.Len(0)).Return(nil)
+				m.EXPECT().Status().Return(status(lifecycle.StateStarting))
+			},
+			wantCode:  http.StatusOK,
+			wantState: lifecycle.StateStarting,
+		},
+		{
+			name:      "pause the syncer",
+			component: di.ComponentSyncer,
+			body:      `{"action":"pause"}`,
+			prepareFn: func(m *mock_di.MockLifecycleComponent) {
+				m.EXPECT().Pause().Return(nil)
+				m.EXPECT().Status().Return(status(lifecycle.StatePaused))
+			},
+			wantCode:  http.StatusOK,
+			wantState: lifecycle.StatePaused,
+		},
+		{
+			name:      "start the recorder with the options",
+			component: di.ComponentRecorder,
+			body:      `{"action":"start","options":{"recordFile":"/tmp/record.jsonl"}}`,
+			prepareFn: func(m *mock_di.MockLifecycleComponent) {
+				m.EXPECT().Start(json.RawMessage(`{"recordFile":"/tmp/record.jsonl"}`)).Return(nil)
+				m.EXPECT().Status().Return(status(lifecycle.StateStarting))
+			},
+			wantCode:  http.StatusOK,
+			wantState: lifecycle.StateStarting,
+		},
+		{
+			name:      "stop the recorder",
+			component: di.ComponentRecorder,
+			body:      `{"action":"stop"}`,
+			prepareFn: func(m *mock_di.MockLifecycleComponent) {
+				m.EXPECT().Stop().Return(nil)
+				m.EXPECT().Status().Return(status(lifecycle.StateStopped))
+			},
+			wantCode:  http.StatusOK,
+			wantState: lifecycle.StateStopped,
+		},
+		{
+			name:      "resume the replayer",
+			component: di.ComponentReplayer,
+			body:      `{"action":"resume"}`,
+			prepareFn: func(m *mock_di.MockLifecycleComponent) {
+				m.EXPECT().Resume().Return(nil)
+				m.EXPECT().Status().Return(status(lifecycle.StateRunning))
+			},
+			wantCode:  http.StatusOK,
+			wantState: lifecycle.StateRunning,
+		},
+		{
+			name:      "illegal transition returns the current state",
+			component: di.ComponentReplayer,
+			body:      `{"action":"pause"}`,
+			prepareFn: func(m *mock_di.MockLifecycleComponent) {
+				m.EXPECT().Pause().Return(xerrors.Errorf("cannot pause the stopped component: %w", lifecycle.ErrIllegalTransition))
+				m.EXPECT().Status().Return(status(lifecycle.StateStopped))
+			},
+			wantCode:  http.StatusConflict,
+			wantState: lifecycle.StateStopped,
+		},
+		{
+			name:      "invalid options",
+			component: di.ComponentReplayer,
+			body:      `{"action":"start","options":{"unknown":true}}`,
+			prepareFn: func(m *mock_di.MockLifecycleComponent) {
+				m.EXPECT().Start(gomock.Any()).Return(xerrors.Errorf("decode options: %w", lifecycle.ErrInvalidOptions))
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:      "options with the other actions",
+			component: di.ComponentSyncer,
+			body:      `{"action":"stop","options":{}}`,
+			prepareFn: func(*mock_di.MockLifecycleComponent) {},
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "unknown action",
+			component: di.ComponentSyncer,
+			body:      `{"action":"restart"}`,
+			prepareFn: func(*mock_di.MockLifecycleComponent) {},
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "unknown component",
+			component: "scheduler",
+			body:      `{"action":"start"}`,
+			prepareFn: func(*mock_di.MockLifecycleComponent) {},
+			wantCode:  http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			components := map[string]di.LifecycleComponent{}
+			for _, name := range []string{di.ComponentSyncer, di.ComponentRecorder, di.ComponentReplayer} {
+				m := mock_di.NewMockLifecycleComponent(ctrl)
+				if name == tt.component {
+					tt.prepareFn(m)
+				}
+				components[name] = m
+			}
+			e := echo.New()
+			e.PUT("/api/v1/components/:name", NewComponentHandler(components).Update)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/components/"+tt.component, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantState == "" {
+				return
+			}
+			got := Component{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, Component{Name: tt.component, Status: status(tt.wantState)}, got)
+		})
+	}
+}
+
+func TestComponentHandler_List(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	syncer := mock_di.NewMockLifecycleComponent(ctrl)
+	syncer.EXPECT().Status().Return(lifecycle.Status{State: lifecycle.StateRunning}).Times(2)
+	h := NewComponentHandler(map[string]di.LifecycleComponent{
+		di.ComponentSyncer:   syncer,
+		di.ComponentReplayer: lifecycle.Disabled{},
+	})
+	e := echo.New()
+	e.GET("/api/v1/components", h.List)
+	e.GET("/api/v1/components/:name", h.Get)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/components", nil))
+	var got []Component
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, []Component{
+		{Name: di.ComponentReplayer, Status: lifecycle.Status{State: lifecycle.StateDisabled}},
+		{Name: di.ComponentSyncer, Status: lifecycle.Status{State: lifecycle.StateRunning}},
+	}, got)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/components/syncer", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/components/recorder", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
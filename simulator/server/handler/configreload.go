@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/configreload"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// ConfigReloadHandler is handler for reloading the config file of the simulator.
+type ConfigReloadHandler struct {
+	service di.ConfigReloadService
+}
+
+// NewConfigReloadHandler initializes ConfigReloadHandler.
+func NewConfigReloadHandler(s di.ConfigReloadService) *ConfigReloadHandler {
+	return &ConfigReloadHandler{service: s}
+}
+
+// Reload reloads the config file, and returns the names of the fields applied.
+// It returns 409 listing the fields if the ones which can't be reloaded, e.g., port, are changed.
+func (h *ConfigReloadHandler) Reload(c echo.Context) error {
+	result, err := h.service.Reload(c.Request().Context())
+	if errors.Is(err, config.ErrNotReloadable) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if errors.Is(err, configreload.ErrInvalidConfig) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to reload config: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/configreload"
+)
+
+type fakeConfigReloadService struct {
+	result *configreload.Result
+	err    error
+}
+
+func (s *fakeConfigReloadService) Register(_ string, _ configreload.ApplyFunc) {}
+
+func (s *fakeConfigReloadService) Reload(_ context.Context) (*configreload.Result, error) {
+	return s.result, s.err
+}
+
+func TestConfigReloadHandler_Reload(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		service  *fakeConfigReloadService
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "reloaded",
+			service:  &fakeConfigReloadService{result: &configreload.Result{Reloaded: []string{config.FieldSyncer}}},
+			wantCode: http.StatusOK,
+			wantBody: `{"reloaded":["Syncer"]}`,
+		},
+		{
+			name:     "the fields which can't be reloaded are changed",
+			service:  &fakeConfigReloadService{err: xerrors.Errorf("reload config: EtcdURL, Port: %w", config.ErrNotReloadable)},
+			wantCode: http.StatusConflict,
+			wantBody: "EtcdURL, Port",
+		},
+		{
+			name:     "invalid config file",
+			service:  &fakeConfigReloadService{err: xerrors.Errorf("reload config: unknown field: %w", configreload.ErrInvalidConfig)},
+			wantCode: http.StatusBadRequest,
+			wantBody: "unknown field",
+		},
+		{
+			name:     "failed to apply",
+			service:  &fakeConfigReloadService{err: xerrors.New("apply Syncer: failed")},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := echo.New()
+			e.POST("/api/v1/config/reload", NewConfigReloadHandler(tt.service).Reload)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/config/reload", nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// DiagnosticsHandler is handler for diagnosing the resources in the simulator.
+type DiagnosticsHandler struct {
+	service func() di.DiagnosticsService
+}
+
+// NewDiagnosticsHandler initializes DiagnosticsHandler.
+func NewDiagnosticsHandler(s func() di.DiagnosticsService) *DiagnosticsHandler {
+	return &DiagnosticsHandler{service: s}
+}
+
+// Unschedulable reports why the Pending Pods aren't scheduled.
+func (h *DiagnosticsHandler) Unschedulable(c echo.Context) error {
+	report, err := h.service().UnschedulablePods(c.Request().Context())
+	if err != nil {
+		klog.Errorf("failed to diagnose unschedulable pods: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/etcdsnapshot"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// EtcdSnapshotHandler is handler for saving the state of the simulator in etcd to the files, and restoring it.
+type EtcdSnapshotHandler struct {
+	service func() di.EtcdSnapshotService
+}
+
+// EtcdSnapshotRequest is the request to save a snapshot.
+type EtcdSnapshotRequest struct {
+	// Name is the name of the snapshot. It's generated from the current time if empty.
+	Name string `json:"name"`
+}
+
+// NewEtcdSnapshotHandler initializes EtcdSnapshotHandler.
+// s can be nil when etcdSnapshotDir isn't configured.
+func NewEtcdSnapshotHandler(s func() di.EtcdSnapshotService) *EtcdSnapshotHandler {
+	return &EtcdSnapshotHandler{service: s}
+}
+
+// errEtcdSnapshotDisabled is returned by all the endpoints when etcdSnapshotDir isn't configured.
+var errEtcdSnapshotDisabled = echo.NewHTTPError(http.StatusNotFound, "The snapshot of etcd is disabled because etcdSnapshotDir isn't configured.")
+
+// Save saves the current state to a snapshot.
+func (h *EtcdSnapshotHandler) Save(c echo.Context) error {
+	if h.service() == nil {
+		return errEtcdSnapshotDisabled
+	}
+
+	req := new(EtcdSnapshotRequest)
+	if c.Request().ContentLength != 0 {
+		if err := bindJSONOrYAML(c, req); err != nil {
+			klog.Errorf("failed to bind snapshot request: %+v", err)
+			return echo.NewHTTPError(http.StatusBadRequest)
+		}
+	}
+
+	info, err := h.service().Save(c.Request().Context(), req.Name)
+	if errors.Is(err, etcdsnapshot.ErrInvalidName) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if errors.Is(err, etcdsnapshot.ErrAlreadyExists) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to save snapshot: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusCreated, info)
+}
+
+// List returns the saved snapshots.
+func (h *EtcdSnapshotHandler) List(c echo.Context) error {
+	if h.service() == nil {
+		return errEtcdSnapshotDisabled
+	}
+
+	infos, err := h.service().List()
+	if err != nil {
+		klog.Errorf("failed to list snapshots: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, infos)
+}
+
+// Restore replaces the current state with the snapshot.
+func (h *EtcdSnapshotHandler) Restore(c echo.Context) error {
+	if h.service() == nil {
+		return errEtcdSnapshotDisabled
+	}
+
+	err := h.service().Restore(c.Request().Context(), c.Param("name"))
+	if errors.Is(err, etcdsnapshot.ErrInvalidName) || errors.Is(err, etcdsnapshot.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if errors.Is(err, etcdsnapshot.ErrJobsInProgress) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to restore snapshot: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
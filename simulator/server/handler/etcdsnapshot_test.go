@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/etcdsnapshot"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+type fakeEtcdSnapshotService struct {
+	saved    []string
+	restored []string
+	// busy makes Restore return ErrJobsInProgress.
+	busy bool
+}
+
+func (s *fakeEtcdSnapshotService) Save(_ context.Context, name string) (*etcdsnapshot.Info, error) {
+	if name == "" {
+		name = "generated"
+	}
+	if strings.Contains(name, "/") {
+		return nil, xerrors.Errorf("save: %w", etcdsnapshot.ErrInvalidName)
+	}
+	for _, n := range s.saved {
+		if n == name {
+			return nil, xerrors.Errorf("save: %w", etcdsnapshot.ErrAlreadyExists)
+		}
+	}
+	s.saved = append(s.saved, name)
+	return &etcdsnapshot.Info{Name: name}, nil
+}
+
+func (s *fakeEtcdSnapshotService) List() ([]etcdsnapshot.Info, error) {
+	infos := []etcdsnapshot.Info{}
+	for _, n := range s.saved {
+		infos = append(infos, etcdsnapshot.Info{Name: n})
+	}
+	return infos, nil
+}
+
+func (s *fakeEtcdSnapshotService) Restore(_ context.Context, name string) error {
+	if s.busy {
+		return xerrors.Errorf("restore: %w", etcdsnapshot.ErrJobsInProgress)
+	}
+	for _, n := range s.saved {
+		if n == name {
+			s.restored = append(s.restored, name)
+			return nil
+		}
+	}
+	return xerrors.Errorf("restore: %w", etcdsnapshot.ErrNotFound)
+}
+
+func TestEtcdSnapshotHandler(t *testing.T) {
+	t.Parallel()
+	type request struct {
+		method, path, body string
+		wantCode           int
+		wantBody           string
+	}
+	tests := []struct {
+		name         string
+		service      *fakeEtcdSnapshotService
+		requests     []request
+		wantRestored []string
+	}{
+		{
+			name:    "save, list and restore",
+			service: &fakeEtcdSnapshotService{},
+			requests: []request{
+				{method: http.MethodPost, path: "/api/v1/snapshot", body: `{"name":"before"}`, wantCode: http.StatusCreated},
+				{method: http.MethodPost, path: "/api/v1/snapshot", wantCode: http.StatusCreated},
+				{method: http.MethodGet, path: "/api/v1/snapshot", wantCode: http.StatusOK, wantBody: `"name":"generated"`},
+				{method: http.MethodPut, path: "/api/v1/snapshot/before/restore", wantCode: http.StatusOK},
+			},
+			wantRestored: []string{"before"},
+		},
+		{
+			name:    "errors",
+			service: &fakeEtcdSnapshotService{saved: []string{"before"}},
+			requests: []request{
+				{method: http.MethodPost, path: "/api/v1/snapshot", body: `{"name":"before"}`, wantCode: http.StatusConflict},
+				{method: http.MethodPost, path: "/api/v1/snapshot", body: `{"name":"a/b"}`, wantCode: http.StatusBadRequest},
+				{method: http.MethodPut, path: "/api/v1/snapshot/unknown/restore", wantCode: http.StatusNotFound},
+			},
+		},
+		{
+			name:    "restore while other jobs are running",
+			service: &fakeEtcdSnapshotService{saved: []string{"before"}, busy: true},
+			requests: []request{
+				{method: http.MethodPut, path: "/api/v1/snapshot/before/restore", wantCode: http.StatusConflict},
+			},
+		},
+		{
+			name: "disabled",
+			requests: []request{
+				{method: http.MethodGet, path: "/api/v1/snapshot", wantCode: http.StatusNotFound},
+				{method: http.MethodPost, path: "/api/v1/snapshot", wantCode: http.StatusNotFound},
+				{method: http.MethodPut, path: "/api/v1/snapshot/before/restore", wantCode: http.StatusNotFound},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var s di.EtcdSnapshotService
+			if tt.service != nil {
+				s = tt.service
+			}
+			h := NewEtcdSnapshotHandler(func() di.EtcdSnapshotService { return s })
+			e := echo.New()
+			e.GET("/api/v1/snapshot", h.List)
+			e.POST("/api/v1/snapshot", h.Save)
+			e.PUT("/api/v1/snapshot/:name/restore", h.Restore)
+
+			for _, r := range tt.requests {
+				req := httptest.NewRequest(r.method, r.path, strings.NewReader(r.body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+				assert.Equal(t, r.wantCode, rec.Code, "%s %s", r.method, r.path)
+				assert.Contains(t, rec.Body.String(), r.wantBody)
+			}
+			if tt.service != nil {
+				assert.Equal(t, tt.wantRestored, tt.service.restored)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/experiment"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// ExperimentHeader is the header to scope the request to the experiment with the ID.
+const ExperimentHeader = "X-Experiment-ID"
+
+// ExperimentHandler is handler for isolating the resources of the users sharing the simulator into the experiments.
+type ExperimentHandler struct {
+	service func() di.ExperimentService
+}
+
+// ExperimentRequest is the request to create an experiment.
+type ExperimentRequest struct {
+	Name       string   `json:"name"`
+	Namespaces []string `json:"namespaces"`
+}
+
+// NewExperimentHandler initializes ExperimentHandler.
+func NewExperimentHandler(s func() di.ExperimentService) *ExperimentHandler {
+	return &ExperimentHandler{service: s}
+}
+
+// Scope returns the middleware scoping the request to the experiment given in ExperimentHeader.
+// scopedRoutes are the routes supporting the scope, as "<method> <path>", e.g., "POST /api/v1/pods/bulk".
+// The other routes reject the header with 400, so that the clients don't change the resources out of the experiment by mistake.
+// It returns 404 if the experiment doesn't exist.
+func (h *ExperimentHandler) Scope(scopedRoutes sets.Set[string]) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id := c.Request().Header.Get(ExperimentHeader)
+			if id == "" {
+				return next(c)
+			}
+			if route := c.Request().Method + " " + c.Path(); !scopedRoutes.Has(route) {
+				return echo.NewHTTPError(http.StatusBadRequest, ExperimentHeader+" isn't supported by "+route)
+			}
+			ctx := c.Request().Context()
+			e, err := h.service().Get(ctx, id)
+			if errors.Is(err, experiment.ErrNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, "experiment "+id+" not found")
+			}
+			if err != nil {
+				klog.Errorf("failed to get experiment: %+v", err)
+				return echo.NewHTTPError(http.StatusInternalServerError)
+			}
+			c.SetRequest(c.Request().WithContext(experiment.NewContext(ctx, e)))
+			return next(c)
+		}
+	}
+}
+
+// Create creates the namespaces of a new experiment, and returns the experiment with its ID.
+func (h *ExperimentHandler) Create(c echo.Context) error {
+	req := new(ExperimentRequest)
+	if err := bindJSONOrYAML(c, req); err != nil {
+		klog.Errorf("failed to bind experiment request: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	e, err := h.service().Create(c.Request().Context(), experiment.CreateOptions{Name: req.Name, Namespaces: req.Namespaces})
+	if errors.Is(err, experiment.ErrInvalidCreateOptions) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if errors.Is(err, experiment.ErrAlreadyExists) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to create experiment: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusCreated, e)
+}
+
+// List returns all the experiments.
+func (h *ExperimentHandler) List(c echo.Context) error {
+	experiments, err := h.service().List(c.Request().Context())
+	if err != nil {
+		klog.Errorf("failed to list experiments: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, experiments)
+}
+
+// Get returns the experiment.
+func (h *ExperimentHandler) Get(c echo.Context) error {
+	e, err := h.service().Get(c.Request().Context(), c.Param("id"))
+	if errors.Is(err, experiment.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+	if err != nil {
+		klog.Errorf("failed to get experiment: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, e)
+}
+
+// Delete tears down the experiment, and returns the number of the deleted resources.
+func (h *ExperimentHandler) Delete(c echo.Context) error {
+	summary, err := h.service().Delete(c.Request().Context(), c.Param("id"))
+	if errors.Is(err, experiment.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+	if err != nil {
+		klog.Errorf("failed to delete experiment: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, summary)
+}
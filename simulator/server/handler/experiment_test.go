@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/bulkpod"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/experiment"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+type fakeExperimentService struct {
+	experiments map[string]*experiment.Experiment
+	deleted     []string
+}
+
+func (s *fakeExperimentService) Create(_ context.Context, opts experiment.CreateOptions) (*experiment.Experiment, error) {
+	namespaces := opts.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{"exp-new"}
+	}
+	for _, ns := range namespaces {
+		if ns == "" {
+			return nil, xerrors.Errorf("create: %w", experiment.ErrInvalidCreateOptions)
+		}
+		for _, e := range s.experiments {
+			if e.HasNamespace(ns) {
+				return nil, xerrors.Errorf("create: %w", experiment.ErrAlreadyExists)
+			}
+		}
+	}
+	e := &experiment.Experiment{ID: "exp-new", Name: opts.Name, Namespaces: namespaces}
+	s.experiments[e.ID] = e
+	return e, nil
+}
+
+func (s *fakeExperimentService) Get(_ context.Context, id string) (*experiment.Experiment, error) {
+	e, ok := s.experiments[id]
+	if !ok {
+		return nil, xerrors.Errorf("get: %w", experiment.ErrNotFound)
+	}
+	return e, nil
+}
+
+func (s *fakeExperimentService) List(_ context.Context) ([]experiment.Experiment, error) {
+	ret := []experiment.Experiment{}
+	for _, e := range s.experiments {
+		ret = append(ret, *e)
+	}
+	return ret, nil
+}
+
+func (s *fakeExperimentService) Delete(_ context.Context, id string) (*experiment.DeleteSummary, error) {
+	if _, ok := s.experiments[id]; !ok {
+		return nil, xerrors.Errorf("delete: %w", experiment.ErrNotFound)
+	}
+	delete(s.experiments, id)
+	s.deleted = append(s.deleted, id)
+	return &experiment.DeleteSummary{Deleted: map[string]int{"pods": 1, "namespaces": 1}}, nil
+}
+
+func TestExperimentHandler(t *testing.T) {
+	t.Parallel()
+	type request struct {
+		method, path, body string
+		wantCode           int
+		wantBody           string
+	}
+	tests := []struct {
+		name        string
+		requests    []request
+		wantDeleted []string
+	}{
+		{
+			name: "create, get, list and delete",
+			requests: []request{
+				{method: http.MethodPost, path: "/api/v1/experiments", body: `{"name":"binpacking","namespaces":["a","b"]}`, wantCode: http.StatusCreated, wantBody: `"namespaces":["a","b"]`},
+				{method: http.MethodGet, path: "/api/v1/experiments/exp-new", wantCode: http.StatusOK, wantBody: `"name":"binpacking"`},
+				{method: http.MethodGet, path: "/api/v1/experiments", wantCode: http.StatusOK, wantBody: `"id":"exp-new"`},
+				{method: http.MethodDelete, path: "/api/v1/experiments/exp-new", wantCode: http.StatusOK, wantBody: `"pods":1`},
+				{method: http.MethodGet, path: "/api/v1/experiments/exp-new", wantCode: http.StatusNotFound},
+			},
+			wantDeleted: []string{"exp-new"},
+		},
+		{
+			name: "create without the body",
+			requests: []request{
+				{method: http.MethodPost, path: "/api/v1/experiments", wantCode: http.StatusCreated, wantBody: `"namespaces":["exp-new"]`},
+			},
+		},
+		{
+			name: "errors",
+			requests: []request{
+				{method: http.MethodPost, path: "/api/v1/experiments", body: `{"namespaces":[""]}`, wantCode: http.StatusBadRequest},
+				{method: http.MethodPost, path: "/api/v1/experiments", body: `{"namespaces":["taken"]}`, wantCode: http.StatusConflict},
+				{method: http.MethodPost, path: "/api/v1/experiments", body: `{`, wantCode: http.StatusBadRequest},
+				{method: http.MethodDelete, path: "/api/v1/experiments/unknown", wantCode: http.StatusNotFound},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := &fakeExperimentService{experiments: map[string]*experiment.Experiment{
+				"exp-taken": {ID: "exp-taken", Namespaces: []string{"taken"}},
+			}}
+			h := NewExperimentHandler(func() di.ExperimentService { return s })
+			e := echo.New()
+			e.POST("/api/v1/experiments", h.Create)
+			e.GET("/api/v1/experiments", h.List)
+			e.GET("/api/v1/experiments/:id", h.Get)
+			e.DELETE("/api/v1/experiments/:id", h.Delete)
+
+			for _, r := range tt.requests {
+				req := httptest.NewRequest(r.method, r.path, strings.NewReader(r.body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+				assert.Equal(t, r.wantCode, rec.Code, "%s %s", r.method, r.path)
+				assert.Contains(t, rec.Body.String(), r.wantBody)
+			}
+			assert.Equal(t, tt.wantDeleted, s.deleted)
+		})
+	}
+}
+
+func TestExperimentHandler_Scope(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		path         string
+		experimentID string
+		body         string
+		wantCode     int
+		wantOpts     bulkpod.CreateOptions
+		wantNS       string
+	}{
+		{
+			name:     "not scoped",
+			path:     "/api/v1/pods/bulk",
+			body:     `{"template":{"apiVersion":"v1","kind":"Pod","metadata":{"namespace":"default"}},"count":1,"labels":{"app":"load"}}`,
+			wantCode: http.StatusOK,
+			wantOpts: bulkpod.CreateOptions{Count: 1, Labels: map[string]string{"app": "load"}},
+			wantNS:   "default",
+		},
+		{
+			name:         "Pods are labeled and created in the first namespace of the experiment",
+			path:         "/api/v1/pods/bulk",
+			experimentID: "exp-1",
+			body:         `{"template":{"apiVersion":"v1","kind":"Pod"},"count":1,"labels":{"app":"load"}}`,
+			wantCode:     http.StatusOK,
+			wantOpts:     bulkpod.CreateOptions{Count: 1, Labels: map[string]string{"app": "load", experiment.LabelKey: "exp-1"}},
+			wantNS:       "a",
+		},
+		{
+			name:         "Pods in the other namespace of the experiment",
+			path:         "/api/v1/pods/bulk",
+			experimentID: "exp-1",
+			body:         `{"template":{"apiVersion":"v1","kind":"Pod","metadata":{"namespace":"b"}},"count":1}`,
+			wantCode:     http.StatusOK,
+			wantOpts:     bulkpod.CreateOptions{Count: 1, Labels: map[string]string{experiment.LabelKey: "exp-1"}},
+			wantNS:       "b",
+		},
+		{
+			name:         "Pods out of the experiment",
+			path:         "/api/v1/pods/bulk",
+			experimentID: "exp-1",
+			body:         `{"template":{"apiVersion":"v1","kind":"Pod","metadata":{"namespace":"default"}},"count":1}`,
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			name:         "unknown experiment",
+			path:         "/api/v1/pods/bulk",
+			experimentID: "exp-unknown",
+			body:         `{"template":{"apiVersion":"v1","kind":"Pod"},"count":1}`,
+			wantCode:     http.StatusNotFound,
+		},
+		{
+			name:         "route not supporting the scope",
+			path:         "/api/v1/import",
+			experimentID: "exp-1",
+			body:         `{}`,
+			wantCode:     http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := &fakeExperimentService{experiments: map[string]*experiment.Experiment{
+				"exp-1": {ID: "exp-1", Namespaces: []string{"a", "b"}},
+			}}
+			bulkPodService := &fakeBulkPodService{}
+			e := echo.New()
+			e.Use(NewExperimentHandler(func() di.ExperimentService { return s }).Scope(sets.New("POST /api/v1/pods/bulk")))
+			e.POST("/api/v1/pods/bulk", NewBulkPodHandler(func() di.BulkPodService { return bulkPodService }).Create)
+			e.POST("/api/v1/import", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tt.experimentID != "" {
+				req.Header.Set(ExperimentHeader, tt.experimentID)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code, rec.Body.String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tt.wantNS, bulkPodService.got.Template.GetNamespace())
+			bulkPodService.got.Template = nil
+			assert.Equal(t, tt.wantOpts, bulkPodService.got)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"net"
 	"net/http"
 	"strconv"
 
@@ -8,6 +10,7 @@ import (
 	"k8s.io/klog/v2"
 	extenderv1 "k8s.io/kube-scheduler/extender/v1"
 
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
 )
 
@@ -39,7 +42,7 @@ func (h *ExtenderHandler) Filter(c echo.Context) error {
 	res, err := h.service.Filter(id, *req)
 	if err != nil {
 		klog.Errorf("failed to Filter request to the extender's actually host server: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError)
+		return echo.NewHTTPError(extenderErrorStatus(err))
 	}
 	return c.JSON(http.StatusOK, res)
 }
@@ -61,7 +64,7 @@ func (h *ExtenderHandler) Prioritize(c echo.Context) error {
 	res, err := h.service.Prioritize(id, *req)
 	if err != nil {
 		klog.Errorf("failed to Prioritize request to the extender's actually host server: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError)
+		return echo.NewHTTPError(extenderErrorStatus(err))
 	}
 	return c.JSON(http.StatusOK, res)
 }
@@ -83,7 +86,7 @@ func (h *ExtenderHandler) Preempt(c echo.Context) error {
 	res, err := h.service.Preempt(id, *req)
 	if err != nil {
 		klog.Errorf("failed to Preempt request to the extender's actually host server: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError)
+		return echo.NewHTTPError(extenderErrorStatus(err))
 	}
 	return c.JSON(http.StatusOK, res)
 }
@@ -105,7 +108,23 @@ func (h *ExtenderHandler) Bind(c echo.Context) error {
 	res, err := h.service.Bind(id, *req)
 	if err != nil {
 		klog.Errorf("failed to bind request to the extender's actually host server: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError)
+		// Propagate the status code from the extender so that the scheduler sees the same failure as without the proxy.
+		return echo.NewHTTPError(extenderErrorStatus(err))
 	}
 	return c.JSON(http.StatusOK, res)
 }
+
+// extenderErrorStatus returns the status code which the extender responded with,
+// http.StatusGatewayTimeout if the extender didn't respond within its httpTimeout,
+// or http.StatusInternalServerError if the request failed before getting the response.
+func extenderErrorStatus(err error) int {
+	var statusErr *extender.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,410 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender/annotation"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender/resultstore"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/storereflector"
+)
+
+// fakeReflector keeps the ResultStore registered by extender.Service.
+type fakeReflector struct {
+	store storereflector.ResultStore
+}
+
+func (r *fakeReflector) AddResultStore(store storereflector.ResultStore, _ string) {
+	r.store = store
+}
+
+func (r *fakeReflector) GetResultStore(_ string) (storereflector.ResultStore, bool) {
+	return r.store, r.store != nil
+}
+
+func (r *fakeReflector) ResisterResultSavingToInformer(_ clientset.Interface, _ <-chan struct{}) error {
+	return nil
+}
+
+// newStubExtender launches the stub extender server which responds to the given verb with the given status code and body.
+func newStubExtender(t *testing.T, verb string, statusCode int, body interface{}) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+verb {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		//nolint:errcheck // it's just a stub.
+		json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// callExtenderHandler sends args to the handler as the scheduler does, and returns the response or the error returned from the handler.
+func callExtenderHandler(t *testing.T, handle echo.HandlerFunc, args interface{}) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+	b, err := json.Marshal(args)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(b))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("0")
+	return rec, handle(c)
+}
+
+func TestExtenderHandler_Preempt(t *testing.T) {
+	t.Parallel()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+	tests := []struct {
+		name           string
+		statusCode     int
+		response       interface{}
+		wantStatusCode int
+		wantStored     string
+	}{
+		{
+			name:       "forward the request and store the node-to-victims map",
+			statusCode: http.StatusOK,
+			response: extenderv1.ExtenderPreemptionResult{
+				NodeNameToMetaVictims: map[string]*extenderv1.MetaVictims{
+					"node1": {Pods: []*extenderv1.MetaPod{{UID: "victim1"}}, NumPDBViolations: 1},
+				},
+			},
+			wantStatusCode: http.StatusOK,
+			// The results are keyed by the extender's URL.
+			wantStored: `{"{{url}}":{"NodeNameToMetaVictims":{"node1":{"Pods":[{"UID":"victim1"}],"NumPDBViolations":1}}}}`,
+		},
+		{
+			name:           "propagate the status code from the extender on failure",
+			statusCode:     http.StatusServiceUnavailable,
+			response:       map[string]string{},
+			wantStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stub := newStubExtender(t, "preempt", tt.statusCode, tt.response)
+			r := &fakeReflector{}
+			s, err := extender.New(fake.NewSimpleClientset(), []configv1.Extender{{URLPrefix: stub.URL, PreemptVerb: "preempt"}}, r)
+			require.NoError(t, err)
+			h := NewExtenderHandler(s)
+
+			rec, err := callExtenderHandler(t, h.Preempt, extenderv1.ExtenderPreemptionArgs{
+				Pod:                   pod,
+				NodeNameToMetaVictims: map[string]*extenderv1.MetaVictims{"node1": {}},
+			})
+
+			stored := r.store.GetStoredResult(pod)
+			if tt.wantStatusCode != http.StatusOK {
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, tt.wantStatusCode, httpErr.Code)
+				assert.Nil(t, stored)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			var got extenderv1.ExtenderPreemptionResult
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tt.response, got)
+			assert.JSONEq(t, strings.ReplaceAll(tt.wantStored, "{{url}}", stub.URL), stored[annotation.ExtenderPreemptResultAnnotationKey])
+		})
+	}
+}
+
+func TestExtenderHandler_Bind(t *testing.T) {
+	t.Parallel()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+	tests := []struct {
+		name           string
+		statusCode     int
+		response       interface{}
+		wantStatusCode int
+		// wantStoredError is the error stored as the result of the extender.
+		wantStoredError string
+	}{
+		{
+			name:           "forward the request and store the result",
+			statusCode:     http.StatusOK,
+			response:       extenderv1.ExtenderBindingResult{},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:            "store the error returned in the result",
+			statusCode:      http.StatusOK,
+			response:        extenderv1.ExtenderBindingResult{Error: "node1 is full"},
+			wantStatusCode:  http.StatusOK,
+			wantStoredError: "node1 is full",
+		},
+		{
+			name:            "propagate the status code from the extender on failure, and store the failure",
+			statusCode:      http.StatusConflict,
+			response:        map[string]string{},
+			wantStatusCode:  http.StatusConflict,
+			wantStoredError: "send bind request: failed bind with extender at URL {{url}}/bind, code 409",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stub := newStubExtender(t, "bind", tt.statusCode, tt.response)
+			r := &fakeReflector{}
+			s, err := extender.New(fake.NewSimpleClientset(), []configv1.Extender{{URLPrefix: stub.URL, BindVerb: "bind"}}, r)
+			require.NoError(t, err)
+			h := NewExtenderHandler(s)
+
+			rec, err := callExtenderHandler(t, h.Bind, extenderv1.ExtenderBindingArgs{PodName: pod.Name, PodNamespace: pod.Namespace, Node: "node1"})
+
+			if tt.wantStatusCode != http.StatusOK {
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, tt.wantStatusCode, httpErr.Code)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rec.Code)
+			}
+
+			stored := map[string]extenderv1.ExtenderBindingResult{}
+			require.NoError(t, json.Unmarshal([]byte(r.store.GetStoredResult(pod)[annotation.ExtenderBindResultAnnotationKey]), &stored))
+			wantErr := strings.ReplaceAll(tt.wantStoredError, "{{url}}", stub.URL)
+			assert.Equal(t, map[string]extenderv1.ExtenderBindingResult{stub.URL: {Error: wantErr}}, stored)
+		})
+	}
+}
+
+// newHangingExtender launches the stub extender server which never responds until the test finishes.
+func newHangingExtender(t *testing.T) *httptest.Server {
+	t.Helper()
+	done := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(func() {
+		close(done)
+		s.Close()
+	})
+	return s
+}
+
+func TestExtenderHandler_Filter_timeout(t *testing.T) {
+	t.Parallel()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+	nodeNames := []string{"node1", "node2"}
+	tests := []struct {
+		name           string
+		ignorable      bool
+		wantStatusCode int
+	}{
+		{
+			name:           "return the error if the extender isn't ignorable",
+			ignorable:      false,
+			wantStatusCode: http.StatusGatewayTimeout,
+		},
+		{
+			name:           "pass all nodes and store the error if the extender is ignorable",
+			ignorable:      true,
+			wantStatusCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stub := newHangingExtender(t)
+			r := &fakeReflector{}
+			s, err := extender.New(fake.NewSimpleClientset(), []configv1.Extender{{
+				URLPrefix:   stub.URL,
+				FilterVerb:  "filter",
+				HTTPTimeout: metav1.Duration{Duration: 100 * time.Millisecond},
+				Ignorable:   tt.ignorable,
+			}}, r)
+			require.NoError(t, err)
+			h := NewExtenderHandler(s)
+
+			rec, err := callExtenderHandler(t, h.Filter, extenderv1.ExtenderArgs{Pod: pod, NodeNames: &nodeNames})
+
+			stored := r.store.GetStoredResult(pod)
+			if !tt.ignorable {
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, tt.wantStatusCode, httpErr.Code)
+				assert.Nil(t, stored)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatusCode, rec.Code)
+			var got extenderv1.ExtenderFilterResult
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, extenderv1.ExtenderFilterResult{NodeNames: &nodeNames}, got)
+
+			ignored := map[string]map[string]string{}
+			require.NoError(t, json.Unmarshal([]byte(stored[annotation.ExtenderIgnoredResultAnnotationKey]), &ignored))
+			assert.Contains(t, ignored[stub.URL]["filter"], "Client.Timeout exceeded")
+		})
+	}
+}
+
+func TestExtenderHandler_Filter_rawExchange(t *testing.T) {
+	t.Parallel()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+	nodes := &corev1.NodeList{Items: []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node2"}},
+	}}
+	fullRequest, err := json.Marshal(extenderv1.ExtenderArgs{Pod: pod, Nodes: nodes})
+	require.NoError(t, err)
+	tests := []struct {
+		name          string
+		maxSize       int
+		nodeNamesOnly bool
+		wantRequest   string
+		wantResponse  string
+		wantTruncated bool
+	}{
+		{
+			name:         "record the request and the response as is",
+			wantRequest:  string(fullRequest),
+			wantResponse: `{"Nodes":null,"NodeNames":["node1"],"FailedNodes":{"node2":"not ready"},"FailedAndUnresolvableNodes":null,"Error":""}`,
+		},
+		{
+			name:          "record the node names only",
+			nodeNamesOnly: true,
+			wantRequest:   `{"Pod":{"metadata":{"name":"pod1","namespace":"default","creationTimestamp":null},"spec":{"containers":null},"status":{}},"Nodes":null,"NodeNames":["node1","node2"]}`,
+			wantResponse:  `{"Nodes":null,"NodeNames":["node1"],"FailedNodes":{"node2":"not ready"},"FailedAndUnresolvableNodes":null,"Error":""}`,
+		},
+		{
+			name:          "truncate the request and the response longer than maxSize",
+			maxSize:       10,
+			nodeNamesOnly: true,
+			wantRequest:   `{"Pod":{"m`,
+			wantResponse:  `{"Nodes":n`,
+			wantTruncated: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stub := newStubExtender(t, "filter", http.StatusOK, extenderv1.ExtenderFilterResult{
+				NodeNames:   &[]string{"node1"},
+				FailedNodes: extenderv1.FailedNodesMap{"node2": "not ready"},
+			})
+			r := &fakeReflector{}
+			s, err := extender.New(fake.NewSimpleClientset(), []configv1.Extender{{URLPrefix: stub.URL, FilterVerb: "filter"}}, r, extender.WithRawExchange(tt.maxSize, tt.nodeNamesOnly))
+			require.NoError(t, err)
+			h := NewExtenderHandler(s)
+
+			_, err = callExtenderHandler(t, h.Filter, extenderv1.ExtenderArgs{Pod: pod, Nodes: nodes})
+			require.NoError(t, err)
+
+			stored := map[string]map[string]resultstore.RawExchange{}
+			require.NoError(t, json.Unmarshal([]byte(r.store.GetStoredResult(pod)[annotation.ExtenderRawExchangeAnnotationKey]), &stored))
+			got := stored[stub.URL]["filter"]
+			assert.Equal(t, tt.wantTruncated, got.Truncated)
+			if tt.wantTruncated {
+				assert.Equal(t, tt.wantRequest, got.Request)
+				assert.Equal(t, tt.wantResponse, got.Response)
+				return
+			}
+			assert.JSONEq(t, tt.wantRequest, got.Request)
+			assert.JSONEq(t, tt.wantResponse, got.Response)
+		})
+	}
+}
+
+func TestExtenderHandler_Filter_managedResources(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		requests    corev1.ResourceList
+		wantNodes   []string
+		wantStored  string
+		wantSkipped string
+	}{
+		{
+			name:       "call the extender for the pod requesting the managed resource",
+			requests:   corev1.ResourceList{"example.com/gpu": resource.MustParse("1")},
+			wantNodes:  []string{"node1"},
+			wantStored: `{"{{url}}":{"Nodes":null,"NodeNames":["node1"],"FailedNodes":{"node2":"no gpu"},"FailedAndUnresolvableNodes":null,"Error":""}}`,
+		},
+		{
+			name:        "skip the extender for the pod requesting none of the managed resources",
+			requests:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			wantNodes:   []string{"node1", "node2"},
+			wantStored:  `{}`,
+			wantSkipped: `{"{{url}}":{"filter":"skipped: no managed resource"}}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stub := newStubExtender(t, "filter", http.StatusOK, extenderv1.ExtenderFilterResult{
+				NodeNames:   &[]string{"node1"},
+				FailedNodes: extenderv1.FailedNodesMap{"node2": "no gpu"},
+			})
+			r := &fakeReflector{}
+			s, err := extender.New(fake.NewSimpleClientset(), []configv1.Extender{{
+				URLPrefix:        stub.URL,
+				FilterVerb:       "filter",
+				NodeCacheCapable: true,
+				ManagedResources: []configv1.ExtenderManagedResource{{Name: "example.com/gpu", IgnoredByScheduler: true}},
+			}}, r)
+			require.NoError(t, err)
+			h := NewExtenderHandler(s)
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: tt.requests}}}},
+			}
+			rec, err := callExtenderHandler(t, h.Filter, extenderv1.ExtenderArgs{Pod: pod, NodeNames: &[]string{"node1", "node2"}})
+			require.NoError(t, err)
+
+			var got extenderv1.ExtenderFilterResult
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			require.NotNil(t, got.NodeNames)
+			assert.Equal(t, tt.wantNodes, *got.NodeNames)
+
+			stored := r.store.GetStoredResult(pod)
+			assert.JSONEq(t, strings.ReplaceAll(tt.wantStored, "{{url}}", stub.URL), stored[annotation.ExtenderFilterResultAnnotationKey])
+			if tt.wantSkipped != "" {
+				assert.JSONEq(t, strings.ReplaceAll(tt.wantSkipped, "{{url}}", stub.URL), stored[annotation.ExtenderSkippedResultAnnotationKey])
+			} else {
+				assert.NotContains(t, stored, annotation.ExtenderSkippedResultAnnotationKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// ExtenderInjectionHandler is a handler to manage the latencies and the failures injected to the requests to extenders.
+type ExtenderInjectionHandler struct {
+	service di.ExtenderInjectionService
+}
+
+// NewExtenderInjectionHandler initializes ExtenderInjectionHandler.
+// The handler responds with 404 if s is nil.
+func NewExtenderInjectionHandler(s di.ExtenderInjectionService) *ExtenderInjectionHandler {
+	return &ExtenderInjectionHandler{
+		service: s,
+	}
+}
+
+// errNoExtenderProxied is the response when there is no extender to inject to.
+var errNoExtenderProxied = echo.NewHTTPError(http.StatusNotFound, "no extender is proxied by the simulator")
+
+// List returns the current injections keyed by the index of the extender.
+func (h *ExtenderInjectionHandler) List(c echo.Context) error {
+	if h.service == nil {
+		return errNoExtenderProxied
+	}
+	return c.JSON(http.StatusOK, h.service.Injections())
+}
+
+// Set replaces the injection to the extender at the index specified by `id`.
+// The empty injection stops the injection.
+func (h *ExtenderInjectionHandler) Set(c echo.Context) error {
+	if h.service == nil {
+		return errNoExtenderProxied
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		klog.Errorf("failed to convert id to integer: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+	req := new(extender.Injection)
+	if err := c.Bind(req); err != nil {
+		klog.Errorf("failed to bind the injection request: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	if err := h.service.SetInjection(id, *req); err != nil {
+		if errors.Is(err, extender.ErrInvalidInjection) {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		klog.Errorf("failed to set the injection: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/extender/annotation"
+)
+
+func TestExtenderInjectionHandler_Set(t *testing.T) {
+	t.Parallel()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}
+	tests := []struct {
+		name               string
+		id                 string
+		body               string
+		wantStatusCode     int
+		wantFilterStatus   int
+		wantStoredInjected string
+	}{
+		{
+			name:               "inject the failure to the filter request",
+			id:                 "0",
+			body:               `{"errorRate": 1}`,
+			wantStatusCode:     http.StatusNoContent,
+			wantFilterStatus:   http.StatusInternalServerError,
+			wantStoredInjected: `{"{{url}}":{"filter":{"error":"failure injected by the simulator"}}}`,
+		},
+		{
+			name:               "inject the latency to the filter request",
+			id:                 "0",
+			body:               `{"minLatency": "10ms"}`,
+			wantStatusCode:     http.StatusNoContent,
+			wantFilterStatus:   http.StatusOK,
+			wantStoredInjected: `{"{{url}}":{"filter":{"latency":"10ms"}}}`,
+		},
+		{
+			name:             "reject the invalid errorRate",
+			id:               "0",
+			body:             `{"errorRate": 2}`,
+			wantStatusCode:   http.StatusBadRequest,
+			wantFilterStatus: http.StatusOK,
+		},
+		{
+			name:             "reject the extender which doesn't exist",
+			id:               "1",
+			body:             `{"errorRate": 1}`,
+			wantStatusCode:   http.StatusBadRequest,
+			wantFilterStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stub := newStubExtender(t, "filter", http.StatusOK, extenderv1.ExtenderFilterResult{NodeNames: &[]string{"node1"}})
+			r := &fakeReflector{}
+			s, err := extender.New(fake.NewSimpleClientset(), []configv1.Extender{{URLPrefix: stub.URL, FilterVerb: "filter"}}, r, extender.WithInjectionSeed(1))
+			require.NoError(t, err)
+			h := NewExtenderInjectionHandler(s)
+
+			req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			require.NoError(t, h.Set(c))
+			assert.Equal(t, tt.wantStatusCode, rec.Code)
+
+			_, err = callExtenderHandler(t, NewExtenderHandler(s).Filter, extenderv1.ExtenderArgs{Pod: pod, NodeNames: &[]string{"node1"}})
+			if tt.wantFilterStatus != http.StatusOK {
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, tt.wantFilterStatus, httpErr.Code)
+			} else {
+				require.NoError(t, err)
+			}
+
+			stored := r.store.GetStoredResult(pod)
+			if tt.wantStoredInjected == "" {
+				assert.NotContains(t, stored, annotation.ExtenderInjectedResultAnnotationKey)
+				return
+			}
+			assert.JSONEq(t, strings.ReplaceAll(tt.wantStoredInjected, "{{url}}", stub.URL), stored[annotation.ExtenderInjectedResultAnnotationKey])
+		})
+	}
+}
+
+func TestExtenderInjectionHandler_List(t *testing.T) {
+	t.Parallel()
+
+	r := &fakeReflector{}
+	s, err := extender.New(fake.NewSimpleClientset(), []configv1.Extender{{URLPrefix: "http://example.com", FilterVerb: "filter"}}, r,
+		extender.WithInjections(map[int]extender.Injection{0: {ErrorRate: 0.5}}))
+	require.NoError(t, err)
+	h := NewExtenderInjectionHandler(s)
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	require.NoError(t, h.List(c))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var got map[int]extender.Injection
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, map[int]extender.Injection{0: {ErrorRate: 0.5}}, got)
+}
+
+func TestExtenderInjectionHandler_noExtender(t *testing.T) {
+	t.Parallel()
+	h := NewExtenderInjectionHandler(nil)
+
+	e := echo.New()
+	e.GET("/extender/injections", h.List)
+	e.PUT("/extender/injections/:id", h.Set)
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/extender/injections", nil),
+		httptest.NewRequest(http.MethodPut, "/extender/injections/0", strings.NewReader(`{"errorRate": 0.5}`)),
+	} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code, "%s %s", req.Method, req.URL.Path)
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// healthCheckTimeout is the timeout of each health check.
+const healthCheckTimeout = 5 * time.Second
+
+// HealthHandler is handler for the liveness and readiness probes.
+type HealthHandler struct {
+	liveness  []di.HealthCheck
+	readiness []di.HealthCheck
+}
+
+// HealthResponse is the response of /healthz and /readyz.
+type HealthResponse struct {
+	// Status is "ok" if all the checks pass, and "failed" otherwise.
+	Status string              `json:"status"`
+	Checks []HealthCheckResult `json:"checks"`
+}
+
+// HealthCheckResult is the result of a check.
+type HealthCheckResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+const (
+	healthStatusOK     = "ok"
+	healthStatusFailed = "failed"
+)
+
+// NewHealthHandler initializes HealthHandler.
+func NewHealthHandler(liveness, readiness []di.HealthCheck) *HealthHandler {
+	return &HealthHandler{liveness: liveness, readiness: readiness}
+}
+
+// Healthz runs the liveness checks, and responds with 503 if any of them fails.
+func (h *HealthHandler) Healthz(c echo.Context) error {
+	return runHealthChecks(c, h.liveness)
+}
+
+// Readyz runs the readiness checks, and responds with 503 if any of them fails.
+func (h *HealthHandler) Readyz(c echo.Context) error {
+	return runHealthChecks(c, h.readiness)
+}
+
+func runHealthChecks(c echo.Context, checks []di.HealthCheck) error {
+	res := HealthResponse{Status: healthStatusOK, Checks: make([]HealthCheckResult, 0, len(checks))}
+	for _, check := range checks {
+		result := HealthCheckResult{Name: check.Name, Status: healthStatusOK}
+		ctx, cancel := context.WithTimeout(c.Request().Context(), healthCheckTimeout)
+		err := check.Check(ctx)
+		cancel()
+		if err != nil {
+			klog.Warningf("health check %s failed: %v", check.Name, err)
+			result.Status = healthStatusFailed
+			result.Error = err.Error()
+			res.Status = healthStatusFailed
+		}
+		res.Checks = append(res.Checks, result)
+	}
+
+	if res.Status != healthStatusOK {
+		return c.JSON(http.StatusServiceUnavailable, res)
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+func TestHealthHandler(t *testing.T) {
+	t.Parallel()
+	healthy := func(_ context.Context) error { return nil }
+	tests := []struct {
+		name       string
+		path       string
+		liveness   []di.HealthCheck
+		readiness  []di.HealthCheck
+		wantCode   int
+		wantResult HealthResponse
+	}{
+		{
+			name:     "healthz responds with 200 when all the checks pass",
+			path:     "/healthz",
+			liveness: []di.HealthCheck{{Name: "etcd", Check: healthy}, {Name: "kube-apiserver", Check: healthy}},
+			wantCode: http.StatusOK,
+			wantResult: HealthResponse{
+				Status: "ok",
+				Checks: []HealthCheckResult{{Name: "etcd", Status: "ok"}, {Name: "kube-apiserver", Status: "ok"}},
+			},
+		},
+		{
+			name: "healthz responds with 503 with the failing check when etcd is unhealthy",
+			path: "/healthz",
+			liveness: []di.HealthCheck{
+				{Name: "etcd", Check: func(_ context.Context) error { return xerrors.New("context deadline exceeded") }},
+				{Name: "kube-apiserver", Check: healthy},
+			},
+			wantCode: http.StatusServiceUnavailable,
+			wantResult: HealthResponse{
+				Status: "failed",
+				Checks: []HealthCheckResult{
+					{Name: "etcd", Status: "failed", Error: "context deadline exceeded"},
+					{Name: "kube-apiserver", Status: "ok"},
+				},
+			},
+		},
+		{
+			name:     "readyz responds with 503 while importing resources",
+			path:     "/readyz",
+			liveness: []di.HealthCheck{{Name: "etcd", Check: healthy}},
+			readiness: []di.HealthCheck{
+				{Name: "etcd", Check: healthy},
+				{Name: "import", Check: func(_ context.Context) error { return xerrors.New("importing resources from the target cluster") }},
+			},
+			wantCode: http.StatusServiceUnavailable,
+			wantResult: HealthResponse{
+				Status: "failed",
+				Checks: []HealthCheckResult{
+					{Name: "etcd", Status: "ok"},
+					{Name: "import", Status: "failed", Error: "importing resources from the target cluster"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := NewHealthHandler(tt.liveness, tt.readiness)
+			e := echo.New()
+			e.GET("/healthz", h.Healthz)
+			e.GET("/readyz", h.Readyz)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantCode, rec.Code)
+			var got HealthResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tt.wantResult, got)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/xerrors"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/reset"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// JobHandler is handler for running the long-running operations as the background jobs.
+type JobHandler struct {
+	manager         func() di.JobManager
+	snapshotService func() di.SnapshotService
+	resetService    di.ResetService
+	replayService   di.ReplayService
+}
+
+// JobRequest is the request to submit a job.
+type JobRequest struct {
+	Type job.Type `json:"type"`
+	// Resources are the resources loaded by the import job.
+	Resources *ResourcesForLoad `json:"resources,omitempty"`
+	// Reset deletes the existing resources before the import job loads Resources.
+	Reset bool `json:"reset,omitempty"`
+}
+
+// NewJobHandler initializes JobHandler.
+// replay can be nil when the replay isn't enabled.
+func NewJobHandler(m func() di.JobManager, s func() di.SnapshotService, r di.ResetService, replay di.ReplayService) *JobHandler {
+	return &JobHandler{manager: m, snapshotService: s, resetService: r, replayService: replay}
+}
+
+// Submit starts the job in background, and returns it without waiting for it to finish.
+func (h *JobHandler) Submit(c echo.Context) error {
+	req := new(JobRequest)
+	if err := bindJSONOrYAML(c, req); err != nil {
+		klog.Errorf("failed to bind job request: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	fn, err := h.jobFunc(req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	j, err := h.manager().Submit(req.Type, fn)
+	if errors.Is(err, job.ErrConflict) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to submit job: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusAccepted, j)
+}
+
+// jobFunc returns the operation of the job requested.
+func (h *JobHandler) jobFunc(req *JobRequest) (job.Func, error) {
+	switch req.Type {
+	case job.TypeExport:
+		return func(ctx context.Context, report func(job.Progress)) (interface{}, error) {
+			report(job.Progress{Total: 1, Message: "exporting the resources"})
+			rs, err := h.snapshotService().Snap(ctx, h.snapshotService().Sanitize())
+			if err != nil {
+				return nil, xerrors.Errorf("export resources: %w", err)
+			}
+			report(job.Progress{Done: 1, Total: 1})
+			return rs, nil
+		}, nil
+	case job.TypeImport:
+		if req.Resources == nil {
+			return nil, xerrors.New("resources are required for the import job")
+		}
+		resources := convertToResourcesApplyConfiguration(req.Resources)
+		return func(ctx context.Context, report func(job.Progress)) (interface{}, error) {
+			total, done := 1, 0
+			if req.Reset {
+				total++
+				report(job.Progress{Total: total, Message: "deleting the existing resources"})
+				if _, err := h.resetService.Clean(ctx, reset.CleanOptions{}); err != nil {
+					return nil, xerrors.Errorf("clean resources before loading: %w", err)
+				}
+				done++
+			}
+			report(job.Progress{Done: done, Total: total, Message: "loading the resources"})
+			if err := h.snapshotService().Load(ctx, resources); err != nil {
+				return nil, xerrors.Errorf("load resources: %w", err)
+			}
+			report(job.Progress{Done: total, Total: total})
+			return nil, nil
+		}, nil
+	case job.TypeReset:
+		return func(ctx context.Context, report func(job.Progress)) (interface{}, error) {
+			report(job.Progress{Total: 1, Message: "resetting the resources and the scheduler configuration"})
+			if err := h.resetService.Reset(ctx); err != nil {
+				return nil, xerrors.Errorf("reset: %w", err)
+			}
+			report(job.Progress{Done: 1, Total: 1})
+			return nil, nil
+		}, nil
+	case job.TypeReplay:
+		if h.replayService == nil {
+			return nil, xerrors.New("the replay isn't enabled")
+		}
+		return func(ctx context.Context, report func(job.Progress)) (interface{}, error) {
+			report(job.Progress{Total: 1, Message: "replaying the recorded events"})
+			if err := h.replayService.Replay(ctx); err != nil {
+				return nil, xerrors.Errorf("replay: %w", err)
+			}
+			report(job.Progress{Done: 1, Total: 1})
+			return nil, nil
+		}, nil
+	default:
+		return nil, xerrors.Errorf("unknown job type %q", req.Type)
+	}
+}
+
+// List returns the jobs kept in the order they are submitted.
+func (h *JobHandler) List(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.manager().List())
+}
+
+// Get returns the state, the progress and the result of the job.
+func (h *JobHandler) Get(c echo.Context) error {
+	j, err := h.manager().Get(c.Param("id"))
+	if errors.Is(err, job.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to get job: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusOK, j)
+}
+
+// Cancel cancels the job. It's accepted while the job is pending or running.
+func (h *JobHandler) Cancel(c echo.Context) error {
+	j, err := h.manager().Cancel(c.Param("id"))
+	if errors.Is(err, job.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if errors.Is(err, job.ErrFinished) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to cancel job: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+	return c.JSON(http.StatusAccepted, j)
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/snapshot"
+)
+
+// slowReplayService replays until the context is cancelled.
+type slowReplayService struct {
+	started chan struct{}
+}
+
+func (s *slowReplayService) Replay(ctx context.Context) error {
+	close(s.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func newJobServer(replay *slowReplayService) (*echo.Echo, *fakeSnapshotService) {
+	snapshotService := &fakeSnapshotService{snapped: &snapshot.ResourcesForSnap{}}
+	var replayService di.ReplayService
+	if replay != nil {
+		replayService = replay
+	}
+	manager := job.NewManager(job.Options{ExclusiveTypes: job.DefaultExclusiveTypes})
+	h := NewJobHandler(func() di.JobManager { return manager }, func() di.SnapshotService { return snapshotService }, &fakeResetService{}, replayService)
+	e := echo.New()
+	e.POST("/api/v1/jobs", h.Submit)
+	e.GET("/api/v1/jobs", h.List)
+	e.GET("/api/v1/jobs/:id", h.Get)
+	e.DELETE("/api/v1/jobs/:id", h.Cancel)
+	return e, snapshotService
+}
+
+func doJobRequest(t *testing.T, e *echo.Echo, method, path, body string) (int, *job.Job) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code >= http.StatusBadRequest {
+		return rec.Code, nil
+	}
+	j := &job.Job{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), j))
+	return rec.Code, j
+}
+
+func waitJobState(t *testing.T, e *echo.Echo, id string, state job.State) *job.Job {
+	t.Helper()
+	var got *job.Job
+	require.Eventually(t, func() bool {
+		code, j := doJobRequest(t, e, http.MethodGet, "/api/v1/jobs/"+id, "")
+		require.Equal(t, http.StatusOK, code)
+		got = j
+		return j.State == state
+	}, wait.ForeverTestTimeout, time.Millisecond)
+	return got
+}
+
+func TestJobHandler_export(t *testing.T) {
+	t.Parallel()
+	e, snapshotService := newJobServer(nil)
+
+	code, submitted := doJobRequest(t, e, http.MethodPost, "/api/v1/jobs", `{"type":"export"}`)
+	require.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, job.TypeExport, submitted.Type)
+
+	succeeded := waitJobState(t, e, submitted.ID, job.StateSucceeded)
+	assert.Equal(t, job.Progress{Done: 1, Total: 1}, succeeded.Progress)
+	assert.NotNil(t, succeeded.Result)
+	assert.True(t, snapshotService.sanitized)
+
+	code, _ = doJobRequest(t, e, http.MethodDelete, "/api/v1/jobs/"+submitted.ID, "")
+	assert.Equal(t, http.StatusConflict, code, "the finished job can't be cancelled")
+}
+
+func TestJobHandler_cancelReplay(t *testing.T) {
+	t.Parallel()
+	replay := &slowReplayService{started: make(chan struct{})}
+	e, _ := newJobServer(replay)
+
+	code, submitted := doJobRequest(t, e, http.MethodPost, "/api/v1/jobs", `{"type":"replay"}`)
+	require.Equal(t, http.StatusAccepted, code)
+	<-replay.started
+	running := waitJobState(t, e, submitted.ID, job.StateRunning)
+	assert.Equal(t, "replaying the recorded events", running.Progress.Message)
+
+	code, _ = doJobRequest(t, e, http.MethodPost, "/api/v1/jobs", `{"type":"replay"}`)
+	assert.Equal(t, http.StatusConflict, code, "only one replay can run")
+
+	code, _ = doJobRequest(t, e, http.MethodDelete, "/api/v1/jobs/"+submitted.ID, "")
+	require.Equal(t, http.StatusAccepted, code)
+	waitJobState(t, e, submitted.ID, job.StateCancelled)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/jobs", nil))
+	var jobs []job.Job
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &jobs))
+	require.Len(t, jobs, 1)
+	assert.Equal(t, submitted.ID, jobs[0].ID)
+}
+
+func TestJobHandler_invalidRequests(t *testing.T) {
+	t.Parallel()
+	e, _ := newJobServer(nil)
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "unknown type",
+			method:   http.MethodPost,
+			path:     "/api/v1/jobs",
+			body:     `{"type":"backup"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "import without resources",
+			method:   http.MethodPost,
+			path:     "/api/v1/jobs",
+			body:     `{"type":"import"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "replay isn't enabled",
+			method:   http.MethodPost,
+			path:     "/api/v1/jobs",
+			body:     `{"type":"replay"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "get unknown job",
+			method:   http.MethodGet,
+			path:     "/api/v1/jobs/unknown",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "cancel unknown job",
+			method:   http.MethodDelete,
+			path:     "/api/v1/jobs/unknown",
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			code, _ := doJobRequest(t, e, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.wantCode, code)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/oplog"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// LogsHandler is handler for reading the recent logs of the simulator.
+type LogsHandler struct {
+	service di.LogService
+}
+
+// NewLogsHandler initializes LogsHandler.
+func NewLogsHandler(s di.LogService) *LogsHandler {
+	return &LogsHandler{service: s}
+}
+
+// logFilter gets the filter of the logs given by the client in `level` and `component`.
+func logFilter(c echo.Context) (oplog.Filter, error) {
+	f := oplog.Filter{Level: c.QueryParam("level")}
+	if f.Level != "" && f.Level != oplog.LevelInfo && f.Level != oplog.LevelError {
+		return oplog.Filter{}, xerrors.Errorf("level must be %q or %q: %q", oplog.LevelInfo, oplog.LevelError, f.Level)
+	}
+	if s := c.QueryParam("component"); s != "" {
+		for _, component := range strings.Split(s, ",") {
+			f.Components = append(f.Components, strings.TrimSpace(component))
+		}
+	}
+	return f, nil
+}
+
+// List returns the recent logs, from the oldest one.
+func (h *LogsHandler) List(c echo.Context) error {
+	f, err := logFilter(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	limit := 0
+	if s := c.QueryParam("limit"); s != "" {
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "limit must be a non-negative integer")
+		}
+	}
+
+	return c.JSON(http.StatusOK, h.service.Entries(f, limit))
+}
+
+// Stream streams the logs written from now on as the newline-delimited JSON until the client disconnects.
+func (h *LogsHandler) Stream(c echo.Context) error {
+	f, err := logFilter(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	ctx := c.Request().Context()
+	sub := h.service.Subscribe(f)
+	defer sub.Close()
+
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Response().WriteHeader(http.StatusOK)
+	c.Response().Flush()
+	enc := json.NewEncoder(c.Response())
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-sub.C():
+			if !ok {
+				return nil
+			}
+			if err := enc.Encode(e); err != nil {
+				// The client has gone away.
+				return nil
+			}
+			c.Response().Flush()
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/oplog"
+)
+
+func newLogsServer(b *oplog.Buffer) *echo.Echo {
+	h := NewLogsHandler(b)
+	e := echo.New()
+	e.GET("/api/v1/logs", h.List)
+	e.GET("/api/v1/logs/stream", h.Stream)
+	return e
+}
+
+func TestLogsHandler_List(t *testing.T) {
+	t.Parallel()
+	b := oplog.NewBuffer(3)
+	b.Add(oplog.Entry{Level: oplog.LevelInfo, Component: oplog.ComponentSyncer, Message: "dropped"})
+	b.Add(oplog.Entry{Level: oplog.LevelInfo, Component: oplog.ComponentSyncer, Message: "synced"})
+	b.Add(oplog.Entry{Level: oplog.LevelError, Component: oplog.ComponentImporter, Message: "import failed"})
+	b.Add(oplog.Entry{Level: oplog.LevelError, Component: oplog.ComponentReplayer, Message: "replay failed"})
+	e := newLogsServer(b)
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		want     []string
+	}{
+		{
+			name:     "all the logs kept",
+			wantCode: http.StatusOK,
+			want:     []string{"synced", "import failed", "replay failed"},
+		},
+		{
+			name:     "errors only",
+			query:    "?level=error",
+			wantCode: http.StatusOK,
+			want:     []string{"import failed", "replay failed"},
+		},
+		{
+			name:     "components",
+			query:    "?component=syncer,%20importer",
+			wantCode: http.StatusOK,
+			want:     []string{"synced", "import failed"},
+		},
+		{
+			name:     "limit",
+			query:    "?level=error&limit=1",
+			wantCode: http.StatusOK,
+			want:     []string{"replay failed"},
+		},
+		{
+			name:     "unknown level",
+			query:    "?level=debug",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid limit",
+			query:    "?limit=-1",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/logs"+tt.query, nil))
+			require.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var entries []oplog.Entry
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+			got := []string{}
+			for _, entry := range entries {
+				got = append(got, entry.Message)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLogsHandler_Stream(t *testing.T) {
+	t.Parallel()
+	b := oplog.NewBuffer(oplog.DefaultCapacity)
+	b.Add(oplog.Entry{Level: oplog.LevelError, Component: oplog.ComponentSyncer, Message: "before streaming"})
+	server := httptest.NewServer(newLogsServer(b))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/logs/stream?level=error&component=syncer")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, echo.MIMEApplicationJSON, resp.Header.Get(echo.HeaderContentType))
+
+	// The handler subscribes before responding with the header, so the logs added from now on are streamed.
+	b.Add(oplog.Entry{Level: oplog.LevelInfo, Component: oplog.ComponentSyncer, Message: "not an error"})
+	b.Add(oplog.Entry{Level: oplog.LevelError, Component: oplog.ComponentImporter, Message: "another component"})
+	b.Add(oplog.Entry{Level: oplog.LevelError, Component: oplog.ComponentSyncer, Message: "sync failed", Time: time.Unix(0, 0).UTC()})
+
+	lines := make(chan string, 3)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	select {
+	case line := <-lines:
+		got := oplog.Entry{}
+		require.NoError(t, json.Unmarshal([]byte(line), &got))
+		assert.Equal(t, oplog.Entry{Level: oplog.LevelError, Component: oplog.ComponentSyncer, Message: "sync failed", Time: time.Unix(0, 0).UTC()}, got)
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("no log is streamed")
+	}
+}
+
+func TestLogsHandler_Stream_invalidFilter(t *testing.T) {
+	t.Parallel()
+	rec := httptest.NewRecorder()
+	newLogsServer(oplog.NewBuffer(oplog.DefaultCapacity)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/logs/stream?level=debug", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/nodefailure"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// NodeFailureHandler is handler for injecting the failures of the nodes.
+type NodeFailureHandler struct {
+	service func() di.NodeFailureService
+}
+
+// NodeFailureRequest is the request to fail a node. The empty body is allowed.
+type NodeFailureRequest struct {
+	// NoExecute adds the NoExecute taint of node.kubernetes.io/not-ready as well as the NoSchedule one.
+	NoExecute bool `json:"noExecute"`
+	// EvictPods deletes the Pods on the node which don't tolerate the NoExecute taint.
+	EvictPods bool `json:"evictPods"`
+}
+
+// NewNodeFailureHandler initializes NodeFailureHandler.
+func NewNodeFailureHandler(s func() di.NodeFailureService) *NodeFailureHandler {
+	return &NodeFailureHandler{service: s}
+}
+
+// Fail makes the node NotReady and unschedulable.
+func (h *NodeFailureHandler) Fail(c echo.Context) error {
+	req := new(NodeFailureRequest)
+	if err := bindJSONOrYAML(c, req); err != nil {
+		klog.Errorf("failed to bind node failure request: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	result, err := h.service().Fail(c.Request().Context(), c.Param("name"), nodefailure.FailOptions{NoExecute: req.NoExecute, EvictPods: req.EvictPods})
+	if errors.Is(err, nodefailure.ErrNodeNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to fail node: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Recover makes the node Ready and schedulable again.
+func (h *NodeFailureHandler) Recover(c echo.Context) error {
+	result, err := h.service().Recover(c.Request().Context(), c.Param("name"))
+	if errors.Is(err, nodefailure.ErrNodeNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to recover node: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
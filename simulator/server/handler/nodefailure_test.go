@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/xerrors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/nodefailure"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+type fakeNodeFailureService struct {
+	gotName string
+	gotOpts nodefailure.FailOptions
+	err     error
+}
+
+func (s *fakeNodeFailureService) Fail(_ context.Context, name string, opts nodefailure.FailOptions) (*nodefailure.Result, error) {
+	s.gotName, s.gotOpts = name, opts
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &nodefailure.Result{Node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}, EvictedPods: []string{"default/pod-1"}}, nil
+}
+
+func (s *fakeNodeFailureService) Recover(_ context.Context, name string) (*nodefailure.Result, error) {
+	s.gotName = name
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &nodefailure.Result{Node: &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}}, nil
+}
+
+func TestNodeFailureHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		err      error
+		wantCode int
+		wantOpts nodefailure.FailOptions
+		wantBody string
+	}{
+		{
+			name:     "fail the node with the options",
+			path:     "/api/v1/nodes/node-1/fail",
+			body:     `{"noExecute":true,"evictPods":true}`,
+			wantCode: http.StatusOK,
+			wantOpts: nodefailure.FailOptions{NoExecute: true, EvictPods: true},
+			wantBody: `"evictedPods":["default/pod-1"]`,
+		},
+		{
+			name:     "fail the node without the body",
+			path:     "/api/v1/nodes/node-1/fail",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "invalid body",
+			path:     "/api/v1/nodes/node-1/fail",
+			body:     `{"noExecute":"yes"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "fail the unknown node",
+			path:     "/api/v1/nodes/node-1/fail",
+			err:      xerrors.Errorf("node node-1: %w", nodefailure.ErrNodeNotFound),
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "recover the node",
+			path:     "/api/v1/nodes/node-1/recover",
+			wantCode: http.StatusOK,
+			wantBody: `"name":"node-1"`,
+		},
+		{
+			name:     "recover the unknown node",
+			path:     "/api/v1/nodes/node-1/recover",
+			err:      xerrors.Errorf("node node-1: %w", nodefailure.ErrNodeNotFound),
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "the node can't be recovered",
+			path:     "/api/v1/nodes/node-1/recover",
+			err:      xerrors.New("update node"),
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			service := &fakeNodeFailureService{err: tt.err}
+			h := NewNodeFailureHandler(func() di.NodeFailureService { return service })
+			e := echo.New()
+			e.POST("/api/v1/nodes/:name/fail", h.Fail)
+			e.POST("/api/v1/nodes/:name/recover", h.Recover)
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantCode == http.StatusBadRequest {
+				return
+			}
+			assert.Equal(t, "node-1", service.gotName)
+			assert.Equal(t, tt.wantOpts, service.gotOpts)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/labstack/echo/v4"
 	"k8s.io/klog/v2"
 
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/experiment"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/reset"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
 )
 
@@ -19,8 +21,41 @@ func NewResetHandler(s di.ResetService) *ResetHandler {
 	return &ResetHandler{service: s}
 }
 
+// Reset restores the resources and scheduler configuration to the initial state.
+// With `mode=clean`, it deletes the resources instead, and returns the number of the deleted ones.
+// In the scope of an experiment, it always deletes the resources of the experiment as `mode=clean` does,
+// since the initial state can't be restored only for the experiment.
 func (h *ResetHandler) Reset(c echo.Context) error {
 	ctx := c.Request().Context()
+	e := experiment.FromContext(ctx)
+	mode := c.QueryParam("mode")
+	if e != nil && mode == "" {
+		mode = "clean"
+	}
+	switch mode {
+	case "", "restore":
+		if e != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "mode=restore can't be scoped to an experiment")
+		}
+	case "clean":
+		preserveKept, err := boolParam(c, "preserveKept")
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		opts := reset.CleanOptions{PreserveKept: preserveKept}
+		if e != nil {
+			opts.Experiment = e.ID
+		}
+		summary, err := h.service.Clean(ctx, opts)
+		if err != nil {
+			klog.Errorf("failed to clean all resources: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError)
+		}
+		return c.JSON(http.StatusOK, summary)
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "mode must be restore or clean")
+	}
+
 	if err := h.service.Reset(ctx); err != nil {
 		klog.Errorf("failed to reset all resources and schediler configuration: %+v", err)
 		return echo.NewHTTPError(http.StatusInternalServerError)
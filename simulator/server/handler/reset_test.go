@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/experiment"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/reset"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di/mock_di"
+)
+
+func TestResetHandler_Reset(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		query      string
+		experiment *experiment.Experiment
+		prepareFn  func(m *mock_di.MockResetService)
+		wantCode   int
+	}{
+		{
+			name: "restore the initial state by default",
+			prepareFn: func(m *mock_di.MockResetService) {
+				m.EXPECT().Reset(gomock.Any()).Return(nil)
+			},
+			wantCode: http.StatusAccepted,
+		},
+		{
+			name:  "clean the resources",
+			query: "?mode=clean&preserveKept=true",
+			prepareFn: func(m *mock_di.MockResetService) {
+				m.EXPECT().Clean(gomock.Any(), reset.CleanOptions{PreserveKept: true}).Return(&reset.CleanSummary{}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:       "clean the resources of the experiment by default in its scope",
+			experiment: &experiment.Experiment{ID: "exp-1"},
+			prepareFn: func(m *mock_di.MockResetService) {
+				m.EXPECT().Clean(gomock.Any(), reset.CleanOptions{Experiment: "exp-1"}).Return(&reset.CleanSummary{}, nil)
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:       "restore can't be scoped to an experiment",
+			query:      "?mode=restore",
+			experiment: &experiment.Experiment{ID: "exp-1"},
+			prepareFn:  func(*mock_di.MockResetService) {},
+			wantCode:   http.StatusBadRequest,
+		},
+		{
+			name:      "invalid preserveKept",
+			query:     "?mode=clean&preserveKept=maybe",
+			prepareFn: func(*mock_di.MockResetService) {},
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:      "unknown mode",
+			query:     "?mode=purge",
+			prepareFn: func(*mock_di.MockResetService) {},
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name: "failure of the service",
+			prepareFn: func(m *mock_di.MockResetService) {
+				m.EXPECT().Reset(gomock.Any()).Return(xerrors.New("etcd is down"))
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			m := mock_di.NewMockResetService(ctrl)
+			tt.prepareFn(m)
+			e := echo.New()
+			e.PUT("/api/v1/reset", NewResetHandler(m).Reset)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/reset"+tt.query, nil)
+			if tt.experiment != nil {
+				req = req.WithContext(experiment.NewContext(context.Background(), tt.experiment))
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcelist"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// ResourceListHandler is handler for listing the resources page by page.
+type ResourceListHandler struct {
+	service func() di.ResourceListService
+}
+
+// NewResourceListHandler initializes ResourceListHandler.
+func NewResourceListHandler(s func() di.ResourceListService) *ResourceListHandler {
+	return &ResourceListHandler{service: s}
+}
+
+// List returns a page of the resources, pruned to the fields in the fields query parameter.
+func (h *ResourceListHandler) List(c echo.Context) error {
+	opts := resourcelist.ListOptions{
+		Group:         c.QueryParam("group"),
+		Version:       c.QueryParam("version"),
+		Resource:      c.Param("resource"),
+		Namespace:     c.QueryParam("namespace"),
+		LabelSelector: c.QueryParam("labelSelector"),
+		Continue:      c.QueryParam("continue"),
+	}
+	if limit := c.QueryParam("limit"); limit != "" {
+		l, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "limit must be an integer")
+		}
+		opts.Limit = l
+	}
+	if fields := c.QueryParam("fields"); fields != "" {
+		opts.Fields = strings.Split(fields, ",")
+	}
+
+	result, err := h.service().List(c.Request().Context(), opts)
+	if errors.Is(err, resourcelist.ErrInvalidListOptions) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to list %s: %+v", opts.Resource, err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcelist"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+type fakeResourceListService struct {
+	got resourcelist.ListOptions
+	err error
+}
+
+func (s *fakeResourceListService) List(_ context.Context, opts resourcelist.ListOptions) (*resourcelist.ListResult, error) {
+	s.got = opts
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &resourcelist.ListResult{Items: []map[string]interface{}{}}, nil
+}
+
+func TestResourceListHandler_List(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		resource   string
+		query      string
+		serviceErr error
+		wantCode   int
+		wantOpts   resourcelist.ListOptions
+	}{
+		{
+			name:     "list with pagination and fields",
+			resource: "pods",
+			query:    "?namespace=default&labelSelector=app%3Dweb&limit=100&continue=token&fields=metadata.name,spec.nodeName",
+			wantCode: http.StatusOK,
+			wantOpts: resourcelist.ListOptions{
+				Resource:      "pods",
+				Namespace:     "default",
+				LabelSelector: "app=web",
+				Limit:         100,
+				Continue:      "token",
+				Fields:        []string{"metadata.name", "spec.nodeName"},
+			},
+		},
+		{
+			name:     "resource in the group",
+			resource: "deployments",
+			query:    "?group=apps&version=v1",
+			wantCode: http.StatusOK,
+			wantOpts: resourcelist.ListOptions{Group: "apps", Version: "v1", Resource: "deployments"},
+		},
+		{
+			name:     "malformed limit",
+			resource: "pods",
+			query:    "?limit=many",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid options",
+			resource:   "pods",
+			query:      "?continue=broken",
+			serviceErr: xerrors.Errorf("malformed continue token: %w", resourcelist.ErrInvalidListOptions),
+			wantCode:   http.StatusBadRequest,
+			wantOpts:   resourcelist.ListOptions{Resource: "pods", Continue: "broken"},
+		},
+		{
+			name:       "failure",
+			resource:   "pods",
+			serviceErr: xerrors.New("apiserver is down"),
+			wantCode:   http.StatusInternalServerError,
+			wantOpts:   resourcelist.ListOptions{Resource: "pods"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			service := &fakeResourceListService{err: tt.serviceErr}
+			h := NewResourceListHandler(func() di.ResourceListService { return service })
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/resources/"+tt.resource+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+			c.SetParamNames("resource")
+			c.SetParamValues(tt.resource)
+
+			err := h.List(c)
+			if tt.wantCode != http.StatusOK {
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, tt.wantCode, httpErr.Code)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rec.Code)
+			}
+			assert.Equal(t, tt.wantOpts, service.got)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scenario"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// ScenarioHandler is handler for running the declarative multi-step experiments.
+type ScenarioHandler struct {
+	service func() di.ScenarioService
+}
+
+// NewScenarioHandler initializes ScenarioHandler.
+func NewScenarioHandler(s func() di.ScenarioService) *ScenarioHandler {
+	return &ScenarioHandler{service: s}
+}
+
+// Submit starts running the scenario in the request body in background, and returns its status.
+func (h *ScenarioHandler) Submit(c echo.Context) error {
+	sc := new(scenario.Scenario)
+	if err := bindJSONOrYAML(c, sc); err != nil {
+		klog.Errorf("failed to bind scenario request: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	st, err := h.service().Submit(sc)
+	if errors.Is(err, scenario.ErrInvalidScenario) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if errors.Is(err, job.ErrConflict) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to submit scenario: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusAccepted, st)
+}
+
+// Get returns the status of the scenario including the status of each step.
+func (h *ScenarioHandler) Get(c echo.Context) error {
+	st, err := h.service().Get(c.Param("id"))
+	if errors.Is(err, scenario.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+	if err != nil {
+		klog.Errorf("failed to get scenario: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, st)
+}
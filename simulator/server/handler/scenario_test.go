@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scenario"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// bindingApplier creates the Pods bound to node1, as if the scheduler scheduled them immediately.
+type bindingApplier struct {
+	client clientset.Interface
+}
+
+func (a *bindingApplier) ApplyAll(ctx context.Context, resources []unstructured.Unstructured, _ resourceapplier.ApplyAllOptions) ([]resourceapplier.Result, error) {
+	for i := range resources {
+		pod := &corev1.Pod{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resources[i].Object, pod); err != nil {
+			return nil, err
+		}
+		pod.Spec.NodeName = "node1"
+		if _, err := a.client.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (a *bindingApplier) Delete(ctx context.Context, resource *unstructured.Unstructured) error {
+	return a.client.CoreV1().Pods(resource.GetNamespace()).Delete(ctx, resource.GetName(), metav1.DeleteOptions{})
+}
+
+func doScenarioRequest(t *testing.T, e *echo.Echo, method, path, contentType, body string) (int, *scenario.Status) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, contentType)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code >= http.StatusBadRequest {
+		return rec.Code, nil
+	}
+	st := &scenario.Status{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), st))
+	return rec.Code, st
+}
+
+func TestScenarioHandler(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset()
+	executor := scenario.NewExecutor(client, &bindingApplier{client: client}, &fakeSchedulerService{}, scenario.Options{PollInterval: time.Millisecond})
+	service := scenario.NewService(executor, job.NewManager(job.Options{ExclusiveTypes: job.DefaultExclusiveTypes}))
+	h := NewScenarioHandler(func() di.ScenarioService { return service })
+	e := echo.New()
+	e.POST("/api/v1/scenarios", h.Submit)
+	e.GET("/api/v1/scenarios/:id", h.Get)
+
+	body := `
+name: two-pods
+steps:
+- name: create
+  createResources:
+    manifests:
+    - apiVersion: v1
+      kind: Pod
+      metadata:
+        namespace: default
+        name: pod1
+        labels:
+          app: web
+    - apiVersion: v1
+      kind: Pod
+      metadata:
+        namespace: default
+        name: pod2
+        labels:
+          app: web
+- name: wait
+  waitForScheduled:
+    namespace: default
+    selector: app=web
+    count: 2
+    timeout: 10s
+`
+	code, submitted := doScenarioRequest(t, e, http.MethodPost, "/api/v1/scenarios", "application/yaml", body)
+	require.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, "two-pods", submitted.Name)
+	require.Len(t, submitted.Steps, 2)
+	assert.Equal(t, scenario.StepCreateResources, submitted.Steps[0].Type)
+	assert.Equal(t, scenario.StepWaitForScheduled, submitted.Steps[1].Type)
+
+	var got *scenario.Status
+	require.Eventually(t, func() bool {
+		code, st := doScenarioRequest(t, e, http.MethodGet, "/api/v1/scenarios/"+submitted.ID, "", "")
+		require.Equal(t, http.StatusOK, code)
+		got = st
+		return st.FinishedAt != nil
+	}, wait.ForeverTestTimeout, time.Millisecond)
+	assert.Equal(t, job.StateSucceeded, got.State)
+	assert.Nil(t, got.FailedStep)
+	assert.Equal(t, "created 2 resources", got.Steps[0].Message)
+	assert.Equal(t, "2 Pods are scheduled", got.Steps[1].Message)
+}
+
+func TestScenarioHandler_errors(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset()
+	executor := scenario.NewExecutor(client, &bindingApplier{client: client}, &fakeSchedulerService{}, scenario.Options{})
+	service := scenario.NewService(executor, job.NewManager(job.Options{ExclusiveTypes: job.DefaultExclusiveTypes}))
+	h := NewScenarioHandler(func() di.ScenarioService { return service })
+	e := echo.New()
+	e.POST("/api/v1/scenarios", h.Submit)
+	e.GET("/api/v1/scenarios/:id", h.Get)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "no steps",
+			method:   http.MethodPost,
+			path:     "/api/v1/scenarios",
+			body:     `{"name":"empty","steps":[]}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unknown step",
+			method:   http.MethodPost,
+			path:     "/api/v1/scenarios",
+			body:     `{"steps":[{"name":"nothing"}]}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "malformed body",
+			method:   http.MethodPost,
+			path:     "/api/v1/scenarios",
+			body:     `{`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unknown scenario",
+			method:   http.MethodGet,
+			path:     "/api/v1/scenarios/unknown",
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			code, _ := doScenarioRequest(t, e, tt.method, tt.path, echo.MIMEApplicationJSON, tt.body)
+			assert.Equal(t, tt.wantCode, code)
+		})
+	}
+}
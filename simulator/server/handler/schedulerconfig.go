@@ -5,21 +5,25 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
 	configv1 "k8s.io/kube-scheduler/config/v1"
 
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
 )
 
 // SchedulerConfigHandler is handler for manage scheduler config.
 type SchedulerConfigHandler struct {
-	service di.SchedulerService
+	service           di.SchedulerService
+	rescheduleService func() di.RescheduleService
 }
 
-func NewSchedulerConfigHandler(s di.SchedulerService) *SchedulerConfigHandler {
+func NewSchedulerConfigHandler(s di.SchedulerService, rs func() di.RescheduleService) *SchedulerConfigHandler {
 	return &SchedulerConfigHandler{
-		service: s,
+		service:           s,
+		rescheduleService: rs,
 	}
 }
 
@@ -36,9 +40,66 @@ func (h *SchedulerConfigHandler) GetSchedulerConfig(c echo.Context) error {
 	return c.JSON(http.StatusOK, cfg)
 }
 
+// SchedulerConfigValidationResult is the result of validating a scheduler configuration.
+type SchedulerConfigValidationResult struct {
+	Valid  bool                             `json:"valid"`
+	Errors []SchedulerConfigValidationError `json:"errors,omitempty"`
+}
+
+// SchedulerConfigValidationError is an invalid field of a scheduler configuration.
+type SchedulerConfigValidationError struct {
+	// Field is the path of the field, e.g., "profiles[0].plugins.filter.enabled[1].name".
+	Field string `json:"field"`
+	// Type is the type of the error, e.g., "Not found".
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+func newSchedulerConfigValidationResult(errs field.ErrorList) *SchedulerConfigValidationResult {
+	res := &SchedulerConfigValidationResult{Valid: len(errs) == 0}
+	for _, err := range errs {
+		res.Errors = append(res.Errors, SchedulerConfigValidationError{
+			Field:   err.Field,
+			Type:    err.Type.String(),
+			Message: err.ErrorBody(),
+		})
+	}
+	return res
+}
+
+// ValidateSchedulerConfig validates the posted scheduler configuration without applying it.
+// The configuration can be posted in YAML with Content-Type: application/yaml.
+func (h *SchedulerConfigHandler) ValidateSchedulerConfig(c echo.Context) error {
+	reqSchedulerCfg := new(configv1.KubeSchedulerConfiguration)
+	if err := bindJSONOrYAML(c, reqSchedulerCfg); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	errs, err := h.service.ValidateSchedulerConfig(reqSchedulerCfg)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	res := newSchedulerConfigValidationResult(errs)
+	if !res.Valid {
+		return c.JSON(http.StatusBadRequest, res)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
 // ApplySchedulerConfig currently only takes profiles and extenders from the
 // posted payload and applies them.
+// With `validate=true`, the configuration is validated first, and the scheduler isn't restarted if it's invalid.
+// With `reschedule=true`, the scheduled Pods are rescheduled with the new configuration in background
+// after the scheduler is restarted, and the job doing it is returned along with the configuration.
 func (h *SchedulerConfigHandler) ApplySchedulerConfig(c echo.Context) error {
+	validate, err := boolParam(c, "validate")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	reschedule, err := boolParam(c, "reschedule")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 	reqSchedulerCfg := new(configv1.KubeSchedulerConfiguration)
 	if err := c.Bind(reqSchedulerCfg); err != nil {
 		klog.Errorf("failed to bind scheduler config request: %+v", err)
@@ -53,10 +114,74 @@ func (h *SchedulerConfigHandler) ApplySchedulerConfig(c echo.Context) error {
 	cfg = cfg.DeepCopy()
 	cfg.Profiles = reqSchedulerCfg.Profiles
 	cfg.Extenders = reqSchedulerCfg.Extenders
+	if validate {
+		errs, err := h.service.ValidateSchedulerConfig(cfg)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if len(errs) > 0 {
+			return c.JSON(http.StatusBadRequest, newSchedulerConfigValidationResult(errs))
+		}
+	}
 	if err := h.service.RestartScheduler(cfg); err != nil {
+		if errors.Is(err, scheduler.ErrInvalidSchedulerConfig) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		klog.Errorf("failed to restart scheduler: %+v", err)
+		if !errors.Is(err, scheduler.ErrSchedulerConfigRolledBack) {
+			return echo.NewHTTPError(http.StatusInternalServerError)
+		}
+		// tell which config is running after the rollback.
+		active, aerr := h.service.ActiveSchedulerConfig()
+		if aerr != nil {
+			klog.Errorf("failed to get active scheduler config: %+v", aerr)
+			return echo.NewHTTPError(http.StatusInternalServerError)
+		}
+		return c.JSON(http.StatusInternalServerError, &SchedulerConfigApplyFailure{Message: err.Error(), Active: active})
+	}
+
+	active, err := h.service.ActiveSchedulerConfig()
+	if err != nil {
+		klog.Errorf("failed to get active scheduler config: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+	res := &SchedulerConfigApplyResult{ActiveSchedulerConfig: active}
+	if reschedule {
+		res.RescheduleJob, err = h.rescheduleService().Submit()
+		if errors.Is(err, job.ErrConflict) {
+			return echo.NewHTTPError(http.StatusConflict, "the configuration is applied, but the pods cannot be rescheduled: "+err.Error())
+		}
+		if err != nil {
+			klog.Errorf("failed to submit rescheduling: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError)
+		}
+	}
+	return c.JSON(http.StatusAccepted, res)
+}
+
+// SchedulerConfigApplyResult is returned when the new configuration is applied.
+type SchedulerConfigApplyResult struct {
+	*scheduler.ActiveSchedulerConfig
+	// RescheduleJob is the job rescheduling the Pods, which is set only with `reschedule=true`.
+	// Its result is reschedule.Result, i.e., the nodes of the Pods before and after the rescheduling.
+	RescheduleJob *job.Job `json:"rescheduleJob,omitempty"`
+}
+
+// SchedulerConfigApplyFailure is returned when the scheduler fails to start with the new configuration,
+// and it's rolled back to the previous one.
+type SchedulerConfigApplyFailure struct {
+	Message string `json:"message"`
+	// Active is the configuration the scheduler is running with after the rollback.
+	Active *scheduler.ActiveSchedulerConfig `json:"active"`
+}
+
+// GetActiveSchedulerConfig returns the configuration the scheduler is running with and its generation.
+func (h *SchedulerConfigHandler) GetActiveSchedulerConfig(c echo.Context) error {
+	active, err := h.service.ActiveSchedulerConfig()
+	if err != nil {
+		klog.Errorf("failed to get active scheduler config: %+v", err)
 		return echo.NewHTTPError(http.StatusInternalServerError)
 	}
 
-	return c.NoContent(http.StatusAccepted)
+	return c.JSON(http.StatusOK, active)
 }
@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/job"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
+	schedulerconfig "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+type fakeSchedulerService struct {
+	cfg        *configv1.KubeSchedulerConfiguration
+	generation int64
+	restarted  []*configv1.KubeSchedulerConfiguration
+	// restartErr is returned by RestartScheduler, and cfg isn't changed then.
+	restartErr error
+	queue      *scheduler.SchedulingQueue
+	queueErr   error
+	// secondaries is the configs of the secondary schedulers keyed by their names.
+	secondaries map[string]*configv1.KubeSchedulerConfiguration
+	// secondaryErr is returned by StartSecondaryScheduler if non-nil.
+	secondaryErr error
+}
+
+func (s *fakeSchedulerService) GetSchedulerConfig() (*configv1.KubeSchedulerConfiguration, error) {
+	return s.cfg, nil
+}
+
+func (s *fakeSchedulerService) SetSchedulerConfig(cfg *configv1.KubeSchedulerConfiguration) {
+	s.cfg = cfg
+}
+
+func (s *fakeSchedulerService) RestartScheduler(cfg *configv1.KubeSchedulerConfiguration) error {
+	s.restarted = append(s.restarted, cfg)
+	if s.restartErr != nil {
+		return s.restartErr
+	}
+	s.cfg = cfg
+	s.generation++
+	return nil
+}
+
+func (s *fakeSchedulerService) ActiveSchedulerConfig() (*scheduler.ActiveSchedulerConfig, error) {
+	return &scheduler.ActiveSchedulerConfig{Config: s.cfg, Generation: s.generation}, nil
+}
+
+func (s *fakeSchedulerService) ValidateSchedulerConfig(cfg *configv1.KubeSchedulerConfiguration) (field.ErrorList, error) {
+	return schedulerconfig.ValidateSchedulerConfig(cfg)
+}
+
+func (s *fakeSchedulerService) ResetScheduler() error {
+	return nil
+}
+
+func (s *fakeSchedulerService) ShutdownScheduler() {}
+
+func (s *fakeSchedulerService) ExtenderService() scheduler.ExtenderService {
+	return nil
+}
+
+func (s *fakeSchedulerService) Healthz() error {
+	return nil
+}
+
+func (s *fakeSchedulerService) SchedulingQueue(_ context.Context) (*scheduler.SchedulingQueue, error) {
+	return s.queue, s.queueErr
+}
+
+func (s *fakeSchedulerService) StartSecondaryScheduler(cfg *configv1.KubeSchedulerConfiguration, name string) error {
+	if s.secondaryErr != nil {
+		return s.secondaryErr
+	}
+	if _, ok := s.secondaries[name]; ok {
+		return scheduler.ErrSecondarySchedulerExists
+	}
+	if s.secondaries == nil {
+		s.secondaries = map[string]*configv1.KubeSchedulerConfiguration{}
+	}
+	s.secondaries[name] = cfg
+	return nil
+}
+
+func (s *fakeSchedulerService) StopSecondaryScheduler(name string) error {
+	if _, ok := s.secondaries[name]; !ok {
+		return scheduler.ErrSecondarySchedulerNotFound
+	}
+	delete(s.secondaries, name)
+	return nil
+}
+
+func (s *fakeSchedulerService) SecondarySchedulers() []scheduler.SecondaryScheduler {
+	ret := []scheduler.SecondaryScheduler{}
+	for name, cfg := range s.secondaries {
+		ret = append(ret, scheduler.SecondaryScheduler{Name: name, Config: cfg})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+func TestSchedulerConfigHandler_ValidateSchedulerConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantCode    int
+		wantErrors  []SchedulerConfigValidationError
+	}{
+		{
+			name:        "valid configuration in YAML",
+			contentType: mimeApplicationYAML,
+			body: `apiVersion: kubescheduler.config.k8s.io/v1
+kind: KubeSchedulerConfiguration
+profiles:
+- schedulerName: default-scheduler
+  plugins:
+    score:
+      disabled:
+      - name: ImageLocality
+`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:        "schema-invalid configuration",
+			contentType: echo.MIMEApplicationJSON,
+			body:        `{"parallelism":0}`,
+			wantCode:    http.StatusBadRequest,
+			wantErrors: []SchedulerConfigValidationError{
+				{Field: "parallelism", Type: "Invalid value", Message: "Invalid value: 0: should be an integer value greater than zero"},
+			},
+		},
+		{
+			name:        "configuration with an unknown plugin",
+			contentType: echo.MIMEApplicationJSON,
+			body:        `{"profiles":[{"schedulerName":"default-scheduler","plugins":{"filter":{"enabled":[{"name":"NoSuchPlugin"}]}}}]}`,
+			wantCode:    http.StatusBadRequest,
+			wantErrors: []SchedulerConfigValidationError{
+				{Field: "profiles[0].plugins.filter.enabled[0].name", Type: "Not found", Message: `Not found: "NoSuchPlugin"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			service := &fakeSchedulerService{}
+			h := NewSchedulerConfigHandler(service, nil)
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			rec := httptest.NewRecorder()
+			require.NoError(t, h.ValidateSchedulerConfig(echo.New().NewContext(req, rec)))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			var got SchedulerConfigValidationResult
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, len(tt.wantErrors) == 0, got.Valid)
+			assert.Equal(t, tt.wantErrors, got.Errors)
+			assert.Empty(t, service.restarted)
+		})
+	}
+}
+
+func TestSchedulerConfigHandler_ApplySchedulerConfig_validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		body          string
+		wantCode      int
+		wantRestarted bool
+	}{
+		{
+			name:          "apply the valid configuration",
+			body:          `{"profiles":[{"schedulerName":"default-scheduler"}]}`,
+			wantCode:      http.StatusAccepted,
+			wantRestarted: true,
+		},
+		{
+			name:     "refuse the invalid configuration without restarting the scheduler",
+			body:     `{"profiles":[{"schedulerName":"default-scheduler","plugins":{"filter":{"enabled":[{"name":"NoSuchPlugin"}]}}}]}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg, err := schedulerconfig.DefaultSchedulerConfig()
+			require.NoError(t, err)
+			service := &fakeSchedulerService{cfg: cfg}
+			h := NewSchedulerConfigHandler(service, nil)
+			req := httptest.NewRequest(http.MethodPost, "/?validate=true", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			require.NoError(t, h.ApplySchedulerConfig(echo.New().NewContext(req, rec)))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantRestarted, len(service.restarted) == 1)
+		})
+	}
+}
+
+func TestSchedulerConfigHandler_ApplySchedulerConfig_active(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		restartErr     error
+		wantCode       int
+		wantGeneration int64
+		wantNewConfig  bool
+	}{
+		{
+			name:           "return the new configuration applied",
+			wantCode:       http.StatusAccepted,
+			wantGeneration: 2,
+			wantNewConfig:  true,
+		},
+		{
+			name:           "return the previous configuration rolled back to",
+			restartErr:     xerrors.Errorf("container isn't running: %w", scheduler.ErrSchedulerConfigRolledBack),
+			wantCode:       http.StatusInternalServerError,
+			wantGeneration: 1,
+		},
+		{
+			name:       "refuse the configuration the service rejects",
+			restartErr: xerrors.Errorf("parallelism: %w", scheduler.ErrInvalidSchedulerConfig),
+			wantCode:   http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg, err := schedulerconfig.DefaultSchedulerConfig()
+			require.NoError(t, err)
+			service := &fakeSchedulerService{cfg: cfg, generation: 1, restartErr: tt.restartErr}
+			h := NewSchedulerConfigHandler(service, nil)
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"profiles":[{"schedulerName":"new-scheduler"}]}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			err = h.ApplySchedulerConfig(echo.New().NewContext(req, rec))
+			if tt.wantCode == http.StatusBadRequest {
+				var herr *echo.HTTPError
+				require.ErrorAs(t, err, &herr)
+				assert.Equal(t, tt.wantCode, herr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCode, rec.Code)
+
+			var active *scheduler.ActiveSchedulerConfig
+			if tt.restartErr != nil {
+				var got SchedulerConfigApplyFailure
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				assert.NotEmpty(t, got.Message)
+				active = got.Active
+			} else {
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &active))
+			}
+			require.NotNil(t, active)
+			assert.Equal(t, tt.wantGeneration, active.Generation)
+			assert.Equal(t, tt.wantNewConfig, *active.Config.Profiles[0].SchedulerName == "new-scheduler")
+		})
+	}
+}
+
+type fakeRescheduleService struct {
+	submitted int
+	err       error
+}
+
+func (s *fakeRescheduleService) Submit() (*job.Job, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.submitted++
+	return &job.Job{ID: "job1", Type: job.TypeReschedule, State: job.StatePending}, nil
+}
+
+func TestSchedulerConfigHandler_ApplySchedulerConfig_reschedule(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		query         string
+		submitErr     error
+		wantCode      int
+		wantSubmitted bool
+	}{
+		{
+			name:          "reschedule the pods after applying the configuration",
+			query:         "?reschedule=true",
+			wantCode:      http.StatusAccepted,
+			wantSubmitted: true,
+		},
+		{
+			name:     "don't reschedule the pods without the parameter",
+			wantCode: http.StatusAccepted,
+		},
+		{
+			name:      "refuse while another rescheduling is in progress",
+			query:     "?reschedule=true",
+			submitErr: xerrors.Errorf("submit the job of rescheduling: %w", job.ErrConflict),
+			wantCode:  http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg, err := schedulerconfig.DefaultSchedulerConfig()
+			require.NoError(t, err)
+			service := &fakeSchedulerService{cfg: cfg, generation: 1}
+			rs := &fakeRescheduleService{err: tt.submitErr}
+			h := NewSchedulerConfigHandler(service, func() di.RescheduleService { return rs })
+			req := httptest.NewRequest(http.MethodPost, "/"+tt.query, strings.NewReader(`{"profiles":[{"schedulerName":"new-scheduler"}]}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			err = h.ApplySchedulerConfig(echo.New().NewContext(req, rec))
+			// The configuration is applied regardless of the rescheduling.
+			assert.Len(t, service.restarted, 1)
+			if tt.wantCode != http.StatusAccepted {
+				var herr *echo.HTTPError
+				require.ErrorAs(t, err, &herr)
+				assert.Equal(t, tt.wantCode, herr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCode, rec.Code)
+
+			var got SchedulerConfigApplyResult
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			require.NotNil(t, got.ActiveSchedulerConfig)
+			assert.Equal(t, int64(2), got.Generation)
+			if tt.wantSubmitted {
+				require.NotNil(t, got.RescheduleJob)
+				assert.Equal(t, job.TypeReschedule, got.RescheduleJob.Type)
+				assert.Equal(t, 1, rs.submitted)
+			} else {
+				assert.Nil(t, got.RescheduleJob)
+				assert.Zero(t, rs.submitted)
+			}
+		})
+	}
+}
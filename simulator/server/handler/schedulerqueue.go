@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// SchedulerQueueHandler is handler for the scheduling queue of the scheduler.
+type SchedulerQueueHandler struct {
+	service di.SchedulerService
+}
+
+// NewSchedulerQueueHandler initializes SchedulerQueueHandler.
+func NewSchedulerQueueHandler(s di.SchedulerService) *SchedulerQueueHandler {
+	return &SchedulerQueueHandler{service: s}
+}
+
+// GetQueue lists the Pods waiting to be scheduled per queue.
+func (h *SchedulerQueueHandler) GetQueue(c echo.Context) error {
+	q, err := h.service.SchedulingQueue(c.Request().Context())
+	if err != nil {
+		klog.Errorf("failed to get the scheduling queue: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, q)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
+)
+
+func TestSchedulerQueueHandler_GetQueue(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		service  *fakeSchedulerService
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "the pods are listed per queue",
+			service: &fakeSchedulerService{queue: &scheduler.SchedulingQueue{
+				ActiveQ:  []scheduler.QueuedPod{{Namespace: "default", Name: "pod-1", SchedulerName: "default-scheduler"}},
+				BackoffQ: []scheduler.QueuedPod{},
+				UnschedulablePods: []scheduler.QueuedPod{
+					{Namespace: "default", Name: "pod-2", SchedulerName: "default-scheduler", Attempts: 3, LastFailureReason: "Unschedulable", LastFailureMessage: "0/1 nodes are available: 1 Insufficient cpu."},
+				},
+			}},
+			wantCode: http.StatusOK,
+			wantBody: `{
+				"activeQ": [{"namespace": "default", "name": "pod-1", "schedulerName": "default-scheduler", "priority": 0, "creationTimestamp": null, "attempts": 0}],
+				"backoffQ": [],
+				"unschedulablePods": [{"namespace": "default", "name": "pod-2", "schedulerName": "default-scheduler", "priority": 0, "creationTimestamp": null, "attempts": 3,
+					"lastFailureReason": "Unschedulable", "lastFailureMessage": "0/1 nodes are available: 1 Insufficient cpu."}]
+			}`,
+		},
+		{
+			name:     "the queue can't be got",
+			service:  &fakeSchedulerService{queueErr: xerrors.New("list pods")},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := echo.New()
+			e.GET("/api/v1/scheduler/queue", NewSchedulerQueueHandler(tt.service).GetQueue)
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/scheduler/queue", nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantBody == "" {
+				return
+			}
+			assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			var got scheduler.SchedulingQueue
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tt.service.queue, &got)
+		})
+	}
+}
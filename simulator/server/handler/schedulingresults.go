@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/resulthistory"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// SchedulingResultsHandler is handler for querying the scheduling results.
+type SchedulingResultsHandler struct {
+	service func() di.SchedulingResultsService
+}
+
+// NewSchedulingResultsHandler initializes SchedulingResultsHandler.
+func NewSchedulingResultsHandler(s func() di.SchedulingResultsService) *SchedulingResultsHandler {
+	return &SchedulingResultsHandler{service: s}
+}
+
+// List returns the scheduling results matching the query parameters, in the order they are recorded.
+func (h *SchedulingResultsHandler) List(c echo.Context) error {
+	q := resulthistory.Query{
+		Namespace:     c.QueryParam("namespace"),
+		Pod:           c.QueryParam("pod"),
+		Node:          c.QueryParam("node"),
+		SchedulerName: c.QueryParam("schedulerName"),
+		Continue:      c.QueryParam("continue"),
+	}
+	if since := c.QueryParam("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "since must be in RFC 3339 format")
+		}
+		q.Since = t
+	}
+	if limit := c.QueryParam("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "limit must be an integer")
+		}
+		q.Limit = l
+	}
+
+	result, err := h.service().Query(q)
+	if errors.Is(err, resulthistory.ErrInvalidQuery) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to query scheduling results: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Latency returns the percentiles of the scheduling latencies and the durations of the scheduling attempts,
+// filtered by the namespace and the schedulerName query parameters.
+func (h *SchedulingResultsHandler) Latency(c echo.Context) error {
+	q := resulthistory.LatencyQuery{
+		Namespace:     c.QueryParam("namespace"),
+		SchedulerName: c.QueryParam("schedulerName"),
+	}
+	return c.JSON(http.StatusOK, h.service().Latency(q))
+}
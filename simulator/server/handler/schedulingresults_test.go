@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/annotation"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/resulthistory"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/storereflector"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// fakeResultStore has the results to reflect on the Pods by the Pod's name.
+type fakeResultStore struct {
+	mu      sync.Mutex
+	results map[string]map[string]string
+}
+
+func (s *fakeResultStore) GetStoredResult(pod *corev1.Pod) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.results[pod.Name]
+}
+
+func (s *fakeResultStore) DeleteData(pod corev1.Pod) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.results, pod.Name)
+}
+
+func TestSchedulingResultsHandler_List(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset()
+	history := resulthistory.New(0, 0)
+	resultStore := &fakeResultStore{results: map[string]map[string]string{
+		"pod1": {annotation.SelectedNodeAnnotationKey: "node1", annotation.FilterResultAnnotationKey: `{"node1":{"NodeName":"passed"}}`},
+		"pod2": {annotation.SelectedNodeAnnotationKey: "node2"},
+		"pod3": {annotation.SelectedNodeAnnotationKey: "node1"},
+	}}
+	reflector := storereflector.New(storereflector.WithResultRecorder(history))
+	reflector.AddResultStore(resultStore, "fake")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, reflector.ResisterResultSavingToInformer(client, ctx.Done()))
+
+	// The results are saved when the Pods are updated, e.g., bound to the nodes.
+	for _, name := range []string{"pod1", "pod2", "pod3"} {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		_, err := client.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{})
+		require.NoError(t, err)
+		pod.Labels = map[string]string{"scheduled": "true"}
+		_, err = client.CoreV1().Pods("default").Update(ctx, pod, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			got, err := history.Query(resulthistory.Query{Pod: name})
+			return err == nil && len(got.Items) == 1
+		}, wait.ForeverTestTimeout, 10*time.Millisecond)
+	}
+	// The results are kept after the Pod is deleted.
+	require.NoError(t, client.CoreV1().Pods("default").Delete(ctx, "pod1", metav1.DeleteOptions{}))
+
+	h := NewSchedulingResultsHandler(func() di.SchedulingResultsService { return history })
+	tests := []struct {
+		name         string
+		query        string
+		wantCode     int
+		wantPods     []string
+		wantContinue bool
+	}{
+		{
+			name:     "all",
+			wantCode: http.StatusOK,
+			wantPods: []string{"pod1", "pod2", "pod3"},
+		},
+		{
+			name:     "filter by the node",
+			query:    "?node=node1",
+			wantCode: http.StatusOK,
+			wantPods: []string{"pod1", "pod3"},
+		},
+		{
+			name:     "filter by the namespace and the pod",
+			query:    "?namespace=default&pod=pod2",
+			wantCode: http.StatusOK,
+			wantPods: []string{"pod2"},
+		},
+		{
+			name:     "no results since the future",
+			query:    "?since=" + time.Now().Add(time.Hour).Format(time.RFC3339),
+			wantCode: http.StatusOK,
+			wantPods: []string{},
+		},
+		{
+			name:         "first page",
+			query:        "?limit=2",
+			wantCode:     http.StatusOK,
+			wantPods:     []string{"pod1", "pod2"},
+			wantContinue: true,
+		},
+		{
+			name:     "malformed since",
+			query:    "?since=yesterday",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "too large limit",
+			query:    "?limit=100000",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/schedulingresults"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			err := h.List(echo.New().NewContext(req, rec))
+			if tt.wantCode != http.StatusOK {
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				assert.Equal(t, tt.wantCode, httpErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var got resulthistory.QueryResult
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			pods := []string{}
+			for _, e := range got.Items {
+				pods = append(pods, e.Pod)
+				assert.NotEmpty(t, e.Results)
+			}
+			assert.Equal(t, tt.wantPods, pods)
+			assert.Equal(t, tt.wantContinue, got.Continue != "")
+		})
+	}
+}
+
+func TestSchedulingResultsHandler_Latency(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := resulthistory.New(0, 0)
+	for i, schedulerName := range []string{"default-scheduler", "default-scheduler", "secondary"} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod%d", i), Namespace: "default", UID: types.UID(fmt.Sprintf("uid%d", i)), CreationTimestamp: metav1.NewTime(created)},
+			Spec:       corev1.PodSpec{SchedulerName: schedulerName},
+		}
+		history.Record(pod, map[string]string{annotation.PluginDurationAnnotationKey: `{"Filter":{"NodeName":"2ms"}}`})
+		history.RecordBinding(pod, created.Add(time.Duration(i+1)*time.Second))
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  resulthistory.LatencyStats
+	}{
+		{
+			name: "all",
+			want: resulthistory.LatencyStats{
+				SchedulingLatency: resulthistory.Percentiles{Count: 3, Mean: 2 * time.Second, P50: 2 * time.Second, P90: 3 * time.Second, P95: 3 * time.Second, P99: 3 * time.Second, Max: 3 * time.Second},
+				AttemptDuration:   resulthistory.Percentiles{Count: 3, Mean: 2 * time.Millisecond, P50: 2 * time.Millisecond, P90: 2 * time.Millisecond, P95: 2 * time.Millisecond, P99: 2 * time.Millisecond, Max: 2 * time.Millisecond},
+			},
+		},
+		{
+			name:  "filter by the scheduler name",
+			query: "?schedulerName=secondary",
+			want: resulthistory.LatencyStats{
+				SchedulingLatency: resulthistory.Percentiles{Count: 1, Mean: 3 * time.Second, P50: 3 * time.Second, P90: 3 * time.Second, P95: 3 * time.Second, P99: 3 * time.Second, Max: 3 * time.Second},
+				AttemptDuration:   resulthistory.Percentiles{Count: 1, Mean: 2 * time.Millisecond, P50: 2 * time.Millisecond, P90: 2 * time.Millisecond, P95: 2 * time.Millisecond, P99: 2 * time.Millisecond, Max: 2 * time.Millisecond},
+			},
+		},
+		{
+			name:  "filter by the namespace",
+			query: "?namespace=kube-system",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/stats/latency"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			require.NoError(t, NewSchedulingResultsHandler(func() di.SchedulingResultsService { return history }).Latency(echo.New().NewContext(req, rec)))
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var got resulthistory.LatencyStats
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// SecondarySchedulerHandler is handler for the secondary schedulers running side by side with the primary one.
+type SecondarySchedulerHandler struct {
+	service di.SchedulerService
+}
+
+// SecondarySchedulerRequest is the request to start a secondary scheduler.
+type SecondarySchedulerRequest struct {
+	// Name is the schedulerName of the Pods the secondary scheduler schedules.
+	Name string `json:"name"`
+	// Config is the config of the secondary scheduler. It can have at most one profile.
+	Config *configv1.KubeSchedulerConfiguration `json:"config"`
+}
+
+// NewSecondarySchedulerHandler initializes SecondarySchedulerHandler.
+func NewSecondarySchedulerHandler(s di.SchedulerService) *SecondarySchedulerHandler {
+	return &SecondarySchedulerHandler{service: s}
+}
+
+// List returns the running secondary schedulers.
+func (h *SecondarySchedulerHandler) List(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.service.SecondarySchedulers())
+}
+
+// Start starts a secondary scheduler with the config in the request.
+func (h *SecondarySchedulerHandler) Start(c echo.Context) error {
+	req := new(SecondarySchedulerRequest)
+	if err := bindJSONOrYAML(c, req); err != nil {
+		klog.Errorf("failed to bind secondary scheduler request: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	err := h.service.StartSecondaryScheduler(req.Config, req.Name)
+	switch {
+	case errors.Is(err, scheduler.ErrInvalidSchedulerConfig):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, scheduler.ErrSecondarySchedulerExists):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case err != nil:
+		klog.Errorf("failed to start secondary scheduler: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// Stop stops the secondary scheduler with the name.
+func (h *SecondarySchedulerHandler) Stop(c echo.Context) error {
+	err := h.service.StopSecondaryScheduler(c.Param("name"))
+	if errors.Is(err, scheduler.ErrSecondarySchedulerNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to stop secondary scheduler: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
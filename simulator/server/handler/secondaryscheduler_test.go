@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
+)
+
+func TestSecondarySchedulerHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		service  *fakeSchedulerService
+		method   string
+		path     string
+		body     string
+		wantCode int
+		// wantNames is the names of the secondary schedulers after the request.
+		wantNames []string
+	}{
+		{
+			name:      "list the secondary schedulers",
+			service:   &fakeSchedulerService{secondaries: map[string]*configv1.KubeSchedulerConfiguration{"scheduler-b": {}, "scheduler-a": {}}},
+			method:    http.MethodGet,
+			path:      "/api/v1/scheduler/secondaries",
+			wantCode:  http.StatusOK,
+			wantNames: []string{"scheduler-a", "scheduler-b"},
+		},
+		{
+			name:      "start a secondary scheduler in YAML",
+			service:   &fakeSchedulerService{},
+			method:    http.MethodPost,
+			path:      "/api/v1/scheduler/secondaries",
+			body:      "name: scheduler-b\nconfig:\n  profiles:\n  - plugins:\n      score:\n        disabled:\n        - name: ImageLocality\n",
+			wantCode:  http.StatusCreated,
+			wantNames: []string{"scheduler-b"},
+		},
+		{
+			name:      "start the secondary scheduler which already exists",
+			service:   &fakeSchedulerService{secondaries: map[string]*configv1.KubeSchedulerConfiguration{"scheduler-b": {}}},
+			method:    http.MethodPost,
+			path:      "/api/v1/scheduler/secondaries",
+			body:      "name: scheduler-b\nconfig: {}\n",
+			wantCode:  http.StatusConflict,
+			wantNames: []string{"scheduler-b"},
+		},
+		{
+			name:      "start a secondary scheduler with an invalid config",
+			service:   &fakeSchedulerService{secondaryErr: xerrors.Errorf("two profiles: %w", scheduler.ErrInvalidSchedulerConfig)},
+			method:    http.MethodPost,
+			path:      "/api/v1/scheduler/secondaries",
+			body:      "name: scheduler-b\nconfig: {}\n",
+			wantCode:  http.StatusBadRequest,
+			wantNames: []string{},
+		},
+		{
+			name:      "fail to start a secondary scheduler",
+			service:   &fakeSchedulerService{secondaryErr: xerrors.New("create container")},
+			method:    http.MethodPost,
+			path:      "/api/v1/scheduler/secondaries",
+			body:      "name: scheduler-b\nconfig: {}\n",
+			wantCode:  http.StatusInternalServerError,
+			wantNames: []string{},
+		},
+		{
+			name:      "stop a secondary scheduler",
+			service:   &fakeSchedulerService{secondaries: map[string]*configv1.KubeSchedulerConfiguration{"scheduler-b": {}}},
+			method:    http.MethodDelete,
+			path:      "/api/v1/scheduler/secondaries/scheduler-b",
+			wantCode:  http.StatusNoContent,
+			wantNames: []string{},
+		},
+		{
+			name:      "stop the secondary scheduler which doesn't exist",
+			service:   &fakeSchedulerService{},
+			method:    http.MethodDelete,
+			path:      "/api/v1/scheduler/secondaries/scheduler-b",
+			wantCode:  http.StatusNotFound,
+			wantNames: []string{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := echo.New()
+			h := NewSecondarySchedulerHandler(tt.service)
+			e.GET("/api/v1/scheduler/secondaries", h.List)
+			e.POST("/api/v1/scheduler/secondaries", h.Start)
+			e.DELETE("/api/v1/scheduler/secondaries/:name", h.Stop)
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, mimeApplicationYAML)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.method == http.MethodGet {
+				var got []scheduler.SecondaryScheduler
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				require.Len(t, got, len(tt.wantNames))
+				for i := range got {
+					assert.Equal(t, tt.wantNames[i], got[i].Name)
+				}
+			}
+			names := []string{}
+			for _, s := range tt.service.SecondarySchedulers() {
+				names = append(names, s.Name)
+			}
+			assert.Equal(t, tt.wantNames, names)
+			if tt.method == http.MethodPost && tt.wantCode == http.StatusCreated {
+				cfg := tt.service.secondaries["scheduler-b"]
+				require.Len(t, cfg.Profiles, 1)
+				assert.Equal(t, "ImageLocality", cfg.Profiles[0].Plugins.Score.Disabled[0].Name)
+			}
+		})
+	}
+}
@@ -1,21 +1,27 @@
 package handler
 
 import (
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
+	"golang.org/x/xerrors"
 	v1 "k8s.io/client-go/applyconfigurations/core/v1"
 	schedulingcfgv1 "k8s.io/client-go/applyconfigurations/scheduling/v1"
 	confstoragev1 "k8s.io/client-go/applyconfigurations/storage/v1"
 	"k8s.io/klog/v2"
 	configv1 "k8s.io/kube-scheduler/config/v1"
+	"sigs.k8s.io/yaml"
 
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/reset"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/snapshot"
 )
 
 type SnapshotHandler struct {
-	service di.SnapshotService
+	service      func() di.SnapshotService
+	resetService di.ResetService
 }
 
 type ResourcesForLoad struct {
@@ -29,38 +35,85 @@ type ResourcesForLoad struct {
 	Namespaces      []v1.NamespaceApplyConfiguration                  `json:"namespaces"`
 }
 
-func NewSnapshotHandler(s di.SnapshotService) *SnapshotHandler {
-	return &SnapshotHandler{service: s}
+func NewSnapshotHandler(s func() di.SnapshotService, r di.ResetService) *SnapshotHandler {
+	return &SnapshotHandler{service: s, resetService: r}
 }
 
+// mimeApplicationYAML is the content type of the snapshot in YAML.
+const mimeApplicationYAML = "application/yaml"
+
+// Snap returns all the resources without the fields set by the server, and the scheduler configuration.
+// It's returned in YAML with `format=yaml`, and in JSON otherwise.
 func (h *SnapshotHandler) Snap(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	rs, err := h.service.Snap(ctx)
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "yaml" {
+		return echo.NewHTTPError(http.StatusBadRequest, "format must be json or yaml")
+	}
+
+	rs, err := h.service().Snap(ctx, h.service().Sanitize())
 	if err != nil {
 		klog.Errorf("failed to save all resources: %+v", err)
 		return echo.NewHTTPError(http.StatusInternalServerError)
 	}
+	if format == "yaml" {
+		b, err := yaml.Marshal(rs)
+		if err != nil {
+			klog.Errorf("failed to marshal all resources to YAML: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError)
+		}
+		return c.Blob(http.StatusOK, mimeApplicationYAML, b)
+	}
 	return c.JSON(http.StatusOK, rs)
 }
 
+// Load applies the resources and the scheduler configuration exported by Snap.
+// The request body is read as YAML if Content-Type is application/yaml, and as JSON otherwise.
+// With `reset=true`, the existing resources are deleted before loading.
 func (h *SnapshotHandler) Load(c echo.Context) error {
 	ctx := c.Request().Context()
 
+	resetBeforeLoad, err := boolParam(c, "reset")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 	reqResources := new(ResourcesForLoad)
-	if err := c.Bind(reqResources); err != nil {
+	if err := bindJSONOrYAML(c, reqResources); err != nil {
 		klog.Errorf("failed to bind request: %+v", err)
 		return echo.NewHTTPError(http.StatusBadRequest)
 	}
 
-	err := h.service.Load(ctx, convertToResourcesApplyConfiguration(reqResources))
-	if err != nil {
+	if resetBeforeLoad {
+		if _, err := h.resetService.Clean(ctx, reset.CleanOptions{}); err != nil {
+			klog.Errorf("failed to clean all resources before loading: %+v", err)
+			return echo.NewHTTPError(http.StatusInternalServerError)
+		}
+	}
+
+	if err := h.service().Load(ctx, convertToResourcesApplyConfiguration(reqResources)); err != nil {
 		klog.Errorf("failed to load all resources: %+v", err)
 		return echo.NewHTTPError(http.StatusInternalServerError)
 	}
 	return c.NoContent(http.StatusOK)
 }
 
+// bindJSONOrYAML reads the request body into v as YAML if Content-Type is application/yaml, and as JSON otherwise.
+func bindJSONOrYAML(c echo.Context, v interface{}) error {
+	if !strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), mimeApplicationYAML) {
+		return c.Bind(v)
+	}
+
+	b, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return xerrors.Errorf("read request body: %w", err)
+	}
+	if err := yaml.Unmarshal(b, v); err != nil {
+		return xerrors.Errorf("unmarshal request body: %w", err)
+	}
+	return nil
+}
+
 // convertToResourcesApplyConfiguration converts from *ResourcesApplyConfiguration to *export.ResourcesApplyConfiguration.
 func convertToResourcesApplyConfiguration(r *ResourcesForLoad) *snapshot.ResourcesForLoad {
 	return &snapshot.ResourcesForLoad{
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/reset"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/snapshot"
+)
+
+type fakeSnapshotService struct {
+	snapped   *snapshot.ResourcesForSnap
+	loaded    *snapshot.ResourcesForLoad
+	sanitized bool
+}
+
+type sanitizeOption struct{ snapshot.Option }
+
+func (s *fakeSnapshotService) Snap(_ context.Context, opts ...snapshot.Option) (*snapshot.ResourcesForSnap, error) {
+	for _, o := range opts {
+		if _, ok := o.(sanitizeOption); ok {
+			s.sanitized = true
+		}
+	}
+	return s.snapped, nil
+}
+
+func (s *fakeSnapshotService) Load(_ context.Context, resources *snapshot.ResourcesForLoad, _ ...snapshot.Option) error {
+	s.loaded = resources
+	return nil
+}
+
+func (s *fakeSnapshotService) IgnoreErr() snapshot.Option {
+	return nil
+}
+
+func (s *fakeSnapshotService) Sanitize() snapshot.Option {
+	return sanitizeOption{}
+}
+
+type fakeResetService struct {
+	cleaned bool
+}
+
+func (s *fakeResetService) Reset(_ context.Context) error {
+	return nil
+}
+
+func (s *fakeResetService) Clean(_ context.Context, _ reset.CleanOptions) (*reset.CleanSummary, error) {
+	s.cleaned = true
+	return &reset.CleanSummary{}, nil
+}
+
+func TestSnapshotHandler_YAMLRoundTrip(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		query       string
+		wantCleaned bool
+	}{
+		{
+			name: "export and import in YAML",
+		},
+		{
+			name:        "export and import in YAML after resetting",
+			query:       "?reset=true",
+			wantCleaned: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			service := &fakeSnapshotService{
+				snapped: &snapshot.ResourcesForSnap{
+					Pods:  []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}, Spec: corev1.PodSpec{NodeName: "node1"}}},
+					Nodes: []corev1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}},
+				},
+			}
+			resetService := &fakeResetService{}
+			h := NewSnapshotHandler(func() di.SnapshotService { return service }, resetService)
+			e := echo.New()
+			e.GET("/export", h.Snap)
+			e.POST("/import", h.Load)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export?format=yaml", nil))
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, mimeApplicationYAML, rec.Header().Get(echo.HeaderContentType))
+			assert.Contains(t, rec.Body.String(), "name: pod1")
+			assert.True(t, service.sanitized)
+
+			req := httptest.NewRequest(http.MethodPost, "/import"+tt.query, bytes.NewReader(rec.Body.Bytes()))
+			req.Header.Set(echo.HeaderContentType, mimeApplicationYAML)
+			rec = httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.wantCleaned, resetService.cleaned)
+			require.Len(t, service.loaded.Pods, 1)
+			assert.Equal(t, "pod1", *service.loaded.Pods[0].Name)
+			assert.Equal(t, "node1", *service.loaded.Pods[0].Spec.NodeName)
+			require.Len(t, service.loaded.Nodes, 1)
+			assert.Equal(t, "node1", *service.loaded.Nodes[0].Name)
+		})
+	}
+}
+
+func TestSnapshotHandler_Snap_invalidFormat(t *testing.T) {
+	t.Parallel()
+	h := NewSnapshotHandler(func() di.SnapshotService { return &fakeSnapshotService{} }, &fakeResetService{})
+	e := echo.New()
+	e.GET("/export", h.Snap)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/export?format=xml", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
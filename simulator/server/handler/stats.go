@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/experiment"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/stats"
+)
+
+// StatsHandler is handler for the aggregated scheduling state of the simulator.
+type StatsHandler struct {
+	service func() di.StatsService
+}
+
+// NewStatsHandler initializes StatsHandler.
+func NewStatsHandler(s func() di.StatsService) *StatsHandler {
+	return &StatsHandler{service: s}
+}
+
+// Scheduling returns the scheduling stats per namespace and per node.
+// The stats can be filtered with the namespace and the nodeSelector query parameters.
+// In the scope of an experiment, only the Pods of the experiment are aggregated.
+func (h *StatsHandler) Scheduling(c echo.Context) error {
+	q := stats.Query{Namespace: c.QueryParam("namespace")}
+	if e := experiment.FromContext(c.Request().Context()); e != nil {
+		q.PodSelector = e.Selector()
+	}
+	if s := c.QueryParam("nodeSelector"); s != "" {
+		selector, err := labels.Parse(s)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid nodeSelector: "+err.Error())
+		}
+		q.NodeSelector = selector
+	}
+
+	ret, err := h.service().Scheduling(q)
+	if errors.Is(err, stats.ErrNotSynced) {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to compute scheduling stats: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, ret)
+}
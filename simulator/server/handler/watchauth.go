@@ -0,0 +1,9 @@
+package handler
+
+import (
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// WatchAuthenticator authenticates the connection to watch resources.
+// It's the same as di.WatchAuthenticator so that the programs embedding the simulator can give their own one.
+type WatchAuthenticator = di.WatchAuthenticator
@@ -1,45 +1,367 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/experiment"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
 )
 
+const (
+	// defaultWebSocketPingInterval is the interval to send pings to the WebSocket clients.
+	defaultWebSocketPingInterval = 30 * time.Second
+	// defaultWebSocketWriteTimeout is the time limit to send a message or a ping to the WebSocket clients.
+	defaultWebSocketWriteTimeout = 10 * time.Second
+)
+
 // ResourceWatcherHandler is a handler for watching the k8s resources in the simulator.
 type ResourceWatcherHandler struct {
-	service di.ResourceWatcherService
+	service func() di.ResourceWatcherService
+	// pingInterval is the interval to send pings to the WebSocket clients.
+	pingInterval time.Duration
+	// writeTimeout is the time limit to send a message or a ping to the WebSocket clients.
+	writeTimeout time.Duration
+	// authenticate authenticates each connection. All connections are accepted if nil.
+	authenticate WatchAuthenticator
+	// allowedOrigins are the origins of the web pages allowed to open the WebSocket, in addition to the same origin.
+	allowedOrigins sets.Set[string]
+}
+
+// NewResourceWatcherHandler initializes ResourceWatcherHandler.
+// authenticate can be nil to accept all connections.
+// allowedOrigins are the origins allowed by the CORS policy, which can open the WebSocket as well. "*" allows any origin.
+func NewResourceWatcherHandler(s func() di.ResourceWatcherService, authenticate WatchAuthenticator, allowedOrigins []string) *ResourceWatcherHandler {
+	return &ResourceWatcherHandler{
+		service:        s,
+		pingInterval:   defaultWebSocketPingInterval,
+		writeTimeout:   defaultWebSocketWriteTimeout,
+		authenticate:   authenticate,
+		allowedOrigins: sets.New(allowedOrigins...),
+	}
+}
+
+// authenticateConnection authenticates the request to watch resources or to manage the watchers,
+// and returns the identity of the client. It returns 401 if the request is rejected.
+func (h *ResourceWatcherHandler) authenticateConnection(c echo.Context) (string, error) {
+	if h.authenticate == nil {
+		return "", nil
+	}
+	identity, err := h.authenticate(c.Request())
+	if err != nil {
+		klog.Infof("rejected the request to %s from %s: %v", c.Path(), c.RealIP(), err)
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+		return "", echo.NewHTTPError(http.StatusUnauthorized)
+	}
+	return identity, nil
+}
+
+// lastResourceVersions gets the last resource versions given by the client.
+// The versions are taken from the cursor in `resourceVersion` if it's given,
+// and the version given for each resource takes precedence over it.
+func lastResourceVersions(c echo.Context) (*resourcewatcher.LastResourceVersions, error) {
+	versions := &resourcewatcher.LastResourceVersions{}
+	if cursor := c.FormValue("resourceVersion"); cursor != "" {
+		v, err := resourcewatcher.ParseCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		versions = v
+	}
+	// If key is not present, FormValue returns the empty string.
+	override := func(version *string, key string) {
+		if v := c.FormValue(key); v != "" {
+			*version = v
+		}
+	}
+	override(&versions.Pods, "podsLastResourceVersion")
+	override(&versions.Nodes, "nodesLastResourceVersion")
+	override(&versions.Pvs, "pvsLastResourceVersion")
+	override(&versions.Pvcs, "pvcsLastResourceVersion")
+	override(&versions.Scs, "scsLastResourceVersion")
+	override(&versions.Pcs, "pcsLastResourceVersion")
+	override(&versions.Namespaces, "namespaceLastResourceVersion")
+	return versions, nil
+}
+
+// watchFilter gets the filter of the events given by the client.
+func watchFilter(c echo.Context) (*resourcewatcher.Filter, error) {
+	filter := &resourcewatcher.Filter{
+		Namespace: c.FormValue("namespace"),
+		Kinds:     sets.New[streamwriter.ResourceKind](),
+	}
+	if s := c.FormValue("labelSelector"); s != "" {
+		selector, err := labels.Parse(s)
+		if err != nil {
+			return nil, xerrors.Errorf("parse labelSelector: %w", err)
+		}
+		filter.LabelSelector = selector
+	}
+	if s := c.FormValue("kinds"); s != "" {
+		for _, k := range strings.Split(s, ",") {
+			kind := streamwriter.ResourceKind(strings.TrimSpace(k))
+			if !resourcewatcher.AllKinds.Has(kind) {
+				return nil, xerrors.Errorf("unknown kind %q, must be one of %v", kind, sets.List(resourcewatcher.AllKinds))
+			}
+			filter.Kinds.Insert(kind)
+		}
+	}
+	if e := experiment.FromContext(c.Request().Context()); e != nil {
+		// Only the resources of the experiment are sent in addition to the labelSelector.
+		if filter.LabelSelector == nil {
+			filter.LabelSelector = labels.Everything()
+		}
+		requirements, _ := e.Selector().Requirements()
+		filter.LabelSelector = filter.LabelSelector.Add(requirements...)
+	}
+	return filter, nil
+}
+
+// batchInterval gets the interval to batch the events given by the client in `batchMs`.
+// The events aren't batched if it's not given.
+func batchInterval(c echo.Context) (time.Duration, error) {
+	s := c.FormValue("batchMs")
+	if s == "" {
+		return 0, nil
+	}
+	ms, err := strconv.Atoi(s)
+	if err != nil || ms < 0 {
+		return 0, xerrors.Errorf("batchMs must be a non-negative integer: %q", s)
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// boolParam gets the boolean given by the client in key. It's false if not given.
+func boolParam(c echo.Context, key string) (bool, error) {
+	s := c.FormValue(key)
+	if s == "" {
+		return false, nil
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, xerrors.Errorf("%s must be a boolean: %q", key, s)
+	}
+	return v, nil
 }
 
-func NewResourceWatcherHandler(s di.ResourceWatcherService) *ResourceWatcherHandler {
-	return &ResourceWatcherHandler{service: s}
+// gvrs gets the additional resources to watch given by the client in `gvr`, which can be repeated.
+func gvrs(c echo.Context) ([]schema.GroupVersionResource, error) {
+	var ret []schema.GroupVersionResource
+	for _, s := range c.QueryParams()["gvr"] {
+		gvr, err := resourcewatcher.ParseGVR(s)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, gvr)
+	}
+	return ret, nil
+}
+
+// watchParams gets the last resource versions and the options of watching given by the client.
+func (h *ResourceWatcherHandler) watchParams(c echo.Context) (*resourcewatcher.LastResourceVersions, resourcewatcher.WatchOptions, error) {
+	versions, err := lastResourceVersions(c)
+	if err != nil {
+		klog.Errorf("failed to parse resourceVersion: %+v", err)
+		return nil, resourcewatcher.WatchOptions{}, echo.NewHTTPError(http.StatusBadRequest, "invalid resourceVersion")
+	}
+	filter, err := watchFilter(c)
+	if err != nil {
+		return nil, resourcewatcher.WatchOptions{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	interval, err := batchInterval(c)
+	if err != nil {
+		return nil, resourcewatcher.WatchOptions{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	strip, err := boolParam(c, "stripResultAnnotations")
+	if err != nil {
+		return nil, resourcewatcher.WatchOptions{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	keepManagedFields, err := boolParam(c, "keepManagedFields")
+	if err != nil {
+		return nil, resourcewatcher.WatchOptions{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	resources, err := gvrs(c)
+	if err == nil {
+		err = h.service().ValidateGVRs(resources)
+	}
+	if err != nil {
+		return nil, resourcewatcher.WatchOptions{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return versions, resourcewatcher.WatchOptions{Filter: filter, BatchInterval: interval, StripResultAnnotations: strip, KeepManagedFields: keepManagedFields, GVRs: resources}, nil
 }
 
 // ListWatchResources provides resource updates using `server-sent events`.
 func (h *ResourceWatcherHandler) ListWatchResources(c echo.Context) error {
 	ctx := c.Request().Context()
-	// If key is not present, FormValue returns the empty string.
-	versions := &resourcewatcher.LastResourceVersions{
-		Pods:       c.FormValue("podsLastResourceVersion"),
-		Nodes:      c.FormValue("nodesLastResourceVersion"),
-		Pvs:        c.FormValue("pvsLastResourceVersion"),
-		Pvcs:       c.FormValue("pvcsLastResourceVersion"),
-		Scs:        c.FormValue("scsLastResourceVersion"),
-		Pcs:        c.FormValue("pcsLastResourceVersion"),
-		Namespaces: c.FormValue("namespaceLastResourceVersion"),
+	identity, err := h.authenticateConnection(c)
+	if err != nil {
+		return err
+	}
+	versions, opts, err := h.watchParams(c)
+	if err != nil {
+		return err
 	}
+	opts.Identity = identity
+	opts.RemoteAddr = c.RealIP()
+	var stream streamwriter.ResponseStream = c.Response()
 	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+	if encoding := negotiateEncoding(c.Request().Header.Get(echo.HeaderAcceptEncoding)); encoding != "" {
+		compressed, err := streamwriter.NewCompressedStream(c.Response(), encoding)
+		if err != nil {
+			return xerrors.Errorf("create compressed stream: %w", err)
+		}
+		defer func() {
+			if err := compressed.Close(); err != nil {
+				klog.Infof("failed to close compressed stream: %v", err)
+			}
+		}()
+		c.Response().Header().Set(echo.HeaderContentEncoding, encoding)
+		stream = compressed
+	}
 	c.Response().WriteHeader(http.StatusOK)
 	// Start to watch and do server push
-	err := h.service.ListWatch(ctx, c.Response(), versions)
-	if err != nil {
-		klog.Errorf("terminated to watch resources: %+v", err)
+	if err := h.service().ListWatch(ctx, stream, versions, opts); err != nil {
+		klog.Errorf("terminated to watch resources for %q: %+v", identity, err)
 		return echo.NewHTTPError(http.StatusInternalServerError)
 	}
 	// We expect this line will be called when the connection is canceled by the client.
 	return c.NoContent(http.StatusOK)
 }
+
+// negotiateEncoding returns the encoding to compress the stream with among the ones the client accepts in Accept-Encoding.
+// gzip is preferred to deflate. The empty string is returned if the client accepts neither of them.
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := sets.New[string]()
+	for _, e := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(e, ";")
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				// q=0 means the encoding isn't acceptable.
+				continue
+			}
+		}
+		accepted.Insert(strings.ToLower(strings.TrimSpace(name)))
+	}
+	for _, encoding := range []string{streamwriter.GzipEncoding, streamwriter.DeflateEncoding} {
+		if accepted.Has(encoding) {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// ListWatchResourcesWebSocket provides the same resource updates as ListWatchResources over WebSocket.
+// Each WatchEvent, or each frame of the WatchEvents if batched, is sent as one text message.
+func (h *ResourceWatcherHandler) ListWatchResourcesWebSocket(c echo.Context) error {
+	identity, err := h.authenticateConnection(c)
+	if err != nil {
+		return err
+	}
+	versions, opts, err := h.watchParams(c)
+	if err != nil {
+		return err
+	}
+	opts.Identity = identity
+	opts.RemoteAddr = c.RealIP()
+	// The browsers don't apply the CORS policy to WebSocket, so the Origin is checked in the handshake instead.
+	s := websocket.Server{Handshake: h.checkOrigin, Handler: func(conn *websocket.Conn) {
+		ctx, cancel := context.WithCancel(c.Request().Context())
+		defer cancel()
+		stream := streamwriter.NewWebSocketStream(conn, h.writeTimeout)
+
+		// The client isn't expected to send messages, but the connection must be read
+		// to respond to the pings from the client and to notice the close from the client.
+		go func() {
+			defer cancel()
+			if _, err := io.Copy(io.Discard, conn); err != nil {
+				klog.Infof("websocket connection for watching resources is closed: %v", err)
+			}
+		}()
+		go h.keepAlive(ctx, cancel, stream)
+
+		if err := h.service().ListWatch(ctx, stream, versions, opts); err != nil {
+			klog.Errorf("terminated to watch resources for %q: %+v", identity, err)
+		}
+		// Close sends the close frame to close the connection cleanly.
+		if err := conn.Close(); err != nil {
+			klog.Infof("failed to close websocket connection: %v", err)
+		}
+	}}
+	s.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+// checkOrigin rejects the WebSocket handshake from the web pages of the origins not allowed,
+// so that any web page can't stream the resources via the browser of the user.
+// The requests without Origin are accepted since they're from non-browser clients.
+func (h *ResourceWatcherHandler) checkOrigin(_ *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" || h.allowedOrigins.Has("*") || h.allowedOrigins.Has(origin) {
+		return nil
+	}
+	if u, err := url.Parse(origin); err == nil && u.Host == r.Host {
+		// the same origin is always allowed as the CORS policy does.
+		return nil
+	}
+	klog.Infof("rejected the websocket connection from %s with the origin %q not allowed", r.RemoteAddr, origin)
+	return xerrors.Errorf("origin %q isn't allowed", origin)
+}
+
+// keepAlive sends pings to the stream periodically until ctx is done,
+// and calls cancel to stop watching when the ping fails since the connection is dead.
+func (h *ResourceWatcherHandler) keepAlive(ctx context.Context, cancel context.CancelFunc, stream *streamwriter.WebSocketStream) {
+	ticker := time.NewTicker(h.pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := stream.Ping(); err != nil {
+				klog.Infof("failed to ping websocket client, closing the connection: %v", err)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// ListWatchers returns the clients watching resources.
+func (h *ResourceWatcherHandler) ListWatchers(c echo.Context) error {
+	if _, err := h.authenticateConnection(c); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, h.service().Watchers())
+}
+
+// DisconnectWatcher disconnects the client watching resources forcibly.
+func (h *ResourceWatcherHandler) DisconnectWatcher(c echo.Context) error {
+	if _, err := h.authenticateConnection(c); err != nil {
+		return err
+	}
+	if err := h.service().Disconnect(c.Param("id")); err != nil {
+		if errors.Is(err, resourcewatcher.ErrWatcherNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		klog.Errorf("failed to disconnect watcher: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
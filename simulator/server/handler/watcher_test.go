@@ -0,0 +1,456 @@
+package handler
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher"
+	sw "sigs.k8s.io/kube-scheduler-simulator/simulator/resourcewatcher/streamwriter"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// fakeResourceWatcherService sends the pods in the client as ADDED events,
+// and sends the pods created after that as ADDED events as well until ctx is done.
+type fakeResourceWatcherService struct {
+	client   clientset.Interface
+	versions chan *resourcewatcher.LastResourceVersions
+	// done is closed when ListWatch returns.
+	done chan struct{}
+	// watchers is the clients returned by Watchers, and removed by Disconnect.
+	watchers []resourcewatcher.Watcher
+}
+
+func (s *fakeResourceWatcherService) ListWatch(ctx context.Context, stream sw.ResponseStream, lrVersions *resourcewatcher.LastResourceVersions, _ resourcewatcher.WatchOptions) error {
+	defer close(s.done)
+	s.versions <- lrVersions
+	writer := sw.NewStreamWriter(stream)
+	pods, err := s.client.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		if err := writer.Write(&sw.WatchEvent{Kind: resourcewatcher.Pods, EventType: watch.Added, Obj: &pods.Items[i]}); err != nil {
+			return err
+		}
+	}
+	w, err := s.client.CoreV1().Pods(metav1.NamespaceAll).Watch(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-w.ResultChan():
+			if err := writer.Write(&sw.WatchEvent{Kind: resourcewatcher.Pods, EventType: e.Type, Obj: e.Object}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ValidateGVRs accepts only the custom resource example.com/v1/widgets.
+func (s *fakeResourceWatcherService) ValidateGVRs(gvrs []schema.GroupVersionResource) error {
+	for _, gvr := range gvrs {
+		if gvr != (schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}) {
+			return xerrors.Errorf("unknown GVR %v", gvr)
+		}
+	}
+	return nil
+}
+
+func (s *fakeResourceWatcherService) Watchers() []resourcewatcher.Watcher {
+	return s.watchers
+}
+
+func (s *fakeResourceWatcherService) Disconnect(id string) error {
+	for i, w := range s.watchers {
+		if w.ID == id {
+			s.watchers = append(s.watchers[:i], s.watchers[i+1:]...)
+			return nil
+		}
+	}
+	return resourcewatcher.ErrWatcherNotFound
+}
+
+type receivedEvent struct {
+	Kind      sw.ResourceKind
+	EventType watch.EventType
+	Obj       corev1.Pod
+}
+
+func TestResourceWatcherHandler_ListWatchResourcesWebSocket(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}})
+	service := &fakeResourceWatcherService{
+		client:   client,
+		versions: make(chan *resourcewatcher.LastResourceVersions, 1),
+		done:     make(chan struct{}),
+	}
+	h := NewResourceWatcherHandler(func() di.ResourceWatcherService { return service }, nil, nil)
+	// ping frequently to make sure pings don't break the messages.
+	h.pingInterval = 10 * time.Millisecond
+	e := echo.New()
+	e.GET("/listwatchresources/ws", h.ListWatchResourcesWebSocket)
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/listwatchresources/ws?podsLastResourceVersion=10"
+	conn, err := websocket.Dial(url, "", server.URL)
+	require.NoError(t, err)
+
+	select {
+	case versions := <-service.versions:
+		assert.Equal(t, &resourcewatcher.LastResourceVersions{Pods: "10"}, versions)
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("ListWatch isn't called")
+	}
+
+	var got receivedEvent
+	require.NoError(t, websocket.JSON.Receive(conn, &got))
+	assert.Equal(t, sw.ResourceKind("pods"), got.Kind)
+	assert.Equal(t, watch.Added, got.EventType)
+	assert.Equal(t, "pod1", got.Obj.Name)
+
+	// wait for some pings before the next event.
+	time.Sleep(50 * time.Millisecond)
+	_, err = client.CoreV1().Pods("default").Create(context.Background(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "default"}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, websocket.JSON.Receive(conn, &got))
+	assert.Equal(t, watch.Added, got.EventType)
+	assert.Equal(t, "pod2", got.Obj.Name)
+
+	// closing the connection from the client should stop watching.
+	require.NoError(t, conn.Close())
+	select {
+	case <-service.done:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("ListWatch doesn't return after the client closes the connection")
+	}
+}
+
+func TestResourceWatcherHandler_ListWatchResourcesWebSocket_origin(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		allowedOrigins []string
+		origin         string
+		wantErr        bool
+	}{
+		{
+			name:           "allowed origin",
+			allowedOrigins: []string{"http://localhost:3000"},
+			origin:         "http://localhost:3000",
+		},
+		{
+			name:           "origin not allowed",
+			allowedOrigins: []string{"http://localhost:3000"},
+			origin:         "http://evil.example.com",
+			wantErr:        true,
+		},
+		{
+			name:    "origin not allowed without the allowed origins",
+			origin:  "http://evil.example.com",
+			wantErr: true,
+		},
+		{
+			name:           "any origin is allowed with *",
+			allowedOrigins: []string{"*"},
+			origin:         "http://evil.example.com",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			service := &fakeResourceWatcherService{
+				client:   fake.NewSimpleClientset(),
+				versions: make(chan *resourcewatcher.LastResourceVersions, 1),
+				done:     make(chan struct{}),
+			}
+			h := NewResourceWatcherHandler(func() di.ResourceWatcherService { return service }, nil, tt.allowedOrigins)
+			e := echo.New()
+			e.GET("/listwatchresources/ws", h.ListWatchResourcesWebSocket)
+			server := httptest.NewServer(e)
+			defer server.Close()
+
+			conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/listwatchresources/ws", "", tt.origin)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, service.versions, "the rejected connection doesn't start watching")
+				return
+			}
+			require.NoError(t, err)
+			defer conn.Close()
+		})
+	}
+}
+
+func TestResourceWatcherHandler_checkOrigin(t *testing.T) {
+	t.Parallel()
+	h := NewResourceWatcherHandler(nil, nil, []string{"http://localhost:3000"})
+	req := httptest.NewRequest(http.MethodGet, "http://simulator.example.com:1212/api/v1/listwatchresources/ws", nil)
+	assert.NoError(t, h.checkOrigin(nil, req), "non-browser clients don't send Origin")
+
+	req.Header.Set("Origin", "http://simulator.example.com:1212")
+	assert.NoError(t, h.checkOrigin(nil, req), "the same origin is allowed")
+
+	req.Header.Set("Origin", "http://simulator.example.com:3000")
+	assert.Error(t, h.checkOrigin(nil, req))
+}
+
+func TestResourceWatcherHandler_ListWatchResources_invalidGVR(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{
+			name:  "unknown GVR",
+			query: "gvr=example.com/v1/widgets&gvr=example.com/v1/gadgets",
+		},
+		{
+			name:  "malformed GVR",
+			query: "gvr=widgets",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			service := &fakeResourceWatcherService{client: fake.NewSimpleClientset()}
+			h := NewResourceWatcherHandler(func() di.ResourceWatcherService { return service }, nil, nil)
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/listwatchresources?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			err := h.ListWatchResources(e.NewContext(req, rec))
+			var httpErr *echo.HTTPError
+			require.ErrorAs(t, err, &httpErr)
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+		})
+	}
+}
+
+func Test_negotiateEncoding(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		want           string
+	}{
+		{
+			name:           "no Accept-Encoding",
+			acceptEncoding: "",
+			want:           "",
+		},
+		{
+			name:           "gzip is preferred to deflate",
+			acceptEncoding: "deflate, gzip;q=0.8, br",
+			want:           "gzip",
+		},
+		{
+			name:           "deflate",
+			acceptEncoding: "deflate",
+			want:           "deflate",
+		},
+		{
+			name:           "gzip with q=0 isn't acceptable",
+			acceptEncoding: "GZIP;q=0, deflate",
+			want:           "deflate",
+		},
+		{
+			name:           "unsupported encoding",
+			acceptEncoding: "br, identity",
+			want:           "",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, negotiateEncoding(tt.acceptEncoding))
+		})
+	}
+}
+
+func TestResourceWatcherHandler_ListWatchResources_gzip(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}})
+	service := &fakeResourceWatcherService{
+		client:   client,
+		versions: make(chan *resourcewatcher.LastResourceVersions, 1),
+		done:     make(chan struct{}),
+	}
+	h := NewResourceWatcherHandler(func() di.ResourceWatcherService { return service }, nil, nil)
+	e := echo.New()
+	e.GET("/listwatchresources", h.ListWatchResources)
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/listwatchresources", nil)
+	require.NoError(t, err)
+	// The transport doesn't decompress the response when Accept-Encoding is set explicitly.
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+
+	r, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+	dec := json.NewDecoder(r)
+	var got receivedEvent
+	require.NoError(t, dec.Decode(&got))
+	assert.Equal(t, "pod1", got.Obj.Name)
+
+	// the event created later arrives without waiting for the end of the stream.
+	_, err = client.CoreV1().Pods("default").Create(context.Background(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "default"}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, dec.Decode(&got))
+	assert.Equal(t, "pod2", got.Obj.Name)
+}
+
+// authenticateSecret accepts only the connections with `Authorization: Bearer secret`.
+func authenticateSecret(r *http.Request) (string, error) {
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		return "", xerrors.New("invalid bearer token")
+	}
+	return "secret", nil
+}
+
+func TestResourceWatcherHandler_ListWatchResources_authentication(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		authorization string
+		wantCode      int
+	}{
+		{
+			name:          "accepted token",
+			authorization: "Bearer secret",
+			wantCode:      http.StatusOK,
+		},
+		{
+			name:          "invalid token",
+			authorization: "Bearer wrong",
+			wantCode:      http.StatusUnauthorized,
+		},
+		{
+			name:          "no token",
+			authorization: "",
+			wantCode:      http.StatusUnauthorized,
+		},
+		{
+			name:          "not bearer",
+			authorization: "Basic c2VjcmV0",
+			wantCode:      http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			service := &fakeResourceWatcherService{
+				// the pod makes the response flushed to the client.
+				client:   fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}),
+				versions: make(chan *resourcewatcher.LastResourceVersions, 1),
+				done:     make(chan struct{}),
+			}
+			h := NewResourceWatcherHandler(func() di.ResourceWatcherService { return service }, authenticateSecret, nil)
+			e := echo.New()
+			e.GET("/listwatchresources", h.ListWatchResources)
+			server := httptest.NewServer(e)
+			defer server.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/listwatchresources", nil)
+			require.NoError(t, err)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.wantCode, resp.StatusCode)
+			if tt.wantCode == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", resp.Header.Get("WWW-Authenticate"))
+				// the rejected connection doesn't start watching.
+				assert.Empty(t, service.versions)
+				return
+			}
+			select {
+			case <-service.versions:
+			case <-time.After(wait.ForeverTestTimeout):
+				t.Fatal("ListWatch isn't called for the accepted connection")
+			}
+		})
+	}
+}
+
+func TestResourceWatcherHandler_Watchers(t *testing.T) {
+	t.Parallel()
+	connectedAt := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	service := &fakeResourceWatcherService{
+		watchers: []resourcewatcher.Watcher{
+			{ID: "1", RemoteAddr: "192.0.2.1", ConnectedAt: connectedAt, Kinds: []sw.ResourceKind{resourcewatcher.Pods}, EventsSent: 10},
+			{ID: "2", RemoteAddr: "192.0.2.2", ConnectedAt: connectedAt, EventsSent: 3, DroppedEvents: 2, QueueDepth: 5},
+		},
+	}
+	h := NewResourceWatcherHandler(func() di.ResourceWatcherService { return service }, nil, nil)
+	e := echo.New()
+	e.GET("/watchers", h.ListWatchers)
+	e.DELETE("/watchers/:id", h.DisconnectWatcher)
+
+	list := func() []resourcewatcher.Watcher {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/watchers", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got []resourcewatcher.Watcher
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		return got
+	}
+	got := list()
+	require.Len(t, got, 2)
+	assert.Equal(t, "1", got[0].ID)
+	assert.Equal(t, []sw.ResourceKind{resourcewatcher.Pods}, got[0].Kinds)
+	assert.Equal(t, int64(10), got[0].EventsSent)
+	assert.Equal(t, "2", got[1].ID)
+	assert.Equal(t, int64(2), got[1].DroppedEvents)
+	assert.Equal(t, 5, got[1].QueueDepth)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/watchers/2", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	got = list()
+	require.Len(t, got, 1)
+	assert.Equal(t, "1", got[0].ID)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/watchers/2", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/whatif"
+)
+
+// WhatIfHandler is handler for evaluating where a Pod would be scheduled without creating it.
+type WhatIfHandler struct {
+	service func() di.WhatIfService
+}
+
+// WhatIfBatchRequest is the request to evaluate a batch of the Pods.
+type WhatIfBatchRequest struct {
+	Items []whatif.BatchItem `json:"items"`
+}
+
+// NewWhatIfHandler initializes WhatIfHandler.
+func NewWhatIfHandler(s func() di.WhatIfService) *WhatIfHandler {
+	return &WhatIfHandler{service: s}
+}
+
+// Pod runs the scheduling cycle of the Pod in the request body, and returns the node it would be bound to
+// with the results of each plugin. The Pod is neither created nor bound.
+func (h *WhatIfHandler) Pod(c echo.Context) error {
+	pod := new(corev1.Pod)
+	if err := bindJSONOrYAML(c, pod); err != nil {
+		klog.Errorf("failed to bind what-if pod request: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	ret, err := h.service().Schedule(c.Request().Context(), pod)
+	if errors.Is(err, whatif.ErrUnknownProfile) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if errors.Is(err, whatif.ErrNotSynced) {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to schedule the what-if pod: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, ret)
+}
+
+// Batch evaluates the Pods created from the templates in the request body one by one,
+// assuming the Pods placed before are bound, and returns the packing plan. Nothing is created nor bound.
+func (h *WhatIfHandler) Batch(c echo.Context) error {
+	req := new(WhatIfBatchRequest)
+	if err := bindJSONOrYAML(c, req); err != nil {
+		klog.Errorf("failed to bind what-if batch request: %+v", err)
+		return echo.NewHTTPError(http.StatusBadRequest)
+	}
+
+	ret, err := h.service().ScheduleBatch(c.Request().Context(), req.Items)
+	if errors.Is(err, whatif.ErrInvalidBatch) || errors.Is(err, whatif.ErrUnknownProfile) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if errors.Is(err, whatif.ErrNotSynced) {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, err.Error())
+	}
+	if err != nil {
+		klog.Errorf("failed to schedule the what-if batch: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, ret)
+}
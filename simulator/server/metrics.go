@@ -0,0 +1,88 @@
+package server
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	httpRequests = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "kube_scheduler_simulator",
+			Name:           "http_requests_total",
+			Help:           "Number of the HTTP requests to the simulator server by method, route and status code.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"method", "path", "code"})
+
+	httpRequestDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      "kube_scheduler_simulator",
+			Name:           "http_request_duration_seconds",
+			Help:           "Latency of the HTTP requests to the simulator server by method and route. The streaming requests are observed when they end.",
+			Buckets:        metrics.ExponentialBuckets(0.001, 4, 10),
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"method", "path"})
+
+	registerMetricsOnce sync.Once
+)
+
+// unmatchedPath is the path label of the requests which don't match any route,
+// so that the random paths don't make the cardinality of the metrics unbounded.
+const unmatchedPath = "unmatched"
+
+// RegisterMetrics registers the metrics of the HTTP requests to the legacy registry, which is served at /metrics.
+func RegisterMetrics() {
+	registerMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(httpRequests, httpRequestDuration)
+	})
+}
+
+// route is the method and the path of a route.
+type route struct {
+	method string
+	path   string
+}
+
+// metricsMiddleware records the number and the latency of the requests by the route, not by the actual path.
+// The routes are collected on the first request since the middleware is installed before they're registered.
+func metricsMiddleware(e *echo.Echo) echo.MiddlewareFunc {
+	var (
+		once   sync.Once
+		routes sets.Set[route]
+	)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			if err := next(c); err != nil {
+				// The error handler writes the status code of the error.
+				c.Error(err)
+			}
+			once.Do(func() { routes = registeredRoutes(e) })
+			method, path := c.Request().Method, c.Path()
+			// echo sets the actual path to the request not matching any route.
+			if !routes.Has(route{method: method, path: path}) {
+				path = unmatchedPath
+			}
+			httpRequests.WithLabelValues(method, path, strconv.Itoa(c.Response().Status)).Inc()
+			httpRequestDuration.WithLabelValues(method, path).Observe(time.Since(start).Seconds())
+			return nil
+		}
+	}
+}
+
+// registeredRoutes returns the routes registered to e.
+func registeredRoutes(e *echo.Echo) sets.Set[route] {
+	routes := sets.New[route]()
+	for _, r := range e.Routes() {
+		routes.Insert(route{method: r.Method, path: r.Path})
+	}
+	return routes
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	t.Parallel()
+	RegisterMetrics()
+	e := echo.New()
+	e.Use(metricsMiddleware(e))
+	e.GET("/metrics", echo.WrapHandler(legacyregistry.Handler()))
+	e.GET("/test/items/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	e.PUT("/test/items/:id", func(_ echo.Context) error {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	})
+	s := httptest.NewServer(e)
+	defer s.Close()
+
+	for _, r := range []struct {
+		method string
+		path   string
+	}{
+		{method: http.MethodGet, path: "/test/items/1"},
+		{method: http.MethodGet, path: "/test/items/2"},
+		{method: http.MethodPut, path: "/test/items/1"},
+		{method: http.MethodGet, path: "/test/unknown"},
+		{method: http.MethodDelete, path: "/test/items/1"},
+	} {
+		req, err := http.NewRequest(r.method, s.URL+r.path, nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	resp, err := http.Get(s.URL + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	body := string(b)
+
+	// The requests are counted by the route, not by the actual path.
+	assert.Contains(t, body, `kube_scheduler_simulator_http_requests_total{code="200",method="GET",path="/test/items/:id"} 2`)
+	assert.Contains(t, body, `kube_scheduler_simulator_http_requests_total{code="400",method="PUT",path="/test/items/:id"} 1`)
+	assert.Contains(t, body, `kube_scheduler_simulator_http_requests_total{code="404",method="GET",path="unmatched"} 1`)
+	// The method is matched as well as the path.
+	assert.Contains(t, body, `kube_scheduler_simulator_http_requests_total{code="405",method="DELETE",path="unmatched"} 1`)
+	assert.Contains(t, body, `kube_scheduler_simulator_http_request_duration_seconds_count{method="GET",path="/test/items/:id"} 2`)
+	// The standard metrics of the process are served as well.
+	assert.Contains(t, body, "go_goroutines")
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"sigs.k8s.io/yaml"
+)
+
+// openAPIYAML is the OpenAPI document of the simulator's API, maintained by hand.
+//
+//go:embed openapi.yaml
+var openAPIYAML []byte
+
+// openAPIJSON is openAPIYAML converted to JSON, served at /openapi.json.
+var openAPIJSON = mustYAMLToJSON(openAPIYAML)
+
+func mustYAMLToJSON(b []byte) []byte {
+	j, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		panic("openapi.yaml is broken: " + err.Error())
+	}
+	return j
+}
+
+// serveOpenAPI serves the OpenAPI document of the simulator's API.
+func serveOpenAPI(c echo.Context) error {
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, openAPIJSON)
+}
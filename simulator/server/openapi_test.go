@@ -0,0 +1,71 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+)
+
+type openAPIDocument struct {
+	OpenAPI string                                `json:"openapi"`
+	Paths   map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// echoParam matches the path parameters in echo, e.g., ":id".
+var echoParam = regexp.MustCompile(`:([^/]+)`)
+
+// TestOpenAPISpecCoversRoutes checks that openapi.yaml describes all the routes under /api/v1, and nothing else.
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	t.Parallel()
+	var doc openAPIDocument
+	require.NoError(t, json.Unmarshal(openAPIJSON, &doc))
+	require.True(t, strings.HasPrefix(doc.OpenAPI, "3."), "openapi version %q", doc.OpenAPI)
+
+	e := echo.New()
+	registerRoutes(e, &config.Config{}, nil, &handlers{})
+	routes := []string{}
+	for _, r := range e.Routes() {
+		path, ok := strings.CutPrefix(r.Path, "/api/v1")
+		if !ok || path == "" || path == "/*" {
+			// The routes without the path are the ones echo adds for the middlewares of the group.
+			continue
+		}
+		routes = append(routes, r.Method+" "+echoParam.ReplaceAllString(path, "{$1}"))
+	}
+
+	described := []string{}
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			if method == "parameters" {
+				continue
+			}
+			described = append(described, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(routes)
+	sort.Strings(described)
+	assert.Equal(t, routes, described, "the routes under /api/v1 and openapi.yaml mismatch")
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	t.Parallel()
+	e := echo.New()
+	registerRoutes(e, &config.Config{}, nil, &handlers{})
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+	assert.True(t, json.Valid(rec.Body.Bytes()))
+}
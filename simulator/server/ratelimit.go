@@ -0,0 +1,39 @@
+package server
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/auth"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/ratelimit"
+)
+
+// rateLimitMiddleware rejects the requests exceeding the limit of the client with 429.
+// The response has Retry-After, the seconds after which the client can retry.
+func rateLimitMiddleware(l *ratelimit.Limiter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ok, retryAfter := l.Allow(rateLimitClient(c), c.Request().Method, c.Path())
+			if ok {
+				return next(c)
+			}
+			klog.V(3).InfoS("Rejected the request exceeding the rate limit", "method", c.Request().Method, "path", c.Request().URL.Path, "remoteAddr", c.RealIP())
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			return echo.NewHTTPError(http.StatusTooManyRequests, "too many requests, retry after "+retryAfter.String())
+		}
+	}
+}
+
+// rateLimitClient identifies the client by the identity authenticated by authMiddleware if any, and by the IP address otherwise.
+// The unauthenticated bearer tokens aren't used since the client can get a new bucket just by changing the token.
+// The IP address is the one of the direct peer, as the echo server has echo.ExtractIPDirect as its IPExtractor.
+func rateLimitClient(c echo.Context) string {
+	if id := auth.IdentityFrom(c.Request().Context()); id != nil {
+		return "client:" + id.Name
+	}
+	return "ip:" + c.RealIP()
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testingclock "k8s.io/utils/clock/testing"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/auth"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/ratelimit"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	t.Parallel()
+	clk := testingclock.NewFakePassiveClock(time.Now())
+	limit := rateLimitMiddleware(ratelimit.New(&ratelimit.Options{Default: ratelimit.Limit{RequestsPerSecond: 1, Burst: 2}}, clk))
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	// The authenticated API is limited by the clients, and the other one is limited by the IP addresses.
+	authenticated := e.Group("/api/v1", authMiddleware(auth.New(&auth.Options{Tokens: []auth.StaticToken{
+		{Name: "a", Token: "token-a", Role: auth.RoleReadWrite},
+		{Name: "b", Token: "token-b", Role: auth.RoleReadWrite},
+	}})), limit)
+	authenticated.POST("/pods/bulk", ok)
+	authenticated.GET("/listwatchresources", ok)
+	e.Group("/public", limit).POST("/pods/bulk", ok)
+
+	do := func(method, path, token, remoteAddr string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = remoteAddr
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		if token != "" {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, do(http.MethodPost, "/api/v1/pods/bulk", "token-a", "192.0.2.1:1234", nil).Code)
+	}
+	rec := do(http.MethodPost, "/api/v1/pods/bulk", "token-a", "192.0.2.2:1234", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "the authenticated client is limited wherever it's from")
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/api/v1/pods/bulk", "token-b", "192.0.2.1:1234", nil).Code, "another client has its own bucket")
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/v1/listwatchresources", "token-a", "192.0.2.1:1234", nil).Code, "the streams are unlimited")
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, do(http.MethodPost, "/public/pods/bulk", "", "192.0.2.3:1234", nil).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, do(http.MethodPost, "/public/pods/bulk", "", "192.0.2.3:5678", nil).Code, "the unauthenticated client is limited by the IP address")
+	assert.Equal(t, http.StatusTooManyRequests, do(http.MethodPost, "/public/pods/bulk", "unknown", "192.0.2.3:1234", nil).Code, "the unauthenticated token doesn't get its own bucket")
+	assert.Equal(t, http.StatusTooManyRequests, do(http.MethodPost, "/public/pods/bulk", "", "192.0.2.3:1234",
+		http.Header{"X-Forwarded-For": []string{"198.51.100.1"}, "X-Real-Ip": []string{"198.51.100.2"}}).Code, "the forwarded headers aren't trusted")
+
+	clk.SetTime(clk.Now().Add(time.Second))
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/api/v1/pods/bulk", "token-a", "192.0.2.1:1234", nil).Code, "the bucket is refilled")
+}
@@ -3,15 +3,22 @@ package server
 import (
 	"context"
 	"errors"
+	"net"
 	"net/http"
-	"strconv"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/labstack/gommon/log"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/utils/clock"
 
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/ratelimit"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/handler"
 )
@@ -19,56 +26,312 @@ import (
 // SimulatorServer is server for simulator.
 type SimulatorServer struct {
 	e *echo.Echo
+	// metrics and extenderProxy serve /metrics and the proxy for the extenders on their own addresses.
+	// They're nil when e serves them.
+	metrics       *echo.Echo
+	extenderProxy *echo.Echo
+	ports         config.Ports
 }
 
 // NewSimulatorServer initialize SimulatorServer.
-func NewSimulatorServer(cfg *config.Config, dic *di.Container) *SimulatorServer {
+// It returns an error if the extra handlers in dic conflict with the built-in routes.
+func NewSimulatorServer(cfg *config.Config, dic *di.Container) (*SimulatorServer, error) {
 	e := echo.New()
+	// The clients are identified by the direct peer for the rate limit, since X-Forwarded-For and X-Real-IP can be spoofed.
+	e.IPExtractor = echo.ExtractIPDirect()
 
 	e.Use(middleware.Logger())
-	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins:     cfg.CorsAllowedOriginList,
-		AllowCredentials: true,
-	}))
+	e.Use(metricsMiddleware(e))
+	e.Use(corsMiddleware(cfg))
+	e.Use(timeoutMiddleware(cfg.RequestTimeout))
 
-	// initialize each handler
-	schedulercfgHandler := handler.NewSchedulerConfigHandler(dic.SchedulerService())
-	snapshotHandler := handler.NewSnapshotHandler(dic.ExportService())
-	resetHandler := handler.NewResetHandler(dic.ResetService())
-	resourcewatcherHandler := handler.NewResourceWatcherHandler(dic.ResourceWatcherService())
-	extenderHandler := handler.NewExtenderHandler(dic.ExtenderService())
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit != nil {
+		limiter = ratelimit.New(cfg.RateLimit, clock.RealClock{})
+		dic.ConfigReloadService().Register(config.FieldRateLimit, func(cfg *config.Config) error {
+			limiter.ApplyConfig(cfg.RateLimit)
+			return nil
+		})
+	}
 
-	// register apis
-	v1 := e.Group("/api/v1")
+	h := newHandlers(cfg, dic)
+	v1 := registerRoutes(e, cfg, limiter, h)
+	if err := registerExtraHandlers(e, v1, dic.ExtraHandlers()); err != nil {
+		return nil, err
+	}
 
-	v1.GET("/schedulerconfiguration", schedulercfgHandler.GetSchedulerConfig)
-	v1.POST("/schedulerconfiguration", schedulercfgHandler.ApplySchedulerConfig)
+	// initialize SimulatorServer.
+	s := &SimulatorServer{e: e, ports: cfg.Ports}
+	s.e.Logger.SetLevel(log.INFO)
+	if cfg.Ports.Metrics.Enabled() {
+		s.metrics = newMetricsServer(cfg)
+	}
+	if cfg.Ports.ExtenderProxy.Enabled() {
+		s.extenderProxy = newExtenderProxyServer(h)
+	}
 
-	v1.PUT("/reset", resetHandler.Reset)
+	return s, nil
+}
 
-	v1.GET("/export", snapshotHandler.Snap)
-	v1.POST("/import", snapshotHandler.Load)
+// newMetricsServer initializes the server serving only /metrics.
+func newMetricsServer(cfg *config.Config) *echo.Echo {
+	e := echo.New()
+	_, _, metricsAuth := authMiddlewares(cfg.Auth)
+	e.GET("/metrics", echo.WrapHandler(legacyregistry.Handler()), metricsAuth...)
+	e.Logger.SetLevel(log.INFO)
+	return e
+}
 
-	v1.GET("/listwatchresources", resourcewatcherHandler.ListWatchResources)
+// newExtenderProxyServer initializes the server serving only the proxy for the extenders.
+func newExtenderProxyServer(h *handlers) *echo.Echo {
+	e := echo.New()
+	e.Use(middleware.Logger())
+	// The injections aren't served here since this server has neither the authentication nor the rate limit.
+	RouteExtender(e.Group("/api/v1"), h.extender)
+	e.Logger.SetLevel(log.INFO)
+	return e
+}
 
-	RouteExtender(v1, extenderHandler)
+// handlers is the handlers of the simulator's API.
+type handlers struct {
+	schedulerConfig    *handler.SchedulerConfigHandler
+	schedulerQueue     *handler.SchedulerQueueHandler
+	secondaryScheduler *handler.SecondarySchedulerHandler
+	snapshot           *handler.SnapshotHandler
+	etcdSnapshot       *handler.EtcdSnapshotHandler
+	reset              *handler.ResetHandler
+	resourceWatcher    *handler.ResourceWatcherHandler
+	extender           *handler.ExtenderHandler
+	extenderInjection  *handler.ExtenderInjectionHandler
+	clusterImport      *handler.ClusterImportHandler
+	bulkPod            *handler.BulkPodHandler
+	bulkNode           *handler.BulkNodeHandler
+	nodeFailure        *handler.NodeFailureHandler
+	schedulingResults  *handler.SchedulingResultsHandler
+	diagnostics        *handler.DiagnosticsHandler
+	stats              *handler.StatsHandler
+	whatIf             *handler.WhatIfHandler
+	resourceList       *handler.ResourceListHandler
+	job                *handler.JobHandler
+	scenario           *handler.ScenarioHandler
+	experiment         *handler.ExperimentHandler
+	logs               *handler.LogsHandler
+	component          *handler.ComponentHandler
+	configReload       *handler.ConfigReloadHandler
+	health             *handler.HealthHandler
+}
 
-	// initialize SimulatorServer.
-	s := &SimulatorServer{e: e}
-	s.e.Logger.SetLevel(log.INFO)
+// newHandlers initializes each handler with the services in dic.
+// The services constructed lazily by dic are given as the accessors
+// so that they aren't constructed until their APIs are called.
+func newHandlers(cfg *config.Config, dic *di.Container) *handlers {
+	return &handlers{
+		schedulerConfig:    handler.NewSchedulerConfigHandler(dic.SchedulerService(), dic.RescheduleService),
+		schedulerQueue:     handler.NewSchedulerQueueHandler(dic.SchedulerService()),
+		secondaryScheduler: handler.NewSecondarySchedulerHandler(dic.SchedulerService()),
+		snapshot:           handler.NewSnapshotHandler(dic.ExportService, dic.ResetService()),
+		etcdSnapshot:       handler.NewEtcdSnapshotHandler(dic.EtcdSnapshotService),
+		reset:              handler.NewResetHandler(dic.ResetService()),
+		resourceWatcher:    handler.NewResourceWatcherHandler(dic.ResourceWatcherService, watchAuthenticator(cfg, dic), cfg.CorsAllowedOriginList),
+		extender:           handler.NewExtenderHandler(dic.ExtenderService()),
+		extenderInjection:  handler.NewExtenderInjectionHandler(dic.ExtenderInjectionService()),
+		clusterImport:      handler.NewClusterImportHandler(dic.OneshotClusterResourceImporter()),
+		bulkPod:            handler.NewBulkPodHandler(dic.BulkPodService),
+		bulkNode:           handler.NewBulkNodeHandler(dic.BulkNodeService),
+		nodeFailure:        handler.NewNodeFailureHandler(dic.NodeFailureService),
+		schedulingResults:  handler.NewSchedulingResultsHandler(dic.SchedulingResultsService),
+		diagnostics:        handler.NewDiagnosticsHandler(dic.DiagnosticsService),
+		stats:              handler.NewStatsHandler(dic.StatsService),
+		whatIf:             handler.NewWhatIfHandler(dic.WhatIfService),
+		resourceList:       handler.NewResourceListHandler(dic.ResourceListService),
+		job:                handler.NewJobHandler(dic.JobManager, dic.ExportService, dic.ResetService(), dic.ReplayService()),
+		scenario:           handler.NewScenarioHandler(dic.ScenarioService),
+		experiment:         handler.NewExperimentHandler(dic.ExperimentService),
+		logs:               handler.NewLogsHandler(dic.LogService()),
+		component:          handler.NewComponentHandler(dic.Components()),
+		configReload:       handler.NewConfigReloadHandler(dic.ConfigReloadService()),
+		health:             handler.NewHealthHandler(dic.LivenessChecks(), dic.ReadinessChecks()),
+	}
+}
+
+// experimentScopedRoutes are the routes which can be scoped to an experiment with handler.ExperimentHeader.
+var experimentScopedRoutes = sets.New(
+	"PUT /api/v1/reset",
+	"POST /api/v1/pods/bulk",
+	"POST /api/v1/nodes/bulk",
+	"GET /api/v1/stats/scheduling",
+	"GET /api/v1/listwatchresources",
+	"GET /api/v1/listwatchresources/ws",
+)
+
+// registerRoutes registers the routes of the simulator's API, and returns the group of /api/v1.
+// The routes under /api/v1 must be described in openapi.yaml as well.
+// The requests to them aren't limited if limiter is nil.
+func registerRoutes(e *echo.Echo, cfg *config.Config, limiter *ratelimit.Limiter, h *handlers) *echo.Group {
+	apiAuth, healthzAuth, metricsAuth := authMiddlewares(cfg.Auth)
+
+	if !cfg.Ports.Metrics.Enabled() {
+		e.GET("/metrics", echo.WrapHandler(legacyregistry.Handler()), metricsAuth...)
+	}
+	e.GET("/healthz", h.health.Healthz, healthzAuth...)
+	e.GET("/readyz", h.health.Readyz, healthzAuth...)
+	e.GET("/openapi.json", serveOpenAPI)
+
+	v1 := e.Group("/api/v1", apiAuth...)
+	if limiter != nil {
+		// It's after the authentication so that the requests without any valid credential don't take the tokens.
+		v1.Use(rateLimitMiddleware(limiter))
+	}
+	v1.Use(h.experiment.Scope(experimentScopedRoutes))
+
+	v1.GET("/schedulerconfiguration", h.schedulerConfig.GetSchedulerConfig)
+	v1.POST("/schedulerconfiguration", h.schedulerConfig.ApplySchedulerConfig)
+	v1.POST("/schedulerconfiguration/validate", h.schedulerConfig.ValidateSchedulerConfig)
+	v1.GET("/schedulerconfiguration/active", h.schedulerConfig.GetActiveSchedulerConfig)
+	v1.GET("/scheduler/queue", h.schedulerQueue.GetQueue)
+	v1.GET("/scheduler/secondaries", h.secondaryScheduler.List)
+	v1.POST("/scheduler/secondaries", h.secondaryScheduler.Start)
+	v1.DELETE("/scheduler/secondaries/:name", h.secondaryScheduler.Stop)
+
+	v1.PUT("/reset", h.reset.Reset)
+
+	v1.GET("/export", h.snapshot.Snap)
+	v1.POST("/import", h.snapshot.Load)
+
+	v1.GET("/snapshot", h.etcdSnapshot.List)
+	v1.POST("/snapshot", h.etcdSnapshot.Save)
+	v1.PUT("/snapshot/:name/restore", h.etcdSnapshot.Restore)
+
+	v1.POST("/import/cluster", h.clusterImport.Import)
+	v1.GET("/import/cluster/status", h.clusterImport.Status)
+	v1.GET("/import/diff", h.clusterImport.Diff)
+
+	v1.POST("/pods/bulk", h.bulkPod.Create)
+	v1.POST("/nodes/bulk", h.bulkNode.Create)
+	v1.POST("/nodes/:name/fail", h.nodeFailure.Fail)
+	v1.POST("/nodes/:name/recover", h.nodeFailure.Recover)
+
+	v1.GET("/schedulingresults", h.schedulingResults.List)
+	v1.GET("/diagnostics/unschedulable", h.diagnostics.Unschedulable)
+	v1.GET("/stats/scheduling", h.stats.Scheduling)
+	v1.GET("/stats/latency", h.schedulingResults.Latency)
+	v1.POST("/whatif/pod", h.whatIf.Pod)
+	v1.POST("/whatif/batch", h.whatIf.Batch)
+
+	v1.GET("/resources/:resource", h.resourceList.List)
 
-	return s
+	v1.POST("/jobs", h.job.Submit)
+	v1.GET("/jobs", h.job.List)
+	v1.GET("/jobs/:id", h.job.Get)
+	v1.DELETE("/jobs/:id", h.job.Cancel)
+
+	v1.POST("/scenarios", h.scenario.Submit)
+	v1.GET("/scenarios/:id", h.scenario.Get)
+
+	v1.POST("/experiments", h.experiment.Create)
+	v1.GET("/experiments", h.experiment.List)
+	v1.GET("/experiments/:id", h.experiment.Get)
+	v1.DELETE("/experiments/:id", h.experiment.Delete)
+
+	v1.GET("/logs", h.logs.List)
+	v1.GET("/logs/stream", h.logs.Stream)
+
+	v1.GET("/components", h.component.List)
+	v1.GET("/components/:name", h.component.Get)
+	v1.PUT("/components/:name", h.component.Update)
+
+	v1.POST("/config/reload", h.configReload.Reload)
+
+	v1.GET("/listwatchresources", h.resourceWatcher.ListWatchResources)
+	v1.GET("/listwatchresources/ws", h.resourceWatcher.ListWatchResourcesWebSocket)
+	v1.GET("/watchers", h.resourceWatcher.ListWatchers)
+	v1.DELETE("/watchers/:id", h.resourceWatcher.DisconnectWatcher)
+
+	// The injections are managed by the users, so they're authenticated as the other APIs
+	// even if the extender endpoints are served on their own port.
+	RouteExtenderInjection(v1, h.extenderInjection)
+	if !cfg.Ports.ExtenderProxy.Enabled() {
+		// The extender endpoints are called by the scheduler, which doesn't have any token.
+		RouteExtender(e.Group("/api/v1"), h.extender)
+	}
+	return v1
+}
+
+// routeParam matches the path parameters in echo, e.g., ":id".
+var routeParam = regexp.MustCompile(`:[^/]+`)
+
+// registerExtraHandlers registers the extra handlers to v1 after the built-in routes are registered to e.
+// It returns an error if any of them conflicts with the registered routes, including the ones with the different names of the parameters,
+// since echo replaces the registered route silently.
+func registerExtraHandlers(e *echo.Echo, v1 *echo.Group, extras []di.ExtraHandler) error {
+	// routeKey returns the key of the route, which is the same for the routes matching the same requests.
+	routeKey := func(method, path string) string {
+		return strings.ToUpper(method) + " " + routeParam.ReplaceAllString(path, ":")
+	}
+	registered := sets.New[string]()
+	for _, r := range e.Routes() {
+		registered.Insert(routeKey(r.Method, r.Path))
+	}
+	for _, extra := range extras {
+		if !strings.HasPrefix(extra.Path, "/") {
+			return xerrors.Errorf("the path of the extra handler %s %s must start with /", extra.Method, extra.Path)
+		}
+		key := routeKey(extra.Method, "/api/v1"+extra.Path)
+		if registered.Has(key) {
+			return xerrors.Errorf("the extra handler %s %s conflicts with the route already registered", extra.Method, "/api/v1"+extra.Path)
+		}
+		registered.Insert(key)
+		v1.Add(strings.ToUpper(extra.Method), extra.Path, extra.Handler)
+	}
+	return nil
 }
 
-// Start starts SimulatorServer.
-func (s *SimulatorServer) Start(port int) (
+// Start starts SimulatorServer, and the servers for /metrics and the proxy for the extenders if they have their own addresses.
+// It binds the addresses before returning so that the caller gets an error if any of them cannot be used.
+func (s *SimulatorServer) Start() (
 	func(), // function for shutdown
 	error,
 ) {
-	e := s.e
+	var shutdownFns []func()
+	shutdownFn := func() {
+		for i := len(shutdownFns) - 1; i >= 0; i-- {
+			shutdownFns[i]()
+		}
+	}
+	for _, server := range []struct {
+		e        *echo.Echo
+		listener config.Listener
+	}{
+		{e: s.e, listener: s.ports.API},
+		{e: s.metrics, listener: s.ports.Metrics},
+		{e: s.extenderProxy, listener: s.ports.ExtenderProxy},
+	} {
+		if server.e == nil {
+			continue
+		}
+		fn, err := startEcho(server.e, server.listener)
+		if err != nil {
+			shutdownFn()
+			return nil, err
+		}
+		shutdownFns = append(shutdownFns, fn)
+	}
+	return shutdownFn, nil
+}
+
+// startEcho starts e on the address of listener.
+func startEcho(e *echo.Echo, listener config.Listener) (
+	func(), // function for shutdown
+	error,
+) {
+	l, err := net.Listen("tcp", listener.HostPort())
+	if err != nil {
+		return nil, xerrors.Errorf("listen on %s: %w", listener.HostPort(), err)
+	}
+	e.Listener = l
 
 	go func() {
-		if err := e.Start(":" + strconv.Itoa(port)); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := e.StartServer(e.Server); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			e.Logger.Fatalf("failed to start server successfully: %v", err)
 		}
 	}()
@@ -79,6 +342,10 @@ func (s *SimulatorServer) Start(port int) (
 		if err := e.Shutdown(ctx); err != nil {
 			e.Logger.Warnf("failed to shutdown simulator server successfully: %v", err)
 		}
+		// Shutdown doesn't close the listener if the server hasn't started serving yet.
+		if err := l.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			e.Logger.Warnf("failed to close the listener: %v", err)
+		}
 	}
 
 	return shutdownFn, nil
@@ -91,3 +358,9 @@ func RouteExtender(v1 *echo.Group, handler *handler.ExtenderHandler) {
 	v1.POST("/extender/preempt/:id", handler.Preempt)
 	v1.POST("/extender/bind/:id", handler.Bind)
 }
+
+// RouteExtenderInjection routes request to manage the injections to the requests to extenders.
+func RouteExtenderInjection(v1 *echo.Group, handler *handler.ExtenderInjectionHandler) {
+	v1.GET("/extender/injections", handler.List)
+	v1.PUT("/extender/injections/:id", handler.Set)
+}
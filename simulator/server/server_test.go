@@ -0,0 +1,217 @@
+package server
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+)
+
+// hasRoute returns true if e has the route of method and path.
+func hasRoute(e *echo.Echo, method, path string) bool {
+	for _, r := range e.Routes() {
+		if r.Method == method && r.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSimulatorServer_Start(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Config{Ports: config.Ports{
+		API:           config.Listener{Port: 1212},
+		Metrics:       config.Listener{Port: 9090},
+		ExtenderProxy: config.Listener{Port: 1213},
+	}}
+	// The handlers are wired with the fake clients instead of the ones of kube-apiserver.
+	dic, _, _ := di.NewTestContainer(t, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "default"}})
+	h := newHandlers(cfg, dic)
+	e := echo.New()
+	registerRoutes(e, cfg, nil, h)
+	assert.False(t, hasRoute(e, http.MethodGet, "/metrics"), "/metrics is served by the metrics server")
+	assert.False(t, hasRoute(e, http.MethodPost, "/api/v1/extender/filter/:id"), "the extenders are served by the proxy server")
+
+	s := &SimulatorServer{
+		e:             e,
+		metrics:       newMetricsServer(cfg),
+		extenderProxy: newExtenderProxyServer(h),
+		// Listen on the ephemeral ports to check that each server binds the configured address.
+		ports: config.Ports{
+			API:           config.Listener{Address: "127.0.0.1"},
+			Metrics:       config.Listener{Address: "127.0.0.1"},
+			ExtenderProxy: config.Listener{Address: "127.0.0.1"},
+		},
+	}
+	assert.True(t, hasRoute(s.extenderProxy, http.MethodPost, "/api/v1/extender/filter/:id"))
+	assert.False(t, hasRoute(s.extenderProxy, http.MethodPut, "/api/v1/extender/injections/:id"), "the proxy server isn't authenticated")
+	assert.True(t, hasRoute(e, http.MethodPut, "/api/v1/extender/injections/:id"))
+
+	shutdownFn, err := s.Start()
+	require.NoError(t, err)
+	defer shutdownFn()
+
+	ports := map[int]bool{}
+	for _, server := range []*echo.Echo{s.e, s.metrics, s.extenderProxy} {
+		require.NotNil(t, server.Listener)
+		addr, ok := server.Listener.Addr().(*net.TCPAddr)
+		require.True(t, ok)
+		assert.Equal(t, "127.0.0.1", addr.IP.String())
+		ports[addr.Port] = true
+	}
+	assert.Len(t, ports, 3, "each server has its own port")
+
+	resp, err := http.Get("http://" + s.metrics.Listener.Addr().String() + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get("http://" + s.e.Listener.Addr().String() + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Get("http://" + s.e.Listener.Addr().String() + "/healthz")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get("http://" + s.e.Listener.Addr().String() + "/api/v1/resources/pods?version=v1&namespace=default")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"name":"pod-1"`)
+}
+
+func TestSimulatorServer_Start_addressInUse(t *testing.T) {
+	t.Parallel()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	port := l.Addr().(*net.TCPAddr).Port
+
+	s := &SimulatorServer{
+		e:       echo.New(),
+		metrics: newMetricsServer(&config.Config{}),
+		ports: config.Ports{
+			API:     config.Listener{Address: "127.0.0.1"},
+			Metrics: config.Listener{Address: "127.0.0.1", Port: port},
+		},
+	}
+	_, err = s.Start()
+	require.Error(t, err)
+
+	// The API server started before the failure is shut down.
+	_, err = http.Get("http://" + s.e.Listener.Addr().String() + "/")
+	assert.Error(t, err)
+}
+
+func TestNewSimulatorServer_extraHandlers(t *testing.T) {
+	t.Parallel()
+	analytics := func(c echo.Context) error {
+		return c.String(http.StatusOK, "analytics of "+c.Param("name"))
+	}
+	tests := []struct {
+		name     string
+		opts     []di.Option
+		wantErr  string
+		wantBody string
+	}{
+		{
+			name:     "the extra handler is served under /api/v1",
+			opts:     []di.Option{di.WithExtraHandler(http.MethodGet, "/analytics/:name", analytics)},
+			wantBody: "analytics of pods",
+		},
+		{
+			name:    "conflict with the built-in route",
+			opts:    []di.Option{di.WithExtraHandler(http.MethodGet, "/jobs/:name", analytics)},
+			wantErr: "the extra handler GET /api/v1/jobs/:name conflicts with the route already registered",
+		},
+		{
+			name: "conflict with the other extra handler",
+			opts: []di.Option{
+				di.WithExtraHandler(http.MethodGet, "/analytics/:name", analytics),
+				di.WithExtraHandler("get", "/analytics/:id", analytics),
+			},
+			wantErr: "the extra handler get /api/v1/analytics/:id conflicts with the route already registered",
+		},
+		{
+			name:    "the path without the leading slash",
+			opts:    []di.Option{di.WithExtraHandler(http.MethodGet, "analytics", analytics)},
+			wantErr: "must start with /",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dic, _, _ := di.NewTestContainerWithOptions(t, tt.opts)
+			s, err := NewSimulatorServer(&config.Config{}, dic)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			s.e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/pods", nil))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
+
+func TestNewSimulatorServer_watchAuthenticator(t *testing.T) {
+	t.Parallel()
+	// The authenticator given to the container takes precedence over the token in the config.
+	authenticate := func(r *http.Request) (string, error) {
+		if r.Header.Get("X-Team") != "scheduling" {
+			return "", xerrors.New("unknown team")
+		}
+		return "scheduling", nil
+	}
+	dic, _, _ := di.NewTestContainerWithOptions(t, []di.Option{di.WithWatchAuthenticator(authenticate)})
+	s, err := NewSimulatorServer(&config.Config{WatcherBearerToken: "secret"}, dic)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		wantCode int
+	}{
+		{
+			name:     "accepted by the given authenticator",
+			header:   http.Header{"X-Team": []string{"scheduling"}},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "the token in the config isn't used",
+			header:   http.Header{"Authorization": []string{"Bearer secret"}},
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/watchers", nil)
+			req.Header = tt.header
+			rec := httptest.NewRecorder()
+			s.e.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"k8s.io/klog/v2"
+)
+
+// streamingRoutes are the routes which keep the connections open as long as the clients want.
+// They are never timed out.
+var streamingRoutes = map[string]bool{
+	"/api/v1/listwatchresources":    true,
+	"/api/v1/listwatchresources/ws": true,
+	"/api/v1/logs/stream":           true,
+}
+
+// timeoutMiddleware cancels the context of the request after timeout, and responds with 504
+// unless the handler has already written the response.
+// The handlers must pass the context of the request to the services so that they stop working on the cancelled requests.
+// It doesn't time out the requests if timeout is zero.
+func timeoutMiddleware(timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if timeout <= 0 {
+			return next
+		}
+		return func(c echo.Context) error {
+			if streamingRoutes[c.Path()] {
+				return next(c)
+			}
+			ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			err := next(c)
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Response().Committed {
+				klog.Warningf("the request %s %s timed out after %s: %v", c.Request().Method, c.Request().URL.Path, timeout, err)
+				return echo.NewHTTPError(http.StatusGatewayTimeout, "the request timed out after "+timeout.String())
+			}
+			return err
+		}
+	}
+}
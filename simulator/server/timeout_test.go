@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcelist"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/di"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/server/handler"
+)
+
+// slowResourceListService blocks until the context is done, and reports the error of the context.
+type slowResourceListService struct {
+	stopped chan error
+}
+
+func (s *slowResourceListService) List(ctx context.Context, _ resourcelist.ListOptions) (*resourcelist.ListResult, error) {
+	<-ctx.Done()
+	s.stopped <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func newSlowServer(timeout time.Duration) (*echo.Echo, *slowResourceListService) {
+	service := &slowResourceListService{stopped: make(chan error, 1)}
+	e := echo.New()
+	e.Use(timeoutMiddleware(timeout))
+	e.GET("/api/v1/resources/:resource", handler.NewResourceListHandler(func() di.ResourceListService { return service }).List)
+	return e, service
+}
+
+func TestTimeoutMiddleware_cancelledRequest(t *testing.T) {
+	t.Parallel()
+	e, service := newSlowServer(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/resources/pods", nil).WithContext(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+
+	cancel()
+	select {
+	case err := <-service.stopped:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("the service call isn't stopped after the request is cancelled")
+	}
+	<-done
+}
+
+func TestTimeoutMiddleware_timeout(t *testing.T) {
+	t.Parallel()
+	e, service := newSlowServer(50 * time.Millisecond)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/resources/pods", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	require.Len(t, service.stopped, 1)
+	assert.ErrorIs(t, <-service.stopped, context.DeadlineExceeded)
+}
+
+func TestTimeoutMiddleware_streamingRoutes(t *testing.T) {
+	t.Parallel()
+	e := echo.New()
+	e.Use(timeoutMiddleware(time.Millisecond))
+	handle := func(c echo.Context) error {
+		if _, ok := c.Request().Context().Deadline(); ok {
+			return c.NoContent(http.StatusGatewayTimeout)
+		}
+		return c.NoContent(http.StatusOK)
+	}
+	e.GET("/api/v1/listwatchresources", handle)
+	e.GET("/api/v1/listwatchresources/ws", handle)
+	e.GET("/api/v1/export", handle)
+
+	for path, want := range map[string]int{
+		"/api/v1/listwatchresources":    http.StatusOK,
+		"/api/v1/listwatchresources/ws": http.StatusOK,
+		"/api/v1/export":                http.StatusGatewayTimeout,
+	} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, want, rec.Code, path)
+	}
+}
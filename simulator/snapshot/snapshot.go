@@ -67,11 +67,13 @@ func NewService(client clientset.Interface, schedulers SchedulerService) *Servic
 type options struct {
 	ignoreErr                    bool
 	ignoreSchedulerConfiguration bool
+	sanitize                     bool
 }
 
 type (
 	ignoreErrOption                    bool
 	ignoreSchedulerConfigurationOption bool
+	sanitizeOption                     bool
 )
 
 type Option interface {
@@ -86,6 +88,10 @@ func (i ignoreSchedulerConfigurationOption) apply(opts *options) {
 	opts.ignoreSchedulerConfiguration = bool(i)
 }
 
+func (s sanitizeOption) apply(opts *options) {
+	opts.sanitize = bool(s)
+}
+
 // IgnoreErr is the option to literally ignore errors.
 // If it is enabled, the method won't return any errors, but just log errors as error logs.
 func (s *Service) IgnoreErr() Option {
@@ -99,6 +105,13 @@ func (s *Service) IgnoreSchedulerConfiguration() Option {
 	return ignoreSchedulerConfigurationOption(true)
 }
 
+// Sanitize is the option to remove the fields set by the server, e.g., UID and managedFields, from the resources.
+// Note: this option is only for Snap method.
+// The sanitized resources can be shared and loaded to another simulator as they are.
+func (s *Service) Sanitize() Option {
+	return sanitizeOption(true)
+}
+
 // get gets all resources from each service.
 func (s *Service) get(ctx context.Context, opts options) (*ResourcesForSnap, error) {
 	errgrp := util.NewErrGroupWithSemaphore(ctx)
@@ -145,6 +158,9 @@ func (s *Service) Snap(ctx context.Context, opts ...Option) (*ResourcesForSnap,
 	if err != nil {
 		return nil, xerrors.Errorf("failed to get(): %w", err)
 	}
+	if options.sanitize {
+		sanitize(resources)
+	}
 	return resources, nil
 }
 
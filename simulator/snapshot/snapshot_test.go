@@ -13,6 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	v1 "k8s.io/client-go/applyconfigurations/core/v1"
@@ -2094,3 +2095,75 @@ func TestService_Load_WithIgnoreSchedulerConfigurationOption(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Snap_SanitizeOption_RoundTrip(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	src := fake.NewClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace1, UID: "ns-uid", ResourceVersion: "1"}},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1", UID: "node-uid", ResourceVersion: "2", Labels: map[string]string{"zone": "a"}},
+			Status:     corev1.NodeStatus{Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pod1", Namespace: testNamespace1, UID: "pod-uid", ResourceVersion: "3", Generation: 1,
+				CreationTimestamp: metav1.Now(),
+				ManagedFields:     []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			},
+			Spec: corev1.PodSpec{NodeName: "node1", Containers: []corev1.Container{{Name: "c", Image: "image"}}},
+		},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "sc1", UID: "sc-uid"}, Provisioner: "p"},
+		&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "pc1", UID: "pc-uid"}, Value: 100},
+	)
+	cfg, err := schedulerCfg.DefaultSchedulerConfig()
+	assert.NoError(t, err)
+	ctrl := gomock.NewController(t)
+	ss := mock_snapshot.NewMockSchedulerService(ctrl)
+	ss.EXPECT().GetSchedulerConfig().Return(cfg, nil)
+	// the scheduler in dst is restarted with the imported configuration.
+	var restarted *configv1.KubeSchedulerConfiguration
+	ss.EXPECT().RestartScheduler(gomock.Any()).DoAndReturn(func(c *configv1.KubeSchedulerConfiguration) error {
+		restarted = c
+		return nil
+	})
+	ss.EXPECT().GetSchedulerConfig().DoAndReturn(func() (*configv1.KubeSchedulerConfiguration, error) {
+		return restarted, nil
+	})
+
+	s := NewService(src, ss)
+	exported, err := s.Snap(ctx, s.Sanitize())
+	assert.NoError(t, err)
+	assert.Len(t, exported.Pods, 1)
+	assert.Equal(t, metav1.ObjectMeta{Name: "pod1", Namespace: testNamespace1}, exported.Pods[0].ObjectMeta)
+	assert.Empty(t, exported.Nodes[0].UID)
+
+	// export -> import -> export is the same.
+	b, err := json.Marshal(exported)
+	assert.NoError(t, err)
+	imported := &ResourcesForLoad{}
+	assert.NoError(t, json.Unmarshal(b, imported))
+	dst := NewService(fake.NewClientset(), ss)
+	assert.NoError(t, dst.Load(ctx, imported))
+	reexported, err := dst.Snap(ctx, dst.Sanitize())
+	assert.NoError(t, err)
+	// The fake clientset keeps the TypeMeta of the applied resources, while kube-apiserver doesn't return it in the list.
+	for i := range reexported.Pods {
+		reexported.Pods[i].TypeMeta = metav1.TypeMeta{}
+	}
+	for i := range reexported.Nodes {
+		reexported.Nodes[i].TypeMeta = metav1.TypeMeta{}
+	}
+	for i := range reexported.StorageClasses {
+		reexported.StorageClasses[i].TypeMeta = metav1.TypeMeta{}
+	}
+	for i := range reexported.PriorityClasses {
+		reexported.PriorityClasses[i].TypeMeta = metav1.TypeMeta{}
+	}
+	for i := range reexported.Namespaces {
+		reexported.Namespaces[i].TypeMeta = metav1.TypeMeta{}
+	}
+	rb, err := json.Marshal(reexported)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(b), string(rb))
+}
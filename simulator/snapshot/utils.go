@@ -7,6 +7,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/client-go/applyconfigurations/core/v1"
 	schedulingcfgv1 "k8s.io/client-go/applyconfigurations/scheduling/v1"
 	cfgstoragev1 "k8s.io/client-go/applyconfigurations/storage/v1"
@@ -201,3 +202,37 @@ func convertToApplyConfiguration(in interface{}, out interface{}) error {
 		return xerrors.Errorf("unknown type")
 	}
 }
+
+// sanitize removes the fields set by the server from all the resources.
+func sanitize(r *ResourcesForSnap) {
+	for i := range r.Pods {
+		sanitizeObjectMeta(&r.Pods[i].ObjectMeta)
+	}
+	for i := range r.Nodes {
+		sanitizeObjectMeta(&r.Nodes[i].ObjectMeta)
+	}
+	for i := range r.Pvs {
+		sanitizeObjectMeta(&r.Pvs[i].ObjectMeta)
+	}
+	for i := range r.Pvcs {
+		sanitizeObjectMeta(&r.Pvcs[i].ObjectMeta)
+	}
+	for i := range r.StorageClasses {
+		sanitizeObjectMeta(&r.StorageClasses[i].ObjectMeta)
+	}
+	for i := range r.PriorityClasses {
+		sanitizeObjectMeta(&r.PriorityClasses[i].ObjectMeta)
+	}
+	for i := range r.Namespaces {
+		sanitizeObjectMeta(&r.Namespaces[i].ObjectMeta)
+	}
+}
+
+func sanitizeObjectMeta(m *metav1.ObjectMeta) {
+	m.UID = ""
+	m.ResourceVersion = ""
+	m.Generation = 0
+	m.CreationTimestamp = metav1.Time{}
+	m.ManagedFields = nil
+	m.SelfLink = ""
+}
@@ -0,0 +1,104 @@
+// Package startup waits for the components which the simulator depends on, e.g., etcd and kube-apiserver,
+// to be ready when the simulator is started.
+package startup
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/xerrors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// backoffFactor multiplies the interval after each failed probe.
+	backoffFactor = 2
+	// probeTimeout is the timeout of each probe, so that a hanging probe doesn't use up the whole timeout.
+	probeTimeout = 10 * time.Second
+)
+
+// Options configures how long and how often the components are probed.
+type Options struct {
+	// Timeout is the time to wait for all the components to be ready.
+	Timeout time.Duration
+	// InitialInterval is the interval after the first failed probe of each component.
+	// It's doubled after each failed probe up to MaxInterval.
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+}
+
+// Probe checks that a component is ready.
+type Probe struct {
+	// Name is the name of the component, e.g., etcd.
+	Name string
+	// URL is the URL of the component, which is shown in the logs and the error.
+	URL string
+	// Check returns nil if the component is ready.
+	Check func(ctx context.Context) error
+}
+
+// NotReadyError is returned when a component doesn't become ready until the timeout.
+type NotReadyError struct {
+	Name     string
+	URL      string
+	Attempts int
+	Elapsed  time.Duration
+	// LastErr is the error of the last probe.
+	LastErr error
+}
+
+func (e *NotReadyError) Error() string {
+	return xerrors.Errorf("%s at %s is not ready after %s and %d attempts, the last error: %w", e.Name, e.URL, e.Elapsed, e.Attempts, e.LastErr).Error()
+}
+
+func (e *NotReadyError) Unwrap() error {
+	return e.LastErr
+}
+
+// Wait probes the components in order, and returns when all of them are ready.
+// Each component is probed after the previous one gets ready, e.g., kube-apiserver after etcd,
+// so that the error tells which component is down.
+// It retries each probe with the exponential backoff, and returns NotReadyError
+// if a component isn't ready within opts.Timeout from the start.
+func Wait(ctx context.Context, opts Options, clk clock.Clock, probes ...Probe) error {
+	start := clk.Now()
+	deadline := start.Add(opts.Timeout)
+	for _, p := range probes {
+		if err := waitProbe(ctx, opts, clk, start, deadline, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func waitProbe(ctx context.Context, opts Options, clk clock.Clock, start, deadline time.Time, p Probe) error {
+	interval := opts.InitialInterval
+	for attempts := 1; ; attempts++ {
+		err := check(ctx, p)
+		if err == nil {
+			klog.InfoS("The component is ready", "component", p.Name, "url", p.URL, "attempts", attempts)
+			return nil
+		}
+
+		remaining := deadline.Sub(clk.Now())
+		if remaining <= 0 {
+			return &NotReadyError{Name: p.Name, URL: p.URL, Attempts: attempts, Elapsed: clk.Since(start), LastErr: err}
+		}
+		wait := min(interval, remaining)
+		klog.InfoS("Waiting for the component to be ready", "component", p.Name, "url", p.URL, "attempts", attempts, "retryAfter", wait, "err", err)
+		select {
+		case <-ctx.Done():
+			return xerrors.Errorf("wait for %s at %s: %w", p.Name, p.URL, ctx.Err())
+		case <-clk.After(wait):
+		}
+		interval = min(interval*backoffFactor, opts.MaxInterval)
+	}
+}
+
+// check runs the probe with probeTimeout.
+func check(ctx context.Context, p Probe) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	return p.Check(ctx)
+}
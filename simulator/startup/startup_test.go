@@ -0,0 +1,155 @@
+package startup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+var errConnectionRefused = errors.New("connection refused")
+
+// fakeProbe fails until it's checked readyAt times, and records when it's checked.
+type fakeProbe struct {
+	mu       sync.Mutex
+	clock    *testingclock.FakeClock
+	start    time.Time
+	readyAt  int
+	attempts []time.Duration
+}
+
+func (p *fakeProbe) check(_ context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.attempts = append(p.attempts, p.clock.Since(p.start))
+	if p.readyAt == 0 || len(p.attempts) < p.readyAt {
+		return errConnectionRefused
+	}
+	return nil
+}
+
+func (p *fakeProbe) checkedAt() []time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.attempts
+}
+
+// runWithClock runs fn, advancing fakeClock by a second whenever fn waits for it, until fn returns.
+func runWithClock(t *testing.T, fakeClock *testingclock.FakeClock, fn func() error) error {
+	t.Helper()
+	errCh := make(chan error, 1)
+	go func() { errCh <- fn() }()
+	timeout := time.After(wait.ForeverTestTimeout)
+	for {
+		select {
+		case err := <-errCh:
+			return err
+		case <-timeout:
+			t.Fatal("Wait doesn't return")
+		case <-time.After(time.Millisecond):
+			if fakeClock.HasWaiters() {
+				fakeClock.Step(time.Second)
+			}
+		}
+	}
+}
+
+func TestWait(t *testing.T) {
+	t.Parallel()
+	opts := Options{Timeout: 10 * time.Second, InitialInterval: time.Second, MaxInterval: 4 * time.Second}
+	tests := []struct {
+		name                  string
+		etcdReadyAt           int
+		apiServerReadyAt      int
+		wantEtcdAttempts      []time.Duration
+		wantAPIServerAttempts []time.Duration
+		wantErr               []string
+	}{
+		{
+			name:                  "the probes are retried with the exponential backoff",
+			etcdReadyAt:           1,
+			apiServerReadyAt:      4,
+			wantEtcdAttempts:      []time.Duration{0},
+			wantAPIServerAttempts: []time.Duration{0, time.Second, 3 * time.Second, 7 * time.Second},
+		},
+		{
+			name:                  "the interval is capped with the max interval and the timeout",
+			etcdReadyAt:           1,
+			wantEtcdAttempts:      []time.Duration{0},
+			wantAPIServerAttempts: []time.Duration{0, time.Second, 3 * time.Second, 7 * time.Second, 10 * time.Second},
+			wantErr: []string{
+				"kube-apiserver at http://127.0.0.1:3131 is not ready after 10s and 5 attempts",
+				"the last error: connection refused",
+			},
+		},
+		{
+			name:             "kube-apiserver isn't probed while etcd is down",
+			wantEtcdAttempts: []time.Duration{0, time.Second, 3 * time.Second, 7 * time.Second, 10 * time.Second},
+			wantErr: []string{
+				"etcd at http://127.0.0.1:2379 is not ready after 10s and 5 attempts",
+				"the last error: connection refused",
+			},
+		},
+		{
+			name:                  "the timeout is shared by all the components",
+			etcdReadyAt:           3,
+			wantEtcdAttempts:      []time.Duration{0, time.Second, 3 * time.Second},
+			wantAPIServerAttempts: []time.Duration{3 * time.Second, 4 * time.Second, 6 * time.Second, 10 * time.Second},
+			wantErr:               []string{"kube-apiserver at http://127.0.0.1:3131 is not ready after 10s and 4 attempts"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			start := time.Now()
+			fakeClock := testingclock.NewFakeClock(start)
+			etcd := &fakeProbe{clock: fakeClock, start: start, readyAt: tt.etcdReadyAt}
+			apiServer := &fakeProbe{clock: fakeClock, start: start, readyAt: tt.apiServerReadyAt}
+
+			err := runWithClock(t, fakeClock, func() error {
+				return Wait(context.Background(), opts, fakeClock,
+					Probe{Name: "etcd", URL: "http://127.0.0.1:2379", Check: etcd.check},
+					Probe{Name: "kube-apiserver", URL: "http://127.0.0.1:3131", Check: apiServer.check},
+				)
+			})
+			assert.Equal(t, tt.wantEtcdAttempts, etcd.checkedAt())
+			assert.Equal(t, tt.wantAPIServerAttempts, apiServer.checkedAt())
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var notReady *NotReadyError
+			require.ErrorAs(t, err, &notReady)
+			require.ErrorIs(t, err, errConnectionRefused)
+			for _, want := range tt.wantErr {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestWait_canceled(t *testing.T) {
+	t.Parallel()
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Wait(ctx, Options{Timeout: time.Minute, InitialInterval: time.Second, MaxInterval: time.Second}, fakeClock,
+			Probe{Name: "etcd", URL: "http://127.0.0.1:2379", Check: func(context.Context) error { return errConnectionRefused }})
+	}()
+	require.Eventually(t, fakeClock.HasWaiters, wait.ForeverTestTimeout, 10*time.Millisecond)
+	cancel()
+	select {
+	case err := <-errCh:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("Wait doesn't return after the context is canceled")
+	}
+}
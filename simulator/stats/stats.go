@@ -0,0 +1,258 @@
+// Package stats aggregates the scheduling state of the simulator for the capacity planning,
+// e.g., the Pods scheduled per namespace and the resources requested on each node.
+package stats
+
+import (
+	"errors"
+	"sort"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	resourcehelper "k8s.io/component-helpers/resource"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+	"k8s.io/kubernetes/pkg/scheduler"
+)
+
+// ErrNotSynced is returned when the stats are requested before the informers have synced.
+var ErrNotSynced = errors.New("the informers have not synced yet")
+
+// Resources are the resources which the stats always have.
+// The extended resources, e.g., nvidia.com/gpu, are aggregated as well, but only when the nodes or the Pods have them.
+var Resources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// Query filters the resources aggregated in the stats.
+type Query struct {
+	// Namespace limits the Pods to the namespace. All the namespaces are aggregated if it's empty.
+	Namespace string
+	// NodeSelector limits the nodes, and the Pods scheduled to the nodes. All the nodes are aggregated if it's nil.
+	// The Pending Pods are aggregated regardless of it because they're not on any node yet.
+	NodeSelector labels.Selector
+	// PodSelector limits the Pods to the ones with the matching labels. All the Pods are aggregated if it's nil.
+	PodSelector labels.Selector
+}
+
+// SchedulingStats is the aggregated scheduling state of the simulator.
+type SchedulingStats struct {
+	Cluster ClusterStats `json:"cluster"`
+	// Namespaces is the stats of each namespace in the order of the names.
+	Namespaces []NamespaceStats `json:"namespaces"`
+	// Nodes is the stats of each node in the order of the names.
+	Nodes []NodeStats `json:"nodes"`
+}
+
+// ClusterStats is the stats of all the nodes.
+type ClusterStats struct {
+	Nodes         int `json:"nodes"`
+	ScheduledPods int `json:"scheduledPods"`
+	PendingPods   int `json:"pendingPods"`
+	// Allocatable is the sum of the allocatable resources of the nodes.
+	Allocatable corev1.ResourceList `json:"allocatable"`
+	// Requested is the sum of the resources requested by the scheduled Pods.
+	Requested corev1.ResourceList `json:"requested"`
+	// Utilization is Requested divided by Allocatable. The resource isn't included if nothing is allocatable.
+	Utilization map[corev1.ResourceName]float64 `json:"utilization"`
+}
+
+// NamespaceStats is the stats of the Pods in a namespace.
+type NamespaceStats struct {
+	Namespace     string `json:"namespace"`
+	ScheduledPods int    `json:"scheduledPods"`
+	PendingPods   int    `json:"pendingPods"`
+	// Requested is the sum of the resources requested by the scheduled Pods.
+	Requested corev1.ResourceList `json:"requested"`
+	// PendingRequested is the sum of the resources requested by the Pending Pods.
+	PendingRequested corev1.ResourceList `json:"pendingRequested"`
+}
+
+// NodeStats is the stats of the Pods scheduled to a node.
+type NodeStats struct {
+	Name        string              `json:"name"`
+	Pods        int                 `json:"pods"`
+	Allocatable corev1.ResourceList `json:"allocatable"`
+	Requested   corev1.ResourceList `json:"requested"`
+	// PackingRatio is Requested divided by Allocatable. The resource isn't included if nothing is allocatable.
+	PackingRatio map[corev1.ResourceName]float64 `json:"packingRatio"`
+}
+
+// Service computes the stats from the informer caches, so that the requests don't list all the resources every time.
+type Service struct {
+	informerFactory informers.SharedInformerFactory
+	podLister       corelisters.PodLister
+	nodeLister      corelisters.NodeLister
+	synced          []cache.InformerSynced
+}
+
+// NewService initializes Service. The informers are started by Start.
+func NewService(client clientset.Interface) *Service {
+	informerFactory := scheduler.NewInformerFactory(client, 0)
+	pods := informerFactory.Core().V1().Pods()
+	nodes := informerFactory.Core().V1().Nodes()
+	return &Service{
+		informerFactory: informerFactory,
+		podLister:       pods.Lister(),
+		nodeLister:      nodes.Lister(),
+		synced:          []cache.InformerSynced{pods.Informer().HasSynced, nodes.Informer().HasSynced},
+	}
+}
+
+// Start starts the informers, and waits for them to sync.
+func (s *Service) Start(stopCh <-chan struct{}) error {
+	s.informerFactory.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, s.synced...) {
+		return xerrors.New("failed to sync the informers of pods and nodes")
+	}
+	return nil
+}
+
+// Scheduling aggregates the Pods and the nodes matching q.
+// The Pods which have finished, i.e., Succeeded or Failed, aren't aggregated.
+func (s *Service) Scheduling(q Query) (*SchedulingStats, error) {
+	for _, synced := range s.synced {
+		if !synced() {
+			return nil, ErrNotSynced
+		}
+	}
+	nodeSelector := q.NodeSelector
+	if nodeSelector == nil {
+		nodeSelector = labels.Everything()
+	}
+	nodes, err := s.nodeLister.List(nodeSelector)
+	if err != nil {
+		return nil, xerrors.Errorf("list nodes: %w", err)
+	}
+	podSelector := q.PodSelector
+	if podSelector == nil {
+		podSelector = labels.Everything()
+	}
+	var pods []*corev1.Pod
+	if q.Namespace != "" {
+		pods, err = s.podLister.Pods(q.Namespace).List(podSelector)
+	} else {
+		pods, err = s.podLister.List(podSelector)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("list pods: %w", err)
+	}
+	return aggregate(pods, nodes), nil
+}
+
+func aggregate(pods []*corev1.Pod, nodes []*corev1.Node) *SchedulingStats {
+	ret := &SchedulingStats{
+		Cluster: ClusterStats{Nodes: len(nodes), Allocatable: emptyResourceList(), Requested: emptyResourceList()},
+	}
+
+	nodeStats := make(map[string]*NodeStats, len(nodes))
+	for _, n := range nodes {
+		ns := &NodeStats{Name: n.Name, Allocatable: emptyResourceList(), Requested: emptyResourceList()}
+		addResources(ns.Allocatable, n.Status.Allocatable)
+		addResources(ret.Cluster.Allocatable, n.Status.Allocatable)
+		nodeStats[n.Name] = ns
+	}
+
+	namespaceStats := map[string]*NamespaceStats{}
+	for _, p := range pods {
+		if p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		// The missing requests are regarded as zero.
+		requests := resourcehelper.PodRequests(p, resourcehelper.PodResourcesOptions{})
+
+		if p.Spec.NodeName == "" {
+			ns := namespaceStatsFor(namespaceStats, p.Namespace)
+			ns.PendingPods++
+			addResources(ns.PendingRequested, requests)
+			ret.Cluster.PendingPods++
+			continue
+		}
+		node, ok := nodeStats[p.Spec.NodeName]
+		if !ok {
+			// The node doesn't match the selector, or is already deleted.
+			continue
+		}
+		ns := namespaceStatsFor(namespaceStats, p.Namespace)
+		ns.ScheduledPods++
+		addResources(ns.Requested, requests)
+		node.Pods++
+		addResources(node.Requested, requests)
+		ret.Cluster.ScheduledPods++
+		addResources(ret.Cluster.Requested, requests)
+	}
+
+	ret.Cluster.Utilization = ratio(ret.Cluster.Requested, ret.Cluster.Allocatable)
+	ret.Nodes = make([]NodeStats, 0, len(nodeStats))
+	for _, n := range nodeStats {
+		n.PackingRatio = ratio(n.Requested, n.Allocatable)
+		ret.Nodes = append(ret.Nodes, *n)
+	}
+	sort.Slice(ret.Nodes, func(i, j int) bool { return ret.Nodes[i].Name < ret.Nodes[j].Name })
+	ret.Namespaces = make([]NamespaceStats, 0, len(namespaceStats))
+	for _, ns := range namespaceStats {
+		ret.Namespaces = append(ret.Namespaces, *ns)
+	}
+	sort.Slice(ret.Namespaces, func(i, j int) bool { return ret.Namespaces[i].Namespace < ret.Namespaces[j].Namespace })
+
+	return ret
+}
+
+func namespaceStatsFor(m map[string]*NamespaceStats, namespace string) *NamespaceStats {
+	ns, ok := m[namespace]
+	if !ok {
+		ns = &NamespaceStats{Namespace: namespace, Requested: emptyResourceList(), PendingRequested: emptyResourceList()}
+		m[namespace] = ns
+	}
+	return ns
+}
+
+// emptyResourceList returns the zero quantities of Resources, so that the stats always have all of them.
+func emptyResourceList() corev1.ResourceList {
+	ret := corev1.ResourceList{}
+	for _, r := range Resources {
+		ret[r] = resource.Quantity{Format: format(r)}
+	}
+	return ret
+}
+
+func format(r corev1.ResourceName) resource.Format {
+	if r == corev1.ResourceMemory {
+		return resource.BinarySI
+	}
+	return resource.DecimalSI
+}
+
+// aggregated returns whether the resource is aggregated in the stats.
+func aggregated(r corev1.ResourceName) bool {
+	return r == corev1.ResourceCPU || r == corev1.ResourceMemory || v1helper.IsExtendedResourceName(r)
+}
+
+// addResources adds the aggregated resources in src to dst.
+func addResources(dst, src corev1.ResourceList) {
+	for r, q := range src {
+		if !aggregated(r) {
+			continue
+		}
+		sum, ok := dst[r]
+		if !ok {
+			sum = resource.Quantity{Format: format(r)}
+		}
+		sum.Add(q)
+		dst[r] = sum
+	}
+}
+
+func ratio(requested, allocatable corev1.ResourceList) map[corev1.ResourceName]float64 {
+	ret := map[corev1.ResourceName]float64{}
+	for r, a := range allocatable {
+		if a.IsZero() {
+			continue
+		}
+		req := requested[r]
+		ret[r] = req.AsApproximateFloat64() / a.AsApproximateFloat64()
+	}
+	return ret
+}
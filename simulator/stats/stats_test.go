@@ -0,0 +1,219 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func node(name, zone string, allocatable corev1.ResourceList) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"zone": zone}},
+		Status:     corev1.NodeStatus{Allocatable: allocatable},
+	}
+}
+
+func pod(namespace, name, nodeName string, phase corev1.PodPhase, requests ...corev1.ResourceList) *corev1.Pod {
+	p := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+	for _, r := range requests {
+		p.Spec.Containers = append(p.Spec.Containers, corev1.Container{Resources: corev1.ResourceRequirements{Requests: r}})
+	}
+	return p
+}
+
+func resources(cpu, memory string) corev1.ResourceList {
+	ret := corev1.ResourceList{}
+	if cpu != "" {
+		ret[corev1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if memory != "" {
+		ret[corev1.ResourceMemory] = resource.MustParse(memory)
+	}
+	return ret
+}
+
+// quantities returns the quantities in the canonical form to compare them.
+func quantities(rl corev1.ResourceList) map[corev1.ResourceName]string {
+	ret := map[corev1.ResourceName]string{}
+	for name, q := range rl {
+		ret[name] = q.String()
+	}
+	return ret
+}
+
+func assertRatio(t *testing.T, want, got map[corev1.ResourceName]float64) {
+	t.Helper()
+	require.Len(t, got, len(want))
+	for name, w := range want {
+		assert.InDelta(t, w, got[name], 1e-9, name)
+	}
+}
+
+func TestService_Scheduling(t *testing.T) {
+	t.Parallel()
+
+	withInit := pod("team", "p3", "node1", corev1.PodRunning, resources("1", "512Mi"))
+	withInit.Spec.InitContainers = []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: resources("2", "")}}}
+	withInit.Labels = map[string]string{"tier": "batch"}
+	objs := []runtime.Object{
+		node("node1", "a", resources("4", "8Gi")),
+		node("node2", "b", resources("2", "4Gi")),
+		node("node3", "b", nil),
+		pod("default", "p1", "node1", corev1.PodRunning, resources("500m", "1Gi"), resources("250m", "")),
+		pod("default", "p2", "node2", corev1.PodRunning),
+		withInit,
+		pod("team", "p4", "", corev1.PodPending, resources("100m", "128Mi")),
+		pod("team", "p5", "node2", corev1.PodSucceeded, resources("1", "1Gi")),
+		pod("team", "p6", "deleted-node", corev1.PodRunning, resources("1", "1Gi")),
+	}
+	s := NewService(fake.NewSimpleClientset(objs...))
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	require.NoError(t, s.Start(stopCh))
+
+	t.Run("all the namespaces and the nodes", func(t *testing.T) {
+		t.Parallel()
+		got, err := s.Scheduling(Query{})
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, got.Cluster.Nodes)
+		assert.Equal(t, 3, got.Cluster.ScheduledPods)
+		assert.Equal(t, 1, got.Cluster.PendingPods)
+		assert.Equal(t, map[corev1.ResourceName]string{"cpu": "6", "memory": "12Gi"}, quantities(got.Cluster.Allocatable))
+		// The init container requests more cpu than the regular container, and the missing requests are zero.
+		assert.Equal(t, map[corev1.ResourceName]string{"cpu": "2750m", "memory": "1536Mi"}, quantities(got.Cluster.Requested))
+		assertRatio(t, map[corev1.ResourceName]float64{"cpu": 2.75 / 6, "memory": 0.125}, got.Cluster.Utilization)
+
+		require.Len(t, got.Namespaces, 2)
+		assert.Equal(t, "default", got.Namespaces[0].Namespace)
+		assert.Equal(t, 2, got.Namespaces[0].ScheduledPods)
+		assert.Equal(t, 0, got.Namespaces[0].PendingPods)
+		assert.Equal(t, map[corev1.ResourceName]string{"cpu": "750m", "memory": "1Gi"}, quantities(got.Namespaces[0].Requested))
+		assert.Equal(t, map[corev1.ResourceName]string{"cpu": "0", "memory": "0"}, quantities(got.Namespaces[0].PendingRequested))
+		assert.Equal(t, "team", got.Namespaces[1].Namespace)
+		assert.Equal(t, 1, got.Namespaces[1].ScheduledPods)
+		assert.Equal(t, 1, got.Namespaces[1].PendingPods)
+		assert.Equal(t, map[corev1.ResourceName]string{"cpu": "2", "memory": "512Mi"}, quantities(got.Namespaces[1].Requested))
+		assert.Equal(t, map[corev1.ResourceName]string{"cpu": "100m", "memory": "128Mi"}, quantities(got.Namespaces[1].PendingRequested))
+
+		require.Len(t, got.Nodes, 3)
+		assert.Equal(t, "node1", got.Nodes[0].Name)
+		assert.Equal(t, 2, got.Nodes[0].Pods)
+		assert.Equal(t, map[corev1.ResourceName]string{"cpu": "2750m", "memory": "1536Mi"}, quantities(got.Nodes[0].Requested))
+		assertRatio(t, map[corev1.ResourceName]float64{"cpu": 0.6875, "memory": 0.1875}, got.Nodes[0].PackingRatio)
+		assert.Equal(t, "node2", got.Nodes[1].Name)
+		assert.Equal(t, 1, got.Nodes[1].Pods)
+		assert.Equal(t, map[corev1.ResourceName]string{"cpu": "0", "memory": "0"}, quantities(got.Nodes[1].Requested))
+		assertRatio(t, map[corev1.ResourceName]float64{"cpu": 0, "memory": 0}, got.Nodes[1].PackingRatio)
+		assert.Equal(t, "node3", got.Nodes[2].Name)
+		assert.Empty(t, got.Nodes[2].PackingRatio, "nothing is allocatable on node3")
+	})
+
+	t.Run("namespace", func(t *testing.T) {
+		t.Parallel()
+		got, err := s.Scheduling(Query{Namespace: "team"})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, got.Cluster.ScheduledPods)
+		assert.Equal(t, 1, got.Cluster.PendingPods)
+		assert.Equal(t, map[corev1.ResourceName]string{"cpu": "6", "memory": "12Gi"}, quantities(got.Cluster.Allocatable))
+		assert.Equal(t, map[corev1.ResourceName]string{"cpu": "2", "memory": "512Mi"}, quantities(got.Cluster.Requested))
+		require.Len(t, got.Namespaces, 1)
+		assert.Equal(t, "team", got.Namespaces[0].Namespace)
+		require.Len(t, got.Nodes, 3)
+		assert.Equal(t, 1, got.Nodes[0].Pods)
+		assertRatio(t, map[corev1.ResourceName]float64{"cpu": 0.5, "memory": 0.0625}, got.Nodes[0].PackingRatio)
+	})
+
+	t.Run("nodeSelector", func(t *testing.T) {
+		t.Parallel()
+		got, err := s.Scheduling(Query{NodeSelector: labels.SelectorFromSet(labels.Set{"zone": "b"})})
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, got.Cluster.Nodes)
+		assert.Equal(t, 1, got.Cluster.ScheduledPods)
+		assert.Equal(t, 1, got.Cluster.PendingPods, "the Pending Pods are counted regardless of the selector")
+		assert.Equal(t, map[corev1.ResourceName]string{"cpu": "2", "memory": "4Gi"}, quantities(got.Cluster.Allocatable))
+		assert.Equal(t, map[corev1.ResourceName]string{"cpu": "0", "memory": "0"}, quantities(got.Cluster.Requested))
+		require.Len(t, got.Nodes, 2)
+		assert.Equal(t, "node2", got.Nodes[0].Name)
+		assert.Equal(t, "node3", got.Nodes[1].Name)
+		require.Len(t, got.Namespaces, 2)
+		assert.Equal(t, 1, got.Namespaces[0].ScheduledPods)
+		assert.Equal(t, 0, got.Namespaces[1].ScheduledPods)
+		assert.Equal(t, 1, got.Namespaces[1].PendingPods)
+	})
+
+	t.Run("podSelector", func(t *testing.T) {
+		t.Parallel()
+		got, err := s.Scheduling(Query{PodSelector: labels.SelectorFromSet(labels.Set{"tier": "batch"})})
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, got.Cluster.Nodes, "the nodes aren't limited by the selector")
+		assert.Equal(t, 1, got.Cluster.ScheduledPods)
+		assert.Equal(t, 0, got.Cluster.PendingPods)
+		assert.Equal(t, map[corev1.ResourceName]string{"cpu": "2", "memory": "512Mi"}, quantities(got.Cluster.Requested))
+		require.Len(t, got.Namespaces, 1)
+		assert.Equal(t, "team", got.Namespaces[0].Namespace)
+	})
+}
+
+func TestService_SchedulingBeforeSync(t *testing.T) {
+	t.Parallel()
+	s := NewService(fake.NewSimpleClientset())
+	_, err := s.Scheduling(Query{})
+	assert.ErrorIs(t, err, ErrNotSynced)
+}
+
+func TestService_Scheduling_extendedResources(t *testing.T) {
+	t.Parallel()
+
+	gpus := func(cpu, memory, gpu string) corev1.ResourceList {
+		ret := resources(cpu, memory)
+		ret["nvidia.com/gpu"] = resource.MustParse(gpu)
+		return ret
+	}
+	cpuNode := resources("4", "8Gi")
+	cpuNode[corev1.ResourcePods] = resource.MustParse("110")
+	objs := []runtime.Object{
+		node("cpu-node", "a", cpuNode),
+		node("gpu-node", "a", gpus("8", "32Gi", "4")),
+		pod("default", "trainer", "gpu-node", corev1.PodRunning, gpus("2", "8Gi", "1")),
+		pod("default", "web", "cpu-node", corev1.PodRunning, resources("1", "1Gi")),
+		pod("default", "pending-trainer", "", corev1.PodPending, gpus("1", "1Gi", "2")),
+	}
+	s := NewService(fake.NewSimpleClientset(objs...))
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	require.NoError(t, s.Start(stopCh))
+
+	got, err := s.Scheduling(Query{})
+	require.NoError(t, err)
+
+	// The pods, which isn't an extended resource, isn't aggregated.
+	assert.Equal(t, map[corev1.ResourceName]string{"cpu": "12", "memory": "40Gi", "nvidia.com/gpu": "4"}, quantities(got.Cluster.Allocatable))
+	assert.Equal(t, map[corev1.ResourceName]string{"cpu": "3", "memory": "9Gi", "nvidia.com/gpu": "1"}, quantities(got.Cluster.Requested))
+	assertRatio(t, map[corev1.ResourceName]float64{"cpu": 0.25, "memory": 9.0 / 40, "nvidia.com/gpu": 0.25}, got.Cluster.Utilization)
+
+	require.Len(t, got.Namespaces, 1)
+	assert.Equal(t, map[corev1.ResourceName]string{"cpu": "1", "memory": "1Gi", "nvidia.com/gpu": "2"}, quantities(got.Namespaces[0].PendingRequested))
+
+	require.Len(t, got.Nodes, 2)
+	assert.Equal(t, "cpu-node", got.Nodes[0].Name)
+	assert.Equal(t, map[corev1.ResourceName]string{"cpu": "4", "memory": "8Gi"}, quantities(got.Nodes[0].Allocatable))
+	assertRatio(t, map[corev1.ResourceName]float64{"cpu": 0.25, "memory": 0.125}, got.Nodes[0].PackingRatio)
+	assert.Equal(t, "gpu-node", got.Nodes[1].Name)
+	assert.Equal(t, map[corev1.ResourceName]string{"cpu": "2", "memory": "8Gi", "nvidia.com/gpu": "1"}, quantities(got.Nodes[1].Requested))
+	assertRatio(t, map[corev1.ResourceName]float64{"cpu": 0.25, "memory": 0.25, "nvidia.com/gpu": 0.25}, got.Nodes[1].PackingRatio)
+}
@@ -2,6 +2,9 @@ package syncer
 
 import (
 	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
 
 	"golang.org/x/xerrors"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -13,7 +16,9 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/lifecycle"
 	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourceapplier"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/resourcefilter"
 )
 
 // DefaultGVRs is a list of GroupVersionResource that we sync by default (configurable with Options),
@@ -31,30 +36,87 @@ var DefaultGVRs = []schema.GroupVersionResource{
 }
 
 type Service struct {
+	// Controller manages the lifecycle of the syncer started through the API.
+	// The events are held while it's paused, and applied after it's resumed.
+	*lifecycle.Controller
+	// optionsMu guards gvrs and labelSelector, which can be changed with ApplyConfig.
+	optionsMu              sync.Mutex
 	gvrs                   []schema.GroupVersionResource
+	labelSelector          string
 	srcDynamicClient       dynamic.Interface
 	resourceApplierService *resourceapplier.Service
+	// synced is true after the resources in the target cluster are synced first.
+	synced atomic.Bool
+	logger klog.Logger
 }
 
-func New(srcDynamicClient dynamic.Interface, resourceApplierService *resourceapplier.Service) *Service {
+// Options is the options of the syncer.
+type Options struct {
+	// GVRs is the resources synced. If it's nil, the GVRsToSync of the resourceapplier.Service or DefaultGVRs is used.
+	GVRs []schema.GroupVersionResource
+	// LabelSelector is the label selector of the resources synced. All the resources are synced if it's empty.
+	LabelSelector string
+}
+
+// New initializes Service. logger is used for the logs of the syncer.
+func New(srcDynamicClient dynamic.Interface, resourceApplierService *resourceapplier.Service, options Options, logger klog.Logger) *Service {
 	s := &Service{
-		gvrs:                   DefaultGVRs,
 		srcDynamicClient:       srcDynamicClient,
 		resourceApplierService: resourceApplierService,
+		logger:                 logger,
 	}
+	s.ApplyConfig(options)
+	s.Controller = lifecycle.NewController(s.prepare)
+
+	return s
+}
 
-	if resourceApplierService.GVRsToSync != nil {
-		s.gvrs = resourceApplierService.GVRsToSync
+// ApplyConfig changes the resources synced and the label selector to the ones in options.
+// They take effect when the syncer is started next time, e.g., after it's restarted through the component API.
+func (s *Service) ApplyConfig(options Options) {
+	gvrs := DefaultGVRs
+	if options.GVRs != nil {
+		gvrs = options.GVRs
+	} else if s.resourceApplierService.GVRsToSync != nil {
+		gvrs = s.resourceApplierService.GVRsToSync
 	}
 
-	return s
+	s.optionsMu.Lock()
+	defer s.optionsMu.Unlock()
+	s.gvrs = gvrs
+	s.labelSelector = options.LabelSelector
+}
+
+// options returns the resources synced and the label selector.
+func (s *Service) options() ([]schema.GroupVersionResource, string) {
+	s.optionsMu.Lock()
+	defer s.optionsMu.Unlock()
+	return s.gvrs, s.labelSelector
+}
+
+// prepare returns the function to run the syncer until it's stopped. The syncer takes no options.
+func (s *Service) prepare(options json.RawMessage) (lifecycle.RunFunc, error) {
+	if err := lifecycle.DecodeOptions(options, &struct{}{}); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, ready func()) error {
+		if err := s.Run(ctx); err != nil {
+			return err
+		}
+		ready()
+		<-ctx.Done()
+		return nil
+	}, nil
 }
 
 func (s *Service) Run(ctx context.Context) error {
-	klog.Info("Starting the cluster resource importer")
+	s.logger.Info("Starting the cluster resource importer")
 
-	infFact := dynamicinformer.NewFilteredDynamicSharedInformerFactory(s.srcDynamicClient, 0, metav1.NamespaceAll, nil)
-	for _, gvr := range s.gvrs {
+	gvrs, labelSelector := s.options()
+	infFact := dynamicinformer.NewFilteredDynamicSharedInformerFactory(s.srcDynamicClient, 0, metav1.NamespaceAll, func(opts *metav1.ListOptions) {
+		opts.LabelSelector = labelSelector
+	})
+	for _, gvr := range gvrs {
 		inf := infFact.ForResource(gvr).Informer()
 		_, err := inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
 			AddFunc:    s.addFunc,
@@ -68,49 +130,103 @@ func (s *Service) Run(ctx context.Context) error {
 		infFact.WaitForCacheSync(ctx.Done())
 	}
 
-	klog.Info("Cluster resource syncer started")
+	s.synced.Store(true)
+	s.logger.Info("Cluster resource syncer started")
+
+	return nil
+}
 
+// Healthz returns an error until the resources in the target cluster are synced first.
+func (s *Service) Healthz() error {
+	if !s.synced.Load() {
+		return xerrors.New("the resources haven't been synced from the target cluster yet")
+	}
 	return nil
 }
 
 func (s *Service) addFunc(obj interface{}) {
+	if err := s.WaitResumed(); err != nil {
+		return
+	}
 	ctx := context.Background()
 	unstructObj, ok := obj.(*unstructured.Unstructured)
 	if !ok {
-		klog.Error("Failed to convert runtime.Object to *unstructured.Unstructured")
+		s.logger.Error(nil, "Failed to convert runtime.Object to *unstructured.Unstructured")
 		return
 	}
 
+	if resourcefilter.IsCompletedOrTerminatingPod(unstructObj) {
+		s.logger.V(2).Info("Skipped to create the completed or terminating pod on destination", "pod", klog.KObj(unstructObj))
+		return
+	}
+
+	// The applier mutates the resource, so keep the original one to copy its status afterward.
+	original := unstructObj.DeepCopy()
 	err := s.resourceApplierService.Create(ctx, unstructObj)
 	if err != nil {
-		klog.ErrorS(err, "Failed to create resource on destination cluster")
+		s.logger.Error(err, "Failed to create resource on destination cluster")
+		return
 	}
+	s.syncNodeStatus(ctx, original)
 }
 
 func (s *Service) updateFunc(_, newObj interface{}) {
+	if err := s.WaitResumed(); err != nil {
+		return
+	}
 	ctx := context.Background()
 	unstructObj, ok := newObj.(*unstructured.Unstructured)
 	if !ok {
-		klog.Error("Failed to convert runtime.Object to *unstructured.Unstructured")
+		s.logger.Error(nil, "Failed to convert runtime.Object to *unstructured.Unstructured")
+		return
+	}
+
+	if resourcefilter.IsCompletedOrTerminatingPod(unstructObj) {
+		s.logger.V(2).Info("Skipped to update the completed or terminating pod on destination", "pod", klog.KObj(unstructObj))
 		return
 	}
 
+	original := unstructObj.DeepCopy()
 	err := s.resourceApplierService.Update(ctx, unstructObj)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// We just ignore the not found error because the scheduler may preempt the Pods, or users may remove the resources for debugging.
-			klog.Info("Skipped to update resource on destination: ", err)
+			s.logger.Info("Skipped to update resource on destination", "err", err)
 		} else {
-			klog.ErrorS(err, "Failed to update resource on destination cluster")
+			s.logger.Error(err, "Failed to update resource on destination cluster")
+		}
+		return
+	}
+	s.syncNodeStatus(ctx, original)
+}
+
+// syncNodeStatus copies the status of the node to the destination cluster,
+// because the status, including the capacity and allocatable of the extended resources such as nvidia.com/gpu,
+// is dropped when the node is created or updated, and then no pods can be scheduled on the node.
+// It does nothing for resources other than nodes.
+func (s *Service) syncNodeStatus(ctx context.Context, node *unstructured.Unstructured) {
+	if node.GroupVersionKind().GroupKind() != (schema.GroupKind{Kind: "Node"}) {
+		return
+	}
+	err := s.resourceApplierService.UpdateStatus(ctx, node)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// The node may be filtered out by the applier.
+			s.logger.V(2).Info("Skipped to sync node status because the node isn't found on destination", "node", klog.KObj(node))
+		} else {
+			s.logger.Error(err, "Failed to sync node status on destination cluster", "node", klog.KObj(node))
 		}
 	}
 }
 
 func (s *Service) deleteFunc(obj interface{}) {
+	if err := s.WaitResumed(); err != nil {
+		return
+	}
 	ctx := context.Background()
 	unstructObj, ok := obj.(*unstructured.Unstructured)
 	if !ok {
-		klog.Error("Failed to convert runtime.Object to *unstructured.Unstructured")
+		s.logger.Error(nil, "Failed to convert runtime.Object to *unstructured.Unstructured")
 		return
 	}
 
@@ -118,9 +234,9 @@ func (s *Service) deleteFunc(obj interface{}) {
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// We just ignore the not found error because the scheduler may preempt the Pods, or users may remove the resources for debugging.
-			klog.Info("Skipped to delete resource on destination: ", err)
+			s.logger.Info("Skipped to delete resource on destination", "err", err)
 		} else {
-			klog.ErrorS(err, "Failed to delete resource on destination cluster")
+			s.logger.Error(err, "Failed to delete resource on destination cluster")
 		}
 	}
 }
@@ -10,13 +10,17 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	dynamicFake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/restmapper"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/klog/v2"
 	scheduling "k8s.io/kubernetes/pkg/apis/scheduling/v1"
 	storage "k8s.io/kubernetes/pkg/apis/storage/v1"
 
@@ -351,6 +355,67 @@ func TestSyncerWithPod(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "completed pod is NOT created in src cluster",
+			podsCreatedInSrcCluster: []*v1.Pod{
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Pod",
+						APIVersion: "v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod-1",
+						Namespace: "default",
+					},
+					Spec: v1.PodSpec{
+						Containers: []v1.Container{
+							{
+								Name: "container-1",
+							},
+						},
+					},
+					Status: v1.PodStatus{
+						Phase: v1.PodSucceeded,
+					},
+				},
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Pod",
+						APIVersion: "v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod-2",
+						Namespace: "default",
+					},
+					Spec: v1.PodSpec{
+						Containers: []v1.Container{
+							{
+								Name: "container-2",
+							},
+						},
+					},
+				},
+			},
+			afterPodsInDestCluster: []*v1.Pod{
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Pod",
+						APIVersion: "v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pod-2",
+						Namespace: "default",
+					},
+					Spec: v1.PodSpec{
+						Containers: []v1.Container{
+							{
+								Name: "container-2",
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -392,7 +457,7 @@ func TestSyncerWithPod(t *testing.T) {
 			}
 			mapper := restmapper.NewDiscoveryRESTMapper(resources)
 			resourceApplier := resourceapplier.New(dest, mapper, resourceapplier.Options{})
-			service := New(src, resourceApplier)
+			service := New(src, resourceApplier, Options{}, klog.Background())
 
 			ctx, cancel := context.WithCancel(context.Background())
 
@@ -494,3 +559,121 @@ func TestSyncerWithPod(t *testing.T) {
 }
 
 type podKey struct{ name, namespace string }
+
+func TestService_ApplyConfig(t *testing.T) {
+	t.Parallel()
+	pods := []schema.GroupVersionResource{{Version: "v1", Resource: "pods"}}
+	service := New(nil, resourceapplier.New(nil, nil, resourceapplier.Options{}), Options{LabelSelector: "env=staging"}, klog.Background())
+
+	service.ApplyConfig(Options{GVRs: pods, LabelSelector: "env=production"})
+	gvrs, labelSelector := service.options()
+	if diff := cmp.Diff(pods, gvrs); diff != "" {
+		t.Errorf("unexpected GVRs (-want, +got): %s", diff)
+	}
+	if labelSelector != "env=production" {
+		t.Errorf("labelSelector = %q, want env=production", labelSelector)
+	}
+
+	service.ApplyConfig(Options{})
+	gvrs, labelSelector = service.options()
+	if diff := cmp.Diff(DefaultGVRs, gvrs); diff != "" {
+		t.Errorf("the default GVRs should be used (-want, +got): %s", diff)
+	}
+	if labelSelector != "" {
+		t.Errorf("labelSelector = %q, want empty", labelSelector)
+	}
+}
+
+func TestSyncerWithNodeStatus(t *testing.T) {
+	t.Parallel()
+
+	s := runtime.NewScheme()
+	v1.AddToScheme(s)
+	src := dynamicFake.NewSimpleDynamicClient(s)
+	dest := dynamicFake.NewSimpleDynamicClient(s)
+	mapper := restmapper.NewDiscoveryRESTMapper([]*restmapper.APIGroupResources{
+		{
+			Group: metav1.APIGroup{
+				Versions: []metav1.GroupVersionForDiscovery{{Version: "v1"}},
+			},
+			VersionedResources: map[string][]metav1.APIResource{
+				"v1": {{Name: "nodes", Namespaced: false, Kind: "Node"}},
+			},
+		},
+	})
+	// Drop the status on creating and updating the node like the API server does, and it's kept only with the status subresource.
+	dropStatus := func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() == "" {
+			if obj, ok := action.(interface{ GetObject() runtime.Object }).GetObject().(*unstructured.Unstructured); ok {
+				unstructured.RemoveNestedField(obj.Object, "status")
+			}
+		}
+		return false, nil, nil
+	}
+	dest.PrependReactor("create", "nodes", dropStatus)
+	dest.PrependReactor("update", "nodes", dropStatus)
+	nodes := v1.Resource("nodes").WithVersion("v1")
+	service := New(src, resourceapplier.New(dest, mapper, resourceapplier.Options{}), Options{GVRs: []schema.GroupVersionResource{nodes}}, klog.Background())
+
+	gpuNode := func(gpus string) *unstructured.Unstructured {
+		resources := v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("8"),
+			v1.ResourcePods:   resource.MustParse("110"),
+			"nvidia.com/gpu":  resource.MustParse(gpus),
+			"example.com/foo": resource.MustParse("1"),
+		}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&v1.Node{
+			TypeMeta:   metav1.TypeMeta{Kind: "Node", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "gpu-node"},
+			Status:     v1.NodeStatus{Capacity: resources, Allocatable: resources},
+		})
+		if err != nil {
+			t.Fatalf("failed to convert node to unstructured: %v", err)
+		}
+		return &unstructured.Unstructured{Object: obj}
+	}
+	// waitForGPUs waits until the node in dest cluster has the gpus in its capacity and allocatable.
+	waitForGPUs := func(ctx context.Context, gpus string) {
+		t.Helper()
+		want := resource.MustParse(gpus)
+		errMessage := ""
+		err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 5*time.Second, false, func(context.Context) (bool, error) {
+			n, err := dest.Resource(nodes).Get(ctx, "gpu-node", metav1.GetOptions{})
+			if err != nil {
+				errMessage = fmt.Sprintf("failed to get node: %v", err)
+				return false, nil
+			}
+			var got v1.Node
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(n.Object, &got); err != nil {
+				errMessage = fmt.Sprintf("failed to convert node to v1.Node: %v", err)
+				return false, nil
+			}
+			if !want.Equal(got.Status.Capacity["nvidia.com/gpu"]) || !want.Equal(got.Status.Allocatable["nvidia.com/gpu"]) {
+				errMessage = fmt.Sprintf("unexpected status: %v", got.Status)
+				return false, nil
+			}
+			if _, ok := got.Status.Allocatable["example.com/foo"]; !ok {
+				errMessage = fmt.Sprintf("example.com/foo is dropped: %v", got.Status)
+				return false, nil
+			}
+			return true, nil
+		})
+		if err != nil {
+			t.Fatal(errMessage)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := src.Resource(nodes).Create(ctx, gpuNode("2"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	go service.Run(ctx)
+	waitForGPUs(ctx, "2")
+
+	// The status updated in src cluster, e.g., by the device plugin, is synced as well.
+	if _, err := src.Resource(nodes).UpdateStatus(ctx, gpuNode("4"), metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update node status: %v", err)
+	}
+	waitForGPUs(ctx, "4")
+}
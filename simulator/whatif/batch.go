@@ -0,0 +1,198 @@
+package whatif
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	internalcache "k8s.io/kubernetes/pkg/scheduler/backend/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/storereflector"
+)
+
+// MaxBatchPods is the maximum number of the Pods in a batch.
+const MaxBatchPods = 1000
+
+// ErrInvalidBatch is returned when the batch is empty, too large, or has an item without the template.
+var ErrInvalidBatch = errors.New("invalid batch")
+
+// BatchItem is the Pods created from the same template in a batch.
+type BatchItem struct {
+	// Template is the Pod to create. The Pods are named "<name>-<index>" after it,
+	// or "<generateName>whatif-<index>" if it doesn't have the name.
+	Template *corev1.Pod `json:"template"`
+	// Count is the number of the Pods. It must be positive.
+	Count int `json:"count"`
+}
+
+// BatchResult is the packing plan of a batch.
+type BatchResult struct {
+	// Placements are the Pods placed to the nodes, in the order they're evaluated.
+	Placements []Placement `json:"placements"`
+	// Unplaced are the Pods which fit no node after the Pods before them are placed.
+	Unplaced []UnplacedPod `json:"unplaced"`
+	// Nodes are the resources left on each node after the plan, in the order of the names.
+	Nodes []NodeRemaining `json:"nodes"`
+}
+
+// Placement is a Pod placed to a node in the plan.
+type Placement struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Node      string `json:"node"`
+}
+
+// UnplacedPod is a Pod which fits no node in the plan.
+type UnplacedPod struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	// Message is why the Pod is unschedulable, in the same format as Result.Message.
+	Message string `json:"message"`
+}
+
+// NodeRemaining is the resources left on a node after the plan.
+type NodeRemaining struct {
+	Name string `json:"name"`
+	// PlacedPods is the number of the Pods placed to the node in the plan.
+	PlacedPods int `json:"placedPods"`
+	// Remaining is the allocatable resources minus the requested ones of the Pods on the node, including the ones placed in the plan.
+	// It has cpu, memory and pods, and the other resources which the node has.
+	Remaining corev1.ResourceList `json:"remaining"`
+}
+
+// snapshotLister is the SharedLister of the snapshot which is replaced every time a Pod of the batch is placed,
+// so that the framework created once sees the Pods placed before.
+type snapshotLister struct {
+	snapshot *internalcache.Snapshot
+}
+
+func (l *snapshotLister) NodeInfos() framework.NodeInfoLister {
+	return l.snapshot.NodeInfos()
+}
+
+func (l *snapshotLister) StorageInfos() framework.StorageInfoLister {
+	return l.snapshot.StorageInfos()
+}
+
+// ScheduleBatch evaluates the Pods of items one by one in the given order, assuming the Pods placed before are bound to their nodes,
+// and returns the packing plan. Nothing is bound nor created, and the nodes and the Pods in the simulator are read only once.
+//
+// Each Pod is evaluated in the same way as Schedule. Unlike the scheduler, the Pods aren't sorted by their priorities,
+// and no Pod is preempted.
+func (s *Service) ScheduleBatch(ctx context.Context, items []BatchItem) (*BatchResult, error) {
+	pods, err := batchPods(items)
+	if err != nil {
+		return nil, err
+	}
+	stopCh, err := s.syncedStopCh()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := s.internalSchedulerConfig()
+	if err != nil {
+		return nil, err
+	}
+	assigned, nodes, err := s.assignedPodsAndNodes()
+	if err != nil {
+		return nil, err
+	}
+	nodeInfos, snapshot, err := newSnapshot(assigned, nodes)
+	if err != nil {
+		return nil, err
+	}
+	lister := &snapshotLister{snapshot: snapshot}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// The frameworks are created for each profile the Pods use.
+	frameworks := map[string]framework.Framework{}
+	ret := &BatchResult{Placements: []Placement{}, Unplaced: []UnplacedPod{}}
+	placed := map[string]int{}
+	for _, pod := range pods {
+		fwk, ok := frameworks[pod.Spec.SchedulerName]
+		if !ok {
+			fwk, err = s.newFramework(ctx, stopCh, cfg, pod.Spec.SchedulerName, lister, storereflector.New())
+			if err != nil {
+				return nil, err
+			}
+			frameworks[pod.Spec.SchedulerName] = fwk
+		}
+
+		result, err := schedule(ctx, fwk, pod, nodeInfos)
+		if err != nil {
+			return nil, xerrors.Errorf("schedule pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		if !result.Schedulable {
+			ret.Unplaced = append(ret.Unplaced, UnplacedPod{Namespace: pod.Namespace, Pod: pod.Name, Message: result.Message})
+			continue
+		}
+		ret.Placements = append(ret.Placements, Placement{Namespace: pod.Namespace, Pod: pod.Name, Node: result.SelectedNode})
+		placed[result.SelectedNode]++
+
+		pod.Spec.NodeName = result.SelectedNode
+		assigned = append(assigned, pod)
+		nodeInfos, lister.snapshot, err = newSnapshot(assigned, nodes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ret.Nodes = make([]NodeRemaining, 0, len(nodeInfos))
+	for _, n := range nodeInfos {
+		ret.Nodes = append(ret.Nodes, NodeRemaining{Name: n.Node().Name, PlacedPods: placed[n.Node().Name], Remaining: remaining(n)})
+	}
+	return ret, nil
+}
+
+// batchPods returns the Pods created from the templates of items.
+func batchPods(items []BatchItem) ([]*corev1.Pod, error) {
+	total := 0
+	for i, item := range items {
+		if item.Template == nil {
+			return nil, xerrors.Errorf("items[%d] has no template: %w", i, ErrInvalidBatch)
+		}
+		if item.Count < 1 {
+			return nil, xerrors.Errorf("items[%d] has non-positive count %d: %w", i, item.Count, ErrInvalidBatch)
+		}
+		total += item.Count
+	}
+	if total == 0 {
+		return nil, xerrors.Errorf("no pods: %w", ErrInvalidBatch)
+	}
+	if total > MaxBatchPods {
+		return nil, xerrors.Errorf("%d pods exceed the limit %d: %w", total, MaxBatchPods, ErrInvalidBatch)
+	}
+
+	pods := make([]*corev1.Pod, 0, total)
+	for _, item := range items {
+		for i := 1; i <= item.Count; i++ {
+			pod := defaultedPod(item.Template)
+			pod.Name = fmt.Sprintf("%s-%d", pod.Name, i)
+			pod.UID = uuid.NewUUID()
+			pod.Spec.NodeName = ""
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+// remaining returns the allocatable resources of the node minus the requested ones.
+func remaining(n *framework.NodeInfo) corev1.ResourceList {
+	ret := corev1.ResourceList{
+		corev1.ResourceCPU:    *resource.NewMilliQuantity(n.Allocatable.MilliCPU-n.Requested.MilliCPU, resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(n.Allocatable.Memory-n.Requested.Memory, resource.BinarySI),
+		corev1.ResourcePods:   *resource.NewQuantity(int64(n.Allocatable.AllowedPodNumber-len(n.Pods)), resource.DecimalSI),
+	}
+	if n.Allocatable.EphemeralStorage > 0 {
+		ret[corev1.ResourceEphemeralStorage] = *resource.NewQuantity(n.Allocatable.EphemeralStorage-n.Requested.EphemeralStorage, resource.BinarySI)
+	}
+	for name, allocatable := range n.Allocatable.ScalarResources {
+		ret[name] = *resource.NewQuantity(allocatable-n.Requested.ScalarResources[name], resource.DecimalSI)
+	}
+	return ret
+}
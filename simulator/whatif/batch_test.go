@@ -0,0 +1,109 @@
+package whatif
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestService_ScheduleBatch(t *testing.T) {
+	t.Parallel()
+	gpu := corev1.Taint{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+	objs := []runtime.Object{
+		node("node1", "2"),
+		node("node2", "2"),
+		node("node3", "4", gpu),
+		pod("running", "node2", "1"),
+	}
+	client := fake.NewSimpleClientset(objs...)
+	s := NewService(client, fakeSchedulerConfig{})
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	require.NoError(t, s.Start(stopCh))
+
+	web := pod("web", "", "1500m")
+	gpuPod := pod("", "", "2")
+	gpuPod.GenerateName = "gpu-"
+	gpuPod.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+
+	got, err := s.ScheduleBatch(context.Background(), []BatchItem{{Template: web, Count: 3}, {Template: gpuPod, Count: 2}})
+	require.NoError(t, err)
+	// web-1 takes node1, and then the other web Pods fit no node.
+	// The gpu Pods fit only node3, and the second one sees the first one on it.
+	assert.Equal(t, []Placement{
+		{Namespace: "default", Pod: "web-1", Node: "node1"},
+		{Namespace: "default", Pod: "gpu-whatif-1", Node: "node3"},
+		{Namespace: "default", Pod: "gpu-whatif-2", Node: "node3"},
+	}, got.Placements)
+	overflow := "0/3 nodes are available: 2 Insufficient cpu, 1 node(s) had untolerated taint {dedicated: gpu}."
+	assert.Equal(t, []UnplacedPod{
+		{Namespace: "default", Pod: "web-2", Message: overflow},
+		{Namespace: "default", Pod: "web-3", Message: overflow},
+	}, got.Unplaced)
+
+	require.Len(t, got.Nodes, 3)
+	wantNodes := []struct {
+		name       string
+		placedPods int
+		cpu        string
+		pods       int64
+	}{
+		{name: "node1", placedPods: 1, cpu: "500m", pods: 109},
+		{name: "node2", placedPods: 0, cpu: "1", pods: 109},
+		{name: "node3", placedPods: 2, cpu: "0", pods: 108},
+	}
+	for i, want := range wantNodes {
+		n := got.Nodes[i]
+		assert.Equal(t, want.name, n.Name)
+		assert.Equal(t, want.placedPods, n.PlacedPods, want.name)
+		assert.True(t, resource.MustParse(want.cpu).Equal(n.Remaining[corev1.ResourceCPU]), "cpu of %s: %s", want.name, n.Remaining.Cpu())
+		assert.Equal(t, want.pods, n.Remaining.Pods().Value(), want.name)
+		assert.True(t, resource.MustParse("8Gi").Equal(n.Remaining[corev1.ResourceMemory]), "memory of %s", want.name)
+	}
+
+	// Nothing is persisted, and the next batch starts from the same state.
+	pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, pods.Items, 1)
+	again, err := s.ScheduleBatch(context.Background(), []BatchItem{{Template: web, Count: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, []Placement{{Namespace: "default", Pod: "web-1", Node: "node1"}}, again.Placements)
+}
+
+func TestService_ScheduleBatch_invalid(t *testing.T) {
+	t.Parallel()
+	client := fake.NewSimpleClientset(node("node1", "2"))
+	s := NewService(client, fakeSchedulerConfig{})
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	require.NoError(t, s.Start(stopCh))
+
+	unknown := pod("whatif", "", "1")
+	unknown.Spec.SchedulerName = "unknown"
+	tests := []struct {
+		name    string
+		items   []BatchItem
+		wantErr error
+	}{
+		{name: "no items", wantErr: ErrInvalidBatch},
+		{name: "no template", items: []BatchItem{{Count: 1}}, wantErr: ErrInvalidBatch},
+		{name: "zero count", items: []BatchItem{{Template: pod("web", "", "1")}}, wantErr: ErrInvalidBatch},
+		{name: "too many pods", items: []BatchItem{{Template: pod("web", "", "1"), Count: MaxBatchPods + 1}}, wantErr: ErrInvalidBatch},
+		{name: "unknown scheduler", items: []BatchItem{{Template: unknown, Count: 1}}, wantErr: ErrUnknownProfile},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := s.ScheduleBatch(context.Background(), tt.items)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,382 @@
+// Package whatif tells where a Pod would be scheduled right now, without creating it in the simulator.
+package whatif
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/informers"
+	clientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	internalcache "k8s.io/kubernetes/pkg/scheduler/backend/cache"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	schedulermetrics "k8s.io/kubernetes/pkg/scheduler/metrics"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler"
+	simulatorschedconfig "sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/config"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin"
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/storereflector"
+)
+
+var (
+	// ErrNotSynced is returned when the Pod is evaluated before the informers have synced.
+	ErrNotSynced = errors.New("the informers have not synced yet")
+	// ErrUnknownProfile is returned when the schedulerName of the Pod doesn't match any profile in the scheduler configuration.
+	ErrUnknownProfile = errors.New("unknown scheduler profile")
+)
+
+// SchedulerConfigGetter returns the scheduler configuration which the scheduler is running with.
+type SchedulerConfigGetter interface {
+	// GetSchedulerConfig returns nil if the configuration hasn't been applied yet.
+	GetSchedulerConfig() (*configv1.KubeSchedulerConfiguration, error)
+}
+
+// Result is the result of the scheduling cycle of the Pod.
+type Result struct {
+	Schedulable bool `json:"schedulable"`
+	// SelectedNode is the node which the Pod would be bound to. It's empty if the Pod is unschedulable.
+	SelectedNode string `json:"selectedNode,omitempty"`
+	// Message is why the Pod is unschedulable,
+	// e.g., "0/3 nodes are available: 2 Insufficient cpu, 1 node(s) had untolerated taint {dedicated: gpu}."
+	Message string `json:"message,omitempty"`
+	// FeasibleNodes are the nodes which passed all the Filter plugins, in the order of the names.
+	FeasibleNodes []string `json:"feasibleNodes"`
+	// Results are the results of each plugin, in the same format as the annotations which the scheduler puts on the Pods.
+	Results map[string]string `json:"results"`
+}
+
+// Service runs the scheduling cycles of the Pods which aren't created, with a dedicated framework for each Pod.
+// The framework only reads the snapshot of the nodes and the Pods taken from the informer caches,
+// so it doesn't interfere with the scheduler running in the simulator.
+type Service struct {
+	client          clientset.Interface
+	schedulerConfig SchedulerConfigGetter
+	informerFactory informers.SharedInformerFactory
+	podLister       corelisters.PodLister
+	nodeLister      corelisters.NodeLister
+	synced          []cache.InformerSynced
+
+	mu sync.Mutex
+	// stopCh is given to Start. The informers which the plugins need are started with it as well.
+	stopCh <-chan struct{}
+}
+
+// NewService initializes Service. The informers are started by Start.
+func NewService(client clientset.Interface, schedulerConfig SchedulerConfigGetter) *Service {
+	// The framework records the scheduler's metrics, which must be initialized.
+	schedulermetrics.Register()
+
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	pods := informerFactory.Core().V1().Pods()
+	nodes := informerFactory.Core().V1().Nodes()
+	return &Service{
+		client:          client,
+		schedulerConfig: schedulerConfig,
+		informerFactory: informerFactory,
+		podLister:       pods.Lister(),
+		nodeLister:      nodes.Lister(),
+		synced:          []cache.InformerSynced{pods.Informer().HasSynced, nodes.Informer().HasSynced},
+	}
+}
+
+// Start starts the informers, and waits for them to sync.
+func (s *Service) Start(stopCh <-chan struct{}) error {
+	s.mu.Lock()
+	s.stopCh = stopCh
+	s.mu.Unlock()
+
+	s.informerFactory.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, s.synced...) {
+		return xerrors.New("failed to sync the informers of pods and nodes")
+	}
+	return nil
+}
+
+// Schedule runs the scheduling cycle of pod up to the Score plugins against the current nodes and Pods,
+// and returns the node which pod would be bound to. Nothing is bound nor created.
+//
+// It evaluates pod with the profile for its schedulerName in the current scheduler configuration.
+// The extenders, the nominated Pods and the plugins which aren't built into the simulator, e.g., the wasm plugins, aren't supported.
+// Unlike the scheduler, the first node in the order of the names is selected if some nodes have the same highest score.
+func (s *Service) Schedule(ctx context.Context, pod *corev1.Pod) (*Result, error) {
+	stopCh, err := s.syncedStopCh()
+	if err != nil {
+		return nil, err
+	}
+	pod = defaultedPod(pod)
+
+	cfg, err := s.internalSchedulerConfig()
+	if err != nil {
+		return nil, err
+	}
+	assigned, nodes, err := s.assignedPodsAndNodes()
+	if err != nil {
+		return nil, err
+	}
+	nodeInfos, snapshot, err := newSnapshot(assigned, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	// The results of the plugins are stored in the store dedicated to this Pod.
+	sharedStore := storereflector.New()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	fwk, err := s.newFramework(ctx, stopCh, cfg, pod.Spec.SchedulerName, snapshot, sharedStore)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := schedule(ctx, fwk, pod, nodeInfos)
+	if err != nil {
+		return nil, err
+	}
+	if store, ok := sharedStore.GetResultStore(plugin.ResultStoreKey); ok {
+		ret.Results = store.GetStoredResult(pod)
+	}
+	if ret.Results == nil {
+		ret.Results = map[string]string{}
+	}
+	return ret, nil
+}
+
+// syncedStopCh returns the channel given to Start, or ErrNotSynced if the informers haven't synced yet.
+func (s *Service) syncedStopCh() (<-chan struct{}, error) {
+	s.mu.Lock()
+	stopCh := s.stopCh
+	s.mu.Unlock()
+	for _, synced := range s.synced {
+		if stopCh == nil || !synced() {
+			return nil, ErrNotSynced
+		}
+	}
+	return stopCh, nil
+}
+
+// defaultedPod returns the copy of pod with the defaults of the fields the scheduling cycle needs.
+func defaultedPod(pod *corev1.Pod) *corev1.Pod {
+	pod = pod.DeepCopy()
+	if pod.Namespace == "" {
+		pod.Namespace = metav1.NamespaceDefault
+	}
+	if pod.Name == "" {
+		pod.Name = pod.GenerateName + "whatif"
+	}
+	if pod.UID == "" {
+		pod.UID = uuid.NewUUID()
+	}
+	if pod.Spec.SchedulerName == "" {
+		pod.Spec.SchedulerName = corev1.DefaultSchedulerName
+	}
+	return pod
+}
+
+// newFramework creates the framework of the profile for schedulerName in cfg, which reads the nodes and the Pods from lister.
+// The results of the plugins are stored in sharedStore.
+func (s *Service) newFramework(ctx context.Context, stopCh <-chan struct{}, cfg *schedulerconfig.KubeSchedulerConfiguration, schedulerName string, lister framework.SharedLister, sharedStore storereflector.Reflector) (framework.Framework, error) {
+	var profile *schedulerconfig.KubeSchedulerProfile
+	for i := range cfg.Profiles {
+		if cfg.Profiles[i].SchedulerName == schedulerName {
+			profile = &cfg.Profiles[i]
+			break
+		}
+	}
+	if profile == nil {
+		return nil, xerrors.Errorf("no profile for %s: %w", schedulerName, ErrUnknownProfile)
+	}
+
+	wrappedRegistry, err := plugin.NewRegistry(sharedStore, cfg, nil, nil, nil, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create the registry of the plugins: %w", err)
+	}
+	registry := simulatorschedconfig.InTreeRegistries()
+	if err := registry.Merge(wrappedRegistry); err != nil {
+		return nil, xerrors.Errorf("merge the registries of the plugins: %w", err)
+	}
+
+	fwk, err := frameworkruntime.NewFramework(ctx, registry, profile,
+		frameworkruntime.WithClientSet(s.client),
+		frameworkruntime.WithInformerFactory(s.informerFactory),
+		frameworkruntime.WithSnapshotSharedLister(lister),
+		frameworkruntime.WithEventRecorder(&events.FakeRecorder{}),
+		frameworkruntime.WithWaitingPods(frameworkruntime.NewWaitingPodsMap()),
+		frameworkruntime.WithParallelism(int(cfg.Parallelism)),
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("create the framework: %w", err)
+	}
+	// The plugins may add the informers of the other resources, e.g., PersistentVolumeClaims.
+	s.informerFactory.Start(stopCh)
+	for typ, synced := range s.informerFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return nil, xerrors.Errorf("failed to sync the informer of %v", typ)
+		}
+	}
+	return fwk, nil
+}
+
+// internalSchedulerConfig returns the current scheduler configuration converted for the simulator.
+// The default configuration is used if the configuration hasn't been applied yet.
+func (s *Service) internalSchedulerConfig() (*schedulerconfig.KubeSchedulerConfiguration, error) {
+	versioned, err := s.schedulerConfig.GetSchedulerConfig()
+	if err != nil {
+		return nil, xerrors.Errorf("get the scheduler config: %w", err)
+	}
+	if versioned == nil {
+		versioned, err = simulatorschedconfig.DefaultSchedulerConfig()
+		if err != nil {
+			return nil, xerrors.Errorf("get the default scheduler config: %w", err)
+		}
+	}
+	// ConvertConfigurationForSimulator modifies the given config.
+	converted, err := scheduler.ConvertConfigurationForSimulator(versioned.DeepCopy())
+	if err != nil {
+		return nil, xerrors.Errorf("convert the scheduler config for the simulator: %w", err)
+	}
+	cfg, err := scheduler.ConvertSchedulerConfigToInternalConfig(converted)
+	if err != nil {
+		return nil, xerrors.Errorf("convert the scheduler config to the internal one: %w", err)
+	}
+	return cfg, nil
+}
+
+// assignedPodsAndNodes returns the nodes and the Pods bound to them from the informer caches.
+func (s *Service) assignedPodsAndNodes() ([]*corev1.Pod, []*corev1.Node, error) {
+	nodes, err := s.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, nil, xerrors.Errorf("list nodes: %w", err)
+	}
+	pods, err := s.podLister.List(labels.Everything())
+	if err != nil {
+		return nil, nil, xerrors.Errorf("list pods: %w", err)
+	}
+	// Like the scheduler's cache, only the Pods bound to the nodes and not finished take the resources.
+	assigned := make([]*corev1.Pod, 0, len(pods))
+	for _, p := range pods {
+		if p.Spec.NodeName == "" || p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		assigned = append(assigned, p)
+	}
+	return assigned, nodes, nil
+}
+
+// newSnapshot takes the snapshot of nodes and the Pods assigned to them.
+// The NodeInfos are returned in the order of the node names.
+func newSnapshot(assigned []*corev1.Pod, nodes []*corev1.Node) ([]*framework.NodeInfo, *internalcache.Snapshot, error) {
+	snapshot := internalcache.NewSnapshot(assigned, nodes)
+	nodeInfos, err := snapshot.NodeInfos().List()
+	if err != nil {
+		return nil, nil, xerrors.Errorf("list the node infos: %w", err)
+	}
+	sort.Slice(nodeInfos, func(i, j int) bool { return nodeInfos[i].Node().Name < nodeInfos[j].Node().Name })
+	return nodeInfos, snapshot, nil
+}
+
+// schedule runs PreFilter, Filter, PreScore and Score plugins in the same way as the scheduler's scheduling cycle.
+func schedule(ctx context.Context, fwk framework.Framework, pod *corev1.Pod, nodeInfos []*framework.NodeInfo) (*Result, error) {
+	ret := &Result{FeasibleNodes: []string{}}
+	state := framework.NewCycleState()
+
+	preFilterResult, s, _ := fwk.RunPreFilterPlugins(ctx, state, pod)
+	if s.IsRejected() {
+		ret.Message = fmt.Sprintf("0/%d nodes are available: %s.", len(nodeInfos), s.Message())
+		return ret, nil
+	}
+	if !s.IsSuccess() {
+		return nil, xerrors.Errorf("run PreFilter plugins: %w", s.AsError())
+	}
+	if !preFilterResult.AllNodes() {
+		filtered := make([]*framework.NodeInfo, 0, len(preFilterResult.NodeNames))
+		for _, n := range nodeInfos {
+			if preFilterResult.NodeNames.Has(n.Node().Name) {
+				filtered = append(filtered, n)
+			}
+		}
+		nodeInfos = filtered
+	}
+
+	statuses := make([]*framework.Status, len(nodeInfos))
+	fwk.Parallelizer().Until(ctx, len(nodeInfos), func(i int) {
+		statuses[i] = fwk.RunFilterPlugins(ctx, state, pod, nodeInfos[i])
+	}, "Filter")
+	feasible := make([]*framework.NodeInfo, 0, len(nodeInfos))
+	reasons := map[string]int{}
+	for i, s := range statuses {
+		if s.IsSuccess() {
+			feasible = append(feasible, nodeInfos[i])
+			ret.FeasibleNodes = append(ret.FeasibleNodes, nodeInfos[i].Node().Name)
+			continue
+		}
+		if !s.IsRejected() {
+			return nil, xerrors.Errorf("run Filter plugins on %s: %w", nodeInfos[i].Node().Name, s.AsError())
+		}
+		for _, r := range s.Reasons() {
+			reasons[r]++
+		}
+	}
+
+	switch len(feasible) {
+	case 0:
+		ret.Message = unschedulableMessage(len(nodeInfos), reasons)
+		return ret, nil
+	case 1:
+		// The scheduler doesn't score the only feasible node either.
+		ret.Schedulable = true
+		ret.SelectedNode = feasible[0].Node().Name
+		return ret, nil
+	}
+
+	if s := fwk.RunPreScorePlugins(ctx, state, pod, feasible); !s.IsSuccess() {
+		return nil, xerrors.Errorf("run PreScore plugins: %w", s.AsError())
+	}
+	scores, s := fwk.RunScorePlugins(ctx, state, pod, feasible)
+	if !s.IsSuccess() {
+		return nil, xerrors.Errorf("run Score plugins: %w", s.AsError())
+	}
+	selected := scores[0]
+	for _, score := range scores[1:] {
+		if score.TotalScore > selected.TotalScore {
+			selected = score
+		}
+	}
+	ret.Schedulable = true
+	ret.SelectedNode = selected.Name
+	return ret, nil
+}
+
+// unschedulableMessage summarizes the reasons why the nodes are filtered out like the message of the FailedScheduling event.
+func unschedulableMessage(nodes int, reasons map[string]int) string {
+	keys := make([]string, 0, len(reasons))
+	for r := range reasons {
+		keys = append(keys, r)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if reasons[keys[i]] != reasons[keys[j]] {
+			return reasons[keys[i]] > reasons[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	parts := make([]string, 0, len(keys))
+	for _, r := range keys {
+		parts = append(parts, fmt.Sprintf("%d %s", reasons[r], r))
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("0/%d nodes are available.", nodes)
+	}
+	return fmt.Sprintf("0/%d nodes are available: %s.", nodes, strings.Join(parts, ", "))
+}
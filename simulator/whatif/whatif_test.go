@@ -0,0 +1,215 @@
+package whatif
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	configv1 "k8s.io/kube-scheduler/config/v1"
+
+	"sigs.k8s.io/kube-scheduler-simulator/simulator/scheduler/plugin/annotation"
+)
+
+type fakeSchedulerConfig struct {
+	cfg *configv1.KubeSchedulerConfiguration
+}
+
+func (f fakeSchedulerConfig) GetSchedulerConfig() (*configv1.KubeSchedulerConfiguration, error) {
+	return f.cfg, nil
+}
+
+func node(name, cpu string, taints ...corev1.Taint) *corev1.Node {
+	allocatable := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse("8Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{Taints: taints},
+		Status:     corev1.NodeStatus{Capacity: allocatable, Allocatable: allocatable},
+	}
+}
+
+func pod(name, nodeName, cpu string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{
+				Name:      "app",
+				Image:     "app",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}},
+			}},
+		},
+	}
+}
+
+func TestService_Schedule(t *testing.T) {
+	t.Parallel()
+	gpu := corev1.Taint{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+	objs := []runtime.Object{
+		node("node1", "1"),
+		node("node2", "4"),
+		node("node3", "4", gpu),
+		// node4 has enough cpu, but it's used by the running Pod.
+		node("node4", "4"),
+		pod("running", "node4", "3"),
+	}
+	client := fake.NewSimpleClientset(objs...)
+	s := NewService(client, fakeSchedulerConfig{})
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	require.NoError(t, s.Start(stopCh))
+	t.Cleanup(func() {
+		pods, err := client.CoreV1().Pods(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Len(t, pods.Items, 1, "the evaluated Pods aren't created")
+	})
+
+	tests := []struct {
+		name        string
+		pod         *corev1.Pod
+		wantResult  *Result
+		wantErr     error
+		wantFilters map[string]map[string]string
+	}{
+		{
+			name: "the pod fits only one node",
+			pod:  pod("whatif", "", "2"),
+			wantResult: &Result{
+				Schedulable:   true,
+				SelectedNode:  "node2",
+				FeasibleNodes: []string{"node2"},
+			},
+			wantFilters: map[string]map[string]string{
+				"node1": {"NodeResourcesFit": "Insufficient cpu"},
+				"node2": {"NodeResourcesFit": "passed", "TaintToleration": "passed"},
+				"node3": {"TaintToleration": "node(s) had untolerated taint {dedicated: gpu}"},
+				"node4": {"NodeResourcesFit": "Insufficient cpu"},
+			},
+		},
+		{
+			name: "the node with the highest score is selected",
+			pod:  pod("whatif", "", "1"),
+			wantResult: &Result{
+				Schedulable:   true,
+				SelectedNode:  "node2",
+				FeasibleNodes: []string{"node1", "node2", "node4"},
+			},
+		},
+		{
+			name: "the pod fits no node",
+			pod:  pod("whatif", "", "8"),
+			wantResult: &Result{
+				Message:       "0/4 nodes are available: 3 Insufficient cpu, 1 node(s) had untolerated taint {dedicated: gpu}.",
+				FeasibleNodes: []string{},
+			},
+		},
+		{
+			name: "unknown scheduler",
+			pod: func() *corev1.Pod {
+				p := pod("whatif", "", "1")
+				p.Spec.SchedulerName = "unknown"
+				return p
+			}(),
+			wantErr: ErrUnknownProfile,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := s.Schedule(context.Background(), tt.pod)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantResult.Schedulable, got.Schedulable)
+			assert.Equal(t, tt.wantResult.SelectedNode, got.SelectedNode)
+			assert.Equal(t, tt.wantResult.Message, got.Message)
+			assert.Equal(t, tt.wantResult.FeasibleNodes, got.FeasibleNodes)
+			if len(got.FeasibleNodes) > 1 {
+				assert.Contains(t, got.Results, annotation.TotalScoreResultAnnotationKey, "the feasible nodes are scored")
+			}
+
+			if tt.wantFilters == nil {
+				return
+			}
+			filters := map[string]map[string]string{}
+			require.NoError(t, json.Unmarshal([]byte(got.Results[annotation.FilterResultAnnotationKey]), &filters))
+			for nodeName, want := range tt.wantFilters {
+				for pluginName, reason := range want {
+					assert.Equal(t, reason, filters[nodeName][pluginName], "%s on %s", pluginName, nodeName)
+				}
+			}
+		})
+	}
+}
+
+func TestService_ScheduleBeforeStart(t *testing.T) {
+	t.Parallel()
+	s := NewService(fake.NewSimpleClientset(), fakeSchedulerConfig{})
+	_, err := s.Schedule(context.Background(), pod("whatif", "", "1"))
+	assert.ErrorIs(t, err, ErrNotSynced)
+}
+
+func TestService_Schedule_extendedResources(t *testing.T) {
+	t.Parallel()
+	gpuNode := node("gpu-node", "1")
+	// The capacity and allocatable share the same list.
+	gpuNode.Status.Allocatable["nvidia.com/gpu"] = resource.MustParse("2")
+	// The GPU node has the least cpu, so the pod would go to the other nodes if it didn't request GPUs.
+	client := fake.NewSimpleClientset(node("node1", "4"), node("node2", "4"), gpuNode)
+	s := NewService(client, fakeSchedulerConfig{})
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	require.NoError(t, s.Start(stopCh))
+
+	tests := []struct {
+		name       string
+		gpus       string
+		wantResult *Result
+	}{
+		{
+			name: "the GPU pod is scheduled onto the only GPU node",
+			gpus: "1",
+			wantResult: &Result{
+				Schedulable:   true,
+				SelectedNode:  "gpu-node",
+				FeasibleNodes: []string{"gpu-node"},
+			},
+		},
+		{
+			name: "the pod requests more GPUs than the node has",
+			gpus: "4",
+			wantResult: &Result{
+				Message:       "0/3 nodes are available: 3 Insufficient nvidia.com/gpu.",
+				FeasibleNodes: []string{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := pod("whatif", "", "500m")
+			p.Spec.Containers[0].Resources.Requests["nvidia.com/gpu"] = resource.MustParse(tt.gpus)
+			p.Spec.Containers[0].Resources.Limits = corev1.ResourceList{"nvidia.com/gpu": resource.MustParse(tt.gpus)}
+			got, err := s.Schedule(context.Background(), p)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantResult.Schedulable, got.Schedulable)
+			assert.Equal(t, tt.wantResult.SelectedNode, got.SelectedNode)
+			assert.Equal(t, tt.wantResult.Message, got.Message)
+			assert.Equal(t, tt.wantResult.FeasibleNodes, got.FeasibleNodes)
+		})
+	}
+}